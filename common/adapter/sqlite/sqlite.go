@@ -0,0 +1,97 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	_ "modernc.org/sqlite"
+)
+
+type Database struct {
+	DB *sql.DB
+}
+
+// NewDatabase opens the sqlite file at the path, ":memory:" opens a database living as long as the process.
+// SQLite allows a single writer at a time, the pool keeps a single connection so that the transactions queue
+// instead of failing on a busy database
+func NewDatabase(path string) Database {
+	db, err := sql.Open("sqlite", fmt.Sprintf("file:%s?_pragma=foreign_keys(1)", path))
+	if err != nil {
+		log.Fatalln(err)
+	}
+	db.SetMaxOpenConns(1)
+
+	return Database{DB: db}
+}
+
+type txKey struct{}
+
+// injectTx injects transaction to context
+func injectTx(ctx context.Context, tx *sql.Tx) context.Context {
+	return context.WithValue(ctx, txKey{}, tx)
+}
+
+// extractTx extracts transaction from context
+func extractTx(ctx context.Context) *sql.Tx {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return nil
+}
+
+// Model returns the executor of the transaction of the context, or the database outside of a transaction
+func (db Database) Model(ctx context.Context) boil.ContextExecutor {
+	tx := extractTx(ctx)
+	if tx != nil {
+		return tx
+	}
+
+	return db.DB
+}
+
+// WithinTransaction runs function within transaction
+//
+// The transaction commits when function were finished without error, a function called within a running
+// transaction joins it since the single connection is taken by the running transaction
+func (db Database) WithinTransaction(ctx context.Context, tFunc func(ctx context.Context) error) error {
+	if extractTx(ctx) != nil {
+		return tFunc(ctx)
+	}
+
+	// begin transaction
+	tx, err := db.DB.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return common.ErrDB(err)
+	}
+
+	defer func() {
+		// finalize transaction on panic, etc.
+		if r := recover(); r != nil {
+			if errTx := tx.Rollback(); errTx != nil {
+				log.Printf("close transaction: %v", errTx)
+			}
+			panic(r)
+		}
+	}()
+
+	// run callback
+	err = tFunc(injectTx(ctx, tx))
+	if err != nil {
+		// if error, rollback
+		if errRollback := tx.Rollback(); errRollback != nil {
+			log.Printf("rollback transaction: %v", errRollback)
+			return common.ErrDB(errRollback)
+		}
+		return err
+	}
+	// if no error, commit
+	if errCommit := tx.Commit(); errCommit != nil {
+		log.Printf("commit transaction: %v", errCommit)
+		return common.ErrDB(errCommit)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenIssuer(t *testing.T) {
+	t.Parallel()
+
+	issuer := NewTokenIssuer("secret", time.Hour)
+
+	token, expiresAt, err := issuer.Issue("user-1")
+	assert.NoError(t, err)
+	assert.True(t, expiresAt.After(time.Now()))
+
+	userID, err := issuer.Verify(token)
+	assert.NoError(t, err)
+	assert.Equal(t, "user-1", userID)
+
+	_, err = NewTokenIssuer("other-secret", time.Hour).Verify(token)
+	assert.Equal(t, ErrInvalidToken, err)
+
+	expired, _, err := NewTokenIssuer("secret", -time.Minute).Issue("user-1")
+	assert.NoError(t, err)
+	_, err = issuer.Verify(expired)
+	assert.Equal(t, ErrInvalidToken, err)
+
+	_, err = issuer.Verify("not-a-token")
+	assert.Equal(t, ErrInvalidToken, err)
+
+	_, _, err = NewTokenIssuer("", time.Hour).Issue("user-1")
+	assert.Equal(t, ErrSecretIsNotConfig, err)
+}
+
+func TestPassword(t *testing.T) {
+	t.Parallel()
+
+	hashed, err := HashPassword("password")
+	assert.NoError(t, err)
+	assert.NotEqual(t, "password", hashed)
+
+	assert.NoError(t, ComparePassword(hashed, "password"))
+	assert.Equal(t, ErrInvalidCredentials, ComparePassword(hashed, "wrong"))
+	assert.Equal(t, ErrInvalidCredentials, ComparePassword("", "password"))
+}
+
+func TestUserIDFromContext(t *testing.T) {
+	t.Parallel()
+
+	_, ok := UserIDFromContext(context.Background())
+	assert.False(t, ok)
+
+	userID, ok := UserIDFromContext(WithUserID(context.Background(), "user-1"))
+	assert.True(t, ok)
+	assert.Equal(t, "user-1", userID)
+}
@@ -0,0 +1,29 @@
+package auth
+
+import "context"
+
+type userIDKey struct{}
+
+// WithUserID returns a copy of the context carrying the authenticated user id
+func WithUserID(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, userIDKey{}, userID)
+}
+
+// UserIDFromContext returns the authenticated user id, ok is false when the request is not authenticated
+func UserIDFromContext(ctx context.Context) (string, bool) {
+	userID, ok := ctx.Value(userIDKey{}).(string)
+	return userID, ok && userID != ""
+}
+
+type adminKey struct{}
+
+// WithAdmin returns a copy of the context marking the request as made with the admin token
+func WithAdmin(ctx context.Context) context.Context {
+	return context.WithValue(ctx, adminKey{}, true)
+}
+
+// IsAdmin reports whether the request was made with the admin token
+func IsAdmin(ctx context.Context) bool {
+	admin, _ := ctx.Value(adminKey{}).(bool)
+	return admin
+}
@@ -0,0 +1,66 @@
+package auth
+
+import (
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+var (
+	ErrInvalidToken      = errors.New("token is not valid")
+	ErrSecretIsNotConfig = errors.New("token secret is not configured")
+)
+
+// TokenIssuer signs and verifies the HS256 tokens carrying the authenticated user id as subject
+type TokenIssuer struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+func NewTokenIssuer(secret string, ttl time.Duration) TokenIssuer {
+	return TokenIssuer{
+		secret: []byte(secret),
+		ttl:    ttl,
+	}
+}
+
+// Issue returns a signed token for the user and the time it expires at
+func (t TokenIssuer) Issue(userID string) (string, time.Time, error) {
+	if len(t.secret) == 0 {
+		return "", time.Time{}, ErrSecretIsNotConfig
+	}
+
+	now := time.Now().UTC()
+	expiresAt := now.Add(t.ttl)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Subject:   userID,
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+	})
+
+	signed, err := token.SignedString(t.secret)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signed, expiresAt, nil
+}
+
+// Verify checks the signature and the expiry of the token and returns the user id it was issued for
+func (t TokenIssuer) Verify(token string) (string, error) {
+	if len(t.secret) == 0 {
+		return "", ErrSecretIsNotConfig
+	}
+
+	claims := &jwt.RegisteredClaims{}
+	parsed, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, ErrInvalidToken
+		}
+		return t.secret, nil
+	})
+	if err != nil || !parsed.Valid || claims.Subject == "" {
+		return "", ErrInvalidToken
+	}
+	return claims.Subject, nil
+}
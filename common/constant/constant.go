@@ -2,9 +2,39 @@ package constant
 
 const (
 	PRODUCTION_ENV_NAME = "prod"
+	DEV_ENV_NAME        = "dev"
 
-	ENV            = "ENV"
-	POSTGRES_URL   = "POSTGRES_URL"
-	PORT           = "PORT"
-	CONFIG_PATH    = "CONFIG_PATH"
+	ENV          = "ENV"
+	POSTGRES_URL = "POSTGRES_URL"
+	PORT         = "PORT"
+	GRPC_PORT    = "GRPC_PORT"
+	CONFIG_PATH  = "CONFIG_PATH"
+
+	STORAGE_DRIVER      = "STORAGE_DRIVER"
+	STORAGE_SQLITE_PATH = "STORAGE_SQLITE_PATH"
+
+	CACHE_DRIVER = "CACHE_DRIVER"
+	CACHE_SIZE   = "CACHE_SIZE"
+	CACHE_TTL    = "CACHE_TTL"
+
+	UNFRIEND_SUBSCRIPTION_POLICY  = "UNFRIEND_SUBSCRIPTION_POLICY"
+	ADMIN_TOKEN                   = "ADMIN_TOKEN"
+	AUTH_SECRET                   = "AUTH_SECRET"
+	AUTH_TOKEN_TTL                = "AUTH_TOKEN_TTL"
+	OUTBOX_POLL_INTERVAL          = "OUTBOX_POLL_INTERVAL"
+	OUTBOX_BATCH_SIZE             = "OUTBOX_BATCH_SIZE"
+	WEBHOOK_POLL_INTERVAL         = "WEBHOOK_POLL_INTERVAL"
+	WEBHOOK_BATCH_SIZE            = "WEBHOOK_BATCH_SIZE"
+	WEBHOOK_TIMEOUT               = "WEBHOOK_TIMEOUT"
+	WEBHOOK_MAX_ATTEMPTS          = "WEBHOOK_MAX_ATTEMPTS"
+	WEBHOOK_BACKOFF_BASE          = "WEBHOOK_BACKOFF_BASE"
+	WEBHOOK_BACKOFF_MAX           = "WEBHOOK_BACKOFF_MAX"
+	WEBHOOK_LEASE                 = "WEBHOOK_LEASE"
+	IMPORT_BATCH_SIZE             = "IMPORT_BATCH_SIZE"
+	ACCOUNT_DELETION_GRACE_PERIOD = "ACCOUNT_DELETION_GRACE_PERIOD"
+	ACCOUNT_PURGE_INTERVAL        = "ACCOUNT_PURGE_INTERVAL"
+	ACCOUNT_PURGE_BATCH_SIZE      = "ACCOUNT_PURGE_BATCH_SIZE"
+	IDEMPOTENCY_TTL               = "IDEMPOTENCY_TTL"
+	IDEMPOTENCY_LEASE             = "IDEMPOTENCY_LEASE"
+	IDEMPOTENCY_CLEANUP_INTERVAL  = "IDEMPOTENCY_CLEANUP_INTERVAL"
 )
@@ -0,0 +1,19 @@
+package requestid
+
+import "context"
+
+// Header is the http header carrying the request id, a request without one is given a new id
+const Header = "X-Request-ID"
+
+type requestIDKey struct{}
+
+// With returns a copy of the context carrying the request id
+func With(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// FromContext returns the request id of the context, empty outside of a request
+func FromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
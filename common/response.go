@@ -39,6 +39,13 @@ func (p SuccessRes) MarshalJSON() ([]byte, error) {
 	return json.Marshal(m)
 }
 
+// Paging is the cursor paging of a list response, NextCursor is empty on the last page
+type Paging struct {
+	Limit      int    `json:"limit"`
+	Cursor     string `json:"cursor,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
 func NewSuccessResponse(data, paging, filter interface{}, custom interface{}) *SuccessRes {
 	return &SuccessRes{
 		Success: true,
@@ -57,6 +64,10 @@ func CustomSuccessResponse(custom interface{}) *SuccessRes {
 	return NewSuccessResponse(nil, nil, nil, custom)
 }
 
+func PagingSuccessResponse(custom, paging, filter interface{}) *SuccessRes {
+	return NewSuccessResponse(nil, paging, filter, custom)
+}
+
 type AppError struct {
 	StatusCode int    `json:"status_code"`
 	RootErr    error  `json:"-"`
@@ -84,6 +95,24 @@ func NewUnauthorized(root error, msg, key string) *AppError {
 	}
 }
 
+func NewForbidden(root error, msg, key string) *AppError {
+	return &AppError{
+		StatusCode: http.StatusForbidden,
+		RootErr:    root,
+		Message:    msg,
+		Key:        key,
+	}
+}
+
+func NewConflict(root error, msg, key string) *AppError {
+	return &AppError{
+		StatusCode: http.StatusConflict,
+		RootErr:    root,
+		Message:    msg,
+		Key:        key,
+	}
+}
+
 func NewCustomError(root error, msg string, key string) *AppError {
 	if root != nil {
 		return NewErrorResponse(root, msg, root.Error(), key)
@@ -1,12 +1,13 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"log"
+	"net"
 
 	"github.com/gin-gonic/gin"
 
-	"github.com/phantranhieunhan/s3-assignment/common/adapter/postgres"
 	"github.com/phantranhieunhan/s3-assignment/common/logger"
 	"github.com/phantranhieunhan/s3-assignment/middleware"
 	"github.com/phantranhieunhan/s3-assignment/module/friendship"
@@ -24,12 +25,38 @@ func main() {
 	// Init logger.
 	logger.Setup(config.C.Env)
 
-	db := postgres.NewDatabase()
+	// migrate up | migrate down N | migrate status | migrate seed
+	if flag.Arg(0) == "migrate" {
+		if err := runMigrate(context.Background(), flag.Args()[1:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	storage, err := friendship.NewStorage(config.C.Storage.Driver)
+	if err != nil {
+		log.Fatal(err)
+	}
+	storage, err = friendship.WithCache(storage, config.C.Cache.Driver, config.C.Cache.Size, config.C.Cache.TTL)
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	r := gin.Default()
 	r.Use(middleware.Recover)
+	r.Use(middleware.RequestID)
 
-	friendship.New(r, db)
+	grpcServer, err := friendship.New(r, storage)
+	if err != nil {
+		log.Fatal(err)
+	}
+	go func() {
+		lis, err := net.Listen("tcp", ":"+config.C.Grpc.Port)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Fatal(grpcServer.Serve(lis))
+	}()
 
 	log.Fatal(r.Run(":" + config.C.Server.Port))
 }
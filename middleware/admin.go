@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/auth"
+)
+
+const AdminTokenHeader = "X-Admin-Token"
+
+var ErrInvalidAdminToken = errors.New("admin token is not valid")
+
+// AdminOnly rejects requests that don't carry the configured admin token and marks the request context of the others
+// as made by the admin, every request is rejected when no token is configured
+func AdminOnly(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		got := c.GetHeader(AdminTokenHeader)
+		if token == "" || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			common.HttpErrorHandler(c, common.NewUnauthorized(ErrInvalidAdminToken, ErrInvalidAdminToken.Error(), "ErrUnauthorized"))
+			return
+		}
+		c.Request = c.Request.WithContext(auth.WithAdmin(c.Request.Context()))
+		c.Next()
+	}
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminOnly(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name       string
+		configured string
+		header     string
+		statusCode int
+	}{
+		{name: "successful", configured: "secret", header: "secret", statusCode: http.StatusOK},
+		{name: "fail because token is wrong", configured: "secret", header: "wrong", statusCode: http.StatusUnauthorized},
+		{name: "fail because token is missing", configured: "secret", statusCode: http.StatusUnauthorized},
+		{name: "fail because admin is not configured", statusCode: http.StatusUnauthorized},
+	}
+
+	for _, tc := range tcs {
+		router := gin.New()
+		router.GET("/test", AdminOnly(tc.configured), func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+
+		req, err := http.NewRequest("GET", "/test", nil)
+		assert.NoError(t, err)
+		if tc.header != "" {
+			req.Header.Set(AdminTokenHeader, tc.header)
+		}
+		res := httptest.NewRecorder()
+		router.ServeHTTP(res, req)
+
+		assert.Equal(t, tc.statusCode, res.Code, tc.name)
+	}
+}
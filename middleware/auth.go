@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/auth"
+)
+
+const (
+	AuthorizationHeader = "Authorization"
+	bearerPrefix        = "Bearer "
+
+	// AuthUserIDKey is the gin context key holding the authenticated user id
+	AuthUserIDKey = "auth_user_id"
+)
+
+var ErrMissingToken = errors.New("bearer token is missing")
+
+type TokenVerifier interface {
+	Verify(token string) (string, error)
+}
+
+// Authenticate rejects requests without a valid bearer token and puts the user id of the token
+// in the gin context and in the request context read by the application handlers
+func Authenticate(verifier TokenVerifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader(AuthorizationHeader)
+		if !strings.HasPrefix(header, bearerPrefix) {
+			common.HttpErrorHandler(c, common.NewUnauthorized(ErrMissingToken, ErrMissingToken.Error(), "ErrUnauthorized"))
+			return
+		}
+
+		userID, err := verifier.Verify(strings.TrimPrefix(header, bearerPrefix))
+		if err != nil {
+			common.HttpErrorHandler(c, common.NewUnauthorized(err, auth.ErrInvalidToken.Error(), "ErrUnauthorized"))
+			return
+		}
+
+		c.Set(AuthUserIDKey, userID)
+		c.Request = c.Request.WithContext(auth.WithUserID(c.Request.Context(), userID))
+		c.Next()
+	}
+}
+
+// AuthenticateIfPresent authenticates the requests carrying a bearer token the same as Authenticate
+// and lets the requests without one through, for the endpoints where only some operations need a user
+func AuthenticateIfPresent(verifier TokenVerifier) gin.HandlerFunc {
+	authenticate := Authenticate(verifier)
+	return func(c *gin.Context) {
+		if c.GetHeader(AuthorizationHeader) == "" {
+			c.Next()
+			return
+		}
+		authenticate(c)
+	}
+}
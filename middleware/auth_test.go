@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/phantranhieunhan/s3-assignment/common/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthenticate(t *testing.T) {
+	t.Parallel()
+
+	issuer := auth.NewTokenIssuer("secret", time.Hour)
+	token, _, err := issuer.Issue("user-1")
+	assert.NoError(t, err)
+
+	tcs := []struct {
+		name       string
+		header     string
+		statusCode int
+	}{
+		{name: "successful", header: "Bearer " + token, statusCode: http.StatusOK},
+		{name: "fail because token is missing", statusCode: http.StatusUnauthorized},
+		{name: "fail because scheme is not bearer", header: "Basic " + token, statusCode: http.StatusUnauthorized},
+		{name: "fail because token is invalid", header: "Bearer invalid", statusCode: http.StatusUnauthorized},
+	}
+
+	for _, tc := range tcs {
+		router := gin.New()
+		router.GET("/test", Authenticate(issuer), func(c *gin.Context) {
+			userID, ok := auth.UserIDFromContext(c.Request.Context())
+			assert.True(t, ok)
+			assert.Equal(t, "user-1", userID)
+			assert.Equal(t, "user-1", c.GetString(AuthUserIDKey))
+			c.Status(http.StatusOK)
+		})
+
+		req, err := http.NewRequest("GET", "/test", nil)
+		assert.NoError(t, err)
+		if tc.header != "" {
+			req.Header.Set(AuthorizationHeader, tc.header)
+		}
+		res := httptest.NewRecorder()
+		router.ServeHTTP(res, req)
+
+		assert.Equal(t, tc.statusCode, res.Code, tc.name)
+	}
+}
+
+func TestAuthenticateIfPresent(t *testing.T) {
+	t.Parallel()
+
+	issuer := auth.NewTokenIssuer("secret", time.Hour)
+	token, _, err := issuer.Issue("user-1")
+	assert.NoError(t, err)
+
+	tcs := []struct {
+		name          string
+		header        string
+		statusCode    int
+		authenticated bool
+	}{
+		{name: "successful with a token", header: "Bearer " + token, statusCode: http.StatusOK, authenticated: true},
+		{name: "successful without token", statusCode: http.StatusOK},
+		{name: "fail because scheme is not bearer", header: "Basic " + token, statusCode: http.StatusUnauthorized},
+		{name: "fail because token is invalid", header: "Bearer invalid", statusCode: http.StatusUnauthorized},
+	}
+
+	for _, tc := range tcs {
+		router := gin.New()
+		router.GET("/test", AuthenticateIfPresent(issuer), func(c *gin.Context) {
+			userID, ok := auth.UserIDFromContext(c.Request.Context())
+			assert.Equal(t, tc.authenticated, ok, tc.name)
+			if tc.authenticated {
+				assert.Equal(t, "user-1", userID)
+			}
+			c.Status(http.StatusOK)
+		})
+
+		req, err := http.NewRequest("GET", "/test", nil)
+		assert.NoError(t, err)
+		if tc.header != "" {
+			req.Header.Set(AuthorizationHeader, tc.header)
+		}
+		res := httptest.NewRecorder()
+		router.ServeHTTP(res, req)
+
+		assert.Equal(t, tc.statusCode, res.Code, tc.name)
+	}
+}
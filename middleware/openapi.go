@@ -0,0 +1,138 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gin-gonic/gin"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/constant"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/pkg/config"
+)
+
+const (
+	contentTypeHeader = "Content-Type"
+
+	// streamedExtension marks the operations streaming their response, it cannot be held back to be validated
+	streamedExtension = "x-streamed"
+)
+
+// bufferedWriter holds the response back until it is validated
+type bufferedWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bufferedWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
+
+// ValidateOpenAPI rejects the requests not matching the operation of the spec, and checks the responses
+// against the spec as well: a response out of the spec is logged, and replaced by an internal error
+// outside production so that the spec and the handlers cannot drift apart unnoticed. The responses of the
+// operations marked x-streamed are sent as they are written and not checked.
+// The tokens are checked by the authenticate middlewares, the security of the spec is not checked again.
+func ValidateOpenAPI(spec *openapi3.T) (gin.HandlerFunc, error) {
+	router, err := gorillamux.NewRouter(spec)
+	if err != nil {
+		return nil, err
+	}
+	options := &openapi3filter.Options{AuthenticationFunc: openapi3filter.NoopAuthenticationFunc}
+	// the bodies of the operations not taking json are streams left to their handlers, they are not read ahead
+	streamOptions := &openapi3filter.Options{AuthenticationFunc: openapi3filter.NoopAuthenticationFunc, ExcludeRequestBody: true}
+
+	return func(c *gin.Context) {
+		route, pathParams, err := router.FindRoute(c.Request)
+		if err != nil {
+			// the routes out of the spec are let through, the tests check that every route is in it
+			c.Next()
+			return
+		}
+
+		reqOptions := streamOptions
+		if takesJSON(route.Operation) {
+			reqOptions = options
+			// the handlers bind the body as json whatever its content type, a body without one is read as json the same
+			if c.GetHeader(contentTypeHeader) == "" {
+				c.Request.Header.Set(contentTypeHeader, gin.MIMEJSON)
+			}
+		}
+
+		reqInput := &openapi3filter.RequestValidationInput{
+			Request:    c.Request,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    reqOptions,
+		}
+		if err = openapi3filter.ValidateRequest(c.Request.Context(), reqInput); err != nil {
+			logger.Error("ValidateOpenAPI.Request: ", err)
+			common.HttpErrorHandler(c, common.ErrInvalidRequest(err, requestErrorField(err)))
+			return
+		}
+
+		if streamed, _ := route.Operation.Extensions[streamedExtension].(bool); streamed {
+			c.Next()
+			return
+		}
+
+		w := &bufferedWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Next()
+		c.Writer = w.ResponseWriter
+
+		err = openapi3filter.ValidateResponse(c.Request.Context(), &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: reqInput,
+			Status:                 w.Status(),
+			Header:                 w.Header(),
+			Body:                   io.NopCloser(bytes.NewReader(w.body.Bytes())),
+			Options:                options,
+		})
+		if err != nil {
+			logger.Error("ValidateOpenAPI.Response: ", err)
+			if config.C.Env != constant.PRODUCTION_ENV_NAME {
+				common.HttpErrorHandler(c, common.ErrInternal(err))
+				return
+			}
+		}
+
+		_, _ = c.Writer.Write(w.body.Bytes())
+	}, nil
+}
+
+// takesJSON reports whether the operation takes a json body, or no body at all
+func takesJSON(op *openapi3.Operation) bool {
+	if op.RequestBody == nil || op.RequestBody.Value == nil {
+		return true
+	}
+	return op.RequestBody.Value.Content.Get(gin.MIMEJSON) != nil
+}
+
+// requestErrorField names the parameter or the body field of a request error, the same as the validate methods
+func requestErrorField(err error) string {
+	var reqErr *openapi3filter.RequestError
+	if !errors.As(err, &reqErr) {
+		return ""
+	}
+	if reqErr.Parameter != nil {
+		return reqErr.Parameter.Name
+	}
+
+	var schemaErr *openapi3.SchemaError
+	if errors.As(err, &schemaErr) {
+		if pointer := schemaErr.JSONPointer(); len(pointer) > 0 {
+			return strings.Join(pointer, ".")
+		}
+	}
+	return "body data"
+}
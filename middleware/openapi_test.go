@@ -0,0 +1,199 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+const testSpec = `
+openapi: 3.0.3
+info:
+  title: test
+  version: 1.0.0
+paths:
+  /users/{id}:
+    parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: string
+    post:
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+            maximum: 10
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [email]
+              properties:
+                email:
+                  type: string
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [success]
+                properties:
+                  success:
+                    type: boolean
+  /upload:
+    post:
+      requestBody:
+        required: true
+        content:
+          text/csv:
+            schema:
+              type: string
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [success]
+                properties:
+                  success:
+                    type: boolean
+  /stream:
+    post:
+      x-streamed: true
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [success]
+                properties:
+                  success:
+                    type: boolean
+`
+
+func TestValidateOpenAPI(t *testing.T) {
+	t.Parallel()
+
+	spec, err := openapi3.NewLoader().LoadFromData([]byte(testSpec))
+	assert.NoError(t, err)
+	validate, err := ValidateOpenAPI(spec)
+	assert.NoError(t, err)
+
+	tcs := []struct {
+		name     string
+		path     string
+		body     interface{}
+		response interface{}
+
+		statusCode  int
+		errorKey    string
+		handlerRuns bool
+	}{
+		{
+			name:        "successful",
+			path:        "/users/1?limit=5",
+			body:        map[string]interface{}{"email": "lisa@example.com"},
+			response:    map[string]interface{}{"success": true},
+			statusCode:  http.StatusOK,
+			handlerRuns: true,
+		},
+		{
+			name:       "fail because a required field of the body is missing",
+			path:       "/users/1",
+			body:       map[string]interface{}{},
+			statusCode: http.StatusBadRequest,
+			errorKey:   "ErrInvalidRequest",
+		},
+		{
+			name:       "fail because a query parameter is out of the spec",
+			path:       "/users/1?limit=50",
+			body:       map[string]interface{}{"email": "lisa@example.com"},
+			statusCode: http.StatusBadRequest,
+			errorKey:   "ErrInvalidRequest",
+		},
+		{
+			name:        "fail because the response is out of the spec",
+			path:        "/users/1",
+			body:        map[string]interface{}{"email": "lisa@example.com"},
+			response:    map[string]interface{}{"success": "yes"},
+			statusCode:  http.StatusBadRequest,
+			errorKey:    "ErrInternal",
+			handlerRuns: true,
+		},
+		{
+			name:        "the body of an operation not taking json is left to the handler",
+			path:        "/upload",
+			body:        "type,user,target",
+			response:    map[string]interface{}{"success": true},
+			statusCode:  http.StatusOK,
+			handlerRuns: true,
+		},
+		{
+			name:        "the response of a streamed operation is not held back to be checked",
+			path:        "/stream",
+			response:    map[string]interface{}{"success": "yes"},
+			statusCode:  http.StatusOK,
+			handlerRuns: true,
+		},
+		{
+			name:        "a route out of the spec is let through",
+			path:        "/other",
+			response:    map[string]interface{}{"anything": 1},
+			statusCode:  http.StatusOK,
+			handlerRuns: true,
+		},
+	}
+
+	for _, tc := range tcs {
+		ran := false
+		handler := func(c *gin.Context) {
+			ran = true
+			c.JSON(http.StatusOK, tc.response)
+		}
+		router := gin.New()
+		router.POST("/users/:id", validate, handler)
+		router.POST("/upload", validate, handler)
+		router.POST("/stream", validate, handler)
+		router.POST("/other", validate, handler)
+
+		jsonBody, err := json.Marshal(tc.body)
+		assert.NoError(t, err)
+		req, err := http.NewRequest(http.MethodPost, tc.path, bytes.NewBuffer(jsonBody))
+		assert.NoError(t, err)
+		res := httptest.NewRecorder()
+		router.ServeHTTP(res, req)
+
+		assert.Equal(t, tc.statusCode, res.Code, tc.name)
+		assert.Equal(t, tc.handlerRuns, ran, tc.name)
+		if tc.errorKey != "" {
+			var body map[string]interface{}
+			assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &body), tc.name)
+			assert.Equal(t, tc.errorKey, body["error_key"], tc.name)
+		} else {
+			assert.JSONEq(t, mustJSON(t, tc.response), res.Body.String(), tc.name)
+		}
+	}
+}
+
+func mustJSON(t *testing.T, v interface{}) string {
+	b, err := json.Marshal(v)
+	assert.NoError(t, err)
+	return string(b)
+}
@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/phantranhieunhan/s3-assignment/common/requestid"
+	"github.com/phantranhieunhan/s3-assignment/pkg/util"
+)
+
+// maxRequestIDLength bounds the id taken from the client, a longer one is replaced by a generated id
+const maxRequestIDLength = 128
+
+// RequestID keeps the request id sent by the client or generates one, puts it in the request context
+// read by the application handlers and echoes it in the response header
+func RequestID(c *gin.Context) {
+	id := c.GetHeader(requestid.Header)
+	if id == "" || len(id) > maxRequestIDLength {
+		id = util.GenUUID()
+	}
+	c.Header(requestid.Header, id)
+	c.Request = c.Request.WithContext(requestid.With(c.Request.Context(), id))
+	c.Next()
+}
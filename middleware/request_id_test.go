@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/phantranhieunhan/s3-assignment/common/requestid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestID(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name      string
+		header    string
+		generated bool
+	}{
+		{name: "keep the id of the client", header: "request-1"},
+		{name: "generate an id when missing", generated: true},
+		{name: "generate an id when too long", header: strings.Repeat("a", maxRequestIDLength+1), generated: true},
+	}
+
+	for _, tc := range tcs {
+		var got string
+		router := gin.New()
+		router.GET("/test", RequestID, func(c *gin.Context) {
+			got = requestid.FromContext(c.Request.Context())
+			c.Status(http.StatusOK)
+		})
+
+		req, err := http.NewRequest("GET", "/test", nil)
+		assert.NoError(t, err)
+		if tc.header != "" {
+			req.Header.Set(requestid.Header, tc.header)
+		}
+		res := httptest.NewRecorder()
+		router.ServeHTTP(res, req)
+
+		assert.Equal(t, got, res.Header().Get(requestid.Header), tc.name)
+		if tc.generated {
+			assert.NotEmpty(t, got, tc.name)
+			assert.NotEqual(t, tc.header, got, tc.name)
+		} else {
+			assert.Equal(t, tc.header, got, tc.name)
+		}
+	}
+}
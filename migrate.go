@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common/adapter/postgres"
+	"github.com/phantranhieunhan/s3-assignment/migration"
+	"github.com/phantranhieunhan/s3-assignment/pkg/migrate"
+)
+
+var errMigrateUsage = errors.New("usage: migrate up | migrate down N | migrate baseline VERSION | migrate status | migrate seed")
+
+// runMigrate runs the migrate command of the arguments on the configured postgres database
+func runMigrate(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return errMigrateUsage
+	}
+	migrations, err := migrate.Load(migration.Migrations, ".")
+	if err != nil {
+		return err
+	}
+	db := postgres.NewDatabase()
+	defer db.DB.Close()
+	m := migrate.NewMigrator(db.DB, migrations)
+
+	switch {
+	case args[0] == "up" && len(args) == 1:
+		applied, err := m.Up(ctx)
+		printMigrations("applied", applied)
+		return err
+	case args[0] == "down" && len(args) == 2:
+		n, err := strconv.Atoi(args[1])
+		if err != nil {
+			return errMigrateUsage
+		}
+		reverted, err := m.Down(ctx, n)
+		printMigrations("reverted", reverted)
+		return err
+	case args[0] == "baseline" && len(args) == 2:
+		version, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return errMigrateUsage
+		}
+		recorded, err := m.Baseline(ctx, version)
+		printMigrations("recorded", recorded)
+		return err
+	case args[0] == "status" && len(args) == 1:
+		list, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, s := range list {
+			appliedAt := "pending"
+			if s.Applied() {
+				appliedAt = s.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\n", s.Version, s.Name, appliedAt)
+		}
+		return w.Flush()
+	case args[0] == "seed" && len(args) == 1:
+		scripts, err := migration.Seeds()
+		if err != nil {
+			return err
+		}
+		if err := m.Seed(ctx, scripts...); err != nil {
+			return err
+		}
+		fmt.Printf("seeded %d scripts\n", len(scripts))
+		return nil
+	}
+	return errMigrateUsage
+}
+
+func printMigrations(action string, list []migrate.Migration) {
+	if len(list) == 0 {
+		fmt.Printf("no migration %s\n", action)
+	}
+	for _, m := range list {
+		fmt.Printf("%s %d_%s\n", action, m.Version, m.Name)
+	}
+}
@@ -0,0 +1,3 @@
+DROP TABLE public.subscriptions;
+DROP TABLE public.friendships;
+DROP TABLE public.users;
@@ -0,0 +1,13 @@
+CREATE TABLE public.blocks(
+	id text not null,
+	user_id text not null,
+	target_id text not null,
+	friendship_status int not null default 0,
+	subscription_status int not null default 0,
+	created_at timestamp with time zone not null,
+	updated_at timestamp with time zone not null,
+	CONSTRAINT blocks_pk PRIMARY KEY (id),
+	CONSTRAINT blocks_user_target_unique UNIQUE (user_id, target_id),
+	CONSTRAINT blocks_users_userid_pk FOREIGN KEY (user_id) REFERENCES users(id),
+	CONSTRAINT blocks_users_targetid_pk FOREIGN KEY (target_id) REFERENCES users(id)
+);
@@ -0,0 +1,4 @@
+ALTER TABLE public.users ADD COLUMN password text NOT NULL DEFAULT '';
+
+-- the seeded users log in with the password "changeme"
+UPDATE public.users SET password = '$2a$10$9OU5e.Xc4fxoW3elWKKybOf3qlaSpNfLAdLqnAQTAqwr0AEMOi.be' WHERE password = '';
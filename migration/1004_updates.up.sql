@@ -0,0 +1,12 @@
+CREATE TABLE public.updates(
+	id text not null,
+	user_id text not null,
+	text text not null,
+	mentions text[] not null default '{}',
+	created_at timestamp with time zone not null,
+	updated_at timestamp with time zone not null,
+	CONSTRAINT updates_pk PRIMARY KEY (id),
+	CONSTRAINT updates_users_userid_pk FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE INDEX updates_userid_createdat_idx ON public.updates (user_id, created_at DESC);
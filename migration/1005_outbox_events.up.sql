@@ -0,0 +1,12 @@
+CREATE TABLE public.outbox_events(
+	id text not null,
+	seq bigserial not null,
+	event_type text not null,
+	aggregate_id text not null,
+	payload jsonb not null,
+	created_at timestamp with time zone not null,
+	delivered_at timestamp with time zone,
+	CONSTRAINT outbox_events_pk PRIMARY KEY (id)
+);
+
+CREATE INDEX outbox_events_undelivered_idx ON public.outbox_events (seq) WHERE delivered_at IS NULL;
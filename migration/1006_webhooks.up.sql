@@ -0,0 +1,29 @@
+CREATE TABLE public.webhooks(
+	id text not null,
+	url text not null,
+	secret text not null,
+	event_types text[] not null,
+	created_at timestamp with time zone not null,
+	updated_at timestamp with time zone not null,
+	CONSTRAINT webhooks_pk PRIMARY KEY (id)
+);
+
+CREATE TABLE public.webhook_deliveries(
+	id text not null,
+	webhook_id text not null,
+	event_id text not null,
+	event_type text not null,
+	payload jsonb not null,
+	status text not null,
+	attempts integer not null default 0,
+	last_error text not null default '',
+	next_attempt_at timestamp with time zone not null,
+	created_at timestamp with time zone not null,
+	updated_at timestamp with time zone not null,
+	CONSTRAINT webhook_deliveries_pk PRIMARY KEY (id),
+	CONSTRAINT webhook_deliveries_webhooks_webhookid_fk FOREIGN KEY (webhook_id) REFERENCES webhooks(id) ON DELETE CASCADE,
+	CONSTRAINT webhook_deliveries_webhookid_eventid_unique UNIQUE (webhook_id, event_id)
+);
+
+CREATE INDEX webhook_deliveries_pending_idx ON public.webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX webhook_deliveries_webhookid_status_idx ON public.webhook_deliveries (webhook_id, status);
@@ -0,0 +1 @@
+DROP TABLE public.user_deletions;
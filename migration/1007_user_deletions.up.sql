@@ -0,0 +1,9 @@
+CREATE TABLE public.user_deletions(
+	user_id text not null,
+	requested_at timestamp with time zone not null,
+	purge_at timestamp with time zone not null,
+	CONSTRAINT user_deletions_pk PRIMARY KEY (user_id),
+	CONSTRAINT user_deletions_users_userid_fk FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX user_deletions_purgeat_idx ON public.user_deletions (purge_at);
@@ -0,0 +1,2 @@
+DROP TABLE public.audit_log;
+DROP FUNCTION public.audit_log_append_only();
@@ -0,0 +1,29 @@
+CREATE TABLE public.audit_log(
+	id text not null,
+	actor text not null,
+	action text not null,
+	target_ids text[] not null default '{}',
+	status_before text not null default '',
+	status_after text not null default '',
+	request_id text not null default '',
+	created_at timestamp with time zone not null,
+	CONSTRAINT audit_log_pk PRIMARY KEY (id)
+);
+
+CREATE INDEX audit_log_createdat_id_idx ON public.audit_log (created_at, id);
+CREATE INDEX audit_log_actor_idx ON public.audit_log (actor);
+CREATE INDEX audit_log_action_idx ON public.audit_log (action);
+CREATE INDEX audit_log_targetids_idx ON public.audit_log USING gin (target_ids);
+
+-- the audit log is append only, the entries can be inserted but never updated nor deleted
+CREATE FUNCTION public.audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+	RAISE EXCEPTION 'audit_log is append only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_append_only_trg BEFORE UPDATE OR DELETE ON public.audit_log
+	FOR EACH ROW EXECUTE FUNCTION public.audit_log_append_only();
+
+CREATE TRIGGER audit_log_no_truncate_trg BEFORE TRUNCATE ON public.audit_log
+	FOR EACH STATEMENT EXECUTE FUNCTION public.audit_log_append_only();
@@ -0,0 +1,10 @@
+CREATE TABLE public.idempotency_keys(
+	scope text not null,
+	key text not null,
+	fingerprint text not null,
+	status_code integer not null default 0,
+	content_type text not null default '',
+	body bytea,
+	created_at timestamp with time zone not null,
+	CONSTRAINT idempotency_keys_pk PRIMARY KEY (scope, key)
+);
@@ -0,0 +1,2 @@
+-- the cleared passwords are not given back, the accounts stay without a usable password
+SELECT 1;
@@ -0,0 +1,3 @@
+-- 1003 gave every existing user the password "changeme", anybody could log in as them.
+-- Their password is cleared so that no login succeeds until the admin sets a new one with POST /admin/users/{email}/password
+UPDATE public.users SET password = '' WHERE password = '$2a$10$9OU5e.Xc4fxoW3elWKKybOf3qlaSpNfLAdLqnAQTAqwr0AEMOi.be';
@@ -0,0 +1,35 @@
+// Package migration embeds the versioned migrations of the postgres database and its optional seed data
+package migration
+
+import (
+	"embed"
+	"io/fs"
+	"sort"
+)
+
+// Migrations holds the <version>_<name>.up.sql and <version>_<name>.down.sql files
+//
+//go:embed *.sql
+var Migrations embed.FS
+
+//go:embed seed/*.sql
+var seeds embed.FS
+
+// Seeds returns the seed scripts ordered by file name
+func Seeds() ([]string, error) {
+	entries, err := fs.ReadDir(seeds, "seed")
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	result := make([]string, 0, len(entries))
+	for _, e := range entries {
+		b, err := seeds.ReadFile("seed/" + e.Name())
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, string(b))
+	}
+	return result, nil
+}
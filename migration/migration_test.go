@@ -0,0 +1,23 @@
+package migration
+
+import (
+	"testing"
+
+	"github.com/phantranhieunhan/s3-assignment/pkg/migrate"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrations(t *testing.T) {
+	migrations, err := migrate.Load(Migrations, ".")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, migrations)
+
+	// every migration can be rolled back
+	for _, m := range migrations {
+		assert.NotEmpty(t, m.Down, "%d_%s has no down file", m.Version, m.Name)
+	}
+
+	seeds, err := Seeds()
+	assert.NoError(t, err)
+	assert.NotEmpty(t, seeds)
+}
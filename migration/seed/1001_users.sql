@@ -0,0 +1,10 @@
+-- the seeded users log in with the password "changeme", the users already there are left as they are:
+-- the sample users created by 1001 keep the password cleared by 1010 until the admin sets one
+INSERT INTO public.users
+(id, email, password, created_at, updated_at)
+VALUES('cd2543cd-6566-4661-a122-2c963fc16b7c', 'andy@example.com', '$2a$10$9OU5e.Xc4fxoW3elWKKybOf3qlaSpNfLAdLqnAQTAqwr0AEMOi.be', now(), now()),
+('b44ca9eb-5d0f-41be-9ecd-dd0158e72e2c', 'john@example.com', '$2a$10$9OU5e.Xc4fxoW3elWKKybOf3qlaSpNfLAdLqnAQTAqwr0AEMOi.be', now(), now()),
+('afed6e29-07d1-443a-a0c7-38d77ef8f332', 'lisa@example.com', '$2a$10$9OU5e.Xc4fxoW3elWKKybOf3qlaSpNfLAdLqnAQTAqwr0AEMOi.be', now(), now()),
+('6bf98bcf-dd9a-4fd8-b43b-b96ea5f5fe7f', 'kate@example.com', '$2a$10$9OU5e.Xc4fxoW3elWKKybOf3qlaSpNfLAdLqnAQTAqwr0AEMOi.be', now(), now()),
+('a46cef8e-ef3d-46e0-9f06-a7bb0d32b310', 'common@example.com', '$2a$10$9OU5e.Xc4fxoW3elWKKybOf3qlaSpNfLAdLqnAQTAqwr0AEMOi.be', now(), now())
+ON CONFLICT DO NOTHING;
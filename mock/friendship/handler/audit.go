@@ -0,0 +1,17 @@
+package mockHandler
+
+import (
+	"context"
+
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockListAuditLogHandler struct {
+	mock.Mock
+}
+
+func (m *MockListAuditLogHandler) Handle(ctx context.Context, filter domain.AuditFilter, page domain.Page) ([]domain.AuditEntry, string, error) {
+	args := m.Called(ctx, filter, page)
+	return args.Get(0).([]domain.AuditEntry), args.String(1), args.Error(2)
+}
@@ -0,0 +1,18 @@
+package mockHandler
+
+import (
+	"context"
+
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockLoginHandler struct {
+	mock.Mock
+}
+
+func (m *MockLoginHandler) Handle(ctx context.Context, payload payload.LoginPayload) (domain.AuthToken, error) {
+	args := m.Called(ctx, payload)
+	return args.Get(0).(domain.AuthToken), args.Error(1)
+}
@@ -3,33 +3,115 @@ package mockHandler
 import (
 	"context"
 
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
 	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
 	"github.com/stretchr/testify/mock"
 )
 
-type MockConnectFriendshipHandler struct {
+type MockListFriendsHandler struct {
 	mock.Mock
 }
 
-func (m *MockConnectFriendshipHandler) Handle(ctx context.Context, userEmail, friendEmail string) (domain.Friendship, error) {
-	args := m.Called(ctx, userEmail, friendEmail)
+func (m *MockListFriendsHandler) Handle(ctx context.Context, email string, page domain.Page) ([]string, string, error) {
+	args := m.Called(ctx, email, page)
+	return args.Get(0).([]string), args.String(1), args.Error(2)
+}
+
+type MockListCommonFriendsHandler struct {
+	mock.Mock
+}
+
+func (m *MockListCommonFriendsHandler) Handle(ctx context.Context, emails []string, page domain.Page) ([]string, string, error) {
+	args := m.Called(ctx, emails, page)
+	return args.Get(0).([]string), args.String(1), args.Error(2)
+}
+
+type MockRequestFriendshipHandler struct {
+	mock.Mock
+}
+
+func (m *MockRequestFriendshipHandler) Handle(ctx context.Context, payload payload.FriendRequestPayload) (domain.Friendship, error) {
+	args := m.Called(ctx, payload)
 	return args.Get(0).(domain.Friendship), args.Error(1)
 }
 
-type MockListFriendsHandler struct {
+type MockAcceptFriendshipHandler struct {
 	mock.Mock
 }
 
-func (m *MockListFriendsHandler) Handle(ctx context.Context, email string) ([]string, error) {
-	args := m.Called(ctx, email)
+func (m *MockAcceptFriendshipHandler) Handle(ctx context.Context, payload payload.FriendRequestPayload) (domain.Friendship, error) {
+	args := m.Called(ctx, payload)
+	return args.Get(0).(domain.Friendship), args.Error(1)
+}
+
+type MockRejectFriendshipHandler struct {
+	mock.Mock
+}
+
+func (m *MockRejectFriendshipHandler) Handle(ctx context.Context, payload payload.FriendRequestPayload) error {
+	args := m.Called(ctx, payload)
+	return args.Error(0)
+}
+
+type MockCancelFriendshipHandler struct {
+	mock.Mock
+}
+
+func (m *MockCancelFriendshipHandler) Handle(ctx context.Context, payload payload.FriendRequestPayload) error {
+	args := m.Called(ctx, payload)
+	return args.Error(0)
+}
+
+type MockUnfriendHandler struct {
+	mock.Mock
+}
+
+func (m *MockUnfriendHandler) Handle(ctx context.Context, payload payload.UnfriendPayload) error {
+	args := m.Called(ctx, payload)
+	return args.Error(0)
+}
+
+type MockListFriendRequestsHandler struct {
+	mock.Mock
+}
+
+func (m *MockListFriendRequestsHandler) Handle(ctx context.Context, email string, direction domain.FriendRequestDirection) ([]string, error) {
+	args := m.Called(ctx, email, direction)
 	return args.Get(0).([]string), args.Error(1)
 }
 
-type MockListCommonFriendsHandler struct {
+type MockListFriendSuggestionsHandler struct {
+	mock.Mock
+}
+
+func (m *MockListFriendSuggestionsHandler) Handle(ctx context.Context, email string, limit int) ([]domain.FriendSuggestion, error) {
+	args := m.Called(ctx, email, limit)
+	return args.Get(0).([]domain.FriendSuggestion), args.Error(1)
+}
+
+type MockFindFriendshipPathHandler struct {
 	mock.Mock
 }
 
-func (m *MockListCommonFriendsHandler) Handle(ctx context.Context, emails []string) ([]string, error) {
-	args := m.Called(ctx, emails)
+func (m *MockFindFriendshipPathHandler) Handle(ctx context.Context, fromEmail, toEmail string, maxDepth int) ([]string, error) {
+	args := m.Called(ctx, fromEmail, toEmail, maxDepth)
 	return args.Get(0).([]string), args.Error(1)
 }
+
+type MockListFriendSetHandler struct {
+	mock.Mock
+}
+
+func (m *MockListFriendSetHandler) Handle(ctx context.Context, emails []string, operation domain.FriendSetOperation) ([]domain.FriendSetMember, error) {
+	args := m.Called(ctx, emails, operation)
+	return args.Get(0).([]domain.FriendSetMember), args.Error(1)
+}
+
+type MockGetFriendshipHandler struct {
+	mock.Mock
+}
+
+func (m *MockGetFriendshipHandler) Handle(ctx context.Context, userEmail, friendEmail string) (domain.Friendship, error) {
+	args := m.Called(ctx, userEmail, friendEmail)
+	return args.Get(0).(domain.Friendship), args.Error(1)
+}
@@ -0,0 +1,18 @@
+package mockHandler
+
+import (
+	"context"
+
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockImportRelationshipsHandler struct {
+	mock.Mock
+}
+
+func (m *MockImportRelationshipsHandler) Handle(ctx context.Context, rows payload.ImportRelationshipPayloads) ([]error, error) {
+	args := m.Called(ctx, rows)
+	rowErrs, _ := args.Get(0).([]error)
+	return rowErrs, args.Error(1)
+}
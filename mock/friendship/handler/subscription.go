@@ -12,11 +12,6 @@ type MockSubscribeUserHandler struct {
 	mock.Mock
 }
 
-func (m *MockSubscribeUserHandler) HandleWithSubscription(ctx context.Context, ds domain.Subscriptions) error {
-	args := m.Called(ctx, ds)
-	return args.Error(0)
-}
-
 func (m *MockSubscribeUserHandler) Handle(ctx context.Context, payload payload.SubscriberUserPayloads) error {
 	args := m.Called(ctx, payload)
 	return args.Error(0)
@@ -31,11 +26,56 @@ func (m *MockBlockUpdatesUserHandler) Handle(ctx context.Context, payload payloa
 	return args.Error(0)
 }
 
+type MockUnblockUserHandler struct {
+	mock.Mock
+}
+
+func (m *MockUnblockUserHandler) Handle(ctx context.Context, payload payload.UnblockUserPayload) error {
+	args := m.Called(ctx, payload)
+	return args.Error(0)
+}
+
+type MockListBlockedUsersHandler struct {
+	mock.Mock
+}
+
+func (m *MockListBlockedUsersHandler) Handle(ctx context.Context, userID string) ([]domain.BlockedEmail, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]domain.BlockedEmail), args.Error(1)
+}
+
+type MockListBlockersHandler struct {
+	mock.Mock
+}
+
+func (m *MockListBlockersHandler) Handle(ctx context.Context, email string) ([]domain.BlockedEmail, error) {
+	args := m.Called(ctx, email)
+	return args.Get(0).([]domain.BlockedEmail), args.Error(1)
+}
+
 type MockListUpdatesUserHandler struct {
 	mock.Mock
 }
 
-func (m *MockListUpdatesUserHandler) Handle(ctx context.Context, email string, text string) ([]string, error) {
-	args := m.Called(ctx, email, text)
-	return args.Get(0).([]string), args.Error(1)
+func (m *MockListUpdatesUserHandler) Handle(ctx context.Context, email string, text string, page domain.Page) ([]string, string, error) {
+	args := m.Called(ctx, email, text, page)
+	return args.Get(0).([]string), args.String(1), args.Error(2)
+}
+
+type MockListSubscribersHandler struct {
+	mock.Mock
+}
+
+func (m *MockListSubscribersHandler) Handle(ctx context.Context, email string, page domain.Page) ([]string, string, error) {
+	args := m.Called(ctx, email, page)
+	return args.Get(0).([]string), args.String(1), args.Error(2)
+}
+
+type MockGetSubscriptionHandler struct {
+	mock.Mock
+}
+
+func (m *MockGetSubscriptionHandler) Handle(ctx context.Context, subscriberEmail, email string) (domain.Subscription, error) {
+	args := m.Called(ctx, subscriberEmail, email)
+	return args.Get(0).(domain.Subscription), args.Error(1)
 }
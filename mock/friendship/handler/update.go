@@ -0,0 +1,27 @@
+package mockHandler
+
+import (
+	"context"
+
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPostUpdateHandler struct {
+	mock.Mock
+}
+
+func (m *MockPostUpdateHandler) Handle(ctx context.Context, payload payload.PostUpdatePayload) (domain.Update, error) {
+	args := m.Called(ctx, payload)
+	return args.Get(0).(domain.Update), args.Error(1)
+}
+
+type MockGetFeedHandler struct {
+	mock.Mock
+}
+
+func (m *MockGetFeedHandler) Handle(ctx context.Context, userID string, page domain.Page) ([]domain.FeedItem, string, error) {
+	args := m.Called(ctx, userID, page)
+	return args.Get(0).([]domain.FeedItem), args.String(1), args.Error(2)
+}
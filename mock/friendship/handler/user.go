@@ -0,0 +1,90 @@
+package mockHandler
+
+import (
+	"context"
+
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCreateUserHandler struct {
+	mock.Mock
+}
+
+func (m *MockCreateUserHandler) Handle(ctx context.Context, payload payload.CreateUserPayload) (domain.User, error) {
+	args := m.Called(ctx, payload)
+	return args.Get(0).(domain.User), args.Error(1)
+}
+
+type MockGetUserHandler struct {
+	mock.Mock
+}
+
+func (m *MockGetUserHandler) Handle(ctx context.Context, email string) (domain.User, error) {
+	args := m.Called(ctx, email)
+	return args.Get(0).(domain.User), args.Error(1)
+}
+
+type MockUpdateUserHandler struct {
+	mock.Mock
+}
+
+func (m *MockUpdateUserHandler) Handle(ctx context.Context, payload payload.UpdateUserPayload) (domain.User, error) {
+	args := m.Called(ctx, payload)
+	return args.Get(0).(domain.User), args.Error(1)
+}
+
+type MockDeleteAccountHandler struct {
+	mock.Mock
+}
+
+func (m *MockDeleteAccountHandler) Handle(ctx context.Context, email string) (domain.UserDeletion, error) {
+	args := m.Called(ctx, email)
+	return args.Get(0).(domain.UserDeletion), args.Error(1)
+}
+
+type MockCancelAccountDeletionHandler struct {
+	mock.Mock
+}
+
+func (m *MockCancelAccountDeletionHandler) Handle(ctx context.Context, payload payload.RestoreAccountPayload) error {
+	args := m.Called(ctx, payload)
+	return args.Error(0)
+}
+
+type MockSetPasswordHandler struct {
+	mock.Mock
+}
+
+func (m *MockSetPasswordHandler) Handle(ctx context.Context, payload payload.SetPasswordPayload) error {
+	args := m.Called(ctx, payload)
+	return args.Error(0)
+}
+
+type MockListUserEmailsHandler struct {
+	mock.Mock
+}
+
+func (m *MockListUserEmailsHandler) Handle(ctx context.Context, userIDs []string) (map[string]string, error) {
+	args := m.Called(ctx, userIDs)
+	return args.Get(0).(map[string]string), args.Error(1)
+}
+
+type MockListUserIDsHandler struct {
+	mock.Mock
+}
+
+func (m *MockListUserIDsHandler) Handle(ctx context.Context, emails []string) (map[string]string, error) {
+	args := m.Called(ctx, emails)
+	return args.Get(0).(map[string]string), args.Error(1)
+}
+
+type MockExportUserHandler struct {
+	mock.Mock
+}
+
+func (m *MockExportUserHandler) Handle(ctx context.Context, email string, w domain.UserExport) error {
+	args := m.Called(ctx, email, w)
+	return args.Error(0)
+}
@@ -0,0 +1,54 @@
+package mockHandler
+
+import (
+	"context"
+
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRegisterWebhookHandler struct {
+	mock.Mock
+}
+
+func (m *MockRegisterWebhookHandler) Handle(ctx context.Context, payload payload.RegisterWebhookPayload) (domain.Webhook, error) {
+	args := m.Called(ctx, payload)
+	return args.Get(0).(domain.Webhook), args.Error(1)
+}
+
+type MockDeleteWebhookHandler struct {
+	mock.Mock
+}
+
+func (m *MockDeleteWebhookHandler) Handle(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+type MockReplayWebhookDeliveryHandler struct {
+	mock.Mock
+}
+
+func (m *MockReplayWebhookDeliveryHandler) Handle(ctx context.Context, payload payload.ReplayWebhookDeliveryPayload) (domain.WebhookDelivery, error) {
+	args := m.Called(ctx, payload)
+	return args.Get(0).(domain.WebhookDelivery), args.Error(1)
+}
+
+type MockListWebhooksHandler struct {
+	mock.Mock
+}
+
+func (m *MockListWebhooksHandler) Handle(ctx context.Context) ([]domain.Webhook, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]domain.Webhook), args.Error(1)
+}
+
+type MockListDeadWebhookDeliveriesHandler struct {
+	mock.Mock
+}
+
+func (m *MockListDeadWebhookDeliveriesHandler) Handle(ctx context.Context, webhookID string) ([]domain.WebhookDelivery, error) {
+	args := m.Called(ctx, webhookID)
+	return args.Get(0).([]domain.WebhookDelivery), args.Error(1)
+}
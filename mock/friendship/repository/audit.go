@@ -0,0 +1,22 @@
+package mockfriendshiprepo
+
+import (
+	"context"
+
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAuditRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditRepository) Create(ctx context.Context, e domain.AuditEntry) (string, error) {
+	args := m.Called(ctx, e)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockAuditRepository) List(ctx context.Context, filter domain.AuditFilter, page domain.Page) ([]domain.AuditEntry, string, error) {
+	args := m.Called(ctx, filter, page)
+	return args.Get(0).([]domain.AuditEntry), args.String(1), args.Error(2)
+}
@@ -0,0 +1,47 @@
+package mockfriendshiprepo
+
+import (
+	"context"
+
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockBlockRepository struct {
+	mock.Mock
+}
+
+func (m *MockBlockRepository) UpsertBlock(ctx context.Context, d domain.Block) (string, error) {
+	args := m.Called(ctx, d)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockBlockRepository) GetBlock(ctx context.Context, userID, targetID string) (domain.Block, error) {
+	args := m.Called(ctx, userID, targetID)
+	return args.Get(0).(domain.Block), args.Error(1)
+}
+
+func (m *MockBlockRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockBlockRepository) GetBlockedEmailsByUserID(ctx context.Context, userID string) ([]domain.BlockedEmail, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]domain.BlockedEmail), args.Error(1)
+}
+
+func (m *MockBlockRepository) GetBlockerEmailsByTargetID(ctx context.Context, targetID string) ([]domain.BlockedEmail, error) {
+	args := m.Called(ctx, targetID)
+	return args.Get(0).([]domain.BlockedEmail), args.Error(1)
+}
+
+func (m *MockBlockRepository) ListBlocksByUserID(ctx context.Context, userID, afterID string, limit int) ([]domain.Block, error) {
+	args := m.Called(ctx, userID, afterID, limit)
+	return args.Get(0).([]domain.Block), args.Error(1)
+}
+
+func (m *MockBlockRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
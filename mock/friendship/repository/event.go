@@ -0,0 +1,36 @@
+package mockfriendshiprepo
+
+import (
+	"context"
+
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockEventRepository struct {
+	mock.Mock
+}
+
+func (m *MockEventRepository) Create(ctx context.Context, e domain.Event) (string, error) {
+	args := m.Called(ctx, e)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockEventRepository) GetUndelivered(ctx context.Context, limit int) ([]domain.Event, error) {
+	args := m.Called(ctx, limit)
+	return args.Get(0).([]domain.Event), args.Error(1)
+}
+
+func (m *MockEventRepository) MarkDelivered(ctx context.Context, ids []string) error {
+	args := m.Called(ctx, ids)
+	return args.Error(0)
+}
+
+type MockEventPublisher struct {
+	mock.Mock
+}
+
+func (m *MockEventPublisher) Publish(ctx context.Context, e domain.Event) error {
+	args := m.Called(ctx, e)
+	return args.Error(0)
+}
@@ -26,7 +26,42 @@ func (m *MockFriendshipRepository) GetFriendshipByUserIDs(ctx context.Context, u
 	return args.Get(0).(domain.Friendship), args.Error(1)
 }
 
-func (m *MockFriendshipRepository) GetFriendshipByUserIDAndStatus(ctx context.Context, mapEmailUser map[string]string, status ...domain.FriendshipStatus) ([]string, error) {
-	args := m.Called(ctx, mapEmailUser, status)
+func (m *MockFriendshipRepository) GetFriendshipByUserIDAndStatus(ctx context.Context, mapEmailUser map[string]string, page domain.Page, status ...domain.FriendshipStatus) ([]string, string, error) {
+	args := m.Called(ctx, mapEmailUser, page, status)
+	return args.Get(0).([]string), args.String(1), args.Error(2)
+}
+
+func (m *MockFriendshipRepository) Update(ctx context.Context, d domain.Friendship) error {
+	args := m.Called(ctx, d)
+	return args.Error(0)
+}
+
+func (m *MockFriendshipRepository) GetFriendRequestEmails(ctx context.Context, userID string, direction domain.FriendRequestDirection) ([]string, error) {
+	args := m.Called(ctx, userID, direction)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockFriendshipRepository) GetFriendSuggestions(ctx context.Context, userID string, limit int) ([]domain.FriendSuggestion, error) {
+	args := m.Called(ctx, userID, limit)
+	return args.Get(0).([]domain.FriendSuggestion), args.Error(1)
+}
+
+func (m *MockFriendshipRepository) GetFriendSet(ctx context.Context, userIDs []string, operation domain.FriendSetOperation) ([]domain.FriendSetMember, error) {
+	args := m.Called(ctx, userIDs, operation)
+	return args.Get(0).([]domain.FriendSetMember), args.Error(1)
+}
+
+func (m *MockFriendshipRepository) GetShortestFriendPath(ctx context.Context, fromID, toID string, maxDepth int) ([]string, error) {
+	args := m.Called(ctx, fromID, toID, maxDepth)
 	return args.Get(0).([]string), args.Error(1)
 }
+
+func (m *MockFriendshipRepository) ListFriendshipsByUserID(ctx context.Context, userID, afterID string, limit int) (domain.Friendships, error) {
+	args := m.Called(ctx, userID, afterID, limit)
+	return args.Get(0).(domain.Friendships), args.Error(1)
+}
+
+func (m *MockFriendshipRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
@@ -0,0 +1,33 @@
+package mockfriendshiprepo
+
+import (
+	"context"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockIdempotencyRepository struct {
+	mock.Mock
+}
+
+func (m *MockIdempotencyRepository) Reserve(ctx context.Context, r domain.IdempotencyRecord, expiredBefore, leaseExpiredBefore time.Time) (domain.IdempotencyRecord, bool, error) {
+	args := m.Called(ctx, r, expiredBefore, leaseExpiredBefore)
+	return args.Get(0).(domain.IdempotencyRecord), args.Bool(1), args.Error(2)
+}
+
+func (m *MockIdempotencyRepository) Complete(ctx context.Context, r domain.IdempotencyRecord) error {
+	args := m.Called(ctx, r)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyRepository) Release(ctx context.Context, r domain.IdempotencyRecord) error {
+	args := m.Called(ctx, r)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyRepository) DeleteExpired(ctx context.Context, expiredBefore time.Time) (int, error) {
+	args := m.Called(ctx, expiredBefore)
+	return args.Int(0), args.Error(1)
+}
@@ -31,7 +31,27 @@ func (m *MockSubscriptionRepository) UpsertSubscription(ctx context.Context, d d
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockSubscriptionRepository) GetSubscriptionEmailsByUserIDAndEmails(ctx context.Context, id string, emails []string) ([]string, error) {
-	args := m.Called(ctx, id, emails)
-	return args.Get(0).([]string), args.Error(1)
+func (m *MockSubscriptionRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockSubscriptionRepository) GetSubscriptionEmailsByUserIDAndEmails(ctx context.Context, id string, emails []string, page domain.Page) ([]string, string, error) {
+	args := m.Called(ctx, id, emails, page)
+	return args.Get(0).([]string), args.String(1), args.Error(2)
+}
+
+func (m *MockSubscriptionRepository) GetSubscriberEmails(ctx context.Context, id string, page domain.Page) ([]string, string, error) {
+	args := m.Called(ctx, id, page)
+	return args.Get(0).([]string), args.String(1), args.Error(2)
+}
+
+func (m *MockSubscriptionRepository) ListSubscriptionsByUserID(ctx context.Context, userID, afterID string, limit int) (domain.Subscriptions, error) {
+	args := m.Called(ctx, userID, afterID, limit)
+	return args.Get(0).(domain.Subscriptions), args.Error(1)
+}
+
+func (m *MockSubscriptionRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
 }
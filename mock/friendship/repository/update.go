@@ -0,0 +1,32 @@
+package mockfriendshiprepo
+
+import (
+	"context"
+
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockUpdateRepository struct {
+	mock.Mock
+}
+
+func (m *MockUpdateRepository) Create(ctx context.Context, d domain.Update) (string, error) {
+	args := m.Called(ctx, d)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockUpdateRepository) GetFeed(ctx context.Context, userID string, page domain.Page) ([]domain.FeedItem, string, error) {
+	args := m.Called(ctx, userID, page)
+	return args.Get(0).([]domain.FeedItem), args.String(1), args.Error(2)
+}
+
+func (m *MockUpdateRepository) ListUpdatesByUserID(ctx context.Context, userID, afterID string, limit int) ([]domain.Update, error) {
+	args := m.Called(ctx, userID, afterID, limit)
+	return args.Get(0).([]domain.Update), args.Error(1)
+}
+
+func (m *MockUpdateRepository) RemoveMentions(ctx context.Context, email string) error {
+	args := m.Called(ctx, email)
+	return args.Error(0)
+}
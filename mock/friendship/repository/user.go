@@ -2,7 +2,9 @@ package mockfriendshiprepo
 
 import (
 	"context"
+	"time"
 
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -19,3 +21,58 @@ func (m *MockUserRepository) GetEmailsByUserIDs(ctx context.Context, userIDs []s
 	args := m.Called(ctx, userIDs)
 	return args.Get(0).(map[string]string), args.Error(1)
 }
+
+func (m *MockUserRepository) Create(ctx context.Context, d domain.User) (string, error) {
+	args := m.Called(ctx, d)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockUserRepository) GetUserByEmail(ctx context.Context, email string) (domain.User, error) {
+	args := m.Called(ctx, email)
+	return args.Get(0).(domain.User), args.Error(1)
+}
+
+func (m *MockUserRepository) Update(ctx context.Context, d domain.User) error {
+	args := m.Called(ctx, d)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) GetPasswordHash(ctx context.Context, userID string) (string, error) {
+	args := m.Called(ctx, userID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockUserRepository) SetPasswordHash(ctx context.Context, userID, hash string) error {
+	args := m.Called(ctx, userID, hash)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) Anonymize(ctx context.Context, id, tombstone string) error {
+	args := m.Called(ctx, id, tombstone)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) ScheduleDeletion(ctx context.Context, d domain.UserDeletion) error {
+	args := m.Called(ctx, d)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) GetDeletion(ctx context.Context, userID string) (domain.UserDeletion, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(domain.UserDeletion), args.Error(1)
+}
+
+func (m *MockUserRepository) CancelDeletion(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) GetDueDeletions(ctx context.Context, now time.Time, limit int) ([]domain.UserDeletion, error) {
+	args := m.Called(ctx, now, limit)
+	return args.Get(0).([]domain.UserDeletion), args.Error(1)
+}
@@ -0,0 +1,67 @@
+package mockfriendshiprepo
+
+import (
+	"context"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockWebhookRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebhookRepository) Create(ctx context.Context, w domain.Webhook) (string, error) {
+	args := m.Called(ctx, w)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockWebhookRepository) List(ctx context.Context) ([]domain.Webhook, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]domain.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) GetByEventType(ctx context.Context, t domain.EventType) ([]domain.Webhook, error) {
+	args := m.Called(ctx, t)
+	return args.Get(0).([]domain.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookRepository) CreateDelivery(ctx context.Context, d domain.WebhookDelivery) (string, error) {
+	args := m.Called(ctx, d)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockWebhookRepository) GetDelivery(ctx context.Context, id string) (domain.WebhookDelivery, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(domain.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookRepository) GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]domain.DueWebhookDelivery, error) {
+	args := m.Called(ctx, now, limit)
+	return args.Get(0).([]domain.DueWebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookRepository) UpdateDelivery(ctx context.Context, d domain.WebhookDelivery) error {
+	args := m.Called(ctx, d)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) GetDeliveriesByStatus(ctx context.Context, webhookID string, status domain.WebhookDeliveryStatus) ([]domain.WebhookDelivery, error) {
+	args := m.Called(ctx, webhookID, status)
+	return args.Get(0).([]domain.WebhookDelivery), args.Error(1)
+}
+
+type MockWebhookSender struct {
+	mock.Mock
+}
+
+func (m *MockWebhookSender) Send(ctx context.Context, w domain.Webhook, d domain.WebhookDelivery) error {
+	args := m.Called(ctx, w, d)
+	return args.Error(0)
+}
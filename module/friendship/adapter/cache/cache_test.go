@@ -0,0 +1,58 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/adapter/memory"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+// countingUserRepo counts the lookups reaching the repository
+type countingUserRepo struct {
+	domain.UserRepo
+	calls [][]string
+}
+
+func (c *countingUserRepo) GetUserIDsByEmails(ctx context.Context, emails []string) (map[string]string, error) {
+	c.calls = append(c.calls, emails)
+	return c.UserRepo.GetUserIDsByEmails(ctx, emails)
+}
+
+// countingFriendshipRepo counts the friend lists loaded from the repository
+type countingFriendshipRepo struct {
+	domain.FriendshipRepo
+	calls int
+}
+
+func (c *countingFriendshipRepo) GetFriendshipByUserIDAndStatus(ctx context.Context, mapEmailUser map[string]string, page domain.Page, status ...domain.FriendshipStatus) ([]string, string, error) {
+	c.calls++
+	return c.FriendshipRepo.GetFriendshipByUserIDAndStatus(ctx, mapEmailUser, page, status...)
+}
+
+// prepareUsers creates the users and returns their ids by email
+func prepareUsers(t *testing.T, ctx context.Context, store *memory.Store, emails ...string) map[string]string {
+	repo := memory.NewUserRepository(store)
+	ids := make(map[string]string, len(emails))
+	for _, email := range emails {
+		id, err := repo.Create(ctx, domain.User{Email: email})
+		assert.NoError(t, err)
+		ids[email] = id
+	}
+	return ids
+}
+
+// racingUserRepo loads the emails, then runs duringLoad before returning them
+type racingUserRepo struct {
+	domain.UserRepo
+	duringLoad func()
+}
+
+func (r *racingUserRepo) GetEmailsByUserIDs(ctx context.Context, userIDs []string) (map[string]string, error) {
+	emails, err := r.UserRepo.GetEmailsByUserIDs(ctx, userIDs)
+	if r.duringLoad != nil {
+		r.duringLoad()
+	}
+	return emails, err
+}
@@ -0,0 +1,160 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	kv "github.com/phantranhieunhan/s3-assignment/pkg/cache"
+	"github.com/phantranhieunhan/s3-assignment/pkg/util"
+)
+
+// friendsVersionKey is the key of the version of the friend lists of the user, or of all of them for an empty user id.
+// A friend list is cached under the versions of its users, invalidating a version drops every list it is part of
+func friendsVersionKey(userID string) string {
+	if userID == "" {
+		return "friends:version"
+	}
+	return "friends:version:" + userID
+}
+
+type friendsPage struct {
+	Emails     []string `json:"emails"`
+	NextCursor string   `json:"next_cursor"`
+}
+
+// FriendshipRepository caches the friend lists
+type FriendshipRepository struct {
+	domain.FriendshipRepo
+	cache kv.Cache
+	ttl   time.Duration
+}
+
+func NewFriendshipRepository(next domain.FriendshipRepo, cache kv.Cache, ttl time.Duration) FriendshipRepository {
+	return FriendshipRepository{
+		FriendshipRepo: next,
+		cache:          cache,
+		ttl:            ttl,
+	}
+}
+
+func (f FriendshipRepository) Create(ctx context.Context, d domain.Friendship) (string, error) {
+	id, err := f.FriendshipRepo.Create(ctx, d)
+	if err != nil {
+		return "", err
+	}
+	invalidateAfterTransaction(ctx, f.cache, friendsVersionKey(d.UserID), friendsVersionKey(d.FriendID))
+	return id, nil
+}
+
+func (f FriendshipRepository) Update(ctx context.Context, d domain.Friendship) error {
+	if err := f.FriendshipRepo.Update(ctx, d); err != nil {
+		return err
+	}
+	invalidateAfterTransaction(ctx, f.cache, friendsVersionKey(d.UserID), friendsVersionKey(d.FriendID))
+	return nil
+}
+
+// UpdateStatus invalidates the friend lists of the users of the friendship when it was read in the same transaction,
+// as the commands do, and all of the friend lists otherwise
+func (f FriendshipRepository) UpdateStatus(ctx context.Context, id string, status domain.FriendshipStatus) error {
+	if err := f.FriendshipRepo.UpdateStatus(ctx, id, status); err != nil {
+		return err
+	}
+	keys := []string{friendsVersionKey("")}
+	if p := pendingFrom(ctx); p != nil {
+		if users, ok := p.friendship(id); ok {
+			keys = []string{friendsVersionKey(users[0]), friendsVersionKey(users[1])}
+		}
+	}
+	invalidateAfterTransaction(ctx, f.cache, keys...)
+	return nil
+}
+
+// DeleteByUserID invalidates all of the friend lists, the friends of the user are not known here
+func (f FriendshipRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	if err := f.FriendshipRepo.DeleteByUserID(ctx, userID); err != nil {
+		return err
+	}
+	invalidateAfterTransaction(ctx, f.cache, friendsVersionKey(""))
+	return nil
+}
+
+func (f FriendshipRepository) GetFriendshipByUserIDs(ctx context.Context, userID, friendID string) (domain.Friendship, error) {
+	d, err := f.FriendshipRepo.GetFriendshipByUserIDs(ctx, userID, friendID)
+	if err != nil {
+		return d, err
+	}
+	if p := pendingFrom(ctx); p != nil {
+		p.addFriendship(d.Id, d.UserID, d.FriendID)
+	}
+	return d, nil
+}
+
+func (f FriendshipRepository) GetFriendshipByUserIDAndStatus(ctx context.Context, mapEmailUser map[string]string, page domain.Page, status ...domain.FriendshipStatus) ([]string, string, error) {
+	if pendingFrom(ctx) != nil {
+		return f.FriendshipRepo.GetFriendshipByUserIDAndStatus(ctx, mapEmailUser, page, status...)
+	}
+
+	key := f.friendsKey(ctx, util.MapValuesToSlice(mapEmailUser), page, status)
+	if b, ok, err := f.cache.Get(ctx, key); err != nil {
+		logger.Errorf("cache.Get %w", err)
+	} else if ok {
+		var cached friendsPage
+		if err := json.Unmarshal(b, &cached); err == nil {
+			return cached.Emails, cached.NextCursor, nil
+		}
+	}
+
+	emails, nextCursor, err := f.FriendshipRepo.GetFriendshipByUserIDAndStatus(ctx, mapEmailUser, page, status...)
+	if err != nil {
+		return emails, nextCursor, err
+	}
+	b, err := json.Marshal(friendsPage{Emails: emails, NextCursor: nextCursor})
+	if err == nil {
+		err = f.cache.Set(ctx, key, b, f.ttl)
+	}
+	if err != nil {
+		logger.Errorf("cache.Set %w", err)
+	}
+	return emails, nextCursor, nil
+}
+
+// friendsKey is the key of the friend list of the users with the current versions of their friend lists
+func (f FriendshipRepository) friendsKey(ctx context.Context, userIDs []string, page domain.Page, status []domain.FriendshipStatus) string {
+	sort.Strings(userIDs)
+	versions := make([]string, 0, len(userIDs)+1)
+	versions = append(versions, currentVersion(ctx, f.cache, friendsVersionKey(""), f.ttl))
+	for _, id := range userIDs {
+		versions = append(versions, id+"@"+currentVersion(ctx, f.cache, friendsVersionKey(id), f.ttl))
+	}
+
+	var since int64
+	if !page.Since.IsZero() {
+		since = page.Since.UnixNano()
+	}
+	return fmt.Sprintf("friends:list:%s:%v:%d:%s:%s:%d", strings.Join(versions, ","), status, page.Limit, page.Sort, page.Cursor, since)
+}
+
+// currentVersion returns the version stored under the key, a new one when it was invalidated. A value cached under
+// the version read before loading it is never read again once the version is invalidated, even when it is stored after
+func currentVersion(ctx context.Context, cache kv.Cache, key string, ttl time.Duration) string {
+	b, ok, err := cache.Get(ctx, key)
+	if err != nil {
+		logger.Errorf("cache.Get %w", err)
+	}
+	if ok {
+		return string(b)
+	}
+
+	version := util.GenUUID()
+	if err := cache.Set(ctx, key, []byte(version), ttl); err != nil {
+		logger.Errorf("cache.Set %w", err)
+	}
+	return version
+}
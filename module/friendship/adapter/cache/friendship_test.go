@@ -0,0 +1,72 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/adapter/memory"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	kv "github.com/phantranhieunhan/s3-assignment/pkg/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFriendshipRepository_GetFriendshipByUserIDAndStatus(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	ids := prepareUsers(t, ctx, store, "a@example.com", "b@example.com", "c@example.com", "d@example.com")
+	cache := kv.NewLRU(100)
+	next := &countingFriendshipRepo{FriendshipRepo: memory.NewFriendshipRepository(store)}
+	repo := NewFriendshipRepository(next, cache, time.Minute)
+	userRepo := NewUserRepository(memory.NewUserRepository(store), cache, time.Minute)
+	transactor := NewTransactor(store, cache)
+
+	a := map[string]string{"a@example.com": ids["a@example.com"]}
+	list := func(page domain.Page) []string {
+		emails, _, err := repo.GetFriendshipByUserIDAndStatus(ctx, a, page, domain.FriendshipStatusFriended)
+		assert.NoError(t, err)
+		return emails
+	}
+	connect := func(ctx context.Context, email string) {
+		_, err := repo.Create(ctx, domain.Friendship{UserID: ids["a@example.com"], FriendID: ids[email], Status: domain.FriendshipStatusFriended})
+		assert.NoError(t, err)
+	}
+	connect(ctx, "b@example.com")
+
+	assert.Equal(t, []string{"b@example.com"}, list(domain.Page{}))
+	assert.Equal(t, []string{"b@example.com"}, list(domain.Page{}))
+	assert.Equal(t, 1, next.calls)
+	// another page is another entry
+	assert.Equal(t, []string{"b@example.com"}, list(domain.Page{Limit: 1}))
+	assert.Equal(t, 2, next.calls)
+
+	err := transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		connect(ctx, "c@example.com")
+		// a list cached before the commit is dropped with it
+		assert.Equal(t, []string{"b@example.com"}, list(domain.Page{}))
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"b@example.com", "c@example.com"}, list(domain.Page{}))
+
+	// the friendship read in the transaction tells the users of the changed status
+	err = transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		f, err := repo.GetFriendshipByUserIDs(ctx, ids["a@example.com"], ids["b@example.com"])
+		assert.NoError(t, err)
+		return repo.UpdateStatus(ctx, f.Id, domain.FriendshipStatusUnfriended)
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"c@example.com"}, list(domain.Page{}))
+
+	// the lists of the other users are kept
+	d := map[string]string{"d@example.com": ids["d@example.com"]}
+	_, _, err = repo.GetFriendshipByUserIDAndStatus(ctx, d, domain.Page{}, domain.FriendshipStatusFriended)
+	assert.Equal(t, domain.ErrRecordNotFound, err)
+	calls := next.calls
+	assert.Equal(t, []string{"c@example.com"}, list(domain.Page{}))
+	assert.Equal(t, calls, next.calls)
+
+	// a renamed friend shows in every list
+	assert.NoError(t, userRepo.Update(ctx, domain.User{Base: domain.Base{Id: ids["c@example.com"]}, Email: "c.new@example.com"}))
+	assert.Equal(t, []string{"c.new@example.com"}, list(domain.Page{}))
+}
@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"context"
+	"sync"
+
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command"
+	kv "github.com/phantranhieunhan/s3-assignment/pkg/cache"
+)
+
+type pendingKey struct{}
+
+// pending collects the keys to invalidate once the transaction is over and the friendships read within it
+type pending struct {
+	mu          sync.Mutex
+	keys        []string
+	friendships map[string][2]string
+}
+
+func pendingFrom(ctx context.Context) *pending {
+	if p, ok := ctx.Value(pendingKey{}).(*pending); ok {
+		return p
+	}
+	return nil
+}
+
+func (p *pending) add(keys ...string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.keys = append(p.keys, keys...)
+}
+
+func (p *pending) addFriendship(id, userID, friendID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.friendships[id] = [2]string{userID, friendID}
+}
+
+func (p *pending) friendship(id string) ([2]string, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	users, ok := p.friendships[id]
+	return users, ok
+}
+
+// Transactor invalidates the entries changed by a transaction after it is over, so that a reader
+// can't cache the state before the commit again. The cached repositories bypass the cache within a transaction
+type Transactor struct {
+	next  command.Transactor
+	cache kv.Cache
+}
+
+func NewTransactor(next command.Transactor, cache kv.Cache) Transactor {
+	return Transactor{
+		next:  next,
+		cache: cache,
+	}
+}
+
+func (t Transactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	// a nested transaction leaves the invalidation to the outermost one
+	if pendingFrom(ctx) != nil {
+		return t.next.WithinTransaction(ctx, fn)
+	}
+
+	p := &pending{friendships: make(map[string][2]string)}
+	err := t.next.WithinTransaction(context.WithValue(ctx, pendingKey{}, p), fn)
+	// the keys are invalidated after a rollback as well, a needless miss is harmless
+	invalidate(ctx, t.cache, p.keys...)
+	return err
+}
+
+// invalidate deletes the keys, a failure is logged and the entries expire with their ttl
+func invalidate(ctx context.Context, cache kv.Cache, keys ...string) {
+	if len(keys) == 0 {
+		return
+	}
+	if err := cache.Delete(ctx, keys...); err != nil {
+		logger.Errorf("cache.Delete %w", err)
+	}
+}
+
+// invalidateAfterTransaction invalidates the keys once the transaction of the context is over, right away outside of a transaction
+func invalidateAfterTransaction(ctx context.Context, cache kv.Cache, keys ...string) {
+	if p := pendingFrom(ctx); p != nil {
+		p.add(keys...)
+		return
+	}
+	invalidate(ctx, cache, keys...)
+}
@@ -0,0 +1,127 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	kv "github.com/phantranhieunhan/s3-assignment/pkg/cache"
+)
+
+// userEmailVersionKey is the key of the version of the lookup of the email, the lookup is cached under its
+// current version so that invalidating the version drops the values loaded before the invalidation
+func userEmailVersionKey(email string) string {
+	return "user:email:version:" + email
+}
+
+// userIDVersionKey is the key of the version of the lookup of the id
+func userIDVersionKey(id string) string {
+	return "user:id:version:" + id
+}
+
+// UserRepository caches the lookups between the emails and the ids of the users
+type UserRepository struct {
+	domain.UserRepo
+	cache kv.Cache
+	ttl   time.Duration
+}
+
+func NewUserRepository(next domain.UserRepo, cache kv.Cache, ttl time.Duration) UserRepository {
+	return UserRepository{
+		UserRepo: next,
+		cache:    cache,
+		ttl:      ttl,
+	}
+}
+
+func (u UserRepository) GetUserIDsByEmails(ctx context.Context, emails []string) (map[string]string, error) {
+	return u.lookup(ctx, emails, userEmailVersionKey, u.UserRepo.GetUserIDsByEmails)
+}
+
+func (u UserRepository) GetEmailsByUserIDs(ctx context.Context, userIDs []string) (map[string]string, error) {
+	return u.lookup(ctx, userIDs, userIDVersionKey, u.UserRepo.GetEmailsByUserIDs)
+}
+
+// lookup returns the cached values of the keys and loads the missing ones, the not found keys are not cached.
+// A loaded value is cached under the version read before the load, a value loaded before the commit of a change
+// and stored after its invalidation is then never read
+func (u UserRepository) lookup(ctx context.Context, keys []string, versionKey func(string) string, load func(context.Context, []string) (map[string]string, error)) (map[string]string, error) {
+	if pendingFrom(ctx) != nil {
+		return load(ctx, keys)
+	}
+
+	result := make(map[string]string, len(keys))
+	missing := make([]string, 0)
+	cacheKeys := make(map[string]string, len(keys))
+	for _, key := range keys {
+		vk := versionKey(key)
+		cacheKeys[key] = vk + "@" + currentVersion(ctx, u.cache, vk, u.ttl)
+		value, ok, err := u.cache.Get(ctx, cacheKeys[key])
+		if err != nil {
+			logger.Errorf("cache.Get %w", err)
+		}
+		if ok {
+			result[key] = string(value)
+		} else {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) == 0 {
+		return result, nil
+	}
+
+	loaded, err := load(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range loaded {
+		result[key] = value
+		cacheKey, ok := cacheKeys[key]
+		if !ok {
+			continue
+		}
+		if err := u.cache.Set(ctx, cacheKey, []byte(value), u.ttl); err != nil {
+			logger.Errorf("cache.Set %w", err)
+		}
+	}
+	return result, nil
+}
+
+// Update invalidates the lookups of the old and the new email and the friend lists showing the old one
+func (u UserRepository) Update(ctx context.Context, d domain.User) error {
+	keys := append(u.userKeys(ctx, d.Base.Id), userEmailVersionKey(d.Email), friendsVersionKey(""))
+	if err := u.UserRepo.Update(ctx, d); err != nil {
+		return err
+	}
+	invalidateAfterTransaction(ctx, u.cache, keys...)
+	return nil
+}
+
+func (u UserRepository) Delete(ctx context.Context, id string) error {
+	keys := append(u.userKeys(ctx, id), friendsVersionKey(""))
+	if err := u.UserRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	invalidateAfterTransaction(ctx, u.cache, keys...)
+	return nil
+}
+
+// Anonymize invalidates the lookups of the old email and the friend lists showing it
+func (u UserRepository) Anonymize(ctx context.Context, id, tombstone string) error {
+	keys := append(u.userKeys(ctx, id), userEmailVersionKey(tombstone), friendsVersionKey(""))
+	if err := u.UserRepo.Anonymize(ctx, id, tombstone); err != nil {
+		return err
+	}
+	invalidateAfterTransaction(ctx, u.cache, keys...)
+	return nil
+}
+
+// userKeys returns the version keys of the lookups of the user as stored before the change
+func (u UserRepository) userKeys(ctx context.Context, id string) []string {
+	keys := []string{userIDVersionKey(id)}
+	if emails, err := u.UserRepo.GetEmailsByUserIDs(ctx, []string{id}); err == nil {
+		keys = append(keys, userEmailVersionKey(emails[id]))
+	}
+	return keys
+}
@@ -0,0 +1,109 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/adapter/memory"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	kv "github.com/phantranhieunhan/s3-assignment/pkg/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserRepository_GetUserIDsByEmails(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	ids := prepareUsers(t, ctx, store, "john@example.com", "lisa@example.com")
+	next := &countingUserRepo{UserRepo: memory.NewUserRepository(store)}
+	repo := NewUserRepository(next, kv.NewLRU(100), time.Minute)
+
+	result, err := repo.GetUserIDsByEmails(ctx, []string{"john@example.com"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"john@example.com": ids["john@example.com"]}, result)
+
+	// only the missing emails are loaded
+	result, err = repo.GetUserIDsByEmails(ctx, []string{"john@example.com", "lisa@example.com"})
+	assert.NoError(t, err)
+	assert.Equal(t, ids, result)
+	result, err = repo.GetUserIDsByEmails(ctx, []string{"lisa@example.com", "john@example.com"})
+	assert.NoError(t, err)
+	assert.Equal(t, ids, result)
+	assert.Equal(t, [][]string{{"john@example.com"}, {"lisa@example.com"}}, next.calls)
+
+	// an unknown email is not cached
+	_, err = repo.GetUserIDsByEmails(ctx, []string{"john@example.com", "kate@example.com"})
+	assert.Equal(t, domain.ErrNotFoundUserByEmail, err)
+	prepareUsers(t, ctx, store, "kate@example.com")
+	_, err = repo.GetUserIDsByEmails(ctx, []string{"john@example.com", "kate@example.com"})
+	assert.NoError(t, err)
+
+	emails, err := repo.GetEmailsByUserIDs(ctx, []string{ids["john@example.com"]})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{ids["john@example.com"]: "john@example.com"}, emails)
+}
+
+func TestUserRepository_UpdateInvalidatesAfterCommit(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	ids := prepareUsers(t, ctx, store, "john@example.com")
+	cache := kv.NewLRU(100)
+	repo := NewUserRepository(memory.NewUserRepository(store), cache, time.Minute)
+	transactor := NewTransactor(store, cache)
+
+	_, err := repo.GetUserIDsByEmails(ctx, []string{"john@example.com"})
+	assert.NoError(t, err)
+	_, err = repo.GetEmailsByUserIDs(ctx, []string{ids["john@example.com"]})
+	assert.NoError(t, err)
+
+	err = transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		assert.NoError(t, repo.Update(ctx, domain.User{Base: domain.Base{Id: ids["john@example.com"]}, Email: "john.new@example.com"}))
+
+		// the transaction reads past the cache
+		result, err := repo.GetUserIDsByEmails(ctx, []string{"john.new@example.com"})
+		assert.NoError(t, err)
+		assert.Equal(t, ids["john@example.com"], result["john.new@example.com"])
+
+		// the others read the cache until the commit
+		result, err = repo.GetUserIDsByEmails(context.Background(), []string{"john@example.com"})
+		assert.NoError(t, err)
+		assert.Equal(t, ids["john@example.com"], result["john@example.com"])
+		return nil
+	})
+	assert.NoError(t, err)
+
+	_, err = repo.GetUserIDsByEmails(ctx, []string{"john@example.com"})
+	assert.Equal(t, domain.ErrNotFoundUserByEmail, err)
+	emails, err := repo.GetEmailsByUserIDs(ctx, []string{ids["john@example.com"]})
+	assert.NoError(t, err)
+	assert.Equal(t, "john.new@example.com", emails[ids["john@example.com"]])
+
+	// outside of a transaction the entries are invalidated right away
+	assert.NoError(t, repo.Delete(ctx, ids["john@example.com"]))
+	_, err = repo.GetUserIDsByEmails(ctx, []string{"john.new@example.com"})
+	assert.Equal(t, domain.ErrNotFoundUserByEmail, err)
+}
+
+// a lookup loaded before the commit of a change and stored after its invalidation is not read again
+func TestUserRepository_LookupLoadedBeforeInvalidation(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	ids := prepareUsers(t, ctx, store, "john@example.com")
+	cache := kv.NewLRU(100)
+	next := &racingUserRepo{UserRepo: memory.NewUserRepository(store)}
+	repo := NewUserRepository(next, cache, time.Minute)
+
+	// the change commits and invalidates the lookup while the reader loads the email
+	next.duringLoad = func() {
+		assert.NoError(t, memory.NewUserRepository(store).Update(ctx, domain.User{Base: domain.Base{Id: ids["john@example.com"]}, Email: "john.new@example.com"}))
+		invalidate(ctx, cache, userIDVersionKey(ids["john@example.com"]))
+	}
+	emails, err := repo.GetEmailsByUserIDs(ctx, []string{ids["john@example.com"]})
+	assert.NoError(t, err)
+	assert.Equal(t, "john@example.com", emails[ids["john@example.com"]])
+
+	next.duringLoad = nil
+	emails, err = repo.GetEmailsByUserIDs(ctx, []string{ids["john@example.com"]})
+	assert.NoError(t, err)
+	assert.Equal(t, "john.new@example.com", emails[ids["john@example.com"]])
+}
@@ -0,0 +1,85 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/phantranhieunhan/s3-assignment/pkg/util"
+)
+
+type AuditRepository struct {
+	store *Store
+}
+
+func NewAuditRepository(store *Store) AuditRepository {
+	return AuditRepository{
+		store: store,
+	}
+}
+
+// Create appends the entry, the store has no way to update or delete it
+func (a AuditRepository) Create(ctx context.Context, d domain.AuditEntry) (string, error) {
+	d.ID = util.GenUUID()
+	d.TargetIDs = append([]string{}, d.TargetIDs...)
+	err := a.store.write(ctx, func(t *tables) error {
+		t.audit.writable()[d.ID] = d
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return d.ID, nil
+}
+
+func (a AuditRepository) List(ctx context.Context, filter domain.AuditFilter, page domain.Page) ([]domain.AuditEntry, string, error) {
+	page = page.WithDefaults()
+	t := a.store.read(ctx)
+
+	var cursor domain.Cursor
+	if page.Cursor != "" {
+		var err error
+		if cursor, err = domain.DecodeCursor(page.Cursor); err != nil {
+			return nil, "", err
+		}
+	}
+	newer := func(aCreatedAt time.Time, aID string, bCreatedAt time.Time, bID string) bool {
+		if !aCreatedAt.Equal(bCreatedAt) {
+			return aCreatedAt.After(bCreatedAt)
+		}
+		return aID > bID
+	}
+
+	list := make([]domain.AuditEntry, 0)
+	for _, e := range t.audit.rows {
+		if filter.UserID != "" && e.Actor != filter.UserID && !util.IsContain(e.TargetIDs, filter.UserID) {
+			continue
+		}
+		if filter.Action != "" && e.Action != filter.Action {
+			continue
+		}
+		if !filter.From.IsZero() && e.CreatedAt.Before(filter.From) {
+			continue
+		}
+		if !filter.To.IsZero() && !e.CreatedAt.Before(filter.To) {
+			continue
+		}
+		if page.Cursor != "" && !newer(cursor.CreatedAt, cursor.Key, e.CreatedAt, e.ID) {
+			continue
+		}
+		e.TargetIDs = append([]string{}, e.TargetIDs...)
+		list = append(list, e)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return newer(list[i].CreatedAt, list[i].ID, list[j].CreatedAt, list[j].ID)
+	})
+
+	var nextCursor string
+	if len(list) > page.Limit {
+		list = list[:page.Limit]
+		last := list[len(list)-1]
+		nextCursor = domain.EncodeCursor(domain.Cursor{Key: last.ID, CreatedAt: last.CreatedAt})
+	}
+	return list, nextCursor, nil
+}
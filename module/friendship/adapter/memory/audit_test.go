@@ -0,0 +1,33 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAudit_CreateList(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore()
+	repo := NewAuditRepository(store)
+
+	targetIDs := []string{"user-1", "user-2"}
+	id, err := repo.Create(ctx, domain.AuditEntry{Actor: "user-1", Action: domain.AuditConnectFriendship, TargetIDs: targetIDs, CreatedAt: time.Now().UTC()})
+	assert.NoError(t, err)
+
+	// the stored entry shares nothing with the ones given and returned
+	targetIDs[0] = "changed"
+	list, _, err := repo.List(ctx, domain.AuditFilter{UserID: "user-1"}, domain.Page{})
+	assert.NoError(t, err)
+	if assert.Len(t, list, 1) {
+		assert.Equal(t, id, list[0].ID)
+		assert.Equal(t, []string{"user-1", "user-2"}, list[0].TargetIDs)
+		list[0].TargetIDs[1] = "changed"
+	}
+	list, _, err = repo.List(ctx, domain.AuditFilter{UserID: "user-2"}, domain.Page{})
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+}
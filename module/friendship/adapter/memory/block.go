@@ -0,0 +1,128 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/phantranhieunhan/s3-assignment/pkg/util"
+)
+
+type BlockRepository struct {
+	store *Store
+}
+
+func NewBlockRepository(store *Store) BlockRepository {
+	return BlockRepository{
+		store: store,
+	}
+}
+
+func (t *tables) block(userID, targetID string) (domain.Block, bool) {
+	for _, m := range t.blocks.rows {
+		if m.UserID == userID && m.TargetID == targetID {
+			return m, true
+		}
+	}
+	return domain.Block{}, false
+}
+
+func (b BlockRepository) UpsertBlock(ctx context.Context, d domain.Block) (string, error) {
+	now := time.Now().UTC()
+	err := b.store.write(ctx, func(t *tables) error {
+		if !t.usersExist(d.UserID, d.TargetID) {
+			return common.ErrDB(ErrForeignKeyViolation)
+		}
+		if m, ok := t.block(d.UserID, d.TargetID); ok {
+			m.FriendshipStatus = d.FriendshipStatus
+			m.SubscriptionStatus = d.SubscriptionStatus
+			m.UpdatedAt = now
+			t.blocks.writable()[m.Id] = m
+			d.Id = m.Id
+			return nil
+		}
+		d.Id = util.GenUUID()
+		d.CreatedAt = now
+		d.UpdatedAt = now
+		t.blocks.writable()[d.Id] = d
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return d.Id, nil
+}
+
+func (b BlockRepository) GetBlock(ctx context.Context, userID, targetID string) (domain.Block, error) {
+	m, ok := b.store.read(ctx).block(userID, targetID)
+	if !ok {
+		return domain.Block{}, domain.ErrRecordNotFound
+	}
+	return m, nil
+}
+
+func (b BlockRepository) Delete(ctx context.Context, id string) error {
+	return b.store.write(ctx, func(t *tables) error {
+		if _, ok := t.blocks.rows[id]; ok {
+			delete(t.blocks.writable(), id)
+		}
+		return nil
+	})
+}
+
+// DeleteByUserID deletes the blocks made by the user and against the user
+func (b BlockRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	return b.store.write(ctx, func(t *tables) error {
+		for id, m := range t.blocks.rows {
+			if m.UserID == userID || m.TargetID == userID {
+				delete(t.blocks.writable(), id)
+			}
+		}
+		return nil
+	})
+}
+
+func (b BlockRepository) GetBlockedEmailsByUserID(ctx context.Context, userID string) ([]domain.BlockedEmail, error) {
+	return b.getBlockedEmails(ctx, func(m domain.Block) (string, bool) {
+		return m.TargetID, m.UserID == userID
+	}), nil
+}
+
+func (b BlockRepository) GetBlockerEmailsByTargetID(ctx context.Context, targetID string) ([]domain.BlockedEmail, error) {
+	return b.getBlockedEmails(ctx, func(m domain.Block) (string, bool) {
+		return m.UserID, m.TargetID == targetID
+	}), nil
+}
+
+// ListBlocksByUserID lists the blocks made by the user, ordered by id from the one after afterID
+func (b BlockRepository) ListBlocksByUserID(ctx context.Context, userID, afterID string, limit int) ([]domain.Block, error) {
+	return listAfter(b.store.read(ctx).blocks, afterID, limit, func(m domain.Block) bool {
+		return m.UserID == userID
+	}), nil
+}
+
+// getBlockedEmails lists the other side of the blocks kept by the filter, the latest block first
+func (b BlockRepository) getBlockedEmails(ctx context.Context, filter func(m domain.Block) (string, bool)) []domain.BlockedEmail {
+	t := b.store.read(ctx)
+
+	blocks := make([]domain.Block, 0)
+	for _, m := range t.blocks.rows {
+		if _, ok := filter(m); ok {
+			blocks = append(blocks, m)
+		}
+	}
+	sort.Slice(blocks, func(i, j int) bool {
+		return blocks[i].UpdatedAt.After(blocks[j].UpdatedAt)
+	})
+
+	result := make([]domain.BlockedEmail, 0, len(blocks))
+	for _, m := range blocks {
+		otherID, _ := filter(m)
+		if u, ok := t.users.rows[otherID]; ok {
+			result = append(result, domain.BlockedEmail{Email: u.Email, BlockedAt: m.UpdatedAt})
+		}
+	}
+	return result
+}
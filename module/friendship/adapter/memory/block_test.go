@@ -0,0 +1,47 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlock_UpsertGetDelete(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore()
+	repo := NewBlockRepository(store)
+	ids := prepareUsers(t, ctx, store, "john@example.com", "lisa@example.com", "kate@example.com")
+
+	block := domain.Block{UserID: ids["john@example.com"], TargetID: ids["lisa@example.com"], FriendshipStatus: domain.FriendshipStatusFriended}
+	id, err := repo.UpsertBlock(ctx, block)
+	assert.NoError(t, err)
+
+	// blocking again keeps the same row with the latest state
+	block.FriendshipStatus = domain.FriendshipStatusUnfriended
+	upsertedID, err := repo.UpsertBlock(ctx, block)
+	assert.NoError(t, err)
+	assert.Equal(t, id, upsertedID)
+	result, err := repo.GetBlock(ctx, block.UserID, block.TargetID)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.FriendshipStatusUnfriended, result.FriendshipStatus)
+
+	// a block is one-way
+	_, err = repo.GetBlock(ctx, block.TargetID, block.UserID)
+	assert.Equal(t, domain.ErrRecordNotFound, err)
+
+	_, err = repo.UpsertBlock(ctx, domain.Block{UserID: ids["kate@example.com"], TargetID: ids["lisa@example.com"]})
+	assert.NoError(t, err)
+	blockers, err := repo.GetBlockerEmailsByTargetID(ctx, ids["lisa@example.com"])
+	assert.NoError(t, err)
+	assert.Equal(t, "kate@example.com", blockers[0].Email)
+	assert.Len(t, blockers, 2)
+	blocked, err := repo.GetBlockedEmailsByUserID(ctx, ids["john@example.com"])
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.BlockedEmail{{Email: "lisa@example.com", BlockedAt: result.UpdatedAt}}, blocked)
+
+	assert.NoError(t, repo.Delete(ctx, id))
+	_, err = repo.GetBlock(ctx, block.UserID, block.TargetID)
+	assert.Equal(t, domain.ErrRecordNotFound, err)
+}
@@ -0,0 +1,80 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/phantranhieunhan/s3-assignment/pkg/util"
+)
+
+// eventRow is an event of the outbox, seq keeps the order the events were written in
+type eventRow struct {
+	domain.Event
+	seq         int64
+	deliveredAt *time.Time
+}
+
+type EventRepository struct {
+	store *Store
+}
+
+func NewEventRepository(store *Store) EventRepository {
+	return EventRepository{
+		store: store,
+	}
+}
+
+func (e EventRepository) Create(ctx context.Context, d domain.Event) (string, error) {
+	d.ID = util.GenUUID()
+	err := e.store.write(ctx, func(t *tables) error {
+		t.eventSeq++
+		t.events.writable()[d.ID] = eventRow{Event: d, seq: t.eventSeq}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return d.ID, nil
+}
+
+// GetUndelivered returns the oldest events not delivered yet, the transactions of the store run one at a time
+// so the events are never relayed by two transactions at once
+func (e EventRepository) GetUndelivered(ctx context.Context, limit int) ([]domain.Event, error) {
+	t := e.store.read(ctx)
+
+	rows := make([]eventRow, 0)
+	for _, r := range t.events.rows {
+		if r.deliveredAt == nil {
+			rows = append(rows, r)
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i].seq < rows[j].seq
+	})
+	if len(rows) > limit {
+		rows = rows[:limit]
+	}
+
+	result := make([]domain.Event, 0, len(rows))
+	for _, r := range rows {
+		result = append(result, r.Event)
+	}
+	return result, nil
+}
+
+func (e EventRepository) MarkDelivered(ctx context.Context, ids []string) error {
+	now := time.Now().UTC()
+	return e.store.write(ctx, func(t *tables) error {
+		for _, id := range ids {
+			r, ok := t.events.rows[id]
+			if !ok {
+				continue
+			}
+			r.deliveredAt = &now
+			t.events.writable()[id] = r
+		}
+		return nil
+	})
+}
@@ -0,0 +1,37 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvent_CreateGetUndeliveredMarkDelivered(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore()
+	repo := NewEventRepository(store)
+
+	ids := make([]string, 0, 3)
+	for _, aggregateID := range []string{"c", "a", "b"} {
+		e, err := domain.NewEvent(domain.EventUserCreated, aggregateID, domain.UserEvent{Email: aggregateID})
+		assert.NoError(t, err)
+		id, err := repo.Create(ctx, e)
+		assert.NoError(t, err)
+		ids = append(ids, id)
+	}
+
+	// in the order they were written
+	events, err := repo.GetUndelivered(ctx, 2)
+	assert.NoError(t, err)
+	assert.Len(t, events, 2)
+	assert.Equal(t, ids[0], events[0].ID)
+	assert.Equal(t, ids[1], events[1].ID)
+
+	assert.NoError(t, repo.MarkDelivered(ctx, []string{ids[0], ids[1]}))
+	events, err = repo.GetUndelivered(ctx, 2)
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+	assert.Equal(t, ids[2], events[0].ID)
+}
@@ -0,0 +1,351 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/phantranhieunhan/s3-assignment/pkg/util"
+)
+
+type FriendshipRepository struct {
+	store *Store
+}
+
+func NewFriendshipRepository(store *Store) FriendshipRepository {
+	return FriendshipRepository{
+		store: store,
+	}
+}
+
+func (f FriendshipRepository) Create(ctx context.Context, d domain.Friendship) (string, error) {
+	d.Id = util.GenUUID()
+	now := time.Now().UTC()
+	if d.CreatedAt.IsZero() {
+		d.CreatedAt = now
+	}
+	if d.UpdatedAt.IsZero() {
+		d.UpdatedAt = now
+	}
+
+	err := f.store.write(ctx, func(t *tables) error {
+		if !t.usersExist(d.UserID, d.FriendID) {
+			return common.ErrDB(ErrForeignKeyViolation)
+		}
+		t.friendships.writable()[d.Id] = d
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return d.Id, nil
+}
+
+func (f FriendshipRepository) UpdateStatus(ctx context.Context, id string, status domain.FriendshipStatus) error {
+	return f.store.write(ctx, func(t *tables) error {
+		m, ok := t.friendships.rows[id]
+		if !ok {
+			return nil
+		}
+		m.Status = status
+		m.UpdatedAt = time.Now().UTC()
+		t.friendships.writable()[id] = m
+		return nil
+	})
+}
+
+func (f FriendshipRepository) Update(ctx context.Context, d domain.Friendship) error {
+	return f.store.write(ctx, func(t *tables) error {
+		m, ok := t.friendships.rows[d.Id]
+		if !ok {
+			return nil
+		}
+		if !t.usersExist(d.UserID, d.FriendID) {
+			return common.ErrDB(ErrForeignKeyViolation)
+		}
+		m.UserID = d.UserID
+		m.FriendID = d.FriendID
+		m.Status = d.Status
+		m.UpdatedAt = time.Now().UTC()
+		t.friendships.writable()[d.Id] = m
+		return nil
+	})
+}
+
+func (f FriendshipRepository) GetFriendshipByUserIDs(ctx context.Context, userID, friendID string) (domain.Friendship, error) {
+	for _, m := range f.store.read(ctx).friendships.rows {
+		if (m.UserID == userID && m.FriendID == friendID) || (m.UserID == friendID && m.FriendID == userID) {
+			return m, nil
+		}
+	}
+	return domain.Friendship{}, domain.ErrRecordNotFound
+}
+
+// DeleteByUserID deletes the friendships of the user in both directions
+func (f FriendshipRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	return f.store.write(ctx, func(t *tables) error {
+		for id, m := range t.friendships.rows {
+			if m.UserID == userID || m.FriendID == userID {
+				delete(t.friendships.writable(), id)
+			}
+		}
+		return nil
+	})
+}
+
+// ListFriendshipsByUserID lists the friendships of the user in both directions and any status,
+// ordered by id from the one after afterID
+func (f FriendshipRepository) ListFriendshipsByUserID(ctx context.Context, userID, afterID string, limit int) (domain.Friendships, error) {
+	return listAfter(f.store.read(ctx).friendships, afterID, limit, func(m domain.Friendship) bool {
+		return m.UserID == userID || m.FriendID == userID
+	}), nil
+}
+
+// friendsOf returns the other side of the friendships of the user having one of the statuses,
+// with the time of the friendship
+func (t *tables) friendsOf(userID string, status ...domain.FriendshipStatus) map[string]time.Time {
+	result := make(map[string]time.Time)
+	for _, m := range t.friendships.rows {
+		if !hasFriendshipStatus(m.Status, status) {
+			continue
+		}
+		switch userID {
+		case m.UserID:
+			result[m.FriendID] = m.CreatedAt
+		case m.FriendID:
+			result[m.UserID] = m.CreatedAt
+		}
+	}
+	return result
+}
+
+func hasFriendshipStatus(s domain.FriendshipStatus, status []domain.FriendshipStatus) bool {
+	for _, v := range status {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+// friendCounts counts for each friend of the users how many of the users it is friend with, the users themselves are left out,
+// a friend is dated by its latest friendship
+func (t *tables) friendCounts(userIDs []string, status ...domain.FriendshipStatus) (map[string]int, map[string]time.Time, map[string]bool) {
+	inputs := make(map[string]bool, len(userIDs))
+	for _, id := range userIDs {
+		inputs[id] = true
+	}
+
+	counts := make(map[string]int)
+	dates := make(map[string]time.Time)
+	// friendOfFirst tells whether the friend is a friend of the first user
+	friendOfFirst := make(map[string]bool)
+	for i, id := range util.RemoveDuplicates(userIDs) {
+		for friendID, createdAt := range t.friendsOf(id, status...) {
+			if inputs[friendID] {
+				continue
+			}
+			counts[friendID]++
+			if createdAt.After(dates[friendID]) {
+				dates[friendID] = createdAt
+			}
+			if i == 0 {
+				friendOfFirst[friendID] = true
+			}
+		}
+	}
+	return counts, dates, friendOfFirst
+}
+
+// GetFriendshipByUserIDAndStatus lists a page of the friends shared by all the users, a single user gives its own friends.
+// A friend shared by several users is dated by its latest friendship
+func (f FriendshipRepository) GetFriendshipByUserIDAndStatus(ctx context.Context, mapEmailUser map[string]string, page domain.Page, status ...domain.FriendshipStatus) ([]string, string, error) {
+	emptyList := []string{}
+	page = page.WithDefaults()
+	t := f.store.read(ctx)
+
+	userIDs := util.MapValuesToSlice(mapEmailUser)
+	counts, dates, _ := t.friendCounts(userIDs, status...)
+
+	list := make([]pageEmail, 0, len(counts))
+	for friendID, count := range counts {
+		u, ok := t.users.rows[friendID]
+		if !ok || count != len(userIDs) {
+			continue
+		}
+		list = append(list, pageEmail{Email: u.Email, CreatedAt: dates[friendID]})
+	}
+
+	result, nextCursor, err := pageEmails(list, page)
+	if err != nil {
+		return emptyList, "", err
+	}
+	if len(result) == 0 {
+		return emptyList, "", domain.ErrRecordNotFound
+	}
+	return result, nextCursor, nil
+}
+
+func (f FriendshipRepository) GetFriendRequestEmails(ctx context.Context, userID string, direction domain.FriendRequestDirection) ([]string, error) {
+	emptyList := []string{}
+	incoming := direction == domain.FriendRequestDirectionIncoming
+	if !incoming && direction != domain.FriendRequestDirectionOutgoing {
+		return emptyList, domain.ErrFriendRequestDirectionIsNotValid
+	}
+	t := f.store.read(ctx)
+
+	requests := make([]domain.Friendship, 0)
+	for _, m := range t.friendships.rows {
+		if m.Status != domain.FriendshipStatusPending {
+			continue
+		}
+		if (incoming && m.FriendID == userID) || (!incoming && m.UserID == userID) {
+			requests = append(requests, m)
+		}
+	}
+	sort.Slice(requests, func(i, j int) bool {
+		return requests[i].UpdatedAt.After(requests[j].UpdatedAt)
+	})
+
+	result := make([]string, 0, len(requests))
+	for _, m := range requests {
+		otherID := m.FriendID
+		if incoming {
+			otherID = m.UserID
+		}
+		if u, ok := t.users.rows[otherID]; ok {
+			result = append(result, u.Email)
+		}
+	}
+	return result, nil
+}
+
+// isBlocked tells whether a block stands between the users in either direction
+func (t *tables) isBlocked(userID, otherID string) bool {
+	for _, b := range t.blocks.rows {
+		if (b.UserID == userID && b.TargetID == otherID) || (b.UserID == otherID && b.TargetID == userID) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetFriendSuggestions ranks the friends of the user's friends by the number of mutual friends,
+// users already connected, pending, blocked in the friendships or blocked in either direction are left out
+func (f FriendshipRepository) GetFriendSuggestions(ctx context.Context, userID string, limit int) ([]domain.FriendSuggestion, error) {
+	t := f.store.read(ctx)
+
+	excluded := t.friendsOf(userID, domain.FriendshipStatusFriended, domain.FriendshipStatusPending, domain.FriendshipStatusBlocked)
+	mutuals := make(map[string]int)
+	for friendID := range t.friendsOf(userID, domain.FriendshipStatusFriended) {
+		for candidateID := range t.friendsOf(friendID, domain.FriendshipStatusFriended) {
+			if candidateID == userID {
+				continue
+			}
+			if _, ok := excluded[candidateID]; ok {
+				continue
+			}
+			mutuals[candidateID]++
+		}
+	}
+
+	result := make([]domain.FriendSuggestion, 0, len(mutuals))
+	for candidateID, count := range mutuals {
+		u, ok := t.users.rows[candidateID]
+		if !ok || t.isBlocked(userID, candidateID) {
+			continue
+		}
+		result = append(result, domain.FriendSuggestion{Email: u.Email, MutualFriends: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].MutualFriends != result[j].MutualFriends {
+			return result[i].MutualFriends > result[j].MutualFriends
+		}
+		return result[i].Email < result[j].Email
+	})
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+// GetFriendSet combines the friends of the users with the set operation,
+// each friend comes with the number of users it is friend with and the users themselves are left out
+func (f FriendshipRepository) GetFriendSet(ctx context.Context, userIDs []string, operation domain.FriendSetOperation) ([]domain.FriendSetMember, error) {
+	if len(userIDs) == 0 {
+		return []domain.FriendSetMember{}, nil
+	}
+	if !operation.IsValid() {
+		return nil, domain.ErrFriendSetOperationIsNotValid
+	}
+	t := f.store.read(ctx)
+
+	counts, _, friendOfFirst := t.friendCounts(userIDs, domain.FriendshipStatusFriended)
+	inputs := len(util.RemoveDuplicates(userIDs))
+
+	result := make([]domain.FriendSetMember, 0, len(counts))
+	for friendID, count := range counts {
+		switch operation {
+		case domain.FriendSetOperationIntersection:
+			if count != inputs {
+				continue
+			}
+		case domain.FriendSetOperationDifference:
+			if count != 1 || !friendOfFirst[friendID] {
+				continue
+			}
+		}
+		u, ok := t.users.rows[friendID]
+		if !ok {
+			continue
+		}
+		result = append(result, domain.FriendSetMember{Email: u.Email, ConnectedUsers: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].ConnectedUsers != result[j].ConnectedUsers {
+			return result[i].ConnectedUsers > result[j].ConnectedUsers
+		}
+		return result[i].Email < result[j].Email
+	})
+	return result, nil
+}
+
+// GetShortestFriendPath returns the user ids of the shortest chain of friends from fromID to toID,
+// both included, with at most maxDepth friendships. Blocked friendships and blocked users are not crossed.
+// It returns ErrRecordNotFound when the users are not connected within maxDepth.
+func (f FriendshipRepository) GetShortestFriendPath(ctx context.Context, fromID, toID string, maxDepth int) ([]string, error) {
+	if fromID == toID {
+		return []string{fromID}, nil
+	}
+	t := f.store.read(ctx)
+
+	parents := map[string]string{fromID: ""}
+	frontier := []string{fromID}
+	for depth := 0; depth < maxDepth && len(frontier) > 0; depth++ {
+		next := make([]string, 0)
+		for _, id := range frontier {
+			// visit the friends in a stable order so that the same path is found every time
+			friends := util.MapKeysToSlice(t.friendsOf(id, domain.FriendshipStatusFriended))
+			sort.Strings(friends)
+			for _, friendID := range friends {
+				if _, ok := parents[friendID]; ok || t.isBlocked(id, friendID) {
+					continue
+				}
+				parents[friendID] = id
+				if friendID == toID {
+					path := make([]string, 0, depth+2)
+					for n := toID; n != ""; n = parents[n] {
+						path = append([]string{n}, path...)
+					}
+					return path, nil
+				}
+				next = append(next, friendID)
+			}
+		}
+		frontier = next
+	}
+
+	return nil, domain.ErrRecordNotFound
+}
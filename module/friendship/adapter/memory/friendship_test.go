@@ -0,0 +1,176 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+// prepareFriendships creates the friendships between the users given by email, one day apart
+func prepareFriendships(t *testing.T, ctx context.Context, store *Store, ids map[string]string, status domain.FriendshipStatus, pairs ...[2]string) {
+	repo := NewFriendshipRepository(store)
+	createdAt := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+	for i, pair := range pairs {
+		_, err := repo.Create(ctx, domain.Friendship{
+			Base:     domain.Base{CreatedAt: createdAt.AddDate(0, 0, i)},
+			UserID:   ids[pair[0]],
+			FriendID: ids[pair[1]],
+			Status:   status,
+		})
+		assert.NoError(t, err)
+	}
+}
+
+func TestFriendship_CreateAndUpdate(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore()
+	repo := NewFriendshipRepository(store)
+	ids := prepareUsers(t, ctx, store, "john@example.com", "lisa@example.com")
+
+	_, err := repo.Create(ctx, domain.Friendship{UserID: ids["john@example.com"], FriendID: "unknown"})
+	assert.Equal(t, common.ErrDB(ErrForeignKeyViolation), err)
+
+	id, err := repo.Create(ctx, domain.Friendship{UserID: ids["john@example.com"], FriendID: ids["lisa@example.com"], Status: domain.FriendshipStatusPending})
+	assert.NoError(t, err)
+
+	// either side finds the friendship
+	friendship, err := repo.GetFriendshipByUserIDs(ctx, ids["lisa@example.com"], ids["john@example.com"])
+	assert.NoError(t, err)
+	assert.Equal(t, id, friendship.Id)
+
+	assert.NoError(t, repo.UpdateStatus(ctx, id, domain.FriendshipStatusFriended))
+	friendship, err = repo.GetFriendshipByUserIDs(ctx, ids["john@example.com"], ids["lisa@example.com"])
+	assert.NoError(t, err)
+	assert.Equal(t, domain.FriendshipStatusFriended, friendship.Status)
+
+	friendship.UserID, friendship.FriendID, friendship.Status = friendship.FriendID, friendship.UserID, domain.FriendshipStatusPending
+	assert.NoError(t, repo.Update(ctx, friendship))
+	emails, err := repo.GetFriendRequestEmails(ctx, ids["john@example.com"], domain.FriendRequestDirectionIncoming)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"lisa@example.com"}, emails)
+
+	_, err = repo.GetFriendRequestEmails(ctx, ids["john@example.com"], domain.FriendRequestDirectionInvalid)
+	assert.Equal(t, domain.ErrFriendRequestDirectionIsNotValid, err)
+}
+
+func TestFriendship_GetFriendshipByUserIDAndStatus(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore()
+	repo := NewFriendshipRepository(store)
+	ids := prepareUsers(t, ctx, store, "a@example.com", "b@example.com", "c@example.com", "d@example.com", "e@example.com")
+	prepareFriendships(t, ctx, store, ids, domain.FriendshipStatusFriended,
+		[2]string{"a@example.com", "d@example.com"},
+		[2]string{"a@example.com", "c@example.com"},
+		[2]string{"e@example.com", "a@example.com"},
+		[2]string{"b@example.com", "c@example.com"},
+	)
+
+	page := domain.Page{Limit: 2}
+	emails, cursor, err := repo.GetFriendshipByUserIDAndStatus(ctx, map[string]string{"a@example.com": ids["a@example.com"]}, page, domain.FriendshipStatusFriended)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"c@example.com", "d@example.com"}, emails)
+	assert.NotEmpty(t, cursor)
+
+	page.Cursor = cursor
+	emails, cursor, err = repo.GetFriendshipByUserIDAndStatus(ctx, map[string]string{"a@example.com": ids["a@example.com"]}, page, domain.FriendshipStatusFriended)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"e@example.com"}, emails)
+	assert.Empty(t, cursor)
+
+	// sorted by the time of the friendship
+	emails, _, err = repo.GetFriendshipByUserIDAndStatus(ctx, map[string]string{"a@example.com": ids["a@example.com"]}, domain.Page{Sort: domain.PageSortCreatedAt}, domain.FriendshipStatusFriended)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"d@example.com", "c@example.com", "e@example.com"}, emails)
+
+	// mutual friends
+	mutual := map[string]string{"a@example.com": ids["a@example.com"], "b@example.com": ids["b@example.com"]}
+	emails, _, err = repo.GetFriendshipByUserIDAndStatus(ctx, mutual, domain.Page{}, domain.FriendshipStatusFriended)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"c@example.com"}, emails)
+
+	_, _, err = repo.GetFriendshipByUserIDAndStatus(ctx, map[string]string{"d@example.com": ids["d@example.com"]}, domain.Page{}, domain.FriendshipStatusPending)
+	assert.Equal(t, domain.ErrRecordNotFound, err)
+}
+
+func TestFriendship_GetFriendSuggestionsAndSet(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore()
+	repo := NewFriendshipRepository(store)
+	ids := prepareUsers(t, ctx, store, "a@example.com", "b@example.com", "c@example.com", "d@example.com", "e@example.com")
+	prepareFriendships(t, ctx, store, ids, domain.FriendshipStatusFriended,
+		[2]string{"a@example.com", "b@example.com"},
+		[2]string{"a@example.com", "c@example.com"},
+		[2]string{"b@example.com", "d@example.com"},
+		[2]string{"c@example.com", "d@example.com"},
+		[2]string{"c@example.com", "e@example.com"},
+	)
+
+	suggestions, err := repo.GetFriendSuggestions(ctx, ids["a@example.com"], 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.FriendSuggestion{
+		{Email: "d@example.com", MutualFriends: 2},
+		{Email: "e@example.com", MutualFriends: 1},
+	}, suggestions)
+
+	_, err = NewBlockRepository(store).UpsertBlock(ctx, domain.Block{UserID: ids["e@example.com"], TargetID: ids["a@example.com"]})
+	assert.NoError(t, err)
+	suggestions, err = repo.GetFriendSuggestions(ctx, ids["a@example.com"], 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.FriendSuggestion{{Email: "d@example.com", MutualFriends: 2}}, suggestions)
+
+	userIDs := []string{ids["b@example.com"], ids["c@example.com"]}
+	set, err := repo.GetFriendSet(ctx, userIDs, domain.FriendSetOperationIntersection)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.FriendSetMember{{Email: "a@example.com", ConnectedUsers: 2}, {Email: "d@example.com", ConnectedUsers: 2}}, set)
+
+	set, err = repo.GetFriendSet(ctx, userIDs, domain.FriendSetOperationUnion)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.FriendSetMember{
+		{Email: "a@example.com", ConnectedUsers: 2},
+		{Email: "d@example.com", ConnectedUsers: 2},
+		{Email: "e@example.com", ConnectedUsers: 1},
+	}, set)
+
+	set, err = repo.GetFriendSet(ctx, []string{ids["c@example.com"], ids["b@example.com"]}, domain.FriendSetOperationDifference)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.FriendSetMember{{Email: "e@example.com", ConnectedUsers: 1}}, set)
+
+	_, err = repo.GetFriendSet(ctx, userIDs, domain.FriendSetOperation("xor"))
+	assert.Equal(t, domain.ErrFriendSetOperationIsNotValid, err)
+}
+
+func TestFriendship_GetShortestFriendPath(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore()
+	repo := NewFriendshipRepository(store)
+	ids := prepareUsers(t, ctx, store, "a@example.com", "b@example.com", "c@example.com", "d@example.com", "e@example.com")
+	prepareFriendships(t, ctx, store, ids, domain.FriendshipStatusFriended,
+		[2]string{"a@example.com", "b@example.com"},
+		[2]string{"b@example.com", "c@example.com"},
+		[2]string{"c@example.com", "d@example.com"},
+		[2]string{"a@example.com", "e@example.com"},
+		[2]string{"e@example.com", "d@example.com"},
+	)
+
+	path, err := repo.GetShortestFriendPath(ctx, ids["a@example.com"], ids["d@example.com"], 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{ids["a@example.com"], ids["e@example.com"], ids["d@example.com"]}, path)
+
+	// a block hides the friendship
+	_, err = NewBlockRepository(store).UpsertBlock(ctx, domain.Block{UserID: ids["d@example.com"], TargetID: ids["e@example.com"]})
+	assert.NoError(t, err)
+	path, err = repo.GetShortestFriendPath(ctx, ids["a@example.com"], ids["d@example.com"], 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{ids["a@example.com"], ids["b@example.com"], ids["c@example.com"], ids["d@example.com"]}, path)
+
+	_, err = repo.GetShortestFriendPath(ctx, ids["a@example.com"], ids["d@example.com"], 2)
+	assert.Equal(t, domain.ErrRecordNotFound, err)
+
+	path, err = repo.GetShortestFriendPath(ctx, ids["a@example.com"], ids["a@example.com"], 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{ids["a@example.com"]}, path)
+}
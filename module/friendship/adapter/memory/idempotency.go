@@ -0,0 +1,88 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+type IdempotencyRepository struct {
+	store *Store
+}
+
+func NewIdempotencyRepository(store *Store) IdempotencyRepository {
+	return IdempotencyRepository{
+		store: store,
+	}
+}
+
+// idempotencyID is the id of the row of a key, a key belongs to its scope
+func idempotencyID(scope, key string) string {
+	return scope + "\x00" + key
+}
+
+func (i IdempotencyRepository) Reserve(ctx context.Context, d domain.IdempotencyRecord, expiredBefore, leaseExpiredBefore time.Time) (domain.IdempotencyRecord, bool, error) {
+	d.StatusCode = 0
+	d.CreatedAt = time.Now().UTC()
+
+	var held domain.IdempotencyRecord
+	reserved := false
+	err := i.store.write(ctx, func(t *tables) error {
+		id := idempotencyID(d.Scope, d.Key)
+		if got, ok := t.idempotency.rows[id]; ok && !got.CreatedAt.Before(expiredBefore) && !(got.IsInFlight() && got.CreatedAt.Before(leaseExpiredBefore)) {
+			held = got
+			return nil
+		}
+		t.idempotency.writable()[id] = d
+		held, reserved = d, true
+		return nil
+	})
+	if err != nil {
+		return domain.IdempotencyRecord{}, false, err
+	}
+	held.Body = append([]byte{}, held.Body...)
+	return held, reserved, nil
+}
+
+func (i IdempotencyRepository) Complete(ctx context.Context, d domain.IdempotencyRecord) error {
+	return i.store.write(ctx, func(t *tables) error {
+		id := idempotencyID(d.Scope, d.Key)
+		got, ok := t.idempotency.rows[id]
+		if !ok || !got.CreatedAt.Equal(d.CreatedAt) {
+			return domain.ErrRecordNotFound
+		}
+		got.StatusCode = d.StatusCode
+		got.ContentType = d.ContentType
+		got.Body = append([]byte{}, d.Body...)
+		t.idempotency.writable()[id] = got
+		return nil
+	})
+}
+
+func (i IdempotencyRepository) Release(ctx context.Context, d domain.IdempotencyRecord) error {
+	return i.store.write(ctx, func(t *tables) error {
+		id := idempotencyID(d.Scope, d.Key)
+		if got, ok := t.idempotency.rows[id]; ok && got.CreatedAt.Equal(d.CreatedAt) {
+			delete(t.idempotency.writable(), id)
+		}
+		return nil
+	})
+}
+
+func (i IdempotencyRepository) DeleteExpired(ctx context.Context, expiredBefore time.Time) (int, error) {
+	deleted := 0
+	err := i.store.write(ctx, func(t *tables) error {
+		for id, got := range t.idempotency.rows {
+			if got.CreatedAt.Before(expiredBefore) {
+				delete(t.idempotency.writable(), id)
+				deleted++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return deleted, nil
+}
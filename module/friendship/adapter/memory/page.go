@@ -0,0 +1,82 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+// listAfter returns at most limit rows kept by the filter, ordered by id from the one after afterID,
+// like a database walking a table by its primary key
+func listAfter[T any](t table[T], afterID string, limit int, filter func(m T) bool) []T {
+	ids := make([]string, 0)
+	for id, m := range t.rows {
+		if id > afterID && filter(m) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+
+	result := make([]T, 0, len(ids))
+	for _, id := range ids {
+		result = append(result, t.rows[id])
+	}
+	return result
+}
+
+// pageEmail is an item of a paginated list of emails
+type pageEmail struct {
+	Email     string
+	CreatedAt time.Time
+}
+
+// pageEmails applies the since filter, the cursor, the order and the limit of the page like the database does,
+// it returns the emails of the page and the cursor of the next page, empty on the last page
+func pageEmails(list []pageEmail, page domain.Page) ([]string, string, error) {
+	var cursor domain.Cursor
+	if page.Cursor != "" {
+		var err error
+		if cursor, err = domain.DecodeCursor(page.Cursor); err != nil {
+			return []string{}, "", err
+		}
+	}
+
+	byCreatedAt := page.Sort == domain.PageSortCreatedAt
+	less := func(a, b pageEmail) bool {
+		if byCreatedAt && !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.Email < b.Email
+	}
+
+	filtered := make([]pageEmail, 0, len(list))
+	for _, v := range list {
+		if !page.Since.IsZero() && v.CreatedAt.Before(page.Since) {
+			continue
+		}
+		if page.Cursor != "" && !less(pageEmail{Email: cursor.Key, CreatedAt: cursor.CreatedAt}, v) {
+			continue
+		}
+		filtered = append(filtered, v)
+	}
+	sort.Slice(filtered, func(i, j int) bool {
+		return less(filtered[i], filtered[j])
+	})
+
+	var nextCursor string
+	if len(filtered) > page.Limit {
+		filtered = filtered[:page.Limit]
+		last := filtered[len(filtered)-1]
+		nextCursor = domain.EncodeCursor(domain.Cursor{Key: last.Email, CreatedAt: last.CreatedAt})
+	}
+
+	result := make([]string, 0, len(filtered))
+	for _, v := range filtered {
+		result = append(result, v.Email)
+	}
+	return result, nextCursor, nil
+}
@@ -0,0 +1,15 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/phantranhieunhan/s3-assignment/module/friendship"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/adapter/memory"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/adapter/storagetest"
+)
+
+func TestStorage(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) friendship.Storage {
+		return friendship.NewMemoryStorage(memory.NewStore())
+	})
+}
@@ -0,0 +1,155 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+var (
+	// ErrUniqueViolation is returned where the database would reject a duplicated row
+	ErrUniqueViolation = errors.New("unique constraint violation")
+	// ErrForeignKeyViolation is returned where the database would reject a row referencing a missing user
+	ErrForeignKeyViolation = errors.New("foreign key constraint violation")
+)
+
+// table is a set of rows by id. A table belongs to a single version of the store,
+// a transaction copies it on its first write so the committed version is never modified
+type table[T any] struct {
+	rows  map[string]T
+	owned bool
+}
+
+func newTable[T any]() table[T] {
+	return table[T]{rows: make(map[string]T), owned: true}
+}
+
+// writable returns the rows the current version can modify, copying them on the first call
+func (t *table[T]) writable() map[string]T {
+	if !t.owned {
+		rows := make(map[string]T, len(t.rows)+1)
+		for k, v := range t.rows {
+			rows[k] = v
+		}
+		t.rows = rows
+		t.owned = true
+	}
+	return t.rows
+}
+
+// tables is a version of the store
+type tables struct {
+	users         table[domain.User]
+	friendships   table[domain.Friendship]
+	subscriptions table[domain.Subscription]
+	blocks        table[domain.Block]
+	updates       table[domain.Update]
+	events        table[eventRow]
+	webhooks      table[domain.Webhook]
+	deliveries    table[domain.WebhookDelivery]
+	deletions     table[domain.UserDeletion]
+	audit         table[domain.AuditEntry]
+	idempotency   table[domain.IdempotencyRecord]
+	eventSeq      int64
+}
+
+func newTables() *tables {
+	return &tables{
+		users:         newTable[domain.User](),
+		friendships:   newTable[domain.Friendship](),
+		subscriptions: newTable[domain.Subscription](),
+		blocks:        newTable[domain.Block](),
+		updates:       newTable[domain.Update](),
+		events:        newTable[eventRow](),
+		webhooks:      newTable[domain.Webhook](),
+		deliveries:    newTable[domain.WebhookDelivery](),
+		deletions:     newTable[domain.UserDeletion](),
+		audit:         newTable[domain.AuditEntry](),
+		idempotency:   newTable[domain.IdempotencyRecord](),
+	}
+}
+
+// fork returns the next version sharing the rows of this one until they are written
+func (t tables) fork() *tables {
+	t.users.owned = false
+	t.friendships.owned = false
+	t.subscriptions.owned = false
+	t.blocks.owned = false
+	t.updates.owned = false
+	t.events.owned = false
+	t.webhooks.owned = false
+	t.deliveries.owned = false
+	t.deletions.owned = false
+	t.audit.owned = false
+	t.idempotency.owned = false
+	return &t
+}
+
+// Store keeps every table in memory, it is the in-memory counterpart of postgres.Database.
+// Transactions run one at a time on a copy-on-write version of the tables which replaces the committed version on commit,
+// readers outside of a transaction see the last committed version
+type Store struct {
+	mu        sync.Mutex
+	committed atomic.Pointer[tables]
+}
+
+func NewStore() *Store {
+	s := &Store{}
+	s.committed.Store(newTables())
+	return s
+}
+
+type txKey struct{}
+
+type tx struct {
+	data *tables
+}
+
+func extractTx(ctx context.Context) *tx {
+	if t, ok := ctx.Value(txKey{}).(*tx); ok {
+		return t
+	}
+	return nil
+}
+
+// WithinTransaction runs function within transaction
+//
+// The transaction commits when function were finished without error, a function called within a running
+// transaction joins it
+func (s *Store) WithinTransaction(ctx context.Context, tFunc func(ctx context.Context) error) error {
+	if extractTx(ctx) != nil {
+		return tFunc(ctx)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t := &tx{data: s.committed.Load().fork()}
+	if err := tFunc(context.WithValue(ctx, txKey{}, t)); err != nil {
+		// rollback by dropping the version
+		return err
+	}
+	s.committed.Store(t.data)
+	return nil
+}
+
+// read returns the version seen by the context
+func (s *Store) read(ctx context.Context) *tables {
+	if t := extractTx(ctx); t != nil {
+		return t.data
+	}
+	return s.committed.Load()
+}
+
+// write runs the function on the version of the transaction of the context, or in a transaction of its own
+func (s *Store) write(ctx context.Context, fn func(t *tables) error) error {
+	if t := extractTx(ctx); t != nil {
+		return fn(t.data)
+	}
+	return s.WithinTransaction(ctx, func(ctx context.Context) error {
+		return fn(extractTx(ctx).data)
+	})
+}
@@ -0,0 +1,123 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+// prepareUsers creates the users and returns their ids by email
+func prepareUsers(t *testing.T, ctx context.Context, store *Store, emails ...string) map[string]string {
+	repo := NewUserRepository(store)
+	ids := make(map[string]string, len(emails))
+	for _, email := range emails {
+		id, err := repo.Create(ctx, domain.User{Email: email})
+		assert.NoError(t, err)
+		ids[email] = id
+	}
+	return ids
+}
+
+func TestStore_WithinTransaction_Commit(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore()
+	repo := NewUserRepository(store)
+
+	err := store.WithinTransaction(ctx, func(ctx context.Context) error {
+		_, err := repo.Create(ctx, domain.User{Email: "john@example.com"})
+		assert.NoError(t, err)
+
+		// the transaction sees its own writes
+		_, err = repo.GetUserByEmail(ctx, "john@example.com")
+		assert.NoError(t, err)
+
+		// the others do not until the commit
+		_, err = repo.GetUserByEmail(context.Background(), "john@example.com")
+		assert.Equal(t, domain.ErrRecordNotFound, err)
+		return nil
+	})
+	assert.NoError(t, err)
+
+	_, err = repo.GetUserByEmail(ctx, "john@example.com")
+	assert.NoError(t, err)
+}
+
+func TestStore_WithinTransaction_Rollback(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore()
+	repo := NewUserRepository(store)
+	ids := prepareUsers(t, ctx, store, "john@example.com")
+
+	errTx := errors.New("some error")
+	err := store.WithinTransaction(ctx, func(ctx context.Context) error {
+		_, err := repo.Create(ctx, domain.User{Email: "lisa@example.com"})
+		assert.NoError(t, err)
+		assert.NoError(t, repo.Update(ctx, domain.User{Base: domain.Base{Id: ids["john@example.com"]}, Email: "john.new@example.com"}))
+		return errTx
+	})
+	assert.Equal(t, errTx, err)
+
+	_, err = repo.GetUserByEmail(ctx, "lisa@example.com")
+	assert.Equal(t, domain.ErrRecordNotFound, err)
+	user, err := repo.GetUserByEmail(ctx, "john@example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, ids["john@example.com"], user.Base.Id)
+}
+
+func TestStore_WithinTransaction_RollbackOnPanic(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore()
+	repo := NewUserRepository(store)
+
+	assert.Panics(t, func() {
+		_ = store.WithinTransaction(ctx, func(ctx context.Context) error {
+			_, err := repo.Create(ctx, domain.User{Email: "john@example.com"})
+			assert.NoError(t, err)
+			panic("some panic")
+		})
+	})
+
+	// the store is usable again and the write is gone
+	_, err := repo.GetUserByEmail(ctx, "john@example.com")
+	assert.Equal(t, domain.ErrRecordNotFound, err)
+	prepareUsers(t, ctx, store, "lisa@example.com")
+}
+
+func TestStore_WithinTransaction_Join(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore()
+	repo := NewUserRepository(store)
+
+	errTx := errors.New("some error")
+	err := store.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := store.WithinTransaction(ctx, func(ctx context.Context) error {
+			_, err := repo.Create(ctx, domain.User{Email: "john@example.com"})
+			return err
+		})
+		assert.NoError(t, err)
+		return errTx
+	})
+	assert.Equal(t, errTx, err)
+
+	// the inner function joined the outer transaction and was rolled back with it
+	_, err = repo.GetUserByEmail(ctx, "john@example.com")
+	assert.Equal(t, domain.ErrRecordNotFound, err)
+}
+
+func TestStore_WithinTransaction_KeepsOlderVersions(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore()
+	repo := NewUserRepository(store)
+	prepareUsers(t, ctx, store, "john@example.com")
+
+	before := store.read(ctx)
+	prepareUsers(t, ctx, store, "lisa@example.com")
+
+	// a committed version is never modified by the next transactions
+	assert.Len(t, before.users.rows, 1)
+	_, err := repo.GetUserIDsByEmails(ctx, []string{"john@example.com", "lisa@example.com"})
+	assert.NoError(t, err)
+}
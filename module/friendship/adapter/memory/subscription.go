@@ -0,0 +1,180 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/phantranhieunhan/s3-assignment/pkg/util"
+)
+
+type SubscriptionRepository struct {
+	store *Store
+}
+
+func NewSubscriptionRepository(store *Store) SubscriptionRepository {
+	return SubscriptionRepository{
+		store: store,
+	}
+}
+
+// subscription returns the subscription of the subscriber to the user
+func (t *tables) subscription(userID, subscriberID string) (domain.Subscription, bool) {
+	for _, m := range t.subscriptions.rows {
+		if m.UserID == userID && m.SubscriberID == subscriberID {
+			return m, true
+		}
+	}
+	return domain.Subscription{}, false
+}
+
+func (s SubscriptionRepository) Create(ctx context.Context, sub domain.Subscription) (string, error) {
+	sub.Id = util.GenUUID()
+	now := time.Now().UTC()
+	if sub.CreatedAt.IsZero() {
+		sub.CreatedAt = now
+	}
+	if sub.UpdatedAt.IsZero() {
+		sub.UpdatedAt = now
+	}
+
+	err := s.store.write(ctx, func(t *tables) error {
+		if !t.usersExist(sub.UserID, sub.SubscriberID) {
+			return common.ErrDB(ErrForeignKeyViolation)
+		}
+		if _, ok := t.subscription(sub.UserID, sub.SubscriberID); ok {
+			return common.ErrDB(ErrUniqueViolation)
+		}
+		t.subscriptions.writable()[sub.Id] = sub
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return sub.Id, nil
+}
+
+func (s SubscriptionRepository) UpdateStatus(ctx context.Context, id string, status domain.SubscriptionStatus) error {
+	return s.store.write(ctx, func(t *tables) error {
+		m, ok := t.subscriptions.rows[id]
+		if !ok {
+			return nil
+		}
+		m.Status = status
+		m.UpdatedAt = time.Now().UTC()
+		t.subscriptions.writable()[id] = m
+		return nil
+	})
+}
+
+// UpsertSubscription updates the status of the subscription of the same users when there is one and returns its id
+func (s SubscriptionRepository) UpsertSubscription(ctx context.Context, sub domain.Subscription) (string, error) {
+	if sub.Id == "" {
+		sub.Id = util.GenUUID()
+	}
+	now := time.Now().UTC()
+
+	err := s.store.write(ctx, func(t *tables) error {
+		if !t.usersExist(sub.UserID, sub.SubscriberID) {
+			return common.ErrDB(ErrForeignKeyViolation)
+		}
+		if m, ok := t.subscription(sub.UserID, sub.SubscriberID); ok {
+			m.Status = sub.Status
+			m.UpdatedAt = now
+			t.subscriptions.writable()[m.Id] = m
+			sub.Id = m.Id
+			return nil
+		}
+		if sub.CreatedAt.IsZero() {
+			sub.CreatedAt = now
+		}
+		sub.UpdatedAt = now
+		t.subscriptions.writable()[sub.Id] = sub
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return sub.Id, nil
+}
+
+func (s SubscriptionRepository) Delete(ctx context.Context, id string) error {
+	return s.store.write(ctx, func(t *tables) error {
+		if _, ok := t.subscriptions.rows[id]; ok {
+			delete(t.subscriptions.writable(), id)
+		}
+		return nil
+	})
+}
+
+func (s SubscriptionRepository) GetSubscription(ctx context.Context, ss domain.Subscriptions) (domain.Subscriptions, error) {
+	t := s.store.read(ctx)
+	result := make(domain.Subscriptions, 0, len(ss))
+	for _, v := range ss {
+		if m, ok := t.subscription(v.UserID, v.SubscriberID); ok {
+			result = append(result, m)
+		}
+	}
+	return result, nil
+}
+
+// DeleteByUserID deletes the subscriptions to the user and of the user
+func (s SubscriptionRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	return s.store.write(ctx, func(t *tables) error {
+		for id, m := range t.subscriptions.rows {
+			if m.UserID == userID || m.SubscriberID == userID {
+				delete(t.subscriptions.writable(), id)
+			}
+		}
+		return nil
+	})
+}
+
+// ListSubscriptionsByUserID lists the subscriptions to the user and of the user in any status,
+// ordered by id from the one after afterID
+func (s SubscriptionRepository) ListSubscriptionsByUserID(ctx context.Context, userID, afterID string, limit int) (domain.Subscriptions, error) {
+	return listAfter(s.store.read(ctx).subscriptions, afterID, limit, func(m domain.Subscription) bool {
+		return m.UserID == userID || m.SubscriberID == userID
+	}), nil
+}
+
+// GetSubscriptionEmailsByUserIDAndEmails lists a page of the subscribers of the user and the mentioned users who did not unsubscribe,
+// a recipient is dated by its subscription or by its sign up when it is only mentioned
+func (s SubscriptionRepository) GetSubscriptionEmailsByUserIDAndEmails(ctx context.Context, id string, emails []string, page domain.Page) ([]string, string, error) {
+	page = page.WithDefaults()
+	t := s.store.read(ctx)
+
+	list := make([]pageEmail, 0)
+	for _, u := range t.users.rows {
+		sub, subscribed := t.subscription(id, u.Base.Id)
+		switch {
+		case subscribed && sub.Status == domain.SubscriptionStatusSubscribed:
+			list = append(list, pageEmail{Email: u.Email, CreatedAt: sub.CreatedAt})
+		case util.IsContain(emails, u.Email) && (!subscribed || sub.Status != domain.SubscriptionStatusUnsubscribed):
+			createdAt := u.Base.CreatedAt
+			if subscribed {
+				createdAt = sub.CreatedAt
+			}
+			list = append(list, pageEmail{Email: u.Email, CreatedAt: createdAt})
+		}
+	}
+	return pageEmails(list, page)
+}
+
+// GetSubscriberEmails lists a page of the users subscribed to the user, dated by their subscription
+func (s SubscriptionRepository) GetSubscriberEmails(ctx context.Context, id string, page domain.Page) ([]string, string, error) {
+	page = page.WithDefaults()
+	t := s.store.read(ctx)
+
+	list := make([]pageEmail, 0)
+	for _, m := range t.subscriptions.rows {
+		if m.UserID != id || m.Status != domain.SubscriptionStatusSubscribed {
+			continue
+		}
+		if u, ok := t.users.rows[m.SubscriberID]; ok {
+			list = append(list, pageEmail{Email: u.Email, CreatedAt: m.CreatedAt})
+		}
+	}
+	return pageEmails(list, page)
+}
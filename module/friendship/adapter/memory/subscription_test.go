@@ -0,0 +1,64 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscription_CreateUpsertDelete(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore()
+	repo := NewSubscriptionRepository(store)
+	ids := prepareUsers(t, ctx, store, "john@example.com", "lisa@example.com")
+
+	sub := domain.Subscription{UserID: ids["john@example.com"], SubscriberID: ids["lisa@example.com"], Status: domain.SubscriptionStatusSubscribed}
+	id, err := repo.Create(ctx, sub)
+	assert.NoError(t, err)
+	_, err = repo.Create(ctx, sub)
+	assert.Equal(t, common.ErrDB(ErrUniqueViolation), err)
+
+	// the upsert keeps the row of the same users
+	sub.Status = domain.SubscriptionStatusUnsubscribed
+	upsertedID, err := repo.UpsertSubscription(ctx, sub)
+	assert.NoError(t, err)
+	assert.Equal(t, id, upsertedID)
+
+	result, err := repo.GetSubscription(ctx, domain.Subscriptions{sub, {UserID: sub.SubscriberID, SubscriberID: sub.UserID}})
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, domain.SubscriptionStatusUnsubscribed, result[0].Status)
+
+	assert.NoError(t, repo.UpdateStatus(ctx, id, domain.SubscriptionStatusSubscribed))
+	emails, _, err := repo.GetSubscriberEmails(ctx, ids["john@example.com"], domain.Page{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"lisa@example.com"}, emails)
+
+	assert.NoError(t, repo.Delete(ctx, id))
+	result, err = repo.GetSubscription(ctx, domain.Subscriptions{sub})
+	assert.NoError(t, err)
+	assert.Empty(t, result)
+}
+
+func TestSubscription_GetSubscriptionEmailsByUserIDAndEmails(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore()
+	repo := NewSubscriptionRepository(store)
+	ids := prepareUsers(t, ctx, store, "john@example.com", "lisa@example.com", "kate@example.com", "mike@example.com")
+
+	_, err := repo.Create(ctx, domain.Subscription{UserID: ids["john@example.com"], SubscriberID: ids["lisa@example.com"], Status: domain.SubscriptionStatusSubscribed})
+	assert.NoError(t, err)
+	_, err = repo.Create(ctx, domain.Subscription{UserID: ids["john@example.com"], SubscriberID: ids["mike@example.com"], Status: domain.SubscriptionStatusUnsubscribed})
+	assert.NoError(t, err)
+
+	// the subscribers and the mentioned users, unless they unsubscribed
+	emails, _, err := repo.GetSubscriptionEmailsByUserIDAndEmails(ctx, ids["john@example.com"], []string{"kate@example.com", "mike@example.com"}, domain.Page{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"kate@example.com", "lisa@example.com"}, emails)
+
+	_, _, err = repo.GetSubscriptionEmailsByUserIDAndEmails(ctx, ids["john@example.com"], nil, domain.Page{Cursor: "not a cursor"})
+	assert.Equal(t, domain.ErrCursorIsNotValid, err)
+}
@@ -0,0 +1,128 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/phantranhieunhan/s3-assignment/pkg/util"
+)
+
+type UpdateRepository struct {
+	store *Store
+}
+
+func NewUpdateRepository(store *Store) UpdateRepository {
+	return UpdateRepository{
+		store: store,
+	}
+}
+
+func (u UpdateRepository) Create(ctx context.Context, d domain.Update) (string, error) {
+	d.Id = util.GenUUID()
+	d.UpdatedAt = d.CreatedAt
+	d.Mentions = append([]string{}, d.Mentions...)
+
+	err := u.store.write(ctx, func(t *tables) error {
+		if !t.usersExist(d.UserID) {
+			return common.ErrDB(ErrForeignKeyViolation)
+		}
+		t.updates.writable()[d.Id] = d
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return d.Id, nil
+}
+
+// GetFeed lists a page of the updates the user receives, the newest first.
+// The user receives the updates of the users it subscribes to and the updates mentioning it unless it unsubscribed from the sender,
+// the same rules as GetSubscriptionEmailsByUserIDAndEmails, and never the updates across a block
+func (u UpdateRepository) GetFeed(ctx context.Context, userID string, page domain.Page) ([]domain.FeedItem, string, error) {
+	page = page.WithDefaults()
+	t := u.store.read(ctx)
+
+	var cursor domain.Cursor
+	if page.Cursor != "" {
+		var err error
+		if cursor, err = domain.DecodeCursor(page.Cursor); err != nil {
+			return nil, "", err
+		}
+	}
+	newer := func(aCreatedAt time.Time, aID string, bCreatedAt time.Time, bID string) bool {
+		if !aCreatedAt.Equal(bCreatedAt) {
+			return aCreatedAt.After(bCreatedAt)
+		}
+		return aID > bID
+	}
+
+	me, ok := t.users.rows[userID]
+	if !ok {
+		return []domain.FeedItem{}, "", nil
+	}
+
+	list := make([]domain.FeedItem, 0)
+	for _, up := range t.updates.rows {
+		sender, ok := t.users.rows[up.UserID]
+		if !ok || up.UserID == userID {
+			continue
+		}
+		sub, subscribed := t.subscription(up.UserID, userID)
+		receives := (subscribed && sub.Status == domain.SubscriptionStatusSubscribed) ||
+			(util.IsContain(up.Mentions, me.Email) && (!subscribed || sub.Status != domain.SubscriptionStatusUnsubscribed))
+		if !receives || t.isBlocked(up.UserID, userID) {
+			continue
+		}
+		if !page.Since.IsZero() && up.CreatedAt.Before(page.Since) {
+			continue
+		}
+		if page.Cursor != "" && !newer(cursor.CreatedAt, cursor.Key, up.CreatedAt, up.Id) {
+			continue
+		}
+		list = append(list, domain.FeedItem{ID: up.Id, Sender: sender.Email, Text: up.Text, CreatedAt: up.CreatedAt})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return newer(list[i].CreatedAt, list[i].ID, list[j].CreatedAt, list[j].ID)
+	})
+
+	var nextCursor string
+	if len(list) > page.Limit {
+		list = list[:page.Limit]
+		last := list[len(list)-1]
+		nextCursor = domain.EncodeCursor(domain.Cursor{Key: last.ID, CreatedAt: last.CreatedAt})
+	}
+	return list, nextCursor, nil
+}
+
+// ListUpdatesByUserID lists the updates posted by the user, ordered by id from the one after afterID
+func (u UpdateRepository) ListUpdatesByUserID(ctx context.Context, userID, afterID string, limit int) ([]domain.Update, error) {
+	list := listAfter(u.store.read(ctx).updates, afterID, limit, func(m domain.Update) bool {
+		return m.UserID == userID
+	})
+	for i := range list {
+		list[i].Mentions = append([]string{}, list[i].Mentions...)
+	}
+	return list, nil
+}
+
+func (u UpdateRepository) RemoveMentions(ctx context.Context, email string) error {
+	return u.store.write(ctx, func(t *tables) error {
+		for id, up := range t.updates.rows {
+			if !util.IsContain(up.Mentions, email) {
+				continue
+			}
+			mentions := make([]string, 0, len(up.Mentions))
+			for _, m := range up.Mentions {
+				if m != email {
+					mentions = append(mentions, m)
+				}
+			}
+			up.Mentions = mentions
+			t.updates.writable()[id] = up
+		}
+		return nil
+	})
+}
@@ -0,0 +1,44 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdate_GetFeed(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore()
+	repo := NewUpdateRepository(store)
+	ids := prepareUsers(t, ctx, store, "john@example.com", "lisa@example.com", "kate@example.com", "mike@example.com")
+
+	_, err := NewSubscriptionRepository(store).Create(ctx, domain.Subscription{UserID: ids["lisa@example.com"], SubscriberID: ids["john@example.com"], Status: domain.SubscriptionStatusSubscribed})
+	assert.NoError(t, err)
+	_, err = NewBlockRepository(store).UpsertBlock(ctx, domain.Block{UserID: ids["john@example.com"], TargetID: ids["mike@example.com"]})
+	assert.NoError(t, err)
+
+	now := time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+	post := func(email, text string, mentions []string, createdAt time.Time) string {
+		id, err := repo.Create(ctx, domain.Update{Base: domain.Base{CreatedAt: createdAt}, UserID: ids[email], Text: text, Mentions: mentions})
+		assert.NoError(t, err)
+		return id
+	}
+	subscribed := post("lisa@example.com", "subscribed", nil, now)
+	mentioned := post("kate@example.com", "hello john@example.com", []string{"john@example.com"}, now.Add(time.Minute))
+	post("kate@example.com", "not for john", nil, now.Add(2*time.Minute))
+	post("mike@example.com", "blocked john@example.com", []string{"john@example.com"}, now.Add(3*time.Minute))
+	post("john@example.com", "own update", nil, now.Add(4*time.Minute))
+
+	feed, cursor, err := repo.GetFeed(ctx, ids["john@example.com"], domain.Page{Limit: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.FeedItem{{ID: mentioned, Sender: "kate@example.com", Text: "hello john@example.com", CreatedAt: now.Add(time.Minute)}}, feed)
+	assert.NotEmpty(t, cursor)
+
+	feed, cursor, err = repo.GetFeed(ctx, ids["john@example.com"], domain.Page{Limit: 1, Cursor: cursor})
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.FeedItem{{ID: subscribed, Sender: "lisa@example.com", Text: "subscribed", CreatedAt: now}}, feed)
+	assert.Empty(t, cursor)
+}
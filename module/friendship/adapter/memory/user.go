@@ -0,0 +1,253 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/phantranhieunhan/s3-assignment/pkg/util"
+)
+
+type UserRepository struct {
+	store *Store
+}
+
+func NewUserRepository(store *Store) UserRepository {
+	return UserRepository{
+		store: store,
+	}
+}
+
+// userByEmail returns the user with the email, the password is kept
+func (t *tables) userByEmail(email string) (domain.User, bool) {
+	for _, u := range t.users.rows {
+		if u.Email == email {
+			return u, true
+		}
+	}
+	return domain.User{}, false
+}
+
+func (t *tables) usersExist(ids ...string) bool {
+	for _, id := range ids {
+		if _, ok := t.users.rows[id]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (f UserRepository) GetUserIDsByEmails(ctx context.Context, emails []string) (map[string]string, error) {
+	t := f.store.read(ctx)
+	result := make(map[string]string, len(emails))
+	for _, email := range emails {
+		if u, ok := t.userByEmail(email); ok {
+			result[u.Email] = u.Base.Id
+		}
+	}
+	if len(result) != len(emails) {
+		return nil, domain.ErrNotFoundUserByEmail
+	}
+	return result, nil
+}
+
+func (f UserRepository) GetEmailsByUserIDs(ctx context.Context, userIDs []string) (map[string]string, error) {
+	t := f.store.read(ctx)
+	result := make(map[string]string, len(userIDs))
+	for _, id := range userIDs {
+		if u, ok := t.users.rows[id]; ok {
+			result[id] = u.Email
+		}
+	}
+	if len(result) != len(userIDs) {
+		return nil, domain.ErrNotFoundUserByEmail
+	}
+	return result, nil
+}
+
+func (f UserRepository) Create(ctx context.Context, d domain.User) (string, error) {
+	d.Base.Id = util.GenUUID()
+	now := time.Now().UTC()
+	if d.Base.CreatedAt.IsZero() {
+		d.Base.CreatedAt = now
+	}
+	d.Base.UpdatedAt = now
+
+	err := f.store.write(ctx, func(t *tables) error {
+		if _, ok := t.userByEmail(d.Email); ok {
+			return common.ErrDB(ErrUniqueViolation)
+		}
+		t.users.writable()[d.Base.Id] = d
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return d.Base.Id, nil
+}
+
+func (f UserRepository) GetPasswordHash(ctx context.Context, userID string) (string, error) {
+	u, ok := f.store.read(ctx).users.rows[userID]
+	if !ok {
+		return "", domain.ErrRecordNotFound
+	}
+	return u.Password, nil
+}
+
+func (f UserRepository) SetPasswordHash(ctx context.Context, userID, hash string) error {
+	return f.store.write(ctx, func(t *tables) error {
+		u, ok := t.users.rows[userID]
+		if !ok {
+			return domain.ErrUpdateRecordNotFound
+		}
+		u.Password = hash
+		u.Base.UpdatedAt = time.Now().UTC()
+		t.users.writable()[userID] = u
+		return nil
+	})
+}
+
+func (f UserRepository) GetUserByEmail(ctx context.Context, email string) (domain.User, error) {
+	u, ok := f.store.read(ctx).userByEmail(email)
+	if !ok {
+		return domain.User{}, domain.ErrRecordNotFound
+	}
+	// the password only comes out through GetPasswordHash
+	u.Password = ""
+	return u, nil
+}
+
+func (f UserRepository) Update(ctx context.Context, d domain.User) error {
+	return f.store.write(ctx, func(t *tables) error {
+		u, ok := t.users.rows[d.Base.Id]
+		if !ok {
+			return domain.ErrUpdateRecordNotFound
+		}
+		if other, ok := t.userByEmail(d.Email); ok && other.Base.Id != d.Base.Id {
+			return common.ErrDB(ErrUniqueViolation)
+		}
+		u.Email = d.Email
+		u.Base.UpdatedAt = time.Now().UTC()
+		t.users.writable()[u.Base.Id] = u
+		return nil
+	})
+}
+
+func (f UserRepository) Delete(ctx context.Context, id string) error {
+	return f.store.write(ctx, func(t *tables) error {
+		if _, ok := t.users.rows[id]; !ok {
+			return domain.ErrRecordNotFound
+		}
+		if t.userHasRelations(id) {
+			return domain.ErrUserHasRelations
+		}
+		delete(t.users.writable(), id)
+		// the scheduled deletion goes with the user like the cascade of the database
+		if _, ok := t.deletions.rows[id]; ok {
+			delete(t.deletions.writable(), id)
+		}
+		return nil
+	})
+}
+
+// userHasRelations tells whether a row references the user, the database would reject deleting it
+func (t *tables) userHasRelations(id string) bool {
+	for _, f := range t.friendships.rows {
+		if f.UserID == id || f.FriendID == id {
+			return true
+		}
+	}
+	for _, s := range t.subscriptions.rows {
+		if s.UserID == id || s.SubscriberID == id {
+			return true
+		}
+	}
+	for _, b := range t.blocks.rows {
+		if b.UserID == id || b.TargetID == id {
+			return true
+		}
+	}
+	for _, u := range t.updates.rows {
+		if u.UserID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Anonymize replaces the email of the user with the tombstone, clears its name and password and drops its scheduled deletion
+func (f UserRepository) Anonymize(ctx context.Context, id, tombstone string) error {
+	return f.store.write(ctx, func(t *tables) error {
+		u, ok := t.users.rows[id]
+		if !ok {
+			return domain.ErrUpdateRecordNotFound
+		}
+		if other, ok := t.userByEmail(tombstone); ok && other.Base.Id != id {
+			return common.ErrDB(ErrUniqueViolation)
+		}
+		u.Email = tombstone
+		u.Username = ""
+		u.Password = ""
+		u.Base.UpdatedAt = time.Now().UTC()
+		t.users.writable()[id] = u
+		if _, ok := t.deletions.rows[id]; ok {
+			delete(t.deletions.writable(), id)
+		}
+		return nil
+	})
+}
+
+func (f UserRepository) ScheduleDeletion(ctx context.Context, d domain.UserDeletion) error {
+	return f.store.write(ctx, func(t *tables) error {
+		if !t.usersExist(d.UserID) {
+			return common.ErrDB(ErrForeignKeyViolation)
+		}
+		if _, ok := t.deletions.rows[d.UserID]; ok {
+			return common.ErrDB(ErrUniqueViolation)
+		}
+		d.RequestedAt = d.RequestedAt.UTC()
+		d.PurgeAt = d.PurgeAt.UTC()
+		t.deletions.writable()[d.UserID] = d
+		return nil
+	})
+}
+
+func (f UserRepository) GetDeletion(ctx context.Context, userID string) (domain.UserDeletion, error) {
+	d, ok := f.store.read(ctx).deletions.rows[userID]
+	if !ok {
+		return domain.UserDeletion{}, domain.ErrRecordNotFound
+	}
+	return d, nil
+}
+
+func (f UserRepository) CancelDeletion(ctx context.Context, userID string) error {
+	return f.store.write(ctx, func(t *tables) error {
+		if _, ok := t.deletions.rows[userID]; !ok {
+			return domain.ErrRecordNotFound
+		}
+		delete(t.deletions.writable(), userID)
+		return nil
+	})
+}
+
+// GetDueDeletions lists the deletions whose grace period is over, the earliest first
+func (f UserRepository) GetDueDeletions(ctx context.Context, now time.Time, limit int) ([]domain.UserDeletion, error) {
+	result := make([]domain.UserDeletion, 0)
+	for _, d := range f.store.read(ctx).deletions.rows {
+		if !d.PurgeAt.After(now) {
+			result = append(result, d)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].PurgeAt.Equal(result[j].PurgeAt) {
+			return result[i].PurgeAt.Before(result[j].PurgeAt)
+		}
+		return result[i].UserID < result[j].UserID
+	})
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
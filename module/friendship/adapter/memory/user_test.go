@@ -0,0 +1,73 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUser_CreateGetUpdateDelete(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore()
+	repo := NewUserRepository(store)
+
+	id, err := repo.Create(ctx, domain.User{Email: "john@example.com", Password: "hash"})
+	assert.NoError(t, err)
+
+	_, err = repo.Create(ctx, domain.User{Email: "john@example.com"})
+	assert.Equal(t, common.ErrDB(ErrUniqueViolation), err)
+
+	user, err := repo.GetUserByEmail(ctx, "john@example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, id, user.Base.Id)
+	assert.Empty(t, user.Password)
+
+	hash, err := repo.GetPasswordHash(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, "hash", hash)
+
+	assert.NoError(t, repo.Update(ctx, domain.User{Base: domain.Base{Id: id}, Email: "john.new@example.com"}))
+	assert.Equal(t, domain.ErrUpdateRecordNotFound, repo.Update(ctx, domain.User{Base: domain.Base{Id: "unknown"}, Email: "lisa@example.com"}))
+
+	emails, err := repo.GetEmailsByUserIDs(ctx, []string{id})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{id: "john.new@example.com"}, emails)
+
+	assert.NoError(t, repo.Delete(ctx, id))
+	assert.Equal(t, domain.ErrRecordNotFound, repo.Delete(ctx, id))
+	_, err = repo.GetUserByEmail(ctx, "john.new@example.com")
+	assert.Equal(t, domain.ErrRecordNotFound, err)
+}
+
+func TestUser_GetUserIDsByEmails(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore()
+	repo := NewUserRepository(store)
+	ids := prepareUsers(t, ctx, store, "john@example.com", "lisa@example.com")
+
+	result, err := repo.GetUserIDsByEmails(ctx, []string{"john@example.com", "lisa@example.com"})
+	assert.NoError(t, err)
+	assert.Equal(t, ids, result)
+
+	_, err = repo.GetUserIDsByEmails(ctx, []string{"john@example.com", "unknown@example.com"})
+	assert.Equal(t, domain.ErrNotFoundUserByEmail, err)
+}
+
+func TestUser_DeleteWithRelations(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore()
+	repo := NewUserRepository(store)
+	ids := prepareUsers(t, ctx, store, "john@example.com", "lisa@example.com")
+
+	_, err := NewFriendshipRepository(store).Create(ctx, domain.Friendship{
+		UserID:   ids["john@example.com"],
+		FriendID: ids["lisa@example.com"],
+		Status:   domain.FriendshipStatusFriended,
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, domain.ErrUserHasRelations, repo.Delete(ctx, ids["lisa@example.com"]))
+}
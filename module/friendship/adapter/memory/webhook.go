@@ -0,0 +1,171 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/phantranhieunhan/s3-assignment/pkg/util"
+)
+
+type WebhookRepository struct {
+	store *Store
+}
+
+func NewWebhookRepository(store *Store) WebhookRepository {
+	return WebhookRepository{
+		store: store,
+	}
+}
+
+func (w WebhookRepository) Create(ctx context.Context, d domain.Webhook) (string, error) {
+	d.Id = util.GenUUID()
+	now := time.Now().UTC()
+	d.CreatedAt = now
+	d.UpdatedAt = now
+	d.EventTypes = append([]domain.EventType{}, d.EventTypes...)
+
+	err := w.store.write(ctx, func(t *tables) error {
+		t.webhooks.writable()[d.Id] = d
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return d.Id, nil
+}
+
+func (w WebhookRepository) List(ctx context.Context) ([]domain.Webhook, error) {
+	return w.getWebhooks(ctx, func(domain.Webhook) bool { return true }), nil
+}
+
+func (w WebhookRepository) GetByEventType(ctx context.Context, t domain.EventType) ([]domain.Webhook, error) {
+	return w.getWebhooks(ctx, func(d domain.Webhook) bool { return d.Accepts(t) }), nil
+}
+
+func (w WebhookRepository) getWebhooks(ctx context.Context, filter func(d domain.Webhook) bool) []domain.Webhook {
+	result := make([]domain.Webhook, 0)
+	for _, d := range w.store.read(ctx).webhooks.rows {
+		if filter(d) {
+			result = append(result, d)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].CreatedAt.Equal(result[j].CreatedAt) {
+			return result[i].CreatedAt.Before(result[j].CreatedAt)
+		}
+		return result[i].Id < result[j].Id
+	})
+	return result
+}
+
+// Delete removes the webhook with its deliveries
+func (w WebhookRepository) Delete(ctx context.Context, id string) error {
+	return w.store.write(ctx, func(t *tables) error {
+		if _, ok := t.webhooks.rows[id]; !ok {
+			return domain.ErrRecordNotFound
+		}
+		delete(t.webhooks.writable(), id)
+		for deliveryID, d := range t.deliveries.rows {
+			if d.WebhookID == id {
+				delete(t.deliveries.writable(), deliveryID)
+			}
+		}
+		return nil
+	})
+}
+
+// CreateDelivery ignores an event already queued for the webhook, the outbox relays an event at least once
+func (w WebhookRepository) CreateDelivery(ctx context.Context, d domain.WebhookDelivery) (string, error) {
+	d.Id = util.GenUUID()
+	now := time.Now().UTC()
+	d.CreatedAt = now
+	d.UpdatedAt = now
+
+	err := w.store.write(ctx, func(t *tables) error {
+		if _, ok := t.webhooks.rows[d.WebhookID]; !ok {
+			return common.ErrDB(ErrForeignKeyViolation)
+		}
+		for _, m := range t.deliveries.rows {
+			if m.WebhookID == d.WebhookID && m.EventID == d.EventID {
+				return nil
+			}
+		}
+		t.deliveries.writable()[d.Id] = d
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return d.Id, nil
+}
+
+func (w WebhookRepository) GetDelivery(ctx context.Context, id string) (domain.WebhookDelivery, error) {
+	d, ok := w.store.read(ctx).deliveries.rows[id]
+	if !ok {
+		return domain.WebhookDelivery{}, domain.ErrRecordNotFound
+	}
+	return d, nil
+}
+
+// GetDueDeliveries returns the pending deliveries due at the given time, the transactions of the store run one at a time
+// so a delivery is never attempted by two transactions at once
+func (w WebhookRepository) GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]domain.DueWebhookDelivery, error) {
+	t := w.store.read(ctx)
+
+	list := make([]domain.WebhookDelivery, 0)
+	for _, d := range t.deliveries.rows {
+		if d.Status == domain.WebhookDeliveryStatusPending && !d.NextAttemptAt.After(now) {
+			list = append(list, d)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].NextAttemptAt.Equal(list[j].NextAttemptAt) {
+			return list[i].NextAttemptAt.Before(list[j].NextAttemptAt)
+		}
+		return list[i].CreatedAt.Before(list[j].CreatedAt)
+	})
+	if len(list) > limit {
+		list = list[:limit]
+	}
+
+	result := make([]domain.DueWebhookDelivery, 0, len(list))
+	for _, d := range list {
+		result = append(result, domain.DueWebhookDelivery{Delivery: d, Webhook: t.webhooks.rows[d.WebhookID]})
+	}
+	return result, nil
+}
+
+func (w WebhookRepository) UpdateDelivery(ctx context.Context, d domain.WebhookDelivery) error {
+	return w.store.write(ctx, func(t *tables) error {
+		m, ok := t.deliveries.rows[d.Id]
+		if !ok {
+			return nil
+		}
+		m.Status = d.Status
+		m.Attempts = d.Attempts
+		m.LastError = d.LastError
+		m.NextAttemptAt = d.NextAttemptAt
+		m.UpdatedAt = time.Now().UTC()
+		t.deliveries.writable()[d.Id] = m
+		return nil
+	})
+}
+
+func (w WebhookRepository) GetDeliveriesByStatus(ctx context.Context, webhookID string, status domain.WebhookDeliveryStatus) ([]domain.WebhookDelivery, error) {
+	result := make([]domain.WebhookDelivery, 0)
+	for _, d := range w.store.read(ctx).deliveries.rows {
+		if d.WebhookID == webhookID && d.Status == status {
+			result = append(result, d)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].CreatedAt.Equal(result[j].CreatedAt) {
+			return result[i].CreatedAt.After(result[j].CreatedAt)
+		}
+		return result[i].Id < result[j].Id
+	})
+	return result, nil
+}
@@ -0,0 +1,65 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhook_Deliveries(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore()
+	repo := NewWebhookRepository(store)
+
+	webhookID, err := repo.Create(ctx, domain.Webhook{URL: "http://example.com/hook", Secret: "0123456789abcdef", EventTypes: []domain.EventType{domain.EventUserBlocked}})
+	assert.NoError(t, err)
+
+	webhooks, err := repo.GetByEventType(ctx, domain.EventUserBlocked)
+	assert.NoError(t, err)
+	assert.Len(t, webhooks, 1)
+	webhooks, err = repo.GetByEventType(ctx, domain.EventUserCreated)
+	assert.NoError(t, err)
+	assert.Empty(t, webhooks)
+
+	now := time.Now().UTC()
+	delivery := domain.WebhookDelivery{
+		WebhookID:     webhookID,
+		EventID:       "event-1",
+		EventType:     domain.EventUserBlocked,
+		Payload:       json.RawMessage(`{}`),
+		Status:        domain.WebhookDeliveryStatusPending,
+		NextAttemptAt: now,
+	}
+	delivery.Id, err = repo.CreateDelivery(ctx, delivery)
+	assert.NoError(t, err)
+	// the same event is queued once
+	_, err = repo.CreateDelivery(ctx, delivery)
+	assert.NoError(t, err)
+
+	due, err := repo.GetDueDeliveries(ctx, now, 10)
+	assert.NoError(t, err)
+	assert.Len(t, due, 1)
+	assert.Equal(t, delivery.Id, due[0].Delivery.Id)
+	assert.Equal(t, "0123456789abcdef", due[0].Webhook.Secret)
+
+	delivery.Status = domain.WebhookDeliveryStatusDead
+	delivery.Attempts = 8
+	assert.NoError(t, repo.UpdateDelivery(ctx, delivery))
+	due, err = repo.GetDueDeliveries(ctx, now, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, due)
+	dead, err := repo.GetDeliveriesByStatus(ctx, webhookID, domain.WebhookDeliveryStatusDead)
+	assert.NoError(t, err)
+	assert.Len(t, dead, 1)
+	assert.Equal(t, 8, dead[0].Attempts)
+
+	// deleting the webhook deletes its deliveries
+	assert.NoError(t, repo.Delete(ctx, webhookID))
+	assert.Equal(t, domain.ErrRecordNotFound, repo.Delete(ctx, webhookID))
+	_, err = repo.GetDelivery(ctx, delivery.Id)
+	assert.Equal(t, domain.ErrRecordNotFound, err)
+}
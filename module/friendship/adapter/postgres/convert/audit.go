@@ -0,0 +1,23 @@
+package convert
+
+import (
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/adapter/postgres/view"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+func ToAuditEntriesDomain(list []view.AuditEntry) []domain.AuditEntry {
+	result := make([]domain.AuditEntry, 0, len(list))
+	for _, v := range list {
+		result = append(result, domain.AuditEntry{
+			ID:           v.ID,
+			Actor:        v.Actor,
+			Action:       domain.AuditAction(v.Action),
+			TargetIDs:    append([]string{}, v.TargetIDs...),
+			StatusBefore: domain.AuditStatus(v.StatusBefore),
+			StatusAfter:  domain.AuditStatus(v.StatusAfter),
+			RequestID:    v.RequestID,
+			CreatedAt:    v.CreatedAt,
+		})
+	}
+	return result
+}
@@ -0,0 +1,36 @@
+package convert
+
+import (
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/adapter/postgres/view"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+func ToBlockedEmailsDomain(list []view.BlockedEmail) []domain.BlockedEmail {
+	result := make([]domain.BlockedEmail, 0, len(list))
+	for _, v := range list {
+		result = append(result, domain.BlockedEmail{Email: v.Email, BlockedAt: v.BlockedAt})
+	}
+	return result
+}
+
+func ToBlockDomain(v view.Block) domain.Block {
+	return domain.Block{
+		Base: domain.Base{
+			Id:        v.ID,
+			CreatedAt: v.CreatedAt,
+			UpdatedAt: v.UpdatedAt,
+		},
+		UserID:             v.UserID,
+		TargetID:           v.TargetID,
+		FriendshipStatus:   domain.FriendshipStatus(v.FriendshipStatus),
+		SubscriptionStatus: domain.SubscriptionStatus(v.SubscriptionStatus),
+	}
+}
+
+func ToBlocksDomain(list []view.Block) []domain.Block {
+	result := make([]domain.Block, 0, len(list))
+	for _, v := range list {
+		result = append(result, ToBlockDomain(v))
+	}
+	return result
+}
@@ -0,0 +1,20 @@
+package convert
+
+import (
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/adapter/postgres/view"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+func ToEventsDomain(list []view.Event) []domain.Event {
+	result := make([]domain.Event, 0, len(list))
+	for _, v := range list {
+		result = append(result, domain.Event{
+			ID:          v.ID,
+			Type:        domain.EventType(v.EventType),
+			AggregateID: v.AggregateID,
+			Payload:     v.Payload,
+			CreatedAt:   v.CreatedAt,
+		})
+	}
+	return result
+}
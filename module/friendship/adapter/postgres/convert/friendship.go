@@ -2,6 +2,7 @@ package convert
 
 import (
 	"github.com/phantranhieunhan/s3-assignment/module/friendship/adapter/postgres/model"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/adapter/postgres/view"
 	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
 )
 
@@ -50,3 +51,19 @@ func ToMapEmailUserDomainList(users model.UserSlice) map[string]string {
 	}
 	return result
 }
+
+func ToFriendSuggestionsDomain(list []view.FriendSuggestion) []domain.FriendSuggestion {
+	result := make([]domain.FriendSuggestion, 0, len(list))
+	for _, v := range list {
+		result = append(result, domain.FriendSuggestion{Email: v.Email, MutualFriends: v.MutualCount})
+	}
+	return result
+}
+
+func ToFriendSetMembersDomain(list []view.FriendSetMember) []domain.FriendSetMember {
+	result := make([]domain.FriendSetMember, 0, len(list))
+	for _, v := range list {
+		result = append(result, domain.FriendSetMember{Email: v.Email, ConnectedUsers: v.ConnectedUsers})
+	}
+	return result
+}
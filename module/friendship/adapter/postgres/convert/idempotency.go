@@ -0,0 +1,18 @@
+package convert
+
+import (
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/adapter/postgres/view"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+func ToIdempotencyRecordDomain(v view.IdempotencyRecord) domain.IdempotencyRecord {
+	return domain.IdempotencyRecord{
+		Scope:       v.Scope,
+		Key:         v.Key,
+		Fingerprint: v.Fingerprint,
+		StatusCode:  v.StatusCode,
+		ContentType: v.ContentType,
+		Body:        v.Body,
+		CreatedAt:   v.CreatedAt,
+	}
+}
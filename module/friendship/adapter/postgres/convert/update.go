@@ -0,0 +1,36 @@
+package convert
+
+import (
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/adapter/postgres/view"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+func ToUpdatesDomain(list []view.Update) []domain.Update {
+	result := make([]domain.Update, 0, len(list))
+	for _, v := range list {
+		result = append(result, domain.Update{
+			Base: domain.Base{
+				Id:        v.ID,
+				CreatedAt: v.CreatedAt,
+				UpdatedAt: v.UpdatedAt,
+			},
+			UserID:   v.UserID,
+			Text:     v.Text,
+			Mentions: append([]string{}, v.Mentions...),
+		})
+	}
+	return result
+}
+
+func ToFeedItemsDomain(list []view.FeedItem) []domain.FeedItem {
+	result := make([]domain.FeedItem, 0, len(list))
+	for _, v := range list {
+		result = append(result, domain.FeedItem{
+			ID:        v.ID,
+			Sender:    v.Sender,
+			Text:      v.Text,
+			CreatedAt: v.CreatedAt,
+		})
+	}
+	return result
+}
@@ -0,0 +1,43 @@
+package convert
+
+import (
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/adapter/postgres/model"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/adapter/postgres/view"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+func ToUserModel(d domain.User) model.User {
+	return model.User{
+		ID:        d.Base.Id,
+		Email:     d.Email,
+		CreatedAt: d.Base.CreatedAt,
+		UpdatedAt: d.Base.UpdatedAt,
+	}
+}
+
+func ToUserDomain(m model.User) domain.User {
+	return domain.User{
+		Base: domain.Base{
+			Id:        m.ID,
+			CreatedAt: m.CreatedAt,
+			UpdatedAt: m.UpdatedAt,
+		},
+		Email: m.Email,
+	}
+}
+
+func ToUserDeletionDomain(v view.UserDeletion) domain.UserDeletion {
+	return domain.UserDeletion{
+		UserID:      v.UserID,
+		RequestedAt: v.RequestedAt,
+		PurgeAt:     v.PurgeAt,
+	}
+}
+
+func ToUserDeletionsDomain(list []view.UserDeletion) []domain.UserDeletion {
+	result := make([]domain.UserDeletion, 0, len(list))
+	for _, v := range list {
+		result = append(result, ToUserDeletionDomain(v))
+	}
+	return result
+}
@@ -0,0 +1,72 @@
+package convert
+
+import (
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/adapter/postgres/view"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+func ToWebhookDomain(v view.Webhook) domain.Webhook {
+	eventTypes := make([]domain.EventType, 0, len(v.EventTypes))
+	for _, t := range v.EventTypes {
+		eventTypes = append(eventTypes, domain.EventType(t))
+	}
+	return domain.Webhook{
+		Base: domain.Base{
+			Id:        v.ID,
+			CreatedAt: v.CreatedAt,
+			UpdatedAt: v.UpdatedAt,
+		},
+		URL:        v.URL,
+		Secret:     v.Secret,
+		EventTypes: eventTypes,
+	}
+}
+
+func ToWebhooksDomain(list []view.Webhook) []domain.Webhook {
+	result := make([]domain.Webhook, 0, len(list))
+	for _, v := range list {
+		result = append(result, ToWebhookDomain(v))
+	}
+	return result
+}
+
+func ToWebhookDeliveryDomain(v view.WebhookDelivery) domain.WebhookDelivery {
+	return domain.WebhookDelivery{
+		Base: domain.Base{
+			Id:        v.ID,
+			CreatedAt: v.CreatedAt,
+			UpdatedAt: v.UpdatedAt,
+		},
+		WebhookID:     v.WebhookID,
+		EventID:       v.EventID,
+		EventType:     domain.EventType(v.EventType),
+		Payload:       v.Payload,
+		Status:        domain.WebhookDeliveryStatus(v.Status),
+		Attempts:      v.Attempts,
+		LastError:     v.LastError,
+		NextAttemptAt: v.NextAttemptAt,
+	}
+}
+
+func ToWebhookDeliveriesDomain(list []view.WebhookDelivery) []domain.WebhookDelivery {
+	result := make([]domain.WebhookDelivery, 0, len(list))
+	for _, v := range list {
+		result = append(result, ToWebhookDeliveryDomain(v))
+	}
+	return result
+}
+
+func ToDueWebhookDeliveriesDomain(list []view.DueWebhookDelivery) []domain.DueWebhookDelivery {
+	result := make([]domain.DueWebhookDelivery, 0, len(list))
+	for _, v := range list {
+		result = append(result, domain.DueWebhookDelivery{
+			Delivery: ToWebhookDeliveryDomain(v.WebhookDelivery),
+			Webhook: domain.Webhook{
+				Base:   domain.Base{Id: v.WebhookID},
+				URL:    v.URL,
+				Secret: v.Secret,
+			},
+		})
+	}
+	return result
+}
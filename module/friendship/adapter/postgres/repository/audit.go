@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/adapter/postgres"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/adapter/postgres/convert"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/adapter/postgres/model"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/adapter/postgres/view"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/phantranhieunhan/s3-assignment/pkg/util"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+)
+
+type AuditRepository struct {
+	db postgres.Database
+}
+
+func NewAuditRepository(db postgres.Database) AuditRepository {
+	return AuditRepository{
+		db: db,
+	}
+}
+
+// Create appends the entry, a trigger of the table rejects the updates and the deletes
+func (a AuditRepository) Create(ctx context.Context, d domain.AuditEntry) (string, error) {
+	d.ID = util.GenUUID()
+	targetIDs := d.TargetIDs
+	if targetIDs == nil {
+		targetIDs = []string{}
+	}
+	_, err := model.NewQuery(
+		qm.SQL(`insert into audit_log (id, actor, action, target_ids, status_before, status_after, request_id, created_at) values ($1, $2, $3, $4, $5, $6, $7, $8)`,
+			d.ID, d.Actor, string(d.Action), pq.Array(targetIDs), string(d.StatusBefore), string(d.StatusAfter), d.RequestID, d.CreatedAt),
+	).ExecContext(ctx, a.db.Model(ctx))
+	if err != nil {
+		return "", common.ErrDB(err)
+	}
+	return d.ID, nil
+}
+
+func (a AuditRepository) List(ctx context.Context, filter domain.AuditFilter, page domain.Page) ([]domain.AuditEntry, string, error) {
+	page = page.WithDefaults()
+	args := []interface{}{}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	query := "select * from audit_log where true"
+	if filter.UserID != "" {
+		userID := arg(filter.UserID)
+		query += fmt.Sprintf(" and (actor = %s or %s = any(target_ids))", userID, userID)
+	}
+	if filter.Action != "" {
+		query += " and action = " + arg(string(filter.Action))
+	}
+	if !filter.From.IsZero() {
+		query += " and created_at >= " + arg(filter.From)
+	}
+	if !filter.To.IsZero() {
+		query += " and created_at < " + arg(filter.To)
+	}
+	if page.Cursor != "" {
+		cursor, err := domain.DecodeCursor(page.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		query += fmt.Sprintf(" and (created_at, id) < (%s, %s)", arg(cursor.CreatedAt), arg(cursor.Key))
+	}
+	query += " order by created_at desc, id desc limit " + arg(page.Limit+1)
+
+	list := make([]view.AuditEntry, 0)
+	err := model.NewQuery(qm.SQL(query, args...)).Bind(ctx, a.db.Model(ctx), &list)
+	if err != nil {
+		return nil, "", common.ErrDB(err)
+	}
+
+	var nextCursor string
+	if len(list) > page.Limit {
+		list = list[:page.Limit]
+		last := list[len(list)-1]
+		nextCursor = domain.EncodeCursor(domain.Cursor{Key: last.ID, CreatedAt: last.CreatedAt})
+	}
+
+	return convert.ToAuditEntriesDomain(list), nextCursor, nil
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/adapter/postgres/model"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/phantranhieunhan/s3-assignment/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+)
+
+func TestAudit_AppendOnly(t *testing.T) {
+	ctx := context.Background()
+	suite := NewSuite(ctx)
+	repo := NewAuditRepository(suite.db)
+
+	// the entries are kept for good, an actor of its own keeps the test away from the other entries
+	actor := util.GenUUID()
+	id, err := repo.Create(ctx, domain.AuditEntry{
+		Actor:        actor,
+		Action:       domain.AuditConnectFriendship,
+		TargetIDs:    []string{actor, "user-2"},
+		StatusBefore: domain.AuditStatusNone,
+		StatusAfter:  domain.AuditStatusFriended,
+		RequestID:    "request-1",
+		CreatedAt:    time.Now().UTC().Truncate(time.Microsecond),
+	})
+	assert.NoError(t, err)
+
+	entries, _, err := repo.List(ctx, domain.AuditFilter{UserID: actor}, domain.Page{})
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, id, entries[0].ID)
+		assert.Equal(t, []string{actor, "user-2"}, entries[0].TargetIDs)
+		assert.Equal(t, domain.AuditStatusFriended, entries[0].StatusAfter)
+		assert.Equal(t, "request-1", entries[0].RequestID)
+	}
+
+	_, err = model.NewQuery(qm.SQL("update audit_log set actor = 'someone' where id = $1", id)).ExecContext(ctx, suite.db.Model(ctx))
+	assert.Error(t, err)
+	_, err = model.NewQuery(qm.SQL("delete from audit_log where id = $1", id)).ExecContext(ctx, suite.db.Model(ctx))
+	assert.Error(t, err)
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/adapter/postgres"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/adapter/postgres/convert"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/adapter/postgres/model"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/adapter/postgres/view"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/phantranhieunhan/s3-assignment/pkg/util"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+)
+
+type BlockRepository struct {
+	db postgres.Database
+}
+
+func NewBlockRepository(db postgres.Database) BlockRepository {
+	return BlockRepository{
+		db: db,
+	}
+}
+
+func (b BlockRepository) UpsertBlock(ctx context.Context, d domain.Block) (string, error) {
+	query := `insert into blocks (id, user_id, target_id, friendship_status, subscription_status, created_at, updated_at)
+		values ($1, $2, $3, $4, $5, now(), now())
+		on conflict (user_id, target_id) do update
+		set friendship_status = excluded.friendship_status, subscription_status = excluded.subscription_status, updated_at = now()
+		returning id`
+
+	var rows []struct {
+		ID string `boil:"id"`
+	}
+	err := model.NewQuery(
+		qm.SQL(query, util.GenUUID(), d.UserID, d.TargetID, d.FriendshipStatus, d.SubscriptionStatus),
+	).Bind(ctx, b.db.Model(ctx), &rows)
+	if err != nil {
+		return "", common.ErrDB(err)
+	}
+	return rows[0].ID, nil
+}
+
+func (b BlockRepository) GetBlock(ctx context.Context, userID, targetID string) (domain.Block, error) {
+	list := make([]view.Block, 0)
+	err := model.NewQuery(
+		qm.SQL("select * from blocks where user_id = $1 and target_id = $2", userID, targetID),
+	).Bind(ctx, b.db.Model(ctx), &list)
+	if err != nil {
+		return domain.Block{}, common.ErrDB(err)
+	}
+	if len(list) == 0 {
+		return domain.Block{}, domain.ErrRecordNotFound
+	}
+	return convert.ToBlockDomain(list[0]), nil
+}
+
+func (b BlockRepository) Delete(ctx context.Context, id string) error {
+	_, err := model.NewQuery(qm.SQL("delete from blocks where id = $1", id)).ExecContext(ctx, b.db.Model(ctx))
+	if err != nil {
+		return common.ErrDB(err)
+	}
+	return nil
+}
+
+// DeleteByUserID deletes the blocks made by the user and against the user
+func (b BlockRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	_, err := model.NewQuery(qm.SQL("delete from blocks where user_id = $1 or target_id = $1", userID)).ExecContext(ctx, b.db.Model(ctx))
+	if err != nil {
+		return common.ErrDB(err)
+	}
+	return nil
+}
+
+func (b BlockRepository) GetBlockedEmailsByUserID(ctx context.Context, userID string) ([]domain.BlockedEmail, error) {
+	return b.getBlockedEmails(ctx, `select u.email, b.updated_at as blocked_at from blocks b
+		inner join users u on u.id = b.target_id
+		where b.user_id = $1
+		order by b.updated_at desc`, userID)
+}
+
+func (b BlockRepository) GetBlockerEmailsByTargetID(ctx context.Context, targetID string) ([]domain.BlockedEmail, error) {
+	return b.getBlockedEmails(ctx, `select u.email, b.updated_at as blocked_at from blocks b
+		inner join users u on u.id = b.user_id
+		where b.target_id = $1
+		order by b.updated_at desc`, targetID)
+}
+
+// ListBlocksByUserID lists the blocks made by the user, ordered by id from the one after afterID
+func (b BlockRepository) ListBlocksByUserID(ctx context.Context, userID, afterID string, limit int) ([]domain.Block, error) {
+	list := make([]view.Block, 0)
+	err := model.NewQuery(
+		qm.SQL("select * from blocks where user_id = $1 and id > $2 order by id limit $3", userID, afterID, limit),
+	).Bind(ctx, b.db.Model(ctx), &list)
+	if err != nil {
+		return []domain.Block{}, common.ErrDB(err)
+	}
+	return convert.ToBlocksDomain(list), nil
+}
+
+func (b BlockRepository) getBlockedEmails(ctx context.Context, query string, id string) ([]domain.BlockedEmail, error) {
+	list := make([]view.BlockedEmail, 0)
+	err := model.NewQuery(qm.SQL(query, id)).Bind(ctx, b.db.Model(ctx), &list)
+	if err != nil {
+		return []domain.BlockedEmail{}, common.ErrDB(err)
+	}
+	return convert.ToBlockedEmailsDomain(list), nil
+}
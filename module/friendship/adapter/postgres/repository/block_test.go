@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/adapter/postgres/model"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/phantranhieunhan/s3-assignment/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+)
+
+func TestBlock_UpsertBlock(t *testing.T) {
+	ctx := context.Background()
+	suite := NewSuite(ctx)
+	repo := NewBlockRepository(suite.db)
+
+	block := domain.Block{
+		UserID:             util.GenUUID(),
+		TargetID:           util.GenUUID(),
+		FriendshipStatus:   domain.FriendshipStatusUnfriended,
+		SubscriptionStatus: domain.SubscriptionStatusSubscribed,
+	}
+	suite.prepareBlock(t, ctx, block)
+
+	id, err := repo.UpsertBlock(ctx, block)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, id)
+
+	// blocking again keeps the same row with the latest state
+	block.FriendshipStatus = domain.FriendshipStatusBlocked
+	upsertedID, err := repo.UpsertBlock(ctx, block)
+	assert.NoError(t, err)
+	assert.Equal(t, id, upsertedID)
+
+	result, err := repo.GetBlock(ctx, block.UserID, block.TargetID)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.FriendshipStatusBlocked, result.FriendshipStatus)
+	assert.Equal(t, domain.SubscriptionStatusSubscribed, result.SubscriptionStatus)
+
+	suite.rollbackBlock(t, ctx, block)
+}
+
+func TestBlock_GetBlockAndDelete(t *testing.T) {
+	ctx := context.Background()
+	suite := NewSuite(ctx)
+	repo := NewBlockRepository(suite.db)
+
+	block := domain.Block{
+		UserID:   util.GenUUID(),
+		TargetID: util.GenUUID(),
+	}
+	suite.prepareBlock(t, ctx, block)
+
+	_, err := repo.GetBlock(ctx, block.UserID, block.TargetID)
+	assert.Equal(t, domain.ErrRecordNotFound, err)
+
+	block.Id, err = repo.UpsertBlock(ctx, block)
+	assert.NoError(t, err)
+
+	// a block is one-way
+	_, err = repo.GetBlock(ctx, block.TargetID, block.UserID)
+	assert.Equal(t, domain.ErrRecordNotFound, err)
+
+	assert.NoError(t, repo.Delete(ctx, block.Id))
+	_, err = repo.GetBlock(ctx, block.UserID, block.TargetID)
+	assert.Equal(t, domain.ErrRecordNotFound, err)
+
+	suite.rollbackBlock(t, ctx, block)
+}
+
+func TestBlock_GetBlockedAndBlockerEmails(t *testing.T) {
+	ctx := context.Background()
+	suite := NewSuite(ctx)
+	repo := NewBlockRepository(suite.db)
+
+	block := domain.Block{
+		UserID:   util.GenUUID(),
+		TargetID: util.GenUUID(),
+	}
+	suite.prepareBlock(t, ctx, block)
+
+	_, err := repo.UpsertBlock(ctx, block)
+	assert.NoError(t, err)
+
+	blocked, err := repo.GetBlockedEmailsByUserID(ctx, block.UserID)
+	require.NoError(t, err)
+	require.Len(t, blocked, 1)
+	assert.Equal(t, block.TargetID+"@example.com", blocked[0].Email)
+	assert.False(t, blocked[0].BlockedAt.IsZero())
+
+	blockers, err := repo.GetBlockerEmailsByTargetID(ctx, block.TargetID)
+	require.NoError(t, err)
+	require.Len(t, blockers, 1)
+	assert.Equal(t, block.UserID+"@example.com", blockers[0].Email)
+
+	blockers, err = repo.GetBlockerEmailsByTargetID(ctx, block.UserID)
+	assert.NoError(t, err)
+	assert.Len(t, blockers, 0)
+
+	suite.rollbackBlock(t, ctx, block)
+}
+
+func (g *Suite) prepareBlock(t *testing.T, ctx context.Context, block domain.Block) {
+	g.prepareFriendship(t, ctx, domain.Friendship{UserID: block.UserID, FriendID: block.TargetID})
+}
+
+func (g *Suite) rollbackBlock(t *testing.T, ctx context.Context, block domain.Block) {
+	db := g.db.Model(ctx)
+	_, err := model.NewQuery(qm.SQL("delete from blocks where user_id = $1 and target_id = $2", block.UserID, block.TargetID)).ExecContext(ctx, db)
+	assert.NoError(t, err)
+
+	g.rollbackFriendship(t, ctx, domain.Friendship{UserID: block.UserID, FriendID: block.TargetID})
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/lib/pq"
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/adapter/postgres"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/adapter/postgres/convert"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/adapter/postgres/model"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/adapter/postgres/view"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/phantranhieunhan/s3-assignment/pkg/util"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+)
+
+// relayLockKey is the key of the advisory lock held by the relay transaction, one instance relays at a time
+const relayLockKey int64 = 918_273_646
+
+type EventRepository struct {
+	db postgres.Database
+}
+
+func NewEventRepository(db postgres.Database) EventRepository {
+	return EventRepository{
+		db: db,
+	}
+}
+
+func (e EventRepository) Create(ctx context.Context, d domain.Event) (string, error) {
+	d.ID = util.GenUUID()
+	_, err := model.NewQuery(
+		qm.SQL(`insert into outbox_events (id, event_type, aggregate_id, payload, created_at) values ($1, $2, $3, $4, $5)`,
+			d.ID, string(d.Type), d.AggregateID, []byte(d.Payload), d.CreatedAt),
+	).ExecContext(ctx, e.db.Model(ctx))
+	if err != nil {
+		return "", common.ErrDB(err)
+	}
+	return d.ID, nil
+}
+
+// GetUndelivered takes the relay lock before reading the oldest events, the instances relay one after the other
+// so the events are published in the order they were written across the batches. The lock is released with the transaction
+func (e EventRepository) GetUndelivered(ctx context.Context, limit int) ([]domain.Event, error) {
+	_, err := model.NewQuery(
+		qm.SQL("select pg_advisory_xact_lock($1)", relayLockKey),
+	).ExecContext(ctx, e.db.Model(ctx))
+	if err != nil {
+		return nil, common.ErrDB(err)
+	}
+
+	list := make([]view.Event, 0)
+	err = model.NewQuery(
+		qm.SQL(`select id, event_type, aggregate_id, payload, created_at from outbox_events
+			where delivered_at is null
+			order by seq
+			limit $1`, limit),
+	).Bind(ctx, e.db.Model(ctx), &list)
+	if err != nil {
+		return nil, common.ErrDB(err)
+	}
+	return convert.ToEventsDomain(list), nil
+}
+
+func (e EventRepository) MarkDelivered(ctx context.Context, ids []string) error {
+	_, err := model.NewQuery(
+		qm.SQL("update outbox_events set delivered_at = now() where id = any($1)", pq.Array(ids)),
+	).ExecContext(ctx, e.db.Model(ctx))
+	if err != nil {
+		return common.ErrDB(err)
+	}
+	return nil
+}
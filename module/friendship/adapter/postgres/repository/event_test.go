@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/adapter/postgres/model"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+)
+
+func TestEvent_CreateGetUndeliveredMarkDelivered(t *testing.T) {
+	ctx := context.Background()
+	suite := NewSuite(ctx)
+	repo := NewEventRepository(suite.db)
+
+	// other undelivered events may exist in the outbox, run within a transaction to only see the new ones in order
+	err := suite.db.WithinTransaction(ctx, func(ctx context.Context) error {
+		ids := make([]string, 0)
+		for _, aggregateID := range []string{"aggregate-1", "aggregate-2"} {
+			e, err := domain.NewEvent(domain.EventUserCreated, aggregateID, domain.UserEvent{Email: aggregateID + "@example.com"})
+			assert.NoError(t, err)
+			id, err := repo.Create(ctx, e)
+			assert.NoError(t, err)
+			ids = append(ids, id)
+		}
+
+		events, err := repo.GetUndelivered(ctx, 1000)
+		assert.NoError(t, err)
+		assert.GreaterOrEqual(t, len(events), 2)
+		last := events[len(events)-2:]
+		assert.Equal(t, ids, []string{last[0].ID, last[1].ID})
+		assert.Equal(t, domain.EventUserCreated, last[0].Type)
+		assert.Equal(t, "aggregate-1", last[0].AggregateID)
+		assert.JSONEq(t, `{"email":"aggregate-1@example.com"}`, string(last[0].Payload))
+
+		assert.NoError(t, repo.MarkDelivered(ctx, ids))
+		events, err = repo.GetUndelivered(ctx, 1000)
+		assert.NoError(t, err)
+		for _, e := range events {
+			assert.NotContains(t, ids, e.ID)
+		}
+
+		_, err = model.NewQuery(qm.SQL("delete from outbox_events where id = any($1)", pq.Array(ids))).ExecContext(ctx, suite.db.Model(ctx))
+		return err
+	})
+	assert.NoError(t, err)
+}
+
+func TestEvent_GetUndeliveredWaitsForTheOtherRelay(t *testing.T) {
+	ctx := context.Background()
+	suite := NewSuite(ctx)
+	repo := NewEventRepository(suite.db)
+
+	locked := make(chan struct{})
+	release := make(chan struct{})
+	first := make(chan error, 1)
+	go func() {
+		first <- suite.db.WithinTransaction(ctx, func(ctx context.Context) error {
+			_, err := repo.GetUndelivered(ctx, 1)
+			close(locked)
+			<-release
+			return err
+		})
+	}()
+	<-locked
+
+	// the second relay reads the outbox only once the first one is done with its batch
+	second := make(chan error, 1)
+	go func() {
+		second <- suite.db.WithinTransaction(ctx, func(ctx context.Context) error {
+			_, err := repo.GetUndelivered(ctx, 1)
+			return err
+		})
+	}()
+	select {
+	case err := <-second:
+		t.Fatalf("the second relay did not wait for the first one: %v", err)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	close(release)
+	assert.NoError(t, <-first)
+	assert.NoError(t, <-second)
+}
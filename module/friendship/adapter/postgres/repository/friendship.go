@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 
+	"github.com/lib/pq"
 	"github.com/phantranhieunhan/s3-assignment/common"
 	"github.com/phantranhieunhan/s3-assignment/common/adapter/postgres"
 	"github.com/phantranhieunhan/s3-assignment/module/friendship/adapter/postgres/convert"
@@ -45,6 +46,20 @@ func (f FriendshipRepository) UpdateStatus(ctx context.Context, id string, statu
 	return nil
 }
 
+func (f FriendshipRepository) Update(ctx context.Context, d domain.Friendship) error {
+	m := convert.ToFriendshipModel(d)
+	_, err := m.Update(ctx, f.db.Model(ctx), boil.Whitelist(
+		model.FriendshipColumns.UserID,
+		model.FriendshipColumns.FriendID,
+		model.FriendshipColumns.Status,
+		model.FriendshipColumns.UpdatedAt,
+	))
+	if err != nil {
+		return common.ErrDB(err)
+	}
+	return nil
+}
+
 func (f FriendshipRepository) GetFriendshipByUserIDs(ctx context.Context, userID, friendID string) (domain.Friendship, error) {
 	m, err := model.Friendships(qm.Where("(user_id = ? AND friend_id = ?) OR (user_id = ? AND friend_id = ?)", userID, friendID, friendID, userID)).All(ctx, f.db.Model(ctx))
 
@@ -57,8 +72,72 @@ func (f FriendshipRepository) GetFriendshipByUserIDs(ctx context.Context, userID
 	return convert.ToFriendshipDomain(*(m[0])), nil
 }
 
-func (f FriendshipRepository) GetFriendshipByUserIDAndStatus(ctx context.Context, mapEmailUser map[string]string, status ...domain.FriendshipStatus) ([]string, error) {
+// ListFriendshipsByUserID lists the friendships of the user in both directions and any status,
+// ordered by id from the one after afterID
+func (f FriendshipRepository) ListFriendshipsByUserID(ctx context.Context, userID, afterID string, limit int) (domain.Friendships, error) {
+	m, err := model.Friendships(
+		qm.Where("(user_id = ? OR friend_id = ?) AND id > ?", userID, userID, afterID),
+		qm.OrderBy(model.FriendshipColumns.ID),
+		qm.Limit(limit),
+	).All(ctx, f.db.Model(ctx))
+	if err != nil {
+		return domain.Friendships{}, common.ErrDB(err)
+	}
+	return convert.ToFriendshipsDomain(m), nil
+}
+
+// DeleteByUserID deletes the friendships of the user in both directions
+func (f FriendshipRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	_, err := model.Friendships(qm.Where("user_id = ? OR friend_id = ?", userID, userID)).DeleteAll(ctx, f.db.Model(ctx))
+	if err != nil {
+		return common.ErrDB(err)
+	}
+	return nil
+}
+
+// GetFriendshipByUserIDAndStatus lists a page of the friends shared by all the users, a single user gives its own friends.
+// A friend shared by several users is dated by its latest friendship
+func (f FriendshipRepository) GetFriendshipByUserIDAndStatus(ctx context.Context, mapEmailUser map[string]string, page domain.Page, status ...domain.FriendshipStatus) ([]string, string, error) {
+	emptyList := []string{}
+	page = page.WithDefaults()
 
+	userIDs := util.MapValuesToSlice(mapEmailUser)
+	statusList := make([]int64, 0, len(status))
+	for _, v := range status {
+		statusList = append(statusList, int64(v))
+	}
+
+	query := `select u.email, max(fr.created_at) as created_at
+		from (
+			select i.user_id as input_id, case when f.user_id = i.user_id then f.friend_id else f.user_id end as friend_id, f.created_at
+			from friendships f
+			inner join unnest($1::text[]) as i(user_id) on f.user_id = i.user_id or f.friend_id = i.user_id
+			where f.status = any($2)
+		) fr
+		inner join users u on u.id = fr.friend_id
+		where fr.friend_id <> all($1::text[])
+		group by u.email
+		having count(distinct fr.input_id) = $3`
+	query, args, err := pageQuery(query, []interface{}{pq.Array(userIDs), pq.Array(statusList), len(userIDs)}, page)
+	if err != nil {
+		return emptyList, "", err
+	}
+
+	list := make([]view.PageEmail, 0)
+	err = model.NewQuery(qm.SQL(query, args...)).Bind(ctx, f.db.Model(ctx), &list)
+	if err != nil {
+		return emptyList, "", common.ErrDB(err)
+	}
+
+	if len(list) == 0 {
+		return emptyList, "", domain.ErrRecordNotFound
+	}
+
+	result, nextCursor := pageResult(list, page)
+	return result, nextCursor, nil
+}
+
+func (f FriendshipRepository) GetFriendRequestEmails(ctx context.Context, userID string, direction domain.FriendRequestDirection) ([]string, error) {
 	var (
 		resultEmails []view.Email
 		emptyList    = []string{}
@@ -67,47 +146,256 @@ func (f FriendshipRepository) GetFriendshipByUserIDAndStatus(ctx context.Context
 	where := []qm.QueryMod{
 		qm.Select("u1.email as user_email", "u2.email as friend_email"),
 		qm.From("friendships f"),
-		qm.LeftOuterJoin("users u1 on f.user_id = u1.id"),
-		qm.LeftOuterJoin("users u2 on f.friend_id = u2.id"),
+		qm.InnerJoin("users u1 on f.user_id = u1.id"),
+		qm.InnerJoin("users u2 on f.friend_id = u2.id"),
+		qm.Where("f.status = ?", domain.FriendshipStatusPending),
+		qm.OrderBy("f.updated_at desc"),
 	}
-	userIDs := util.MapValuesToSlice(mapEmailUser)
-	for _, userID := range userIDs {
-		where = append(where, qm.Or("(f.user_id = ? OR f.friend_id = ?)", userID, userID))
+	switch direction {
+	case domain.FriendRequestDirectionIncoming:
+		where = append(where, qm.Where("f.friend_id = ?", userID))
+	case domain.FriendRequestDirectionOutgoing:
+		where = append(where, qm.Where("f.user_id = ?", userID))
+	default:
+		return emptyList, domain.ErrFriendRequestDirectionIsNotValid
 	}
-	statusList, err := util.InterfaceSlice(status)
+
+	err := model.NewQuery(where...).Bind(ctx, f.db.Model(ctx), &resultEmails)
 	if err != nil {
-		return emptyList, err
+		return emptyList, common.ErrDB(err)
 	}
 
-	where = append(where, qm.AndIn("status IN ?", statusList...))
+	result := make([]string, 0, len(resultEmails))
+	for _, v := range resultEmails {
+		if direction == domain.FriendRequestDirectionIncoming {
+			result = append(result, v.UserEmail)
+		} else {
+			result = append(result, v.FriendEmail)
+		}
+	}
+
+	return result, nil
+}
 
-	err = model.NewQuery(where...).Bind(ctx, f.db.Model(ctx), &resultEmails)
+// GetFriendSuggestions ranks the friends of the user's friends by the number of mutual friends,
+// users already connected, pending, blocked in the friendships or blocked in either direction are left out
+func (f FriendshipRepository) GetFriendSuggestions(ctx context.Context, userID string, limit int) ([]domain.FriendSuggestion, error) {
+	query := `with friends as (
+			select case when f.user_id = $1 then f.friend_id else f.user_id end as friend_id
+			from friendships f
+			where (f.user_id = $1 or f.friend_id = $1) and f.status = $2
+		),
+		candidates as (
+			select case when f.user_id = fr.friend_id then f.friend_id else f.user_id end as candidate_id, fr.friend_id as mutual_id
+			from friendships f
+			inner join friends fr on f.user_id = fr.friend_id or f.friend_id = fr.friend_id
+			where f.status = $2
+		)
+		select u.email, count(distinct c.mutual_id) as mutual_count
+		from candidates c
+		inner join users u on u.id = c.candidate_id
+		where c.candidate_id <> $1
+			and not exists (
+				select 1 from friendships x
+				where ((x.user_id = $1 and x.friend_id = c.candidate_id) or (x.user_id = c.candidate_id and x.friend_id = $1))
+					and x.status in ($2, $3, $4)
+			)
+			and not exists (
+				select 1 from blocks b
+				where (b.user_id = $1 and b.target_id = c.candidate_id) or (b.user_id = c.candidate_id and b.target_id = $1)
+			)
+		group by u.email
+		order by mutual_count desc, u.email
+		limit $5`
+
+	list := make([]view.FriendSuggestion, 0)
+	err := model.NewQuery(
+		qm.SQL(query, userID, domain.FriendshipStatusFriended, domain.FriendshipStatusPending, domain.FriendshipStatusBlocked, limit),
+	).Bind(ctx, f.db.Model(ctx), &list)
 	if err != nil {
-		return emptyList, common.ErrDB(err)
+		return nil, common.ErrDB(err)
+	}
+
+	return convert.ToFriendSuggestionsDomain(list), nil
+}
+
+// friendSetHaving filters the friends of the users grouped by friend for each set operation
+func friendSetHaving(userIDs []string, operation domain.FriendSetOperation) (string, []interface{}, bool) {
+	switch operation {
+	case domain.FriendSetOperationIntersection:
+		return "count(distinct fr.input_id) = $3", []interface{}{len(userIDs)}, true
+	case domain.FriendSetOperationUnion:
+		return "count(distinct fr.input_id) > 0", nil, true
+	case domain.FriendSetOperationDifference:
+		return "count(distinct fr.input_id) = 1 and bool_or(fr.input_id = $3)", []interface{}{userIDs[0]}, true
 	}
+	return "", nil, false
+}
 
-	if len(resultEmails) == 0 {
-		return emptyList, domain.ErrRecordNotFound
+// GetFriendSet combines the friends of the users with the set operation in a single query,
+// each friend comes with the number of users it is friend with and the users themselves are left out
+func (f FriendshipRepository) GetFriendSet(ctx context.Context, userIDs []string, operation domain.FriendSetOperation) ([]domain.FriendSetMember, error) {
+	if len(userIDs) == 0 {
+		return []domain.FriendSetMember{}, nil
+	}
+	having, havingArgs, ok := friendSetHaving(userIDs, operation)
+	if !ok {
+		return nil, domain.ErrFriendSetOperationIsNotValid
 	}
 
-	result := f.getFriendsOfUsers(resultEmails, mapEmailUser)
+	query := `with inputs as (
+			select unnest($1::text[]) as user_id
+		),
+		friends as (
+			select i.user_id as input_id, case when f.user_id = i.user_id then f.friend_id else f.user_id end as friend_id
+			from friendships f
+			inner join inputs i on f.user_id = i.user_id or f.friend_id = i.user_id
+			where f.status = $2
+		)
+		select u.email, count(distinct fr.input_id) as connected_users
+		from friends fr
+		inner join users u on u.id = fr.friend_id
+		where fr.friend_id <> all($1::text[])
+		group by u.email
+		having ` + having + `
+		order by connected_users desc, u.email`
 
-	return result, nil
+	args := append([]interface{}{pq.Array(userIDs), domain.FriendshipStatusFriended}, havingArgs...)
+	list := make([]view.FriendSetMember, 0)
+	err := model.NewQuery(
+		qm.SQL(query, args...),
+	).Bind(ctx, f.db.Model(ctx), &list)
+	if err != nil {
+		return nil, common.ErrDB(err)
+	}
+
+	return convert.ToFriendSetMembersDomain(list), nil
 }
 
-// get friendIDs from userId or friendId field if not same userID
-func (f FriendshipRepository) getFriendsOfUsers(entireEmails []view.Email, mapEmailUser map[string]string) []string {
-	result := make([]string, 0)
-	emails := util.MapKeysToSlice(mapEmailUser)
-	for _, v := range entireEmails {
-		var y string
-		if util.IsContain(emails, v.UserEmail) {
-			y = v.FriendEmail
+// GetShortestFriendPath returns the user ids of the shortest chain of friends from fromID to toID,
+// both included, with at most maxDepth friendships. It runs a bidirectional breadth first search
+// loading one level of friendships per query, blocked friendships and blocked users are not crossed.
+// It returns ErrRecordNotFound when the users are not connected within maxDepth.
+func (f FriendshipRepository) GetShortestFriendPath(ctx context.Context, fromID, toID string, maxDepth int) ([]string, error) {
+	if fromID == toID {
+		return []string{fromID}, nil
+	}
+
+	from := newPathSearch(fromID)
+	to := newPathSearch(toID)
+	for depth := 0; depth < maxDepth; depth++ {
+		// expand the smaller side to keep the frontier queries small
+		near, far := from, to
+		if len(to.frontier) < len(from.frontier) {
+			near, far = to, from
+		}
+		if len(near.frontier) == 0 {
+			break
+		}
+
+		edges, err := f.getFriendEdges(ctx, near.frontier)
+		if err != nil {
+			return nil, err
 		}
-		if util.IsContain(emails, v.FriendEmail) {
-			y = v.UserEmail
+
+		meeting, found := near.expand(edges, far)
+		if found {
+			return joinPaths(from.pathTo(meeting), to.pathTo(meeting)), nil
 		}
-		result = append(result, y)
 	}
-	return result
+
+	return nil, domain.ErrRecordNotFound
+}
+
+// getFriendEdges loads the friended friendships touching the users which are not hidden by a block
+func (f FriendshipRepository) getFriendEdges(ctx context.Context, userIDs []string) ([]view.FriendEdge, error) {
+	query := `select f.user_id, f.friend_id from friendships f
+		where f.status = $1 and (f.user_id = any($2) or f.friend_id = any($2))
+			and not exists (
+				select 1 from blocks b
+				where (b.user_id = f.user_id and b.target_id = f.friend_id) or (b.user_id = f.friend_id and b.target_id = f.user_id)
+			)`
+
+	edges := make([]view.FriendEdge, 0)
+	err := model.NewQuery(
+		qm.SQL(query, domain.FriendshipStatusFriended, pq.Array(userIDs)),
+	).Bind(ctx, f.db.Model(ctx), &edges)
+	if err != nil {
+		return nil, common.ErrDB(err)
+	}
+	return edges, nil
+}
+
+// pathSearch is one side of the bidirectional search
+type pathSearch struct {
+	parents  map[string]string
+	depths   map[string]int
+	frontier []string
+}
+
+func newPathSearch(root string) *pathSearch {
+	return &pathSearch{
+		parents:  map[string]string{root: ""},
+		depths:   map[string]int{root: 0},
+		frontier: []string{root},
+	}
+}
+
+// expand visits the next level from the frontier and returns the node where the shortest path meets the other side
+func (s *pathSearch) expand(edges []view.FriendEdge, other *pathSearch) (string, bool) {
+	inFrontier := make(map[string]bool, len(s.frontier))
+	for _, id := range s.frontier {
+		inFrontier[id] = true
+	}
+
+	var (
+		next     []string
+		meeting  string
+		shortest = -1
+	)
+	visit := func(parent, child string) {
+		if !inFrontier[parent] {
+			return
+		}
+		depth := s.depths[parent] + 1
+		if d, ok := s.depths[child]; ok && d < depth {
+			return
+		}
+		if _, ok := s.parents[child]; !ok {
+			s.parents[child] = parent
+			s.depths[child] = depth
+			next = append(next, child)
+		}
+		if d, ok := other.depths[child]; ok && (shortest == -1 || depth+d < shortest) {
+			shortest = depth + d
+			meeting = child
+			s.parents[child] = parent
+		}
+	}
+	for _, e := range edges {
+		visit(e.UserID, e.FriendID)
+		visit(e.FriendID, e.UserID)
+	}
+
+	s.frontier = next
+	return meeting, shortest != -1
+}
+
+// pathTo returns the ids from the root of the search to the node
+func (s *pathSearch) pathTo(node string) []string {
+	path := make([]string, 0, s.depths[node]+1)
+	for id := node; id != ""; id = s.parents[id] {
+		path = append([]string{id}, path...)
+	}
+	return path
+}
+
+// joinPaths joins the path from the source to the meeting node and the path from the target to it
+func joinPaths(fromPath, toPath []string) []string {
+	path := make([]string, 0, len(fromPath)+len(toPath)-1)
+	path = append(path, fromPath...)
+	for i := len(toPath) - 2; i >= 0; i-- {
+		path = append(path, toPath[i])
+	}
+	return path
 }
@@ -2,6 +2,9 @@ package repository
 
 import (
 	"context"
+	"fmt"
+	"math/rand"
+	"sort"
 	"testing"
 
 	"github.com/phantranhieunhan/s3-assignment/module/friendship/adapter/postgres/model"
@@ -9,6 +12,7 @@ import (
 	"github.com/phantranhieunhan/s3-assignment/pkg/util"
 	"github.com/stretchr/testify/assert"
 	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
 )
 
 type FriendshipTestCase struct {
@@ -148,7 +152,7 @@ func TestGetFriendshipByUserIDAndStatus(t *testing.T) {
 			mapEmailUser := map[string]string{
 				fri.UserID + "@example.com": fri.UserID,
 			}
-			result, err := repo.GetFriendshipByUserIDAndStatus(ctx, mapEmailUser, domain.FriendshipStatusFriended)
+			result, _, err := repo.GetFriendshipByUserIDAndStatus(ctx, mapEmailUser, domain.Page{}, domain.FriendshipStatusFriended)
 			if tc.hasError {
 				assert.Len(t, result, 0)
 				assert.Equal(t, err, domain.ErrRecordNotFound)
@@ -162,6 +166,126 @@ func TestGetFriendshipByUserIDAndStatus(t *testing.T) {
 	}
 }
 
+func TestFriendship_GetFriendshipByUserIDAndStatus_Page(t *testing.T) {
+	ctx := context.Background()
+	suite := NewSuite(ctx)
+	repo := NewFriendshipRepository(suite.db)
+
+	names := []string{"a", "b", "c", "d", "e", "f", "g"}
+	graph := suite.prepareFriendGraph(t, ctx, names, []domain.Friendship{
+		{UserID: "a", FriendID: "e", Status: domain.FriendshipStatusFriended},
+		{UserID: "c", FriendID: "a", Status: domain.FriendshipStatusFriended},
+		{UserID: "a", FriendID: "f", Status: domain.FriendshipStatusFriended},
+		{UserID: "a", FriendID: "d", Status: domain.FriendshipStatusFriended},
+		{UserID: "a", FriendID: "g", Status: domain.FriendshipStatusPending},
+		{UserID: "b", FriendID: "d", Status: domain.FriendshipStatusFriended},
+		{UserID: "b", FriendID: "c", Status: domain.FriendshipStatusFriended},
+		{UserID: "a", FriendID: "b", Status: domain.FriendshipStatusFriended},
+	})
+	emails := func(names ...string) []string {
+		result := make([]string, 0, len(names))
+		for _, name := range names {
+			result = append(result, graph.users[name].Email)
+		}
+		return result
+	}
+	// reads every page of the list with the sort
+	readAll := func(mapEmailUser map[string]string, page domain.Page) []string {
+		result := make([]string, 0)
+		for {
+			list, nextCursor, err := repo.GetFriendshipByUserIDAndStatus(ctx, mapEmailUser, page, domain.FriendshipStatusFriended)
+			assert.NoError(t, err)
+			assert.LessOrEqual(t, len(list), page.Limit)
+			result = append(result, list...)
+			if nextCursor == "" {
+				return result
+			}
+			page.Cursor = nextCursor
+		}
+	}
+	a := map[string]string{graph.users["a"].Email: graph.users["a"].ID}
+	ab := map[string]string{graph.users["a"].Email: graph.users["a"].ID, graph.users["b"].Email: graph.users["b"].ID}
+
+	byEmail := emails("b", "c", "d", "e", "f")
+	sort.Strings(byEmail)
+	assert.Equal(t, byEmail, readAll(a, domain.Page{Limit: 2, Sort: domain.PageSortEmail}))
+	assert.Equal(t, emails("e", "c", "f", "d", "b"), readAll(a, domain.Page{Limit: 2, Sort: domain.PageSortCreatedAt}))
+
+	// the mutual friends are dated by the latest of the two friendships
+	assert.Equal(t, emails("d", "c"), readAll(ab, domain.Page{Limit: 1, Sort: domain.PageSortCreatedAt}))
+
+	m, err := model.Friendships(qm.Where("id = ?", graph.friendshipIDs[2])).One(ctx, suite.db.Model(ctx))
+	assert.NoError(t, err)
+	assert.Equal(t, emails("f", "d", "b"), readAll(a, domain.Page{Limit: 2, Sort: domain.PageSortCreatedAt, Since: m.CreatedAt}))
+
+	_, _, err = repo.GetFriendshipByUserIDAndStatus(ctx, a, domain.Page{Cursor: "not-a-cursor"}, domain.FriendshipStatusFriended)
+	assert.Equal(t, domain.ErrCursorIsNotValid, err)
+
+	suite.rollbackFriendGraph(t, ctx, graph)
+}
+
+func TestFriendship_Update(t *testing.T) {
+	ctx := context.Background()
+	suite := NewSuite(ctx)
+	repo := NewFriendshipRepository(suite.db)
+
+	fri := domain.Friendship{
+		UserID:   util.GenUUID(),
+		FriendID: util.GenUUID(),
+		Status:   domain.FriendshipStatusUnfriended,
+	}
+	suite.prepareFriendship(t, ctx, fri)
+	var err error
+	fri.Id, err = repo.Create(ctx, fri)
+	assert.NoError(t, err)
+
+	updated := fri
+	updated.UserID, updated.FriendID = fri.FriendID, fri.UserID
+	updated.Status = domain.FriendshipStatusPending
+	assert.NoError(t, repo.Update(ctx, updated))
+
+	result, err := repo.GetFriendshipByUserIDs(ctx, fri.UserID, fri.FriendID)
+	assert.NoError(t, err)
+	assert.Equal(t, updated.UserID, result.UserID)
+	assert.Equal(t, updated.FriendID, result.FriendID)
+	assert.Equal(t, domain.FriendshipStatusPending, result.Status)
+
+	suite.rollbackFriendship(t, ctx, fri)
+}
+
+func TestGetFriendRequestEmails(t *testing.T) {
+	ctx := context.Background()
+	suite := NewSuite(ctx)
+	repo := NewFriendshipRepository(suite.db)
+
+	fri := domain.Friendship{
+		UserID:   util.GenUUID(),
+		FriendID: util.GenUUID(),
+		Status:   domain.FriendshipStatusPending,
+	}
+	suite.prepareFriendship(t, ctx, fri)
+	var err error
+	fri.Id, err = repo.Create(ctx, fri)
+	assert.NoError(t, err)
+
+	outgoing, err := repo.GetFriendRequestEmails(ctx, fri.UserID, domain.FriendRequestDirectionOutgoing)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{fri.FriendID + "@example.com"}, outgoing)
+
+	incoming, err := repo.GetFriendRequestEmails(ctx, fri.FriendID, domain.FriendRequestDirectionIncoming)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{fri.UserID + "@example.com"}, incoming)
+
+	incoming, err = repo.GetFriendRequestEmails(ctx, fri.UserID, domain.FriendRequestDirectionIncoming)
+	assert.NoError(t, err)
+	assert.Len(t, incoming, 0)
+
+	_, err = repo.GetFriendRequestEmails(ctx, fri.UserID, domain.FriendRequestDirectionInvalid)
+	assert.Equal(t, domain.ErrFriendRequestDirectionIsNotValid, err)
+
+	suite.rollbackFriendship(t, ctx, fri)
+}
+
 func (g *Suite) prepareFriendship(t *testing.T, ctx context.Context, sub domain.Friendship) {
 	db := g.db.Model(ctx)
 	u := model.User{
@@ -196,3 +320,263 @@ func (g *Suite) rollbackFriendship(t *testing.T, ctx context.Context, sub domain
 	_, err := users.DeleteAll(ctx, db)
 	assert.NoError(t, err)
 }
+
+func TestFriendship_GetFriendSuggestions(t *testing.T) {
+	ctx := context.Background()
+	suite := NewSuite(ctx)
+	repo := NewFriendshipRepository(suite.db)
+	blockRepo := NewBlockRepository(suite.db)
+
+	graph := suite.prepareFriendGraph(t, ctx, []string{"a", "b", "c", "d", "e", "f", "g", "h"}, []domain.Friendship{
+		{UserID: "a", FriendID: "b", Status: domain.FriendshipStatusFriended},
+		{UserID: "a", FriendID: "c", Status: domain.FriendshipStatusFriended},
+		{UserID: "b", FriendID: "d", Status: domain.FriendshipStatusFriended},
+		{UserID: "c", FriendID: "d", Status: domain.FriendshipStatusFriended},
+		{UserID: "e", FriendID: "c", Status: domain.FriendshipStatusFriended},
+		{UserID: "b", FriendID: "f", Status: domain.FriendshipStatusFriended},
+		{UserID: "a", FriendID: "f", Status: domain.FriendshipStatusUnfriended},
+		{UserID: "b", FriendID: "g", Status: domain.FriendshipStatusFriended},
+		{UserID: "b", FriendID: "h", Status: domain.FriendshipStatusFriended},
+		{UserID: "a", FriendID: "h", Status: domain.FriendshipStatusPending},
+	})
+	_, err := blockRepo.UpsertBlock(ctx, domain.Block{UserID: graph.users["g"].ID, TargetID: graph.users["a"].ID})
+	assert.NoError(t, err)
+
+	suggestions, err := repo.GetFriendSuggestions(ctx, graph.users["a"].ID, 10)
+	assert.NoError(t, err)
+	assert.Len(t, suggestions, 3)
+	assert.Equal(t, domain.FriendSuggestion{Email: graph.users["d"].Email, MutualFriends: 2}, suggestions[0])
+	assert.ElementsMatch(t, []domain.FriendSuggestion{
+		{Email: graph.users["e"].Email, MutualFriends: 1},
+		{Email: graph.users["f"].Email, MutualFriends: 1},
+	}, suggestions[1:])
+
+	suggestions, err = repo.GetFriendSuggestions(ctx, graph.users["a"].ID, 1)
+	assert.NoError(t, err)
+	assert.Len(t, suggestions, 1)
+
+	suite.rollbackFriendGraph(t, ctx, graph)
+}
+
+func TestFriendship_GetFriendSet(t *testing.T) {
+	ctx := context.Background()
+	suite := NewSuite(ctx)
+	repo := NewFriendshipRepository(suite.db)
+
+	graph := suite.prepareFriendGraph(t, ctx, []string{"a", "b", "c", "v", "w", "x", "y", "z"}, []domain.Friendship{
+		{UserID: "a", FriendID: "b", Status: domain.FriendshipStatusFriended},
+		{UserID: "a", FriendID: "x", Status: domain.FriendshipStatusFriended},
+		{UserID: "x", FriendID: "b", Status: domain.FriendshipStatusFriended},
+		{UserID: "c", FriendID: "x", Status: domain.FriendshipStatusFriended},
+		{UserID: "a", FriendID: "y", Status: domain.FriendshipStatusFriended},
+		{UserID: "b", FriendID: "y", Status: domain.FriendshipStatusFriended},
+		{UserID: "z", FriendID: "a", Status: domain.FriendshipStatusFriended},
+		{UserID: "c", FriendID: "w", Status: domain.FriendshipStatusFriended},
+		{UserID: "a", FriendID: "v", Status: domain.FriendshipStatusPending},
+	})
+	userIDs := []string{graph.users["a"].ID, graph.users["b"].ID, graph.users["c"].ID}
+	member := func(name string, connected int) domain.FriendSetMember {
+		return domain.FriendSetMember{Email: graph.users[name].Email, ConnectedUsers: connected}
+	}
+
+	result, err := repo.GetFriendSet(ctx, userIDs, domain.FriendSetOperationIntersection)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.FriendSetMember{member("x", 3)}, result)
+
+	result, err = repo.GetFriendSet(ctx, userIDs, domain.FriendSetOperationUnion)
+	assert.NoError(t, err)
+	assert.Len(t, result, 4)
+	assert.Equal(t, []domain.FriendSetMember{member("x", 3), member("y", 2)}, result[:2])
+	assert.ElementsMatch(t, []domain.FriendSetMember{member("w", 1), member("z", 1)}, result[2:])
+
+	result, err = repo.GetFriendSet(ctx, userIDs, domain.FriendSetOperationDifference)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.FriendSetMember{member("z", 1)}, result)
+
+	_, err = repo.GetFriendSet(ctx, userIDs, domain.FriendSetOperation("xor"))
+	assert.Equal(t, domain.ErrFriendSetOperationIsNotValid, err)
+
+	suite.rollbackFriendGraph(t, ctx, graph)
+}
+
+func TestFriendship_GetShortestFriendPath_Chain(t *testing.T) {
+	ctx := context.Background()
+	suite := NewSuite(ctx)
+	repo := NewFriendshipRepository(suite.db)
+
+	names := []string{"n0", "n1", "n2", "n3", "n4", "n5"}
+	friendships := make([]domain.Friendship, 0, len(names)-1)
+	for i := 0; i+1 < len(names); i++ {
+		friendships = append(friendships, domain.Friendship{UserID: names[i], FriendID: names[i+1], Status: domain.FriendshipStatusFriended})
+	}
+	graph := suite.prepareFriendGraph(t, ctx, names, friendships)
+
+	path, err := repo.GetShortestFriendPath(ctx, graph.users["n0"].ID, graph.users["n5"].ID, 5)
+	assert.NoError(t, err)
+	expected := make([]string, 0, len(names))
+	for _, name := range names {
+		expected = append(expected, graph.users[name].ID)
+	}
+	assert.Equal(t, expected, path)
+
+	_, err = repo.GetShortestFriendPath(ctx, graph.users["n0"].ID, graph.users["n5"].ID, 4)
+	assert.Equal(t, domain.ErrRecordNotFound, err)
+
+	path, err = repo.GetShortestFriendPath(ctx, graph.users["n2"].ID, graph.users["n2"].ID, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{graph.users["n2"].ID}, path)
+
+	suite.rollbackFriendGraph(t, ctx, graph)
+}
+
+func TestFriendship_GetShortestFriendPath_IgnoreBlockedEdges(t *testing.T) {
+	ctx := context.Background()
+	suite := NewSuite(ctx)
+	repo := NewFriendshipRepository(suite.db)
+	blockRepo := NewBlockRepository(suite.db)
+
+	// a - b - d is the short way, a - c - e - d the long one
+	graph := suite.prepareFriendGraph(t, ctx, []string{"a", "b", "c", "d", "e", "f"}, []domain.Friendship{
+		{UserID: "a", FriendID: "b", Status: domain.FriendshipStatusFriended},
+		{UserID: "b", FriendID: "d", Status: domain.FriendshipStatusFriended},
+		{UserID: "a", FriendID: "c", Status: domain.FriendshipStatusFriended},
+		{UserID: "c", FriendID: "e", Status: domain.FriendshipStatusFriended},
+		{UserID: "e", FriendID: "d", Status: domain.FriendshipStatusFriended},
+		{UserID: "a", FriendID: "f", Status: domain.FriendshipStatusBlocked},
+	})
+	ids := func(names ...string) []string {
+		result := make([]string, 0, len(names))
+		for _, name := range names {
+			result = append(result, graph.users[name].ID)
+		}
+		return result
+	}
+
+	path, err := repo.GetShortestFriendPath(ctx, graph.users["a"].ID, graph.users["d"].ID, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, ids("a", "b", "d"), path)
+
+	// a friend blocked by the other keeps the friended status but the edge is not crossed anymore
+	_, err = blockRepo.UpsertBlock(ctx, domain.Block{UserID: graph.users["d"].ID, TargetID: graph.users["b"].ID})
+	assert.NoError(t, err)
+
+	path, err = repo.GetShortestFriendPath(ctx, graph.users["a"].ID, graph.users["d"].ID, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, ids("a", "c", "e", "d"), path)
+
+	_, err = repo.GetShortestFriendPath(ctx, graph.users["a"].ID, graph.users["f"].ID, 3)
+	assert.Equal(t, domain.ErrRecordNotFound, err)
+
+	suite.rollbackFriendGraph(t, ctx, graph)
+}
+
+func TestFriendship_GetShortestFriendPath_RandomGraphs(t *testing.T) {
+	ctx := context.Background()
+	suite := NewSuite(ctx)
+	repo := NewFriendshipRepository(suite.db)
+
+	for seed := int64(1); seed <= 5; seed++ {
+		t.Run(fmt.Sprintf("seed %d", seed), func(t *testing.T) {
+			r := rand.New(rand.NewSource(seed))
+			names := make([]string, 12)
+			for i := range names {
+				names[i] = fmt.Sprintf("n%d", i)
+			}
+			adjacency := make(map[string][]string)
+			friendships := make([]domain.Friendship, 0)
+			for i := range names {
+				for j := i + 1; j < len(names); j++ {
+					if r.Float64() >= 0.2 {
+						continue
+					}
+					status := domain.FriendshipStatusFriended
+					if r.Float64() < 0.2 {
+						status = domain.FriendshipStatusBlocked
+					} else {
+						adjacency[names[i]] = append(adjacency[names[i]], names[j])
+						adjacency[names[j]] = append(adjacency[names[j]], names[i])
+					}
+					friendships = append(friendships, domain.Friendship{UserID: names[i], FriendID: names[j], Status: status})
+				}
+			}
+			graph := suite.prepareFriendGraph(t, ctx, names, friendships)
+			nameByID := make(map[string]string, len(names))
+			for name, u := range graph.users {
+				nameByID[u.ID] = name
+			}
+
+			for _, to := range names[1:] {
+				expected := shortestDistance(adjacency, names[0], to)
+				path, err := repo.GetShortestFriendPath(ctx, graph.users[names[0]].ID, graph.users[to].ID, 4)
+				if expected == -1 || expected > 4 {
+					assert.Equal(t, domain.ErrRecordNotFound, err, to)
+					continue
+				}
+				assert.NoError(t, err, to)
+				assert.Len(t, path, expected+1, to)
+				for i := 0; i+1 < len(path); i++ {
+					assert.Contains(t, adjacency[nameByID[path[i]]], nameByID[path[i+1]], to)
+				}
+			}
+
+			suite.rollbackFriendGraph(t, ctx, graph)
+		})
+	}
+}
+
+// shortestDistance is the reference breadth first search run on the generated graph, -1 when not connected
+func shortestDistance(adjacency map[string][]string, from, to string) int {
+	distances := map[string]int{from: 0}
+	queue := []string{from}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		if node == to {
+			return distances[node]
+		}
+		for _, next := range adjacency[node] {
+			if _, ok := distances[next]; !ok {
+				distances[next] = distances[node] + 1
+				queue = append(queue, next)
+			}
+		}
+	}
+	return -1
+}
+
+// friendGraph holds the users and the friendships created for a graph test
+type friendGraph struct {
+	users         map[string]model.User
+	friendshipIDs []string
+}
+
+// prepareFriendGraph creates a user per name and the friendships between them,
+// the UserID and FriendID of the given friendships are names
+func (g *Suite) prepareFriendGraph(t *testing.T, ctx context.Context, names []string, friendships []domain.Friendship) friendGraph {
+	graph := friendGraph{users: g.initialUsers(t, ctx, names)}
+	repo := NewFriendshipRepository(g.db)
+	for _, f := range friendships {
+		f.UserID = graph.users[f.UserID].ID
+		f.FriendID = graph.users[f.FriendID].ID
+		id, err := repo.Create(ctx, f)
+		assert.NoError(t, err)
+		graph.friendshipIDs = append(graph.friendshipIDs, id)
+	}
+	return graph
+}
+
+func (g *Suite) rollbackFriendGraph(t *testing.T, ctx context.Context, graph friendGraph) {
+	db := g.db.Model(ctx)
+	users := make(model.UserSlice, 0, len(graph.users))
+	for _, u := range graph.users {
+		_, err := model.NewQuery(qm.SQL("delete from blocks where user_id = $1 or target_id = $1", u.ID)).ExecContext(ctx, db)
+		assert.NoError(t, err)
+		users = append(users, &model.User{ID: u.ID})
+	}
+	for _, id := range graph.friendshipIDs {
+		_, err := (&model.Friendship{ID: id}).Delete(ctx, db)
+		assert.NoError(t, err)
+	}
+	_, err := users.DeleteAll(ctx, db)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/adapter/postgres"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/adapter/postgres/convert"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/adapter/postgres/model"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/adapter/postgres/view"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+)
+
+type IdempotencyRepository struct {
+	db postgres.Database
+}
+
+func NewIdempotencyRepository(db postgres.Database) IdempotencyRepository {
+	return IdempotencyRepository{
+		db: db,
+	}
+}
+
+// Reserve inserts the record or takes over an expired one, the record holding the key is read back otherwise.
+// A concurrent insert of the same key waits for the other one to commit, so a single request reserves the key
+func (i IdempotencyRepository) Reserve(ctx context.Context, d domain.IdempotencyRecord, expiredBefore, leaseExpiredBefore time.Time) (domain.IdempotencyRecord, bool, error) {
+	d.StatusCode = 0
+	d.ContentType = ""
+	d.Body = nil
+	// the reservation is matched by its created_at, postgres keeps microseconds
+	d.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+
+	var held []view.IdempotencyRecord
+	reserved := false
+	err := i.db.WithinTransaction(ctx, func(ctx context.Context) error {
+		result, err := model.NewQuery(
+			qm.SQL(`insert into idempotency_keys (scope, key, fingerprint, status_code, content_type, body, created_at) values ($1, $2, $3, 0, '', null, $4)
+			on conflict (scope, key) do update set fingerprint = excluded.fingerprint, status_code = 0, content_type = '', body = null, created_at = excluded.created_at
+			where idempotency_keys.created_at < $5 or (idempotency_keys.status_code = 0 and idempotency_keys.created_at < $6)`,
+				d.Scope, d.Key, d.Fingerprint, d.CreatedAt, expiredBefore, leaseExpiredBefore),
+		).ExecContext(ctx, i.db.Model(ctx))
+		if err != nil {
+			return common.ErrDB(err)
+		}
+		rowsAff, err := result.RowsAffected()
+		if err != nil {
+			return common.ErrDB(err)
+		}
+		if rowsAff > 0 {
+			reserved = true
+			return nil
+		}
+
+		err = model.NewQuery(
+			qm.SQL("select * from idempotency_keys where scope = $1 and key = $2", d.Scope, d.Key),
+		).Bind(ctx, i.db.Model(ctx), &held)
+		if err != nil {
+			return common.ErrDB(err)
+		}
+		if len(held) == 0 {
+			return domain.ErrRecordNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return domain.IdempotencyRecord{}, false, err
+	}
+	if reserved {
+		return d, true, nil
+	}
+	return convert.ToIdempotencyRecordDomain(held[0]), false, nil
+}
+
+func (i IdempotencyRepository) Complete(ctx context.Context, d domain.IdempotencyRecord) error {
+	result, err := model.NewQuery(
+		qm.SQL("update idempotency_keys set status_code = $1, content_type = $2, body = $3 where scope = $4 and key = $5 and created_at = $6",
+			d.StatusCode, d.ContentType, d.Body, d.Scope, d.Key, d.CreatedAt),
+	).ExecContext(ctx, i.db.Model(ctx))
+	if err != nil {
+		return common.ErrDB(err)
+	}
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return common.ErrDB(err)
+	}
+	if rowsAff == 0 {
+		return domain.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (i IdempotencyRepository) Release(ctx context.Context, d domain.IdempotencyRecord) error {
+	_, err := model.NewQuery(
+		qm.SQL("delete from idempotency_keys where scope = $1 and key = $2 and created_at = $3", d.Scope, d.Key, d.CreatedAt),
+	).ExecContext(ctx, i.db.Model(ctx))
+	if err != nil {
+		return common.ErrDB(err)
+	}
+	return nil
+}
+
+func (i IdempotencyRepository) DeleteExpired(ctx context.Context, expiredBefore time.Time) (int, error) {
+	result, err := model.NewQuery(
+		qm.SQL("delete from idempotency_keys where created_at < $1", expiredBefore),
+	).ExecContext(ctx, i.db.Model(ctx))
+	if err != nil {
+		return 0, common.ErrDB(err)
+	}
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, common.ErrDB(err)
+	}
+	return int(rowsAff), nil
+}
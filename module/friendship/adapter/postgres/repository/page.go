@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/adapter/postgres/view"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+// pageQuery wraps a query selecting email and created_at with the since filter, the cursor and the order of the page,
+// one more item than the limit is asked to know whether there is a next page
+func pageQuery(query string, args []interface{}, page domain.Page) (string, []interface{}, error) {
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	where := "true"
+	if !page.Since.IsZero() {
+		where += " and created_at >= " + arg(page.Since)
+	}
+	if page.Cursor != "" {
+		cursor, err := domain.DecodeCursor(page.Cursor)
+		if err != nil {
+			return "", nil, err
+		}
+		switch page.Sort {
+		case domain.PageSortCreatedAt:
+			where += fmt.Sprintf(" and (created_at, email) > (%s, %s)", arg(cursor.CreatedAt), arg(cursor.Key))
+		default:
+			where += " and email > " + arg(cursor.Key)
+		}
+	}
+
+	orderBy := "email"
+	if page.Sort == domain.PageSortCreatedAt {
+		orderBy = "created_at, email"
+	}
+
+	return fmt.Sprintf("select email, created_at from (%s) as page_query where %s order by %s limit %s",
+		query, where, orderBy, arg(page.Limit+1)), args, nil
+}
+
+// pageResult cuts the extra item asked by pageQuery and returns the cursor of the next page, empty on the last page
+func pageResult(list []view.PageEmail, page domain.Page) ([]string, string) {
+	var nextCursor string
+	if len(list) > page.Limit {
+		list = list[:page.Limit]
+		last := list[len(list)-1]
+		nextCursor = domain.EncodeCursor(domain.Cursor{Key: last.Email, CreatedAt: last.CreatedAt})
+	}
+
+	result := make([]string, 0, len(list))
+	for _, v := range list {
+		result = append(result, v.Email)
+	}
+	return result, nextCursor
+}
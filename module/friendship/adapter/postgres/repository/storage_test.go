@@ -0,0 +1,22 @@
+package repository_test
+
+import (
+	"log"
+	"testing"
+
+	"github.com/phantranhieunhan/s3-assignment/common/adapter/postgres"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/adapter/storagetest"
+	"github.com/phantranhieunhan/s3-assignment/pkg/config"
+)
+
+func TestStorage(t *testing.T) {
+	if err := config.ReadConfig(); err != nil {
+		log.Fatal(err)
+	}
+	db := postgres.NewDatabase()
+
+	storagetest.Run(t, func(t *testing.T) friendship.Storage {
+		return friendship.NewPostgresStorage(db)
+	})
+}
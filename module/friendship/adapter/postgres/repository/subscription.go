@@ -2,9 +2,8 @@ package repository
 
 import (
 	"context"
-	"fmt"
-	"strings"
 
+	"github.com/lib/pq"
 	"github.com/phantranhieunhan/s3-assignment/common"
 	"github.com/phantranhieunhan/s3-assignment/common/adapter/postgres"
 	"github.com/phantranhieunhan/s3-assignment/module/friendship/adapter/postgres/convert"
@@ -61,6 +60,14 @@ func (f SubscriptionRepository) UpsertSubscription(ctx context.Context, sub doma
 	return m.ID, nil
 }
 
+func (s SubscriptionRepository) Delete(ctx context.Context, id string) error {
+	m := model.Subscription{ID: id}
+	if _, err := m.Delete(ctx, s.db.Model(ctx)); err != nil {
+		return common.ErrDB(err)
+	}
+	return nil
+}
+
 func (s SubscriptionRepository) GetSubscription(ctx context.Context, ss domain.Subscriptions) (domain.Subscriptions, error) {
 	where := make([]qm.QueryMod, 0)
 	for _, v := range ss {
@@ -74,29 +81,65 @@ func (s SubscriptionRepository) GetSubscription(ctx context.Context, ss domain.S
 	return convert.ToSubscriptionsDomain(m), nil
 }
 
-func (s SubscriptionRepository) GetSubscriptionEmailsByUserIDAndEmails(ctx context.Context, id string, emails []string) ([]string, error) {
-	list := make([]view.SubscriberEmail, 0)
-	query := `select distinct * from (
-		select email from public.users u1 where id in (select subscriber_id from subscriptions where user_id = $1 and status = $2)
-		union
-		select email from public.users u2
-		where email = any('{%s}'::text[])
-		and id not in (select subscriber_id from subscriptions where user_id = $1 and status = $3)
-	) as sub_query`
-	iEmails := strings.Join(emails, ",")
-
-	queryWithEmails := fmt.Sprintf(query, iEmails)
-	err := model.NewQuery(
-		qm.SQL(queryWithEmails, id, domain.SubscriptionStatusSubscribed, domain.SubscriptionStatusUnsubscribed),
-	).Bind(ctx, s.db.Model(ctx), &list)
+// ListSubscriptionsByUserID lists the subscriptions to the user and of the user in any status,
+// ordered by id from the one after afterID
+func (s SubscriptionRepository) ListSubscriptionsByUserID(ctx context.Context, userID, afterID string, limit int) (domain.Subscriptions, error) {
+	m, err := model.Subscriptions(
+		qm.Where("(user_id = ? OR subscriber_id = ?) AND id > ?", userID, userID, afterID),
+		qm.OrderBy(model.SubscriptionColumns.ID),
+		qm.Limit(limit),
+	).All(ctx, s.db.Model(ctx))
+	if err != nil {
+		return domain.Subscriptions{}, common.ErrDB(err)
+	}
+	return convert.ToSubscriptionsDomain(m), nil
+}
+
+// DeleteByUserID deletes the subscriptions to the user and of the user
+func (s SubscriptionRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	_, err := model.Subscriptions(qm.Where("user_id = ? OR subscriber_id = ?", userID, userID)).DeleteAll(ctx, s.db.Model(ctx))
+	if err != nil {
+		return common.ErrDB(err)
+	}
+	return nil
+}
+
+// GetSubscriptionEmailsByUserIDAndEmails lists a page of the subscribers of the user and the mentioned users who did not unsubscribe,
+// a recipient is dated by its subscription or by its sign up when it is only mentioned
+func (s SubscriptionRepository) GetSubscriptionEmailsByUserIDAndEmails(ctx context.Context, id string, emails []string, page domain.Page) ([]string, string, error) {
+	page = page.WithDefaults()
+	query := `select u.email, coalesce(s.created_at, u.created_at) as created_at
+		from users u
+		left join subscriptions s on s.subscriber_id = u.id and s.user_id = $1
+		where s.status = $2
+		or (u.email = any($3::text[]) and (s.status is null or s.status <> $4))`
+
+	return s.getPageEmails(ctx, query, []interface{}{id, domain.SubscriptionStatusSubscribed, pq.Array(emails), domain.SubscriptionStatusUnsubscribed}, page)
+}
+
+// GetSubscriberEmails lists a page of the users subscribed to the user, dated by their subscription
+func (s SubscriptionRepository) GetSubscriberEmails(ctx context.Context, id string, page domain.Page) ([]string, string, error) {
+	page = page.WithDefaults()
+	query := `select u.email, s.created_at
+		from subscriptions s
+		inner join users u on u.id = s.subscriber_id
+		where s.user_id = $1 and s.status = $2`
+
+	return s.getPageEmails(ctx, query, []interface{}{id, domain.SubscriptionStatusSubscribed}, page)
+}
+
+func (s SubscriptionRepository) getPageEmails(ctx context.Context, query string, args []interface{}, page domain.Page) ([]string, string, error) {
+	query, args, err := pageQuery(query, args, page)
 	if err != nil {
-		return []string{}, common.ErrDB(err)
+		return []string{}, "", err
 	}
 
-	result := make([]string, len(list))
-	for i := range result {
-		result[i] = list[i].Email
+	list := make([]view.PageEmail, 0)
+	err = model.NewQuery(qm.SQL(query, args...)).Bind(ctx, s.db.Model(ctx), &list)
+	if err != nil {
+		return []string{}, "", common.ErrDB(err)
 	}
 
-	return result, nil
+	result, nextCursor := pageResult(list, page)
+	return result, nextCursor, nil
 }
@@ -184,6 +184,29 @@ func TestSubscription_UpsertSubscription(t *testing.T) {
 	}
 }
 
+func TestSubscription_Delete(t *testing.T) {
+	ctx := context.Background()
+	suite := NewSuite(ctx)
+	repo := NewSubscriptionRepository(suite.db)
+
+	sub := domain.Subscription{
+		UserID:       util.GenUUID(),
+		SubscriberID: util.GenUUID(),
+		Status:       domain.SubscriptionStatusSubscribed,
+	}
+	suite.prepareSubscription(t, ctx, sub)
+	var err error
+	sub.Id, err = repo.Create(ctx, sub)
+	assert.NoError(t, err)
+
+	assert.NoError(t, repo.Delete(ctx, sub.Id))
+	result, err := repo.GetSubscription(ctx, domain.Subscriptions{sub})
+	assert.NoError(t, err)
+	assert.Len(t, result, 0)
+
+	suite.rollbackSubscription(t, ctx, sub, []string{})
+}
+
 func TestGetSubscription(t *testing.T) {
 	ctx := context.Background()
 	suite := NewSuite(ctx)
@@ -312,7 +335,7 @@ func TestGetSubscriptionEmailsByUserIDAndStatus(t *testing.T) {
 				}
 			}
 
-			result, err := repo.GetSubscriptionEmailsByUserIDAndEmails(ctx, sub.UserID, mentionedEmail)
+			result, _, err := repo.GetSubscriptionEmailsByUserIDAndEmails(ctx, sub.UserID, mentionedEmail, domain.Page{})
 			assert.NoError(t, err)
 			if tc.isFounded {
 				assert.Equal(t, len(tc.result)+1, len(result))
@@ -331,6 +354,42 @@ func TestGetSubscriptionEmailsByUserIDAndStatus(t *testing.T) {
 	}
 }
 
+func TestSubscription_GetSubscriberEmails(t *testing.T) {
+	ctx := context.Background()
+	suite := NewSuite(ctx)
+	repo := NewSubscriptionRepository(suite.db)
+
+	graph := suite.prepareFriendGraph(t, ctx, []string{"owner", "s1", "s2", "s3", "s4"}, nil)
+	subIds := make([]string, 0)
+	for _, name := range []string{"s3", "s1", "s4", "s2"} {
+		status := domain.SubscriptionStatusSubscribed
+		if name == "s4" {
+			status = domain.SubscriptionStatusUnsubscribed
+		}
+		id, err := repo.Create(ctx, domain.Subscription{UserID: graph.users["owner"].ID, SubscriberID: graph.users[name].ID, Status: status})
+		assert.NoError(t, err)
+		subIds = append(subIds, id)
+	}
+
+	page := domain.Page{Limit: 2, Sort: domain.PageSortCreatedAt}
+	list, nextCursor, err := repo.GetSubscriberEmails(ctx, graph.users["owner"].ID, page)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{graph.users["s3"].Email, graph.users["s1"].Email}, list)
+	assert.NotEmpty(t, nextCursor)
+
+	page.Cursor = nextCursor
+	list, nextCursor, err = repo.GetSubscriberEmails(ctx, graph.users["owner"].ID, page)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{graph.users["s2"].Email}, list)
+	assert.Empty(t, nextCursor)
+
+	for _, id := range subIds {
+		_, err = (&model.Subscription{ID: id}).Delete(ctx, suite.db.Model(ctx))
+		assert.NoError(t, err)
+	}
+	suite.rollbackFriendGraph(t, ctx, graph)
+}
+
 func (g *Suite) prepareSubscription(t *testing.T, ctx context.Context, sub domain.Subscription) {
 	db := g.db.Model(ctx)
 	u := model.User{
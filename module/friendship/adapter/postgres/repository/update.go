@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/adapter/postgres"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/adapter/postgres/convert"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/adapter/postgres/model"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/adapter/postgres/view"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/phantranhieunhan/s3-assignment/pkg/util"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+)
+
+type UpdateRepository struct {
+	db postgres.Database
+}
+
+func NewUpdateRepository(db postgres.Database) UpdateRepository {
+	return UpdateRepository{
+		db: db,
+	}
+}
+
+func (u UpdateRepository) Create(ctx context.Context, d domain.Update) (string, error) {
+	d.Id = util.GenUUID()
+	mentions := d.Mentions
+	if mentions == nil {
+		mentions = []string{}
+	}
+
+	_, err := model.NewQuery(
+		qm.SQL(`insert into updates (id, user_id, text, mentions, created_at, updated_at) values ($1, $2, $3, $4, $5, $5)`,
+			d.Id, d.UserID, d.Text, pq.Array(mentions), d.CreatedAt),
+	).ExecContext(ctx, u.db.Model(ctx))
+	if err != nil {
+		return "", common.ErrDB(err)
+	}
+	return d.Id, nil
+}
+
+// GetFeed lists a page of the updates the user receives, the newest first.
+// The user receives the updates of the users it subscribes to and the updates mentioning it unless it unsubscribed from the sender,
+// the same rules as GetSubscriptionEmailsByUserIDAndEmails, and never the updates across a block
+func (u UpdateRepository) GetFeed(ctx context.Context, userID string, page domain.Page) ([]domain.FeedItem, string, error) {
+	page = page.WithDefaults()
+	args := []interface{}{userID, domain.SubscriptionStatusSubscribed, domain.SubscriptionStatusUnsubscribed}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	query := `select up.id, sender.email as sender, up.text, up.created_at
+		from updates up
+		inner join users sender on sender.id = up.user_id
+		inner join users me on me.id = $1
+		left join subscriptions s on s.user_id = up.user_id and s.subscriber_id = $1
+		where up.user_id <> $1
+		and (s.status = $2 or (me.email = any(up.mentions) and (s.status is null or s.status <> $3)))
+		and not exists (
+			select 1 from blocks b
+			where (b.user_id = up.user_id and b.target_id = $1) or (b.user_id = $1 and b.target_id = up.user_id)
+		)`
+	if !page.Since.IsZero() {
+		query += " and up.created_at >= " + arg(page.Since)
+	}
+	if page.Cursor != "" {
+		cursor, err := domain.DecodeCursor(page.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		query += fmt.Sprintf(" and (up.created_at, up.id) < (%s, %s)", arg(cursor.CreatedAt), arg(cursor.Key))
+	}
+	query += " order by up.created_at desc, up.id desc limit " + arg(page.Limit+1)
+
+	list := make([]view.FeedItem, 0)
+	err := model.NewQuery(qm.SQL(query, args...)).Bind(ctx, u.db.Model(ctx), &list)
+	if err != nil {
+		return nil, "", common.ErrDB(err)
+	}
+
+	var nextCursor string
+	if len(list) > page.Limit {
+		list = list[:page.Limit]
+		last := list[len(list)-1]
+		nextCursor = domain.EncodeCursor(domain.Cursor{Key: last.ID, CreatedAt: last.CreatedAt})
+	}
+
+	return convert.ToFeedItemsDomain(list), nextCursor, nil
+}
+
+// ListUpdatesByUserID lists the updates posted by the user, ordered by id from the one after afterID
+func (u UpdateRepository) ListUpdatesByUserID(ctx context.Context, userID, afterID string, limit int) ([]domain.Update, error) {
+	list := make([]view.Update, 0)
+	err := model.NewQuery(
+		qm.SQL("select id, user_id, text, mentions, created_at, updated_at from updates where user_id = $1 and id > $2 order by id limit $3", userID, afterID, limit),
+	).Bind(ctx, u.db.Model(ctx), &list)
+	if err != nil {
+		return []domain.Update{}, common.ErrDB(err)
+	}
+	return convert.ToUpdatesDomain(list), nil
+}
+
+func (u UpdateRepository) RemoveMentions(ctx context.Context, email string) error {
+	_, err := model.NewQuery(
+		qm.SQL("update updates set mentions = array_remove(mentions, $1) where $1 = any(mentions)", email),
+	).ExecContext(ctx, u.db.Model(ctx))
+	if err != nil {
+		return common.ErrDB(err)
+	}
+	return nil
+}
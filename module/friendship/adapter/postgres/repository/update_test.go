@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/adapter/postgres/model"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+)
+
+func TestUpdate_GetFeed(t *testing.T) {
+	ctx := context.Background()
+	suite := NewSuite(ctx)
+	repo := NewUpdateRepository(suite.db)
+	subRepo := NewSubscriptionRepository(suite.db)
+	blockRepo := NewBlockRepository(suite.db)
+
+	graph := suite.prepareFriendGraph(t, ctx, []string{"me", "sub", "mentioner", "unsub", "blocker", "other"}, nil)
+	me := graph.users["me"]
+	subIds := make([]string, 0)
+	for name, status := range map[string]domain.SubscriptionStatus{
+		"sub":     domain.SubscriptionStatusSubscribed,
+		"unsub":   domain.SubscriptionStatusUnsubscribed,
+		"blocker": domain.SubscriptionStatusSubscribed,
+	} {
+		id, err := subRepo.Create(ctx, domain.Subscription{UserID: graph.users[name].ID, SubscriberID: me.ID, Status: status})
+		assert.NoError(t, err)
+		subIds = append(subIds, id)
+	}
+	_, err := blockRepo.UpsertBlock(ctx, domain.Block{UserID: graph.users["blocker"].ID, TargetID: me.ID})
+	assert.NoError(t, err)
+
+	start := time.Now().UTC().Truncate(time.Millisecond)
+	post := func(name, text string, minute int) string {
+		id, err := repo.Create(ctx, domain.Update{
+			Base:     domain.Base{CreatedAt: start.Add(time.Duration(minute) * time.Minute)},
+			UserID:   graph.users[name].ID,
+			Text:     text,
+			Mentions: []string{me.Email},
+		})
+		assert.NoError(t, err)
+		return id
+	}
+	first := post("sub", "subscribed", 1)
+	mentioned := post("mentioner", "mentioned", 2)
+	post("unsub", "unsubscribed", 3)
+	post("blocker", "blocked", 4)
+	post("me", "mine", 5)
+	last := post("sub", "subscribed again", 6)
+	_, err = repo.Create(ctx, domain.Update{Base: domain.Base{CreatedAt: start}, UserID: graph.users["other"].ID, Text: "not mentioned"})
+	assert.NoError(t, err)
+
+	page := domain.Page{Limit: 2}
+	feed, nextCursor, err := repo.GetFeed(ctx, me.ID, page)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.FeedItem{
+		{ID: last, Sender: graph.users["sub"].Email, Text: "subscribed again", CreatedAt: start.Add(6 * time.Minute)},
+		{ID: mentioned, Sender: graph.users["mentioner"].Email, Text: "mentioned", CreatedAt: start.Add(2 * time.Minute)},
+	}, normalizeFeed(feed))
+	assert.NotEmpty(t, nextCursor)
+
+	page.Cursor = nextCursor
+	feed, nextCursor, err = repo.GetFeed(ctx, me.ID, page)
+	assert.NoError(t, err)
+	assert.Len(t, feed, 1)
+	assert.Equal(t, first, feed[0].ID)
+	assert.Empty(t, nextCursor)
+
+	feed, _, err = repo.GetFeed(ctx, me.ID, domain.Page{Since: start.Add(3 * time.Minute)})
+	assert.NoError(t, err)
+	assert.Len(t, feed, 1)
+	assert.Equal(t, last, feed[0].ID)
+
+	db := suite.db.Model(ctx)
+	for _, u := range graph.users {
+		_, err = model.NewQuery(qm.SQL("delete from updates where user_id = $1", u.ID)).ExecContext(ctx, db)
+		assert.NoError(t, err)
+	}
+	for _, id := range subIds {
+		_, err = (&model.Subscription{ID: id}).Delete(ctx, db)
+		assert.NoError(t, err)
+	}
+	suite.rollbackFriendGraph(t, ctx, graph)
+}
+
+// normalizeFeed keeps the feed comparable with the times built by the test
+func normalizeFeed(feed []domain.FeedItem) []domain.FeedItem {
+	for i := range feed {
+		feed[i].CreatedAt = feed[i].CreatedAt.UTC()
+	}
+	return feed
+}
@@ -2,13 +2,20 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"time"
 
 	"github.com/phantranhieunhan/s3-assignment/common"
 	"github.com/phantranhieunhan/s3-assignment/common/adapter/postgres"
 	"github.com/phantranhieunhan/s3-assignment/module/friendship/adapter/postgres/convert"
 	"github.com/phantranhieunhan/s3-assignment/module/friendship/adapter/postgres/model"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/adapter/postgres/view"
 	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
 	"github.com/phantranhieunhan/s3-assignment/pkg/util"
+
+	"github.com/lib/pq"
+	"github.com/volatiletech/sqlboiler/v4/boil"
 	qm "github.com/volatiletech/sqlboiler/v4/queries/qm"
 )
 
@@ -58,3 +65,152 @@ func (f UserRepository) GetEmailsByUserIDs(ctx context.Context, userIDs []string
 
 	return result, nil
 }
+
+// pgForeignKeyViolation is the postgres error code raised when a row is still referenced by another table
+const pgForeignKeyViolation = "23503"
+
+func (f UserRepository) Create(ctx context.Context, d domain.User) (string, error) {
+	d.Base.Id = util.GenUUID()
+	m := convert.ToUserModel(d)
+	if err := m.Insert(ctx, f.db.Model(ctx), boil.Infer()); err != nil {
+		return "", common.ErrDB(err)
+	}
+	if d.Password != "" {
+		// the password column is not part of the generated model
+		_, err := model.NewQuery(qm.SQL("update users set password = $1 where id = $2", d.Password, m.ID)).ExecContext(ctx, f.db.Model(ctx))
+		if err != nil {
+			return "", common.ErrDB(err)
+		}
+	}
+	return m.ID, nil
+}
+
+func (f UserRepository) GetPasswordHash(ctx context.Context, userID string) (string, error) {
+	var rows []struct {
+		Password string `boil:"password"`
+	}
+	err := model.NewQuery(qm.SQL("select password from users where id = $1", userID)).Bind(ctx, f.db.Model(ctx), &rows)
+	if err != nil {
+		return "", common.ErrDB(err)
+	}
+	if len(rows) == 0 {
+		return "", domain.ErrRecordNotFound
+	}
+	return rows[0].Password, nil
+}
+
+func (f UserRepository) SetPasswordHash(ctx context.Context, userID, hash string) error {
+	result, err := model.NewQuery(qm.SQL("update users set password = $1, updated_at = $2 where id = $3", hash, time.Now().UTC(), userID)).ExecContext(ctx, f.db.Model(ctx))
+	if err != nil {
+		return common.ErrDB(err)
+	}
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return common.ErrDB(err)
+	}
+	if rowsAff == 0 {
+		return domain.ErrUpdateRecordNotFound
+	}
+	return nil
+}
+
+func (f UserRepository) GetUserByEmail(ctx context.Context, email string) (domain.User, error) {
+	m, err := model.Users(qm.Where("email = ?", email)).One(ctx, f.db.Model(ctx))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.User{}, domain.ErrRecordNotFound
+		}
+		return domain.User{}, common.ErrDB(err)
+	}
+	return convert.ToUserDomain(*m), nil
+}
+
+func (f UserRepository) Update(ctx context.Context, d domain.User) error {
+	m := convert.ToUserModel(d)
+	rowsAff, err := m.Update(ctx, f.db.Model(ctx), boil.Whitelist(model.UserColumns.Email, model.UserColumns.UpdatedAt))
+	if err != nil {
+		return common.ErrDB(err)
+	}
+	if rowsAff == 0 {
+		return domain.ErrUpdateRecordNotFound
+	}
+	return nil
+}
+
+func (f UserRepository) Delete(ctx context.Context, id string) error {
+	m := model.User{ID: id}
+	rowsAff, err := m.Delete(ctx, f.db.Model(ctx))
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == pgForeignKeyViolation {
+			return domain.ErrUserHasRelations
+		}
+		return common.ErrDB(err)
+	}
+	if rowsAff == 0 {
+		return domain.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Anonymize replaces the email of the user with the tombstone, clears its password and drops its scheduled deletion
+func (f UserRepository) Anonymize(ctx context.Context, id, tombstone string) error {
+	res, err := model.NewQuery(
+		qm.SQL("update users set email = $1, password = '', updated_at = now() where id = $2", tombstone, id),
+	).ExecContext(ctx, f.db.Model(ctx))
+	if err != nil {
+		return common.ErrDB(err)
+	}
+	if rowsAff, err := res.RowsAffected(); err == nil && rowsAff == 0 {
+		return domain.ErrUpdateRecordNotFound
+	}
+	if _, err = model.NewQuery(qm.SQL("delete from user_deletions where user_id = $1", id)).ExecContext(ctx, f.db.Model(ctx)); err != nil {
+		return common.ErrDB(err)
+	}
+	return nil
+}
+
+func (f UserRepository) ScheduleDeletion(ctx context.Context, d domain.UserDeletion) error {
+	_, err := model.NewQuery(
+		qm.SQL("insert into user_deletions (user_id, requested_at, purge_at) values ($1, $2, $3)", d.UserID, d.RequestedAt, d.PurgeAt),
+	).ExecContext(ctx, f.db.Model(ctx))
+	if err != nil {
+		return common.ErrDB(err)
+	}
+	return nil
+}
+
+func (f UserRepository) GetDeletion(ctx context.Context, userID string) (domain.UserDeletion, error) {
+	list := make([]view.UserDeletion, 0)
+	err := model.NewQuery(qm.SQL("select * from user_deletions where user_id = $1", userID)).Bind(ctx, f.db.Model(ctx), &list)
+	if err != nil {
+		return domain.UserDeletion{}, common.ErrDB(err)
+	}
+	if len(list) == 0 {
+		return domain.UserDeletion{}, domain.ErrRecordNotFound
+	}
+	return convert.ToUserDeletionDomain(list[0]), nil
+}
+
+func (f UserRepository) CancelDeletion(ctx context.Context, userID string) error {
+	res, err := model.NewQuery(qm.SQL("delete from user_deletions where user_id = $1", userID)).ExecContext(ctx, f.db.Model(ctx))
+	if err != nil {
+		return common.ErrDB(err)
+	}
+	if rowsAff, err := res.RowsAffected(); err == nil && rowsAff == 0 {
+		return domain.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetDueDeletions lists the deletions whose grace period is over, the earliest first
+func (f UserRepository) GetDueDeletions(ctx context.Context, now time.Time, limit int) ([]domain.UserDeletion, error) {
+	list := make([]view.UserDeletion, 0)
+	err := model.NewQuery(
+		qm.SQL("select * from user_deletions where purge_at <= $1 order by purge_at, user_id limit $2", now, limit),
+	).Bind(ctx, f.db.Model(ctx), &list)
+	if err != nil {
+		return []domain.UserDeletion{}, common.ErrDB(err)
+	}
+	return convert.ToUserDeletionsDomain(list), nil
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/phantranhieunhan/s3-assignment/pkg/util"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -84,3 +85,85 @@ func TestGetUserIDsByEmails(t *testing.T) {
 
 	}
 }
+
+func TestUser_CreateGetUpdateDelete(t *testing.T) {
+	ctx := context.Background()
+	suite := NewSuite(ctx)
+	repo := NewUserRepository(suite.db)
+
+	email := util.GenUUID() + "@example.com"
+	id, err := repo.Create(ctx, domain.User{Email: email})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, id)
+
+	_, err = repo.Create(ctx, domain.User{Email: email})
+	assert.Error(t, err)
+
+	user, err := repo.GetUserByEmail(ctx, email)
+	assert.NoError(t, err)
+	assert.Equal(t, id, user.Base.Id)
+	assert.Equal(t, email, user.Email)
+
+	newEmail := util.GenUUID() + "@example.com"
+	user.Email = newEmail
+	err = repo.Update(ctx, user)
+	assert.NoError(t, err)
+
+	_, err = repo.GetUserByEmail(ctx, email)
+	assert.Equal(t, domain.ErrRecordNotFound, err)
+
+	user, err = repo.GetUserByEmail(ctx, newEmail)
+	assert.NoError(t, err)
+	assert.Equal(t, id, user.Base.Id)
+
+	err = repo.Update(ctx, domain.User{Base: domain.Base{Id: "fake-id"}, Email: email})
+	assert.Equal(t, domain.ErrUpdateRecordNotFound, err)
+
+	err = repo.Delete(ctx, id)
+	assert.NoError(t, err)
+
+	err = repo.Delete(ctx, id)
+	assert.Equal(t, domain.ErrRecordNotFound, err)
+}
+
+func TestUser_GetPasswordHash(t *testing.T) {
+	ctx := context.Background()
+	suite := NewSuite(ctx)
+	repo := NewUserRepository(suite.db)
+
+	id, err := repo.Create(ctx, domain.User{Email: util.GenUUID() + "@example.com", Password: "hashed-password"})
+	assert.NoError(t, err)
+
+	hashed, err := repo.GetPasswordHash(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, "hashed-password", hashed)
+
+	_, err = repo.GetPasswordHash(ctx, "fake-id")
+	assert.Equal(t, domain.ErrRecordNotFound, err)
+
+	err = repo.Delete(ctx, id)
+	assert.NoError(t, err)
+}
+
+func TestUser_DeleteWithRelations(t *testing.T) {
+	ctx := context.Background()
+	suite := NewSuite(ctx)
+	repo := NewUserRepository(suite.db)
+	friendshipRepo := NewFriendshipRepository(suite.db)
+
+	fri := domain.Friendship{
+		UserID:   util.GenUUID(),
+		FriendID: util.GenUUID(),
+		Status:   domain.FriendshipStatusFriended,
+	}
+	suite.prepareFriendship(t, ctx, fri)
+
+	var err error
+	fri.Id, err = friendshipRepo.Create(ctx, fri)
+	assert.NoError(t, err)
+
+	err = repo.Delete(ctx, fri.UserID)
+	assert.Equal(t, domain.ErrUserHasRelations, err)
+
+	suite.rollbackFriendship(t, ctx, fri)
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/adapter/postgres"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/adapter/postgres/convert"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/adapter/postgres/model"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/adapter/postgres/view"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/phantranhieunhan/s3-assignment/pkg/util"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+)
+
+type WebhookRepository struct {
+	db postgres.Database
+}
+
+func NewWebhookRepository(db postgres.Database) WebhookRepository {
+	return WebhookRepository{
+		db: db,
+	}
+}
+
+func (w WebhookRepository) Create(ctx context.Context, d domain.Webhook) (string, error) {
+	d.Id = util.GenUUID()
+	eventTypes := make([]string, 0, len(d.EventTypes))
+	for _, t := range d.EventTypes {
+		eventTypes = append(eventTypes, string(t))
+	}
+	_, err := model.NewQuery(
+		qm.SQL(`insert into webhooks (id, url, secret, event_types, created_at, updated_at) values ($1, $2, $3, $4, now(), now())`,
+			d.Id, d.URL, d.Secret, pq.Array(eventTypes)),
+	).ExecContext(ctx, w.db.Model(ctx))
+	if err != nil {
+		return "", common.ErrDB(err)
+	}
+	return d.Id, nil
+}
+
+func (w WebhookRepository) List(ctx context.Context) ([]domain.Webhook, error) {
+	return w.getWebhooks(ctx, "select * from webhooks order by created_at, id")
+}
+
+func (w WebhookRepository) GetByEventType(ctx context.Context, t domain.EventType) ([]domain.Webhook, error) {
+	return w.getWebhooks(ctx, "select * from webhooks where $1 = any(event_types) order by created_at, id", string(t))
+}
+
+func (w WebhookRepository) getWebhooks(ctx context.Context, query string, args ...interface{}) ([]domain.Webhook, error) {
+	list := make([]view.Webhook, 0)
+	if err := model.NewQuery(qm.SQL(query, args...)).Bind(ctx, w.db.Model(ctx), &list); err != nil {
+		return nil, common.ErrDB(err)
+	}
+	return convert.ToWebhooksDomain(list), nil
+}
+
+// Delete removes the webhook with its deliveries
+func (w WebhookRepository) Delete(ctx context.Context, id string) error {
+	result, err := model.NewQuery(qm.SQL("delete from webhooks where id = $1", id)).ExecContext(ctx, w.db.Model(ctx))
+	if err != nil {
+		return common.ErrDB(err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return common.ErrDB(err)
+	}
+	if n == 0 {
+		return domain.ErrRecordNotFound
+	}
+	return nil
+}
+
+// CreateDelivery ignores an event already queued for the webhook, the outbox relays an event at least once
+func (w WebhookRepository) CreateDelivery(ctx context.Context, d domain.WebhookDelivery) (string, error) {
+	d.Id = util.GenUUID()
+	_, err := model.NewQuery(
+		qm.SQL(`insert into webhook_deliveries (id, webhook_id, event_id, event_type, payload, status, attempts, last_error, next_attempt_at, created_at, updated_at)
+			values ($1, $2, $3, $4, $5, $6, $7, $8, $9, now(), now())
+			on conflict (webhook_id, event_id) do nothing`,
+			d.Id, d.WebhookID, d.EventID, string(d.EventType), []byte(d.Payload), string(d.Status), d.Attempts, d.LastError, d.NextAttemptAt),
+	).ExecContext(ctx, w.db.Model(ctx))
+	if err != nil {
+		return "", common.ErrDB(err)
+	}
+	return d.Id, nil
+}
+
+func (w WebhookRepository) GetDelivery(ctx context.Context, id string) (domain.WebhookDelivery, error) {
+	list := make([]view.WebhookDelivery, 0)
+	err := model.NewQuery(
+		qm.SQL("select * from webhook_deliveries where id = $1", id),
+	).Bind(ctx, w.db.Model(ctx), &list)
+	if err != nil {
+		return domain.WebhookDelivery{}, common.ErrDB(err)
+	}
+	if len(list) == 0 {
+		return domain.WebhookDelivery{}, domain.ErrRecordNotFound
+	}
+	return convert.ToWebhookDeliveryDomain(list[0]), nil
+}
+
+// GetDueDeliveries skips the deliveries locked by another worker so that several instances can deliver at the same time
+func (w WebhookRepository) GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]domain.DueWebhookDelivery, error) {
+	list := make([]view.DueWebhookDelivery, 0)
+	err := model.NewQuery(
+		qm.SQL(`select d.*, w.url, w.secret from webhook_deliveries d
+			inner join webhooks w on w.id = d.webhook_id
+			where d.status = $1 and d.next_attempt_at <= $2
+			order by d.next_attempt_at, d.created_at
+			limit $3
+			for update of d skip locked`, string(domain.WebhookDeliveryStatusPending), now, limit),
+	).Bind(ctx, w.db.Model(ctx), &list)
+	if err != nil {
+		return nil, common.ErrDB(err)
+	}
+	return convert.ToDueWebhookDeliveriesDomain(list), nil
+}
+
+func (w WebhookRepository) UpdateDelivery(ctx context.Context, d domain.WebhookDelivery) error {
+	_, err := model.NewQuery(
+		qm.SQL(`update webhook_deliveries set status = $2, attempts = $3, last_error = $4, next_attempt_at = $5, updated_at = now() where id = $1`,
+			d.Id, string(d.Status), d.Attempts, d.LastError, d.NextAttemptAt),
+	).ExecContext(ctx, w.db.Model(ctx))
+	if err != nil {
+		return common.ErrDB(err)
+	}
+	return nil
+}
+
+func (w WebhookRepository) GetDeliveriesByStatus(ctx context.Context, webhookID string, status domain.WebhookDeliveryStatus) ([]domain.WebhookDelivery, error) {
+	list := make([]view.WebhookDelivery, 0)
+	err := model.NewQuery(
+		qm.SQL("select * from webhook_deliveries where webhook_id = $1 and status = $2 order by created_at desc, id", webhookID, string(status)),
+	).Bind(ctx, w.db.Model(ctx), &list)
+	if err != nil {
+		return nil, common.ErrDB(err)
+	}
+	return convert.ToWebhookDeliveriesDomain(list), nil
+}
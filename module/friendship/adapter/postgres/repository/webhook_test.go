@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhook_CreateListDelete(t *testing.T) {
+	ctx := context.Background()
+	suite := NewSuite(ctx)
+	repo := NewWebhookRepository(suite.db)
+
+	id, err := repo.Create(ctx, domain.Webhook{
+		URL:        "https://partner.example.com/hook",
+		Secret:     "a-secret-of-16-chars",
+		EventTypes: []domain.EventType{domain.EventUserBlocked, domain.EventUserSubscribed},
+	})
+	assert.NoError(t, err)
+
+	webhooks, err := repo.GetByEventType(ctx, domain.EventUserSubscribed)
+	assert.NoError(t, err)
+	found := false
+	for _, w := range webhooks {
+		if w.Base.Id == id {
+			found = true
+			assert.Equal(t, "https://partner.example.com/hook", w.URL)
+			assert.Equal(t, "a-secret-of-16-chars", w.Secret)
+			assert.Equal(t, []domain.EventType{domain.EventUserBlocked, domain.EventUserSubscribed}, w.EventTypes)
+		}
+	}
+	assert.True(t, found)
+
+	webhooks, err = repo.GetByEventType(ctx, domain.EventUserDeleted)
+	assert.NoError(t, err)
+	for _, w := range webhooks {
+		assert.NotEqual(t, id, w.Base.Id)
+	}
+
+	webhooks, err = repo.List(ctx)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, webhooks)
+
+	assert.NoError(t, repo.Delete(ctx, id))
+	assert.Equal(t, domain.ErrRecordNotFound, repo.Delete(ctx, id))
+}
+
+func TestWebhook_Deliveries(t *testing.T) {
+	ctx := context.Background()
+	suite := NewSuite(ctx)
+	repo := NewWebhookRepository(suite.db)
+
+	webhookID, err := repo.Create(ctx, domain.Webhook{
+		URL:        "https://partner.example.com/hook",
+		Secret:     "a-secret-of-16-chars",
+		EventTypes: []domain.EventType{domain.EventUserBlocked},
+	})
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, repo.Delete(ctx, webhookID))
+	}()
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	delivery := domain.WebhookDelivery{
+		WebhookID:     webhookID,
+		EventID:       "event-id",
+		EventType:     domain.EventUserBlocked,
+		Payload:       json.RawMessage(`{"user_id":"user-1","target_id":"user-2"}`),
+		Status:        domain.WebhookDeliveryStatusPending,
+		NextAttemptAt: now,
+	}
+	id, err := repo.CreateDelivery(ctx, delivery)
+	assert.NoError(t, err)
+	// the same event is queued once per webhook
+	_, err = repo.CreateDelivery(ctx, delivery)
+	assert.NoError(t, err)
+
+	err = suite.db.WithinTransaction(ctx, func(ctx context.Context) error {
+		due, err := repo.GetDueDeliveries(ctx, now.Add(time.Second), 1000)
+		assert.NoError(t, err)
+		count := 0
+		for _, d := range due {
+			if d.Delivery.WebhookID == webhookID {
+				count++
+				assert.Equal(t, id, d.Delivery.Base.Id)
+				assert.Equal(t, "https://partner.example.com/hook", d.Webhook.URL)
+				assert.Equal(t, "a-secret-of-16-chars", d.Webhook.Secret)
+				assert.JSONEq(t, string(delivery.Payload), string(d.Delivery.Payload))
+			}
+		}
+		assert.Equal(t, 1, count)
+
+		d := due[0].Delivery
+		for _, dd := range due {
+			if dd.Delivery.Base.Id == id {
+				d = dd.Delivery
+			}
+		}
+		d.Status = domain.WebhookDeliveryStatusDead
+		d.Attempts = 8
+		d.LastError = "webhook responded with status 500"
+		return repo.UpdateDelivery(ctx, d)
+	})
+	assert.NoError(t, err)
+
+	dead, err := repo.GetDeliveriesByStatus(ctx, webhookID, domain.WebhookDeliveryStatusDead)
+	assert.NoError(t, err)
+	assert.Len(t, dead, 1)
+	assert.Equal(t, 8, dead[0].Attempts)
+	assert.Equal(t, "webhook responded with status 500", dead[0].LastError)
+
+	got, err := repo.GetDelivery(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.WebhookDeliveryStatusDead, got.Status)
+
+	_, err = repo.GetDelivery(ctx, "unknown-id")
+	assert.Equal(t, domain.ErrRecordNotFound, err)
+}
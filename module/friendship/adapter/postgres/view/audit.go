@@ -0,0 +1,18 @@
+package view
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type AuditEntry struct {
+	ID           string         `boil:"id"`
+	Actor        string         `boil:"actor"`
+	Action       string         `boil:"action"`
+	TargetIDs    pq.StringArray `boil:"target_ids"`
+	StatusBefore string         `boil:"status_before"`
+	StatusAfter  string         `boil:"status_after"`
+	RequestID    string         `boil:"request_id"`
+	CreatedAt    time.Time      `boil:"created_at"`
+}
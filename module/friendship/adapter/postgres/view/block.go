@@ -0,0 +1,18 @@
+package view
+
+import "time"
+
+type Block struct {
+	ID                 string    `boil:"id"`
+	UserID             string    `boil:"user_id"`
+	TargetID           string    `boil:"target_id"`
+	FriendshipStatus   int       `boil:"friendship_status"`
+	SubscriptionStatus int       `boil:"subscription_status"`
+	CreatedAt          time.Time `boil:"created_at"`
+	UpdatedAt          time.Time `boil:"updated_at"`
+}
+
+type BlockedEmail struct {
+	Email     string    `boil:"email"`
+	BlockedAt time.Time `boil:"blocked_at"`
+}
@@ -0,0 +1,11 @@
+package view
+
+import "time"
+
+type Event struct {
+	ID          string    `boil:"id"`
+	EventType   string    `boil:"event_type"`
+	AggregateID string    `boil:"aggregate_id"`
+	Payload     []byte    `boil:"payload"`
+	CreatedAt   time.Time `boil:"created_at"`
+}
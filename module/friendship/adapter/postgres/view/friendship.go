@@ -3,4 +3,18 @@ package view
 type Email struct {
 	UserEmail   string `json:"user_email" boil:"user_email"`
 	FriendEmail string `json:"friend_email" boil:"friend_email"`
-}
\ No newline at end of file
+}
+type FriendSuggestion struct {
+	Email       string `boil:"email"`
+	MutualCount int    `boil:"mutual_count"`
+}
+
+type FriendEdge struct {
+	UserID   string `boil:"user_id"`
+	FriendID string `boil:"friend_id"`
+}
+
+type FriendSetMember struct {
+	Email          string `boil:"email"`
+	ConnectedUsers int    `boil:"connected_users"`
+}
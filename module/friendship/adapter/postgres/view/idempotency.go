@@ -0,0 +1,13 @@
+package view
+
+import "time"
+
+type IdempotencyRecord struct {
+	Scope       string    `boil:"scope"`
+	Key         string    `boil:"key"`
+	Fingerprint string    `boil:"fingerprint"`
+	StatusCode  int       `boil:"status_code"`
+	ContentType string    `boil:"content_type"`
+	Body        []byte    `boil:"body"`
+	CreatedAt   time.Time `boil:"created_at"`
+}
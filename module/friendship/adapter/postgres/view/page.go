@@ -0,0 +1,8 @@
+package view
+
+import "time"
+
+type PageEmail struct {
+	Email     string    `boil:"email"`
+	CreatedAt time.Time `boil:"created_at"`
+}
@@ -1,5 +0,0 @@
-package view
-
-type SubscriberEmail struct {
-	Email string `boil:"email"`
-}
@@ -0,0 +1,23 @@
+package view
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type Update struct {
+	ID        string         `boil:"id"`
+	UserID    string         `boil:"user_id"`
+	Text      string         `boil:"text"`
+	Mentions  pq.StringArray `boil:"mentions"`
+	CreatedAt time.Time      `boil:"created_at"`
+	UpdatedAt time.Time      `boil:"updated_at"`
+}
+
+type FeedItem struct {
+	ID        string    `boil:"id"`
+	Sender    string    `boil:"sender"`
+	Text      string    `boil:"text"`
+	CreatedAt time.Time `boil:"created_at"`
+}
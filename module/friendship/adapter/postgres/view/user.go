@@ -0,0 +1,9 @@
+package view
+
+import "time"
+
+type UserDeletion struct {
+	UserID      string    `boil:"user_id"`
+	RequestedAt time.Time `boil:"requested_at"`
+	PurgeAt     time.Time `boil:"purge_at"`
+}
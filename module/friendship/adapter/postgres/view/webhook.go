@@ -0,0 +1,37 @@
+package view
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type Webhook struct {
+	ID         string         `boil:"id"`
+	URL        string         `boil:"url"`
+	Secret     string         `boil:"secret"`
+	EventTypes pq.StringArray `boil:"event_types"`
+	CreatedAt  time.Time      `boil:"created_at"`
+	UpdatedAt  time.Time      `boil:"updated_at"`
+}
+
+type WebhookDelivery struct {
+	ID            string    `boil:"id"`
+	WebhookID     string    `boil:"webhook_id"`
+	EventID       string    `boil:"event_id"`
+	EventType     string    `boil:"event_type"`
+	Payload       []byte    `boil:"payload"`
+	Status        string    `boil:"status"`
+	Attempts      int       `boil:"attempts"`
+	LastError     string    `boil:"last_error"`
+	NextAttemptAt time.Time `boil:"next_attempt_at"`
+	CreatedAt     time.Time `boil:"created_at"`
+	UpdatedAt     time.Time `boil:"updated_at"`
+}
+
+// DueWebhookDelivery is a delivery joined with the url and secret of its webhook
+type DueWebhookDelivery struct {
+	WebhookDelivery `boil:",bind"`
+	URL             string `boil:"url"`
+	Secret          string `boil:"secret"`
+}
@@ -0,0 +1,20 @@
+package publisher
+
+import (
+	"context"
+
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+// LogPublisher writes the events to the log, it is the default publisher until a broker is plugged in
+type LogPublisher struct{}
+
+func NewLogPublisher() LogPublisher {
+	return LogPublisher{}
+}
+
+func (p LogPublisher) Publish(ctx context.Context, e domain.Event) error {
+	logger.Infow("event published", "id", e.ID, "type", e.Type, "aggregate_id", e.AggregateID, "payload", string(e.Payload))
+	return nil
+}
@@ -0,0 +1,27 @@
+package publisher
+
+import (
+	"context"
+
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+// MultiPublisher publishes every event to each publisher in turn, it fails at the first publisher failing
+type MultiPublisher struct {
+	publishers []domain.EventPublisher
+}
+
+func NewMultiPublisher(publishers ...domain.EventPublisher) MultiPublisher {
+	return MultiPublisher{
+		publishers: publishers,
+	}
+}
+
+func (p MultiPublisher) Publish(ctx context.Context, e domain.Event) error {
+	for _, pub := range p.publishers {
+		if err := pub.Publish(ctx, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
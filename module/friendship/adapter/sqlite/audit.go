@@ -0,0 +1,122 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	sqlitedb "github.com/phantranhieunhan/s3-assignment/common/adapter/sqlite"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/phantranhieunhan/s3-assignment/pkg/util"
+)
+
+type AuditRepository struct {
+	db sqlitedb.Database
+}
+
+func NewAuditRepository(db sqlitedb.Database) AuditRepository {
+	return AuditRepository{
+		db: db,
+	}
+}
+
+// Create stores the target ids of the entry in audit_log_targets, sqlite has no arrays,
+// the triggers of both tables reject the updates and the deletes
+func (a AuditRepository) Create(ctx context.Context, d domain.AuditEntry) (string, error) {
+	d.ID = util.GenUUID()
+	err := a.db.WithinTransaction(ctx, func(ctx context.Context) error {
+		_, err := a.db.Model(ctx).ExecContext(ctx,
+			"insert into audit_log (id, actor, action, status_before, status_after, request_id, created_at) values (?, ?, ?, ?, ?, ?, ?)",
+			d.ID, d.Actor, string(d.Action), string(d.StatusBefore), string(d.StatusAfter), d.RequestID, toTime(d.CreatedAt))
+		if err != nil {
+			return common.ErrDB(err)
+		}
+		for i, targetID := range d.TargetIDs {
+			_, err = a.db.Model(ctx).ExecContext(ctx,
+				"insert into audit_log_targets (audit_id, position, target_id) values (?, ?, ?)", d.ID, i, targetID)
+			if err != nil {
+				return common.ErrDB(err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return d.ID, nil
+}
+
+func (a AuditRepository) List(ctx context.Context, filter domain.AuditFilter, page domain.Page) ([]domain.AuditEntry, string, error) {
+	page = page.WithDefaults()
+	args := make([]interface{}, 0)
+
+	query := `select id, actor, action, status_before, status_after, request_id, created_at from audit_log a where true`
+	if filter.UserID != "" {
+		query += " and (a.actor = ? or exists (select 1 from audit_log_targets t where t.audit_id = a.id and t.target_id = ?))"
+		args = append(args, filter.UserID, filter.UserID)
+	}
+	if filter.Action != "" {
+		query += " and a.action = ?"
+		args = append(args, string(filter.Action))
+	}
+	if !filter.From.IsZero() {
+		query += " and a.created_at >= ?"
+		args = append(args, toTime(filter.From))
+	}
+	if !filter.To.IsZero() {
+		query += " and a.created_at < ?"
+		args = append(args, toTime(filter.To))
+	}
+	if page.Cursor != "" {
+		cursor, err := domain.DecodeCursor(page.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		query += " and (a.created_at, a.id) < (?, ?)"
+		args = append(args, toTime(cursor.CreatedAt), cursor.Key)
+	}
+	query += " order by a.created_at desc, a.id desc limit ?"
+	args = append(args, page.Limit+1)
+
+	list := make([]domain.AuditEntry, 0)
+	index := make(map[string]int)
+	err := queryRows(ctx, a.db.Model(ctx), query, args, func(rows *sql.Rows) error {
+		var v domain.AuditEntry
+		if err := rows.Scan(&v.ID, &v.Actor, &v.Action, &v.StatusBefore, &v.StatusAfter, &v.RequestID, scanTime{&v.CreatedAt}); err != nil {
+			return err
+		}
+		v.TargetIDs = make([]string, 0)
+		list = append(list, v)
+		return nil
+	})
+	if err != nil {
+		return nil, "", common.ErrDB(err)
+	}
+
+	var nextCursor string
+	if len(list) > page.Limit {
+		list = list[:page.Limit]
+		last := list[len(list)-1]
+		nextCursor = domain.EncodeCursor(domain.Cursor{Key: last.ID, CreatedAt: last.CreatedAt})
+	}
+
+	ids := make([]string, 0, len(list))
+	for i, v := range list {
+		index[v.ID] = i
+		ids = append(ids, v.ID)
+	}
+	err = queryRows(ctx, a.db.Model(ctx),
+		"select audit_id, target_id from audit_log_targets where "+inClause("audit_id", len(ids))+" order by audit_id, position",
+		stringArgs(ids), func(rows *sql.Rows) error {
+			var id, targetID string
+			if err := rows.Scan(&id, &targetID); err != nil {
+				return err
+			}
+			list[index[id]].TargetIDs = append(list[index[id]].TargetIDs, targetID)
+			return nil
+		})
+	if err != nil {
+		return nil, "", common.ErrDB(err)
+	}
+	return list, nextCursor, nil
+}
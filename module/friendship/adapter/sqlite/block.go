@@ -0,0 +1,119 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	sqlitedb "github.com/phantranhieunhan/s3-assignment/common/adapter/sqlite"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/phantranhieunhan/s3-assignment/pkg/util"
+)
+
+type BlockRepository struct {
+	db sqlitedb.Database
+}
+
+func NewBlockRepository(db sqlitedb.Database) BlockRepository {
+	return BlockRepository{
+		db: db,
+	}
+}
+
+func (b BlockRepository) UpsertBlock(ctx context.Context, d domain.Block) (string, error) {
+	now := toTime(time.Now())
+	var id string
+	err := b.db.Model(ctx).QueryRowContext(ctx,
+		`insert into blocks (id, user_id, target_id, friendship_status, subscription_status, created_at, updated_at)
+		values (?, ?, ?, ?, ?, ?, ?)
+		on conflict (user_id, target_id) do update
+		set friendship_status = excluded.friendship_status, subscription_status = excluded.subscription_status, updated_at = excluded.updated_at
+		returning id`,
+		util.GenUUID(), d.UserID, d.TargetID, d.FriendshipStatus, d.SubscriptionStatus, now, now).Scan(&id)
+	if err != nil {
+		return "", common.ErrDB(err)
+	}
+	return id, nil
+}
+
+func (b BlockRepository) GetBlock(ctx context.Context, userID, targetID string) (domain.Block, error) {
+	var d domain.Block
+	err := b.db.Model(ctx).QueryRowContext(ctx,
+		`select id, user_id, target_id, friendship_status, subscription_status, created_at, updated_at from blocks
+		where user_id = ? and target_id = ?`, userID, targetID).
+		Scan(&d.Id, &d.UserID, &d.TargetID, &d.FriendshipStatus, &d.SubscriptionStatus, scanTime{&d.CreatedAt}, scanTime{&d.UpdatedAt})
+	if err == sql.ErrNoRows {
+		return domain.Block{}, domain.ErrRecordNotFound
+	}
+	if err != nil {
+		return domain.Block{}, common.ErrDB(err)
+	}
+	return d, nil
+}
+
+func (b BlockRepository) Delete(ctx context.Context, id string) error {
+	if _, err := b.db.Model(ctx).ExecContext(ctx, "delete from blocks where id = ?", id); err != nil {
+		return common.ErrDB(err)
+	}
+	return nil
+}
+
+// DeleteByUserID deletes the blocks made by the user and against the user
+func (b BlockRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	if _, err := b.db.Model(ctx).ExecContext(ctx, "delete from blocks where user_id = ? or target_id = ?", userID, userID); err != nil {
+		return common.ErrDB(err)
+	}
+	return nil
+}
+
+func (b BlockRepository) GetBlockedEmailsByUserID(ctx context.Context, userID string) ([]domain.BlockedEmail, error) {
+	return b.getBlockedEmails(ctx, `select u.email, b.updated_at as blocked_at from blocks b
+		inner join users u on u.id = b.target_id
+		where b.user_id = ?
+		order by b.updated_at desc`, userID)
+}
+
+func (b BlockRepository) GetBlockerEmailsByTargetID(ctx context.Context, targetID string) ([]domain.BlockedEmail, error) {
+	return b.getBlockedEmails(ctx, `select u.email, b.updated_at as blocked_at from blocks b
+		inner join users u on u.id = b.user_id
+		where b.target_id = ?
+		order by b.updated_at desc`, targetID)
+}
+
+// ListBlocksByUserID lists the blocks made by the user, ordered by id from the one after afterID
+func (b BlockRepository) ListBlocksByUserID(ctx context.Context, userID, afterID string, limit int) ([]domain.Block, error) {
+	result := make([]domain.Block, 0)
+	err := queryRows(ctx, b.db.Model(ctx),
+		`select id, user_id, target_id, friendship_status, subscription_status, created_at, updated_at from blocks
+		where user_id = ? and id > ?
+		order by id limit ?`, []interface{}{userID, afterID, limit},
+		func(rows *sql.Rows) error {
+			var d domain.Block
+			if err := rows.Scan(&d.Id, &d.UserID, &d.TargetID, &d.FriendshipStatus, &d.SubscriptionStatus, scanTime{&d.CreatedAt}, scanTime{&d.UpdatedAt}); err != nil {
+				return err
+			}
+			result = append(result, d)
+			return nil
+		})
+	if err != nil {
+		return []domain.Block{}, common.ErrDB(err)
+	}
+	return result, nil
+}
+
+func (b BlockRepository) getBlockedEmails(ctx context.Context, query string, id string) ([]domain.BlockedEmail, error) {
+	result := make([]domain.BlockedEmail, 0)
+	err := queryRows(ctx, b.db.Model(ctx), query, []interface{}{id}, func(rows *sql.Rows) error {
+		var v domain.BlockedEmail
+		if err := rows.Scan(&v.Email, scanTime{&v.BlockedAt}); err != nil {
+			return err
+		}
+		result = append(result, v)
+		return nil
+	})
+	if err != nil {
+		return []domain.BlockedEmail{}, common.ErrDB(err)
+	}
+	return result, nil
+}
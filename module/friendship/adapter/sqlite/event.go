@@ -0,0 +1,68 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	sqlitedb "github.com/phantranhieunhan/s3-assignment/common/adapter/sqlite"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/phantranhieunhan/s3-assignment/pkg/util"
+)
+
+type EventRepository struct {
+	db sqlitedb.Database
+}
+
+func NewEventRepository(db sqlitedb.Database) EventRepository {
+	return EventRepository{
+		db: db,
+	}
+}
+
+func (e EventRepository) Create(ctx context.Context, d domain.Event) (string, error) {
+	d.ID = util.GenUUID()
+	_, err := e.db.Model(ctx).ExecContext(ctx,
+		"insert into outbox_events (id, event_type, aggregate_id, payload, created_at) values (?, ?, ?, ?, ?)",
+		d.ID, string(d.Type), d.AggregateID, string(d.Payload), toTime(d.CreatedAt))
+	if err != nil {
+		return "", common.ErrDB(err)
+	}
+	return d.ID, nil
+}
+
+// GetUndelivered needs no row lock, the single connection of the database runs one transaction at a time
+func (e EventRepository) GetUndelivered(ctx context.Context, limit int) ([]domain.Event, error) {
+	result := make([]domain.Event, 0)
+	err := queryRows(ctx, e.db.Model(ctx),
+		`select id, event_type, aggregate_id, payload, created_at from outbox_events
+		where delivered_at is null
+		order by seq
+		limit ?`, []interface{}{limit},
+		func(rows *sql.Rows) error {
+			var (
+				v       domain.Event
+				payload string
+			)
+			if err := rows.Scan(&v.ID, &v.Type, &v.AggregateID, &payload, scanTime{&v.CreatedAt}); err != nil {
+				return err
+			}
+			v.Payload = []byte(payload)
+			result = append(result, v)
+			return nil
+		})
+	if err != nil {
+		return nil, common.ErrDB(err)
+	}
+	return result, nil
+}
+
+func (e EventRepository) MarkDelivered(ctx context.Context, ids []string) error {
+	args := append([]interface{}{toTime(time.Now())}, stringArgs(ids)...)
+	_, err := e.db.Model(ctx).ExecContext(ctx, "update outbox_events set delivered_at = ? where "+inClause("id", len(ids)), args...)
+	if err != nil {
+		return common.ErrDB(err)
+	}
+	return nil
+}
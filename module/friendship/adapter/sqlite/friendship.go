@@ -0,0 +1,349 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	sqlitedb "github.com/phantranhieunhan/s3-assignment/common/adapter/sqlite"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/phantranhieunhan/s3-assignment/pkg/util"
+)
+
+type FriendshipRepository struct {
+	db sqlitedb.Database
+}
+
+func NewFriendshipRepository(db sqlitedb.Database) FriendshipRepository {
+	return FriendshipRepository{
+		db: db,
+	}
+}
+
+func (f FriendshipRepository) Create(ctx context.Context, d domain.Friendship) (string, error) {
+	d.Id = util.GenUUID()
+	now := time.Now().UTC()
+	if d.CreatedAt.IsZero() {
+		d.CreatedAt = now
+	}
+	if d.UpdatedAt.IsZero() {
+		d.UpdatedAt = now
+	}
+
+	_, err := f.db.Model(ctx).ExecContext(ctx,
+		"insert into friendships (id, user_id, friend_id, status, created_at, updated_at) values (?, ?, ?, ?, ?, ?)",
+		d.Id, d.UserID, d.FriendID, d.Status, toTime(d.CreatedAt), toTime(d.UpdatedAt))
+	if err != nil {
+		return "", common.ErrDB(err)
+	}
+	return d.Id, nil
+}
+
+func (f FriendshipRepository) UpdateStatus(ctx context.Context, id string, status domain.FriendshipStatus) error {
+	_, err := f.db.Model(ctx).ExecContext(ctx, "update friendships set status = ?, updated_at = ? where id = ?", status, toTime(time.Now()), id)
+	if err != nil {
+		return common.ErrDB(err)
+	}
+	return nil
+}
+
+func (f FriendshipRepository) Update(ctx context.Context, d domain.Friendship) error {
+	_, err := f.db.Model(ctx).ExecContext(ctx,
+		"update friendships set user_id = ?, friend_id = ?, status = ?, updated_at = ? where id = ?",
+		d.UserID, d.FriendID, d.Status, toTime(time.Now()), d.Id)
+	if err != nil {
+		return common.ErrDB(err)
+	}
+	return nil
+}
+
+func (f FriendshipRepository) GetFriendshipByUserIDs(ctx context.Context, userID, friendID string) (domain.Friendship, error) {
+	var d domain.Friendship
+	err := f.db.Model(ctx).QueryRowContext(ctx,
+		`select id, user_id, friend_id, status, created_at, updated_at from friendships
+		where (user_id = ? and friend_id = ?) or (user_id = ? and friend_id = ?)`, userID, friendID, friendID, userID).
+		Scan(&d.Id, &d.UserID, &d.FriendID, &d.Status, scanTime{&d.CreatedAt}, scanTime{&d.UpdatedAt})
+	if err == sql.ErrNoRows {
+		return domain.Friendship{}, domain.ErrRecordNotFound
+	}
+	if err != nil {
+		return domain.Friendship{}, common.ErrDB(err)
+	}
+	return d, nil
+}
+
+// DeleteByUserID deletes the friendships of the user in both directions
+func (f FriendshipRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	if _, err := f.db.Model(ctx).ExecContext(ctx, "delete from friendships where user_id = ? or friend_id = ?", userID, userID); err != nil {
+		return common.ErrDB(err)
+	}
+	return nil
+}
+
+// ListFriendshipsByUserID lists the friendships of the user in both directions and any status,
+// ordered by id from the one after afterID
+func (f FriendshipRepository) ListFriendshipsByUserID(ctx context.Context, userID, afterID string, limit int) (domain.Friendships, error) {
+	result := make(domain.Friendships, 0)
+	err := queryRows(ctx, f.db.Model(ctx),
+		`select id, user_id, friend_id, status, created_at, updated_at from friendships
+		where (user_id = ? or friend_id = ?) and id > ?
+		order by id limit ?`, []interface{}{userID, userID, afterID, limit},
+		func(rows *sql.Rows) error {
+			var d domain.Friendship
+			if err := rows.Scan(&d.Id, &d.UserID, &d.FriendID, &d.Status, scanTime{&d.CreatedAt}, scanTime{&d.UpdatedAt}); err != nil {
+				return err
+			}
+			result = append(result, d)
+			return nil
+		})
+	if err != nil {
+		return domain.Friendships{}, common.ErrDB(err)
+	}
+	return result, nil
+}
+
+// friendsQuery selects the friends of the input users as (input_id, friend_id) having one of the statuses,
+// the input users themselves are left out
+func friendsQuery(userIDs []string, status []domain.FriendshipStatus) (string, []interface{}) {
+	args := stringArgs(userIDs)
+	for _, v := range status {
+		args = append(args, v)
+	}
+	args = append(args, stringArgs(userIDs)...)
+
+	return `select i.user_id as input_id, case when f.user_id = i.user_id then f.friend_id else f.user_id end as friend_id, f.created_at
+		from friendships f
+		inner join (` + valuesTable("user_id", len(userIDs)) + `) as i on f.user_id = i.user_id or f.friend_id = i.user_id
+		where ` + inClause("f.status", len(status)) + `
+		and not ` + inClause("(case when f.user_id = i.user_id then f.friend_id else f.user_id end)", len(userIDs)), args
+}
+
+// GetFriendshipByUserIDAndStatus lists a page of the friends shared by all the users, a single user gives its own friends.
+// A friend shared by several users is dated by its latest friendship
+func (f FriendshipRepository) GetFriendshipByUserIDAndStatus(ctx context.Context, mapEmailUser map[string]string, page domain.Page, status ...domain.FriendshipStatus) ([]string, string, error) {
+	emptyList := []string{}
+	page = page.WithDefaults()
+
+	userIDs := util.MapValuesToSlice(mapEmailUser)
+	friends, args := friendsQuery(userIDs, status)
+	query := `select u.email, max(fr.created_at) as created_at
+		from (` + friends + `) fr
+		inner join users u on u.id = fr.friend_id
+		group by u.email
+		having count(distinct fr.input_id) = ?`
+
+	list, err := getPageEmails(ctx, f.db.Model(ctx), query, append(args, len(userIDs)), page)
+	if err != nil {
+		return emptyList, "", err
+	}
+	if len(list) == 0 {
+		return emptyList, "", domain.ErrRecordNotFound
+	}
+
+	result, nextCursor := pageResult(list, page)
+	return result, nextCursor, nil
+}
+
+func (f FriendshipRepository) GetFriendRequestEmails(ctx context.Context, userID string, direction domain.FriendRequestDirection) ([]string, error) {
+	emptyList := []string{}
+
+	var query string
+	switch direction {
+	case domain.FriendRequestDirectionIncoming:
+		query = "select u.email from friendships f inner join users u on u.id = f.user_id where f.friend_id = ?"
+	case domain.FriendRequestDirectionOutgoing:
+		query = "select u.email from friendships f inner join users u on u.id = f.friend_id where f.user_id = ?"
+	default:
+		return emptyList, domain.ErrFriendRequestDirectionIsNotValid
+	}
+	query += " and f.status = ? order by f.updated_at desc"
+
+	result := make([]string, 0)
+	err := queryRows(ctx, f.db.Model(ctx), query, []interface{}{userID, domain.FriendshipStatusPending}, func(rows *sql.Rows) error {
+		var email string
+		if err := rows.Scan(&email); err != nil {
+			return err
+		}
+		result = append(result, email)
+		return nil
+	})
+	if err != nil {
+		return emptyList, common.ErrDB(err)
+	}
+	return result, nil
+}
+
+// GetFriendSuggestions ranks the friends of the user's friends by the number of mutual friends,
+// users already connected, pending, blocked in the friendships or blocked in either direction are left out
+func (f FriendshipRepository) GetFriendSuggestions(ctx context.Context, userID string, limit int) ([]domain.FriendSuggestion, error) {
+	query := `with friends as (
+			select case when f.user_id = ? then f.friend_id else f.user_id end as friend_id
+			from friendships f
+			where (f.user_id = ? or f.friend_id = ?) and f.status = ?
+		),
+		candidates as (
+			select case when f.user_id = fr.friend_id then f.friend_id else f.user_id end as candidate_id, fr.friend_id as mutual_id
+			from friendships f
+			inner join friends fr on f.user_id = fr.friend_id or f.friend_id = fr.friend_id
+			where f.status = ?
+		)
+		select u.email, count(distinct c.mutual_id) as mutual_count
+		from candidates c
+		inner join users u on u.id = c.candidate_id
+		where c.candidate_id <> ?
+			and not exists (
+				select 1 from friendships x
+				where ((x.user_id = ? and x.friend_id = c.candidate_id) or (x.user_id = c.candidate_id and x.friend_id = ?))
+					and x.status in (?, ?, ?)
+			)
+			and not exists (
+				select 1 from blocks b
+				where (b.user_id = ? and b.target_id = c.candidate_id) or (b.user_id = c.candidate_id and b.target_id = ?)
+			)
+		group by u.email
+		order by mutual_count desc, u.email
+		limit ?`
+	args := []interface{}{
+		userID, userID, userID, domain.FriendshipStatusFriended,
+		domain.FriendshipStatusFriended,
+		userID,
+		userID, userID, domain.FriendshipStatusFriended, domain.FriendshipStatusPending, domain.FriendshipStatusBlocked,
+		userID, userID,
+		limit,
+	}
+
+	result := make([]domain.FriendSuggestion, 0)
+	err := queryRows(ctx, f.db.Model(ctx), query, args, func(rows *sql.Rows) error {
+		var v domain.FriendSuggestion
+		if err := rows.Scan(&v.Email, &v.MutualFriends); err != nil {
+			return err
+		}
+		result = append(result, v)
+		return nil
+	})
+	if err != nil {
+		return nil, common.ErrDB(err)
+	}
+	return result, nil
+}
+
+// friendSetHaving filters the friends of the users grouped by friend for each set operation,
+// the difference needs the friends of the first user which are friend of none of the others
+func friendSetHaving(userIDs []string, operation domain.FriendSetOperation) (string, []interface{}, bool) {
+	switch operation {
+	case domain.FriendSetOperationIntersection:
+		return "count(distinct fr.input_id) = ?", []interface{}{len(util.RemoveDuplicates(userIDs))}, true
+	case domain.FriendSetOperationUnion:
+		return "count(distinct fr.input_id) > 0", nil, true
+	case domain.FriendSetOperationDifference:
+		return "count(distinct fr.input_id) = 1 and max(case when fr.input_id = ? then 1 else 0 end) = 1", []interface{}{userIDs[0]}, true
+	}
+	return "", nil, false
+}
+
+// GetFriendSet combines the friends of the users with the set operation in a single query,
+// each friend comes with the number of users it is friend with and the users themselves are left out
+func (f FriendshipRepository) GetFriendSet(ctx context.Context, userIDs []string, operation domain.FriendSetOperation) ([]domain.FriendSetMember, error) {
+	if len(userIDs) == 0 {
+		return []domain.FriendSetMember{}, nil
+	}
+	having, havingArgs, ok := friendSetHaving(userIDs, operation)
+	if !ok {
+		return nil, domain.ErrFriendSetOperationIsNotValid
+	}
+
+	friends, args := friendsQuery(userIDs, []domain.FriendshipStatus{domain.FriendshipStatusFriended})
+	query := `select u.email, count(distinct fr.input_id) as connected_users
+		from (` + friends + `) fr
+		inner join users u on u.id = fr.friend_id
+		group by u.email
+		having ` + having + `
+		order by connected_users desc, u.email`
+
+	result := make([]domain.FriendSetMember, 0)
+	err := queryRows(ctx, f.db.Model(ctx), query, append(args, havingArgs...), func(rows *sql.Rows) error {
+		var v domain.FriendSetMember
+		if err := rows.Scan(&v.Email, &v.ConnectedUsers); err != nil {
+			return err
+		}
+		result = append(result, v)
+		return nil
+	})
+	if err != nil {
+		return nil, common.ErrDB(err)
+	}
+	return result, nil
+}
+
+// GetShortestFriendPath returns the user ids of the shortest chain of friends from fromID to toID,
+// both included, with at most maxDepth friendships. It runs a breadth first search loading one level
+// of friendships per query, blocked friendships and blocked users are not crossed.
+// It returns ErrRecordNotFound when the users are not connected within maxDepth.
+func (f FriendshipRepository) GetShortestFriendPath(ctx context.Context, fromID, toID string, maxDepth int) ([]string, error) {
+	if fromID == toID {
+		return []string{fromID}, nil
+	}
+
+	parents := map[string]string{fromID: ""}
+	frontier := []string{fromID}
+	for depth := 0; depth < maxDepth && len(frontier) > 0; depth++ {
+		friends, err := f.getFriends(ctx, frontier)
+		if err != nil {
+			return nil, err
+		}
+
+		next := make([]string, 0)
+		for _, id := range frontier {
+			for _, friendID := range friends[id] {
+				if _, ok := parents[friendID]; ok {
+					continue
+				}
+				parents[friendID] = id
+				if friendID == toID {
+					path := make([]string, 0, depth+2)
+					for n := toID; n != ""; n = parents[n] {
+						path = append([]string{n}, path...)
+					}
+					return path, nil
+				}
+				next = append(next, friendID)
+			}
+		}
+		frontier = next
+	}
+
+	return nil, domain.ErrRecordNotFound
+}
+
+// getFriends loads the friends of the users through the friended friendships which are not hidden by a block,
+// sorted so that the same path is found every time
+func (f FriendshipRepository) getFriends(ctx context.Context, userIDs []string) (map[string][]string, error) {
+	query := `select f.user_id, f.friend_id from friendships f
+		where f.status = ? and (` + inClause("f.user_id", len(userIDs)) + ` or ` + inClause("f.friend_id", len(userIDs)) + `)
+			and not exists (
+				select 1 from blocks b
+				where (b.user_id = f.user_id and b.target_id = f.friend_id) or (b.user_id = f.friend_id and b.target_id = f.user_id)
+			)`
+	args := append([]interface{}{domain.FriendshipStatusFriended}, stringArgs(userIDs)...)
+	args = append(args, stringArgs(userIDs)...)
+
+	friends := make(map[string][]string)
+	err := queryRows(ctx, f.db.Model(ctx), query, args, func(rows *sql.Rows) error {
+		var userID, friendID string
+		if err := rows.Scan(&userID, &friendID); err != nil {
+			return err
+		}
+		friends[userID] = append(friends[userID], friendID)
+		friends[friendID] = append(friends[friendID], userID)
+		return nil
+	})
+	if err != nil {
+		return nil, common.ErrDB(err)
+	}
+	for _, list := range friends {
+		sort.Strings(list)
+	}
+	return friends, nil
+}
@@ -0,0 +1,102 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	sqlitedb "github.com/phantranhieunhan/s3-assignment/common/adapter/sqlite"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+type IdempotencyRepository struct {
+	db sqlitedb.Database
+}
+
+func NewIdempotencyRepository(db sqlitedb.Database) IdempotencyRepository {
+	return IdempotencyRepository{
+		db: db,
+	}
+}
+
+// Reserve inserts the record or takes over an expired one, the record holding the key is read back otherwise
+func (i IdempotencyRepository) Reserve(ctx context.Context, d domain.IdempotencyRecord, expiredBefore, leaseExpiredBefore time.Time) (domain.IdempotencyRecord, bool, error) {
+	d.StatusCode = 0
+	d.ContentType = ""
+	d.Body = nil
+	d.CreatedAt = time.Now().UTC()
+
+	held := domain.IdempotencyRecord{Scope: d.Scope, Key: d.Key}
+	reserved := false
+	err := i.db.WithinTransaction(ctx, func(ctx context.Context) error {
+		result, err := i.db.Model(ctx).ExecContext(ctx,
+			`insert into idempotency_keys (scope, key, fingerprint, status_code, content_type, body, created_at) values (?, ?, ?, 0, '', null, ?)
+			on conflict (scope, key) do update set fingerprint = excluded.fingerprint, status_code = 0, content_type = '', body = null, created_at = excluded.created_at
+			where idempotency_keys.created_at < ? or (idempotency_keys.status_code = 0 and idempotency_keys.created_at < ?)`,
+			d.Scope, d.Key, d.Fingerprint, toTime(d.CreatedAt), toTime(expiredBefore), toTime(leaseExpiredBefore))
+		if err != nil {
+			return common.ErrDB(err)
+		}
+		rowsAff, err := result.RowsAffected()
+		if err != nil {
+			return common.ErrDB(err)
+		}
+		if rowsAff > 0 {
+			held, reserved = d, true
+			return nil
+		}
+
+		err = i.db.Model(ctx).QueryRowContext(ctx,
+			"select fingerprint, status_code, content_type, body, created_at from idempotency_keys where scope = ? and key = ?", d.Scope, d.Key).
+			Scan(&held.Fingerprint, &held.StatusCode, &held.ContentType, &held.Body, scanTime{&held.CreatedAt})
+		if err == sql.ErrNoRows {
+			return domain.ErrRecordNotFound
+		}
+		if err != nil {
+			return common.ErrDB(err)
+		}
+		return nil
+	})
+	if err != nil {
+		return domain.IdempotencyRecord{}, false, err
+	}
+	return held, reserved, nil
+}
+
+func (i IdempotencyRepository) Complete(ctx context.Context, d domain.IdempotencyRecord) error {
+	result, err := i.db.Model(ctx).ExecContext(ctx,
+		"update idempotency_keys set status_code = ?, content_type = ?, body = ? where scope = ? and key = ? and created_at = ?",
+		d.StatusCode, d.ContentType, d.Body, d.Scope, d.Key, toTime(d.CreatedAt))
+	if err != nil {
+		return common.ErrDB(err)
+	}
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return common.ErrDB(err)
+	}
+	if rowsAff == 0 {
+		return domain.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (i IdempotencyRepository) Release(ctx context.Context, d domain.IdempotencyRecord) error {
+	_, err := i.db.Model(ctx).ExecContext(ctx, "delete from idempotency_keys where scope = ? and key = ? and created_at = ?", d.Scope, d.Key, toTime(d.CreatedAt))
+	if err != nil {
+		return common.ErrDB(err)
+	}
+	return nil
+}
+
+func (i IdempotencyRepository) DeleteExpired(ctx context.Context, expiredBefore time.Time) (int, error) {
+	result, err := i.db.Model(ctx).ExecContext(ctx, "delete from idempotency_keys where created_at < ?", toTime(expiredBefore))
+	if err != nil {
+		return 0, common.ErrDB(err)
+	}
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, common.ErrDB(err)
+	}
+	return int(rowsAff), nil
+}
@@ -0,0 +1,89 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+)
+
+// pageEmail is a row of a paginated list of emails
+type pageEmail struct {
+	Email     string
+	CreatedAt time.Time
+}
+
+// pageQuery wraps a query selecting email and created_at with the since filter, the cursor and the order of the page,
+// one more item than the limit is asked to know whether there is a next page
+func pageQuery(query string, args []interface{}, page domain.Page) (string, []interface{}, error) {
+	where := "1 = 1"
+	if !page.Since.IsZero() {
+		where += " and created_at >= ?"
+		args = append(args, toTime(page.Since))
+	}
+	if page.Cursor != "" {
+		cursor, err := domain.DecodeCursor(page.Cursor)
+		if err != nil {
+			return "", nil, err
+		}
+		switch page.Sort {
+		case domain.PageSortCreatedAt:
+			where += " and (created_at, email) > (?, ?)"
+			args = append(args, toTime(cursor.CreatedAt), cursor.Key)
+		default:
+			where += " and email > ?"
+			args = append(args, cursor.Key)
+		}
+	}
+
+	orderBy := "email"
+	if page.Sort == domain.PageSortCreatedAt {
+		orderBy = "created_at, email"
+	}
+
+	args = append(args, page.Limit+1)
+	return fmt.Sprintf("select email, created_at from (%s) as page_query where %s order by %s limit ?",
+		query, where, orderBy), args, nil
+}
+
+// getPageEmails runs the query wrapped by pageQuery
+func getPageEmails(ctx context.Context, exec boil.ContextExecutor, query string, args []interface{}, page domain.Page) ([]pageEmail, error) {
+	query, args, err := pageQuery(query, args, page)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]pageEmail, 0)
+	err = queryRows(ctx, exec, query, args, func(rows *sql.Rows) error {
+		var v pageEmail
+		if err := rows.Scan(&v.Email, scanTime{&v.CreatedAt}); err != nil {
+			return err
+		}
+		list = append(list, v)
+		return nil
+	})
+	if err != nil {
+		return nil, common.ErrDB(err)
+	}
+	return list, nil
+}
+
+// pageResult cuts the extra item asked by pageQuery and returns the cursor of the next page, empty on the last page
+func pageResult(list []pageEmail, page domain.Page) ([]string, string) {
+	var nextCursor string
+	if len(list) > page.Limit {
+		list = list[:page.Limit]
+		last := list[len(list)-1]
+		nextCursor = domain.EncodeCursor(domain.Cursor{Key: last.Email, CreatedAt: last.CreatedAt})
+	}
+
+	result := make([]string, 0, len(list))
+	for _, v := range list {
+		result = append(result, v.Email)
+	}
+	return result, nextCursor
+}
@@ -0,0 +1,165 @@
+-- the sqlite counterpart of the postgres migrations, the arrays of postgres are tables of their own
+
+create table if not exists users(
+	id text not null primary key,
+	email text not null unique,
+	password text not null default '',
+	created_at text not null,
+	updated_at text not null
+);
+
+create table if not exists friendships(
+	id text not null primary key,
+	user_id text not null references users(id),
+	friend_id text not null references users(id),
+	status integer not null default 0,
+	created_at text not null,
+	updated_at text not null
+);
+
+create table if not exists subscriptions(
+	id text not null primary key,
+	user_id text not null references users(id),
+	subscriber_id text not null references users(id),
+	status integer not null default 0,
+	created_at text not null,
+	updated_at text not null,
+	unique (user_id, subscriber_id)
+);
+
+create table if not exists blocks(
+	id text not null primary key,
+	user_id text not null references users(id),
+	target_id text not null references users(id),
+	friendship_status integer not null default 0,
+	subscription_status integer not null default 0,
+	created_at text not null,
+	updated_at text not null,
+	unique (user_id, target_id)
+);
+
+create table if not exists updates(
+	id text not null primary key,
+	user_id text not null references users(id),
+	text text not null,
+	created_at text not null,
+	updated_at text not null
+);
+
+create index if not exists updates_userid_createdat_idx on updates (user_id, created_at);
+
+create table if not exists update_mentions(
+	update_id text not null references updates(id) on delete cascade,
+	email text not null,
+	primary key (update_id, email)
+);
+
+create index if not exists update_mentions_email_idx on update_mentions (email);
+
+create table if not exists outbox_events(
+	seq integer primary key autoincrement,
+	id text not null unique,
+	event_type text not null,
+	aggregate_id text not null,
+	payload text not null,
+	created_at text not null,
+	delivered_at text
+);
+
+create index if not exists outbox_events_undelivered_idx on outbox_events (seq) where delivered_at is null;
+
+create table if not exists webhooks(
+	id text not null primary key,
+	url text not null,
+	secret text not null,
+	created_at text not null,
+	updated_at text not null
+);
+
+create table if not exists webhook_event_types(
+	webhook_id text not null references webhooks(id) on delete cascade,
+	position integer not null,
+	event_type text not null,
+	primary key (webhook_id, event_type)
+);
+
+create table if not exists webhook_deliveries(
+	id text not null primary key,
+	webhook_id text not null references webhooks(id) on delete cascade,
+	event_id text not null,
+	event_type text not null,
+	payload text not null,
+	status text not null,
+	attempts integer not null default 0,
+	last_error text not null default '',
+	next_attempt_at text not null,
+	created_at text not null,
+	updated_at text not null,
+	unique (webhook_id, event_id)
+);
+
+create index if not exists webhook_deliveries_pending_idx on webhook_deliveries (next_attempt_at) where status = 'pending';
+create index if not exists webhook_deliveries_webhookid_status_idx on webhook_deliveries (webhook_id, status);
+
+create table if not exists user_deletions(
+	user_id text not null primary key references users(id) on delete cascade,
+	requested_at text not null,
+	purge_at text not null
+);
+
+create index if not exists user_deletions_purgeat_idx on user_deletions (purge_at);
+
+-- the audit log is append only, the triggers reject the updates and the deletes
+create table if not exists audit_log(
+	id text not null primary key,
+	actor text not null,
+	action text not null,
+	status_before text not null default '',
+	status_after text not null default '',
+	request_id text not null default '',
+	created_at text not null
+);
+
+create index if not exists audit_log_createdat_id_idx on audit_log (created_at, id);
+create index if not exists audit_log_actor_idx on audit_log (actor);
+create index if not exists audit_log_action_idx on audit_log (action);
+
+create table if not exists audit_log_targets(
+	audit_id text not null references audit_log(id),
+	position integer not null,
+	target_id text not null,
+	primary key (audit_id, position)
+);
+
+create index if not exists audit_log_targets_targetid_idx on audit_log_targets (target_id);
+
+create trigger if not exists audit_log_no_update before update on audit_log
+begin
+	select raise(abort, 'audit_log is append only');
+end;
+
+create trigger if not exists audit_log_no_delete before delete on audit_log
+begin
+	select raise(abort, 'audit_log is append only');
+end;
+
+create trigger if not exists audit_log_targets_no_update before update on audit_log_targets
+begin
+	select raise(abort, 'audit_log is append only');
+end;
+
+create trigger if not exists audit_log_targets_no_delete before delete on audit_log_targets
+begin
+	select raise(abort, 'audit_log is append only');
+end;
+
+create table if not exists idempotency_keys(
+	scope text not null,
+	key text not null,
+	fingerprint text not null,
+	status_code integer not null default 0,
+	content_type text not null default '',
+	body blob,
+	created_at text not null,
+	primary key (scope, key)
+);
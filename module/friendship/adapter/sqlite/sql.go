@@ -0,0 +1,114 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	sqlitedb "github.com/phantranhieunhan/s3-assignment/common/adapter/sqlite"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+//go:embed schema.sql
+var schema string
+
+// CreateSchema creates the tables which do not exist yet
+func CreateSchema(ctx context.Context, db sqlitedb.Database) error {
+	_, err := db.DB.ExecContext(ctx, schema)
+	return err
+}
+
+// timeLayout has a fixed width so that the text columns order the times like the times themselves
+const timeLayout = "2006-01-02T15:04:05.000000000Z"
+
+// toTime is the text a time is stored as, every time given to a query goes through it
+func toTime(t time.Time) string {
+	return t.UTC().Format(timeLayout)
+}
+
+// scanTime scans a time stored by toTime
+type scanTime struct {
+	t *time.Time
+}
+
+func (s scanTime) Scan(src interface{}) error {
+	var v string
+	switch src := src.(type) {
+	case nil:
+		*s.t = time.Time{}
+		return nil
+	case string:
+		v = src
+	case []byte:
+		v = string(src)
+	default:
+		return fmt.Errorf("cannot scan %T into a time", src)
+	}
+	t, err := time.Parse(timeLayout, v)
+	if err != nil {
+		return err
+	}
+	*s.t = t
+	return nil
+}
+
+// placeholders returns n comma separated placeholders
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// inClause is the dialect neutral form of "column = any($1)", an empty list matches nothing
+func inClause(column string, n int) string {
+	if n == 0 {
+		return "1 = 0"
+	}
+	return fmt.Sprintf("%s in (%s)", column, placeholders(n))
+}
+
+// valuesTable is the dialect neutral form of "unnest($1::text[]) as t(column)", a table of n rows of a single column
+func valuesTable(column string, n int) string {
+	if n == 0 {
+		return fmt.Sprintf("select null as %s where 1 = 0", column)
+	}
+	return "select ? as " + column + strings.Repeat(" union all select ?", n-1)
+}
+
+func stringArgs(values []string) []interface{} {
+	args := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		args = append(args, v)
+	}
+	return args
+}
+
+// queryRows runs the query and calls scan for each row
+func queryRows(ctx context.Context, exec boil.ContextExecutor, query string, args []interface{}, scan func(rows *sql.Rows) error) error {
+	rows, err := exec.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// isConstraintError tells whether sqlite rejected the statement because of the constraint
+func isConstraintError(err error, code int) bool {
+	var sqliteErr *sqlite.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.Code() == code
+}
+
+func isForeignKeyViolation(err error) bool {
+	return isConstraintError(err, sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY)
+}
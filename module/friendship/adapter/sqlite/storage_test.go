@@ -0,0 +1,25 @@
+package sqlite_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	sqlitedb "github.com/phantranhieunhan/s3-assignment/common/adapter/sqlite"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/adapter/sqlite"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/adapter/storagetest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStorage(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) friendship.Storage {
+		db := sqlitedb.NewDatabase(filepath.Join(t.TempDir(), "friendship.db"))
+		t.Cleanup(func() { db.DB.Close() })
+
+		assert.NoError(t, sqlite.CreateSchema(context.Background(), db))
+		// the schema can be created again on an existing file
+		assert.NoError(t, sqlite.CreateSchema(context.Background(), db))
+		return friendship.NewSQLiteStorage(db)
+	})
+}
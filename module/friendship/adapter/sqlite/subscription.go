@@ -0,0 +1,173 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	sqlitedb "github.com/phantranhieunhan/s3-assignment/common/adapter/sqlite"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/phantranhieunhan/s3-assignment/pkg/util"
+)
+
+type SubscriptionRepository struct {
+	db sqlitedb.Database
+}
+
+func NewSubscriptionRepository(db sqlitedb.Database) SubscriptionRepository {
+	return SubscriptionRepository{
+		db: db,
+	}
+}
+
+func (s SubscriptionRepository) Create(ctx context.Context, sub domain.Subscription) (string, error) {
+	sub.Id = util.GenUUID()
+	now := time.Now().UTC()
+	if sub.CreatedAt.IsZero() {
+		sub.CreatedAt = now
+	}
+	if sub.UpdatedAt.IsZero() {
+		sub.UpdatedAt = now
+	}
+
+	_, err := s.db.Model(ctx).ExecContext(ctx,
+		"insert into subscriptions (id, user_id, subscriber_id, status, created_at, updated_at) values (?, ?, ?, ?, ?, ?)",
+		sub.Id, sub.UserID, sub.SubscriberID, sub.Status, toTime(sub.CreatedAt), toTime(sub.UpdatedAt))
+	if err != nil {
+		return "", common.ErrDB(err)
+	}
+	return sub.Id, nil
+}
+
+func (s SubscriptionRepository) UpdateStatus(ctx context.Context, id string, status domain.SubscriptionStatus) error {
+	_, err := s.db.Model(ctx).ExecContext(ctx, "update subscriptions set status = ?, updated_at = ? where id = ?", status, toTime(time.Now()), id)
+	if err != nil {
+		return common.ErrDB(err)
+	}
+	return nil
+}
+
+// UpsertSubscription updates the status of the subscription of the same users when there is one and returns its id
+func (s SubscriptionRepository) UpsertSubscription(ctx context.Context, sub domain.Subscription) (string, error) {
+	if sub.Id == "" {
+		sub.Id = util.GenUUID()
+	}
+	now := toTime(time.Now())
+	createdAt := now
+	if !sub.CreatedAt.IsZero() {
+		createdAt = toTime(sub.CreatedAt)
+	}
+
+	var id string
+	err := s.db.Model(ctx).QueryRowContext(ctx,
+		`insert into subscriptions (id, user_id, subscriber_id, status, created_at, updated_at) values (?, ?, ?, ?, ?, ?)
+		on conflict (user_id, subscriber_id) do update set status = excluded.status, updated_at = excluded.updated_at
+		returning id`,
+		sub.Id, sub.UserID, sub.SubscriberID, sub.Status, createdAt, now).Scan(&id)
+	if err != nil {
+		return "", common.ErrDB(err)
+	}
+	return id, nil
+}
+
+func (s SubscriptionRepository) Delete(ctx context.Context, id string) error {
+	if _, err := s.db.Model(ctx).ExecContext(ctx, "delete from subscriptions where id = ?", id); err != nil {
+		return common.ErrDB(err)
+	}
+	return nil
+}
+
+func (s SubscriptionRepository) GetSubscription(ctx context.Context, ss domain.Subscriptions) (domain.Subscriptions, error) {
+	where := make([]string, 0, len(ss))
+	args := make([]interface{}, 0, 2*len(ss))
+	for _, v := range ss {
+		where = append(where, "(user_id = ? and subscriber_id = ?)")
+		args = append(args, v.UserID, v.SubscriberID)
+	}
+	if len(where) == 0 {
+		where = append(where, "1 = 0")
+	}
+
+	result := make(domain.Subscriptions, 0)
+	err := queryRows(ctx, s.db.Model(ctx),
+		"select id, user_id, subscriber_id, status, created_at, updated_at from subscriptions where "+strings.Join(where, " or "), args,
+		func(rows *sql.Rows) error {
+			var d domain.Subscription
+			if err := rows.Scan(&d.Id, &d.UserID, &d.SubscriberID, &d.Status, scanTime{&d.CreatedAt}, scanTime{&d.UpdatedAt}); err != nil {
+				return err
+			}
+			result = append(result, d)
+			return nil
+		})
+	if err != nil {
+		return domain.Subscriptions{}, common.ErrDB(err)
+	}
+	return result, nil
+}
+
+// DeleteByUserID deletes the subscriptions to the user and of the user
+func (s SubscriptionRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	if _, err := s.db.Model(ctx).ExecContext(ctx, "delete from subscriptions where user_id = ? or subscriber_id = ?", userID, userID); err != nil {
+		return common.ErrDB(err)
+	}
+	return nil
+}
+
+// ListSubscriptionsByUserID lists the subscriptions to the user and of the user in any status,
+// ordered by id from the one after afterID
+func (s SubscriptionRepository) ListSubscriptionsByUserID(ctx context.Context, userID, afterID string, limit int) (domain.Subscriptions, error) {
+	result := make(domain.Subscriptions, 0)
+	err := queryRows(ctx, s.db.Model(ctx),
+		`select id, user_id, subscriber_id, status, created_at, updated_at from subscriptions
+		where (user_id = ? or subscriber_id = ?) and id > ?
+		order by id limit ?`, []interface{}{userID, userID, afterID, limit},
+		func(rows *sql.Rows) error {
+			var d domain.Subscription
+			if err := rows.Scan(&d.Id, &d.UserID, &d.SubscriberID, &d.Status, scanTime{&d.CreatedAt}, scanTime{&d.UpdatedAt}); err != nil {
+				return err
+			}
+			result = append(result, d)
+			return nil
+		})
+	if err != nil {
+		return domain.Subscriptions{}, common.ErrDB(err)
+	}
+	return result, nil
+}
+
+// GetSubscriptionEmailsByUserIDAndEmails lists a page of the subscribers of the user and the mentioned users who did not unsubscribe,
+// a recipient is dated by its subscription or by its sign up when it is only mentioned
+func (s SubscriptionRepository) GetSubscriptionEmailsByUserIDAndEmails(ctx context.Context, id string, emails []string, page domain.Page) ([]string, string, error) {
+	page = page.WithDefaults()
+	query := `select u.email, coalesce(s.created_at, u.created_at) as created_at
+		from users u
+		left join subscriptions s on s.subscriber_id = u.id and s.user_id = ?
+		where s.status = ?
+		or (` + inClause("u.email", len(emails)) + ` and (s.status is null or s.status <> ?))`
+	args := append([]interface{}{id, domain.SubscriptionStatusSubscribed}, stringArgs(emails)...)
+
+	return s.getPageEmails(ctx, query, append(args, domain.SubscriptionStatusUnsubscribed), page)
+}
+
+// GetSubscriberEmails lists a page of the users subscribed to the user, dated by their subscription
+func (s SubscriptionRepository) GetSubscriberEmails(ctx context.Context, id string, page domain.Page) ([]string, string, error) {
+	page = page.WithDefaults()
+	query := `select u.email, s.created_at
+		from subscriptions s
+		inner join users u on u.id = s.subscriber_id
+		where s.user_id = ? and s.status = ?`
+
+	return s.getPageEmails(ctx, query, []interface{}{id, domain.SubscriptionStatusSubscribed}, page)
+}
+
+func (s SubscriptionRepository) getPageEmails(ctx context.Context, query string, args []interface{}, page domain.Page) ([]string, string, error) {
+	list, err := getPageEmails(ctx, s.db.Model(ctx), query, args, page)
+	if err != nil {
+		return []string{}, "", err
+	}
+
+	result, nextCursor := pageResult(list, page)
+	return result, nextCursor, nil
+}
@@ -0,0 +1,153 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	sqlitedb "github.com/phantranhieunhan/s3-assignment/common/adapter/sqlite"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/phantranhieunhan/s3-assignment/pkg/util"
+)
+
+type UpdateRepository struct {
+	db sqlitedb.Database
+}
+
+func NewUpdateRepository(db sqlitedb.Database) UpdateRepository {
+	return UpdateRepository{
+		db: db,
+	}
+}
+
+// Create stores the mentions of the update in update_mentions, sqlite has no arrays
+func (u UpdateRepository) Create(ctx context.Context, d domain.Update) (string, error) {
+	d.Id = util.GenUUID()
+	err := u.db.WithinTransaction(ctx, func(ctx context.Context) error {
+		_, err := u.db.Model(ctx).ExecContext(ctx,
+			"insert into updates (id, user_id, text, created_at, updated_at) values (?, ?, ?, ?, ?)",
+			d.Id, d.UserID, d.Text, toTime(d.CreatedAt), toTime(d.CreatedAt))
+		if err != nil {
+			return common.ErrDB(err)
+		}
+		for _, email := range util.RemoveDuplicates(d.Mentions) {
+			_, err = u.db.Model(ctx).ExecContext(ctx, "insert into update_mentions (update_id, email) values (?, ?)", d.Id, email)
+			if err != nil {
+				return common.ErrDB(err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return d.Id, nil
+}
+
+// GetFeed lists a page of the updates the user receives, the newest first.
+// The user receives the updates of the users it subscribes to and the updates mentioning it unless it unsubscribed from the sender,
+// the same rules as GetSubscriptionEmailsByUserIDAndEmails, and never the updates across a block
+func (u UpdateRepository) GetFeed(ctx context.Context, userID string, page domain.Page) ([]domain.FeedItem, string, error) {
+	page = page.WithDefaults()
+	args := []interface{}{userID, userID, userID, domain.SubscriptionStatusSubscribed, domain.SubscriptionStatusUnsubscribed, userID, userID}
+
+	query := `select up.id, sender.email as sender, up.text, up.created_at
+		from updates up
+		inner join users sender on sender.id = up.user_id
+		inner join users me on me.id = ?
+		left join subscriptions s on s.user_id = up.user_id and s.subscriber_id = ?
+		where up.user_id <> ?
+		and (s.status = ? or (
+			exists (select 1 from update_mentions m where m.update_id = up.id and m.email = me.email)
+			and (s.status is null or s.status <> ?)
+		))
+		and not exists (
+			select 1 from blocks b
+			where (b.user_id = up.user_id and b.target_id = ?) or (b.user_id = ? and b.target_id = up.user_id)
+		)`
+	if !page.Since.IsZero() {
+		query += " and up.created_at >= ?"
+		args = append(args, toTime(page.Since))
+	}
+	if page.Cursor != "" {
+		cursor, err := domain.DecodeCursor(page.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		query += " and (up.created_at, up.id) < (?, ?)"
+		args = append(args, toTime(cursor.CreatedAt), cursor.Key)
+	}
+	query += " order by up.created_at desc, up.id desc limit ?"
+	args = append(args, page.Limit+1)
+
+	list := make([]domain.FeedItem, 0)
+	err := queryRows(ctx, u.db.Model(ctx), query, args, func(rows *sql.Rows) error {
+		var v domain.FeedItem
+		if err := rows.Scan(&v.ID, &v.Sender, &v.Text, scanTime{&v.CreatedAt}); err != nil {
+			return err
+		}
+		list = append(list, v)
+		return nil
+	})
+	if err != nil {
+		return nil, "", common.ErrDB(err)
+	}
+
+	var nextCursor string
+	if len(list) > page.Limit {
+		list = list[:page.Limit]
+		last := list[len(list)-1]
+		nextCursor = domain.EncodeCursor(domain.Cursor{Key: last.ID, CreatedAt: last.CreatedAt})
+	}
+	return list, nextCursor, nil
+}
+
+// ListUpdatesByUserID lists the updates posted by the user, ordered by id from the one after afterID,
+// the mentions of the updates listed are read at once
+func (u UpdateRepository) ListUpdatesByUserID(ctx context.Context, userID, afterID string, limit int) ([]domain.Update, error) {
+	result := make([]domain.Update, 0)
+	err := queryRows(ctx, u.db.Model(ctx),
+		`select id, user_id, text, created_at, updated_at from updates
+		where user_id = ? and id > ?
+		order by id limit ?`, []interface{}{userID, afterID, limit},
+		func(rows *sql.Rows) error {
+			d := domain.Update{Mentions: []string{}}
+			if err := rows.Scan(&d.Id, &d.UserID, &d.Text, scanTime{&d.CreatedAt}, scanTime{&d.UpdatedAt}); err != nil {
+				return err
+			}
+			result = append(result, d)
+			return nil
+		})
+	if err != nil {
+		return []domain.Update{}, common.ErrDB(err)
+	}
+
+	index := make(map[string]int, len(result))
+	ids := make([]interface{}, 0, len(result))
+	for i, d := range result {
+		index[d.Id] = i
+		ids = append(ids, d.Id)
+	}
+	err = queryRows(ctx, u.db.Model(ctx),
+		"select update_id, email from update_mentions where "+inClause("update_id", len(ids))+" order by rowid", ids,
+		func(rows *sql.Rows) error {
+			var id, email string
+			if err := rows.Scan(&id, &email); err != nil {
+				return err
+			}
+			d := &result[index[id]]
+			d.Mentions = append(d.Mentions, email)
+			return nil
+		})
+	if err != nil {
+		return []domain.Update{}, common.ErrDB(err)
+	}
+	return result, nil
+}
+
+func (u UpdateRepository) RemoveMentions(ctx context.Context, email string) error {
+	if _, err := u.db.Model(ctx).ExecContext(ctx, "delete from update_mentions where email = ?", email); err != nil {
+		return common.ErrDB(err)
+	}
+	return nil
+}
@@ -0,0 +1,232 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	sqlitedb "github.com/phantranhieunhan/s3-assignment/common/adapter/sqlite"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/phantranhieunhan/s3-assignment/pkg/util"
+)
+
+type UserRepository struct {
+	db sqlitedb.Database
+}
+
+func NewUserRepository(db sqlitedb.Database) UserRepository {
+	return UserRepository{
+		db: db,
+	}
+}
+
+// getEmailsAndIDs maps the email to the id of the users whose column is one of the values
+func (f UserRepository) getEmailsAndIDs(ctx context.Context, column string, values []string) (map[string]string, error) {
+	result := make(map[string]string, len(values))
+	err := queryRows(ctx, f.db.Model(ctx), "select email, id from users where "+inClause(column, len(values)), stringArgs(values), func(rows *sql.Rows) error {
+		var email, id string
+		if err := rows.Scan(&email, &id); err != nil {
+			return err
+		}
+		result[email] = id
+		return nil
+	})
+	if err != nil {
+		return nil, common.ErrDB(err)
+	}
+	return result, nil
+}
+
+func (f UserRepository) GetUserIDsByEmails(ctx context.Context, emails []string) (map[string]string, error) {
+	result, err := f.getEmailsAndIDs(ctx, "email", emails)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) != len(emails) {
+		return nil, domain.ErrNotFoundUserByEmail
+	}
+	return result, nil
+}
+
+func (f UserRepository) GetEmailsByUserIDs(ctx context.Context, userIDs []string) (map[string]string, error) {
+	users, err := f.getEmailsAndIDs(ctx, "id", userIDs)
+	if err != nil {
+		return make(map[string]string, 0), err
+	}
+	if len(users) != len(userIDs) {
+		return nil, domain.ErrNotFoundUserByEmail
+	}
+
+	result := make(map[string]string, len(users))
+	for email, id := range users {
+		result[id] = email
+	}
+	return result, nil
+}
+
+func (f UserRepository) Create(ctx context.Context, d domain.User) (string, error) {
+	d.Base.Id = util.GenUUID()
+	now := time.Now().UTC()
+	if d.Base.CreatedAt.IsZero() {
+		d.Base.CreatedAt = now
+	}
+
+	_, err := f.db.Model(ctx).ExecContext(ctx,
+		"insert into users (id, email, password, created_at, updated_at) values (?, ?, ?, ?, ?)",
+		d.Base.Id, d.Email, d.Password, toTime(d.Base.CreatedAt), toTime(now))
+	if err != nil {
+		return "", common.ErrDB(err)
+	}
+	return d.Base.Id, nil
+}
+
+func (f UserRepository) GetPasswordHash(ctx context.Context, userID string) (string, error) {
+	var password string
+	err := f.db.Model(ctx).QueryRowContext(ctx, "select password from users where id = ?", userID).Scan(&password)
+	if err == sql.ErrNoRows {
+		return "", domain.ErrRecordNotFound
+	}
+	if err != nil {
+		return "", common.ErrDB(err)
+	}
+	return password, nil
+}
+
+func (f UserRepository) SetPasswordHash(ctx context.Context, userID, hash string) error {
+	result, err := f.db.Model(ctx).ExecContext(ctx, "update users set password = ?, updated_at = ? where id = ?", hash, toTime(time.Now()), userID)
+	if err != nil {
+		return common.ErrDB(err)
+	}
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return common.ErrDB(err)
+	}
+	if rowsAff == 0 {
+		return domain.ErrUpdateRecordNotFound
+	}
+	return nil
+}
+
+func (f UserRepository) GetUserByEmail(ctx context.Context, email string) (domain.User, error) {
+	var d domain.User
+	err := f.db.Model(ctx).QueryRowContext(ctx, "select id, email, created_at, updated_at from users where email = ?", email).
+		Scan(&d.Base.Id, &d.Email, scanTime{&d.Base.CreatedAt}, scanTime{&d.Base.UpdatedAt})
+	if err == sql.ErrNoRows {
+		return domain.User{}, domain.ErrRecordNotFound
+	}
+	if err != nil {
+		return domain.User{}, common.ErrDB(err)
+	}
+	return d, nil
+}
+
+func (f UserRepository) Update(ctx context.Context, d domain.User) error {
+	result, err := f.db.Model(ctx).ExecContext(ctx, "update users set email = ?, updated_at = ? where id = ?", d.Email, toTime(time.Now()), d.Base.Id)
+	if err != nil {
+		return common.ErrDB(err)
+	}
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return common.ErrDB(err)
+	}
+	if rowsAff == 0 {
+		return domain.ErrUpdateRecordNotFound
+	}
+	return nil
+}
+
+func (f UserRepository) Delete(ctx context.Context, id string) error {
+	result, err := f.db.Model(ctx).ExecContext(ctx, "delete from users where id = ?", id)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return domain.ErrUserHasRelations
+		}
+		return common.ErrDB(err)
+	}
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return common.ErrDB(err)
+	}
+	if rowsAff == 0 {
+		return domain.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Anonymize replaces the email of the user with the tombstone, clears its password and drops its scheduled deletion
+func (f UserRepository) Anonymize(ctx context.Context, id, tombstone string) error {
+	result, err := f.db.Model(ctx).ExecContext(ctx, "update users set email = ?, password = '', updated_at = ? where id = ?",
+		tombstone, toTime(time.Now()), id)
+	if err != nil {
+		return common.ErrDB(err)
+	}
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return common.ErrDB(err)
+	}
+	if rowsAff == 0 {
+		return domain.ErrUpdateRecordNotFound
+	}
+	if _, err = f.db.Model(ctx).ExecContext(ctx, "delete from user_deletions where user_id = ?", id); err != nil {
+		return common.ErrDB(err)
+	}
+	return nil
+}
+
+func (f UserRepository) ScheduleDeletion(ctx context.Context, d domain.UserDeletion) error {
+	_, err := f.db.Model(ctx).ExecContext(ctx, "insert into user_deletions (user_id, requested_at, purge_at) values (?, ?, ?)",
+		d.UserID, toTime(d.RequestedAt), toTime(d.PurgeAt))
+	if err != nil {
+		return common.ErrDB(err)
+	}
+	return nil
+}
+
+func (f UserRepository) GetDeletion(ctx context.Context, userID string) (domain.UserDeletion, error) {
+	var d domain.UserDeletion
+	err := f.db.Model(ctx).QueryRowContext(ctx, "select user_id, requested_at, purge_at from user_deletions where user_id = ?", userID).
+		Scan(&d.UserID, scanTime{&d.RequestedAt}, scanTime{&d.PurgeAt})
+	if err == sql.ErrNoRows {
+		return domain.UserDeletion{}, domain.ErrRecordNotFound
+	}
+	if err != nil {
+		return domain.UserDeletion{}, common.ErrDB(err)
+	}
+	return d, nil
+}
+
+func (f UserRepository) CancelDeletion(ctx context.Context, userID string) error {
+	result, err := f.db.Model(ctx).ExecContext(ctx, "delete from user_deletions where user_id = ?", userID)
+	if err != nil {
+		return common.ErrDB(err)
+	}
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return common.ErrDB(err)
+	}
+	if rowsAff == 0 {
+		return domain.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetDueDeletions lists the deletions whose grace period is over, the earliest first
+func (f UserRepository) GetDueDeletions(ctx context.Context, now time.Time, limit int) ([]domain.UserDeletion, error) {
+	result := make([]domain.UserDeletion, 0)
+	err := queryRows(ctx, f.db.Model(ctx),
+		"select user_id, requested_at, purge_at from user_deletions where purge_at <= ? order by purge_at, user_id limit ?",
+		[]interface{}{toTime(now), limit},
+		func(rows *sql.Rows) error {
+			var d domain.UserDeletion
+			if err := rows.Scan(&d.UserID, scanTime{&d.RequestedAt}, scanTime{&d.PurgeAt}); err != nil {
+				return err
+			}
+			result = append(result, d)
+			return nil
+		})
+	if err != nil {
+		return []domain.UserDeletion{}, common.ErrDB(err)
+	}
+	return result, nil
+}
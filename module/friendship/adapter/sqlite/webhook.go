@@ -0,0 +1,219 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	sqlitedb "github.com/phantranhieunhan/s3-assignment/common/adapter/sqlite"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/phantranhieunhan/s3-assignment/pkg/util"
+)
+
+const deliveryColumns = "id, webhook_id, event_id, event_type, payload, status, attempts, last_error, next_attempt_at, created_at, updated_at"
+
+type WebhookRepository struct {
+	db sqlitedb.Database
+}
+
+func NewWebhookRepository(db sqlitedb.Database) WebhookRepository {
+	return WebhookRepository{
+		db: db,
+	}
+}
+
+// Create stores the event types of the webhook in webhook_event_types, sqlite has no arrays
+func (w WebhookRepository) Create(ctx context.Context, d domain.Webhook) (string, error) {
+	d.Id = util.GenUUID()
+	now := toTime(time.Now())
+	err := w.db.WithinTransaction(ctx, func(ctx context.Context) error {
+		_, err := w.db.Model(ctx).ExecContext(ctx,
+			"insert into webhooks (id, url, secret, created_at, updated_at) values (?, ?, ?, ?, ?)",
+			d.Id, d.URL, d.Secret, now, now)
+		if err != nil {
+			return common.ErrDB(err)
+		}
+		for i, t := range d.EventTypes {
+			_, err = w.db.Model(ctx).ExecContext(ctx,
+				"insert into webhook_event_types (webhook_id, position, event_type) values (?, ?, ?) on conflict do nothing",
+				d.Id, i, string(t))
+			if err != nil {
+				return common.ErrDB(err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return d.Id, nil
+}
+
+func (w WebhookRepository) List(ctx context.Context) ([]domain.Webhook, error) {
+	return w.getWebhooks(ctx, "select id, url, secret, created_at, updated_at from webhooks order by created_at, id")
+}
+
+func (w WebhookRepository) GetByEventType(ctx context.Context, t domain.EventType) ([]domain.Webhook, error) {
+	return w.getWebhooks(ctx, `select w.id, w.url, w.secret, w.created_at, w.updated_at from webhooks w
+		where exists (select 1 from webhook_event_types et where et.webhook_id = w.id and et.event_type = ?)
+		order by w.created_at, w.id`, string(t))
+}
+
+func (w WebhookRepository) getWebhooks(ctx context.Context, query string, args ...interface{}) ([]domain.Webhook, error) {
+	list := make([]domain.Webhook, 0)
+	index := make(map[string]int)
+	err := queryRows(ctx, w.db.Model(ctx), query, args, func(rows *sql.Rows) error {
+		var v domain.Webhook
+		if err := rows.Scan(&v.Id, &v.URL, &v.Secret, scanTime{&v.CreatedAt}, scanTime{&v.UpdatedAt}); err != nil {
+			return err
+		}
+		v.EventTypes = make([]domain.EventType, 0)
+		index[v.Id] = len(list)
+		list = append(list, v)
+		return nil
+	})
+	if err != nil {
+		return nil, common.ErrDB(err)
+	}
+
+	ids := make([]string, 0, len(list))
+	for _, v := range list {
+		ids = append(ids, v.Id)
+	}
+	err = queryRows(ctx, w.db.Model(ctx),
+		"select webhook_id, event_type from webhook_event_types where "+inClause("webhook_id", len(ids))+" order by webhook_id, position",
+		stringArgs(ids), func(rows *sql.Rows) error {
+			var (
+				id string
+				t  domain.EventType
+			)
+			if err := rows.Scan(&id, &t); err != nil {
+				return err
+			}
+			list[index[id]].EventTypes = append(list[index[id]].EventTypes, t)
+			return nil
+		})
+	if err != nil {
+		return nil, common.ErrDB(err)
+	}
+	return list, nil
+}
+
+// Delete removes the webhook with its deliveries
+func (w WebhookRepository) Delete(ctx context.Context, id string) error {
+	result, err := w.db.Model(ctx).ExecContext(ctx, "delete from webhooks where id = ?", id)
+	if err != nil {
+		return common.ErrDB(err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return common.ErrDB(err)
+	}
+	if n == 0 {
+		return domain.ErrRecordNotFound
+	}
+	return nil
+}
+
+// CreateDelivery ignores an event already queued for the webhook, the outbox relays an event at least once
+func (w WebhookRepository) CreateDelivery(ctx context.Context, d domain.WebhookDelivery) (string, error) {
+	d.Id = util.GenUUID()
+	now := toTime(time.Now())
+	_, err := w.db.Model(ctx).ExecContext(ctx,
+		`insert into webhook_deliveries (`+deliveryColumns+`)
+		values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		on conflict (webhook_id, event_id) do nothing`,
+		d.Id, d.WebhookID, d.EventID, string(d.EventType), string(d.Payload), string(d.Status), d.Attempts, d.LastError, toTime(d.NextAttemptAt), now, now)
+	if err != nil {
+		return "", common.ErrDB(err)
+	}
+	return d.Id, nil
+}
+
+func (w WebhookRepository) GetDelivery(ctx context.Context, id string) (domain.WebhookDelivery, error) {
+	list, err := w.getDeliveries(ctx, "select "+deliveryColumns+" from webhook_deliveries where id = ?", id)
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	if len(list) == 0 {
+		return domain.WebhookDelivery{}, domain.ErrRecordNotFound
+	}
+	return list[0], nil
+}
+
+// GetDueDeliveries needs no row lock, the single connection of the database runs one transaction at a time
+func (w WebhookRepository) GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]domain.DueWebhookDelivery, error) {
+	result := make([]domain.DueWebhookDelivery, 0)
+	err := queryRows(ctx, w.db.Model(ctx),
+		`select d.id, d.webhook_id, d.event_id, d.event_type, d.payload, d.status, d.attempts, d.last_error, d.next_attempt_at, d.created_at, d.updated_at,
+		w.url, w.secret from webhook_deliveries d
+		inner join webhooks w on w.id = d.webhook_id
+		where d.status = ? and d.next_attempt_at <= ?
+		order by d.next_attempt_at, d.created_at
+		limit ?`, []interface{}{string(domain.WebhookDeliveryStatusPending), toTime(now), limit},
+		func(rows *sql.Rows) error {
+			var v domain.DueWebhookDelivery
+			d, err := scanDelivery(rows, &v.Webhook.URL, &v.Webhook.Secret)
+			if err != nil {
+				return err
+			}
+			v.Delivery = d
+			v.Webhook.Id = d.WebhookID
+			result = append(result, v)
+			return nil
+		})
+	if err != nil {
+		return nil, common.ErrDB(err)
+	}
+	return result, nil
+}
+
+func (w WebhookRepository) UpdateDelivery(ctx context.Context, d domain.WebhookDelivery) error {
+	_, err := w.db.Model(ctx).ExecContext(ctx,
+		"update webhook_deliveries set status = ?, attempts = ?, last_error = ?, next_attempt_at = ?, updated_at = ? where id = ?",
+		string(d.Status), d.Attempts, d.LastError, toTime(d.NextAttemptAt), toTime(time.Now()), d.Id)
+	if err != nil {
+		return common.ErrDB(err)
+	}
+	return nil
+}
+
+func (w WebhookRepository) GetDeliveriesByStatus(ctx context.Context, webhookID string, status domain.WebhookDeliveryStatus) ([]domain.WebhookDelivery, error) {
+	return w.getDeliveries(ctx,
+		"select "+deliveryColumns+" from webhook_deliveries where webhook_id = ? and status = ? order by created_at desc, id",
+		webhookID, string(status))
+}
+
+func (w WebhookRepository) getDeliveries(ctx context.Context, query string, args ...interface{}) ([]domain.WebhookDelivery, error) {
+	result := make([]domain.WebhookDelivery, 0)
+	err := queryRows(ctx, w.db.Model(ctx), query, args, func(rows *sql.Rows) error {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return err
+		}
+		result = append(result, d)
+		return nil
+	})
+	if err != nil {
+		return nil, common.ErrDB(err)
+	}
+	return result, nil
+}
+
+// scanDelivery scans the columns of deliveryColumns followed by the extra destinations
+func scanDelivery(rows *sql.Rows, extra ...interface{}) (domain.WebhookDelivery, error) {
+	var (
+		d       domain.WebhookDelivery
+		payload string
+	)
+	dest := append([]interface{}{
+		&d.Id, &d.WebhookID, &d.EventID, &d.EventType, &payload, &d.Status, &d.Attempts, &d.LastError,
+		scanTime{&d.NextAttemptAt}, scanTime{&d.CreatedAt}, scanTime{&d.UpdatedAt},
+	}, extra...)
+	if err := rows.Scan(dest...); err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	d.Payload = []byte(payload)
+	return d, nil
+}
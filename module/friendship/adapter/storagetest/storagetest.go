@@ -0,0 +1,954 @@
+// Package storagetest is the conformance suite of the storage drivers, every driver must pass it
+// so that the application behaves the same whatever the configured storage
+package storagetest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/module/friendship"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/phantranhieunhan/s3-assignment/pkg/util"
+	"github.com/stretchr/testify/assert"
+)
+
+// Run runs the suite against the storages returned by open, it is called once per test.
+// The storage may be shared with other tests, every test works on users of its own
+func Run(t *testing.T, open func(t *testing.T) friendship.Storage) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, s suite)
+	}{
+		{"Transaction", testTransaction},
+		{"User", testUser},
+		{"Friendship", testFriendship},
+		{"FriendshipPage", testFriendshipPage},
+		{"FriendSuggestionsAndSet", testFriendSuggestionsAndSet},
+		{"ShortestFriendPath", testShortestFriendPath},
+		{"Subscription", testSubscription},
+		{"SubscriptionEmails", testSubscriptionEmails},
+		{"Block", testBlock},
+		{"ListByUser", testListByUser},
+		{"AccountDeletion", testAccountDeletion},
+		{"Feed", testFeed},
+		{"Event", testEvent},
+		{"Webhook", testWebhook},
+		{"Audit", testAudit},
+		{"Idempotency", testIdempotency},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.run(t, suite{storage: open(t), ns: util.GenUUID()[:8]})
+		})
+	}
+}
+
+type suite struct {
+	storage friendship.Storage
+	// ns keeps the emails of a test apart from the ones of the other tests sharing the storage
+	ns string
+}
+
+// email returns the email of the user of the test, the emails sort as their names do
+func (s suite) email(name string) string {
+	return name + "-" + s.ns + "@example.com"
+}
+
+func (s suite) emails(names ...string) []string {
+	result := make([]string, 0, len(names))
+	for _, name := range names {
+		result = append(result, s.email(name))
+	}
+	return result
+}
+
+// users creates the users and returns their ids by name
+func (s suite) users(t *testing.T, ctx context.Context, names ...string) map[string]string {
+	ids := make(map[string]string, len(names))
+	for _, name := range names {
+		id, err := s.storage.UserRepo.Create(ctx, domain.User{Email: s.email(name)})
+		assert.NoError(t, err)
+		ids[name] = id
+	}
+	return ids
+}
+
+// friendships creates the friendships between the users given by name, one day apart
+func (s suite) friendships(t *testing.T, ctx context.Context, ids map[string]string, status domain.FriendshipStatus, pairs ...[2]string) {
+	createdAt := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+	for i, pair := range pairs {
+		_, err := s.storage.FriendshipRepo.Create(ctx, domain.Friendship{
+			Base:     domain.Base{CreatedAt: createdAt.AddDate(0, 0, i)},
+			UserID:   ids[pair[0]],
+			FriendID: ids[pair[1]],
+			Status:   status,
+		})
+		assert.NoError(t, err)
+	}
+}
+
+func testTransaction(t *testing.T, s suite) {
+	ctx := context.Background()
+	repo := s.storage.UserRepo
+	ids := s.users(t, ctx, "john")
+
+	err := s.storage.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		_, err := repo.Create(ctx, domain.User{Email: s.email("lisa")})
+		assert.NoError(t, err)
+
+		// the transaction sees its own writes, a nested transaction joins it
+		return s.storage.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			_, err := repo.GetUserByEmail(ctx, s.email("lisa"))
+			return err
+		})
+	})
+	assert.NoError(t, err)
+	_, err = repo.GetUserByEmail(ctx, s.email("lisa"))
+	assert.NoError(t, err)
+
+	errTx := errors.New("some error")
+	err = s.storage.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		_, err := repo.Create(ctx, domain.User{Email: s.email("kate")})
+		assert.NoError(t, err)
+		assert.NoError(t, repo.Update(ctx, domain.User{Base: domain.Base{Id: ids["john"]}, Email: s.email("john.new")}))
+		return errTx
+	})
+	assert.Equal(t, errTx, err)
+
+	// a failed transaction leaves nothing behind
+	_, err = repo.GetUserByEmail(ctx, s.email("kate"))
+	assert.Equal(t, domain.ErrRecordNotFound, err)
+	user, err := repo.GetUserByEmail(ctx, s.email("john"))
+	assert.NoError(t, err)
+	assert.Equal(t, ids["john"], user.Base.Id)
+}
+
+func testUser(t *testing.T, s suite) {
+	ctx := context.Background()
+	repo := s.storage.UserRepo
+
+	id, err := repo.Create(ctx, domain.User{Email: s.email("john"), Password: "hash"})
+	assert.NoError(t, err)
+	_, err = repo.Create(ctx, domain.User{Email: s.email("john")})
+	assert.Error(t, err)
+
+	user, err := repo.GetUserByEmail(ctx, s.email("john"))
+	assert.NoError(t, err)
+	assert.Equal(t, id, user.Base.Id)
+	assert.Empty(t, user.Password)
+	hash, err := repo.GetPasswordHash(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, "hash", hash)
+	assert.NoError(t, repo.SetPasswordHash(ctx, id, "new hash"))
+	hash, err = repo.GetPasswordHash(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, "new hash", hash)
+	assert.Equal(t, domain.ErrUpdateRecordNotFound, repo.SetPasswordHash(ctx, util.GenUUID(), "hash"))
+
+	ids := s.users(t, ctx, "lisa")
+	ids["john"] = id
+	result, err := repo.GetUserIDsByEmails(ctx, s.emails("john", "lisa"))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{s.email("john"): id, s.email("lisa"): ids["lisa"]}, result)
+	_, err = repo.GetUserIDsByEmails(ctx, s.emails("john", "unknown"))
+	assert.Equal(t, domain.ErrNotFoundUserByEmail, err)
+
+	assert.NoError(t, repo.Update(ctx, domain.User{Base: domain.Base{Id: id}, Email: s.email("john.new")}))
+	assert.Equal(t, domain.ErrUpdateRecordNotFound, repo.Update(ctx, domain.User{Base: domain.Base{Id: util.GenUUID()}, Email: s.email("kate")}))
+	emails, err := repo.GetEmailsByUserIDs(ctx, []string{id})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{id: s.email("john.new")}, emails)
+
+	// the friendship keeps the users from being deleted
+	s.friendships(t, ctx, ids, domain.FriendshipStatusFriended, [2]string{"john", "lisa"})
+	assert.Equal(t, domain.ErrUserHasRelations, repo.Delete(ctx, ids["lisa"]))
+
+	other := s.users(t, ctx, "kate")
+	assert.NoError(t, repo.Delete(ctx, other["kate"]))
+	assert.Equal(t, domain.ErrRecordNotFound, repo.Delete(ctx, other["kate"]))
+	_, err = repo.GetUserByEmail(ctx, s.email("kate"))
+	assert.Equal(t, domain.ErrRecordNotFound, err)
+}
+
+func testFriendship(t *testing.T, s suite) {
+	ctx := context.Background()
+	repo := s.storage.FriendshipRepo
+	ids := s.users(t, ctx, "john", "lisa")
+
+	_, err := repo.Create(ctx, domain.Friendship{UserID: ids["john"], FriendID: util.GenUUID()})
+	assert.Error(t, err)
+
+	id, err := repo.Create(ctx, domain.Friendship{UserID: ids["john"], FriendID: ids["lisa"], Status: domain.FriendshipStatusPending})
+	assert.NoError(t, err)
+	_, err = repo.GetFriendshipByUserIDs(ctx, ids["john"], util.GenUUID())
+	assert.Equal(t, domain.ErrRecordNotFound, err)
+
+	// either side finds the friendship
+	friendship, err := repo.GetFriendshipByUserIDs(ctx, ids["lisa"], ids["john"])
+	assert.NoError(t, err)
+	assert.Equal(t, id, friendship.Id)
+	emails, err := repo.GetFriendRequestEmails(ctx, ids["john"], domain.FriendRequestDirectionOutgoing)
+	assert.NoError(t, err)
+	assert.Equal(t, s.emails("lisa"), emails)
+
+	assert.NoError(t, repo.UpdateStatus(ctx, id, domain.FriendshipStatusFriended))
+	friendship, err = repo.GetFriendshipByUserIDs(ctx, ids["john"], ids["lisa"])
+	assert.NoError(t, err)
+	assert.Equal(t, domain.FriendshipStatusFriended, friendship.Status)
+
+	friendship.UserID, friendship.FriendID, friendship.Status = friendship.FriendID, friendship.UserID, domain.FriendshipStatusPending
+	assert.NoError(t, repo.Update(ctx, friendship))
+	emails, err = repo.GetFriendRequestEmails(ctx, ids["john"], domain.FriendRequestDirectionIncoming)
+	assert.NoError(t, err)
+	assert.Equal(t, s.emails("lisa"), emails)
+	emails, err = repo.GetFriendRequestEmails(ctx, ids["john"], domain.FriendRequestDirectionOutgoing)
+	assert.NoError(t, err)
+	assert.Empty(t, emails)
+
+	_, err = repo.GetFriendRequestEmails(ctx, ids["john"], domain.FriendRequestDirectionInvalid)
+	assert.Equal(t, domain.ErrFriendRequestDirectionIsNotValid, err)
+}
+
+func testFriendshipPage(t *testing.T, s suite) {
+	ctx := context.Background()
+	repo := s.storage.FriendshipRepo
+	ids := s.users(t, ctx, "a", "b", "c", "d", "e")
+	s.friendships(t, ctx, ids, domain.FriendshipStatusFriended,
+		[2]string{"a", "d"},
+		[2]string{"a", "c"},
+		[2]string{"e", "a"},
+		[2]string{"b", "c"},
+	)
+	a := map[string]string{s.email("a"): ids["a"]}
+
+	page := domain.Page{Limit: 2}
+	emails, cursor, err := repo.GetFriendshipByUserIDAndStatus(ctx, a, page, domain.FriendshipStatusFriended)
+	assert.NoError(t, err)
+	assert.Equal(t, s.emails("c", "d"), emails)
+	assert.NotEmpty(t, cursor)
+
+	page.Cursor = cursor
+	emails, cursor, err = repo.GetFriendshipByUserIDAndStatus(ctx, a, page, domain.FriendshipStatusFriended)
+	assert.NoError(t, err)
+	assert.Equal(t, s.emails("e"), emails)
+	assert.Empty(t, cursor)
+
+	// sorted by the time of the friendship
+	page = domain.Page{Limit: 2, Sort: domain.PageSortCreatedAt}
+	emails, cursor, err = repo.GetFriendshipByUserIDAndStatus(ctx, a, page, domain.FriendshipStatusFriended)
+	assert.NoError(t, err)
+	assert.Equal(t, s.emails("d", "c"), emails)
+	page.Cursor = cursor
+	emails, _, err = repo.GetFriendshipByUserIDAndStatus(ctx, a, page, domain.FriendshipStatusFriended)
+	assert.NoError(t, err)
+	assert.Equal(t, s.emails("e"), emails)
+
+	// the friendships made from the given time
+	emails, _, err = repo.GetFriendshipByUserIDAndStatus(ctx, a, domain.Page{Since: time.Date(2023, 3, 2, 0, 0, 0, 0, time.UTC)}, domain.FriendshipStatusFriended)
+	assert.NoError(t, err)
+	assert.Equal(t, s.emails("c", "e"), emails)
+
+	// mutual friends
+	mutual := map[string]string{s.email("a"): ids["a"], s.email("b"): ids["b"]}
+	emails, _, err = repo.GetFriendshipByUserIDAndStatus(ctx, mutual, domain.Page{}, domain.FriendshipStatusFriended)
+	assert.NoError(t, err)
+	assert.Equal(t, s.emails("c"), emails)
+
+	_, _, err = repo.GetFriendshipByUserIDAndStatus(ctx, map[string]string{s.email("d"): ids["d"]}, domain.Page{}, domain.FriendshipStatusPending)
+	assert.Equal(t, domain.ErrRecordNotFound, err)
+	_, _, err = repo.GetFriendshipByUserIDAndStatus(ctx, a, domain.Page{Cursor: "not a cursor"}, domain.FriendshipStatusFriended)
+	assert.Equal(t, domain.ErrCursorIsNotValid, err)
+}
+
+func testFriendSuggestionsAndSet(t *testing.T, s suite) {
+	ctx := context.Background()
+	repo := s.storage.FriendshipRepo
+	ids := s.users(t, ctx, "a", "b", "c", "d", "e")
+	s.friendships(t, ctx, ids, domain.FriendshipStatusFriended,
+		[2]string{"a", "b"},
+		[2]string{"a", "c"},
+		[2]string{"b", "d"},
+		[2]string{"c", "d"},
+		[2]string{"c", "e"},
+	)
+
+	suggestions, err := repo.GetFriendSuggestions(ctx, ids["a"], 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.FriendSuggestion{
+		{Email: s.email("d"), MutualFriends: 2},
+		{Email: s.email("e"), MutualFriends: 1},
+	}, suggestions)
+
+	_, err = s.storage.BlockRepo.UpsertBlock(ctx, domain.Block{UserID: ids["e"], TargetID: ids["a"]})
+	assert.NoError(t, err)
+	suggestions, err = repo.GetFriendSuggestions(ctx, ids["a"], 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.FriendSuggestion{{Email: s.email("d"), MutualFriends: 2}}, suggestions)
+
+	userIDs := []string{ids["b"], ids["c"]}
+	set, err := repo.GetFriendSet(ctx, userIDs, domain.FriendSetOperationIntersection)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.FriendSetMember{{Email: s.email("a"), ConnectedUsers: 2}, {Email: s.email("d"), ConnectedUsers: 2}}, set)
+
+	set, err = repo.GetFriendSet(ctx, userIDs, domain.FriendSetOperationUnion)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.FriendSetMember{
+		{Email: s.email("a"), ConnectedUsers: 2},
+		{Email: s.email("d"), ConnectedUsers: 2},
+		{Email: s.email("e"), ConnectedUsers: 1},
+	}, set)
+
+	set, err = repo.GetFriendSet(ctx, []string{ids["c"], ids["b"]}, domain.FriendSetOperationDifference)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.FriendSetMember{{Email: s.email("e"), ConnectedUsers: 1}}, set)
+
+	_, err = repo.GetFriendSet(ctx, userIDs, domain.FriendSetOperation("xor"))
+	assert.Equal(t, domain.ErrFriendSetOperationIsNotValid, err)
+}
+
+func testShortestFriendPath(t *testing.T, s suite) {
+	ctx := context.Background()
+	repo := s.storage.FriendshipRepo
+	ids := s.users(t, ctx, "a", "b", "c", "d", "e")
+	s.friendships(t, ctx, ids, domain.FriendshipStatusFriended,
+		[2]string{"a", "b"},
+		[2]string{"b", "c"},
+		[2]string{"c", "d"},
+		[2]string{"a", "e"},
+		[2]string{"e", "d"},
+	)
+
+	path, err := repo.GetShortestFriendPath(ctx, ids["a"], ids["d"], 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{ids["a"], ids["e"], ids["d"]}, path)
+
+	// a block hides the friendship
+	_, err = s.storage.BlockRepo.UpsertBlock(ctx, domain.Block{UserID: ids["d"], TargetID: ids["e"]})
+	assert.NoError(t, err)
+	path, err = repo.GetShortestFriendPath(ctx, ids["a"], ids["d"], 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{ids["a"], ids["b"], ids["c"], ids["d"]}, path)
+
+	_, err = repo.GetShortestFriendPath(ctx, ids["a"], ids["d"], 2)
+	assert.Equal(t, domain.ErrRecordNotFound, err)
+
+	path, err = repo.GetShortestFriendPath(ctx, ids["a"], ids["a"], 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{ids["a"]}, path)
+}
+
+func testSubscription(t *testing.T, s suite) {
+	ctx := context.Background()
+	repo := s.storage.SubscriptionRepo
+	ids := s.users(t, ctx, "john", "lisa")
+
+	sub := domain.Subscription{UserID: ids["john"], SubscriberID: ids["lisa"], Status: domain.SubscriptionStatusSubscribed}
+	id, err := repo.Create(ctx, sub)
+	assert.NoError(t, err)
+	_, err = repo.Create(ctx, sub)
+	assert.Error(t, err)
+
+	// the upsert keeps the row of the same users
+	sub.Status = domain.SubscriptionStatusUnsubscribed
+	upsertedID, err := repo.UpsertSubscription(ctx, sub)
+	assert.NoError(t, err)
+	assert.Equal(t, id, upsertedID)
+
+	result, err := repo.GetSubscription(ctx, domain.Subscriptions{sub, {UserID: sub.SubscriberID, SubscriberID: sub.UserID}})
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, domain.SubscriptionStatusUnsubscribed, result[0].Status)
+
+	assert.NoError(t, repo.UpdateStatus(ctx, id, domain.SubscriptionStatusSubscribed))
+	emails, cursor, err := repo.GetSubscriberEmails(ctx, ids["john"], domain.Page{})
+	assert.NoError(t, err)
+	assert.Equal(t, s.emails("lisa"), emails)
+	assert.Empty(t, cursor)
+
+	assert.NoError(t, repo.Delete(ctx, id))
+	result, err = repo.GetSubscription(ctx, domain.Subscriptions{sub})
+	assert.NoError(t, err)
+	assert.Empty(t, result)
+}
+
+func testSubscriptionEmails(t *testing.T, s suite) {
+	ctx := context.Background()
+	repo := s.storage.SubscriptionRepo
+	ids := s.users(t, ctx, "john", "lisa", "kate", "mike", "anna")
+
+	_, err := repo.Create(ctx, domain.Subscription{UserID: ids["john"], SubscriberID: ids["lisa"], Status: domain.SubscriptionStatusSubscribed})
+	assert.NoError(t, err)
+	_, err = repo.Create(ctx, domain.Subscription{UserID: ids["john"], SubscriberID: ids["anna"], Status: domain.SubscriptionStatusSubscribed})
+	assert.NoError(t, err)
+	_, err = repo.Create(ctx, domain.Subscription{UserID: ids["john"], SubscriberID: ids["mike"], Status: domain.SubscriptionStatusUnsubscribed})
+	assert.NoError(t, err)
+
+	// the subscribers and the mentioned users, unless they unsubscribed
+	emails, cursor, err := repo.GetSubscriptionEmailsByUserIDAndEmails(ctx, ids["john"], s.emails("kate", "mike"), domain.Page{Limit: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, s.emails("anna", "kate"), emails)
+	emails, cursor, err = repo.GetSubscriptionEmailsByUserIDAndEmails(ctx, ids["john"], s.emails("kate", "mike"), domain.Page{Limit: 2, Cursor: cursor})
+	assert.NoError(t, err)
+	assert.Equal(t, s.emails("lisa"), emails)
+	assert.Empty(t, cursor)
+
+	emails, _, err = repo.GetSubscriptionEmailsByUserIDAndEmails(ctx, ids["john"], nil, domain.Page{})
+	assert.NoError(t, err)
+	assert.Equal(t, s.emails("anna", "lisa"), emails)
+
+	_, _, err = repo.GetSubscriptionEmailsByUserIDAndEmails(ctx, ids["john"], nil, domain.Page{Cursor: "not a cursor"})
+	assert.Equal(t, domain.ErrCursorIsNotValid, err)
+}
+
+func testBlock(t *testing.T, s suite) {
+	ctx := context.Background()
+	repo := s.storage.BlockRepo
+	ids := s.users(t, ctx, "john", "lisa", "kate")
+
+	block := domain.Block{UserID: ids["john"], TargetID: ids["lisa"], FriendshipStatus: domain.FriendshipStatusFriended, SubscriptionStatus: domain.SubscriptionStatusSubscribed}
+	id, err := repo.UpsertBlock(ctx, block)
+	assert.NoError(t, err)
+
+	// blocking again keeps the same row with the latest state
+	block.FriendshipStatus = domain.FriendshipStatusUnfriended
+	upsertedID, err := repo.UpsertBlock(ctx, block)
+	assert.NoError(t, err)
+	assert.Equal(t, id, upsertedID)
+	result, err := repo.GetBlock(ctx, block.UserID, block.TargetID)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.FriendshipStatusUnfriended, result.FriendshipStatus)
+	assert.Equal(t, domain.SubscriptionStatusSubscribed, result.SubscriptionStatus)
+
+	// a block is one-way
+	_, err = repo.GetBlock(ctx, block.TargetID, block.UserID)
+	assert.Equal(t, domain.ErrRecordNotFound, err)
+
+	// the latest block first
+	_, err = repo.UpsertBlock(ctx, domain.Block{UserID: ids["kate"], TargetID: ids["lisa"]})
+	assert.NoError(t, err)
+	blockers, err := repo.GetBlockerEmailsByTargetID(ctx, ids["lisa"])
+	assert.NoError(t, err)
+	if assert.Len(t, blockers, 2) {
+		assert.Equal(t, s.emails("kate", "john"), []string{blockers[0].Email, blockers[1].Email})
+	}
+	blocked, err := repo.GetBlockedEmailsByUserID(ctx, ids["john"])
+	assert.NoError(t, err)
+	if assert.Len(t, blocked, 1) {
+		assert.Equal(t, s.email("lisa"), blocked[0].Email)
+		assert.True(t, result.UpdatedAt.Equal(blocked[0].BlockedAt))
+	}
+
+	assert.NoError(t, repo.Delete(ctx, id))
+	_, err = repo.GetBlock(ctx, block.UserID, block.TargetID)
+	assert.Equal(t, domain.ErrRecordNotFound, err)
+}
+
+func testListByUser(t *testing.T, s suite) {
+	ctx := context.Background()
+	ids := s.users(t, ctx, "john", "lisa", "kate", "mike")
+	s.friendships(t, ctx, ids, domain.FriendshipStatusFriended, [2]string{"john", "lisa"}, [2]string{"lisa", "kate"})
+	s.friendships(t, ctx, ids, domain.FriendshipStatusPending, [2]string{"kate", "john"})
+
+	for _, sub := range []domain.Subscription{
+		{UserID: ids["lisa"], SubscriberID: ids["john"], Status: domain.SubscriptionStatusSubscribed},
+		{UserID: ids["john"], SubscriberID: ids["kate"], Status: domain.SubscriptionStatusUnsubscribed},
+		{UserID: ids["kate"], SubscriberID: ids["lisa"], Status: domain.SubscriptionStatusSubscribed},
+	} {
+		_, err := s.storage.SubscriptionRepo.Create(ctx, sub)
+		assert.NoError(t, err)
+	}
+	for _, pair := range [][2]string{{"john", "mike"}, {"mike", "john"}} {
+		_, err := s.storage.BlockRepo.UpsertBlock(ctx, domain.Block{UserID: ids[pair[0]], TargetID: ids[pair[1]]})
+		assert.NoError(t, err)
+	}
+
+	// walking the rows one at a time gives every row of the user once, in the order of the ids
+	var friendships domain.Friendships
+	for afterID := ""; ; {
+		list, err := s.storage.FriendshipRepo.ListFriendshipsByUserID(ctx, ids["john"], afterID, 1)
+		assert.NoError(t, err)
+		if len(list) == 0 {
+			break
+		}
+		friendships = append(friendships, list...)
+		afterID = list[len(list)-1].Id
+	}
+	if assert.Len(t, friendships, 2) {
+		assert.Less(t, friendships[0].Id, friendships[1].Id)
+		for _, f := range friendships {
+			assert.True(t, f.UserID == ids["john"] || f.FriendID == ids["john"])
+			assert.False(t, f.CreatedAt.IsZero())
+		}
+	}
+
+	subscriptions, err := s.storage.SubscriptionRepo.ListSubscriptionsByUserID(ctx, ids["john"], "", 10)
+	assert.NoError(t, err)
+	if assert.Len(t, subscriptions, 2) {
+		assert.Less(t, subscriptions[0].Id, subscriptions[1].Id)
+		rest, err := s.storage.SubscriptionRepo.ListSubscriptionsByUserID(ctx, ids["john"], subscriptions[0].Id, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, subscriptions[1:], rest)
+	}
+
+	// the blocks against the user are not its own
+	blocks, err := s.storage.BlockRepo.ListBlocksByUserID(ctx, ids["john"], "", 10)
+	assert.NoError(t, err)
+	if assert.Len(t, blocks, 1) {
+		assert.Equal(t, ids["mike"], blocks[0].TargetID)
+	}
+}
+
+func testAccountDeletion(t *testing.T, s suite) {
+	ctx := context.Background()
+	repo := s.storage.UserRepo
+	ids := s.users(t, ctx, "john", "lisa", "kate", "mike")
+	s.friendships(t, ctx, ids, domain.FriendshipStatusFriended, [2]string{"john", "lisa"}, [2]string{"lisa", "kate"})
+	s.friendships(t, ctx, ids, domain.FriendshipStatusPending, [2]string{"kate", "john"})
+	for _, sub := range []domain.Subscription{
+		{UserID: ids["lisa"], SubscriberID: ids["john"], Status: domain.SubscriptionStatusSubscribed},
+		{UserID: ids["kate"], SubscriberID: ids["lisa"], Status: domain.SubscriptionStatusSubscribed},
+	} {
+		_, err := s.storage.SubscriptionRepo.Create(ctx, sub)
+		assert.NoError(t, err)
+	}
+	for _, pair := range [][2]string{{"mike", "john"}, {"mike", "kate"}} {
+		_, err := s.storage.BlockRepo.UpsertBlock(ctx, domain.Block{UserID: ids[pair[0]], TargetID: ids[pair[1]]})
+		assert.NoError(t, err)
+	}
+
+	now := time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+	for name, purgeAt := range map[string]time.Time{"john": now.Add(-time.Hour), "lisa": now.Add(-2 * time.Hour), "kate": now.Add(time.Hour)} {
+		assert.NoError(t, repo.ScheduleDeletion(ctx, domain.UserDeletion{UserID: ids[name], RequestedAt: now.Add(-24 * time.Hour), PurgeAt: purgeAt}))
+	}
+	assert.Error(t, repo.ScheduleDeletion(ctx, domain.UserDeletion{UserID: ids["john"], RequestedAt: now, PurgeAt: now}))
+	assert.Error(t, repo.ScheduleDeletion(ctx, domain.UserDeletion{UserID: "missing", RequestedAt: now, PurgeAt: now}))
+
+	deletion, err := repo.GetDeletion(ctx, ids["john"])
+	assert.NoError(t, err)
+	assert.Equal(t, domain.UserDeletion{UserID: ids["john"], RequestedAt: now.Add(-24 * time.Hour), PurgeAt: now.Add(-time.Hour)}, deletion)
+	_, err = repo.GetDeletion(ctx, ids["mike"])
+	assert.Equal(t, domain.ErrRecordNotFound, err)
+
+	// the due deletions come the earliest first
+	due, err := repo.GetDueDeletions(ctx, now, 10)
+	assert.NoError(t, err)
+	if assert.Len(t, due, 2) {
+		assert.Equal(t, ids["lisa"], due[0].UserID)
+		assert.Equal(t, ids["john"], due[1].UserID)
+	}
+	due, err = repo.GetDueDeletions(ctx, now, 1)
+	assert.NoError(t, err)
+	assert.Len(t, due, 1)
+
+	assert.NoError(t, repo.CancelDeletion(ctx, ids["lisa"]))
+	assert.Equal(t, domain.ErrRecordNotFound, repo.CancelDeletion(ctx, ids["lisa"]))
+
+	// purging john leaves the rows between the other users alone
+	err = s.storage.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		assert.NoError(t, s.storage.FriendshipRepo.DeleteByUserID(ctx, ids["john"]))
+		assert.NoError(t, s.storage.SubscriptionRepo.DeleteByUserID(ctx, ids["john"]))
+		assert.NoError(t, s.storage.BlockRepo.DeleteByUserID(ctx, ids["john"]))
+		return repo.Anonymize(ctx, ids["john"], domain.UserTombstoneEmail(ids["john"]))
+	})
+	assert.NoError(t, err)
+
+	friendships, err := s.storage.FriendshipRepo.ListFriendshipsByUserID(ctx, ids["john"], "", 10)
+	assert.NoError(t, err)
+	assert.Empty(t, friendships)
+	friendships, err = s.storage.FriendshipRepo.ListFriendshipsByUserID(ctx, ids["lisa"], "", 10)
+	assert.NoError(t, err)
+	assert.Len(t, friendships, 1)
+	subscriptions, err := s.storage.SubscriptionRepo.ListSubscriptionsByUserID(ctx, ids["lisa"], "", 10)
+	assert.NoError(t, err)
+	assert.Len(t, subscriptions, 1)
+	blocks, err := s.storage.BlockRepo.ListBlocksByUserID(ctx, ids["mike"], "", 10)
+	assert.NoError(t, err)
+	if assert.Len(t, blocks, 1) {
+		assert.Equal(t, ids["kate"], blocks[0].TargetID)
+	}
+
+	// the email is free again and the tombstone takes its place, the deletion is gone with the purge
+	_, err = repo.GetUserByEmail(ctx, s.email("john"))
+	assert.Equal(t, domain.ErrRecordNotFound, err)
+	user, err := repo.GetUserByEmail(ctx, domain.UserTombstoneEmail(ids["john"]))
+	assert.NoError(t, err)
+	assert.Equal(t, ids["john"], user.Base.Id)
+	password, err := repo.GetPasswordHash(ctx, ids["john"])
+	assert.NoError(t, err)
+	assert.Empty(t, password)
+	_, err = repo.GetDeletion(ctx, ids["john"])
+	assert.Equal(t, domain.ErrRecordNotFound, err)
+	assert.Equal(t, domain.ErrUpdateRecordNotFound, repo.Anonymize(ctx, "missing", domain.UserTombstoneEmail("missing")))
+
+	// the scheduled deletion goes with the user
+	assert.NoError(t, s.storage.FriendshipRepo.DeleteByUserID(ctx, ids["kate"]))
+	assert.NoError(t, s.storage.SubscriptionRepo.DeleteByUserID(ctx, ids["kate"]))
+	assert.NoError(t, s.storage.BlockRepo.DeleteByUserID(ctx, ids["kate"]))
+	assert.NoError(t, repo.Delete(ctx, ids["kate"]))
+	_, err = repo.GetDeletion(ctx, ids["kate"])
+	assert.Equal(t, domain.ErrRecordNotFound, err)
+}
+
+func testFeed(t *testing.T, s suite) {
+	ctx := context.Background()
+	repo := s.storage.UpdateRepo
+	ids := s.users(t, ctx, "john", "lisa", "kate", "mike", "anna")
+
+	_, err := s.storage.SubscriptionRepo.Create(ctx, domain.Subscription{UserID: ids["lisa"], SubscriberID: ids["john"], Status: domain.SubscriptionStatusSubscribed})
+	assert.NoError(t, err)
+	_, err = s.storage.SubscriptionRepo.Create(ctx, domain.Subscription{UserID: ids["anna"], SubscriberID: ids["john"], Status: domain.SubscriptionStatusUnsubscribed})
+	assert.NoError(t, err)
+	_, err = s.storage.BlockRepo.UpsertBlock(ctx, domain.Block{UserID: ids["john"], TargetID: ids["mike"]})
+	assert.NoError(t, err)
+
+	now := time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+	post := func(name, text string, mentions []string, createdAt time.Time) string {
+		id, err := repo.Create(ctx, domain.Update{Base: domain.Base{CreatedAt: createdAt}, UserID: ids[name], Text: text, Mentions: mentions})
+		assert.NoError(t, err)
+		return id
+	}
+	subscribed := post("lisa", "subscribed", nil, now)
+	mentioned := post("kate", "hello john", s.emails("john"), now.Add(time.Minute))
+	post("kate", "not for john", nil, now.Add(2*time.Minute))
+	post("mike", "blocked john", s.emails("john"), now.Add(3*time.Minute))
+	post("anna", "unsubscribed john", s.emails("john"), now.Add(4*time.Minute))
+	post("john", "own update", nil, now.Add(5*time.Minute))
+
+	feed, cursor, err := repo.GetFeed(ctx, ids["john"], domain.Page{Limit: 1})
+	assert.NoError(t, err)
+	if assert.Len(t, feed, 1) {
+		assert.Equal(t, domain.FeedItem{ID: mentioned, Sender: s.email("kate"), Text: "hello john"}, domain.FeedItem{ID: feed[0].ID, Sender: feed[0].Sender, Text: feed[0].Text})
+		assert.True(t, now.Add(time.Minute).Equal(feed[0].CreatedAt))
+	}
+	assert.NotEmpty(t, cursor)
+
+	feed, cursor, err = repo.GetFeed(ctx, ids["john"], domain.Page{Limit: 1, Cursor: cursor})
+	assert.NoError(t, err)
+	if assert.Len(t, feed, 1) {
+		assert.Equal(t, subscribed, feed[0].ID)
+		assert.Equal(t, s.email("lisa"), feed[0].Sender)
+	}
+	assert.Empty(t, cursor)
+
+	// the updates from the given time
+	feed, _, err = repo.GetFeed(ctx, ids["john"], domain.Page{Since: now.Add(time.Second)})
+	assert.NoError(t, err)
+	assert.Len(t, feed, 1)
+
+	// the updates kate posted, a chunk at a time
+	updates, err := repo.ListUpdatesByUserID(ctx, ids["kate"], "", 1)
+	assert.NoError(t, err)
+	if assert.Len(t, updates, 1) {
+		assert.Equal(t, ids["kate"], updates[0].UserID)
+		more, err := repo.ListUpdatesByUserID(ctx, ids["kate"], updates[0].Id, 10)
+		assert.NoError(t, err)
+		if assert.Len(t, more, 1) {
+			updates = append(updates, more[0])
+		}
+	}
+	byID := make(map[string]domain.Update, len(updates))
+	for _, up := range updates {
+		byID[up.Id] = up
+	}
+	assert.Equal(t, "hello john", byID[mentioned].Text)
+	assert.Equal(t, s.emails("john"), byID[mentioned].Mentions)
+	assert.True(t, now.Add(time.Minute).Equal(byID[mentioned].CreatedAt))
+
+	// without the mention john only receives the update of lisa
+	assert.NoError(t, repo.RemoveMentions(ctx, s.email("john")))
+	feed, _, err = repo.GetFeed(ctx, ids["john"], domain.Page{})
+	assert.NoError(t, err)
+	if assert.Len(t, feed, 1) {
+		assert.Equal(t, subscribed, feed[0].ID)
+	}
+}
+
+func testEvent(t *testing.T, s suite) {
+	ctx := context.Background()
+	repo := s.storage.EventRepo
+
+	// other undelivered events may exist in a shared storage, only the ones of the test are checked
+	ids := make([]string, 0, 3)
+	for _, name := range []string{"c", "a", "b"} {
+		e, err := domain.NewEvent(domain.EventUserCreated, s.email(name), domain.UserEvent{Email: s.email(name)})
+		assert.NoError(t, err)
+		id, err := repo.Create(ctx, e)
+		assert.NoError(t, err)
+		ids = append(ids, id)
+	}
+	undelivered := func() []domain.Event {
+		var result []domain.Event
+		err := s.storage.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			events, err := repo.GetUndelivered(ctx, 1000)
+			for _, e := range events {
+				for _, id := range ids {
+					if e.ID == id {
+						result = append(result, e)
+					}
+				}
+			}
+			return err
+		})
+		assert.NoError(t, err)
+		return result
+	}
+
+	// in the order they were written
+	events := undelivered()
+	if assert.Len(t, events, 3) {
+		assert.Equal(t, ids, []string{events[0].ID, events[1].ID, events[2].ID})
+		assert.Equal(t, domain.EventUserCreated, events[0].Type)
+		assert.Equal(t, s.email("c"), events[0].AggregateID)
+		assert.JSONEq(t, `{"email":"`+s.email("c")+`"}`, string(events[0].Payload))
+	}
+
+	assert.NoError(t, repo.MarkDelivered(ctx, ids[:2]))
+	assert.NoError(t, repo.MarkDelivered(ctx, nil))
+	events = undelivered()
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, ids[2], events[0].ID)
+	}
+}
+
+func testWebhook(t *testing.T, s suite) {
+	ctx := context.Background()
+	repo := s.storage.WebhookRepo
+	eventTypes := []domain.EventType{domain.EventUserBlocked, domain.EventFriendshipConnected}
+
+	webhookID, err := repo.Create(ctx, domain.Webhook{URL: "http://example.com/" + s.ns, Secret: "0123456789abcdef", EventTypes: eventTypes})
+	assert.NoError(t, err)
+
+	// other webhooks may exist in a shared storage
+	find := func(list []domain.Webhook) *domain.Webhook {
+		for _, w := range list {
+			if w.Id == webhookID {
+				return &w
+			}
+		}
+		return nil
+	}
+	webhooks, err := repo.List(ctx)
+	assert.NoError(t, err)
+	if w := find(webhooks); assert.NotNil(t, w) {
+		assert.Equal(t, "http://example.com/"+s.ns, w.URL)
+		assert.Equal(t, eventTypes, w.EventTypes)
+	}
+	webhooks, err = repo.GetByEventType(ctx, domain.EventFriendshipConnected)
+	assert.NoError(t, err)
+	assert.NotNil(t, find(webhooks))
+	webhooks, err = repo.GetByEventType(ctx, domain.EventUserCreated)
+	assert.NoError(t, err)
+	assert.Nil(t, find(webhooks))
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	delivery := domain.WebhookDelivery{
+		WebhookID:     webhookID,
+		EventID:       util.GenUUID(),
+		EventType:     domain.EventUserBlocked,
+		Payload:       json.RawMessage(`{"email":"john@example.com"}`),
+		Status:        domain.WebhookDeliveryStatusPending,
+		NextAttemptAt: now,
+	}
+	delivery.Id, err = repo.CreateDelivery(ctx, delivery)
+	assert.NoError(t, err)
+	// the same event is queued once
+	_, err = repo.CreateDelivery(ctx, delivery)
+	assert.NoError(t, err)
+
+	result, err := repo.GetDelivery(ctx, delivery.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, delivery.EventID, result.EventID)
+	assert.JSONEq(t, string(delivery.Payload), string(result.Payload))
+	assert.True(t, now.Equal(result.NextAttemptAt))
+
+	var due []domain.DueWebhookDelivery
+	err = s.storage.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		list, err := repo.GetDueDeliveries(ctx, now, 1000)
+		for _, d := range list {
+			if d.Delivery.WebhookID == webhookID {
+				due = append(due, d)
+			}
+		}
+		return err
+	})
+	assert.NoError(t, err)
+	if assert.Len(t, due, 1) {
+		assert.Equal(t, delivery.Id, due[0].Delivery.Id)
+		assert.Equal(t, "0123456789abcdef", due[0].Webhook.Secret)
+	}
+
+	delivery.Status = domain.WebhookDeliveryStatusDead
+	delivery.Attempts = 8
+	delivery.LastError = "unexpected status 500"
+	assert.NoError(t, repo.UpdateDelivery(ctx, delivery))
+	dead, err := repo.GetDeliveriesByStatus(ctx, webhookID, domain.WebhookDeliveryStatusDead)
+	assert.NoError(t, err)
+	if assert.Len(t, dead, 1) {
+		assert.Equal(t, 8, dead[0].Attempts)
+		assert.Equal(t, "unexpected status 500", dead[0].LastError)
+	}
+	pending, err := repo.GetDeliveriesByStatus(ctx, webhookID, domain.WebhookDeliveryStatusPending)
+	assert.NoError(t, err)
+	assert.Empty(t, pending)
+
+	// deleting the webhook deletes its deliveries
+	assert.NoError(t, repo.Delete(ctx, webhookID))
+	assert.Equal(t, domain.ErrRecordNotFound, repo.Delete(ctx, webhookID))
+	_, err = repo.GetDelivery(ctx, delivery.Id)
+	assert.Equal(t, domain.ErrRecordNotFound, err)
+}
+
+func testAudit(t *testing.T, s suite) {
+	ctx := context.Background()
+	repo := s.storage.AuditRepo
+
+	// the entries are never deleted, the actors and the targets of the test are ids of its own
+	a, b, c := "a-"+s.ns, "b-"+s.ns, "c-"+s.ns
+	createdAt := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+	entries := []domain.AuditEntry{
+		{Actor: a, Action: domain.AuditConnectFriendship, TargetIDs: []string{a, b}, StatusAfter: domain.AuditStatusFriended, RequestID: "request-1", CreatedAt: createdAt},
+		{Actor: b, Action: domain.AuditSubscribeUser, TargetIDs: []string{b, c}, StatusAfter: domain.AuditStatusSubscribed, CreatedAt: createdAt.Add(time.Hour)},
+		{Actor: domain.AuditActorAdmin, Action: domain.AuditDeleteUser, TargetIDs: []string{a}, StatusBefore: domain.AuditStatusActive, CreatedAt: createdAt.Add(2 * time.Hour)},
+		{Actor: a, Action: domain.AuditLogin, TargetIDs: []string{a}, StatusBefore: domain.AuditStatusActive, StatusAfter: domain.AuditStatusActive, CreatedAt: createdAt.Add(3 * time.Hour)},
+		{Actor: a, Action: domain.AuditLogin, TargetIDs: []string{a}, StatusBefore: domain.AuditStatusActive, StatusAfter: domain.AuditStatusActive, CreatedAt: createdAt.Add(3 * time.Hour)},
+	}
+	for i := range entries {
+		id, err := repo.Create(ctx, entries[i])
+		assert.NoError(t, err)
+		entries[i].ID = id
+	}
+	// the entries of a failed transaction are not kept
+	errTx := errors.New("some error")
+	err := s.storage.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		_, err := repo.Create(ctx, domain.AuditEntry{Actor: c, Action: domain.AuditLogin, TargetIDs: []string{c}, CreatedAt: createdAt})
+		assert.NoError(t, err)
+		return errTx
+	})
+	assert.Equal(t, errTx, err)
+
+	ids := func(list []domain.AuditEntry) []string {
+		result := make([]string, 0, len(list))
+		for _, e := range list {
+			result = append(result, e.ID)
+		}
+		return result
+	}
+	// the newest first, the entries created at the same time by the id descending
+	newest := []string{entries[3].ID, entries[4].ID}
+	if newest[0] < newest[1] {
+		newest[0], newest[1] = newest[1], newest[0]
+	}
+
+	// the entries acted by the user or targeting them
+	list, nextCursor, err := repo.List(ctx, domain.AuditFilter{UserID: a}, domain.Page{Limit: 3})
+	assert.NoError(t, err)
+	assert.Equal(t, append(newest, entries[2].ID), ids(list))
+	assert.NotEmpty(t, nextCursor)
+	list, nextCursor, err = repo.List(ctx, domain.AuditFilter{UserID: a}, domain.Page{Limit: 3, Cursor: nextCursor})
+	assert.NoError(t, err)
+	if assert.Len(t, list, 1) {
+		assert.Equal(t, entries[0], list[0])
+	}
+	assert.Empty(t, nextCursor)
+
+	list, _, err = repo.List(ctx, domain.AuditFilter{UserID: c}, domain.Page{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{entries[1].ID}, ids(list))
+
+	list, _, err = repo.List(ctx, domain.AuditFilter{UserID: a, Action: domain.AuditLogin}, domain.Page{})
+	assert.NoError(t, err)
+	assert.Equal(t, newest, ids(list))
+
+	// from is inclusive and to is exclusive
+	list, _, err = repo.List(ctx, domain.AuditFilter{UserID: a, From: createdAt, To: createdAt.Add(2 * time.Hour)}, domain.Page{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{entries[0].ID}, ids(list))
+	list, _, err = repo.List(ctx, domain.AuditFilter{UserID: b, From: createdAt.Add(time.Hour)}, domain.Page{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{entries[1].ID}, ids(list))
+
+	_, _, err = repo.List(ctx, domain.AuditFilter{UserID: a}, domain.Page{Cursor: "not-a-cursor"})
+	assert.Error(t, err)
+}
+
+func testIdempotency(t *testing.T, s suite) {
+	ctx := context.Background()
+	repo := s.storage.IdempotencyRepo
+	key := "key-" + s.ns
+	long := time.Now().Add(-time.Hour)
+
+	first, reserved, err := repo.Reserve(ctx, domain.IdempotencyRecord{Scope: "scope-a", Key: key, Fingerprint: "fingerprint-1"}, long, long)
+	assert.NoError(t, err)
+	assert.True(t, reserved)
+	assert.True(t, first.IsInFlight())
+
+	// the key is held while the first request is running, whatever the fingerprint
+	got, reserved, err := repo.Reserve(ctx, domain.IdempotencyRecord{Scope: "scope-a", Key: key, Fingerprint: "fingerprint-2"}, long, long)
+	assert.NoError(t, err)
+	assert.False(t, reserved)
+	assert.Equal(t, "fingerprint-1", got.Fingerprint)
+	assert.True(t, got.IsInFlight())
+
+	// the same key in another scope is another key
+	_, reserved, err = repo.Reserve(ctx, domain.IdempotencyRecord{Scope: "scope-b", Key: key, Fingerprint: "fingerprint-2"}, long, long)
+	assert.NoError(t, err)
+	assert.True(t, reserved)
+
+	first.StatusCode = 200
+	first.ContentType = "application/json"
+	first.Body = []byte(`{"success":true}`)
+	assert.NoError(t, repo.Complete(ctx, first))
+	// a completed record outlives the lease
+	got, reserved, err = repo.Reserve(ctx, domain.IdempotencyRecord{Scope: "scope-a", Key: key, Fingerprint: "fingerprint-1"}, long, time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+	assert.False(t, reserved)
+	assert.Equal(t, "fingerprint-1", got.Fingerprint)
+	assert.Equal(t, 200, got.StatusCode)
+	assert.Equal(t, "application/json", got.ContentType)
+	assert.Equal(t, []byte(`{"success":true}`), got.Body)
+	assert.False(t, got.CreatedAt.IsZero())
+
+	// an expired record gives the key to the next request
+	third, reserved, err := repo.Reserve(ctx, domain.IdempotencyRecord{Scope: "scope-a", Key: key, Fingerprint: "fingerprint-3"}, time.Now().Add(time.Hour), long)
+	assert.NoError(t, err)
+	assert.True(t, reserved)
+	assert.Equal(t, "fingerprint-3", third.Fingerprint)
+	assert.True(t, third.IsInFlight())
+
+	// so does a request running past its lease, the request taken over can neither complete nor release the key
+	fourth, reserved, err := repo.Reserve(ctx, domain.IdempotencyRecord{Scope: "scope-a", Key: key, Fingerprint: "fingerprint-4"}, long, time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+	assert.True(t, reserved)
+	third.StatusCode = 200
+	assert.Equal(t, domain.ErrRecordNotFound, repo.Complete(ctx, third))
+	assert.NoError(t, repo.Release(ctx, third))
+	got, reserved, err = repo.Reserve(ctx, domain.IdempotencyRecord{Scope: "scope-a", Key: key, Fingerprint: "fingerprint-5"}, long, long)
+	assert.NoError(t, err)
+	assert.False(t, reserved)
+	assert.Equal(t, "fingerprint-4", got.Fingerprint)
+
+	// a released key can be reserved again
+	assert.NoError(t, repo.Release(ctx, fourth))
+	_, reserved, err = repo.Reserve(ctx, domain.IdempotencyRecord{Scope: "scope-a", Key: key, Fingerprint: "fingerprint-5"}, long, long)
+	assert.NoError(t, err)
+	assert.True(t, reserved)
+
+	assert.NoError(t, repo.Release(ctx, domain.IdempotencyRecord{Scope: "scope-a", Key: "missing-" + s.ns}))
+	assert.Equal(t, domain.ErrRecordNotFound, repo.Complete(ctx, domain.IdempotencyRecord{Scope: "scope-a", Key: "missing-" + s.ns, StatusCode: 200}))
+
+	// the expired records are deleted, the others are kept
+	_, err = repo.DeleteExpired(ctx, long)
+	assert.NoError(t, err)
+	_, reserved, err = repo.Reserve(ctx, domain.IdempotencyRecord{Scope: "scope-a", Key: key, Fingerprint: "fingerprint-6"}, long, long)
+	assert.NoError(t, err)
+	assert.False(t, reserved)
+	n, err := repo.DeleteExpired(ctx, time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, n, 2)
+	_, reserved, err = repo.Reserve(ctx, domain.IdempotencyRecord{Scope: "scope-b", Key: key, Fingerprint: "fingerprint-6"}, long, long)
+	assert.NoError(t, err)
+	assert.True(t, reserved)
+}
@@ -0,0 +1,195 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	mockRepo "github.com/phantranhieunhan/s3-assignment/mock/friendship/repository"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/webhook"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const secret = "a-secret-of-16-chars"
+
+// receiver is a partner endpoint checking the signature of every request and answering with the given statuses in turn
+type receiver struct {
+	t        *testing.T
+	mu       sync.Mutex
+	statuses []int
+	bodies   []Body
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	assert.NoError(r.t, err)
+
+	timestamp, err := strconv.ParseInt(req.Header.Get(HeaderTimestamp), 10, 64)
+	assert.NoError(r.t, err)
+	assert.Equal(r.t, Sign(secret, timestamp, body), req.Header.Get(HeaderSignature))
+	assert.Equal(r.t, "application/json", req.Header.Get("Content-Type"))
+	assert.NotEmpty(r.t, req.Header.Get(HeaderDelivery))
+	assert.NotEmpty(r.t, req.Header.Get(HeaderEvent))
+
+	var b Body
+	assert.NoError(r.t, json.Unmarshal(body, &b))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.bodies = append(r.bodies, b)
+	status := http.StatusOK
+	if len(r.statuses) > 0 {
+		status, r.statuses = r.statuses[0], r.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+func testDelivery() domain.WebhookDelivery {
+	return domain.WebhookDelivery{
+		Base:      domain.Base{Id: "delivery-id"},
+		WebhookID: "webhook-id",
+		EventID:   "event-id",
+		EventType: domain.EventUserBlocked,
+		Payload:   json.RawMessage(`{"user_id":"user-1","target_id":"user-2"}`),
+		Status:    domain.WebhookDeliveryStatusPending,
+	}
+}
+
+type TestCase_HTTPSender_Send struct {
+	name   string
+	status int
+
+	hasErr bool
+}
+
+func TestHTTPSender_Send(t *testing.T) {
+	t.Parallel()
+
+	tcs := []TestCase_HTTPSender_Send{
+		{
+			name:   "send successfully",
+			status: http.StatusOK,
+		},
+		{
+			name:   "send successfully with any 2xx status",
+			status: http.StatusNoContent,
+		},
+		{
+			name:   "send fail because the receiver responds with an error",
+			status: http.StatusInternalServerError,
+			hasErr: true,
+		},
+		{
+			name:   "send fail because the receiver is not found",
+			status: http.StatusNotFound,
+			hasErr: true,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := &receiver{t: t, statuses: []int{tc.status}}
+			server := httptest.NewServer(rec)
+			defer server.Close()
+
+			err := NewHTTPSender(time.Second).Send(context.Background(), domain.Webhook{URL: server.URL, Secret: secret}, testDelivery())
+			if tc.hasErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, []Body{{
+				ID:      "event-id",
+				Type:    domain.EventUserBlocked,
+				Payload: json.RawMessage(`{"user_id":"user-1","target_id":"user-2"}`),
+			}}, rec.bodies)
+		})
+	}
+}
+
+func TestHTTPSender_Send_Timeout(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	err := NewHTTPSender(50*time.Millisecond).Send(context.Background(), domain.Webhook{URL: server.URL, Secret: secret}, testDelivery())
+	assert.Error(t, err)
+}
+
+func TestSign(t *testing.T) {
+	t.Parallel()
+
+	body := []byte(`{"id":"event-id"}`)
+	assert.Equal(t, Sign(secret, 1700000000, body), Sign(secret, 1700000000, body))
+	assert.NotEqual(t, Sign(secret, 1700000000, body), Sign("another-secret-16-chars", 1700000000, body))
+	assert.NotEqual(t, Sign(secret, 1700000000, body), Sign(secret, 1700000001, body))
+}
+
+// the worker retries a delivery against a receiver failing twice, then the delivery is delivered
+func TestWorker_DeliverToReceiver(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	rec := &receiver{t: t, statuses: []int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK}}
+	server := httptest.NewServer(rec)
+	defer server.Close()
+
+	mockWebhookRepo := new(mockRepo.MockWebhookRepository)
+	w := webhook.NewWorker(mockWebhookRepo, NewHTTPSender(time.Second), transactor{}, webhook.WorkerConfig{
+		BatchSize:   10,
+		MaxAttempts: 5,
+		BackoffBase: time.Millisecond,
+		BackoffMax:  time.Second,
+		Lease:       time.Minute,
+	})
+
+	due := domain.DueWebhookDelivery{
+		Delivery: testDelivery(),
+		Webhook:  domain.Webhook{Base: domain.Base{Id: "webhook-id"}, URL: server.URL, Secret: secret},
+	}
+	var updated []domain.WebhookDelivery
+	for i := 0; i < 3; i++ {
+		mockWebhookRepo.On("GetDueDeliveries", ctx, mock.Anything, 10).Return([]domain.DueWebhookDelivery{due}, nil).Once()
+		// the claim, then the outcome
+		mockWebhookRepo.On("UpdateDelivery", ctx, mock.Anything).Return(nil).Once()
+		mockWebhookRepo.On("UpdateDelivery", ctx, mock.Anything).Run(func(args mock.Arguments) {
+			d := args[1].(domain.WebhookDelivery)
+			updated = append(updated, d)
+			due.Delivery = d
+		}).Return(nil).Once()
+
+		n, err := w.DeliverOnce(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, n)
+	}
+
+	assert.Len(t, rec.bodies, 3)
+	assert.Equal(t, []domain.WebhookDeliveryStatus{
+		domain.WebhookDeliveryStatusPending,
+		domain.WebhookDeliveryStatusPending,
+		domain.WebhookDeliveryStatusDelivered,
+	}, []domain.WebhookDeliveryStatus{updated[0].Status, updated[1].Status, updated[2].Status})
+	assert.Equal(t, "webhook responded with status 503", updated[0].LastError)
+	assert.Equal(t, 3, updated[2].Attempts)
+	assert.Empty(t, updated[2].LastError)
+	mock.AssertExpectationsForObjects(t, mockWebhookRepo)
+}
+
+// transactor runs the function as is and returns its error like a rollback would
+type transactor struct{}
+
+func (transactor) WithinTransaction(ctx context.Context, f func(ctx context.Context) error) error {
+	return f(ctx)
+}
@@ -0,0 +1,73 @@
+package account
+
+import (
+	"context"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+// PurgeAccount purges the account of a user whose deletion is due, each account in a transaction of its own
+type PurgeAccount interface {
+	Handle(ctx context.Context, userID string) error
+}
+
+// Purger polls the deletions whose grace period is over and purges their accounts
+type Purger struct {
+	userRepo  domain.UserRepo
+	purge     PurgeAccount
+	interval  time.Duration
+	batchSize int
+}
+
+func NewPurger(userRepo domain.UserRepo, purge PurgeAccount, interval time.Duration, batchSize int) Purger {
+	return Purger{
+		userRepo:  userRepo,
+		purge:     purge,
+		interval:  interval,
+		batchSize: batchSize,
+	}
+}
+
+// Run purges the due accounts every interval until the context is done
+func (p Purger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// drain the backlog without waiting for the next tick
+			for {
+				n, err := p.PurgeOnce(ctx)
+				if err != nil {
+					logger.Errorf("purger.PurgeOnce %w", err)
+				}
+				if err != nil || n < p.batchSize {
+					break
+				}
+			}
+		}
+	}
+}
+
+// PurgeOnce purges a batch of due accounts. It stops at the first account failing to purge,
+// the account is still due and is retried next time
+func (p Purger) PurgeOnce(ctx context.Context) (int, error) {
+	deletions, err := p.userRepo.GetDueDeletions(ctx, time.Now().UTC(), p.batchSize)
+	if err != nil {
+		logger.Errorf("userRepo.GetDueDeletions %w", err)
+		return 0, err
+	}
+
+	for i, d := range deletions {
+		if err = p.purge.Handle(ctx, d.UserID); err != nil {
+			logger.Errorf("purge.Handle %s %w", d.UserID, err)
+			return i, err
+		}
+	}
+	return len(deletions), nil
+}
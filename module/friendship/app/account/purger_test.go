@@ -0,0 +1,93 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	mockRepo "github.com/phantranhieunhan/s3-assignment/mock/friendship/repository"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// purgeAccount records the accounts purged and fails on the ones given an error
+type purgeAccount struct {
+	purged []string
+	errors map[string]error
+}
+
+func (p *purgeAccount) Handle(ctx context.Context, userID string) error {
+	if err := p.errors[userID]; err != nil {
+		return err
+	}
+	p.purged = append(p.purged, userID)
+	return nil
+}
+
+type TestCase_Purger_PurgeOnce struct {
+	name string
+
+	getDueDeletionsData  []domain.UserDeletion
+	getDueDeletionsError error
+	purgeErrors          map[string]error
+
+	purged []string
+	n      int
+	err    error
+}
+
+func TestPurger_PurgeOnce(t *testing.T) {
+	t.Parallel()
+
+	deletions := []domain.UserDeletion{{UserID: "user-1"}, {UserID: "user-2"}, {UserID: "user-3"}}
+	errDB := errors.New("some error from db")
+
+	tcs := []TestCase_Purger_PurgeOnce{
+		{
+			name:                "purge all due accounts successfully",
+			getDueDeletionsData: deletions,
+			purged:              []string{"user-1", "user-2", "user-3"},
+			n:                   3,
+		},
+		{
+			name:                "purge nothing when no deletion is due",
+			getDueDeletionsData: []domain.UserDeletion{},
+		},
+		{
+			name:                 "purge nothing when listing the deletions fail",
+			getDueDeletionsData:  []domain.UserDeletion{},
+			getDueDeletionsError: errDB,
+			err:                  errDB,
+		},
+		{
+			name:                "purge the accounts before the first failing one",
+			getDueDeletionsData: deletions,
+			purgeErrors:         map[string]error{"user-2": errDB},
+			purged:              []string{"user-1"},
+			n:                   1,
+			err:                 errDB,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			mockUserRepo := new(mockRepo.MockUserRepository)
+			purge := &purgeAccount{errors: tc.purgeErrors}
+			p := NewPurger(mockUserRepo, purge, time.Hour, 3)
+
+			mockUserRepo.On("GetDueDeletions", ctx, mock.AnythingOfType("time.Time"), 3).Return(tc.getDueDeletionsData, tc.getDueDeletionsError).Once()
+
+			n, err := p.PurgeOnce(ctx)
+			assert.Equal(t, tc.err, err)
+			assert.Equal(t, tc.n, n)
+			assert.Equal(t, tc.purged, purge.purged)
+			mock.AssertExpectationsForObjects(t, mockUserRepo)
+		})
+	}
+}
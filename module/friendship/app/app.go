@@ -13,26 +13,124 @@ type Application struct {
 }
 
 type Commands struct {
-	ConnectFriendship interface {
-		Handle(ctx context.Context, userEmail string, friendEmail string) (domain.Friendship, error)
-	}
 	SubscribeUser interface {
 		Handle(ctx context.Context, payload payload.SubscriberUserPayloads) error
-		HandleWithSubscription(ctx context.Context, ds domain.Subscriptions) error
 	}
 	BlockUpdatesUser interface {
 		Handle(ctx context.Context, payload payload.BlockUpdatesUserPayload) error
 	}
+	RequestFriendship interface {
+		Handle(ctx context.Context, payload payload.FriendRequestPayload) (domain.Friendship, error)
+	}
+	AcceptFriendship interface {
+		Handle(ctx context.Context, payload payload.FriendRequestPayload) (domain.Friendship, error)
+	}
+	RejectFriendship interface {
+		Handle(ctx context.Context, payload payload.FriendRequestPayload) error
+	}
+	CancelFriendship interface {
+		Handle(ctx context.Context, payload payload.FriendRequestPayload) error
+	}
+	Unfriend interface {
+		Handle(ctx context.Context, payload payload.UnfriendPayload) error
+	}
+	UnblockUser interface {
+		Handle(ctx context.Context, payload payload.UnblockUserPayload) error
+	}
+	CreateUser interface {
+		Handle(ctx context.Context, payload payload.CreateUserPayload) (domain.User, error)
+	}
+	UpdateUser interface {
+		Handle(ctx context.Context, payload payload.UpdateUserPayload) (domain.User, error)
+	}
+	DeleteAccount interface {
+		Handle(ctx context.Context, email string) (domain.UserDeletion, error)
+	}
+	CancelAccountDeletion interface {
+		Handle(ctx context.Context, payload payload.RestoreAccountPayload) error
+	}
+	SetPassword interface {
+		Handle(ctx context.Context, payload payload.SetPasswordPayload) error
+	}
+	Login interface {
+		Handle(ctx context.Context, payload payload.LoginPayload) (domain.AuthToken, error)
+	}
+	PostUpdate interface {
+		Handle(ctx context.Context, payload payload.PostUpdatePayload) (domain.Update, error)
+	}
+	RegisterWebhook interface {
+		Handle(ctx context.Context, payload payload.RegisterWebhookPayload) (domain.Webhook, error)
+	}
+	DeleteWebhook interface {
+		Handle(ctx context.Context, id string) error
+	}
+	ReplayWebhookDelivery interface {
+		Handle(ctx context.Context, payload payload.ReplayWebhookDeliveryPayload) (domain.WebhookDelivery, error)
+	}
+	ImportRelationships interface {
+		Handle(ctx context.Context, rows payload.ImportRelationshipPayloads) ([]error, error)
+	}
 }
 
 type Queries struct {
 	ListFriends interface {
-		Handle(ctx context.Context, email string) ([]string, error)
+		Handle(ctx context.Context, email string, page domain.Page) ([]string, string, error)
 	}
 	ListCommonFriends interface {
-		Handle(ctx context.Context, emails []string) ([]string, error)
+		Handle(ctx context.Context, emails []string, page domain.Page) ([]string, string, error)
 	}
 	ListUpdatesUser interface {
-		Handle(ctx context.Context, email string, text string) ([]string, error)
+		Handle(ctx context.Context, email string, text string, page domain.Page) ([]string, string, error)
+	}
+	ListSubscribers interface {
+		Handle(ctx context.Context, email string, page domain.Page) ([]string, string, error)
+	}
+	ListFriendRequests interface {
+		Handle(ctx context.Context, email string, direction domain.FriendRequestDirection) ([]string, error)
+	}
+	ListBlockedUsers interface {
+		Handle(ctx context.Context, userID string) ([]domain.BlockedEmail, error)
+	}
+	ListBlockers interface {
+		Handle(ctx context.Context, email string) ([]domain.BlockedEmail, error)
+	}
+	ListFriendSuggestions interface {
+		Handle(ctx context.Context, email string, limit int) ([]domain.FriendSuggestion, error)
+	}
+	FindFriendshipPath interface {
+		Handle(ctx context.Context, fromEmail, toEmail string, maxDepth int) ([]string, error)
+	}
+	ListFriendSet interface {
+		Handle(ctx context.Context, emails []string, operation domain.FriendSetOperation) ([]domain.FriendSetMember, error)
+	}
+	GetUser interface {
+		Handle(ctx context.Context, email string) (domain.User, error)
+	}
+	ExportUser interface {
+		Handle(ctx context.Context, email string, w domain.UserExport) error
+	}
+	ListUserEmails interface {
+		Handle(ctx context.Context, userIDs []string) (map[string]string, error)
+	}
+	ListUserIDs interface {
+		Handle(ctx context.Context, emails []string) (map[string]string, error)
+	}
+	GetFriendship interface {
+		Handle(ctx context.Context, userEmail, friendEmail string) (domain.Friendship, error)
+	}
+	GetSubscription interface {
+		Handle(ctx context.Context, subscriberEmail, email string) (domain.Subscription, error)
+	}
+	GetFeed interface {
+		Handle(ctx context.Context, userID string, page domain.Page) ([]domain.FeedItem, string, error)
+	}
+	ListWebhooks interface {
+		Handle(ctx context.Context) ([]domain.Webhook, error)
+	}
+	ListDeadWebhookDeliveries interface {
+		Handle(ctx context.Context, webhookID string) ([]domain.WebhookDelivery, error)
+	}
+	ListAuditLog interface {
+		Handle(ctx context.Context, filter domain.AuditFilter, page domain.Page) ([]domain.AuditEntry, string, error)
 	}
 }
@@ -0,0 +1,79 @@
+package command
+
+import (
+	"context"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+type AcceptFriendshipHandler struct {
+	friendshipRepo   domain.FriendshipRepo
+	userRepo         domain.UserRepo
+	subscriptionRepo domain.SubscriptionRepo
+	eventRepo        domain.EventRepo
+	auditRepo        domain.AuditRepo
+	transactor       Transactor
+}
+
+func NewAcceptFriendshipHandler(repo domain.FriendshipRepo, userRepo domain.UserRepo, subRepo domain.SubscriptionRepo, eventRepo domain.EventRepo, auditRepo domain.AuditRepo, transactor Transactor) AcceptFriendshipHandler {
+	return AcceptFriendshipHandler{
+		friendshipRepo:   repo,
+		userRepo:         userRepo,
+		subscriptionRepo: subRepo,
+		eventRepo:        eventRepo,
+		auditRepo:        auditRepo,
+		transactor:       transactor,
+	}
+}
+
+// Handle accepts the friend request the target sent to the requestor,
+// the friends receive updates from each other from then on
+func (h AcceptFriendshipHandler) Handle(ctx context.Context, payload payload.FriendRequestPayload) (domain.Friendship, error) {
+	if payload.Requestor == payload.Target {
+		return domain.Friendship{}, common.ErrInvalidRequest(domain.ErrEmailIsNotValid, "payload")
+	}
+
+	userIDs, err := getUserIDsByEmails(ctx, h.userRepo, payload.Requestor, payload.Target)
+	if err != nil {
+		return domain.Friendship{}, err
+	}
+
+	if err = authorizeRequestor(ctx, userIDs[payload.Requestor]); err != nil {
+		return domain.Friendship{}, err
+	}
+
+	var d domain.Friendship
+	err = h.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		d, err = getFriendRequest(ctx, h.friendshipRepo, userIDs[payload.Target], userIDs[payload.Requestor])
+		if err != nil {
+			return err
+		}
+
+		if err = h.friendshipRepo.UpdateStatus(ctx, d.Id, domain.FriendshipStatusFriended); err != nil {
+			logger.Errorf("friendshipRepo.UpdateStatus %w", err)
+			return common.ErrCannotUpdateEntity(d.DomainName(), err)
+		}
+		d.Status = domain.FriendshipStatusFriended
+		if err = recordEvent(ctx, h.eventRepo, domain.EventFriendshipAccepted, d.Id, domain.RelationEvent{UserID: userIDs[payload.Requestor], TargetID: userIDs[payload.Target]}); err != nil {
+			return err
+		}
+		if err = recordAudit(ctx, h.auditRepo, domain.AuditAcceptFriendship, domain.AuditStatusPending, domain.AuditStatusFriended, userIDs[payload.Requestor], userIDs[payload.Target]); err != nil {
+			return err
+		}
+
+		// a subscription which already exists is kept
+		_, err = subscribe(ctx, h.subscriptionRepo, h.eventRepo, h.auditRepo, domain.Subscriptions{
+			{UserID: d.UserID, SubscriberID: d.FriendID},
+			{UserID: d.FriendID, SubscriberID: d.UserID},
+		})
+		return err
+	})
+	if err != nil {
+		return domain.Friendship{}, err
+	}
+
+	return d, nil
+}
@@ -0,0 +1,238 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/auth"
+	mockRepo "github.com/phantranhieunhan/s3-assignment/mock/friendship/repository"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestCase_Friendship_RespondFriendRequest struct {
+	name string
+	err  error
+
+	getUserIDsByEmailsError error
+	getUserIDsByEmailsData  map[string]string
+
+	withinTransactionError error
+
+	getFriendshipByUserIDsError error
+	getFriendshipByUserIDsData  domain.Friendship
+
+	updateError error
+}
+
+// respondFriendRequestTestCases builds the cases shared by the accept, reject and cancel handlers,
+// where the pending request was sent from requesterID to receiverID
+func respondFriendRequestTestCases(mapEmails map[string]string, requesterID, receiverID string) []TestCase_Friendship_RespondFriendRequest {
+	errDB := errors.New("some error from db")
+	pending := domain.Friendship{
+		Base:     domain.Base{Id: "friendship-id"},
+		UserID:   requesterID,
+		FriendID: receiverID,
+		Status:   domain.FriendshipStatusPending,
+	}
+	reversed := pending
+	reversed.UserID, reversed.FriendID = receiverID, requesterID
+	friended := pending
+	friended.Status = domain.FriendshipStatusFriended
+
+	return []TestCase_Friendship_RespondFriendRequest{
+		{
+			name:                       "successfully because the request is pending",
+			getUserIDsByEmailsData:     mapEmails,
+			getFriendshipByUserIDsData: pending,
+		},
+		{
+			name:                    "fail because emails invalid",
+			getUserIDsByEmailsError: domain.ErrNotFoundUserByEmail,
+			getUserIDsByEmailsData:  map[string]string{},
+			err:                     common.ErrInvalidRequest(domain.ErrNotFoundUserByEmail, "emails"),
+		},
+		{
+			name:                        "fail because the request does not exist",
+			getUserIDsByEmailsData:      mapEmails,
+			getFriendshipByUserIDsError: domain.ErrRecordNotFound,
+			withinTransactionError:      common.ErrInvalidRequest(domain.ErrFriendRequestNotFound, ""),
+			err:                         common.ErrInvalidRequest(domain.ErrFriendRequestNotFound, ""),
+		},
+		{
+			name:                       "fail because the request was sent by the other side",
+			getUserIDsByEmailsData:     mapEmails,
+			getFriendshipByUserIDsData: reversed,
+			withinTransactionError:     common.ErrInvalidRequest(domain.ErrFriendRequestNotFound, ""),
+			err:                        common.ErrInvalidRequest(domain.ErrFriendRequestNotFound, ""),
+		},
+		{
+			name:                       "fail because they are already friends",
+			getUserIDsByEmailsData:     mapEmails,
+			getFriendshipByUserIDsData: friended,
+			withinTransactionError:     common.ErrInvalidRequest(domain.ErrFriendRequestNotFound, ""),
+			err:                        common.ErrInvalidRequest(domain.ErrFriendRequestNotFound, ""),
+		},
+		{
+			name:                        "fail because get friendship fail",
+			getUserIDsByEmailsData:      mapEmails,
+			getFriendshipByUserIDsError: errDB,
+			withinTransactionError:      common.ErrCannotGetEntity(domain.Friendship{}.DomainName(), errDB),
+			err:                         common.ErrCannotGetEntity(domain.Friendship{}.DomainName(), errDB),
+		},
+		{
+			name:                       "fail because update friendship fail",
+			getUserIDsByEmailsData:     mapEmails,
+			getFriendshipByUserIDsData: pending,
+			updateError:                errDB,
+			withinTransactionError:     common.ErrCannotUpdateEntity(domain.Friendship{}.DomainName(), errDB),
+			err:                        common.ErrCannotUpdateEntity(domain.Friendship{}.DomainName(), errDB),
+		},
+	}
+}
+
+// prepareRespondFriendRequest mocks the repositories for a request sent from requesterID to receiverID
+func prepareRespondFriendRequest(t *testing.T, ctx context.Context, tc TestCase_Friendship_RespondFriendRequest, emails []string, requesterID, receiverID string, status domain.FriendshipStatus,
+	mockFriendshipRepo *mockRepo.MockFriendshipRepository, mockUserRepo *mockRepo.MockUserRepository, mockTransaction *mockRepo.MockTransaction) {
+	mockUserRepo.On("GetUserIDsByEmails", ctx, emails).Return(tc.getUserIDsByEmailsData, tc.getUserIDsByEmailsError).Once()
+	if tc.getUserIDsByEmailsError != nil {
+		return
+	}
+	prepareWithinTransaction(t, ctx, mockTransaction, tc.withinTransactionError)
+	mockFriendshipRepo.On("GetFriendshipByUserIDs", ctx, requesterID, receiverID).Return(tc.getFriendshipByUserIDsData, tc.getFriendshipByUserIDsError).Once()
+	if tc.getFriendshipByUserIDsError == nil && tc.getFriendshipByUserIDsData.IsRequestedBy(requesterID, receiverID) {
+		mockFriendshipRepo.On("UpdateStatus", ctx, tc.getFriendshipByUserIDsData.Id, status).Return(tc.updateError).Once()
+	}
+}
+
+func TestFriendship_AcceptFriendship(t *testing.T) {
+	t.Parallel()
+
+	emails := []string{"email-1", "email-2"}
+	friends := []string{"friend-1", "friend-2"}
+	mapEmails := map[string]string{
+		emails[0]: friends[0],
+		emails[1]: friends[1],
+	}
+
+	// the requestor accepts the request the target sent
+	for _, tc := range respondFriendRequestTestCases(mapEmails, friends[1], friends[0]) {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			// the requestor is the authenticated user
+			ctx = auth.WithUserID(ctx, mapEmails[emails[0]])
+
+			mockFriendshipRepo := new(mockRepo.MockFriendshipRepository)
+			mockUserRepo := newActiveUserRepo()
+			mockSubscriptionRepo := new(mockRepo.MockSubscriptionRepository)
+			mockTransaction := new(mockRepo.MockTransaction)
+			h := NewAcceptFriendshipHandler(mockFriendshipRepo, mockUserRepo, mockSubscriptionRepo, allowRecordEvent(), allowRecordAudit(), mockTransaction)
+
+			prepareRespondFriendRequest(t, ctx, tc, emails, friends[1], friends[0], domain.FriendshipStatusFriended, mockFriendshipRepo, mockUserRepo, mockTransaction)
+			if tc.err == nil {
+				prepareSubscribeFriends(ctx, mockSubscriptionRepo, friends[1], friends[0], nil, nil)
+			}
+
+			f, err := h.Handle(ctx, payload.FriendRequestPayload{Requestor: emails[0], Target: emails[1]})
+			assert.Equal(t, tc.err, err)
+			if tc.err == nil {
+				expected := tc.getFriendshipByUserIDsData
+				expected.Status = domain.FriendshipStatusFriended
+				assert.Equal(t, expected, f)
+			}
+			mock.AssertExpectationsForObjects(t, mockFriendshipRepo, mockUserRepo, mockSubscriptionRepo, mockTransaction)
+		})
+	}
+}
+
+// prepareSubscribeFriends mocks the subscriptions of the friends to each other, got holds the ones which already exist
+func prepareSubscribeFriends(ctx context.Context, m *mockRepo.MockSubscriptionRepository, userID, friendID string, got domain.Subscriptions, createErr error) {
+	ds := domain.Subscriptions{
+		{UserID: userID, SubscriberID: friendID},
+		{UserID: friendID, SubscriberID: userID},
+	}
+	m.On("GetSubscription", ctx, ds).Return(got, nil).Once()
+	existing := make(map[string]bool, len(got))
+	for _, v := range got {
+		existing[v.GetUserSubscriberMapKey()] = true
+	}
+	for _, v := range ds {
+		if existing[v.GetUserSubscriberMapKey()] {
+			continue
+		}
+		v.Status = domain.SubscriptionStatusSubscribed
+		m.On("Create", ctx, v).Return("subscription-id", createErr).Once()
+		if createErr != nil {
+			return
+		}
+	}
+}
+
+func TestFriendship_AcceptFriendship_Subscriptions(t *testing.T) {
+	t.Parallel()
+
+	emails := []string{"email-1", "email-2"}
+	friends := []string{"friend-1", "friend-2"}
+	mapEmails := map[string]string{
+		emails[0]: friends[0],
+		emails[1]: friends[1],
+	}
+	pending := domain.Friendship{
+		Base:     domain.Base{Id: "friendship-id"},
+		UserID:   friends[1],
+		FriendID: friends[0],
+		Status:   domain.FriendshipStatusPending,
+	}
+	errDB := errors.New("some error from db")
+
+	tcs := []struct {
+		name      string
+		got       domain.Subscriptions
+		createErr error
+		err       error
+	}{
+		{
+			name: "successfully keeps the subscription which already exists",
+			got: domain.Subscriptions{
+				{Base: domain.Base{Id: "subscription-id"}, UserID: friends[1], SubscriberID: friends[0], Status: domain.SubscriptionStatusSubscribed},
+			},
+		},
+		{
+			name:      "fail and roll back the acceptance because create subscription fail",
+			createErr: errDB,
+			err:       common.ErrCannotCreateEntity(domain.Subscription{}.DomainName(), errDB),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			// the requestor is the authenticated user
+			ctx = auth.WithUserID(ctx, mapEmails[emails[0]])
+
+			mockFriendshipRepo := new(mockRepo.MockFriendshipRepository)
+			mockUserRepo := newActiveUserRepo()
+			mockSubscriptionRepo := new(mockRepo.MockSubscriptionRepository)
+			mockTransaction := new(mockRepo.MockTransaction)
+			h := NewAcceptFriendshipHandler(mockFriendshipRepo, mockUserRepo, mockSubscriptionRepo, allowRecordEvent(), allowRecordAudit(), mockTransaction)
+
+			mockUserRepo.On("GetUserIDsByEmails", ctx, emails).Return(mapEmails, nil).Once()
+			prepareWithinTransaction(t, ctx, mockTransaction, tc.err)
+			mockFriendshipRepo.On("GetFriendshipByUserIDs", ctx, friends[1], friends[0]).Return(pending, nil).Once()
+			mockFriendshipRepo.On("UpdateStatus", ctx, pending.Id, domain.FriendshipStatusFriended).Return(nil).Once()
+			prepareSubscribeFriends(ctx, mockSubscriptionRepo, friends[1], friends[0], tc.got, tc.createErr)
+
+			_, err := h.Handle(ctx, payload.FriendRequestPayload{Requestor: emails[0], Target: emails[1]})
+			assert.Equal(t, tc.err, err)
+			mock.AssertExpectationsForObjects(t, mockFriendshipRepo, mockUserRepo, mockSubscriptionRepo, mockTransaction)
+		})
+	}
+}
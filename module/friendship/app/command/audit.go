@@ -0,0 +1,52 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/auth"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/common/requestid"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+// recordAudit appends the entry of the command to the audit log, it must run within the transaction of the change it records
+// so that the entry is stored if and only if the change is. The actor and the request id are read from the context
+func recordAudit(ctx context.Context, auditRepo domain.AuditRepo, action domain.AuditAction, before, after domain.AuditStatus, targetIDs ...string) error {
+	e := domain.AuditEntry{
+		Actor:        auditActor(ctx),
+		Action:       action,
+		TargetIDs:    targetIDs,
+		StatusBefore: before,
+		StatusAfter:  after,
+		RequestID:    requestid.FromContext(ctx),
+		CreatedAt:    time.Now().UTC(),
+	}
+	if _, err := auditRepo.Create(ctx, e); err != nil {
+		logger.Errorf("auditRepo.Create %w", err)
+		return common.ErrCannotCreateEntity(e.DomainName(), err)
+	}
+	return nil
+}
+
+// actingAs returns the context of the user for the commands run before the user is authenticated,
+// the signup, the login and the restore of an account, unless the request already has an actor
+func actingAs(ctx context.Context, userID string) context.Context {
+	if _, ok := auth.UserIDFromContext(ctx); ok || auth.IsAdmin(ctx) {
+		return ctx
+	}
+	return auth.WithUserID(ctx, userID)
+}
+
+// auditActor is the authenticated user, the admin when the request carries the admin token,
+// or the system for the work done outside of a request
+func auditActor(ctx context.Context) string {
+	if userID, ok := auth.UserIDFromContext(ctx); ok {
+		return userID
+	}
+	if auth.IsAdmin(ctx) {
+		return domain.AuditActorAdmin
+	}
+	return domain.AuditActorSystem
+}
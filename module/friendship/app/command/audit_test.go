@@ -0,0 +1,90 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/auth"
+	"github.com/phantranhieunhan/s3-assignment/common/requestid"
+	mockRepo "github.com/phantranhieunhan/s3-assignment/mock/friendship/repository"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// allowRecordAudit returns an audit repository accepting any entry, for the tests not asserting on the audit log
+func allowRecordAudit() *mockRepo.MockAuditRepository {
+	m := new(mockRepo.MockAuditRepository)
+	m.On("Create", mock.Anything, mock.Anything).Return("audit-id", nil).Maybe()
+	return m
+}
+
+func prepareRecordAudit(ctx context.Context, m *mockRepo.MockAuditRepository, action domain.AuditAction, before, after domain.AuditStatus, err error) {
+	m.On("Create", ctx, mock.MatchedBy(func(e domain.AuditEntry) bool {
+		return e.Action == action && e.StatusBefore == before && e.StatusAfter == after
+	})).Return("audit-id", err).Once()
+}
+
+type TestCase_RecordAudit struct {
+	name  string
+	ctx   func(ctx context.Context) context.Context
+	actor string
+
+	createError error
+
+	err error
+}
+
+func TestRecordAudit(t *testing.T) {
+	t.Parallel()
+
+	errDB := errors.New("some error from db")
+
+	tcs := []TestCase_RecordAudit{
+		{
+			name:  "record the authenticated user as the actor",
+			ctx:   func(ctx context.Context) context.Context { return auth.WithUserID(ctx, "user-1") },
+			actor: "user-1",
+		},
+		{
+			name:  "record the admin as the actor",
+			ctx:   auth.WithAdmin,
+			actor: domain.AuditActorAdmin,
+		},
+		{
+			name:  "record the system as the actor outside of a request",
+			ctx:   func(ctx context.Context) context.Context { return ctx },
+			actor: domain.AuditActorSystem,
+		},
+		{
+			name:        "record audit fail because create entry fail",
+			ctx:         func(ctx context.Context) context.Context { return ctx },
+			actor:       domain.AuditActorSystem,
+			createError: errDB,
+			err:         common.ErrCannotCreateEntity(domain.AuditEntry{}.DomainName(), errDB),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			ctx = tc.ctx(requestid.With(ctx, "request-1"))
+
+			mockAuditRepo := new(mockRepo.MockAuditRepository)
+			mockAuditRepo.On("Create", ctx, mock.MatchedBy(func(e domain.AuditEntry) bool {
+				return e.Actor == tc.actor && e.Action == domain.AuditAcceptFriendship && e.RequestID == "request-1" &&
+					e.StatusBefore == domain.AuditStatusPending && e.StatusAfter == domain.AuditStatusFriended &&
+					assert.ObjectsAreEqual([]string{"user-1", "user-2"}, e.TargetIDs) && !e.CreatedAt.IsZero()
+			})).Return("audit-id", tc.createError).Once()
+
+			err := recordAudit(ctx, mockAuditRepo, domain.AuditAcceptFriendship, domain.AuditStatusPending, domain.AuditStatusFriended, "user-1", "user-2")
+			assert.Equal(t, tc.err, err)
+			mock.AssertExpectationsForObjects(t, mockAuditRepo)
+		})
+	}
+}
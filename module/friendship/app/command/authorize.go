@@ -0,0 +1,45 @@
+package command
+
+import (
+	"context"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/auth"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+// authorizeRequestor rejects the command when the authenticated user is none of the given users.
+// Only a request made with the admin token acts on behalf of any user, a request with neither is not authenticated
+func authorizeRequestor(ctx context.Context, userIDs ...string) error {
+	if auth.IsAdmin(ctx) {
+		return nil
+	}
+	authUserID, ok := auth.UserIDFromContext(ctx)
+	if !ok {
+		return common.NewUnauthorized(domain.ErrRequestIsNotAuthenticated, domain.ErrRequestIsNotAuthenticated.Error(), "ErrUnauthorized")
+	}
+	for _, id := range userIDs {
+		if id == authUserID {
+			return nil
+		}
+	}
+	return common.NewForbidden(domain.ErrRequestorIsNotAuthenticatedUser, domain.ErrRequestorIsNotAuthenticatedUser.Error(), "ErrForbidden")
+}
+
+// ensureUsersAreActive rejects the command when one of the users is scheduled for deletion, such an account
+// neither acts nor is acted on until it is restored. A purged account is already out of reach by its email
+func ensureUsersAreActive(ctx context.Context, userRepo domain.UserRepo, field string, userIDs ...string) error {
+	for _, id := range userIDs {
+		deletion, err := userRepo.GetDeletion(ctx, id)
+		if err == domain.ErrRecordNotFound {
+			continue
+		}
+		if err != nil {
+			logger.Errorf("userRepo.GetDeletion %w", err)
+			return common.ErrCannotGetEntity(deletion.DomainName(), err)
+		}
+		return common.ErrInvalidRequest(domain.ErrUserIsDeleted, field)
+	}
+	return nil
+}
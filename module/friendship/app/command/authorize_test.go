@@ -0,0 +1,99 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/auth"
+	mockRepo "github.com/phantranhieunhan/s3-assignment/mock/friendship/repository"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAuthorizeRequestor(t *testing.T) {
+	t.Parallel()
+
+	errForbidden := common.NewForbidden(domain.ErrRequestorIsNotAuthenticatedUser, domain.ErrRequestorIsNotAuthenticatedUser.Error(), "ErrForbidden")
+	errUnauthorized := common.NewUnauthorized(domain.ErrRequestIsNotAuthenticated, domain.ErrRequestIsNotAuthenticated.Error(), "ErrUnauthorized")
+	tcs := []struct {
+		name       string
+		authUserID string
+		admin      bool
+		userIDs    []string
+		err        error
+	}{
+		{name: "not authenticated", userIDs: []string{"user-1"}, err: errUnauthorized},
+		{name: "admin acts on behalf of any user", admin: true, userIDs: []string{"user-1"}},
+		{name: "authenticated requestor", authUserID: "user-1", userIDs: []string{"user-1"}},
+		{name: "authenticated user is one of the users", authUserID: "user-2", userIDs: []string{"user-1", "user-2"}},
+		{name: "authenticated user is not the requestor", authUserID: "user-2", userIDs: []string{"user-1"}, err: errForbidden},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tc.authUserID != "" {
+				ctx = auth.WithUserID(ctx, tc.authUserID)
+			}
+			if tc.admin {
+				ctx = auth.WithAdmin(ctx)
+			}
+			assert.Equal(t, tc.err, authorizeRequestor(ctx, tc.userIDs...))
+		})
+	}
+}
+
+func TestGetUserIDsByEmails(t *testing.T) {
+	t.Parallel()
+
+	emails := []string{"email-1", "email-2"}
+	mapEmails := map[string]string{emails[0]: "user-1", emails[1]: "user-2"}
+	errDB := errors.New("some error from db")
+	tcs := []struct {
+		name             string
+		emails           []string
+		scheduledUserID  string
+		getDeletionError error
+		err              error
+	}{
+		{name: "both users are active", emails: emails},
+		{name: "the target is scheduled for deletion", emails: emails, scheduledUserID: "user-2", err: common.ErrInvalidRequest(domain.ErrUserIsDeleted, "emails")},
+		{name: "the requestor is scheduled for deletion", emails: emails, scheduledUserID: "user-1", err: common.ErrInvalidRequest(domain.ErrUserIsDeleted, "emails")},
+		{name: "get deletion fail", emails: emails, getDeletionError: errDB, err: common.ErrCannotGetEntity(domain.UserDeletion{}.DomainName(), errDB)},
+		{name: "the target is purged", emails: []string{emails[0], domain.UserTombstoneEmail("user-2")}, err: common.ErrInvalidRequest(domain.ErrNotFoundUserByEmail, "emails")},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			mockUserRepo := new(mockRepo.MockUserRepository)
+			if !domain.IsUserTombstoneEmail(tc.emails[1]) {
+				mockUserRepo.On("GetUserIDsByEmails", ctx, tc.emails).Return(mapEmails, nil).Once()
+				for _, id := range []string{"user-1", "user-2"} {
+					err := tc.getDeletionError
+					if err == nil && id != tc.scheduledUserID {
+						err = domain.ErrRecordNotFound
+					}
+					mockUserRepo.On("GetDeletion", ctx, id).Return(domain.UserDeletion{UserID: id}, err).Maybe()
+				}
+			}
+
+			userIDs, err := getUserIDsByEmails(ctx, mockUserRepo, tc.emails...)
+			assert.Equal(t, tc.err, err)
+			if tc.err == nil {
+				assert.Equal(t, mapEmails, userIDs)
+			}
+			mock.AssertExpectationsForObjects(t, mockUserRepo)
+		})
+	}
+}
+
+// newActiveUserRepo returns a user repository whose users have no scheduled deletion
+func newActiveUserRepo() *mockRepo.MockUserRepository {
+	m := new(mockRepo.MockUserRepository)
+	m.On("GetDeletion", mock.Anything, mock.Anything).Return(domain.UserDeletion{}, domain.ErrRecordNotFound).Maybe()
+	return m
+}
@@ -13,14 +13,20 @@ type BlockUpdatesUserHandler struct {
 	friendshipRepo   domain.FriendshipRepo
 	userRepo         domain.UserRepo
 	subscriptionRepo domain.SubscriptionRepo
+	blockRepo        domain.BlockRepo
+	eventRepo        domain.EventRepo
+	auditRepo        domain.AuditRepo
 	transactor       Transactor
 }
 
-func NewBlockUpdatesUserHandler(repo domain.FriendshipRepo, userRepo domain.UserRepo, subRepo domain.SubscriptionRepo, transactor Transactor) BlockUpdatesUserHandler {
+func NewBlockUpdatesUserHandler(repo domain.FriendshipRepo, userRepo domain.UserRepo, subRepo domain.SubscriptionRepo, blockRepo domain.BlockRepo, eventRepo domain.EventRepo, auditRepo domain.AuditRepo, transactor Transactor) BlockUpdatesUserHandler {
 	return BlockUpdatesUserHandler{
 		friendshipRepo:   repo,
 		userRepo:         userRepo,
 		subscriptionRepo: subRepo,
+		blockRepo:        blockRepo,
+		eventRepo:        eventRepo,
+		auditRepo:        auditRepo,
 		transactor:       transactor,
 	}
 }
@@ -30,18 +36,18 @@ func (b BlockUpdatesUserHandler) Handle(ctx context.Context, payload payload.Blo
 		return common.ErrInvalidRequest(domain.ErrEmailIsNotValid, "payload")
 	}
 
-	userIDs, err := b.userRepo.GetUserIDsByEmails(ctx, []string{payload.Requestor, payload.Target})
+	userIDs, err := getUserIDsByEmails(ctx, b.userRepo, payload.Requestor, payload.Target)
 	if err != nil {
-		logger.Errorf("userRepo.GetUserIDsByEmails %w", err)
-		if err == domain.ErrNotFoundUserByEmail {
-			return common.ErrInvalidRequest(err, "emails")
-		}
-		return common.ErrCannotGetEntity(domain.Subscription{}.DomainName(), err)
+		return err
 	}
 
 	requestorID := userIDs[payload.Requestor]
 	targetID := userIDs[payload.Target]
 
+	if err = authorizeRequestor(ctx, requestorID); err != nil {
+		return err
+	}
+
 	err = b.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
 		gotSub, err := b.subscriptionRepo.GetSubscription(ctx, domain.Subscriptions{
 			{UserID: targetID, SubscriberID: requestorID},
@@ -53,6 +59,10 @@ func (b BlockUpdatesUserHandler) Handle(ctx context.Context, payload payload.Blo
 		if len(gotSub) > 0 && !gotSub[0].Status.AllowBlock() {
 			return common.ErrInvalidRequest(domain.ErrAlreadyExists, "emails")
 		}
+		block := domain.Block{UserID: requestorID, TargetID: targetID}
+		if len(gotSub) > 0 {
+			block.SubscriptionStatus = gotSub[0].Status
+		}
 
 		f, err := b.friendshipRepo.GetFriendshipByUserIDs(ctx, requestorID, targetID)
 		if err != nil && err != domain.ErrRecordNotFound {
@@ -60,6 +70,8 @@ func (b BlockUpdatesUserHandler) Handle(ctx context.Context, payload payload.Blo
 			return common.ErrCannotGetEntity(f.DomainName(), err)
 		}
 
+		block.FriendshipStatus = f.Status
+
 		if err == domain.ErrRecordNotFound || f.Status.CanBlockUser() {
 			if err = b.blockUser(ctx, f.Id, requestorID, targetID); err != nil {
 				return err
@@ -70,7 +82,16 @@ func (b BlockUpdatesUserHandler) Handle(ctx context.Context, payload payload.Blo
 			return err
 		}
 
-		return nil
+		// keep the state before blocking so that the block can be lifted
+		if block.Id, err = b.blockRepo.UpsertBlock(ctx, block); err != nil {
+			logger.Errorf("blockRepo.UpsertBlock %w", err)
+			return common.ErrCannotCreateEntity(block.DomainName(), err)
+		}
+
+		if err = recordEvent(ctx, b.eventRepo, domain.EventUserBlocked, block.Id, domain.RelationEvent{UserID: requestorID, TargetID: targetID}); err != nil {
+			return err
+		}
+		return recordAudit(ctx, b.auditRepo, domain.AuditBlockUpdatesUser, domain.FriendshipAuditStatus(block.FriendshipStatus), domain.AuditStatusBlocked, requestorID, targetID)
 	})
 	return err
 }
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/auth"
 	mockRepo "github.com/phantranhieunhan/s3-assignment/mock/friendship/repository"
 	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
 	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
@@ -38,21 +39,25 @@ type TestCase_Friendship_BlockUpdatesUserHandler struct {
 	updateError error
 
 	upsertSubscriptionError error
+
+	upsertBlockError error
 }
 
 func TestFriendship_BlockUpdatesUserHandler(t *testing.T) {
 	t.Parallel()
 	mockFriendshipRepo := new(mockRepo.MockFriendshipRepository)
-	mockUserRepo := new(mockRepo.MockUserRepository)
+	mockUserRepo := newActiveUserRepo()
 	mockTransaction := new(mockRepo.MockTransaction)
 	mockSub := new(mockRepo.MockSubscriptionRepository)
+	mockBlock := new(mockRepo.MockBlockRepository)
 
-	h := NewBlockUpdatesUserHandler(mockFriendshipRepo, mockUserRepo, mockSub, mockTransaction)
+	h := NewBlockUpdatesUserHandler(mockFriendshipRepo, mockUserRepo, mockSub, mockBlock, allowRecordEvent(), allowRecordAudit(), mockTransaction)
 
 	repoMock := &RepoMock_TestFriendship_BlockUpdatesUserHandler{
 		mockUserRepo:         mockUserRepo,
 		mockFriendshipRepo:   mockFriendshipRepo,
 		mockSubscriptionRepo: mockSub,
+		mockBlockRepo:        mockBlock,
 		mockTransaction:      mockTransaction,
 	}
 
@@ -94,6 +99,22 @@ func TestFriendship_BlockUpdatesUserHandler(t *testing.T) {
 			},
 			createData: friendshipId,
 		},
+		{
+			name:           "block updates user successfully and block the pending friend request",
+			err:            nil,
+			requestorEmail: emails[0],
+			targetEmail:    emails[1],
+
+			getUserIDsByEmailsData: mapEmails,
+			getFriendshipByUserIDsData: domain.Friendship{
+				Base: domain.Base{
+					Id: friendshipId,
+				},
+				UserID:   friends[1],
+				FriendID: friends[0],
+				Status:   domain.FriendshipStatusPending,
+			},
+		},
 		{
 			name:           "block updates user successfully because they did be a friend before AND did not subscribe before",
 			err:            nil,
@@ -131,7 +152,7 @@ func TestFriendship_BlockUpdatesUserHandler(t *testing.T) {
 		},
 		{
 			name:           "block updates user fail because GetUserIDsByEmails failed",
-			err:            common.ErrCannotGetEntity(domain.Subscription{}.DomainName(), errDB),
+			err:            common.ErrCannotGetEntity(domain.User{}.DomainName(), errDB),
 			requestorEmail: emails[0],
 			targetEmail:    emails[1],
 
@@ -194,12 +215,32 @@ func TestFriendship_BlockUpdatesUserHandler(t *testing.T) {
 			upsertSubscriptionError: errDB,
 			withinTransactionError:  common.ErrCannotGetEntity(domain.Subscription{}.DomainName(), errDB),
 		},
+		{
+			name:           "block updates user fail because upsert block failed",
+			err:            common.ErrCannotCreateEntity(domain.Block{}.DomainName(), errDB),
+			requestorEmail: emails[0],
+			targetEmail:    emails[1],
+
+			getUserIDsByEmailsData: mapEmails,
+			getSubscriptionData:    domain.SubscriptionStatusSubscribed,
+			getFriendshipByUserIDsData: domain.Friendship{
+				Base: domain.Base{
+					Id: friendshipId,
+				},
+				UserID:   friends[0],
+				FriendID: friends[1],
+				Status:   domain.FriendshipStatusFriended,
+			},
+			upsertBlockError:       errDB,
+			withinTransactionError: common.ErrCannotCreateEntity(domain.Block{}.DomainName(), errDB),
+		},
 	}
 
 	for _, tc := range tcs {
 		t.Run(tc.name, func(t *testing.T) {
 			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 			defer cancel()
+			ctx = auth.WithUserID(ctx, mapEmails[tc.requestorEmail])
 
 			repoMock.prepare(ctx, t, tc)
 
@@ -208,7 +249,7 @@ func TestFriendship_BlockUpdatesUserHandler(t *testing.T) {
 				Target:    tc.targetEmail,
 			})
 			assert.Equal(t, err, tc.err)
-			mock.AssertExpectationsForObjects(t, mockFriendshipRepo, mockUserRepo, mockTransaction, mockSub)
+			mock.AssertExpectationsForObjects(t, mockFriendshipRepo, mockUserRepo, mockTransaction, mockSub, mockBlock)
 		})
 	}
 }
@@ -217,6 +258,7 @@ type RepoMock_TestFriendship_BlockUpdatesUserHandler struct {
 	mockUserRepo         *mockRepo.MockUserRepository
 	mockFriendshipRepo   *mockRepo.MockFriendshipRepository
 	mockSubscriptionRepo *mockRepo.MockSubscriptionRepository
+	mockBlockRepo        *mockRepo.MockBlockRepository
 	mockTransaction      *mockRepo.MockTransaction
 }
 
@@ -273,4 +315,13 @@ func (r *RepoMock_TestFriendship_BlockUpdatesUserHandler) prepareUnsubscribeMock
 	r.mockSubscriptionRepo.On("UpsertSubscription", ctx, domain.Subscription{
 		UserID: friends[1], SubscriberID: friends[0], Status: domain.SubscriptionStatusUnsubscribed},
 	).Return("", tc.upsertSubscriptionError).Once()
+
+	if tc.upsertSubscriptionError == nil {
+		r.mockBlockRepo.On("UpsertBlock", ctx, domain.Block{
+			UserID:             friends[0],
+			TargetID:           friends[1],
+			FriendshipStatus:   tc.getFriendshipByUserIDsData.Status,
+			SubscriptionStatus: tc.getSubscriptionData,
+		}).Return("block-id", tc.upsertBlockError).Once()
+	}
 }
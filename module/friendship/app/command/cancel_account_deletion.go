@@ -0,0 +1,50 @@
+package command
+
+import (
+	"context"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+type CancelAccountDeletionHandler struct {
+	userRepo   domain.UserRepo
+	eventRepo  domain.EventRepo
+	auditRepo  domain.AuditRepo
+	transactor Transactor
+}
+
+func NewCancelAccountDeletionHandler(userRepo domain.UserRepo, eventRepo domain.EventRepo, auditRepo domain.AuditRepo, transactor Transactor) CancelAccountDeletionHandler {
+	return CancelAccountDeletionHandler{
+		userRepo:   userRepo,
+		eventRepo:  eventRepo,
+		auditRepo:  auditRepo,
+		transactor: transactor,
+	}
+}
+
+// Handle cancels the scheduled deletion of the account, an account already purged cannot be found by its email anymore.
+// The account can't log in while it is scheduled for deletion, so the owner restores it with its password
+func (h CancelAccountDeletionHandler) Handle(ctx context.Context, payload payload.RestoreAccountPayload) error {
+	return h.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		user, err := checkCredentials(ctx, h.userRepo, payload.Email, payload.Password)
+		if err != nil {
+			return err
+		}
+		ctx = actingAs(ctx, user.Base.Id)
+
+		if err = h.userRepo.CancelDeletion(ctx, user.Base.Id); err != nil {
+			logger.Errorf("userRepo.CancelDeletion %w", err)
+			if err == domain.ErrRecordNotFound {
+				return common.ErrInvalidRequest(domain.ErrUserDeletionNotFound, "email")
+			}
+			return common.ErrCannotDeleteEntity(domain.UserDeletion{}.DomainName(), err)
+		}
+		if err = recordEvent(ctx, h.eventRepo, domain.EventUserDeletionCancelled, user.Base.Id, domain.UserEvent{Email: user.Email}); err != nil {
+			return err
+		}
+		return recordAudit(ctx, h.auditRepo, domain.AuditCancelAccountDeletion, domain.AuditStatusDeletionScheduled, domain.AuditStatusActive, user.Base.Id)
+	})
+}
@@ -0,0 +1,109 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/auth"
+	mockRepo "github.com/phantranhieunhan/s3-assignment/mock/friendship/repository"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestCase_User_CancelAccountDeletion struct {
+	name string
+	err  error
+
+	withinTransactionError error
+
+	password string
+
+	getUserByEmailError error
+	cancelDeletionError error
+}
+
+func TestUser_CancelAccountDeletion(t *testing.T) {
+	t.Parallel()
+
+	email := "email-1"
+	user := domain.User{Base: domain.Base{Id: "user-1"}, Email: email}
+	hashed, err := auth.HashPassword("password")
+	assert.NoError(t, err)
+	errDB := errors.New("some error from db")
+
+	tcs := []TestCase_User_CancelAccountDeletion{
+		{
+			name: "cancel deletion successfully",
+		},
+		{
+			name:                   "cancel deletion fail because user not found",
+			getUserByEmailError:    domain.ErrRecordNotFound,
+			withinTransactionError: common.NewUnauthorized(auth.ErrInvalidCredentials, auth.ErrInvalidCredentials.Error(), "ErrInvalidCredentials"),
+			err:                    common.NewUnauthorized(auth.ErrInvalidCredentials, auth.ErrInvalidCredentials.Error(), "ErrInvalidCredentials"),
+		},
+		{
+			name:                   "cancel deletion fail because password is wrong",
+			password:               "wrong-password",
+			withinTransactionError: common.NewUnauthorized(auth.ErrInvalidCredentials, auth.ErrInvalidCredentials.Error(), "ErrInvalidCredentials"),
+			err:                    common.NewUnauthorized(auth.ErrInvalidCredentials, auth.ErrInvalidCredentials.Error(), "ErrInvalidCredentials"),
+		},
+		{
+			name:                   "cancel deletion fail because no deletion is scheduled",
+			cancelDeletionError:    domain.ErrRecordNotFound,
+			withinTransactionError: common.ErrInvalidRequest(domain.ErrUserDeletionNotFound, "email"),
+			err:                    common.ErrInvalidRequest(domain.ErrUserDeletionNotFound, "email"),
+		},
+		{
+			name:                   "cancel deletion fail because cancel fail",
+			cancelDeletionError:    errDB,
+			withinTransactionError: common.ErrCannotDeleteEntity(domain.UserDeletion{}.DomainName(), errDB),
+			err:                    common.ErrCannotDeleteEntity(domain.UserDeletion{}.DomainName(), errDB),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			// the account can't log in, the restore is made without a token
+			password := tc.password
+			if password == "" {
+				password = "password"
+			}
+			actorCtx := actingAs(ctx, user.Base.Id)
+
+			mockUserRepo := new(mockRepo.MockUserRepository)
+			mockEventRepo := new(mockRepo.MockEventRepository)
+			mockTransaction := new(mockRepo.MockTransaction)
+			mockAuditRepo := new(mockRepo.MockAuditRepository)
+			h := NewCancelAccountDeletionHandler(mockUserRepo, mockEventRepo, mockAuditRepo, mockTransaction)
+
+			prepareWithinTransaction(t, ctx, mockTransaction, tc.withinTransactionError)
+			mockUserRepo.On("GetUserByEmail", ctx, email).Return(user, tc.getUserByEmailError).Once()
+			if tc.getUserByEmailError == nil {
+				mockUserRepo.On("GetPasswordHash", ctx, user.Base.Id).Return(hashed, nil).Once()
+			}
+			if tc.getUserByEmailError == nil && password == "password" {
+				mockUserRepo.On("CancelDeletion", actorCtx, user.Base.Id).Return(tc.cancelDeletionError).Once()
+			}
+			if tc.err == nil {
+				prepareRecordEvent(actorCtx, mockEventRepo, domain.EventUserDeletionCancelled, user.Base.Id, nil)
+				// the owner restoring the account is the actor of the entry
+				mockAuditRepo.On("Create", actorCtx, mock.MatchedBy(func(e domain.AuditEntry) bool {
+					return e.Actor == user.Base.Id && e.Action == domain.AuditCancelAccountDeletion &&
+						e.StatusBefore == domain.AuditStatusDeletionScheduled && e.StatusAfter == domain.AuditStatusActive
+				})).Return("audit-id", nil).Once()
+			}
+
+			err := h.Handle(ctx, payload.RestoreAccountPayload{Email: email, Password: password})
+			assert.Equal(t, tc.err, err)
+			mock.AssertExpectationsForObjects(t, mockUserRepo, mockEventRepo, mockAuditRepo, mockTransaction)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package command
+
+import (
+	"context"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+type CancelFriendshipHandler struct {
+	friendshipRepo domain.FriendshipRepo
+	userRepo       domain.UserRepo
+	eventRepo      domain.EventRepo
+	auditRepo      domain.AuditRepo
+	transactor     Transactor
+}
+
+func NewCancelFriendshipHandler(repo domain.FriendshipRepo, userRepo domain.UserRepo, eventRepo domain.EventRepo, auditRepo domain.AuditRepo, transactor Transactor) CancelFriendshipHandler {
+	return CancelFriendshipHandler{
+		friendshipRepo: repo,
+		userRepo:       userRepo,
+		eventRepo:      eventRepo,
+		auditRepo:      auditRepo,
+		transactor:     transactor,
+	}
+}
+
+// Handle withdraws the friend request the requestor sent to the target
+func (h CancelFriendshipHandler) Handle(ctx context.Context, payload payload.FriendRequestPayload) error {
+	if payload.Requestor == payload.Target {
+		return common.ErrInvalidRequest(domain.ErrEmailIsNotValid, "payload")
+	}
+
+	userIDs, err := getUserIDsByEmails(ctx, h.userRepo, payload.Requestor, payload.Target)
+	if err != nil {
+		return err
+	}
+
+	if err = authorizeRequestor(ctx, userIDs[payload.Requestor]); err != nil {
+		return err
+	}
+
+	return h.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		f, err := getFriendRequest(ctx, h.friendshipRepo, userIDs[payload.Requestor], userIDs[payload.Target])
+		if err != nil {
+			return err
+		}
+
+		if err = h.friendshipRepo.UpdateStatus(ctx, f.Id, domain.FriendshipStatusUnfriended); err != nil {
+			logger.Errorf("friendshipRepo.UpdateStatus %w", err)
+			return common.ErrCannotUpdateEntity(f.DomainName(), err)
+		}
+		if err = recordEvent(ctx, h.eventRepo, domain.EventFriendshipCancelled, f.Id, domain.RelationEvent{UserID: userIDs[payload.Requestor], TargetID: userIDs[payload.Target]}); err != nil {
+			return err
+		}
+		return recordAudit(ctx, h.auditRepo, domain.AuditCancelFriendship, domain.AuditStatusPending, domain.AuditStatusUnfriended, userIDs[payload.Requestor], userIDs[payload.Target])
+	})
+}
@@ -0,0 +1,47 @@
+package command
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common/auth"
+	mockRepo "github.com/phantranhieunhan/s3-assignment/mock/friendship/repository"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestFriendship_CancelFriendship(t *testing.T) {
+	t.Parallel()
+
+	emails := []string{"email-1", "email-2"}
+	friends := []string{"friend-1", "friend-2"}
+	mapEmails := map[string]string{
+		emails[0]: friends[0],
+		emails[1]: friends[1],
+	}
+
+	// the requestor withdraws the request they sent to the target
+	for _, tc := range respondFriendRequestTestCases(mapEmails, friends[0], friends[1]) {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			// the requestor is the authenticated user
+			ctx = auth.WithUserID(ctx, mapEmails[emails[0]])
+
+			mockFriendshipRepo := new(mockRepo.MockFriendshipRepository)
+			mockUserRepo := newActiveUserRepo()
+			mockTransaction := new(mockRepo.MockTransaction)
+			h := NewCancelFriendshipHandler(mockFriendshipRepo, mockUserRepo, allowRecordEvent(), allowRecordAudit(), mockTransaction)
+
+			prepareRespondFriendRequest(t, ctx, tc, emails, friends[0], friends[1], domain.FriendshipStatusUnfriended, mockFriendshipRepo, mockUserRepo, mockTransaction)
+
+			err := h.Handle(ctx, payload.FriendRequestPayload{Requestor: emails[0], Target: emails[1]})
+			assert.Equal(t, tc.err, err)
+			mock.AssertExpectationsForObjects(t, mockFriendshipRepo, mockUserRepo, mockTransaction)
+		})
+	}
+}
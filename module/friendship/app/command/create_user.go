@@ -0,0 +1,100 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/auth"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+type CreateUserHandler struct {
+	userRepo   domain.UserRepo
+	eventRepo  domain.EventRepo
+	auditRepo  domain.AuditRepo
+	transactor Transactor
+}
+
+func NewCreateUserHandler(userRepo domain.UserRepo, eventRepo domain.EventRepo, auditRepo domain.AuditRepo, transactor Transactor) CreateUserHandler {
+	return CreateUserHandler{
+		userRepo:   userRepo,
+		eventRepo:  eventRepo,
+		auditRepo:  auditRepo,
+		transactor: transactor,
+	}
+}
+
+// Handle registers a new user, the email must not be used by another user
+func (h CreateUserHandler) Handle(ctx context.Context, payload payload.CreateUserPayload) (domain.User, error) {
+	hashed, err := auth.HashPassword(payload.Password)
+	if err != nil {
+		logger.Errorf("auth.HashPassword %w", err)
+		return domain.User{}, common.ErrInternal(err)
+	}
+
+	now := time.Now().UTC()
+	user := domain.User{
+		Base: domain.Base{
+			CreatedAt: now,
+			UpdatedAt: now,
+		},
+		Email:    payload.Email,
+		Password: hashed,
+	}
+
+	err = h.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := ensureEmailIsAvailable(ctx, h.userRepo, payload.Email); err != nil {
+			return err
+		}
+
+		id, err := h.userRepo.Create(ctx, user)
+		if err != nil {
+			logger.Errorf("userRepo.Create %w", err)
+			return common.ErrCannotCreateEntity(user.DomainName(), err)
+		}
+		user.Base.Id = id
+		if err = recordEvent(ctx, h.eventRepo, domain.EventUserCreated, id, domain.UserEvent{Email: user.Email}); err != nil {
+			return err
+		}
+		return recordAudit(actingAs(ctx, id), h.auditRepo, domain.AuditCreateUser, domain.AuditStatusNone, domain.AuditStatusActive, id)
+	})
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	user.Password = ""
+	return user, nil
+}
+
+// ensureEmailIsAvailable returns an invalid request error when the email already belongs to a user
+func ensureEmailIsAvailable(ctx context.Context, userRepo domain.UserRepo, email string) error {
+	_, err := userRepo.GetUserByEmail(ctx, email)
+	if err == nil {
+		return common.ErrInvalidRequest(domain.ErrAlreadyExists, "email")
+	}
+	if err != domain.ErrRecordNotFound {
+		logger.Errorf("userRepo.GetUserByEmail %w", err)
+		return common.ErrCannotGetEntity(domain.User{}.DomainName(), err)
+	}
+	return nil
+}
+
+// getUserByEmail returns the user owning the email or an invalid request error when there is none,
+// the tombstone of a purged user is owned by none
+func getUserByEmail(ctx context.Context, userRepo domain.UserRepo, email string) (domain.User, error) {
+	if domain.IsUserTombstoneEmail(email) {
+		return domain.User{}, common.ErrInvalidRequest(domain.ErrNotFoundUserByEmail, "email")
+	}
+	user, err := userRepo.GetUserByEmail(ctx, email)
+	if err != nil {
+		if err == domain.ErrRecordNotFound {
+			return domain.User{}, common.ErrInvalidRequest(domain.ErrNotFoundUserByEmail, "email")
+		}
+		logger.Errorf("userRepo.GetUserByEmail %w", err)
+		return domain.User{}, common.ErrCannotGetEntity(user.DomainName(), err)
+	}
+	return user, nil
+}
@@ -0,0 +1,91 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/auth"
+	mockRepo "github.com/phantranhieunhan/s3-assignment/mock/friendship/repository"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestCase_User_CreateUser struct {
+	name string
+	err  error
+
+	withinTransactionError error
+
+	getUserByEmailError error
+	createError         error
+}
+
+func TestUser_CreateUser(t *testing.T) {
+	t.Parallel()
+
+	email := "email-1"
+	password := "password"
+	userID := "user-1"
+	errDB := errors.New("some error from db")
+
+	tcs := []TestCase_User_CreateUser{
+		{
+			name:                "create user successfully",
+			getUserByEmailError: domain.ErrRecordNotFound,
+		},
+		{
+			name:                   "create user fail because email already exists",
+			withinTransactionError: common.ErrInvalidRequest(domain.ErrAlreadyExists, "email"),
+			err:                    common.ErrInvalidRequest(domain.ErrAlreadyExists, "email"),
+		},
+		{
+			name:                   "create user fail because get user fail",
+			getUserByEmailError:    errDB,
+			withinTransactionError: common.ErrCannotGetEntity(domain.User{}.DomainName(), errDB),
+			err:                    common.ErrCannotGetEntity(domain.User{}.DomainName(), errDB),
+		},
+		{
+			name:                   "create user fail because create fail",
+			getUserByEmailError:    domain.ErrRecordNotFound,
+			createError:            errDB,
+			withinTransactionError: common.ErrCannotCreateEntity(domain.User{}.DomainName(), errDB),
+			err:                    common.ErrCannotCreateEntity(domain.User{}.DomainName(), errDB),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			mockUserRepo := new(mockRepo.MockUserRepository)
+			mockTransaction := new(mockRepo.MockTransaction)
+			h := NewCreateUserHandler(mockUserRepo, allowRecordEvent(), allowRecordAudit(), mockTransaction)
+
+			prepareWithinTransaction(t, ctx, mockTransaction, tc.withinTransactionError)
+			mockUserRepo.On("GetUserByEmail", ctx, email).Return(domain.User{}, tc.getUserByEmailError).Once()
+			if tc.getUserByEmailError == domain.ErrRecordNotFound {
+				mockUserRepo.On("Create", ctx, mock.MatchedBy(func(d domain.User) bool {
+					return d.Email == email && !d.Base.CreatedAt.IsZero() && auth.ComparePassword(d.Password, password) == nil
+				})).Return(userID, tc.createError).Once()
+			}
+
+			user, err := h.Handle(ctx, payload.CreateUserPayload{Email: email, Password: password})
+			assert.Equal(t, tc.err, err)
+			if tc.err == nil {
+				assert.Equal(t, userID, user.Base.Id)
+				assert.Equal(t, email, user.Email)
+				assert.Empty(t, user.Password)
+			} else {
+				assert.Equal(t, domain.User{}, user)
+			}
+			mock.AssertExpectationsForObjects(t, mockUserRepo, mockTransaction)
+		})
+	}
+}
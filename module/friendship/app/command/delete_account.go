@@ -0,0 +1,135 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+type DeleteAccountHandler struct {
+	purger      accountPurger
+	userRepo    domain.UserRepo
+	eventRepo   domain.EventRepo
+	auditRepo   domain.AuditRepo
+	transactor  Transactor
+	gracePeriod time.Duration
+}
+
+func NewDeleteAccountHandler(userRepo domain.UserRepo, friendshipRepo domain.FriendshipRepo, subscriptionRepo domain.SubscriptionRepo, blockRepo domain.BlockRepo,
+	updateRepo domain.UpdateRepo, eventRepo domain.EventRepo, auditRepo domain.AuditRepo, transactor Transactor, gracePeriod time.Duration) DeleteAccountHandler {
+	return DeleteAccountHandler{
+		purger:      newAccountPurger(userRepo, friendshipRepo, subscriptionRepo, blockRepo, updateRepo, eventRepo, auditRepo),
+		userRepo:    userRepo,
+		eventRepo:   eventRepo,
+		auditRepo:   auditRepo,
+		transactor:  transactor,
+		gracePeriod: gracePeriod,
+	}
+}
+
+// Handle deletes the account of the user. Without a grace period the account is purged right away, otherwise
+// its deletion is scheduled and can be cancelled until the purge. Deleting an account already scheduled returns
+// the deletion as it is
+func (h DeleteAccountHandler) Handle(ctx context.Context, email string) (domain.UserDeletion, error) {
+	var deletion domain.UserDeletion
+
+	err := h.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		user, err := getUserByEmail(ctx, h.userRepo, email)
+		if err != nil {
+			return err
+		}
+
+		if err = authorizeRequestor(ctx, user.Base.Id); err != nil {
+			return err
+		}
+
+		now := time.Now().UTC()
+		if h.gracePeriod <= 0 {
+			deletion = domain.UserDeletion{UserID: user.Base.Id, RequestedAt: now, PurgeAt: now}
+			return h.purger.purge(ctx, user, domain.AuditDeleteAccount, domain.AuditStatusActive)
+		}
+
+		deletion, err = h.userRepo.GetDeletion(ctx, user.Base.Id)
+		if err == nil {
+			return nil
+		}
+		if err != domain.ErrRecordNotFound {
+			logger.Errorf("userRepo.GetDeletion %w", err)
+			return common.ErrCannotGetEntity(deletion.DomainName(), err)
+		}
+
+		deletion = domain.UserDeletion{UserID: user.Base.Id, RequestedAt: now, PurgeAt: now.Add(h.gracePeriod)}
+		if err = h.userRepo.ScheduleDeletion(ctx, deletion); err != nil {
+			logger.Errorf("userRepo.ScheduleDeletion %w", err)
+			return common.ErrCannotCreateEntity(deletion.DomainName(), err)
+		}
+		if err = recordEvent(ctx, h.eventRepo, domain.EventUserDeletionScheduled, user.Base.Id, deletion); err != nil {
+			return err
+		}
+		return recordAudit(ctx, h.auditRepo, domain.AuditDeleteAccount, domain.AuditStatusActive, domain.AuditStatusDeletionScheduled, user.Base.Id)
+	})
+	if err != nil {
+		return domain.UserDeletion{}, err
+	}
+
+	return deletion, nil
+}
+
+// accountPurger removes what ties a user to the others and anonymizes the user. The user row stays under
+// a tombstone email so that its updates keep their sender, the email itself is free again and is taken out
+// of the mentions of the updates
+type accountPurger struct {
+	userRepo         domain.UserRepo
+	friendshipRepo   domain.FriendshipRepo
+	subscriptionRepo domain.SubscriptionRepo
+	blockRepo        domain.BlockRepo
+	updateRepo       domain.UpdateRepo
+	eventRepo        domain.EventRepo
+	auditRepo        domain.AuditRepo
+}
+
+func newAccountPurger(userRepo domain.UserRepo, friendshipRepo domain.FriendshipRepo, subscriptionRepo domain.SubscriptionRepo, blockRepo domain.BlockRepo,
+	updateRepo domain.UpdateRepo, eventRepo domain.EventRepo, auditRepo domain.AuditRepo) accountPurger {
+	return accountPurger{
+		userRepo:         userRepo,
+		friendshipRepo:   friendshipRepo,
+		subscriptionRepo: subscriptionRepo,
+		blockRepo:        blockRepo,
+		updateRepo:       updateRepo,
+		eventRepo:        eventRepo,
+		auditRepo:        auditRepo,
+	}
+}
+
+// purge must run within a transaction, the account is purged entirely or not at all.
+// The audit entry records the action purging the account and the status of the account before it
+func (p accountPurger) purge(ctx context.Context, user domain.User, action domain.AuditAction, before domain.AuditStatus) error {
+	if err := p.friendshipRepo.DeleteByUserID(ctx, user.Base.Id); err != nil {
+		logger.Errorf("friendshipRepo.DeleteByUserID %w", err)
+		return common.ErrCannotDeleteEntity(domain.Friendship{}.DomainName(), err)
+	}
+	if err := p.subscriptionRepo.DeleteByUserID(ctx, user.Base.Id); err != nil {
+		logger.Errorf("subscriptionRepo.DeleteByUserID %w", err)
+		return common.ErrCannotDeleteEntity(domain.Subscription{}.DomainName(), err)
+	}
+	if err := p.blockRepo.DeleteByUserID(ctx, user.Base.Id); err != nil {
+		logger.Errorf("blockRepo.DeleteByUserID %w", err)
+		return common.ErrCannotDeleteEntity(domain.Block{}.DomainName(), err)
+	}
+	if err := p.updateRepo.RemoveMentions(ctx, user.Email); err != nil {
+		logger.Errorf("updateRepo.RemoveMentions %w", err)
+		return common.ErrCannotUpdateEntity(domain.Update{}.DomainName(), err)
+	}
+	if err := p.userRepo.Anonymize(ctx, user.Base.Id, domain.UserTombstoneEmail(user.Base.Id)); err != nil {
+		logger.Errorf("userRepo.Anonymize %w", err)
+		return common.ErrCannotUpdateEntity(user.DomainName(), err)
+	}
+	// the event outlives the account in the outbox and goes to the webhooks, it must not carry the email
+	if err := recordEvent(ctx, p.eventRepo, domain.EventUserDeleted, user.Base.Id, domain.UserDeletedEvent{UserID: user.Base.Id}); err != nil {
+		return err
+	}
+	return recordAudit(ctx, p.auditRepo, action, before, domain.AuditStatusDeleted, user.Base.Id)
+}
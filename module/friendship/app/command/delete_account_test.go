@@ -0,0 +1,176 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/auth"
+	mockRepo "github.com/phantranhieunhan/s3-assignment/mock/friendship/repository"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestCase_User_DeleteAccount struct {
+	name        string
+	authUserID  string
+	gracePeriod time.Duration
+	err         error
+
+	withinTransactionError error
+
+	getUserByEmailError   error
+	getDeletionData       domain.UserDeletion
+	getDeletionError      error
+	scheduleDeletionError error
+
+	// purge is set when the account is expected to be purged right away
+	purge                  bool
+	deleteFriendshipsError error
+	anonymizeError         error
+
+	scheduled bool
+}
+
+func TestUser_DeleteAccount(t *testing.T) {
+	t.Parallel()
+
+	email := "email-1"
+	user := domain.User{Base: domain.Base{Id: "user-1"}, Email: email}
+	scheduled := domain.UserDeletion{UserID: "user-1", RequestedAt: time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC), PurgeAt: time.Date(2023, 3, 31, 10, 0, 0, 0, time.UTC)}
+	errDB := errors.New("some error from db")
+
+	tcs := []TestCase_User_DeleteAccount{
+		{
+			name:             "schedule the deletion successfully",
+			authUserID:       "user-1",
+			gracePeriod:      time.Hour,
+			getDeletionError: domain.ErrRecordNotFound,
+			scheduled:        true,
+		},
+		{
+			name:            "return the deletion already scheduled",
+			gracePeriod:     time.Hour,
+			getDeletionData: scheduled,
+		},
+		{
+			name:  "purge the account right away without a grace period",
+			purge: true,
+		},
+		{
+			name:                   "delete account fail because user not found",
+			gracePeriod:            time.Hour,
+			getUserByEmailError:    domain.ErrRecordNotFound,
+			withinTransactionError: common.ErrInvalidRequest(domain.ErrNotFoundUserByEmail, "email"),
+			err:                    common.ErrInvalidRequest(domain.ErrNotFoundUserByEmail, "email"),
+		},
+		{
+			name:                   "delete account fail because requestor is another user",
+			authUserID:             "user-2",
+			gracePeriod:            time.Hour,
+			withinTransactionError: common.NewForbidden(domain.ErrRequestorIsNotAuthenticatedUser, domain.ErrRequestorIsNotAuthenticatedUser.Error(), "ErrForbidden"),
+			err:                    common.NewForbidden(domain.ErrRequestorIsNotAuthenticatedUser, domain.ErrRequestorIsNotAuthenticatedUser.Error(), "ErrForbidden"),
+		},
+		{
+			name:                   "delete account fail because get deletion fail",
+			gracePeriod:            time.Hour,
+			getDeletionError:       errDB,
+			withinTransactionError: common.ErrCannotGetEntity(domain.UserDeletion{}.DomainName(), errDB),
+			err:                    common.ErrCannotGetEntity(domain.UserDeletion{}.DomainName(), errDB),
+		},
+		{
+			name:                   "delete account fail because schedule deletion fail",
+			gracePeriod:            time.Hour,
+			getDeletionError:       domain.ErrRecordNotFound,
+			scheduleDeletionError:  errDB,
+			withinTransactionError: common.ErrCannotCreateEntity(domain.UserDeletion{}.DomainName(), errDB),
+			err:                    common.ErrCannotCreateEntity(domain.UserDeletion{}.DomainName(), errDB),
+		},
+		{
+			name:                   "delete account fail because delete friendships fail",
+			purge:                  true,
+			deleteFriendshipsError: errDB,
+			withinTransactionError: common.ErrCannotDeleteEntity(domain.Friendship{}.DomainName(), errDB),
+			err:                    common.ErrCannotDeleteEntity(domain.Friendship{}.DomainName(), errDB),
+		},
+		{
+			name:                   "delete account fail because anonymize fail",
+			purge:                  true,
+			anonymizeError:         errDB,
+			withinTransactionError: common.ErrCannotUpdateEntity(domain.User{}.DomainName(), errDB),
+			err:                    common.ErrCannotUpdateEntity(domain.User{}.DomainName(), errDB),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			// the requestor is the authenticated user unless the case says otherwise
+			authUserID := tc.authUserID
+			if authUserID == "" {
+				authUserID = "user-1"
+			}
+			ctx = auth.WithUserID(ctx, authUserID)
+
+			mockUserRepo := new(mockRepo.MockUserRepository)
+			mockFriendshipRepo := new(mockRepo.MockFriendshipRepository)
+			mockSubscriptionRepo := new(mockRepo.MockSubscriptionRepository)
+			mockBlockRepo := new(mockRepo.MockBlockRepository)
+			mockUpdateRepo := new(mockRepo.MockUpdateRepository)
+			mockEventRepo := allowRecordEvent()
+			mockTransaction := new(mockRepo.MockTransaction)
+			mockAuditRepo := allowRecordAudit()
+			h := NewDeleteAccountHandler(mockUserRepo, mockFriendshipRepo, mockSubscriptionRepo, mockBlockRepo, mockUpdateRepo, mockEventRepo, mockAuditRepo, mockTransaction, tc.gracePeriod)
+
+			prepareWithinTransaction(t, ctx, mockTransaction, tc.withinTransactionError)
+			mockUserRepo.On("GetUserByEmail", ctx, email).Return(user, tc.getUserByEmailError).Once()
+			mockUserRepo.On("GetDeletion", ctx, "user-1").Return(tc.getDeletionData, tc.getDeletionError).Maybe()
+			mockUserRepo.On("ScheduleDeletion", ctx, mock.MatchedBy(func(d domain.UserDeletion) bool {
+				return d.UserID == "user-1" && d.PurgeAt.Sub(d.RequestedAt) == tc.gracePeriod
+			})).Return(tc.scheduleDeletionError).Maybe()
+			if tc.purge {
+				mockFriendshipRepo.On("DeleteByUserID", ctx, "user-1").Return(tc.deleteFriendshipsError).Once()
+				if tc.deleteFriendshipsError == nil {
+					mockSubscriptionRepo.On("DeleteByUserID", ctx, "user-1").Return(nil).Once()
+					mockBlockRepo.On("DeleteByUserID", ctx, "user-1").Return(nil).Once()
+					mockUpdateRepo.On("RemoveMentions", ctx, email).Return(nil).Once()
+					mockUserRepo.On("Anonymize", ctx, "user-1", domain.UserTombstoneEmail("user-1")).Return(tc.anonymizeError).Once()
+				}
+			}
+
+			deletion, err := h.Handle(ctx, email)
+			assert.Equal(t, tc.err, err)
+			mock.AssertExpectationsForObjects(t, mockUserRepo, mockFriendshipRepo, mockSubscriptionRepo, mockBlockRepo, mockUpdateRepo, mockTransaction)
+
+			switch {
+			case tc.err != nil:
+				assert.Equal(t, domain.UserDeletion{}, deletion)
+			case tc.scheduled:
+				mockUserRepo.AssertCalled(t, "ScheduleDeletion", ctx, deletion)
+				mockEventRepo.AssertCalled(t, "Create", ctx, mock.MatchedBy(func(e domain.Event) bool {
+					return e.Type == domain.EventUserDeletionScheduled && e.AggregateID == "user-1"
+				}))
+				mockAuditRepo.AssertCalled(t, "Create", ctx, mock.MatchedBy(func(e domain.AuditEntry) bool {
+					return e.Action == domain.AuditDeleteAccount && e.StatusAfter == domain.AuditStatusDeletionScheduled
+				}))
+			case tc.purge:
+				assert.Equal(t, deletion.RequestedAt, deletion.PurgeAt)
+				mockEventRepo.AssertCalled(t, "Create", ctx, mock.MatchedBy(func(e domain.Event) bool {
+					return e.Type == domain.EventUserDeleted && e.AggregateID == "user-1"
+				}))
+				mockAuditRepo.AssertCalled(t, "Create", ctx, mock.MatchedBy(func(e domain.AuditEntry) bool {
+					return e.Action == domain.AuditDeleteAccount && e.StatusBefore == domain.AuditStatusActive && e.StatusAfter == domain.AuditStatusDeleted
+				}))
+			default:
+				assert.Equal(t, tc.getDeletionData, deletion)
+				mockUserRepo.AssertNotCalled(t, "ScheduleDeletion", mock.Anything, mock.Anything)
+				mockAuditRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
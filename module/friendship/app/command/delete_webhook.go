@@ -0,0 +1,37 @@
+package command
+
+import (
+	"context"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+type DeleteWebhookHandler struct {
+	webhookRepo domain.WebhookRepo
+	auditRepo   domain.AuditRepo
+	transactor  Transactor
+}
+
+func NewDeleteWebhookHandler(webhookRepo domain.WebhookRepo, auditRepo domain.AuditRepo, transactor Transactor) DeleteWebhookHandler {
+	return DeleteWebhookHandler{
+		webhookRepo: webhookRepo,
+		auditRepo:   auditRepo,
+		transactor:  transactor,
+	}
+}
+
+// Handle deletes the webhook, its pending and dead deliveries are dropped with it
+func (h DeleteWebhookHandler) Handle(ctx context.Context, id string) error {
+	return h.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := h.webhookRepo.Delete(ctx, id); err != nil {
+			if err == domain.ErrRecordNotFound {
+				return common.ErrInvalidRequest(err, "id")
+			}
+			logger.Errorf("webhookRepo.Delete %w", err)
+			return common.ErrCannotDeleteEntity(domain.Webhook{}.DomainName(), err)
+		}
+		return recordAudit(ctx, h.auditRepo, domain.AuditDeleteWebhook, domain.AuditStatusActive, domain.AuditStatusNone, id)
+	})
+}
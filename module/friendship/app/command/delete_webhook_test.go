@@ -0,0 +1,73 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	mockRepo "github.com/phantranhieunhan/s3-assignment/mock/friendship/repository"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestCase_Webhook_DeleteWebhook struct {
+	name string
+
+	deleteError      error
+	recordAuditError error
+
+	err error
+}
+
+func TestWebhook_DeleteWebhook(t *testing.T) {
+	t.Parallel()
+
+	errDB := errors.New("some error from db")
+
+	tcs := []TestCase_Webhook_DeleteWebhook{
+		{
+			name: "delete webhook successfully",
+		},
+		{
+			name:        "delete webhook fail because webhook not found",
+			deleteError: domain.ErrRecordNotFound,
+			err:         common.ErrInvalidRequest(domain.ErrRecordNotFound, "id"),
+		},
+		{
+			name:        "delete webhook fail because delete fail",
+			deleteError: errDB,
+			err:         common.ErrCannotDeleteEntity(domain.Webhook{}.DomainName(), errDB),
+		},
+		{
+			name:             "delete webhook fail because record audit fail",
+			recordAuditError: errDB,
+			err:              common.ErrCannotCreateEntity(domain.AuditEntry{}.DomainName(), errDB),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			mockWebhookRepo := new(mockRepo.MockWebhookRepository)
+			mockAuditRepo := new(mockRepo.MockAuditRepository)
+			mockTransaction := new(mockRepo.MockTransaction)
+			h := NewDeleteWebhookHandler(mockWebhookRepo, mockAuditRepo, mockTransaction)
+
+			prepareWithinTransaction(t, ctx, mockTransaction, tc.err)
+			mockWebhookRepo.On("Delete", ctx, "webhook-id").Return(tc.deleteError).Once()
+			if tc.deleteError == nil {
+				prepareRecordAudit(ctx, mockAuditRepo, domain.AuditDeleteWebhook, domain.AuditStatusActive, domain.AuditStatusNone, tc.recordAuditError)
+			}
+
+			err := h.Handle(ctx, "webhook-id")
+			assert.Equal(t, tc.err, err)
+			mock.AssertExpectationsForObjects(t, mockWebhookRepo, mockAuditRepo, mockTransaction)
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package command
+
+import (
+	"context"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+// recordEvent writes the event in the outbox, it must run within the transaction of the change it describes
+// so that the event is stored if and only if the change is
+func recordEvent(ctx context.Context, eventRepo domain.EventRepo, eventType domain.EventType, aggregateID string, payload interface{}) error {
+	e, err := domain.NewEvent(eventType, aggregateID, payload)
+	if err != nil {
+		logger.Errorf("domain.NewEvent %w", err)
+		return common.ErrInternal(err)
+	}
+
+	if _, err = eventRepo.Create(ctx, e); err != nil {
+		logger.Errorf("eventRepo.Create %w", err)
+		return common.ErrCannotCreateEntity(e.DomainName(), err)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	mockRepo "github.com/phantranhieunhan/s3-assignment/mock/friendship/repository"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// allowRecordEvent returns an event repository accepting any event, for the tests not asserting on the outbox
+func allowRecordEvent() *mockRepo.MockEventRepository {
+	m := new(mockRepo.MockEventRepository)
+	m.On("Create", mock.Anything, mock.Anything).Return("event-id", nil).Maybe()
+	return m
+}
+
+func prepareRecordEvent(ctx context.Context, m *mockRepo.MockEventRepository, eventType domain.EventType, aggregateID string, err error) {
+	m.On("Create", ctx, mock.MatchedBy(func(e domain.Event) bool {
+		return e.Type == eventType && e.AggregateID == aggregateID
+	})).Return("event-id", err).Once()
+}
+
+type TestCase_RecordEvent struct {
+	name    string
+	payload interface{}
+
+	createError error
+
+	err error
+}
+
+func TestRecordEvent(t *testing.T) {
+	t.Parallel()
+
+	errDB := errors.New("some error from db")
+
+	tcs := []TestCase_RecordEvent{
+		{
+			name:    "record event successfully",
+			payload: domain.RelationEvent{UserID: "user-1", TargetID: "user-2"},
+		},
+		{
+			name:    "record event fail because create event fail",
+			payload: domain.RelationEvent{UserID: "user-1", TargetID: "user-2"},
+
+			createError: errDB,
+			err:         common.ErrCannotCreateEntity(domain.Event{}.DomainName(), errDB),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			mockEventRepo := new(mockRepo.MockEventRepository)
+			mockEventRepo.On("Create", ctx, mock.MatchedBy(func(e domain.Event) bool {
+				return e.Type == domain.EventUserBlocked && e.AggregateID == "block-id" &&
+					string(e.Payload) == `{"user_id":"user-1","target_id":"user-2"}`
+			})).Return("event-id", tc.createError).Once()
+
+			err := recordEvent(ctx, mockEventRepo, domain.EventUserBlocked, "block-id", tc.payload)
+			assert.Equal(t, tc.err, err)
+			mock.AssertExpectationsForObjects(t, mockEventRepo)
+		})
+	}
+}
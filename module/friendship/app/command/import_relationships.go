@@ -0,0 +1,190 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+type ImportRelationshipsHandler struct {
+	friendshipRepo   domain.FriendshipRepo
+	userRepo         domain.UserRepo
+	subscriptionRepo domain.SubscriptionRepo
+	eventRepo        domain.EventRepo
+	auditRepo        domain.AuditRepo
+	transactor       Transactor
+}
+
+func NewImportRelationshipsHandler(repo domain.FriendshipRepo, userRepo domain.UserRepo, subscriptionRepo domain.SubscriptionRepo, eventRepo domain.EventRepo, auditRepo domain.AuditRepo, transactor Transactor) ImportRelationshipsHandler {
+	return ImportRelationshipsHandler{
+		friendshipRepo:   repo,
+		userRepo:         userRepo,
+		subscriptionRepo: subscriptionRepo,
+		eventRepo:        eventRepo,
+		auditRepo:        auditRepo,
+		transactor:       transactor,
+	}
+}
+
+// Handle writes a batch of rows within a single transaction and returns the error of each row, nil for a row imported.
+// A row breaking a rule of the domain is checked before anything of it is written, it is skipped and the batch goes on;
+// any other error rolls the whole batch back and is returned as the error of the batch.
+func (h ImportRelationshipsHandler) Handle(ctx context.Context, rows payload.ImportRelationshipPayloads) ([]error, error) {
+	var rowErrs []error
+	err := h.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		rowErrs = make([]error, len(rows))
+		for i, row := range rows {
+			err := h.importRow(ctx, row)
+			if err != nil && !isInvalidRequest(err) {
+				return err
+			}
+			rowErrs[i] = err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rowErrs, nil
+}
+
+func (h ImportRelationshipsHandler) importRow(ctx context.Context, row payload.ImportRelationshipPayload) error {
+	if row.Type == payload.ImportRelationshipUser {
+		return h.importUser(ctx, row.User)
+	}
+
+	userIDs, err := h.userRepo.GetUserIDsByEmails(ctx, []string{row.User, row.Target})
+	if err != nil {
+		if err == domain.ErrNotFoundUserByEmail {
+			return common.ErrInvalidRequest(err, "emails")
+		}
+		logger.Errorf("userRepo.GetUserIDsByEmails %w", err)
+		return common.ErrCannotGetEntity(domain.User{}.DomainName(), err)
+	}
+	userID, targetID := userIDs[row.User], userIDs[row.Target]
+
+	switch row.Type {
+	case payload.ImportRelationshipFriendship:
+		return h.importFriendship(ctx, userID, targetID)
+	case payload.ImportRelationshipSubscription:
+		return h.subscribe(ctx, domain.Subscription{UserID: targetID, SubscriberID: userID})
+	}
+	return common.ErrInvalidRequest(domain.ErrImportTypeIsNotValid, "type")
+}
+
+// importUser registers the user without a password, the admin sets one before the user can log in
+func (h ImportRelationshipsHandler) importUser(ctx context.Context, email string) error {
+	if err := ensureEmailIsAvailable(ctx, h.userRepo, email); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	user := domain.User{
+		Base: domain.Base{
+			CreatedAt: now,
+			UpdatedAt: now,
+		},
+		Email: email,
+	}
+	id, err := h.userRepo.Create(ctx, user)
+	if err != nil {
+		logger.Errorf("userRepo.Create %w", err)
+		return common.ErrCannotCreateEntity(user.DomainName(), err)
+	}
+	if err = recordEvent(ctx, h.eventRepo, domain.EventUserCreated, id, domain.UserEvent{Email: email}); err != nil {
+		return err
+	}
+	return recordAudit(ctx, h.auditRepo, domain.AuditImportRelationships, domain.AuditStatusNone, domain.AuditStatusActive, id)
+}
+
+// importFriendship connects the users and makes them receive updates from each other, the same as a connection
+func (h ImportRelationshipsHandler) importFriendship(ctx context.Context, userID, friendID string) error {
+	d := domain.Friendship{
+		Status:   domain.FriendshipStatusFriended,
+		UserID:   userID,
+		FriendID: friendID,
+	}
+
+	before := domain.AuditStatusNone
+	f, err := h.friendshipRepo.GetFriendshipByUserIDs(ctx, userID, friendID)
+	if err != nil && err != domain.ErrRecordNotFound {
+		logger.Errorf("friendshipRepo.GetFriendshipByUserIDs %w", err)
+		return common.ErrCannotGetEntity(d.DomainName(), err)
+	}
+	if err == domain.ErrRecordNotFound {
+		d.Id, err = h.friendshipRepo.Create(ctx, d)
+		if err != nil {
+			logger.Errorf("friendshipRepo.Create %w", err)
+			return common.ErrCannotCreateEntity(d.DomainName(), err)
+		}
+	} else {
+		if !f.Status.CanConnect() {
+			return common.ErrInvalidRequest(domain.ErrFriendshipIsUnavailable, "")
+		}
+		if err = h.friendshipRepo.UpdateStatus(ctx, f.Id, domain.FriendshipStatusFriended); err != nil {
+			logger.Errorf("friendshipRepo.UpdateStatus %w", err)
+			return common.ErrCannotUpdateEntity(d.DomainName(), err)
+		}
+		d.Id = f.Id
+		before = domain.FriendshipAuditStatus(f.Status)
+	}
+	if err = recordEvent(ctx, h.eventRepo, domain.EventFriendshipConnected, d.Id, domain.RelationEvent{UserID: userID, TargetID: friendID}); err != nil {
+		return err
+	}
+	if err = recordAudit(ctx, h.auditRepo, domain.AuditImportRelationships, before, domain.AuditStatusFriended, userID, friendID); err != nil {
+		return err
+	}
+
+	// a subscription the friends already have is kept
+	for _, sub := range []domain.Subscription{
+		{UserID: friendID, SubscriberID: userID},
+		{UserID: userID, SubscriberID: friendID},
+	} {
+		if err = h.subscribe(ctx, sub); err != nil && !isInvalidRequest(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// subscribe creates or renews the subscription, a subscription already running is an invalid request
+func (h ImportRelationshipsHandler) subscribe(ctx context.Context, sub domain.Subscription) error {
+	got, err := h.subscriptionRepo.GetSubscription(ctx, domain.Subscriptions{sub})
+	if err != nil {
+		logger.Errorf("subscriptionRepo.GetSubscription %w", err)
+		return common.ErrCannotGetEntity(sub.DomainName(), err)
+	}
+	if len(got) > 0 {
+		sub = got[0]
+	}
+	if !sub.Status.AllowSubscribe() {
+		return common.ErrInvalidRequest(domain.ErrAlreadyExists, "emails")
+	}
+
+	before := domain.SubscriptionAuditStatus(sub.Status)
+	if sub.Status.IsNoneExisted() {
+		sub.Status = domain.SubscriptionStatusSubscribed
+		if sub.Id, err = h.subscriptionRepo.Create(ctx, sub); err != nil {
+			logger.Errorf("subscriptionRepo.Create %w", err)
+			return common.ErrCannotCreateEntity(sub.DomainName(), err)
+		}
+	} else if err = h.subscriptionRepo.UpdateStatus(ctx, sub.Id, domain.SubscriptionStatusSubscribed); err != nil {
+		logger.Errorf("subscriptionRepo.UpdateStatus %w", err)
+		return common.ErrCannotUpdateEntity(sub.DomainName(), err)
+	}
+	if err = recordEvent(ctx, h.eventRepo, domain.EventUserSubscribed, sub.Id, domain.RelationEvent{UserID: sub.SubscriberID, TargetID: sub.UserID}); err != nil {
+		return err
+	}
+	return recordAudit(ctx, h.auditRepo, domain.AuditImportRelationships, before, domain.AuditStatusSubscribed, sub.SubscriberID, sub.UserID)
+}
+
+// isInvalidRequest reports whether the error is a request breaking a rule of the domain, rather than a failure of the storage
+func isInvalidRequest(err error) bool {
+	appErr, ok := err.(*common.AppError)
+	return ok && appErr.Key == "ErrInvalidRequest"
+}
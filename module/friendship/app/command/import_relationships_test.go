@@ -0,0 +1,210 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	mockRepo "github.com/phantranhieunhan/s3-assignment/mock/friendship/repository"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestCase_ImportRelationships struct {
+	name string
+	rows payload.ImportRelationshipPayloads
+
+	getUserIDsByEmailsError error
+
+	getFriendshipStatus domain.FriendshipStatus
+
+	getSubscriptionStatus domain.SubscriptionStatus
+	getSubscriptionError  error
+
+	createFriendshipError error
+
+	rowErrs []error
+	err     error
+}
+
+func TestImportRelationships(t *testing.T) {
+	t.Parallel()
+
+	emails := []string{"email-1", "email-2"}
+	mapEmails := map[string]string{emails[0]: "user-1", emails[1]: "user-2"}
+	friendship := payload.ImportRelationshipPayload{Type: payload.ImportRelationshipFriendship, User: emails[0], Target: emails[1]}
+	subscription := payload.ImportRelationshipPayload{Type: payload.ImportRelationshipSubscription, User: emails[0], Target: emails[1]}
+
+	errDB := errors.New("some error from db")
+
+	tcs := []TestCase_ImportRelationships{
+		{
+			name:    "import a friendship and subscribe the friends to each other",
+			rows:    payload.ImportRelationshipPayloads{friendship},
+			rowErrs: []error{nil},
+		},
+		{
+			name:                  "import a friendship and keep the subscriptions the friends already have",
+			rows:                  payload.ImportRelationshipPayloads{friendship},
+			getSubscriptionStatus: domain.SubscriptionStatusSubscribed,
+			rowErrs:               []error{nil},
+		},
+		{
+			name:    "import a subscription",
+			rows:    payload.ImportRelationshipPayloads{subscription},
+			rowErrs: []error{nil},
+		},
+		{
+			name:                  "skip a subscription which already exists",
+			rows:                  payload.ImportRelationshipPayloads{subscription},
+			getSubscriptionStatus: domain.SubscriptionStatusSubscribed,
+			rowErrs:               []error{common.ErrInvalidRequest(domain.ErrAlreadyExists, "emails")},
+		},
+		{
+			name:                "skip a friendship which is unavailable",
+			rows:                payload.ImportRelationshipPayloads{friendship},
+			getFriendshipStatus: domain.FriendshipStatusBlocked,
+			rowErrs:             []error{common.ErrInvalidRequest(domain.ErrFriendshipIsUnavailable, "")},
+		},
+		{
+			name:                    "skip a row of unknown users",
+			rows:                    payload.ImportRelationshipPayloads{subscription},
+			getUserIDsByEmailsError: domain.ErrNotFoundUserByEmail,
+			rowErrs:                 []error{common.ErrInvalidRequest(domain.ErrNotFoundUserByEmail, "emails")},
+		},
+		{
+			name:                    "fail the batch because get user ids fail",
+			rows:                    payload.ImportRelationshipPayloads{subscription},
+			getUserIDsByEmailsError: errDB,
+			err:                     common.ErrCannotGetEntity(domain.User{}.DomainName(), errDB),
+		},
+		{
+			name:                 "fail the batch because get subscription fail",
+			rows:                 payload.ImportRelationshipPayloads{subscription},
+			getSubscriptionError: errDB,
+			err:                  common.ErrCannotGetEntity(domain.Subscription{}.DomainName(), errDB),
+		},
+		{
+			name:                  "fail the batch because create friendship fail",
+			rows:                  payload.ImportRelationshipPayloads{friendship},
+			createFriendshipError: errDB,
+			err:                   common.ErrCannotCreateEntity(domain.Friendship{}.DomainName(), errDB),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			mockFriendshipRepo := new(mockRepo.MockFriendshipRepository)
+			mockUserRepo := new(mockRepo.MockUserRepository)
+			mockSubscriptionRepo := new(mockRepo.MockSubscriptionRepository)
+			mockTransaction := new(mockRepo.MockTransaction)
+			h := NewImportRelationshipsHandler(mockFriendshipRepo, mockUserRepo, mockSubscriptionRepo, allowRecordEvent(), allowRecordAudit(), mockTransaction)
+
+			mockTransaction.On("WithinTransaction", ctx, mock.Anything).Run(func(args mock.Arguments) {
+				f := args[1].(func(ctx context.Context) error)
+				assert.Equal(t, tc.err, f(ctx))
+			}).Return(tc.err).Once()
+			mockUserRepo.On("GetUserIDsByEmails", ctx, emails).Return(mapEmails, tc.getUserIDsByEmailsError).Once()
+
+			if tc.getFriendshipStatus == domain.FriendshipStatusInvalid {
+				mockFriendshipRepo.On("GetFriendshipByUserIDs", ctx, "user-1", "user-2").Return(domain.Friendship{}, domain.ErrRecordNotFound).Maybe()
+			} else {
+				mockFriendshipRepo.On("GetFriendshipByUserIDs", ctx, "user-1", "user-2").Return(domain.Friendship{Base: domain.Base{Id: "friendship-id"}, Status: tc.getFriendshipStatus}, nil).Maybe()
+			}
+			mockFriendshipRepo.On("Create", ctx, domain.Friendship{UserID: "user-1", FriendID: "user-2", Status: domain.FriendshipStatusFriended}).
+				Return("friendship-id", tc.createFriendshipError).Maybe()
+
+			gotSubscriptions := domain.Subscriptions{}
+			if tc.getSubscriptionStatus != domain.SubscriptionStatusInvalid {
+				gotSubscriptions = domain.Subscriptions{{Base: domain.Base{Id: "subscription-id"}, Status: tc.getSubscriptionStatus}}
+			}
+			mockSubscriptionRepo.On("GetSubscription", ctx, mock.Anything).Return(gotSubscriptions, tc.getSubscriptionError).Maybe()
+			mockSubscriptionRepo.On("Create", ctx, mock.MatchedBy(func(sub domain.Subscription) bool {
+				return sub.Status == domain.SubscriptionStatusSubscribed
+			})).Return("subscription-id", nil).Maybe()
+
+			rowErrs, err := h.Handle(ctx, tc.rows)
+			assert.Equal(t, tc.err, err)
+			assert.Equal(t, tc.rowErrs, rowErrs)
+			mock.AssertExpectationsForObjects(t, mockUserRepo, mockTransaction)
+
+			switch {
+			case tc.err != nil || tc.rowErrs[0] != nil:
+				mockSubscriptionRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+			case tc.rows[0].Type == payload.ImportRelationshipSubscription:
+				mockSubscriptionRepo.AssertCalled(t, "Create", ctx, domain.Subscription{UserID: "user-2", SubscriberID: "user-1", Status: domain.SubscriptionStatusSubscribed})
+			case tc.getSubscriptionStatus == domain.SubscriptionStatusInvalid:
+				mockFriendshipRepo.AssertCalled(t, "Create", mock.Anything, mock.Anything)
+				mockSubscriptionRepo.AssertNumberOfCalls(t, "Create", 2)
+			default:
+				mockSubscriptionRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestImportRelationships_Users(t *testing.T) {
+	t.Parallel()
+
+	row := payload.ImportRelationshipPayload{Type: payload.ImportRelationshipUser, User: "email-1"}
+	errDB := errors.New("some error from db")
+
+	tcs := []struct {
+		name            string
+		getUserError    error
+		createUserError error
+		rowErrs         []error
+		err             error
+	}{
+		{
+			name:         "import a user without a password",
+			getUserError: domain.ErrRecordNotFound,
+			rowErrs:      []error{nil},
+		},
+		{
+			name:    "skip a user whose email is already used",
+			rowErrs: []error{common.ErrInvalidRequest(domain.ErrAlreadyExists, "email")},
+		},
+		{
+			name:            "fail the batch because create user fail",
+			getUserError:    domain.ErrRecordNotFound,
+			createUserError: errDB,
+			err:             common.ErrCannotCreateEntity(domain.User{}.DomainName(), errDB),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			mockUserRepo := new(mockRepo.MockUserRepository)
+			mockTransaction := new(mockRepo.MockTransaction)
+			h := NewImportRelationshipsHandler(new(mockRepo.MockFriendshipRepository), mockUserRepo, new(mockRepo.MockSubscriptionRepository), allowRecordEvent(), allowRecordAudit(), mockTransaction)
+
+			mockTransaction.On("WithinTransaction", ctx, mock.Anything).Run(func(args mock.Arguments) {
+				f := args[1].(func(ctx context.Context) error)
+				assert.Equal(t, tc.err, f(ctx))
+			}).Return(tc.err).Once()
+			mockUserRepo.On("GetUserByEmail", ctx, row.User).Return(domain.User{Email: row.User}, tc.getUserError).Once()
+			if tc.getUserError == domain.ErrRecordNotFound {
+				mockUserRepo.On("Create", ctx, mock.MatchedBy(func(u domain.User) bool {
+					return u.Email == row.User && u.Password == ""
+				})).Return("user-1", tc.createUserError).Once()
+			}
+
+			rowErrs, err := h.Handle(ctx, payload.ImportRelationshipPayloads{row})
+			assert.Equal(t, tc.err, err)
+			assert.Equal(t, tc.rowErrs, rowErrs)
+			mock.AssertExpectationsForObjects(t, mockUserRepo, mockTransaction)
+		})
+	}
+}
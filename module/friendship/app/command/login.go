@@ -0,0 +1,87 @@
+package command
+
+import (
+	"context"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/auth"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+type LoginHandler struct {
+	userRepo    domain.UserRepo
+	auditRepo   domain.AuditRepo
+	tokenIssuer TokenIssuer
+}
+
+func NewLoginHandler(userRepo domain.UserRepo, auditRepo domain.AuditRepo, tokenIssuer TokenIssuer) LoginHandler {
+	return LoginHandler{
+		userRepo:    userRepo,
+		auditRepo:   auditRepo,
+		tokenIssuer: tokenIssuer,
+	}
+}
+
+// Handle checks the credentials of the user and issues a signed token for it,
+// an unknown email and a wrong password are reported the same way. An account scheduled for deletion
+// can't log in until it is restored, a purged account has no password left. Only the successful logins are audited,
+// a login changes nothing so the entry is its only write
+func (h LoginHandler) Handle(ctx context.Context, payload payload.LoginPayload) (domain.AuthToken, error) {
+	user, err := checkCredentials(ctx, h.userRepo, payload.Email, payload.Password)
+	if err != nil {
+		return domain.AuthToken{}, err
+	}
+
+	deletion, err := h.userRepo.GetDeletion(ctx, user.Base.Id)
+	if err == nil {
+		return domain.AuthToken{}, common.NewForbidden(domain.ErrUserIsDeleted, domain.ErrUserIsDeleted.Error(), "ErrForbidden")
+	}
+	if err != domain.ErrRecordNotFound {
+		logger.Errorf("userRepo.GetDeletion %w", err)
+		return domain.AuthToken{}, common.ErrCannotGetEntity(deletion.DomainName(), err)
+	}
+
+	token, expiresAt, err := h.tokenIssuer.Issue(user.Base.Id)
+	if err != nil {
+		logger.Errorf("tokenIssuer.Issue %w", err)
+		return domain.AuthToken{}, common.ErrInternal(err)
+	}
+
+	if err = recordAudit(actingAs(ctx, user.Base.Id), h.auditRepo, domain.AuditLogin, domain.AuditStatusActive, domain.AuditStatusActive, user.Base.Id); err != nil {
+		return domain.AuthToken{}, err
+	}
+
+	return domain.AuthToken{Token: token, ExpiresAt: expiresAt}, nil
+}
+
+// checkCredentials returns the user owning the email when the password is its password
+func checkCredentials(ctx context.Context, userRepo domain.UserRepo, email, password string) (domain.User, error) {
+	if domain.IsUserTombstoneEmail(email) {
+		return domain.User{}, invalidCredentials()
+	}
+	user, err := userRepo.GetUserByEmail(ctx, email)
+	if err != nil {
+		if err == domain.ErrRecordNotFound {
+			return domain.User{}, invalidCredentials()
+		}
+		logger.Errorf("userRepo.GetUserByEmail %w", err)
+		return domain.User{}, common.ErrCannotGetEntity(user.DomainName(), err)
+	}
+
+	hashed, err := userRepo.GetPasswordHash(ctx, user.Base.Id)
+	if err != nil {
+		logger.Errorf("userRepo.GetPasswordHash %w", err)
+		return domain.User{}, common.ErrCannotGetEntity(user.DomainName(), err)
+	}
+
+	if err = auth.ComparePassword(hashed, password); err != nil {
+		return domain.User{}, invalidCredentials()
+	}
+	return user, nil
+}
+
+func invalidCredentials() error {
+	return common.NewUnauthorized(auth.ErrInvalidCredentials, auth.ErrInvalidCredentials.Error(), "ErrInvalidCredentials")
+}
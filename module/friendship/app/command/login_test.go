@@ -0,0 +1,142 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/auth"
+	mockRepo "github.com/phantranhieunhan/s3-assignment/mock/friendship/repository"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestCase_User_Login struct {
+	name     string
+	email    string
+	password string
+	err      error
+
+	getUserByEmailError  error
+	getPasswordHashError error
+	scheduledForDeletion bool
+	getDeletionError     error
+	recordAuditError     error
+}
+
+func TestUser_Login(t *testing.T) {
+	t.Parallel()
+
+	email := "email-1"
+	user := domain.User{Base: domain.Base{Id: "user-1"}, Email: email}
+	hashed, err := auth.HashPassword("password")
+	assert.NoError(t, err)
+	errDB := errors.New("some error from db")
+	errInvalidCredentials := common.NewUnauthorized(auth.ErrInvalidCredentials, auth.ErrInvalidCredentials.Error(), "ErrInvalidCredentials")
+
+	tcs := []TestCase_User_Login{
+		{
+			name:     "login successfully",
+			password: "password",
+		},
+		{
+			name:     "login fail because password is wrong",
+			password: "wrong-password",
+			err:      errInvalidCredentials,
+		},
+		{
+			name:                "login fail because email is unknown",
+			password:            "password",
+			getUserByEmailError: domain.ErrRecordNotFound,
+			err:                 errInvalidCredentials,
+		},
+		{
+			name:                "login fail because get user fail",
+			password:            "password",
+			getUserByEmailError: errDB,
+			err:                 common.ErrCannotGetEntity(domain.User{}.DomainName(), errDB),
+		},
+		{
+			name:                 "login fail because get password fail",
+			password:             "password",
+			getPasswordHashError: errDB,
+			err:                  common.ErrCannotGetEntity(domain.User{}.DomainName(), errDB),
+		},
+		{
+			name:                 "login fail because the account is scheduled for deletion",
+			password:             "password",
+			scheduledForDeletion: true,
+			err:                  common.NewForbidden(domain.ErrUserIsDeleted, domain.ErrUserIsDeleted.Error(), "ErrForbidden"),
+		},
+		{
+			name:             "login fail because get deletion fail",
+			password:         "password",
+			getDeletionError: errDB,
+			err:              common.ErrCannotGetEntity(domain.UserDeletion{}.DomainName(), errDB),
+		},
+		{
+			name:     "login fail because the account is purged",
+			email:    domain.UserTombstoneEmail(user.Base.Id),
+			password: "",
+			err:      errInvalidCredentials,
+		},
+		{
+			name:             "login fail because record audit fail",
+			password:         "password",
+			recordAuditError: errDB,
+			err:              common.ErrCannotCreateEntity(domain.AuditEntry{}.DomainName(), errDB),
+		},
+	}
+
+	issuer := auth.NewTokenIssuer("secret", time.Hour)
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			mockUserRepo := new(mockRepo.MockUserRepository)
+			mockAuditRepo := new(mockRepo.MockAuditRepository)
+			h := NewLoginHandler(mockUserRepo, mockAuditRepo, issuer)
+
+			loginEmail := tc.email
+			if loginEmail == "" {
+				loginEmail = email
+			}
+			// the tombstone of a purged account is rejected before any lookup
+			if !domain.IsUserTombstoneEmail(loginEmail) {
+				mockUserRepo.On("GetUserByEmail", ctx, email).Return(user, tc.getUserByEmailError).Once()
+			}
+			if tc.getUserByEmailError == nil && !domain.IsUserTombstoneEmail(loginEmail) {
+				mockUserRepo.On("GetPasswordHash", ctx, user.Base.Id).Return(hashed, tc.getPasswordHashError).Once()
+			}
+			credentialsOK := tc.getUserByEmailError == nil && tc.getPasswordHashError == nil && tc.password == "password"
+			if credentialsOK {
+				deletionError := tc.getDeletionError
+				if !tc.scheduledForDeletion && deletionError == nil {
+					deletionError = domain.ErrRecordNotFound
+				}
+				mockUserRepo.On("GetDeletion", ctx, user.Base.Id).Return(domain.UserDeletion{UserID: user.Base.Id}, deletionError).Once()
+			}
+			if credentialsOK && !tc.scheduledForDeletion && tc.getDeletionError == nil {
+				// the user logging in is the actor of the entry
+				mockAuditRepo.On("Create", mock.Anything, mock.MatchedBy(func(e domain.AuditEntry) bool {
+					return e.Actor == user.Base.Id && e.Action == domain.AuditLogin
+				})).Return("audit-id", tc.recordAuditError).Once()
+			}
+
+			token, err := h.Handle(ctx, payload.LoginPayload{Email: loginEmail, Password: tc.password})
+			assert.Equal(t, tc.err, err)
+			if tc.err == nil {
+				userID, err := issuer.Verify(token.Token)
+				assert.NoError(t, err)
+				assert.Equal(t, user.Base.Id, userID)
+			}
+			mock.AssertExpectationsForObjects(t, mockUserRepo, mockAuditRepo)
+		})
+	}
+}
@@ -0,0 +1,6 @@
+package payload
+
+type FriendRequestPayload struct {
+	Requestor string
+	Target    string
+}
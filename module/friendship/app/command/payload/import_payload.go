@@ -0,0 +1,29 @@
+package payload
+
+// ImportRelationshipType is the kind of row of an import, a user or a relationship between two users
+type ImportRelationshipType string
+
+const (
+	ImportRelationshipUser         ImportRelationshipType = "user"
+	ImportRelationshipFriendship   ImportRelationshipType = "friendship"
+	ImportRelationshipSubscription ImportRelationshipType = "subscription"
+)
+
+func (t ImportRelationshipType) IsValid() bool {
+	switch t {
+	case ImportRelationshipUser, ImportRelationshipFriendship, ImportRelationshipSubscription:
+		return true
+	default:
+		return false
+	}
+}
+
+// ImportRelationshipPayload is a row of an import, either a user registered with the email of User and no target,
+// a friendship between the user and the target or a subscription of the user to the updates of the target
+type ImportRelationshipPayload struct {
+	Type   ImportRelationshipType
+	User   string
+	Target string
+}
+
+type ImportRelationshipPayloads []ImportRelationshipPayload
@@ -0,0 +1,6 @@
+package payload
+
+type PostUpdatePayload struct {
+	Sender string
+	Text   string
+}
@@ -0,0 +1,6 @@
+package payload
+
+type UnblockUserPayload struct {
+	Requestor string
+	Target    string
+}
@@ -0,0 +1,6 @@
+package payload
+
+type UnfriendPayload struct {
+	Requestor string
+	Target    string
+}
@@ -0,0 +1,26 @@
+package payload
+
+type CreateUserPayload struct {
+	Email    string
+	Password string
+}
+
+type LoginPayload struct {
+	Email    string
+	Password string
+}
+
+type UpdateUserPayload struct {
+	Email    string
+	NewEmail string
+}
+
+type SetPasswordPayload struct {
+	Email    string
+	Password string
+}
+
+type RestoreAccountPayload struct {
+	Email    string
+	Password string
+}
@@ -0,0 +1,14 @@
+package payload
+
+import "github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+
+type RegisterWebhookPayload struct {
+	URL        string
+	Secret     string
+	EventTypes []domain.EventType
+}
+
+type ReplayWebhookDeliveryPayload struct {
+	WebhookID  string
+	DeliveryID string
+}
@@ -0,0 +1,73 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/phantranhieunhan/s3-assignment/pkg/util"
+)
+
+type PostUpdateHandler struct {
+	updateRepo domain.UpdateRepo
+	userRepo   domain.UserRepo
+	eventRepo  domain.EventRepo
+	auditRepo  domain.AuditRepo
+	transactor Transactor
+}
+
+func NewPostUpdateHandler(updateRepo domain.UpdateRepo, userRepo domain.UserRepo, eventRepo domain.EventRepo, auditRepo domain.AuditRepo, transactor Transactor) PostUpdateHandler {
+	return PostUpdateHandler{
+		updateRepo: updateRepo,
+		userRepo:   userRepo,
+		eventRepo:  eventRepo,
+		auditRepo:  auditRepo,
+		transactor: transactor,
+	}
+}
+
+// Handle stores the text posted by the sender with the emails it mentions, the recipients are found when the feed is read
+func (h PostUpdateHandler) Handle(ctx context.Context, payload payload.PostUpdatePayload) (domain.Update, error) {
+	now := time.Now().UTC()
+	update := domain.Update{
+		Base: domain.Base{
+			CreatedAt: now,
+			UpdatedAt: now,
+		},
+		Text:     payload.Text,
+		Mentions: util.RemoveDuplicates(util.GetEmailsFromString(payload.Text)),
+	}
+
+	err := h.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		sender, err := getUserByEmail(ctx, h.userRepo, payload.Sender)
+		if err != nil {
+			return err
+		}
+
+		if err = authorizeRequestor(ctx, sender.Base.Id); err != nil {
+			return err
+		}
+		if err = ensureUsersAreActive(ctx, h.userRepo, "email", sender.Base.Id); err != nil {
+			return err
+		}
+
+		update.UserID = sender.Base.Id
+		update.Base.Id, err = h.updateRepo.Create(ctx, update)
+		if err != nil {
+			logger.Errorf("updateRepo.Create %w", err)
+			return common.ErrCannotCreateEntity(update.DomainName(), err)
+		}
+		if err = recordEvent(ctx, h.eventRepo, domain.EventUpdatePosted, update.Base.Id, update); err != nil {
+			return err
+		}
+		return recordAudit(ctx, h.auditRepo, domain.AuditPostUpdate, domain.AuditStatusNone, domain.AuditStatusActive, update.Base.Id)
+	})
+	if err != nil {
+		return domain.Update{}, err
+	}
+
+	return update, nil
+}
@@ -0,0 +1,103 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/auth"
+	mockRepo "github.com/phantranhieunhan/s3-assignment/mock/friendship/repository"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestCase_Update_PostUpdate struct {
+	name       string
+	authUserID string
+	err        error
+
+	withinTransactionError error
+
+	getUserByEmailError error
+	createError         error
+}
+
+func TestUpdate_PostUpdate(t *testing.T) {
+	t.Parallel()
+
+	sender := "john@example.com"
+	text := "hello lisa@example.com and kate@example.com, lisa@example.com"
+	userID := "user-1"
+	updateID := "update-1"
+	errDB := errors.New("some error from db")
+
+	tcs := []TestCase_Update_PostUpdate{
+		{
+			name: "post update successfully",
+		},
+		{
+			name:       "post update successfully by the authenticated sender",
+			authUserID: userID,
+		},
+		{
+			name:                   "post update fail because sender is not the authenticated user",
+			authUserID:             "user-2",
+			withinTransactionError: common.NewForbidden(domain.ErrRequestorIsNotAuthenticatedUser, domain.ErrRequestorIsNotAuthenticatedUser.Error(), "ErrForbidden"),
+			err:                    common.NewForbidden(domain.ErrRequestorIsNotAuthenticatedUser, domain.ErrRequestorIsNotAuthenticatedUser.Error(), "ErrForbidden"),
+		},
+		{
+			name:                   "post update fail because sender not found",
+			getUserByEmailError:    domain.ErrRecordNotFound,
+			withinTransactionError: common.ErrInvalidRequest(domain.ErrNotFoundUserByEmail, "email"),
+			err:                    common.ErrInvalidRequest(domain.ErrNotFoundUserByEmail, "email"),
+		},
+		{
+			name:                   "post update fail because create fail",
+			createError:            errDB,
+			withinTransactionError: common.ErrCannotCreateEntity(domain.Update{}.DomainName(), errDB),
+			err:                    common.ErrCannotCreateEntity(domain.Update{}.DomainName(), errDB),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			// the requestor is the authenticated user unless the case says otherwise
+			authUserID := tc.authUserID
+			if authUserID == "" {
+				authUserID = userID
+			}
+			ctx = auth.WithUserID(ctx, authUserID)
+
+			mockUpdateRepo := new(mockRepo.MockUpdateRepository)
+			mockUserRepo := newActiveUserRepo()
+			mockTransaction := new(mockRepo.MockTransaction)
+			h := NewPostUpdateHandler(mockUpdateRepo, mockUserRepo, allowRecordEvent(), allowRecordAudit(), mockTransaction)
+
+			prepareWithinTransaction(t, ctx, mockTransaction, tc.withinTransactionError)
+			mockUserRepo.On("GetUserByEmail", ctx, sender).Return(domain.User{Base: domain.Base{Id: userID}, Email: sender}, tc.getUserByEmailError).Once()
+			if tc.getUserByEmailError == nil && (tc.authUserID == "" || tc.authUserID == userID) {
+				mockUpdateRepo.On("Create", ctx, mock.MatchedBy(func(d domain.Update) bool {
+					return d.UserID == userID && d.Text == text && !d.Base.CreatedAt.IsZero() &&
+						assert.ObjectsAreEqual([]string{"lisa@example.com", "kate@example.com"}, d.Mentions)
+				})).Return(updateID, tc.createError).Once()
+			}
+
+			update, err := h.Handle(ctx, payload.PostUpdatePayload{Sender: sender, Text: text})
+			assert.Equal(t, tc.err, err)
+			if tc.err == nil {
+				assert.Equal(t, updateID, update.Base.Id)
+				assert.Equal(t, userID, update.UserID)
+			} else {
+				assert.Equal(t, domain.Update{}, update)
+			}
+			mock.AssertExpectationsForObjects(t, mockUpdateRepo, mockUserRepo, mockTransaction)
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+type PurgeAccountHandler struct {
+	purger     accountPurger
+	userRepo   domain.UserRepo
+	transactor Transactor
+}
+
+func NewPurgeAccountHandler(userRepo domain.UserRepo, friendshipRepo domain.FriendshipRepo, subscriptionRepo domain.SubscriptionRepo, blockRepo domain.BlockRepo,
+	updateRepo domain.UpdateRepo, eventRepo domain.EventRepo, auditRepo domain.AuditRepo, transactor Transactor) PurgeAccountHandler {
+	return PurgeAccountHandler{
+		purger:     newAccountPurger(userRepo, friendshipRepo, subscriptionRepo, blockRepo, updateRepo, eventRepo, auditRepo),
+		userRepo:   userRepo,
+		transactor: transactor,
+	}
+}
+
+// Handle purges the account whose grace period is over. The deletion is read again within the transaction,
+// a deletion cancelled or not due anymore is left alone
+func (h PurgeAccountHandler) Handle(ctx context.Context, userID string) error {
+	return h.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		deletion, err := h.userRepo.GetDeletion(ctx, userID)
+		if err == domain.ErrRecordNotFound {
+			return nil
+		}
+		if err != nil {
+			logger.Errorf("userRepo.GetDeletion %w", err)
+			return common.ErrCannotGetEntity(deletion.DomainName(), err)
+		}
+		if deletion.PurgeAt.After(time.Now()) {
+			return nil
+		}
+
+		emails, err := h.userRepo.GetEmailsByUserIDs(ctx, []string{userID})
+		if err != nil {
+			logger.Errorf("userRepo.GetEmailsByUserIDs %w", err)
+			return common.ErrCannotGetEntity(domain.User{}.DomainName(), err)
+		}
+		return h.purger.purge(ctx, domain.User{Base: domain.Base{Id: userID}, Email: emails[userID]}, domain.AuditPurgeAccount, domain.AuditStatusDeletionScheduled)
+	})
+}
@@ -0,0 +1,133 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	mockRepo "github.com/phantranhieunhan/s3-assignment/mock/friendship/repository"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestCase_User_PurgeAccount struct {
+	name string
+	err  error
+
+	withinTransactionError error
+
+	getDeletionData  domain.UserDeletion
+	getDeletionError error
+
+	purged               bool
+	deleteBlocksError    error
+	recordEventError     error
+	recordAuditError     error
+	getEmailsByUserError error
+}
+
+func TestUser_PurgeAccount(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().UTC()
+	due := domain.UserDeletion{UserID: "user-1", RequestedAt: now.Add(-time.Hour), PurgeAt: now.Add(-time.Minute)}
+	errDB := errors.New("some error from db")
+
+	tcs := []TestCase_User_PurgeAccount{
+		{
+			name:            "purge account successfully",
+			getDeletionData: due,
+			purged:          true,
+		},
+		{
+			name:             "purge nothing because the deletion was cancelled",
+			getDeletionError: domain.ErrRecordNotFound,
+		},
+		{
+			name:            "purge nothing because the deletion is not due",
+			getDeletionData: domain.UserDeletion{UserID: "user-1", RequestedAt: now, PurgeAt: now.Add(time.Hour)},
+		},
+		{
+			name:                   "purge account fail because get deletion fail",
+			getDeletionError:       errDB,
+			withinTransactionError: common.ErrCannotGetEntity(domain.UserDeletion{}.DomainName(), errDB),
+			err:                    common.ErrCannotGetEntity(domain.UserDeletion{}.DomainName(), errDB),
+		},
+		{
+			name:                   "purge account fail because get email fail",
+			getDeletionData:        due,
+			getEmailsByUserError:   errDB,
+			withinTransactionError: common.ErrCannotGetEntity(domain.User{}.DomainName(), errDB),
+			err:                    common.ErrCannotGetEntity(domain.User{}.DomainName(), errDB),
+		},
+		{
+			name:                   "purge account fail because delete blocks fail",
+			getDeletionData:        due,
+			purged:                 true,
+			deleteBlocksError:      errDB,
+			withinTransactionError: common.ErrCannotDeleteEntity(domain.Block{}.DomainName(), errDB),
+			err:                    common.ErrCannotDeleteEntity(domain.Block{}.DomainName(), errDB),
+		},
+		{
+			name:                   "purge account fail because record event fail",
+			getDeletionData:        due,
+			purged:                 true,
+			recordEventError:       errDB,
+			withinTransactionError: common.ErrCannotCreateEntity(domain.Event{}.DomainName(), errDB),
+			err:                    common.ErrCannotCreateEntity(domain.Event{}.DomainName(), errDB),
+		},
+		{
+			name:                   "purge account fail because record audit fail",
+			getDeletionData:        due,
+			purged:                 true,
+			recordAuditError:       errDB,
+			withinTransactionError: common.ErrCannotCreateEntity(domain.AuditEntry{}.DomainName(), errDB),
+			err:                    common.ErrCannotCreateEntity(domain.AuditEntry{}.DomainName(), errDB),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			mockUserRepo := new(mockRepo.MockUserRepository)
+			mockFriendshipRepo := new(mockRepo.MockFriendshipRepository)
+			mockSubscriptionRepo := new(mockRepo.MockSubscriptionRepository)
+			mockBlockRepo := new(mockRepo.MockBlockRepository)
+			mockUpdateRepo := new(mockRepo.MockUpdateRepository)
+			mockEventRepo := new(mockRepo.MockEventRepository)
+			mockTransaction := new(mockRepo.MockTransaction)
+			mockAuditRepo := new(mockRepo.MockAuditRepository)
+			h := NewPurgeAccountHandler(mockUserRepo, mockFriendshipRepo, mockSubscriptionRepo, mockBlockRepo, mockUpdateRepo, mockEventRepo, mockAuditRepo, mockTransaction)
+
+			prepareWithinTransaction(t, ctx, mockTransaction, tc.withinTransactionError)
+			mockUserRepo.On("GetDeletion", ctx, "user-1").Return(tc.getDeletionData, tc.getDeletionError).Once()
+			mockUserRepo.On("GetEmailsByUserIDs", ctx, []string{"user-1"}).Return(map[string]string{"user-1": "email-1"}, tc.getEmailsByUserError).Maybe()
+			if tc.purged {
+				mockFriendshipRepo.On("DeleteByUserID", ctx, "user-1").Return(nil).Once()
+				mockSubscriptionRepo.On("DeleteByUserID", ctx, "user-1").Return(nil).Once()
+				mockBlockRepo.On("DeleteByUserID", ctx, "user-1").Return(tc.deleteBlocksError).Once()
+				if tc.deleteBlocksError == nil {
+					mockUpdateRepo.On("RemoveMentions", ctx, "email-1").Return(nil).Once()
+					mockUserRepo.On("Anonymize", ctx, "user-1", domain.UserTombstoneEmail("user-1")).Return(nil).Once()
+					// the email is gone with the account, the event only carries the id
+					mockEventRepo.On("Create", ctx, mock.MatchedBy(func(e domain.Event) bool {
+						return e.Type == domain.EventUserDeleted && e.AggregateID == "user-1" && string(e.Payload) == `{"user_id":"user-1"}`
+					})).Return("event-id", tc.recordEventError).Once()
+					if tc.recordEventError == nil {
+						prepareRecordAudit(ctx, mockAuditRepo, domain.AuditPurgeAccount, domain.AuditStatusDeletionScheduled, domain.AuditStatusDeleted, tc.recordAuditError)
+					}
+				}
+			}
+
+			err := h.Handle(ctx, "user-1")
+			assert.Equal(t, tc.err, err)
+			mock.AssertExpectationsForObjects(t, mockUserRepo, mockFriendshipRepo, mockSubscriptionRepo, mockBlockRepo, mockUpdateRepo, mockEventRepo, mockAuditRepo, mockTransaction)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+type RegisterWebhookHandler struct {
+	webhookRepo domain.WebhookRepo
+	auditRepo   domain.AuditRepo
+	transactor  Transactor
+}
+
+func NewRegisterWebhookHandler(webhookRepo domain.WebhookRepo, auditRepo domain.AuditRepo, transactor Transactor) RegisterWebhookHandler {
+	return RegisterWebhookHandler{
+		webhookRepo: webhookRepo,
+		auditRepo:   auditRepo,
+		transactor:  transactor,
+	}
+}
+
+// Handle registers the webhook, it receives the events of the given types written from now on
+func (h RegisterWebhookHandler) Handle(ctx context.Context, payload payload.RegisterWebhookPayload) (domain.Webhook, error) {
+	now := time.Now().UTC()
+	webhook := domain.Webhook{
+		Base: domain.Base{
+			CreatedAt: now,
+			UpdatedAt: now,
+		},
+		URL:        payload.URL,
+		Secret:     payload.Secret,
+		EventTypes: make([]domain.EventType, 0, len(payload.EventTypes)),
+	}
+	for _, t := range payload.EventTypes {
+		if !t.IsValid() {
+			return domain.Webhook{}, common.ErrInvalidRequest(domain.ErrWebhookEventTypeIsNotValid, "event_types")
+		}
+		if !webhook.Accepts(t) {
+			webhook.EventTypes = append(webhook.EventTypes, t)
+		}
+	}
+
+	err := h.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		webhook.Base.Id, err = h.webhookRepo.Create(ctx, webhook)
+		if err != nil {
+			logger.Errorf("webhookRepo.Create %w", err)
+			return common.ErrCannotCreateEntity(webhook.DomainName(), err)
+		}
+		return recordAudit(ctx, h.auditRepo, domain.AuditRegisterWebhook, domain.AuditStatusNone, domain.AuditStatusActive, webhook.Base.Id)
+	})
+	if err != nil {
+		return domain.Webhook{}, err
+	}
+	return webhook, nil
+}
@@ -0,0 +1,96 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	mockRepo "github.com/phantranhieunhan/s3-assignment/mock/friendship/repository"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestCase_Webhook_RegisterWebhook struct {
+	name       string
+	eventTypes []domain.EventType
+
+	createError      error
+	recordAuditError error
+
+	expectedEventTypes []domain.EventType
+	err                error
+}
+
+func TestWebhook_RegisterWebhook(t *testing.T) {
+	t.Parallel()
+
+	errDB := errors.New("some error from db")
+
+	tcs := []TestCase_Webhook_RegisterWebhook{
+		{
+			name:               "register webhook successfully",
+			eventTypes:         []domain.EventType{domain.EventFriendshipConnected, domain.EventUserSubscribed, domain.EventUserBlocked},
+			expectedEventTypes: []domain.EventType{domain.EventFriendshipConnected, domain.EventUserSubscribed, domain.EventUserBlocked},
+		},
+		{
+			name:               "register webhook successfully without duplicated event types",
+			eventTypes:         []domain.EventType{domain.EventUserBlocked, domain.EventUserBlocked},
+			expectedEventTypes: []domain.EventType{domain.EventUserBlocked},
+		},
+		{
+			name:       "register webhook fail because event type is not valid",
+			eventTypes: []domain.EventType{domain.EventUserBlocked, "UserTeleported"},
+			err:        common.ErrInvalidRequest(domain.ErrWebhookEventTypeIsNotValid, "event_types"),
+		},
+		{
+			name:               "register webhook fail because create fail",
+			eventTypes:         []domain.EventType{domain.EventUserBlocked},
+			expectedEventTypes: []domain.EventType{domain.EventUserBlocked},
+			createError:        errDB,
+			err:                common.ErrCannotCreateEntity(domain.Webhook{}.DomainName(), errDB),
+		},
+		{
+			name:               "register webhook fail because record audit fail",
+			eventTypes:         []domain.EventType{domain.EventUserBlocked},
+			expectedEventTypes: []domain.EventType{domain.EventUserBlocked},
+			recordAuditError:   errDB,
+			err:                common.ErrCannotCreateEntity(domain.AuditEntry{}.DomainName(), errDB),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			mockWebhookRepo := new(mockRepo.MockWebhookRepository)
+			mockAuditRepo := new(mockRepo.MockAuditRepository)
+			mockTransaction := new(mockRepo.MockTransaction)
+			h := NewRegisterWebhookHandler(mockWebhookRepo, mockAuditRepo, mockTransaction)
+
+			p := payload.RegisterWebhookPayload{URL: "https://partner.example.com/hook", Secret: "a-secret-of-16-chars", EventTypes: tc.eventTypes}
+			if tc.expectedEventTypes != nil {
+				mockWebhookRepo.On("Create", ctx, mock.MatchedBy(func(w domain.Webhook) bool {
+					return w.URL == p.URL && w.Secret == p.Secret && assert.ObjectsAreEqual(tc.expectedEventTypes, w.EventTypes)
+				})).Return("webhook-id", tc.createError).Once()
+				prepareWithinTransaction(t, ctx, mockTransaction, tc.err)
+				if tc.createError == nil {
+					prepareRecordAudit(ctx, mockAuditRepo, domain.AuditRegisterWebhook, domain.AuditStatusNone, domain.AuditStatusActive, tc.recordAuditError)
+				}
+			}
+
+			webhook, err := h.Handle(ctx, p)
+			assert.Equal(t, tc.err, err)
+			if tc.err == nil {
+				assert.Equal(t, "webhook-id", webhook.Base.Id)
+				assert.Equal(t, tc.expectedEventTypes, webhook.EventTypes)
+			}
+			mock.AssertExpectationsForObjects(t, mockWebhookRepo, mockAuditRepo, mockTransaction)
+		})
+	}
+}
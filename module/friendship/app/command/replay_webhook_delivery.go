@@ -0,0 +1,62 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+type ReplayWebhookDeliveryHandler struct {
+	webhookRepo domain.WebhookRepo
+	auditRepo   domain.AuditRepo
+	transactor  Transactor
+}
+
+func NewReplayWebhookDeliveryHandler(webhookRepo domain.WebhookRepo, auditRepo domain.AuditRepo, transactor Transactor) ReplayWebhookDeliveryHandler {
+	return ReplayWebhookDeliveryHandler{
+		webhookRepo: webhookRepo,
+		auditRepo:   auditRepo,
+		transactor:  transactor,
+	}
+}
+
+// Handle moves a dead delivery back to pending with a fresh set of attempts, the worker sends it on its next poll
+func (h ReplayWebhookDeliveryHandler) Handle(ctx context.Context, payload payload.ReplayWebhookDeliveryPayload) (domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+	err := h.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		delivery, err = h.webhookRepo.GetDelivery(ctx, payload.DeliveryID)
+		if err != nil {
+			if err == domain.ErrRecordNotFound {
+				return common.ErrInvalidRequest(err, "delivery_id")
+			}
+			logger.Errorf("webhookRepo.GetDelivery %w", err)
+			return common.ErrCannotGetEntity(delivery.DomainName(), err)
+		}
+		if delivery.WebhookID != payload.WebhookID {
+			return common.ErrInvalidRequest(domain.ErrRecordNotFound, "delivery_id")
+		}
+		if delivery.Status != domain.WebhookDeliveryStatusDead {
+			return common.ErrInvalidRequest(domain.ErrWebhookDeliveryIsNotDead, "delivery_id")
+		}
+
+		now := time.Now().UTC()
+		delivery.Status = domain.WebhookDeliveryStatusPending
+		delivery.Attempts = 0
+		delivery.NextAttemptAt = now
+		delivery.Base.UpdatedAt = now
+		if err = h.webhookRepo.UpdateDelivery(ctx, delivery); err != nil {
+			logger.Errorf("webhookRepo.UpdateDelivery %w", err)
+			return common.ErrCannotUpdateEntity(delivery.DomainName(), err)
+		}
+		return recordAudit(ctx, h.auditRepo, domain.AuditReplayWebhookDelivery, domain.AuditStatusDead, domain.AuditStatusPending, delivery.WebhookID, delivery.Base.Id)
+	})
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	return delivery, nil
+}
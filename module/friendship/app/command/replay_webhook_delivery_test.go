@@ -0,0 +1,130 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	mockRepo "github.com/phantranhieunhan/s3-assignment/mock/friendship/repository"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestCase_Webhook_ReplayWebhookDelivery struct {
+	name      string
+	webhookID string
+
+	withinTransactionError error
+
+	getDeliveryData  domain.WebhookDeliveryStatus
+	getDeliveryError error
+
+	updateDeliveryError error
+	recordAuditError    error
+
+	err error
+}
+
+func TestWebhook_ReplayWebhookDelivery(t *testing.T) {
+	t.Parallel()
+
+	errDB := errors.New("some error from db")
+
+	tcs := []TestCase_Webhook_ReplayWebhookDelivery{
+		{
+			name:            "replay delivery successfully",
+			webhookID:       "webhook-id",
+			getDeliveryData: domain.WebhookDeliveryStatusDead,
+		},
+		{
+			name:                   "replay delivery fail because delivery not found",
+			webhookID:              "webhook-id",
+			getDeliveryError:       domain.ErrRecordNotFound,
+			withinTransactionError: common.ErrInvalidRequest(domain.ErrRecordNotFound, "delivery_id"),
+			err:                    common.ErrInvalidRequest(domain.ErrRecordNotFound, "delivery_id"),
+		},
+		{
+			name:                   "replay delivery fail because delivery belongs to another webhook",
+			webhookID:              "another-webhook-id",
+			getDeliveryData:        domain.WebhookDeliveryStatusDead,
+			withinTransactionError: common.ErrInvalidRequest(domain.ErrRecordNotFound, "delivery_id"),
+			err:                    common.ErrInvalidRequest(domain.ErrRecordNotFound, "delivery_id"),
+		},
+		{
+			name:                   "replay delivery fail because delivery is not dead",
+			webhookID:              "webhook-id",
+			getDeliveryData:        domain.WebhookDeliveryStatusPending,
+			withinTransactionError: common.ErrInvalidRequest(domain.ErrWebhookDeliveryIsNotDead, "delivery_id"),
+			err:                    common.ErrInvalidRequest(domain.ErrWebhookDeliveryIsNotDead, "delivery_id"),
+		},
+		{
+			name:                   "replay delivery fail because get delivery fail",
+			webhookID:              "webhook-id",
+			getDeliveryError:       errDB,
+			withinTransactionError: common.ErrCannotGetEntity(domain.WebhookDelivery{}.DomainName(), errDB),
+			err:                    common.ErrCannotGetEntity(domain.WebhookDelivery{}.DomainName(), errDB),
+		},
+		{
+			name:                   "replay delivery fail because update delivery fail",
+			webhookID:              "webhook-id",
+			getDeliveryData:        domain.WebhookDeliveryStatusDead,
+			updateDeliveryError:    errDB,
+			withinTransactionError: common.ErrCannotUpdateEntity(domain.WebhookDelivery{}.DomainName(), errDB),
+			err:                    common.ErrCannotUpdateEntity(domain.WebhookDelivery{}.DomainName(), errDB),
+		},
+		{
+			name:                   "replay delivery fail because record audit fail",
+			webhookID:              "webhook-id",
+			getDeliveryData:        domain.WebhookDeliveryStatusDead,
+			recordAuditError:       errDB,
+			withinTransactionError: common.ErrCannotCreateEntity(domain.AuditEntry{}.DomainName(), errDB),
+			err:                    common.ErrCannotCreateEntity(domain.AuditEntry{}.DomainName(), errDB),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			mockWebhookRepo := new(mockRepo.MockWebhookRepository)
+			mockTransaction := new(mockRepo.MockTransaction)
+			mockAuditRepo := new(mockRepo.MockAuditRepository)
+			h := NewReplayWebhookDeliveryHandler(mockWebhookRepo, mockAuditRepo, mockTransaction)
+
+			delivery := domain.WebhookDelivery{
+				Base:      domain.Base{Id: "delivery-id"},
+				WebhookID: "webhook-id",
+				EventID:   "event-id",
+				EventType: domain.EventUserBlocked,
+				Status:    tc.getDeliveryData,
+				Attempts:  8,
+				LastError: "webhook responded with status 500",
+			}
+
+			prepareWithinTransaction(t, ctx, mockTransaction, tc.withinTransactionError)
+			mockWebhookRepo.On("GetDelivery", ctx, delivery.Base.Id).Return(delivery, tc.getDeliveryError).Once()
+			if tc.getDeliveryError == nil && tc.webhookID == delivery.WebhookID && tc.getDeliveryData == domain.WebhookDeliveryStatusDead {
+				mockWebhookRepo.On("UpdateDelivery", ctx, mock.MatchedBy(func(d domain.WebhookDelivery) bool {
+					return d.Base.Id == delivery.Base.Id && d.Status == domain.WebhookDeliveryStatusPending && d.Attempts == 0 && !d.NextAttemptAt.IsZero()
+				})).Return(tc.updateDeliveryError).Once()
+				if tc.updateDeliveryError == nil {
+					prepareRecordAudit(ctx, mockAuditRepo, domain.AuditReplayWebhookDelivery, domain.AuditStatusDead, domain.AuditStatusPending, tc.recordAuditError)
+				}
+			}
+
+			result, err := h.Handle(ctx, payload.ReplayWebhookDeliveryPayload{WebhookID: tc.webhookID, DeliveryID: delivery.Base.Id})
+			assert.Equal(t, tc.err, err)
+			if tc.err == nil {
+				assert.Equal(t, domain.WebhookDeliveryStatusPending, result.Status)
+				assert.Equal(t, 0, result.Attempts)
+			}
+			mock.AssertExpectationsForObjects(t, mockWebhookRepo, mockAuditRepo, mockTransaction)
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package command
+
+import (
+	"context"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+type RequestFriendshipHandler struct {
+	friendshipRepo domain.FriendshipRepo
+	userRepo       domain.UserRepo
+	eventRepo      domain.EventRepo
+	auditRepo      domain.AuditRepo
+	transactor     Transactor
+}
+
+func NewRequestFriendshipHandler(repo domain.FriendshipRepo, userRepo domain.UserRepo, eventRepo domain.EventRepo, auditRepo domain.AuditRepo, transactor Transactor) RequestFriendshipHandler {
+	return RequestFriendshipHandler{
+		friendshipRepo: repo,
+		userRepo:       userRepo,
+		eventRepo:      eventRepo,
+		auditRepo:      auditRepo,
+		transactor:     transactor,
+	}
+}
+
+func (h RequestFriendshipHandler) Handle(ctx context.Context, payload payload.FriendRequestPayload) (domain.Friendship, error) {
+	if payload.Requestor == payload.Target {
+		return domain.Friendship{}, common.ErrInvalidRequest(domain.ErrEmailIsNotValid, "payload")
+	}
+
+	userIDs, err := getUserIDsByEmails(ctx, h.userRepo, payload.Requestor, payload.Target)
+	if err != nil {
+		return domain.Friendship{}, err
+	}
+
+	if err = authorizeRequestor(ctx, userIDs[payload.Requestor]); err != nil {
+		return domain.Friendship{}, err
+	}
+
+	d := domain.Friendship{
+		Status:   domain.FriendshipStatusPending,
+		UserID:   userIDs[payload.Requestor],
+		FriendID: userIDs[payload.Target],
+	}
+
+	err = h.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		f, err := h.friendshipRepo.GetFriendshipByUserIDs(ctx, d.UserID, d.FriendID)
+		if err != nil && err != domain.ErrRecordNotFound {
+			logger.Errorf("friendshipRepo.GetFriendshipByUserIDs %w", err)
+			return common.ErrCannotGetEntity(d.DomainName(), err)
+		}
+
+		if err == domain.ErrRecordNotFound {
+			d.Id, err = h.friendshipRepo.Create(ctx, d)
+			if err != nil {
+				logger.Errorf("friendshipRepo.Create %w", err)
+				return common.ErrCannotCreateEntity(d.DomainName(), err)
+			}
+			return h.record(ctx, d, domain.AuditStatusNone)
+		}
+
+		if !f.Status.CanRequest() {
+			logger.Errorf("Status.CanRequest")
+			return common.ErrInvalidRequest(domain.ErrFriendshipIsUnavailable, "")
+		}
+		// the previous row may have been created by the other side, so the direction is rewritten too
+		d.Id = f.Id
+		if err = h.friendshipRepo.Update(ctx, d); err != nil {
+			logger.Errorf("friendshipRepo.Update %w", err)
+			return common.ErrCannotUpdateEntity(d.DomainName(), err)
+		}
+		return h.record(ctx, d, domain.FriendshipAuditStatus(f.Status))
+	})
+	if err != nil {
+		return domain.Friendship{}, err
+	}
+
+	return d, nil
+}
+
+// record writes the event and the audit entry of the request, before is the status of the friendship before it
+func (h RequestFriendshipHandler) record(ctx context.Context, d domain.Friendship, before domain.AuditStatus) error {
+	if err := recordEvent(ctx, h.eventRepo, domain.EventFriendshipRequested, d.Id, domain.RelationEvent{UserID: d.UserID, TargetID: d.FriendID}); err != nil {
+		return err
+	}
+	return recordAudit(ctx, h.auditRepo, domain.AuditRequestFriendship, before, domain.AuditStatusPending, d.UserID, d.FriendID)
+}
+
+// getUserIDsByEmails maps the emails of a command payload to the user ids of the active users,
+// the tombstone of a purged user and a user scheduled for deletion are rejected
+func getUserIDsByEmails(ctx context.Context, userRepo domain.UserRepo, emails ...string) (map[string]string, error) {
+	for _, email := range emails {
+		if domain.IsUserTombstoneEmail(email) {
+			return nil, common.ErrInvalidRequest(domain.ErrNotFoundUserByEmail, "emails")
+		}
+	}
+	userIDs, err := userRepo.GetUserIDsByEmails(ctx, emails)
+	if err != nil {
+		logger.Errorf("userRepo.GetUserIDsByEmails %w", err)
+		if err == domain.ErrNotFoundUserByEmail {
+			return nil, common.ErrInvalidRequest(err, "emails")
+		}
+		return nil, common.ErrCannotGetEntity(domain.User{}.DomainName(), err)
+	}
+	for _, email := range emails {
+		if err = ensureUsersAreActive(ctx, userRepo, "emails", userIDs[email]); err != nil {
+			return nil, err
+		}
+	}
+	return userIDs, nil
+}
+
+// getFriendRequest returns the pending friendship sent from requesterID to receiverID
+func getFriendRequest(ctx context.Context, friendshipRepo domain.FriendshipRepo, requesterID, receiverID string) (domain.Friendship, error) {
+	f, err := friendshipRepo.GetFriendshipByUserIDs(ctx, requesterID, receiverID)
+	if err != nil && err != domain.ErrRecordNotFound {
+		logger.Errorf("friendshipRepo.GetFriendshipByUserIDs %w", err)
+		return domain.Friendship{}, common.ErrCannotGetEntity(domain.Friendship{}.DomainName(), err)
+	}
+	if err == domain.ErrRecordNotFound || !f.IsRequestedBy(requesterID, receiverID) {
+		return domain.Friendship{}, common.ErrInvalidRequest(domain.ErrFriendRequestNotFound, "")
+	}
+	return f, nil
+}
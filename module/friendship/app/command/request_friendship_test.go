@@ -0,0 +1,168 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/auth"
+	mockRepo "github.com/phantranhieunhan/s3-assignment/mock/friendship/repository"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestCase_Friendship_RequestFriendship struct {
+	name string
+	err  error
+
+	getUserIDsByEmailsError error
+	getUserIDsByEmailsData  map[string]string
+
+	withinTransactionError error
+
+	getFriendshipByUserIDsError error
+	getFriendshipByUserIDsData  domain.Friendship
+
+	createError error
+	updateError error
+}
+
+func TestFriendship_RequestFriendship(t *testing.T) {
+	t.Parallel()
+
+	emails := []string{"email-1", "email-2"}
+	friends := []string{"friend-1", "friend-2"}
+	mapEmails := map[string]string{
+		emails[0]: friends[0],
+		emails[1]: friends[1],
+	}
+	friendshipId := "friendship-id"
+
+	errDB := errors.New("some error from db")
+
+	tcs := []TestCase_Friendship_RequestFriendship{
+		{
+			name:                        "request friendship successfully because have never connected in the past",
+			getUserIDsByEmailsData:      mapEmails,
+			getFriendshipByUserIDsError: domain.ErrRecordNotFound,
+		},
+		{
+			name:                   "request friendship successfully because the target unfriended the requestor in the past",
+			getUserIDsByEmailsData: mapEmails,
+			getFriendshipByUserIDsData: domain.Friendship{
+				Base:     domain.Base{Id: friendshipId},
+				UserID:   friends[1],
+				FriendID: friends[0],
+				Status:   domain.FriendshipStatusUnfriended,
+			},
+		},
+		{
+			name:                   "request friendship fail because the request is pending",
+			getUserIDsByEmailsData: mapEmails,
+			getFriendshipByUserIDsData: domain.Friendship{
+				Base:     domain.Base{Id: friendshipId},
+				UserID:   friends[1],
+				FriendID: friends[0],
+				Status:   domain.FriendshipStatusPending,
+			},
+			withinTransactionError: common.ErrInvalidRequest(domain.ErrFriendshipIsUnavailable, ""),
+			err:                    common.ErrInvalidRequest(domain.ErrFriendshipIsUnavailable, ""),
+		},
+		{
+			name:                    "request friendship fail because emails invalid",
+			getUserIDsByEmailsError: domain.ErrNotFoundUserByEmail,
+			getUserIDsByEmailsData:  map[string]string{},
+			err:                     common.ErrInvalidRequest(domain.ErrNotFoundUserByEmail, "emails"),
+		},
+		{
+			name:                        "request friendship fail because get friendship fail",
+			getUserIDsByEmailsData:      mapEmails,
+			getFriendshipByUserIDsError: errDB,
+			withinTransactionError:      common.ErrCannotGetEntity(domain.Friendship{}.DomainName(), errDB),
+			err:                         common.ErrCannotGetEntity(domain.Friendship{}.DomainName(), errDB),
+		},
+		{
+			name:                        "request friendship fail because create friendship fail",
+			getUserIDsByEmailsData:      mapEmails,
+			getFriendshipByUserIDsError: domain.ErrRecordNotFound,
+			createError:                 errDB,
+			withinTransactionError:      common.ErrCannotCreateEntity(domain.Friendship{}.DomainName(), errDB),
+			err:                         common.ErrCannotCreateEntity(domain.Friendship{}.DomainName(), errDB),
+		},
+		{
+			name:                   "request friendship fail because update friendship fail",
+			getUserIDsByEmailsData: mapEmails,
+			getFriendshipByUserIDsData: domain.Friendship{
+				Base:   domain.Base{Id: friendshipId},
+				Status: domain.FriendshipStatusUnfriended,
+			},
+			updateError:            errDB,
+			withinTransactionError: common.ErrCannotUpdateEntity(domain.Friendship{}.DomainName(), errDB),
+			err:                    common.ErrCannotUpdateEntity(domain.Friendship{}.DomainName(), errDB),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			// the requestor is the authenticated user
+			ctx = auth.WithUserID(ctx, mapEmails[emails[0]])
+
+			mockFriendshipRepo := new(mockRepo.MockFriendshipRepository)
+			mockUserRepo := newActiveUserRepo()
+			mockTransaction := new(mockRepo.MockTransaction)
+			h := NewRequestFriendshipHandler(mockFriendshipRepo, mockUserRepo, allowRecordEvent(), allowRecordAudit(), mockTransaction)
+
+			expected := domain.Friendship{
+				Base:     domain.Base{Id: friendshipId},
+				UserID:   friends[0],
+				FriendID: friends[1],
+				Status:   domain.FriendshipStatusPending,
+			}
+			mockUserRepo.On("GetUserIDsByEmails", ctx, emails).Return(tc.getUserIDsByEmailsData, tc.getUserIDsByEmailsError).Once()
+			if tc.getUserIDsByEmailsError == nil {
+				prepareWithinTransaction(t, ctx, mockTransaction, tc.withinTransactionError)
+				mockFriendshipRepo.On("GetFriendshipByUserIDs", ctx, friends[0], friends[1]).Return(tc.getFriendshipByUserIDsData, tc.getFriendshipByUserIDsError).Once()
+				if tc.getFriendshipByUserIDsError == domain.ErrRecordNotFound {
+					mockFriendshipRepo.On("Create", ctx, domain.Friendship{UserID: friends[0], FriendID: friends[1], Status: domain.FriendshipStatusPending}).Return(friendshipId, tc.createError).Once()
+				} else if tc.getFriendshipByUserIDsData.Status.CanRequest() {
+					mockFriendshipRepo.On("Update", ctx, expected).Return(tc.updateError).Once()
+				}
+			}
+
+			f, err := h.Handle(ctx, payload.FriendRequestPayload{Requestor: emails[0], Target: emails[1]})
+			assert.Equal(t, tc.err, err)
+			if tc.err == nil {
+				assert.Equal(t, expected, f)
+			}
+			mock.AssertExpectationsForObjects(t, mockFriendshipRepo, mockUserRepo, mockTransaction)
+		})
+	}
+}
+
+func TestFriendship_RequestFriendship_SameEmail(t *testing.T) {
+	t.Parallel()
+
+	h := NewRequestFriendshipHandler(new(mockRepo.MockFriendshipRepository), newActiveUserRepo(), new(mockRepo.MockEventRepository), new(mockRepo.MockAuditRepository), new(mockRepo.MockTransaction))
+	_, err := h.Handle(context.Background(), payload.FriendRequestPayload{Requestor: "email-1", Target: "email-1"})
+	assert.Equal(t, common.ErrInvalidRequest(domain.ErrEmailIsNotValid, "payload"), err)
+}
+
+// prepareWithinTransaction runs the transaction callback and checks it returns the expected error
+func prepareWithinTransaction(t *testing.T, ctx context.Context, mockTransaction *mockRepo.MockTransaction, expectedErr error) {
+	mockTransaction.On("WithinTransaction", ctx, mock.Anything).Run(func(args mock.Arguments) {
+		f := args[1].(func(ctx context.Context) error)
+		err := f(ctx)
+		if expectedErr == nil {
+			assert.NoError(t, err)
+		} else {
+			assert.Equal(t, expectedErr.Error(), err.Error())
+		}
+	}).Return(expectedErr).Once()
+}
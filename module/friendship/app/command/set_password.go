@@ -0,0 +1,52 @@
+package command
+
+import (
+	"context"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/auth"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+type SetPasswordHandler struct {
+	userRepo   domain.UserRepo
+	auditRepo  domain.AuditRepo
+	transactor Transactor
+}
+
+func NewSetPasswordHandler(userRepo domain.UserRepo, auditRepo domain.AuditRepo, transactor Transactor) SetPasswordHandler {
+	return SetPasswordHandler{
+		userRepo:   userRepo,
+		auditRepo:  auditRepo,
+		transactor: transactor,
+	}
+}
+
+// Handle replaces the password of the user, it resets the accounts left without a usable password.
+// No event is written, a password change is not published outside the application
+func (h SetPasswordHandler) Handle(ctx context.Context, payload payload.SetPasswordPayload) error {
+	hashed, err := auth.HashPassword(payload.Password)
+	if err != nil {
+		logger.Errorf("auth.HashPassword %w", err)
+		return common.ErrInternal(err)
+	}
+
+	return h.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		user, err := getUserByEmail(ctx, h.userRepo, payload.Email)
+		if err != nil {
+			return err
+		}
+
+		if err = authorizeRequestor(ctx, user.Base.Id); err != nil {
+			return err
+		}
+
+		if err = h.userRepo.SetPasswordHash(ctx, user.Base.Id, hashed); err != nil {
+			logger.Errorf("userRepo.SetPasswordHash %w", err)
+			return common.ErrCannotUpdateEntity(user.DomainName(), err)
+		}
+		return recordAudit(ctx, h.auditRepo, domain.AuditSetPassword, domain.AuditStatusActive, domain.AuditStatusActive, user.Base.Id)
+	})
+}
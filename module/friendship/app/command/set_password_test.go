@@ -0,0 +1,82 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/auth"
+	mockRepo "github.com/phantranhieunhan/s3-assignment/mock/friendship/repository"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestCase_User_SetPassword struct {
+	name string
+	err  error
+
+	withinTransactionError error
+
+	getUserByEmailError  error
+	setPasswordHashError error
+}
+
+func TestUser_SetPassword(t *testing.T) {
+	t.Parallel()
+
+	p := payload.SetPasswordPayload{Email: "email-1", Password: "new password"}
+	user := domain.User{Base: domain.Base{Id: "user-1"}, Email: p.Email}
+	errDB := errors.New("some error from db")
+
+	tcs := []TestCase_User_SetPassword{
+		{
+			name: "set password successfully",
+		},
+		{
+			name:                   "set password fail because user not found",
+			getUserByEmailError:    domain.ErrRecordNotFound,
+			withinTransactionError: common.ErrInvalidRequest(domain.ErrNotFoundUserByEmail, "email"),
+			err:                    common.ErrInvalidRequest(domain.ErrNotFoundUserByEmail, "email"),
+		},
+		{
+			name:                   "set password fail because update fail",
+			setPasswordHashError:   errDB,
+			withinTransactionError: common.ErrCannotUpdateEntity(user.DomainName(), errDB),
+			err:                    common.ErrCannotUpdateEntity(user.DomainName(), errDB),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			// the password is set by the admin
+			ctx = auth.WithAdmin(ctx)
+
+			mockUserRepo := new(mockRepo.MockUserRepository)
+			mockTransaction := new(mockRepo.MockTransaction)
+			mockAuditRepo := new(mockRepo.MockAuditRepository)
+			h := NewSetPasswordHandler(mockUserRepo, mockAuditRepo, mockTransaction)
+
+			prepareWithinTransaction(t, ctx, mockTransaction, tc.withinTransactionError)
+			mockUserRepo.On("GetUserByEmail", ctx, p.Email).Return(user, tc.getUserByEmailError).Once()
+			if tc.getUserByEmailError == nil {
+				mockUserRepo.On("SetPasswordHash", ctx, user.Base.Id, mock.MatchedBy(func(hash string) bool {
+					return auth.ComparePassword(hash, p.Password) == nil
+				})).Return(tc.setPasswordHashError).Once()
+			}
+			if tc.err == nil {
+				prepareRecordAudit(ctx, mockAuditRepo, domain.AuditSetPassword, domain.AuditStatusActive, domain.AuditStatusActive, nil)
+			}
+
+			err := h.Handle(ctx, p)
+			assert.Equal(t, tc.err, err)
+			mock.AssertExpectationsForObjects(t, mockUserRepo, mockAuditRepo, mockTransaction)
+		})
+	}
+}
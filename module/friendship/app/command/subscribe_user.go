@@ -15,14 +15,18 @@ type SubscribeUserHandler struct {
 	friendshipRepo    domain.FriendshipRepo
 	userRepo          domain.UserRepo
 	subscribeUserRepo domain.SubscriptionRepo
+	eventRepo         domain.EventRepo
+	auditRepo         domain.AuditRepo
 	transactor        Transactor
 }
 
-func NewSubscribeUserHandler(repo domain.FriendshipRepo, userRepo domain.UserRepo, subscribeUserRepo domain.SubscriptionRepo, transactor Transactor) SubscribeUserHandler {
+func NewSubscribeUserHandler(repo domain.FriendshipRepo, userRepo domain.UserRepo, subscribeUserRepo domain.SubscriptionRepo, eventRepo domain.EventRepo, auditRepo domain.AuditRepo, transactor Transactor) SubscribeUserHandler {
 	return SubscribeUserHandler{
 		friendshipRepo:    repo,
 		userRepo:          userRepo,
 		subscribeUserRepo: subscribeUserRepo,
+		eventRepo:         eventRepo,
+		auditRepo:         auditRepo,
 		transactor:        transactor,
 	}
 }
@@ -32,18 +36,17 @@ func (h SubscribeUserHandler) Handle(ctx context.Context, payload payload.Subscr
 	if len(emails) < EMAIL_TOTAL {
 		return common.ErrInvalidRequest(domain.ErrEmailIsNotValid, "payload")
 	}
-	userIDs, err := h.userRepo.GetUserIDsByEmails(ctx, emails)
+	userIDs, err := getUserIDsByEmails(ctx, h.userRepo, emails...)
 	if err != nil {
-		logger.Errorf("userRepo.GetUserIDsByEmails %w", err)
-		if err == domain.ErrNotFoundUserByEmail {
-			return common.ErrInvalidRequest(err, "emails")
-		}
-		return common.ErrCannotGetEntity(domain.Subscription{}.DomainName(), err)
+		return err
 	}
 
 	ds := make(domain.Subscriptions, 0, len(payload))
 
 	for _, v := range payload {
+		if err = authorizeRequestor(ctx, userIDs[v.Requestor]); err != nil {
+			return err
+		}
 		sc := domain.Subscription{
 			UserID:       userIDs[v.Target],
 			SubscriberID: userIDs[v.Requestor],
@@ -53,50 +56,61 @@ func (h SubscribeUserHandler) Handle(ctx context.Context, payload payload.Subscr
 	return h.handle(ctx, ds)
 }
 
-func (h SubscribeUserHandler) HandleWithSubscription(ctx context.Context, ds domain.Subscriptions) error {
-	return h.handle(ctx, ds)
+func (h SubscribeUserHandler) handle(ctx context.Context, ds domain.Subscriptions) error {
+	return h.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		isAlreadySubscribed, err := subscribe(ctx, h.subscribeUserRepo, h.eventRepo, h.auditRepo, ds)
+		if err != nil {
+			return err
+		}
+		if isAlreadySubscribed {
+			return common.ErrInvalidRequest(domain.ErrAlreadyExists, "emails")
+		}
+		return nil
+	})
 }
 
-func (h SubscribeUserHandler) handle(ctx context.Context, ds domain.Subscriptions) error {
+// subscribe subscribes the subscribers of ds to their users within the transaction of ctx,
+// it reports whether one of them was already subscribed, that subscription is kept as it is
+func subscribe(ctx context.Context, subRepo domain.SubscriptionRepo, eventRepo domain.EventRepo, auditRepo domain.AuditRepo, ds domain.Subscriptions) (bool, error) {
 	mapSub := make(map[string]domain.Subscription, 0)
 	for _, v := range ds {
 		mapSub[v.GetUserSubscriberMapKey()] = v
 	}
 
-	err := h.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
-		gotSub, err := h.subscribeUserRepo.GetSubscription(ctx, ds)
-		if err != nil {
-			logger.Errorf("subscribeUserRepo.GetSubscription %w", err)
-			return common.ErrCannotGetEntity(domain.Subscription{}.DomainName(), err)
-		}
+	gotSub, err := subRepo.GetSubscription(ctx, ds)
+	if err != nil {
+		logger.Errorf("subscribeUserRepo.GetSubscription %w", err)
+		return false, common.ErrCannotGetEntity(domain.Subscription{}.DomainName(), err)
+	}
 
-		for _, v := range gotSub {
-			mapSub[v.GetUserSubscriberMapKey()] = v
+	for _, v := range gotSub {
+		mapSub[v.GetUserSubscriberMapKey()] = v
+	}
+	var isAlreadySubscribed bool
+	for _, v := range ds {
+		sub := mapSub[v.GetUserSubscriberMapKey()]
+		if !sub.Status.AllowSubscribe() {
+			isAlreadySubscribed = true
+			continue
 		}
-		var isAlreadySubscribed bool
-		for _, v := range ds {
-			sub := mapSub[v.GetUserSubscriberMapKey()]
-			if sub.Status.AllowSubscribe() {
-				if sub.Status.IsNoneExisted() {
-					sub.Status = domain.SubscriptionStatusSubscribed
-					sub.Id, err = h.subscribeUserRepo.Create(ctx, sub)
-					if err != nil {
-						return common.ErrCannotCreateEntity(sub.DomainName(), err)
-					}
-				} else {
-					if err := h.subscribeUserRepo.UpdateStatus(ctx, sub.Id, domain.SubscriptionStatusSubscribed); err != nil {
-						return common.ErrCannotUpdateEntity(sub.DomainName(), err)
-					}
-				}
-			} else {
-				isAlreadySubscribed = true
+		before := domain.SubscriptionAuditStatus(sub.Status)
+		if sub.Status.IsNoneExisted() {
+			sub.Status = domain.SubscriptionStatusSubscribed
+			sub.Id, err = subRepo.Create(ctx, sub)
+			if err != nil {
+				return false, common.ErrCannotCreateEntity(sub.DomainName(), err)
+			}
+		} else {
+			if err := subRepo.UpdateStatus(ctx, sub.Id, domain.SubscriptionStatusSubscribed); err != nil {
+				return false, common.ErrCannotUpdateEntity(sub.DomainName(), err)
 			}
 		}
-		if isAlreadySubscribed {
-			return common.ErrInvalidRequest(domain.ErrAlreadyExists, "emails")
+		if err = recordEvent(ctx, eventRepo, domain.EventUserSubscribed, sub.Id, domain.RelationEvent{UserID: sub.SubscriberID, TargetID: sub.UserID}); err != nil {
+			return false, err
 		}
-		return nil
-	})
-
-	return err
+		if err = recordAudit(ctx, auditRepo, domain.AuditSubscribeUser, before, domain.AuditStatusSubscribed, sub.SubscriberID, sub.UserID); err != nil {
+			return false, err
+		}
+	}
+	return isAlreadySubscribed, nil
 }
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/auth"
 	mockRepo "github.com/phantranhieunhan/s3-assignment/mock/friendship/repository"
 	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
 	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
@@ -36,7 +37,7 @@ type TestCase_SubscribeUser_Handle struct {
 func TestSubscribeUser_Handle(t *testing.T) {
 	t.Parallel()
 	mockFriendshipRepo := new(mockRepo.MockFriendshipRepository)
-	mockUserRepo := new(mockRepo.MockUserRepository)
+	mockUserRepo := newActiveUserRepo()
 	mockSubscriptionRepo := new(mockRepo.MockSubscriptionRepository)
 	mockTransaction := new(mockRepo.MockTransaction)
 
@@ -47,7 +48,7 @@ func TestSubscribeUser_Handle(t *testing.T) {
 		mockTransaction:      mockTransaction,
 	}
 
-	h := NewSubscribeUserHandler(mockFriendshipRepo, mockUserRepo, mockSubscriptionRepo, mockTransaction)
+	h := NewSubscribeUserHandler(mockFriendshipRepo, mockUserRepo, mockSubscriptionRepo, allowRecordEvent(), allowRecordAudit(), mockTransaction)
 
 	emails := []string{"email-1", "email-2"}
 	friends := []string{"friend-1", "friend-2"}
@@ -86,9 +87,9 @@ func TestSubscribeUser_Handle(t *testing.T) {
 		{
 			name: "subscriber a user fail because get user id by email fail",
 
-			err:                     common.ErrCannotGetEntity(domain.Subscription{}.DomainName(), errDB),
+			err:                     common.ErrCannotGetEntity(domain.User{}.DomainName(), errDB),
 			getUserIDsByEmailsError: errDB,
-			withinTransactionError:  common.ErrCannotGetEntity(domain.Subscription{}.DomainName(), errDB),
+			withinTransactionError:  common.ErrCannotGetEntity(domain.User{}.DomainName(), errDB),
 		},
 		{
 			name: "subscriber a user fail because get subscription fail",
@@ -122,6 +123,8 @@ func TestSubscribeUser_Handle(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 			defer cancel()
+			// the requestor is the authenticated user
+			ctx = auth.WithUserID(ctx, mapEmails[emails[0]])
 			repoMock.prepare(ctx, t, tc)
 
 			err := h.Handle(ctx, payload.SubscriberUserPayloads{
@@ -0,0 +1,8 @@
+package command
+
+import "time"
+
+// TokenIssuer signs the tokens returned to the users on login
+type TokenIssuer interface {
+	Issue(userID string) (string, time.Time, error)
+}
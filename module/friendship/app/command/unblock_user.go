@@ -0,0 +1,138 @@
+package command
+
+import (
+	"context"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+type UnblockUserHandler struct {
+	friendshipRepo   domain.FriendshipRepo
+	userRepo         domain.UserRepo
+	subscriptionRepo domain.SubscriptionRepo
+	blockRepo        domain.BlockRepo
+	eventRepo        domain.EventRepo
+	auditRepo        domain.AuditRepo
+	transactor       Transactor
+}
+
+func NewUnblockUserHandler(repo domain.FriendshipRepo, userRepo domain.UserRepo, subRepo domain.SubscriptionRepo, blockRepo domain.BlockRepo, eventRepo domain.EventRepo, auditRepo domain.AuditRepo, transactor Transactor) UnblockUserHandler {
+	return UnblockUserHandler{
+		friendshipRepo:   repo,
+		userRepo:         userRepo,
+		subscriptionRepo: subRepo,
+		blockRepo:        blockRepo,
+		eventRepo:        eventRepo,
+		auditRepo:        auditRepo,
+		transactor:       transactor,
+	}
+}
+
+// Handle lifts the block the requestor made on the target and restores the state saved when blocking
+func (h UnblockUserHandler) Handle(ctx context.Context, payload payload.UnblockUserPayload) error {
+	if payload.Requestor == payload.Target {
+		return common.ErrInvalidRequest(domain.ErrEmailIsNotValid, "payload")
+	}
+
+	userIDs, err := getUserIDsByEmails(ctx, h.userRepo, payload.Requestor, payload.Target)
+	if err != nil {
+		return err
+	}
+
+	if err = authorizeRequestor(ctx, userIDs[payload.Requestor]); err != nil {
+		return err
+	}
+
+	requestorID := userIDs[payload.Requestor]
+	targetID := userIDs[payload.Target]
+
+	return h.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		block, err := h.blockRepo.GetBlock(ctx, requestorID, targetID)
+		if err != nil {
+			if err == domain.ErrRecordNotFound {
+				return common.ErrInvalidRequest(domain.ErrBlockNotFound, "")
+			}
+			logger.Errorf("blockRepo.GetBlock %w", err)
+			return common.ErrCannotGetEntity(block.DomainName(), err)
+		}
+
+		if err = h.restoreFriendship(ctx, block); err != nil {
+			return err
+		}
+
+		if err = h.restoreSubscription(ctx, block); err != nil {
+			return err
+		}
+
+		if err = h.blockRepo.Delete(ctx, block.Id); err != nil {
+			logger.Errorf("blockRepo.Delete %w", err)
+			return common.ErrCannotDeleteEntity(block.DomainName(), err)
+		}
+		if err = recordEvent(ctx, h.eventRepo, domain.EventUserUnblocked, block.Id, domain.RelationEvent{UserID: requestorID, TargetID: targetID}); err != nil {
+			return err
+		}
+		return recordAudit(ctx, h.auditRepo, domain.AuditUnblockUser, domain.AuditStatusBlocked, domain.AuditStatusNone, requestorID, targetID)
+	})
+}
+
+// restoreFriendship sets the friendship back unless it is still blocked by the other side
+func (h UnblockUserHandler) restoreFriendship(ctx context.Context, block domain.Block) error {
+	f, err := h.friendshipRepo.GetFriendshipByUserIDs(ctx, block.UserID, block.TargetID)
+	if err != nil {
+		if err == domain.ErrRecordNotFound {
+			return nil
+		}
+		logger.Errorf("friendshipRepo.GetFriendshipByUserIDs %w", err)
+		return common.ErrCannotGetEntity(f.DomainName(), err)
+	}
+	if f.Status != domain.FriendshipStatusBlocked {
+		return nil
+	}
+
+	_, err = h.blockRepo.GetBlock(ctx, block.TargetID, block.UserID)
+	if err == nil {
+		return nil
+	}
+	if err != domain.ErrRecordNotFound {
+		logger.Errorf("blockRepo.GetBlock %w", err)
+		return common.ErrCannotGetEntity(block.DomainName(), err)
+	}
+
+	if err = h.friendshipRepo.UpdateStatus(ctx, f.Id, block.RestoredFriendshipStatus()); err != nil {
+		logger.Errorf("friendshipRepo.UpdateStatus %w", err)
+		return common.ErrCannotUpdateEntity(f.DomainName(), err)
+	}
+	return nil
+}
+
+// restoreSubscription sets the requestor's subscription to the target back, unless it was changed after blocking
+func (h UnblockUserHandler) restoreSubscription(ctx context.Context, block domain.Block) error {
+	subs, err := h.subscriptionRepo.GetSubscription(ctx, domain.Subscriptions{
+		{UserID: block.TargetID, SubscriberID: block.UserID},
+	})
+	if err != nil {
+		logger.Errorf("subscriptionRepo.GetSubscription %w", err)
+		return common.ErrCannotGetEntity(domain.Subscription{}.DomainName(), err)
+	}
+	if len(subs) == 0 || subs[0].Status != domain.SubscriptionStatusUnsubscribed {
+		return nil
+	}
+
+	sub := subs[0]
+	if block.SubscriptionStatus.IsNoneExisted() {
+		if err = h.subscriptionRepo.Delete(ctx, sub.Id); err != nil {
+			logger.Errorf("subscriptionRepo.Delete %w", err)
+			return common.ErrCannotDeleteEntity(sub.DomainName(), err)
+		}
+		return nil
+	}
+
+	if err = h.subscriptionRepo.UpdateStatus(ctx, sub.Id, block.SubscriptionStatus); err != nil {
+		logger.Errorf("subscriptionRepo.UpdateStatus %w", err)
+		return common.ErrCannotUpdateEntity(sub.DomainName(), err)
+	}
+	return nil
+}
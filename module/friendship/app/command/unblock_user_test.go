@@ -0,0 +1,216 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/auth"
+	mockRepo "github.com/phantranhieunhan/s3-assignment/mock/friendship/repository"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestCase_Friendship_UnblockUser struct {
+	name string
+	err  error
+
+	getUserIDsByEmailsError error
+	getUserIDsByEmailsData  map[string]string
+
+	withinTransactionError error
+
+	getBlockError error
+	getBlockData  domain.Block
+
+	getFriendshipByUserIDsError error
+	getFriendshipByUserIDsData  domain.FriendshipStatus
+
+	getReverseBlockError error
+
+	updateFriendshipError error
+
+	getSubscriptionError error
+	getSubscriptionData  domain.SubscriptionStatus
+
+	updateSubscriptionError error
+	deleteSubscriptionError error
+
+	deleteBlockError error
+}
+
+func TestFriendship_UnblockUser(t *testing.T) {
+	t.Parallel()
+
+	emails := []string{"email-1", "email-2"}
+	friends := []string{"friend-1", "friend-2"}
+	mapEmails := map[string]string{
+		emails[0]: friends[0],
+		emails[1]: friends[1],
+	}
+	friendshipId := "friendship-id"
+	subId := "sub-id"
+	blockWithoutSub := domain.Block{
+		Base:     domain.Base{Id: "block-id"},
+		UserID:   friends[0],
+		TargetID: friends[1],
+	}
+	blockWithPendingRequest := blockWithoutSub
+	blockWithPendingRequest.FriendshipStatus = domain.FriendshipStatusPending
+	blockWithSub := blockWithoutSub
+	blockWithSub.FriendshipStatus = domain.FriendshipStatusFriended
+	blockWithSub.SubscriptionStatus = domain.SubscriptionStatusSubscribed
+
+	errDB := errors.New("some error from db")
+
+	tcs := []TestCase_Friendship_UnblockUser{
+		{
+			name:                       "unblock user successfully and restore unfriended AND drop the subscription",
+			getUserIDsByEmailsData:     mapEmails,
+			getBlockData:               blockWithoutSub,
+			getFriendshipByUserIDsData: domain.FriendshipStatusBlocked,
+			getReverseBlockError:       domain.ErrRecordNotFound,
+			getSubscriptionData:        domain.SubscriptionStatusUnsubscribed,
+		},
+		{
+			name:                       "unblock user successfully and restore the pending friend request",
+			getUserIDsByEmailsData:     mapEmails,
+			getBlockData:               blockWithPendingRequest,
+			getFriendshipByUserIDsData: domain.FriendshipStatusBlocked,
+			getReverseBlockError:       domain.ErrRecordNotFound,
+			getSubscriptionData:        domain.SubscriptionStatusUnsubscribed,
+		},
+		{
+			name:                       "unblock user successfully and keep friended AND restore the subscription",
+			getUserIDsByEmailsData:     mapEmails,
+			getBlockData:               blockWithSub,
+			getFriendshipByUserIDsData: domain.FriendshipStatusFriended,
+			getSubscriptionData:        domain.SubscriptionStatusUnsubscribed,
+		},
+		{
+			name:                       "unblock user successfully but keep blocked because the target blocked the requestor too",
+			getUserIDsByEmailsData:     mapEmails,
+			getBlockData:               blockWithoutSub,
+			getFriendshipByUserIDsData: domain.FriendshipStatusBlocked,
+			getSubscriptionData:        domain.SubscriptionStatusSubscribed,
+		},
+		{
+			name:                    "unblock user fail because emails invalid",
+			getUserIDsByEmailsError: domain.ErrNotFoundUserByEmail,
+			getUserIDsByEmailsData:  map[string]string{},
+			err:                     common.ErrInvalidRequest(domain.ErrNotFoundUserByEmail, "emails"),
+		},
+		{
+			name:                   "unblock user fail because no block exists",
+			getUserIDsByEmailsData: mapEmails,
+			getBlockError:          domain.ErrRecordNotFound,
+			withinTransactionError: common.ErrInvalidRequest(domain.ErrBlockNotFound, ""),
+			err:                    common.ErrInvalidRequest(domain.ErrBlockNotFound, ""),
+		},
+		{
+			name:                   "unblock user fail because get block fail",
+			getUserIDsByEmailsData: mapEmails,
+			getBlockError:          errDB,
+			withinTransactionError: common.ErrCannotGetEntity(domain.Block{}.DomainName(), errDB),
+			err:                    common.ErrCannotGetEntity(domain.Block{}.DomainName(), errDB),
+		},
+		{
+			name:                        "unblock user fail because get friendship fail",
+			getUserIDsByEmailsData:      mapEmails,
+			getBlockData:                blockWithoutSub,
+			getFriendshipByUserIDsError: errDB,
+			withinTransactionError:      common.ErrCannotGetEntity(domain.Friendship{}.DomainName(), errDB),
+			err:                         common.ErrCannotGetEntity(domain.Friendship{}.DomainName(), errDB),
+		},
+		{
+			name:                       "unblock user fail because update friendship fail",
+			getUserIDsByEmailsData:     mapEmails,
+			getBlockData:               blockWithoutSub,
+			getFriendshipByUserIDsData: domain.FriendshipStatusBlocked,
+			getReverseBlockError:       domain.ErrRecordNotFound,
+			updateFriendshipError:      errDB,
+			withinTransactionError:     common.ErrCannotUpdateEntity(domain.Friendship{}.DomainName(), errDB),
+			err:                        common.ErrCannotUpdateEntity(domain.Friendship{}.DomainName(), errDB),
+		},
+		{
+			name:                       "unblock user fail because update subscription fail",
+			getUserIDsByEmailsData:     mapEmails,
+			getBlockData:               blockWithSub,
+			getFriendshipByUserIDsData: domain.FriendshipStatusFriended,
+			getSubscriptionData:        domain.SubscriptionStatusUnsubscribed,
+			updateSubscriptionError:    errDB,
+			withinTransactionError:     common.ErrCannotUpdateEntity(domain.Subscription{}.DomainName(), errDB),
+			err:                        common.ErrCannotUpdateEntity(domain.Subscription{}.DomainName(), errDB),
+		},
+		{
+			name:                       "unblock user fail because delete block fail",
+			getUserIDsByEmailsData:     mapEmails,
+			getBlockData:               blockWithSub,
+			getFriendshipByUserIDsData: domain.FriendshipStatusFriended,
+			getSubscriptionData:        domain.SubscriptionStatusSubscribed,
+			deleteBlockError:           errDB,
+			withinTransactionError:     common.ErrCannotDeleteEntity(domain.Block{}.DomainName(), errDB),
+			err:                        common.ErrCannotDeleteEntity(domain.Block{}.DomainName(), errDB),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			// the requestor is the authenticated user
+			ctx = auth.WithUserID(ctx, mapEmails[emails[0]])
+
+			mockFriendshipRepo := new(mockRepo.MockFriendshipRepository)
+			mockUserRepo := newActiveUserRepo()
+			mockSub := new(mockRepo.MockSubscriptionRepository)
+			mockBlock := new(mockRepo.MockBlockRepository)
+			mockTransaction := new(mockRepo.MockTransaction)
+			h := NewUnblockUserHandler(mockFriendshipRepo, mockUserRepo, mockSub, mockBlock, allowRecordEvent(), allowRecordAudit(), mockTransaction)
+
+			mockUserRepo.On("GetUserIDsByEmails", ctx, emails).Return(tc.getUserIDsByEmailsData, tc.getUserIDsByEmailsError).Once()
+			if tc.getUserIDsByEmailsError == nil {
+				prepareWithinTransaction(t, ctx, mockTransaction, tc.withinTransactionError)
+				mockBlock.On("GetBlock", ctx, friends[0], friends[1]).Return(tc.getBlockData, tc.getBlockError).Once()
+			}
+			if tc.getUserIDsByEmailsError == nil && tc.getBlockError == nil {
+				mockFriendshipRepo.On("GetFriendshipByUserIDs", ctx, friends[0], friends[1]).Return(domain.Friendship{
+					Base:   domain.Base{Id: friendshipId},
+					Status: tc.getFriendshipByUserIDsData,
+				}, tc.getFriendshipByUserIDsError).Once()
+				if tc.getFriendshipByUserIDsData == domain.FriendshipStatusBlocked {
+					mockBlock.On("GetBlock", ctx, friends[1], friends[0]).Return(domain.Block{}, tc.getReverseBlockError).Once()
+					if tc.getReverseBlockError == domain.ErrRecordNotFound {
+						mockFriendshipRepo.On("UpdateStatus", ctx, friendshipId, tc.getBlockData.RestoredFriendshipStatus()).Return(tc.updateFriendshipError).Once()
+					}
+				}
+			}
+			if tc.getUserIDsByEmailsError == nil && tc.getBlockError == nil && tc.getFriendshipByUserIDsError == nil && tc.updateFriendshipError == nil {
+				mockSub.On("GetSubscription", ctx, domain.Subscriptions{
+					{UserID: friends[1], SubscriberID: friends[0]},
+				}).Return(domain.Subscriptions{
+					{Base: domain.Base{Id: subId}, UserID: friends[1], SubscriberID: friends[0], Status: tc.getSubscriptionData},
+				}, tc.getSubscriptionError).Once()
+				if tc.getSubscriptionData == domain.SubscriptionStatusUnsubscribed {
+					if tc.getBlockData.SubscriptionStatus.IsNoneExisted() {
+						mockSub.On("Delete", ctx, subId).Return(tc.deleteSubscriptionError).Once()
+					} else {
+						mockSub.On("UpdateStatus", ctx, subId, tc.getBlockData.SubscriptionStatus).Return(tc.updateSubscriptionError).Once()
+					}
+				}
+				if tc.updateSubscriptionError == nil && tc.deleteSubscriptionError == nil {
+					mockBlock.On("Delete", ctx, tc.getBlockData.Id).Return(tc.deleteBlockError).Once()
+				}
+			}
+
+			err := h.Handle(ctx, payload.UnblockUserPayload{Requestor: emails[0], Target: emails[1]})
+			assert.Equal(t, tc.err, err)
+			mock.AssertExpectationsForObjects(t, mockFriendshipRepo, mockUserRepo, mockSub, mockBlock, mockTransaction)
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package command
+
+import (
+	"context"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+type UnfriendHandler struct {
+	friendshipRepo   domain.FriendshipRepo
+	userRepo         domain.UserRepo
+	subscriptionRepo domain.SubscriptionRepo
+	eventRepo        domain.EventRepo
+	auditRepo        domain.AuditRepo
+	transactor       Transactor
+	policy           domain.UnfriendSubscriptionPolicy
+}
+
+func NewUnfriendHandler(repo domain.FriendshipRepo, userRepo domain.UserRepo, subRepo domain.SubscriptionRepo, eventRepo domain.EventRepo, auditRepo domain.AuditRepo, transactor Transactor, policy domain.UnfriendSubscriptionPolicy) UnfriendHandler {
+	return UnfriendHandler{
+		friendshipRepo:   repo,
+		userRepo:         userRepo,
+		subscriptionRepo: subRepo,
+		eventRepo:        eventRepo,
+		auditRepo:        auditRepo,
+		transactor:       transactor,
+		policy:           policy,
+	}
+}
+
+func (h UnfriendHandler) Handle(ctx context.Context, payload payload.UnfriendPayload) error {
+	if payload.Requestor == payload.Target {
+		return common.ErrInvalidRequest(domain.ErrEmailIsNotValid, "payload")
+	}
+
+	userIDs, err := getUserIDsByEmails(ctx, h.userRepo, payload.Requestor, payload.Target)
+	if err != nil {
+		return err
+	}
+
+	if err = authorizeRequestor(ctx, userIDs[payload.Requestor]); err != nil {
+		return err
+	}
+
+	requestorID := userIDs[payload.Requestor]
+	targetID := userIDs[payload.Target]
+
+	return h.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		f, err := h.friendshipRepo.GetFriendshipByUserIDs(ctx, requestorID, targetID)
+		if err != nil && err != domain.ErrRecordNotFound {
+			logger.Errorf("friendshipRepo.GetFriendshipByUserIDs %w", err)
+			return common.ErrCannotGetEntity(f.DomainName(), err)
+		}
+		if err == domain.ErrRecordNotFound || !f.Status.CanUnfriend() {
+			return common.ErrInvalidRequest(domain.ErrFriendshipIsUnavailable, "")
+		}
+
+		if err = h.friendshipRepo.UpdateStatus(ctx, f.Id, domain.FriendshipStatusUnfriended); err != nil {
+			logger.Errorf("friendshipRepo.UpdateStatus %w", err)
+			return common.ErrCannotUpdateEntity(f.DomainName(), err)
+		}
+
+		if err = recordEvent(ctx, h.eventRepo, domain.EventUnfriended, f.Id, domain.RelationEvent{UserID: requestorID, TargetID: targetID}); err != nil {
+			return err
+		}
+		if err = recordAudit(ctx, h.auditRepo, domain.AuditUnfriend, domain.AuditStatusFriended, domain.AuditStatusUnfriended, requestorID, targetID); err != nil {
+			return err
+		}
+
+		if !h.policy.DropSubscriptions() {
+			return nil
+		}
+		return h.dropSubscriptions(ctx, requestorID, targetID)
+	})
+}
+
+// dropSubscriptions removes the subscriptions between the two users,
+// unsubscribed rows are kept because they record a block
+func (h UnfriendHandler) dropSubscriptions(ctx context.Context, requestorID, targetID string) error {
+	subs, err := h.subscriptionRepo.GetSubscription(ctx, domain.Subscriptions{
+		{UserID: requestorID, SubscriberID: targetID},
+		{UserID: targetID, SubscriberID: requestorID},
+	})
+	if err != nil {
+		logger.Errorf("subscriptionRepo.GetSubscription %w", err)
+		return common.ErrCannotGetEntity(domain.Subscription{}.DomainName(), err)
+	}
+
+	for _, sub := range subs {
+		if sub.Status != domain.SubscriptionStatusSubscribed {
+			continue
+		}
+		if err = h.subscriptionRepo.Delete(ctx, sub.Id); err != nil {
+			logger.Errorf("subscriptionRepo.Delete %w", err)
+			return common.ErrCannotDeleteEntity(sub.DomainName(), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/auth"
+	mockRepo "github.com/phantranhieunhan/s3-assignment/mock/friendship/repository"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestCase_Friendship_Unfriend struct {
+	name   string
+	err    error
+	policy domain.UnfriendSubscriptionPolicy
+
+	authUserID string
+
+	getUserIDsByEmailsError error
+	getUserIDsByEmailsData  map[string]string
+
+	withinTransactionError error
+
+	getFriendshipByUserIDsError error
+	getFriendshipByUserIDsData  domain.FriendshipStatus
+
+	updateError error
+
+	getSubscriptionError error
+	getSubscriptionData  domain.Subscriptions
+
+	deleteError error
+}
+
+func TestFriendship_Unfriend(t *testing.T) {
+	t.Parallel()
+
+	emails := []string{"email-1", "email-2"}
+	friends := []string{"friend-1", "friend-2"}
+	mapEmails := map[string]string{
+		emails[0]: friends[0],
+		emails[1]: friends[1],
+	}
+	friendshipId := "friendship-id"
+	subs := domain.Subscriptions{
+		{Base: domain.Base{Id: "sub-1"}, UserID: friends[0], SubscriberID: friends[1], Status: domain.SubscriptionStatusSubscribed},
+		{Base: domain.Base{Id: "sub-2"}, UserID: friends[1], SubscriberID: friends[0], Status: domain.SubscriptionStatusUnsubscribed},
+	}
+
+	errDB := errors.New("some error from db")
+
+	tcs := []TestCase_Friendship_Unfriend{
+		{
+			name:                       "unfriend successfully and keep subscriptions",
+			policy:                     domain.UnfriendSubscriptionPolicyKeep,
+			getUserIDsByEmailsData:     mapEmails,
+			getFriendshipByUserIDsData: domain.FriendshipStatusFriended,
+		},
+		{
+			name:                       "unfriend successfully when the requestor is the authenticated user",
+			policy:                     domain.UnfriendSubscriptionPolicyKeep,
+			authUserID:                 friends[0],
+			getUserIDsByEmailsData:     mapEmails,
+			getFriendshipByUserIDsData: domain.FriendshipStatusFriended,
+		},
+		{
+			name:                   "unfriend fail because the requestor is not the authenticated user",
+			policy:                 domain.UnfriendSubscriptionPolicyKeep,
+			authUserID:             friends[1],
+			getUserIDsByEmailsData: mapEmails,
+			err:                    common.NewForbidden(domain.ErrRequestorIsNotAuthenticatedUser, domain.ErrRequestorIsNotAuthenticatedUser.Error(), "ErrForbidden"),
+		},
+		{
+			name:                       "unfriend successfully and drop subscriptions",
+			policy:                     domain.UnfriendSubscriptionPolicyDrop,
+			getUserIDsByEmailsData:     mapEmails,
+			getFriendshipByUserIDsData: domain.FriendshipStatusFriended,
+			getSubscriptionData:        subs,
+		},
+		{
+			name:                    "unfriend fail because emails invalid",
+			getUserIDsByEmailsError: domain.ErrNotFoundUserByEmail,
+			getUserIDsByEmailsData:  map[string]string{},
+			err:                     common.ErrInvalidRequest(domain.ErrNotFoundUserByEmail, "emails"),
+		},
+		{
+			name:                        "unfriend fail because they have never connected",
+			getUserIDsByEmailsData:      mapEmails,
+			getFriendshipByUserIDsError: domain.ErrRecordNotFound,
+			withinTransactionError:      common.ErrInvalidRequest(domain.ErrFriendshipIsUnavailable, ""),
+			err:                         common.ErrInvalidRequest(domain.ErrFriendshipIsUnavailable, ""),
+		},
+		{
+			name:                       "unfriend fail because their relationship is blocked",
+			getUserIDsByEmailsData:     mapEmails,
+			getFriendshipByUserIDsData: domain.FriendshipStatusBlocked,
+			withinTransactionError:     common.ErrInvalidRequest(domain.ErrFriendshipIsUnavailable, ""),
+			err:                        common.ErrInvalidRequest(domain.ErrFriendshipIsUnavailable, ""),
+		},
+		{
+			name:                        "unfriend fail because get friendship fail",
+			getUserIDsByEmailsData:      mapEmails,
+			getFriendshipByUserIDsError: errDB,
+			withinTransactionError:      common.ErrCannotGetEntity(domain.Friendship{}.DomainName(), errDB),
+			err:                         common.ErrCannotGetEntity(domain.Friendship{}.DomainName(), errDB),
+		},
+		{
+			name:                       "unfriend fail because update friendship fail",
+			getUserIDsByEmailsData:     mapEmails,
+			getFriendshipByUserIDsData: domain.FriendshipStatusFriended,
+			updateError:                errDB,
+			withinTransactionError:     common.ErrCannotUpdateEntity(domain.Friendship{}.DomainName(), errDB),
+			err:                        common.ErrCannotUpdateEntity(domain.Friendship{}.DomainName(), errDB),
+		},
+		{
+			name:                       "unfriend fail because get subscriptions fail",
+			policy:                     domain.UnfriendSubscriptionPolicyDrop,
+			getUserIDsByEmailsData:     mapEmails,
+			getFriendshipByUserIDsData: domain.FriendshipStatusFriended,
+			getSubscriptionError:       errDB,
+			getSubscriptionData:        domain.Subscriptions{},
+			withinTransactionError:     common.ErrCannotGetEntity(domain.Subscription{}.DomainName(), errDB),
+			err:                        common.ErrCannotGetEntity(domain.Subscription{}.DomainName(), errDB),
+		},
+		{
+			name:                       "unfriend fail because delete subscription fail",
+			policy:                     domain.UnfriendSubscriptionPolicyDrop,
+			getUserIDsByEmailsData:     mapEmails,
+			getFriendshipByUserIDsData: domain.FriendshipStatusFriended,
+			getSubscriptionData:        subs,
+			deleteError:                errDB,
+			withinTransactionError:     common.ErrCannotDeleteEntity(domain.Subscription{}.DomainName(), errDB),
+			err:                        common.ErrCannotDeleteEntity(domain.Subscription{}.DomainName(), errDB),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			// the requestor is the authenticated user unless the case says otherwise
+			authUserID := tc.authUserID
+			if authUserID == "" {
+				authUserID = mapEmails[emails[0]]
+			}
+			ctx = auth.WithUserID(ctx, authUserID)
+
+			mockFriendshipRepo := new(mockRepo.MockFriendshipRepository)
+			mockUserRepo := newActiveUserRepo()
+			mockSub := new(mockRepo.MockSubscriptionRepository)
+			mockTransaction := new(mockRepo.MockTransaction)
+			h := NewUnfriendHandler(mockFriendshipRepo, mockUserRepo, mockSub, allowRecordEvent(), allowRecordAudit(), mockTransaction, tc.policy)
+
+			mockUserRepo.On("GetUserIDsByEmails", ctx, emails).Return(tc.getUserIDsByEmailsData, tc.getUserIDsByEmailsError).Once()
+			isAuthorized := tc.authUserID == "" || tc.authUserID == friends[0]
+			if tc.getUserIDsByEmailsError == nil && isAuthorized {
+				prepareWithinTransaction(t, ctx, mockTransaction, tc.withinTransactionError)
+				mockFriendshipRepo.On("GetFriendshipByUserIDs", ctx, friends[0], friends[1]).Return(domain.Friendship{
+					Base:   domain.Base{Id: friendshipId},
+					Status: tc.getFriendshipByUserIDsData,
+				}, tc.getFriendshipByUserIDsError).Once()
+				if tc.getFriendshipByUserIDsError == nil && tc.getFriendshipByUserIDsData.CanUnfriend() {
+					mockFriendshipRepo.On("UpdateStatus", ctx, friendshipId, domain.FriendshipStatusUnfriended).Return(tc.updateError).Once()
+					if tc.updateError == nil && tc.policy.DropSubscriptions() {
+						mockSub.On("GetSubscription", ctx, domain.Subscriptions{
+							{UserID: friends[0], SubscriberID: friends[1]},
+							{UserID: friends[1], SubscriberID: friends[0]},
+						}).Return(tc.getSubscriptionData, tc.getSubscriptionError).Once()
+						if tc.getSubscriptionError == nil {
+							// only the subscribed row is dropped, the unsubscribed one records a block
+							mockSub.On("Delete", ctx, "sub-1").Return(tc.deleteError).Once()
+						}
+					}
+				}
+			}
+
+			err := h.Handle(ctx, payload.UnfriendPayload{Requestor: emails[0], Target: emails[1]})
+			assert.Equal(t, tc.err, err)
+			mock.AssertExpectationsForObjects(t, mockFriendshipRepo, mockUserRepo, mockSub, mockTransaction)
+		})
+	}
+}
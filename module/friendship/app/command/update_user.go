@@ -0,0 +1,71 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+type UpdateUserHandler struct {
+	userRepo   domain.UserRepo
+	eventRepo  domain.EventRepo
+	auditRepo  domain.AuditRepo
+	transactor Transactor
+}
+
+func NewUpdateUserHandler(userRepo domain.UserRepo, eventRepo domain.EventRepo, auditRepo domain.AuditRepo, transactor Transactor) UpdateUserHandler {
+	return UpdateUserHandler{
+		userRepo:   userRepo,
+		eventRepo:  eventRepo,
+		auditRepo:  auditRepo,
+		transactor: transactor,
+	}
+}
+
+// Handle changes the email of the user, the new email must not be used by another user
+func (h UpdateUserHandler) Handle(ctx context.Context, payload payload.UpdateUserPayload) (domain.User, error) {
+	var user domain.User
+
+	err := h.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		user, err = getUserByEmail(ctx, h.userRepo, payload.Email)
+		if err != nil {
+			return err
+		}
+
+		if err = authorizeRequestor(ctx, user.Base.Id); err != nil {
+			return err
+		}
+		if err = ensureUsersAreActive(ctx, h.userRepo, "email", user.Base.Id); err != nil {
+			return err
+		}
+
+		if payload.NewEmail == user.Email {
+			return nil
+		}
+
+		if err = ensureEmailIsAvailable(ctx, h.userRepo, payload.NewEmail); err != nil {
+			return err
+		}
+
+		user.Email = payload.NewEmail
+		user.Base.UpdatedAt = time.Now().UTC()
+		if err = h.userRepo.Update(ctx, user); err != nil {
+			logger.Errorf("userRepo.Update %w", err)
+			return common.ErrCannotUpdateEntity(user.DomainName(), err)
+		}
+		if err = recordEvent(ctx, h.eventRepo, domain.EventUserUpdated, user.Base.Id, domain.UserEvent{Email: user.Email}); err != nil {
+			return err
+		}
+		return recordAudit(ctx, h.auditRepo, domain.AuditUpdateUser, domain.AuditStatusActive, domain.AuditStatusActive, user.Base.Id)
+	})
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	return user, nil
+}
@@ -0,0 +1,108 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/auth"
+	mockRepo "github.com/phantranhieunhan/s3-assignment/mock/friendship/repository"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestCase_User_UpdateUser struct {
+	name     string
+	newEmail string
+	err      error
+
+	withinTransactionError error
+
+	getUserByEmailError    error
+	getUserByNewEmailError error
+	updateError            error
+}
+
+func TestUser_UpdateUser(t *testing.T) {
+	t.Parallel()
+
+	email := "email-1"
+	newEmail := "email-2"
+	user := domain.User{Base: domain.Base{Id: "user-1"}, Email: email}
+	errDB := errors.New("some error from db")
+
+	tcs := []TestCase_User_UpdateUser{
+		{
+			name:                   "update user successfully",
+			newEmail:               newEmail,
+			getUserByNewEmailError: domain.ErrRecordNotFound,
+		},
+		{
+			name:     "update user successfully without change",
+			newEmail: email,
+		},
+		{
+			name:                   "update user fail because user not found",
+			newEmail:               newEmail,
+			getUserByEmailError:    domain.ErrRecordNotFound,
+			withinTransactionError: common.ErrInvalidRequest(domain.ErrNotFoundUserByEmail, "email"),
+			err:                    common.ErrInvalidRequest(domain.ErrNotFoundUserByEmail, "email"),
+		},
+		{
+			name:                   "update user fail because get user fail",
+			newEmail:               newEmail,
+			getUserByEmailError:    errDB,
+			withinTransactionError: common.ErrCannotGetEntity(domain.User{}.DomainName(), errDB),
+			err:                    common.ErrCannotGetEntity(domain.User{}.DomainName(), errDB),
+		},
+		{
+			name:                   "update user fail because new email already exists",
+			newEmail:               newEmail,
+			withinTransactionError: common.ErrInvalidRequest(domain.ErrAlreadyExists, "email"),
+			err:                    common.ErrInvalidRequest(domain.ErrAlreadyExists, "email"),
+		},
+		{
+			name:                   "update user fail because update fail",
+			newEmail:               newEmail,
+			getUserByNewEmailError: domain.ErrRecordNotFound,
+			updateError:            errDB,
+			withinTransactionError: common.ErrCannotUpdateEntity(domain.User{}.DomainName(), errDB),
+			err:                    common.ErrCannotUpdateEntity(domain.User{}.DomainName(), errDB),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			ctx = auth.WithUserID(ctx, user.Base.Id)
+
+			mockUserRepo := newActiveUserRepo()
+			mockTransaction := new(mockRepo.MockTransaction)
+			h := NewUpdateUserHandler(mockUserRepo, allowRecordEvent(), allowRecordAudit(), mockTransaction)
+
+			prepareWithinTransaction(t, ctx, mockTransaction, tc.withinTransactionError)
+			mockUserRepo.On("GetUserByEmail", ctx, email).Return(user, tc.getUserByEmailError).Once()
+			if tc.getUserByEmailError == nil && tc.newEmail != email {
+				mockUserRepo.On("GetUserByEmail", ctx, newEmail).Return(domain.User{}, tc.getUserByNewEmailError).Once()
+				if tc.getUserByNewEmailError == domain.ErrRecordNotFound {
+					mockUserRepo.On("Update", ctx, mock.MatchedBy(func(d domain.User) bool {
+						return d.Base.Id == user.Base.Id && d.Email == newEmail
+					})).Return(tc.updateError).Once()
+				}
+			}
+
+			result, err := h.Handle(ctx, payload.UpdateUserPayload{Email: email, NewEmail: tc.newEmail})
+			assert.Equal(t, tc.err, err)
+			if tc.err == nil {
+				assert.Equal(t, tc.newEmail, result.Email)
+			}
+			mock.AssertExpectationsForObjects(t, mockUserRepo, mockTransaction)
+		})
+	}
+}
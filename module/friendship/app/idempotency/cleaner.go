@@ -0,0 +1,52 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+// Cleaner deletes the idempotency records once their ttl is over, an expired record is never replayed
+// but it would stay in the table otherwise
+type Cleaner struct {
+	repo     domain.IdempotencyRepo
+	ttl      time.Duration
+	interval time.Duration
+}
+
+func NewCleaner(repo domain.IdempotencyRepo, ttl, interval time.Duration) Cleaner {
+	return Cleaner{
+		repo:     repo,
+		ttl:      ttl,
+		interval: interval,
+	}
+}
+
+// Run deletes the expired records every interval until the context is done
+func (c Cleaner) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := c.CleanOnce(ctx); err != nil {
+				logger.Errorf("cleaner.CleanOnce %w", err)
+			}
+		}
+	}
+}
+
+// CleanOnce deletes the records created before the ttl and returns how many there were
+func (c Cleaner) CleanOnce(ctx context.Context) (int, error) {
+	n, err := c.repo.DeleteExpired(ctx, time.Now().Add(-c.ttl))
+	if err != nil {
+		logger.Errorf("repo.DeleteExpired %w", err)
+		return 0, err
+	}
+	return n, nil
+}
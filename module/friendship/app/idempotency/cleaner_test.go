@@ -0,0 +1,62 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	mockRepo "github.com/phantranhieunhan/s3-assignment/mock/friendship/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestCase_Cleaner_CleanOnce struct {
+	name string
+
+	deleteExpiredData  int
+	deleteExpiredError error
+
+	n   int
+	err error
+}
+
+func TestCleaner_CleanOnce(t *testing.T) {
+	t.Parallel()
+
+	errDB := errors.New("some error from db")
+
+	tcs := []TestCase_Cleaner_CleanOnce{
+		{
+			name:              "delete the expired records successfully",
+			deleteExpiredData: 3,
+			n:                 3,
+		},
+		{
+			name:               "delete nothing when the delete fails",
+			deleteExpiredError: errDB,
+			err:                errDB,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			repo := new(mockRepo.MockIdempotencyRepository)
+			c := NewCleaner(repo, time.Hour, time.Minute)
+
+			// the records expire one ttl before now
+			repo.On("DeleteExpired", ctx, mock.MatchedBy(func(before time.Time) bool {
+				return time.Since(before) >= time.Hour && time.Since(before) < time.Hour+time.Minute
+			})).Return(tc.deleteExpiredData, tc.deleteExpiredError).Once()
+
+			n, err := c.CleanOnce(ctx)
+			assert.Equal(t, tc.err, err)
+			assert.Equal(t, tc.n, n)
+			mock.AssertExpectationsForObjects(t, repo)
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+// Relay polls the outbox and publishes the events in the order they were written. The instances relay one batch
+// at a time and an event is only read after the events before it are delivered, so the order holds across the batches.
+// The events are published at least once: an event published right before a failing commit is published again,
+// before the events written after it
+type Relay struct {
+	eventRepo  domain.EventRepo
+	publisher  domain.EventPublisher
+	transactor command.Transactor
+	interval   time.Duration
+	batchSize  int
+}
+
+func NewRelay(eventRepo domain.EventRepo, publisher domain.EventPublisher, transactor command.Transactor, interval time.Duration, batchSize int) Relay {
+	return Relay{
+		eventRepo:  eventRepo,
+		publisher:  publisher,
+		transactor: transactor,
+		interval:   interval,
+		batchSize:  batchSize,
+	}
+}
+
+// Run relays the outbox every interval until the context is done
+func (r Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// drain the backlog without waiting for the next tick
+			for {
+				n, err := r.RelayOnce(ctx)
+				if err != nil {
+					logger.Errorf("relay.RelayOnce %w", err)
+				}
+				if err != nil || n < r.batchSize {
+					break
+				}
+			}
+		}
+	}
+}
+
+// RelayOnce publishes a batch of undelivered events and marks them delivered.
+// It stops at the first event failing to publish so that the order is kept, the rest is retried next time
+func (r Relay) RelayOnce(ctx context.Context) (int, error) {
+	var (
+		delivered  []string
+		errPublish error
+	)
+	err := r.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		delivered, errPublish = nil, nil
+		events, err := r.eventRepo.GetUndelivered(ctx, r.batchSize)
+		if err != nil {
+			logger.Errorf("eventRepo.GetUndelivered %w", err)
+			return err
+		}
+
+		for _, e := range events {
+			if errPublish = r.publisher.Publish(ctx, e); errPublish != nil {
+				logger.Errorf("publisher.Publish %s %w", e.ID, errPublish)
+				break
+			}
+			delivered = append(delivered, e.ID)
+		}
+
+		if len(delivered) > 0 {
+			if err = r.eventRepo.MarkDelivered(ctx, delivered); err != nil {
+				logger.Errorf("eventRepo.MarkDelivered %w", err)
+				return err
+			}
+		}
+		// commit what was published even when an event failed
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(delivered), errPublish
+}
@@ -0,0 +1,121 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	mockRepo "github.com/phantranhieunhan/s3-assignment/mock/friendship/repository"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// transactor runs the function as is and returns its error like a rollback would
+type transactor struct{}
+
+func (transactor) WithinTransaction(ctx context.Context, f func(ctx context.Context) error) error {
+	return f(ctx)
+}
+
+type TestCase_Relay_RelayOnce struct {
+	name string
+
+	getUndeliveredData  []domain.Event
+	getUndeliveredError error
+
+	// publishErrors holds the error of each event published in order
+	publishErrors []error
+
+	markDeliveredIDs   []string
+	markDeliveredError error
+
+	n   int
+	err error
+}
+
+func TestRelay_RelayOnce(t *testing.T) {
+	t.Parallel()
+
+	events := []domain.Event{
+		{ID: "event-1", Type: domain.EventUserCreated},
+		{ID: "event-2", Type: domain.EventFriendshipConnected},
+		{ID: "event-3", Type: domain.EventUserDeleted},
+	}
+	errDB := errors.New("some error from db")
+	errPublish := errors.New("some error from broker")
+
+	tcs := []TestCase_Relay_RelayOnce{
+		{
+			name: "relay all events successfully",
+
+			getUndeliveredData: events,
+			publishErrors:      []error{nil, nil, nil},
+			markDeliveredIDs:   []string{"event-1", "event-2", "event-3"},
+			n:                  3,
+		},
+		{
+			name: "relay nothing when the outbox is empty",
+
+			getUndeliveredData: []domain.Event{},
+		},
+		{
+			name: "relay the events before the first failing one",
+
+			getUndeliveredData: events,
+			publishErrors:      []error{nil, errPublish},
+			markDeliveredIDs:   []string{"event-1"},
+			n:                  1,
+			err:                errPublish,
+		},
+		{
+			name: "relay nothing when the first event fails",
+
+			getUndeliveredData: events,
+			publishErrors:      []error{errPublish},
+			err:                errPublish,
+		},
+		{
+			name: "relay fail because get undelivered events fail",
+
+			getUndeliveredData:  []domain.Event{},
+			getUndeliveredError: errDB,
+			err:                 errDB,
+		},
+		{
+			name: "relay fail because mark delivered fail",
+
+			getUndeliveredData: events,
+			publishErrors:      []error{nil, nil, nil},
+			markDeliveredIDs:   []string{"event-1", "event-2", "event-3"},
+			markDeliveredError: errDB,
+			err:                errDB,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			mockEventRepo := new(mockRepo.MockEventRepository)
+			mockPublisher := new(mockRepo.MockEventPublisher)
+			r := NewRelay(mockEventRepo, mockPublisher, transactor{}, time.Second, 10)
+
+			mockEventRepo.On("GetUndelivered", ctx, 10).Return(tc.getUndeliveredData, tc.getUndeliveredError).Once()
+			for i, err := range tc.publishErrors {
+				mockPublisher.On("Publish", ctx, tc.getUndeliveredData[i]).Return(err).Once()
+			}
+			if len(tc.markDeliveredIDs) > 0 {
+				mockEventRepo.On("MarkDelivered", ctx, tc.markDeliveredIDs).Return(tc.markDeliveredError).Once()
+			}
+
+			n, err := r.RelayOnce(ctx)
+			assert.Equal(t, tc.err, err)
+			assert.Equal(t, tc.n, n)
+			mock.AssertExpectationsForObjects(t, mockEventRepo, mockPublisher)
+		})
+	}
+}
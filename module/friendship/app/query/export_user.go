@@ -0,0 +1,224 @@
+package query
+
+import (
+	"context"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/auth"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+// exportChunkSize is the number of rows of a section read and written at a time
+const exportChunkSize = 200
+
+type ExportUserHandler struct {
+	userRepo         domain.UserRepo
+	friendshipRepo   domain.FriendshipRepo
+	subscriptionRepo domain.SubscriptionRepo
+	blockRepo        domain.BlockRepo
+	updateRepo       domain.UpdateRepo
+	auditRepo        domain.AuditRepo
+}
+
+func NewExportUserHandler(userRepo domain.UserRepo, friendshipRepo domain.FriendshipRepo, subscriptionRepo domain.SubscriptionRepo, blockRepo domain.BlockRepo, updateRepo domain.UpdateRepo, auditRepo domain.AuditRepo) ExportUserHandler {
+	return ExportUserHandler{
+		userRepo:         userRepo,
+		friendshipRepo:   friendshipRepo,
+		subscriptionRepo: subscriptionRepo,
+		blockRepo:        blockRepo,
+		updateRepo:       updateRepo,
+		auditRepo:        auditRepo,
+	}
+}
+
+// Handle writes everything held about the user to the export. The rows are read a chunk at a time so that
+// a large account is never held in memory as a whole. Nothing is written when the user is not found
+// or is not the authenticated user
+func (h ExportUserHandler) Handle(ctx context.Context, email string, w domain.UserExport) error {
+	user, err := h.userRepo.GetUserByEmail(ctx, email)
+	if err != nil {
+		logger.Errorf("userRepo.GetUserByEmail %w", err)
+		if err == domain.ErrRecordNotFound {
+			return common.ErrInvalidRequest(domain.ErrNotFoundUserByEmail, "email")
+		}
+		return common.ErrCannotGetEntity(user.DomainName(), err)
+	}
+
+	// the export holds the blocks of the user, only the user itself may read it
+	if authUserID, ok := auth.UserIDFromContext(ctx); ok && authUserID != user.Base.Id {
+		return common.NewForbidden(domain.ErrRequestorIsNotAuthenticatedUser, domain.ErrRequestorIsNotAuthenticatedUser.Error(), "ErrForbidden")
+	}
+
+	user.Password = ""
+	if err = w.WriteUser(user); err != nil {
+		return err
+	}
+	if err = h.exportFriendships(ctx, user.Base.Id, w); err != nil {
+		return err
+	}
+	if err = h.exportSubscriptions(ctx, user.Base.Id, w); err != nil {
+		return err
+	}
+	if err = h.exportBlocks(ctx, user.Base.Id, w); err != nil {
+		return err
+	}
+	if err = h.exportUpdates(ctx, user.Base.Id, w); err != nil {
+		return err
+	}
+	return h.exportAuditLog(ctx, user.Base.Id, w)
+}
+
+func (h ExportUserHandler) exportFriendships(ctx context.Context, userID string, w domain.UserExport) error {
+	return exportChunks(ctx, h.userRepo,
+		func(afterID string) ([]domain.Friendship, string, error) {
+			list, err := h.friendshipRepo.ListFriendshipsByUserID(ctx, userID, afterID, exportChunkSize)
+			if err != nil {
+				logger.Errorf("friendshipRepo.ListFriendshipsByUserID %w", err)
+				return nil, "", common.ErrCannotListEntity(domain.Friendship{}.DomainName(), err)
+			}
+			return list, nextAfterID(list, func(f domain.Friendship) string { return f.Id }), nil
+		},
+		func(f domain.Friendship) []string {
+			return []string{f.UserID, f.FriendID}
+		},
+		func(list []domain.Friendship, emails map[string]string) error {
+			rows := make([]domain.ExportedFriendship, 0, len(list))
+			for _, f := range list {
+				rows = append(rows, domain.ExportedFriendship{Friendship: f, UserEmail: emails[f.UserID], FriendEmail: emails[f.FriendID]})
+			}
+			return w.WriteFriendships(rows)
+		})
+}
+
+func (h ExportUserHandler) exportSubscriptions(ctx context.Context, userID string, w domain.UserExport) error {
+	return exportChunks(ctx, h.userRepo,
+		func(afterID string) ([]domain.Subscription, string, error) {
+			list, err := h.subscriptionRepo.ListSubscriptionsByUserID(ctx, userID, afterID, exportChunkSize)
+			if err != nil {
+				logger.Errorf("subscriptionRepo.ListSubscriptionsByUserID %w", err)
+				return nil, "", common.ErrCannotListEntity(domain.Subscription{}.DomainName(), err)
+			}
+			return list, nextAfterID(list, func(s domain.Subscription) string { return s.Id }), nil
+		},
+		func(s domain.Subscription) []string {
+			return []string{s.UserID, s.SubscriberID}
+		},
+		func(list []domain.Subscription, emails map[string]string) error {
+			rows := make([]domain.ExportedSubscription, 0, len(list))
+			for _, s := range list {
+				rows = append(rows, domain.ExportedSubscription{Subscription: s, UserEmail: emails[s.UserID], SubscriberEmail: emails[s.SubscriberID]})
+			}
+			return w.WriteSubscriptions(rows)
+		})
+}
+
+func (h ExportUserHandler) exportBlocks(ctx context.Context, userID string, w domain.UserExport) error {
+	return exportChunks(ctx, h.userRepo,
+		func(afterID string) ([]domain.Block, string, error) {
+			list, err := h.blockRepo.ListBlocksByUserID(ctx, userID, afterID, exportChunkSize)
+			if err != nil {
+				logger.Errorf("blockRepo.ListBlocksByUserID %w", err)
+				return nil, "", common.ErrCannotListEntity(domain.Block{}.DomainName(), err)
+			}
+			return list, nextAfterID(list, func(b domain.Block) string { return b.Id }), nil
+		},
+		func(b domain.Block) []string {
+			return []string{b.TargetID}
+		},
+		func(list []domain.Block, emails map[string]string) error {
+			rows := make([]domain.ExportedBlock, 0, len(list))
+			for _, b := range list {
+				rows = append(rows, domain.ExportedBlock{Block: b, TargetEmail: emails[b.TargetID]})
+			}
+			return w.WriteBlocks(rows)
+		})
+}
+
+// exportUpdates writes the updates posted by the user, the mentions are emails already
+func (h ExportUserHandler) exportUpdates(ctx context.Context, userID string, w domain.UserExport) error {
+	return exportChunks(ctx, h.userRepo,
+		func(afterID string) ([]domain.Update, string, error) {
+			list, err := h.updateRepo.ListUpdatesByUserID(ctx, userID, afterID, exportChunkSize)
+			if err != nil {
+				logger.Errorf("updateRepo.ListUpdatesByUserID %w", err)
+				return nil, "", common.ErrCannotListEntity(domain.Update{}.DomainName(), err)
+			}
+			return list, nextAfterID(list, func(u domain.Update) string { return u.Id }), nil
+		},
+		func(u domain.Update) []string {
+			return nil
+		},
+		func(list []domain.Update, _ map[string]string) error {
+			return w.WriteUpdates(list)
+		})
+}
+
+// exportAuditLog writes the audit entries acted by the user or targeting them, the newest first. The actors
+// and the targets stay ids, as in the audit log, since they are not all users
+func (h ExportUserHandler) exportAuditLog(ctx context.Context, userID string, w domain.UserExport) error {
+	return exportChunks(ctx, h.userRepo,
+		func(cursor string) ([]domain.AuditEntry, string, error) {
+			list, next, err := h.auditRepo.List(ctx, domain.AuditFilter{UserID: userID}, domain.Page{Limit: exportChunkSize, Cursor: cursor})
+			if err != nil {
+				logger.Errorf("auditRepo.List %w", err)
+				return nil, "", common.ErrCannotListEntity(domain.AuditEntry{}.DomainName(), err)
+			}
+			return list, next, nil
+		},
+		func(e domain.AuditEntry) []string {
+			return nil
+		},
+		func(list []domain.AuditEntry, _ map[string]string) error {
+			return w.WriteAuditEntries(list)
+		})
+}
+
+// nextAfterID is the cursor of the chunk after a chunk listed by id, none when the chunk came short
+func nextAfterID[T any](rows []T, id func(row T) string) string {
+	if len(rows) < exportChunkSize {
+		return ""
+	}
+	return id(rows[len(rows)-1])
+}
+
+// exportChunks reads the rows of a section chunk by chunk until the list returns no next cursor. The emails
+// of the users referenced by a chunk are looked up at once before the chunk is written
+func exportChunks[T any](ctx context.Context, userRepo domain.UserRepo, list func(cursor string) ([]T, string, error),
+	refs func(row T) []string, write func(rows []T, emails map[string]string) error) error {
+	for cursor := ""; ; {
+		rows, next, err := list(cursor)
+		if err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+
+		userIDs := make([]string, 0, len(rows))
+		seen := make(map[string]bool, len(rows))
+		for _, row := range rows {
+			for _, id := range refs(row) {
+				if !seen[id] {
+					seen[id] = true
+					userIDs = append(userIDs, id)
+				}
+			}
+		}
+		emails := map[string]string{}
+		if len(userIDs) > 0 {
+			if emails, err = userRepo.GetEmailsByUserIDs(ctx, userIDs); err != nil {
+				logger.Errorf("userRepo.GetEmailsByUserIDs %w", err)
+				return common.ErrCannotGetEntity(domain.User{}.DomainName(), err)
+			}
+		}
+
+		if err = write(rows, emails); err != nil {
+			return err
+		}
+		if next == "" {
+			return nil
+		}
+		cursor = next
+	}
+}
@@ -0,0 +1,233 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/auth"
+	mockRepo "github.com/phantranhieunhan/s3-assignment/mock/friendship/repository"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// recordedExport keeps what is written to the export, a chunk per write
+type recordedExport struct {
+	users         []domain.User
+	friendships   [][]domain.ExportedFriendship
+	subscriptions [][]domain.ExportedSubscription
+	blocks        [][]domain.ExportedBlock
+	updates       [][]domain.Update
+	auditEntries  [][]domain.AuditEntry
+}
+
+func (r *recordedExport) WriteUser(u domain.User) error {
+	r.users = append(r.users, u)
+	return nil
+}
+
+func (r *recordedExport) WriteFriendships(list []domain.ExportedFriendship) error {
+	r.friendships = append(r.friendships, list)
+	return nil
+}
+
+func (r *recordedExport) WriteSubscriptions(list []domain.ExportedSubscription) error {
+	r.subscriptions = append(r.subscriptions, list)
+	return nil
+}
+
+func (r *recordedExport) WriteBlocks(list []domain.ExportedBlock) error {
+	r.blocks = append(r.blocks, list)
+	return nil
+}
+
+func (r *recordedExport) WriteUpdates(list []domain.Update) error {
+	r.updates = append(r.updates, list)
+	return nil
+}
+
+func (r *recordedExport) WriteAuditEntries(list []domain.AuditEntry) error {
+	r.auditEntries = append(r.auditEntries, list)
+	return nil
+}
+
+type TestCase_User_ExportUser struct {
+	name       string
+	authUserID string
+
+	getUserByEmailError error
+
+	friendships                    domain.Friendships
+	listFriendshipsByUserIDError   error
+	subscriptions                  domain.Subscriptions
+	listSubscriptionsByUserIDError error
+	updates                        []domain.Update
+	listAuditError                 error
+
+	err              error
+	friendshipChunks int
+	subscriptionRows int
+	auditChunks      int
+	userWritten      bool
+}
+
+func TestUser_ExportUser(t *testing.T) {
+	t.Parallel()
+
+	email := "email-1"
+	user := domain.User{Base: domain.Base{Id: "user-1"}, Email: email, Password: "hash"}
+	emails := map[string]string{"user-1": email, "user-2": "email-2", "user-3": "email-3"}
+	errDB := errors.New("some error from db")
+
+	friendships := domain.Friendships{
+		{Base: domain.Base{Id: "friendship-1"}, UserID: "user-1", FriendID: "user-2", Status: domain.FriendshipStatusFriended},
+		{Base: domain.Base{Id: "friendship-2"}, UserID: "user-3", FriendID: "user-1", Status: domain.FriendshipStatusPending},
+	}
+	fullChunk := make(domain.Friendships, 0, exportChunkSize)
+	for i := 0; i < exportChunkSize; i++ {
+		fullChunk = append(fullChunk, domain.Friendship{Base: domain.Base{Id: fmt.Sprintf("friendship-%03d", i)}, UserID: "user-1", FriendID: "user-2"})
+	}
+	subscriptions := domain.Subscriptions{
+		{Base: domain.Base{Id: "subscription-1"}, UserID: "user-2", SubscriberID: "user-1", Status: domain.SubscriptionStatusSubscribed},
+	}
+	updates := []domain.Update{{Base: domain.Base{Id: "update-1"}, UserID: "user-1", Text: "hello email-2", Mentions: []string{"email-2"}}}
+	auditFilter := domain.AuditFilter{UserID: "user-1"}
+	auditPages := [][]domain.AuditEntry{
+		{{ID: "audit-2", Actor: "user-1", Action: domain.AuditPostUpdate, TargetIDs: []string{"user-1"}}},
+		{{ID: "audit-1", Actor: domain.AuditActorAdmin, Action: domain.AuditCreateUser, TargetIDs: []string{"user-1"}}},
+	}
+
+	tcs := []TestCase_User_ExportUser{
+		{
+			name:             "export successfully",
+			authUserID:       "user-1",
+			friendships:      friendships,
+			subscriptions:    subscriptions,
+			updates:          updates,
+			friendshipChunks: 1,
+			subscriptionRows: 1,
+			auditChunks:      2,
+			userWritten:      true,
+		},
+		{
+			name:             "export the rows chunk by chunk until a chunk comes short",
+			friendships:      fullChunk,
+			subscriptions:    domain.Subscriptions{},
+			friendshipChunks: 2,
+			auditChunks:      2,
+			userWritten:      true,
+		},
+		{
+			name:                "export fail because user not found",
+			getUserByEmailError: domain.ErrRecordNotFound,
+			err:                 common.ErrInvalidRequest(domain.ErrNotFoundUserByEmail, "email"),
+		},
+		{
+			name:       "export fail because requestor is another user",
+			authUserID: "user-2",
+			err:        common.NewForbidden(domain.ErrRequestorIsNotAuthenticatedUser, domain.ErrRequestorIsNotAuthenticatedUser.Error(), "ErrForbidden"),
+		},
+		{
+			name:                         "export fail because list friendships fail",
+			listFriendshipsByUserIDError: errDB,
+			err:                          common.ErrCannotListEntity(domain.Friendship{}.DomainName(), errDB),
+			userWritten:                  true,
+		},
+		{
+			name:                           "export fail because list subscriptions fail",
+			friendships:                    friendships,
+			listSubscriptionsByUserIDError: errDB,
+			err:                            common.ErrCannotListEntity(domain.Subscription{}.DomainName(), errDB),
+			friendshipChunks:               1,
+			userWritten:                    true,
+		},
+		{
+			name:             "export fail because list audit log fail",
+			friendships:      friendships,
+			subscriptions:    subscriptions,
+			listAuditError:   errDB,
+			err:              common.ErrCannotListEntity(domain.AuditEntry{}.DomainName(), errDB),
+			friendshipChunks: 1,
+			subscriptionRows: 1,
+			userWritten:      true,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			if tc.authUserID != "" {
+				ctx = auth.WithUserID(ctx, tc.authUserID)
+			}
+
+			mockUserRepo := new(mockRepo.MockUserRepository)
+			mockFriendshipRepo := new(mockRepo.MockFriendshipRepository)
+			mockSubscriptionRepo := new(mockRepo.MockSubscriptionRepository)
+			mockBlockRepo := new(mockRepo.MockBlockRepository)
+			mockUpdateRepo := new(mockRepo.MockUpdateRepository)
+			mockAuditRepo := new(mockRepo.MockAuditRepository)
+			h := NewExportUserHandler(mockUserRepo, mockFriendshipRepo, mockSubscriptionRepo, mockBlockRepo, mockUpdateRepo, mockAuditRepo)
+
+			mockUserRepo.On("GetUserByEmail", ctx, email).Return(user, tc.getUserByEmailError).Once()
+			mockUserRepo.On("GetEmailsByUserIDs", ctx, mock.Anything).Return(emails, nil).Maybe()
+			mockFriendshipRepo.On("ListFriendshipsByUserID", ctx, "user-1", "", exportChunkSize).Return(tc.friendships, tc.listFriendshipsByUserIDError).Maybe()
+			if len(tc.friendships) == exportChunkSize {
+				last := tc.friendships[exportChunkSize-1].Id
+				mockFriendshipRepo.On("ListFriendshipsByUserID", ctx, "user-1", last, exportChunkSize).Return(friendships, nil).Once()
+			}
+			mockSubscriptionRepo.On("ListSubscriptionsByUserID", ctx, "user-1", "", exportChunkSize).Return(tc.subscriptions, tc.listSubscriptionsByUserIDError).Maybe()
+			mockBlockRepo.On("ListBlocksByUserID", ctx, "user-1", "", exportChunkSize).Return([]domain.Block{}, nil).Maybe()
+			if tc.updates == nil {
+				tc.updates = []domain.Update{}
+			}
+			mockUpdateRepo.On("ListUpdatesByUserID", ctx, "user-1", "", exportChunkSize).Return(tc.updates, nil).Maybe()
+			if tc.listAuditError != nil {
+				mockAuditRepo.On("List", ctx, auditFilter, domain.Page{Limit: exportChunkSize}).Return([]domain.AuditEntry{}, "", tc.listAuditError).Once()
+			} else {
+				// the audit log is read page by page with the cursor of the previous page
+				mockAuditRepo.On("List", ctx, auditFilter, domain.Page{Limit: exportChunkSize}).Return(auditPages[0], "cursor-1", nil).Maybe()
+				mockAuditRepo.On("List", ctx, auditFilter, domain.Page{Limit: exportChunkSize, Cursor: "cursor-1"}).Return(auditPages[1], "", nil).Maybe()
+			}
+
+			w := &recordedExport{}
+			err := h.Handle(ctx, email, w)
+			assert.Equal(t, tc.err, err)
+			mock.AssertExpectationsForObjects(t, mockUserRepo, mockFriendshipRepo)
+
+			if !tc.userWritten {
+				assert.Empty(t, w.users)
+				return
+			}
+			if assert.Len(t, w.users, 1) {
+				assert.Empty(t, w.users[0].Password)
+			}
+			assert.Len(t, w.friendships, tc.friendshipChunks)
+			if tc.friendshipChunks > 0 {
+				last := w.friendships[len(w.friendships)-1]
+				assert.Equal(t, domain.ExportedFriendship{Friendship: friendships[1], UserEmail: "email-3", FriendEmail: email}, last[1])
+			}
+			rows := 0
+			for _, chunk := range w.subscriptions {
+				rows += len(chunk)
+			}
+			assert.Equal(t, tc.subscriptionRows, rows)
+			if tc.subscriptionRows > 0 {
+				assert.Equal(t, "email-2", w.subscriptions[0][0].UserEmail)
+			}
+			assert.Empty(t, w.blocks)
+			if len(tc.updates) > 0 {
+				assert.Equal(t, [][]domain.Update{updates}, w.updates)
+			}
+			assert.Len(t, w.auditEntries, tc.auditChunks)
+			if tc.auditChunks > 0 {
+				assert.Equal(t, auditPages, w.auditEntries)
+			}
+		})
+	}
+}
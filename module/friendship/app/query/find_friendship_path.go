@@ -0,0 +1,59 @@
+package query
+
+import (
+	"context"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+type FindFriendshipPathHandler struct {
+	repo     domain.FriendshipRepo
+	userRepo domain.UserRepo
+}
+
+func NewFindFriendshipPathHandler(repo domain.FriendshipRepo, userRepo domain.UserRepo) FindFriendshipPathHandler {
+	return FindFriendshipPathHandler{
+		repo:     repo,
+		userRepo: userRepo,
+	}
+}
+
+// Handle returns the emails of the shortest chain of friends between the users, both included,
+// the list is empty when they are not connected within maxDepth friendships
+func (h FindFriendshipPathHandler) Handle(ctx context.Context, fromEmail, toEmail string, maxDepth int) ([]string, error) {
+	emails := []string{fromEmail}
+	if toEmail != fromEmail {
+		emails = append(emails, toEmail)
+	}
+	mapEmailUser, err := h.userRepo.GetUserIDsByEmails(ctx, emails)
+	if err != nil {
+		logger.Errorf("userRepo.GetUserIDsByEmails %w", err)
+		if err == domain.ErrNotFoundUserByEmail {
+			return nil, common.ErrInvalidRequest(err, "emails")
+		}
+		return nil, common.ErrCannotGetEntity(domain.User{}.DomainName(), err)
+	}
+
+	path, err := h.repo.GetShortestFriendPath(ctx, mapEmailUser[fromEmail], mapEmailUser[toEmail], maxDepth)
+	if err != nil {
+		if err == domain.ErrRecordNotFound {
+			return []string{}, nil
+		}
+		logger.Errorf("friendshipRepo.GetShortestFriendPath %w", err)
+		return nil, common.ErrCannotListEntity(domain.Friendship{}.DomainName(), err)
+	}
+
+	mapUserEmail, err := h.userRepo.GetEmailsByUserIDs(ctx, path)
+	if err != nil {
+		logger.Errorf("userRepo.GetEmailsByUserIDs %w", err)
+		return nil, common.ErrCannotGetEntity(domain.User{}.DomainName(), err)
+	}
+
+	result := make([]string, 0, len(path))
+	for _, id := range path {
+		result = append(result, mapUserEmail[id])
+	}
+	return result, nil
+}
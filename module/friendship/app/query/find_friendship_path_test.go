@@ -0,0 +1,108 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	mockRepo "github.com/phantranhieunhan/s3-assignment/mock/friendship/repository"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestCase_Friendship_FindFriendshipPath struct {
+	name                    string
+	result                  []string
+	err                     error
+	getUserIDsByEmailsError error
+	getUserIDsByEmailsData  map[string]string
+
+	getShortestFriendPathError error
+	getShortestFriendPathData  []string
+
+	getEmailsByUserIDsError error
+}
+
+func TestFriendship_FindFriendshipPath(t *testing.T) {
+	t.Parallel()
+
+	emails := []string{"email-1", "email-3"}
+	mapEmails := map[string]string{
+		"email-1": "user-1",
+		"email-3": "user-3",
+	}
+	path := []string{"user-1", "user-2", "user-3"}
+	mapUsers := map[string]string{
+		"user-1": "email-1",
+		"user-2": "email-2",
+		"user-3": "email-3",
+	}
+	maxDepth := 3
+
+	errDB := errors.New("some error from db")
+
+	tcs := []TestCase_Friendship_FindFriendshipPath{
+		{
+			name:                      "find successfully",
+			getUserIDsByEmailsData:    mapEmails,
+			getShortestFriendPathData: path,
+			result:                    []string{"email-1", "email-2", "email-3"},
+		},
+		{
+			name:                       "find successfully but not connected",
+			getUserIDsByEmailsData:     mapEmails,
+			getShortestFriendPathError: domain.ErrRecordNotFound,
+			getShortestFriendPathData:  []string{},
+			result:                     []string{},
+		},
+		{
+			name:                    "find fail because email invalid",
+			err:                     common.ErrInvalidRequest(domain.ErrNotFoundUserByEmail, "emails"),
+			getUserIDsByEmailsError: domain.ErrNotFoundUserByEmail,
+			getUserIDsByEmailsData:  map[string]string{},
+		},
+		{
+			name:                       "find fail because search fail",
+			getUserIDsByEmailsData:     mapEmails,
+			getShortestFriendPathError: errDB,
+			getShortestFriendPathData:  []string{},
+			err:                        common.ErrCannotListEntity(domain.Friendship{}.DomainName(), errDB),
+		},
+		{
+			name:                      "find fail because get emails fail",
+			getUserIDsByEmailsData:    mapEmails,
+			getShortestFriendPathData: path,
+			getEmailsByUserIDsError:   errDB,
+			err:                       common.ErrCannotGetEntity(domain.User{}.DomainName(), errDB),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			mockFriendshipRepo := new(mockRepo.MockFriendshipRepository)
+			mockUserRepo := new(mockRepo.MockUserRepository)
+			h := NewFindFriendshipPathHandler(mockFriendshipRepo, mockUserRepo)
+
+			mockUserRepo.On("GetUserIDsByEmails", ctx, emails).Return(tc.getUserIDsByEmailsData, tc.getUserIDsByEmailsError).Once()
+			if tc.getUserIDsByEmailsError == nil {
+				mockFriendshipRepo.On("GetShortestFriendPath", ctx, "user-1", "user-3", maxDepth).Return(tc.getShortestFriendPathData, tc.getShortestFriendPathError).Once()
+				if tc.getShortestFriendPathError == nil {
+					mockUserRepo.On("GetEmailsByUserIDs", ctx, path).Return(mapUsers, tc.getEmailsByUserIDsError).Once()
+				}
+			}
+
+			result, err := h.Handle(ctx, emails[0], emails[1], maxDepth)
+			assert.Equal(t, tc.err, err)
+			assert.Equal(t, tc.result, result)
+
+			mock.AssertExpectationsForObjects(t, mockFriendshipRepo, mockUserRepo)
+		})
+	}
+}
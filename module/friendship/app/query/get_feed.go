@@ -0,0 +1,33 @@
+package query
+
+import (
+	"context"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+type GetFeedHandler struct {
+	updateRepo domain.UpdateRepo
+}
+
+func NewGetFeedHandler(updateRepo domain.UpdateRepo) GetFeedHandler {
+	return GetFeedHandler{
+		updateRepo: updateRepo,
+	}
+}
+
+// Handle lists a page of the updates received by the user, the newest first, and the cursor of the next page
+func (h GetFeedHandler) Handle(ctx context.Context, userID string, page domain.Page) ([]domain.FeedItem, string, error) {
+	feed, nextCursor, err := h.updateRepo.GetFeed(ctx, userID, page)
+	if err != nil {
+		logger.Errorf("updateRepo.GetFeed %w", err)
+		if err == domain.ErrCursorIsNotValid {
+			return nil, "", common.ErrInvalidRequest(err, "cursor")
+		}
+		return nil, "", common.ErrCannotListEntity(domain.Update{}.DomainName(), err)
+	}
+
+	return feed, nextCursor, nil
+}
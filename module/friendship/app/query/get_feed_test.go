@@ -0,0 +1,80 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	mockRepo "github.com/phantranhieunhan/s3-assignment/mock/friendship/repository"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestCase_Update_GetFeed struct {
+	name       string
+	result     []domain.FeedItem
+	nextCursor string
+	err        error
+
+	getFeedError error
+	getFeedData  []domain.FeedItem
+}
+
+func TestUpdate_GetFeed(t *testing.T) {
+	t.Parallel()
+
+	userID := "user-1"
+	page := domain.Page{Limit: 2, Sort: domain.PageSortCreatedAt}
+	now := time.Now()
+	feed := []domain.FeedItem{
+		{ID: "update-2", Sender: "email-2", Text: "hello", CreatedAt: now},
+		{ID: "update-1", Sender: "email-3", Text: "hi email-1", CreatedAt: now.Add(-time.Minute)},
+	}
+	nextCursor := domain.EncodeCursor(domain.Cursor{Key: "update-1", CreatedAt: now.Add(-time.Minute)})
+
+	errDB := errors.New("some error from db")
+
+	tcs := []TestCase_Update_GetFeed{
+		{
+			name:        "get feed successfully",
+			getFeedData: feed,
+			result:      feed,
+			nextCursor:  nextCursor,
+		},
+		{
+			name:         "get feed fail because cursor invalid",
+			getFeedError: domain.ErrCursorIsNotValid,
+			getFeedData:  []domain.FeedItem{},
+			err:          common.ErrInvalidRequest(domain.ErrCursorIsNotValid, "cursor"),
+		},
+		{
+			name:         "get feed fail because get feed fail",
+			getFeedError: errDB,
+			getFeedData:  []domain.FeedItem{},
+			err:          common.ErrCannotListEntity(domain.Update{}.DomainName(), errDB),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			mockUpdateRepo := new(mockRepo.MockUpdateRepository)
+			h := NewGetFeedHandler(mockUpdateRepo)
+
+			mockUpdateRepo.On("GetFeed", ctx, userID, page).Return(tc.getFeedData, tc.nextCursor, tc.getFeedError).Once()
+
+			result, cursor, err := h.Handle(ctx, userID, page)
+			assert.Equal(t, tc.err, err)
+			assert.Equal(t, tc.result, result)
+			assert.Equal(t, tc.nextCursor, cursor)
+
+			mock.AssertExpectationsForObjects(t, mockUpdateRepo)
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package query
+
+import (
+	"context"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+type GetFriendshipHandler struct {
+	repo     domain.FriendshipRepo
+	userRepo domain.UserRepo
+}
+
+func NewGetFriendshipHandler(repo domain.FriendshipRepo, userRepo domain.UserRepo) GetFriendshipHandler {
+	return GetFriendshipHandler{
+		repo:     repo,
+		userRepo: userRepo,
+	}
+}
+
+// Handle gets the friendship between the two users whoever made it, the friendship is zero when they never connected
+func (h GetFriendshipHandler) Handle(ctx context.Context, userEmail, friendEmail string) (domain.Friendship, error) {
+	mapEmailUser, err := h.userRepo.GetUserIDsByEmails(ctx, []string{userEmail, friendEmail})
+	if err != nil {
+		logger.Errorf("userRepo.GetUserIDsByEmails %w", err)
+		if err == domain.ErrNotFoundUserByEmail {
+			return domain.Friendship{}, common.ErrInvalidRequest(err, "emails")
+		}
+		return domain.Friendship{}, common.ErrCannotGetEntity(domain.User{}.DomainName(), err)
+	}
+
+	friendship, err := h.repo.GetFriendshipByUserIDs(ctx, mapEmailUser[userEmail], mapEmailUser[friendEmail])
+	if err != nil {
+		if err == domain.ErrRecordNotFound {
+			return domain.Friendship{}, nil
+		}
+		logger.Errorf("friendshipRepo.GetFriendshipByUserIDs %w", err)
+		return domain.Friendship{}, common.ErrCannotGetEntity(friendship.DomainName(), err)
+	}
+
+	return friendship, nil
+}
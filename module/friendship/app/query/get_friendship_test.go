@@ -0,0 +1,79 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	mockRepo "github.com/phantranhieunhan/s3-assignment/mock/friendship/repository"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestCase_Friendship_GetFriendship struct {
+	name   string
+	result domain.Friendship
+	err    error
+
+	getUserIDsByEmailsError error
+
+	getFriendshipByUserIDsError error
+	getFriendshipByUserIDsData  domain.Friendship
+}
+
+func TestFriendship_GetFriendship(t *testing.T) {
+	t.Parallel()
+
+	emails := []string{"email-1", "email-2"}
+	mapEmails := map[string]string{emails[0]: "user-1", emails[1]: "user-2"}
+	friendship := domain.Friendship{Base: domain.Base{Id: "friendship-1"}, UserID: "user-2", FriendID: "user-1", Status: domain.FriendshipStatusFriended}
+	errDB := errors.New("some error from db")
+
+	tcs := []TestCase_Friendship_GetFriendship{
+		{
+			name:                       "get friendship successfully",
+			getFriendshipByUserIDsData: friendship,
+			result:                     friendship,
+		},
+		{
+			name:                        "get friendship successfully when they never connected",
+			getFriendshipByUserIDsError: domain.ErrRecordNotFound,
+			result:                      domain.Friendship{},
+		},
+		{
+			name:                    "get friendship fail because email invalid",
+			getUserIDsByEmailsError: domain.ErrNotFoundUserByEmail,
+			err:                     common.ErrInvalidRequest(domain.ErrNotFoundUserByEmail, "emails"),
+		},
+		{
+			name:                        "get friendship fail because db fail",
+			getFriendshipByUserIDsError: errDB,
+			err:                         common.ErrCannotGetEntity(domain.Friendship{}.DomainName(), errDB),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			mockFriendshipRepo := new(mockRepo.MockFriendshipRepository)
+			mockUserRepo := new(mockRepo.MockUserRepository)
+			h := NewGetFriendshipHandler(mockFriendshipRepo, mockUserRepo)
+
+			mockUserRepo.On("GetUserIDsByEmails", ctx, emails).Return(mapEmails, tc.getUserIDsByEmailsError).Once()
+			if tc.getUserIDsByEmailsError == nil {
+				mockFriendshipRepo.On("GetFriendshipByUserIDs", ctx, "user-1", "user-2").Return(tc.getFriendshipByUserIDsData, tc.getFriendshipByUserIDsError).Once()
+			}
+
+			result, err := h.Handle(ctx, emails[0], emails[1])
+			assert.Equal(t, tc.err, err)
+			assert.Equal(t, tc.result, result)
+			mock.AssertExpectationsForObjects(t, mockFriendshipRepo, mockUserRepo)
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package query
+
+import (
+	"context"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+type GetSubscriptionHandler struct {
+	subscriptionRepo domain.SubscriptionRepo
+	userRepo         domain.UserRepo
+}
+
+func NewGetSubscriptionHandler(subscriptionRepo domain.SubscriptionRepo, userRepo domain.UserRepo) GetSubscriptionHandler {
+	return GetSubscriptionHandler{
+		subscriptionRepo: subscriptionRepo,
+		userRepo:         userRepo,
+	}
+}
+
+// Handle gets the subscription of the subscriber to the updates of the user,
+// the subscription is zero when the subscriber never subscribed
+func (h GetSubscriptionHandler) Handle(ctx context.Context, subscriberEmail, email string) (domain.Subscription, error) {
+	mapEmailUser, err := h.userRepo.GetUserIDsByEmails(ctx, []string{subscriberEmail, email})
+	if err != nil {
+		logger.Errorf("userRepo.GetUserIDsByEmails %w", err)
+		if err == domain.ErrNotFoundUserByEmail {
+			return domain.Subscription{}, common.ErrInvalidRequest(err, "emails")
+		}
+		return domain.Subscription{}, common.ErrCannotGetEntity(domain.User{}.DomainName(), err)
+	}
+
+	subs, err := h.subscriptionRepo.GetSubscription(ctx, domain.Subscriptions{
+		{UserID: mapEmailUser[email], SubscriberID: mapEmailUser[subscriberEmail]},
+	})
+	if err != nil {
+		logger.Errorf("subscriptionRepo.GetSubscription %w", err)
+		return domain.Subscription{}, common.ErrCannotGetEntity(domain.Subscription{}.DomainName(), err)
+	}
+	if len(subs) == 0 {
+		return domain.Subscription{}, nil
+	}
+
+	return subs[0], nil
+}
@@ -0,0 +1,80 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	mockRepo "github.com/phantranhieunhan/s3-assignment/mock/friendship/repository"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestCase_Subscription_GetSubscription struct {
+	name   string
+	result domain.Subscription
+	err    error
+
+	getUserIDsByEmailsError error
+
+	getSubscriptionError error
+	getSubscriptionData  domain.Subscriptions
+}
+
+func TestSubscription_GetSubscription(t *testing.T) {
+	t.Parallel()
+
+	emails := []string{"subscriber", "email-1"}
+	mapEmails := map[string]string{emails[0]: "user-1", emails[1]: "user-2"}
+	subscription := domain.Subscription{Base: domain.Base{Id: "subscription-1"}, UserID: "user-2", SubscriberID: "user-1", Status: domain.SubscriptionStatusSubscribed}
+	errDB := errors.New("some error from db")
+
+	tcs := []TestCase_Subscription_GetSubscription{
+		{
+			name:                "get subscription successfully",
+			getSubscriptionData: domain.Subscriptions{subscription},
+			result:              subscription,
+		},
+		{
+			name:                "get subscription successfully when the subscriber never subscribed",
+			getSubscriptionData: domain.Subscriptions{},
+			result:              domain.Subscription{},
+		},
+		{
+			name:                    "get subscription fail because email invalid",
+			getUserIDsByEmailsError: domain.ErrNotFoundUserByEmail,
+			err:                     common.ErrInvalidRequest(domain.ErrNotFoundUserByEmail, "emails"),
+		},
+		{
+			name:                 "get subscription fail because db fail",
+			getSubscriptionError: errDB,
+			err:                  common.ErrCannotGetEntity(domain.Subscription{}.DomainName(), errDB),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			mockSubscriptionRepo := new(mockRepo.MockSubscriptionRepository)
+			mockUserRepo := new(mockRepo.MockUserRepository)
+			h := NewGetSubscriptionHandler(mockSubscriptionRepo, mockUserRepo)
+
+			mockUserRepo.On("GetUserIDsByEmails", ctx, emails).Return(mapEmails, tc.getUserIDsByEmailsError).Once()
+			if tc.getUserIDsByEmailsError == nil {
+				mockSubscriptionRepo.On("GetSubscription", ctx, domain.Subscriptions{{UserID: "user-2", SubscriberID: "user-1"}}).
+					Return(tc.getSubscriptionData, tc.getSubscriptionError).Once()
+			}
+
+			result, err := h.Handle(ctx, emails[0], emails[1])
+			assert.Equal(t, tc.err, err)
+			assert.Equal(t, tc.result, result)
+			mock.AssertExpectationsForObjects(t, mockSubscriptionRepo, mockUserRepo)
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package query
+
+import (
+	"context"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+type GetUserHandler struct {
+	userRepo domain.UserRepo
+}
+
+func NewGetUserHandler(userRepo domain.UserRepo) GetUserHandler {
+	return GetUserHandler{
+		userRepo: userRepo,
+	}
+}
+
+func (h GetUserHandler) Handle(ctx context.Context, email string) (domain.User, error) {
+	user, err := h.userRepo.GetUserByEmail(ctx, email)
+	if err != nil {
+		logger.Errorf("userRepo.GetUserByEmail %w", err)
+		if err == domain.ErrRecordNotFound {
+			return domain.User{}, common.ErrInvalidRequest(domain.ErrNotFoundUserByEmail, "email")
+		}
+		return domain.User{}, common.ErrCannotGetEntity(user.DomainName(), err)
+	}
+
+	return user, nil
+}
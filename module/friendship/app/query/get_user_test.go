@@ -0,0 +1,67 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	mockRepo "github.com/phantranhieunhan/s3-assignment/mock/friendship/repository"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestCase_User_GetUser struct {
+	name   string
+	result domain.User
+	err    error
+
+	getUserByEmailError error
+	getUserByEmailData  domain.User
+}
+
+func TestUser_GetUser(t *testing.T) {
+	t.Parallel()
+
+	email := "email-1"
+	user := domain.User{Base: domain.Base{Id: "user-1"}, Email: email}
+	errDB := errors.New("some error from db")
+
+	tcs := []TestCase_User_GetUser{
+		{
+			name:               "get user successfully",
+			getUserByEmailData: user,
+			result:             user,
+		},
+		{
+			name:                "get user fail because user not found",
+			getUserByEmailError: domain.ErrRecordNotFound,
+			err:                 common.ErrInvalidRequest(domain.ErrNotFoundUserByEmail, "email"),
+		},
+		{
+			name:                "get user fail because db fail",
+			getUserByEmailError: errDB,
+			err:                 common.ErrCannotGetEntity(domain.User{}.DomainName(), errDB),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			mockUserRepo := new(mockRepo.MockUserRepository)
+			h := NewGetUserHandler(mockUserRepo)
+
+			mockUserRepo.On("GetUserByEmail", ctx, email).Return(tc.getUserByEmailData, tc.getUserByEmailError).Once()
+
+			result, err := h.Handle(ctx, email)
+			assert.Equal(t, tc.err, err)
+			assert.Equal(t, tc.result, result)
+			mock.AssertExpectationsForObjects(t, mockUserRepo)
+		})
+	}
+}
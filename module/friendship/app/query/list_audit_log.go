@@ -0,0 +1,56 @@
+package query
+
+import (
+	"context"
+	"strings"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+type ListAuditLogHandler struct {
+	auditRepo domain.AuditRepo
+	userRepo  domain.UserRepo
+}
+
+func NewListAuditLogHandler(auditRepo domain.AuditRepo, userRepo domain.UserRepo) ListAuditLogHandler {
+	return ListAuditLogHandler{
+		auditRepo: auditRepo,
+		userRepo:  userRepo,
+	}
+}
+
+// Handle lists a page of the audit log, the newest first, and the cursor of the next page.
+// The user of the filter is an email or a user id, an id still finds the entries of an account purged since
+func (h ListAuditLogHandler) Handle(ctx context.Context, filter domain.AuditFilter, page domain.Page) ([]domain.AuditEntry, string, error) {
+	if filter.Action != "" && !filter.Action.IsValid() {
+		return nil, "", common.ErrInvalidRequest(domain.ErrAuditActionIsNotValid, "action")
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && filter.From.After(filter.To) {
+		return nil, "", common.ErrInvalidRequest(domain.ErrAuditRangeIsNotValid, "from")
+	}
+
+	if strings.Contains(filter.UserID, "@") {
+		mapEmailUser, err := h.userRepo.GetUserIDsByEmails(ctx, []string{filter.UserID})
+		if err != nil {
+			logger.Errorf("userRepo.GetUserIDsByEmails %w", err)
+			if err == domain.ErrNotFoundUserByEmail {
+				return nil, "", common.ErrInvalidRequest(err, "user")
+			}
+			return nil, "", common.ErrCannotGetEntity(domain.User{}.DomainName(), err)
+		}
+		filter.UserID = mapEmailUser[filter.UserID]
+	}
+
+	entries, nextCursor, err := h.auditRepo.List(ctx, filter, page)
+	if err != nil {
+		logger.Errorf("auditRepo.List %w", err)
+		if err == domain.ErrCursorIsNotValid {
+			return nil, "", common.ErrInvalidRequest(err, "cursor")
+		}
+		return nil, "", common.ErrCannotListEntity(domain.AuditEntry{}.DomainName(), err)
+	}
+
+	return entries, nextCursor, nil
+}
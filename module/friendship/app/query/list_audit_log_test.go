@@ -0,0 +1,113 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	mockRepo "github.com/phantranhieunhan/s3-assignment/mock/friendship/repository"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestCase_Audit_ListAuditLog struct {
+	name   string
+	filter domain.AuditFilter
+	// listFilter is the filter given to the repository, the email of the user is resolved to its id
+	listFilter domain.AuditFilter
+	result     []domain.AuditEntry
+	nextCursor string
+	err        error
+
+	getUserIDsByEmailsError error
+
+	listError error
+}
+
+func TestAudit_ListAuditLog(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().UTC()
+	page := domain.Page{Limit: 2}
+	entries := []domain.AuditEntry{
+		{ID: "audit-2", Actor: "user-1", Action: domain.AuditLogin, TargetIDs: []string{"user-1"}, CreatedAt: now},
+		{ID: "audit-1", Actor: "user-1", Action: domain.AuditCreateUser, TargetIDs: []string{"user-1"}, CreatedAt: now.Add(-time.Minute)},
+	}
+	nextCursor := domain.EncodeCursor(domain.Cursor{Key: "audit-1", CreatedAt: now.Add(-time.Minute)})
+	errDB := errors.New("some error from db")
+
+	tcs := []TestCase_Audit_ListAuditLog{
+		{
+			name:       "list audit log successfully by user id",
+			filter:     domain.AuditFilter{UserID: "user-1", Action: domain.AuditLogin, From: now.Add(-time.Hour), To: now},
+			listFilter: domain.AuditFilter{UserID: "user-1", Action: domain.AuditLogin, From: now.Add(-time.Hour), To: now},
+			result:     entries,
+			nextCursor: nextCursor,
+		},
+		{
+			name:       "list audit log successfully by user email",
+			filter:     domain.AuditFilter{UserID: "andy@example.com"},
+			listFilter: domain.AuditFilter{UserID: "user-1"},
+			result:     entries,
+		},
+		{
+			name:   "list audit log fail because action is not valid",
+			filter: domain.AuditFilter{Action: "Teleport"},
+			err:    common.ErrInvalidRequest(domain.ErrAuditActionIsNotValid, "action"),
+		},
+		{
+			name:   "list audit log fail because from is after to",
+			filter: domain.AuditFilter{From: now, To: now.Add(-time.Hour)},
+			err:    common.ErrInvalidRequest(domain.ErrAuditRangeIsNotValid, "from"),
+		},
+		{
+			name:                    "list audit log fail because email is unknown",
+			filter:                  domain.AuditFilter{UserID: "andy@example.com"},
+			getUserIDsByEmailsError: domain.ErrNotFoundUserByEmail,
+			err:                     common.ErrInvalidRequest(domain.ErrNotFoundUserByEmail, "user"),
+		},
+		{
+			name:      "list audit log fail because cursor is not valid",
+			listError: domain.ErrCursorIsNotValid,
+			err:       common.ErrInvalidRequest(domain.ErrCursorIsNotValid, "cursor"),
+		},
+		{
+			name:      "list audit log fail because list fail",
+			listError: errDB,
+			err:       common.ErrCannotListEntity(domain.AuditEntry{}.DomainName(), errDB),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			mockAuditRepo := new(mockRepo.MockAuditRepository)
+			mockUserRepo := new(mockRepo.MockUserRepository)
+			h := NewListAuditLogHandler(mockAuditRepo, mockUserRepo)
+
+			mockUserRepo.On("GetUserIDsByEmails", ctx, []string{"andy@example.com"}).
+				Return(map[string]string{"andy@example.com": "user-1"}, tc.getUserIDsByEmailsError).Maybe()
+			var listData []domain.AuditEntry
+			if tc.listError == nil {
+				listData = tc.result
+			}
+			mockAuditRepo.On("List", ctx, tc.listFilter, page).Return(listData, tc.nextCursor, tc.listError).Maybe()
+
+			result, cursor, err := h.Handle(ctx, tc.filter, page)
+			assert.Equal(t, tc.err, err)
+			assert.Equal(t, tc.result, result)
+			assert.Equal(t, tc.nextCursor, cursor)
+			if tc.err == nil || tc.listError != nil {
+				mockAuditRepo.AssertCalled(t, "List", ctx, tc.listFilter, page)
+			} else {
+				mockAuditRepo.AssertNotCalled(t, "List", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
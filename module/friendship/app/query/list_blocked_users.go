@@ -0,0 +1,30 @@
+package query
+
+import (
+	"context"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+type ListBlockedUsersHandler struct {
+	blockRepo domain.BlockRepo
+}
+
+func NewListBlockedUsersHandler(blockRepo domain.BlockRepo) ListBlockedUsersHandler {
+	return ListBlockedUsersHandler{
+		blockRepo: blockRepo,
+	}
+}
+
+// Handle lists the users blocked by the user with the time of each block
+func (h ListBlockedUsersHandler) Handle(ctx context.Context, userID string) ([]domain.BlockedEmail, error) {
+	result, err := h.blockRepo.GetBlockedEmailsByUserID(ctx, userID)
+	if err != nil {
+		logger.Errorf("blockRepo.GetBlockedEmailsByUserID %w", err)
+		return nil, common.ErrCannotListEntity(domain.Block{}.DomainName(), err)
+	}
+
+	return result, nil
+}
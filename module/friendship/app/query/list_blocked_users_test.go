@@ -0,0 +1,68 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	mockRepo "github.com/phantranhieunhan/s3-assignment/mock/friendship/repository"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestCase_Subscription_ListBlockedUsers struct {
+	name   string
+	result []domain.BlockedEmail
+	err    error
+
+	GetBlockedEmailsByUserIDError error
+	GetBlockedEmailsByUserIDData  []domain.BlockedEmail
+}
+
+func TestSubscription_ListBlockedUsers(t *testing.T) {
+	t.Parallel()
+
+	userID := "user-1"
+	blocked := []domain.BlockedEmail{
+		{Email: "email-2", BlockedAt: time.Now().UTC()},
+		{Email: "email-3", BlockedAt: time.Now().UTC().Add(-time.Hour)},
+	}
+
+	errDB := errors.New("some error from db")
+
+	tcs := []TestCase_Subscription_ListBlockedUsers{
+		{
+			name:                         "list successfully",
+			GetBlockedEmailsByUserIDData: blocked,
+			result:                       blocked,
+		},
+		{
+			name:                          "list fail because get blocks fail",
+			GetBlockedEmailsByUserIDError: errDB,
+			GetBlockedEmailsByUserIDData:  []domain.BlockedEmail{},
+			err:                           common.ErrCannotListEntity(domain.Block{}.DomainName(), errDB),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			mockBlockRepo := new(mockRepo.MockBlockRepository)
+			h := NewListBlockedUsersHandler(mockBlockRepo)
+
+			mockBlockRepo.On("GetBlockedEmailsByUserID", ctx, userID).Return(tc.GetBlockedEmailsByUserIDData, tc.GetBlockedEmailsByUserIDError).Once()
+
+			result, err := h.Handle(ctx, userID)
+			assert.Equal(t, tc.err, err)
+			assert.Equal(t, tc.result, result)
+
+			mock.AssertExpectationsForObjects(t, mockBlockRepo)
+		})
+	}
+}
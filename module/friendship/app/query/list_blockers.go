@@ -0,0 +1,42 @@
+package query
+
+import (
+	"context"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+type ListBlockersHandler struct {
+	blockRepo domain.BlockRepo
+	userRepo  domain.UserRepo
+}
+
+func NewListBlockersHandler(blockRepo domain.BlockRepo, userRepo domain.UserRepo) ListBlockersHandler {
+	return ListBlockersHandler{
+		blockRepo: blockRepo,
+		userRepo:  userRepo,
+	}
+}
+
+// Handle lists the users who have blocked the user with the time of each block
+func (h ListBlockersHandler) Handle(ctx context.Context, email string) ([]domain.BlockedEmail, error) {
+	// get userId from email to check available
+	mapEmailUser, err := h.userRepo.GetUserIDsByEmails(ctx, []string{email})
+	if err != nil {
+		logger.Errorf("userRepo.GetUserIDsByEmails %w", err)
+		if err == domain.ErrNotFoundUserByEmail {
+			return nil, common.ErrInvalidRequest(err, "emails")
+		}
+		return nil, common.ErrCannotGetEntity(domain.User{}.DomainName(), err)
+	}
+
+	result, err := h.blockRepo.GetBlockerEmailsByTargetID(ctx, mapEmailUser[email])
+	if err != nil {
+		logger.Errorf("blockRepo.GetBlockerEmailsByTargetID %w", err)
+		return nil, common.ErrCannotListEntity(domain.Block{}.DomainName(), err)
+	}
+
+	return result, nil
+}
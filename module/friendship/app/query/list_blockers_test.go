@@ -0,0 +1,85 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	mockRepo "github.com/phantranhieunhan/s3-assignment/mock/friendship/repository"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestCase_Subscription_ListBlockers struct {
+	name                    string
+	result                  []domain.BlockedEmail
+	err                     error
+	getUserIDsByEmailsError error
+	getUserIDsByEmailsData  map[string]string
+
+	GetBlockerEmailsByTargetIDError error
+	GetBlockerEmailsByTargetIDData  []domain.BlockedEmail
+}
+
+func TestSubscription_ListBlockers(t *testing.T) {
+	t.Parallel()
+
+	email := "email-1"
+	mapEmails := map[string]string{
+		email: "user-1",
+	}
+	blocked := []domain.BlockedEmail{
+		{Email: "email-2", BlockedAt: time.Now().UTC()},
+		{Email: "email-3", BlockedAt: time.Now().UTC().Add(-time.Hour)},
+	}
+
+	errDB := errors.New("some error from db")
+
+	tcs := []TestCase_Subscription_ListBlockers{
+		{
+			name:                           "list successfully",
+			getUserIDsByEmailsData:         mapEmails,
+			GetBlockerEmailsByTargetIDData: blocked,
+			result:                         blocked,
+		},
+		{
+			name:                    "list fail because email invalid",
+			err:                     common.ErrInvalidRequest(domain.ErrNotFoundUserByEmail, "emails"),
+			getUserIDsByEmailsError: domain.ErrNotFoundUserByEmail,
+			getUserIDsByEmailsData:  map[string]string{},
+		},
+		{
+			name:                            "list fail because get blocks fail",
+			getUserIDsByEmailsData:          mapEmails,
+			GetBlockerEmailsByTargetIDError: errDB,
+			GetBlockerEmailsByTargetIDData:  []domain.BlockedEmail{},
+			err:                             common.ErrCannotListEntity(domain.Block{}.DomainName(), errDB),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			mockBlockRepo := new(mockRepo.MockBlockRepository)
+			mockUserRepo := new(mockRepo.MockUserRepository)
+			h := NewListBlockersHandler(mockBlockRepo, mockUserRepo)
+
+			mockUserRepo.On("GetUserIDsByEmails", ctx, []string{email}).Return(tc.getUserIDsByEmailsData, tc.getUserIDsByEmailsError).Once()
+			if tc.getUserIDsByEmailsError == nil {
+				mockBlockRepo.On("GetBlockerEmailsByTargetID", ctx, mapEmails[email]).Return(tc.GetBlockerEmailsByTargetIDData, tc.GetBlockerEmailsByTargetIDError).Once()
+			}
+
+			result, err := h.Handle(ctx, email)
+			assert.Equal(t, tc.err, err)
+			assert.Equal(t, tc.result, result)
+
+			mock.AssertExpectationsForObjects(t, mockBlockRepo, mockUserRepo)
+		})
+	}
+}
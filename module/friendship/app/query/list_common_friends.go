@@ -22,44 +22,32 @@ func NewListCommonFriendsHandler(repo domain.FriendshipRepo, userRepo domain.Use
 	}
 }
 
-func (h ListCommonFriendsHandler) Handle(ctx context.Context, emails []string) ([]string, error) {
+// Handle lists a page of the friends shared by the two users and the cursor of the next page
+func (h ListCommonFriendsHandler) Handle(ctx context.Context, emails []string, page domain.Page) ([]string, string, error) {
 	if len(emails) != EMAIL_TOTAL {
-		return nil, common.ErrInvalidRequest(domain.ErrEmailIsNotValid, "emails")
+		return nil, "", common.ErrInvalidRequest(domain.ErrEmailIsNotValid, "emails")
 	}
 	// get userId from email to check available
 	mapEmailUserIDs, err := h.userRepo.GetUserIDsByEmails(ctx, emails)
 	if err != nil {
 		logger.Errorf("userRepo.GetUserIDsByEmails %w", err)
 		if err == domain.ErrNotFoundUserByEmail {
-			return nil, common.ErrInvalidRequest(err, "emails")
+			return nil, "", common.ErrInvalidRequest(err, "emails")
 		}
-		return nil, common.ErrCannotGetEntity(domain.User{}.DomainName(), err)
+		return nil, "", common.ErrCannotGetEntity(domain.User{}.DomainName(), err)
 	}
 
-	friends, err := h.repo.GetFriendshipByUserIDAndStatus(ctx, mapEmailUserIDs, domain.FriendshipStatusFriended)
+	mutual, nextCursor, err := h.repo.GetFriendshipByUserIDAndStatus(ctx, mapEmailUserIDs, page, domain.FriendshipStatusFriended)
 	if err != nil {
 		if err == domain.ErrRecordNotFound {
-			return []string{}, nil
+			return []string{}, "", nil
 		}
 		logger.Errorf("friendshipRepo.GetFriendshipByUserIDAndStatus %w", err)
-		return nil, common.ErrCannotListEntity(domain.Friendship{}.DomainName(), err)
-	}
-
-	mutual := getMutual(friends)
-
-	return mutual, nil
-}
-
-// after add all friends of 2 user in to a list, then get items is duplicated
-func getMutual(fullList []string) []string {
-	allKeys := make(map[string]bool)
-	list := []string{}
-	for _, item := range fullList {
-		if _, value := allKeys[item]; value {
-			list = append(list, item)
-		} else {
-			allKeys[item] = true
+		if err == domain.ErrCursorIsNotValid {
+			return nil, "", common.ErrInvalidRequest(err, "cursor")
 		}
+		return nil, "", common.ErrCannotListEntity(domain.Friendship{}.DomainName(), err)
 	}
-	return list
+
+	return mutual, nextCursor, nil
 }
@@ -26,7 +26,9 @@ func TestFriendship_ListCommonFriends(t *testing.T) {
 		emails[1]: friends[1],
 	}
 
-	friendEmails := []string{"email-1", "email-3", "email-2", "email-4", "email-3", "email-4"}
+	friendEmails := emails[2:4]
+	page := domain.Page{Limit: 2, Sort: domain.PageSortEmail}
+	nextCursor := domain.EncodeCursor(domain.Cursor{Key: emails[3]})
 	var nilSlice []string
 
 	errDB := errors.New("some error from db")
@@ -34,6 +36,7 @@ func TestFriendship_ListCommonFriends(t *testing.T) {
 	tcs := []struct {
 		name            string
 		result          []string
+		nextCursor      string
 		requestedEmails []string
 
 		getUserIDsByEmailsData  map[string]string
@@ -50,8 +53,27 @@ func TestFriendship_ListCommonFriends(t *testing.T) {
 			getUserIDsByEmailsData:             mapEmails,
 			getFriendshipByUserIDAndStatusData: friendEmails,
 			result:                             emails[2:4],
+			nextCursor:                         nextCursor,
 			err:                                nil,
 		},
+		{
+			name:                                "get list common friendship successfully when there is no common friend",
+			requestedEmails:                     requestedEmails,
+			getUserIDsByEmailsData:              mapEmails,
+			getFriendshipByUserIDAndStatusData:  []string{},
+			getFriendshipByUserIDAndStatusError: domain.ErrRecordNotFound,
+			result:                              []string{},
+			err:                                 nil,
+		},
+		{
+			name:                                "get list common friendship fail because cursor is invalid",
+			requestedEmails:                     requestedEmails,
+			getUserIDsByEmailsData:              mapEmails,
+			getFriendshipByUserIDAndStatusData:  []string{},
+			getFriendshipByUserIDAndStatusError: domain.ErrCursorIsNotValid,
+			result:                              nilSlice,
+			err:                                 common.ErrInvalidRequest(domain.ErrCursorIsNotValid, "cursor"),
+		},
 		{
 			name:            "get list common friendship fail because of parameters is invalid",
 			requestedEmails: []string{"email"},
@@ -87,14 +109,15 @@ func TestFriendship_ListCommonFriends(t *testing.T) {
 				mockUserRepo.On("GetUserIDsByEmails", ctx, requestedEmails).Return(tc.getUserIDsByEmailsData, tc.getUserIDsByEmailsError).Once()
 
 				if tc.getUserIDsByEmailsError == nil {
-					mockFriendshipRepo.On("GetFriendshipByUserIDAndStatus", ctx, tc.getUserIDsByEmailsData, []domain.FriendshipStatus{domain.FriendshipStatusFriended}).Return(
-						tc.getFriendshipByUserIDAndStatusData, tc.getFriendshipByUserIDAndStatusError).Once()
+					mockFriendshipRepo.On("GetFriendshipByUserIDAndStatus", ctx, tc.getUserIDsByEmailsData, page, []domain.FriendshipStatus{domain.FriendshipStatusFriended}).Return(
+						tc.getFriendshipByUserIDAndStatusData, tc.nextCursor, tc.getFriendshipByUserIDAndStatusError).Once()
 				}
 			}
 
-			ids, err := h.Handle(ctx, tc.requestedEmails)
+			ids, cursor, err := h.Handle(ctx, tc.requestedEmails, page)
 			assert.Equal(t, err, tc.err)
 			assert.Equal(t, tc.result, ids)
+			assert.Equal(t, tc.nextCursor, cursor)
 
 			mock.AssertExpectationsForObjects(t, mockFriendshipRepo, mockUserRepo)
 		})
@@ -0,0 +1,30 @@
+package query
+
+import (
+	"context"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+type ListFriendRequestsHandler struct {
+	repo domain.FriendshipRepo
+}
+
+func NewListFriendRequestsHandler(repo domain.FriendshipRepo) ListFriendRequestsHandler {
+	return ListFriendRequestsHandler{
+		repo: repo,
+	}
+}
+
+// Handle lists the emails of the pending friend requests received (incoming) or sent (outgoing) by the user
+func (h ListFriendRequestsHandler) Handle(ctx context.Context, userID string, direction domain.FriendRequestDirection) ([]string, error) {
+	result, err := h.repo.GetFriendRequestEmails(ctx, userID, direction)
+	if err != nil {
+		logger.Errorf("friendshipRepo.GetFriendRequestEmails %w", err)
+		return nil, common.ErrCannotListEntity(domain.Friendship{}.DomainName(), err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,73 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	mockRepo "github.com/phantranhieunhan/s3-assignment/mock/friendship/repository"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestCase_Friendship_ListFriendRequests struct {
+	name      string
+	direction domain.FriendRequestDirection
+	result    []string
+	err       error
+
+	getFriendRequestEmailsError error
+	getFriendRequestEmailsData  []string
+}
+
+func TestFriendship_ListFriendRequests(t *testing.T) {
+	t.Parallel()
+
+	emails := []string{"email-2", "email-3"}
+	errDB := errors.New("some error from db")
+
+	tcs := []TestCase_Friendship_ListFriendRequests{
+		{
+			name:                       "list incoming friend requests successfully",
+			direction:                  domain.FriendRequestDirectionIncoming,
+			getFriendRequestEmailsData: emails,
+			result:                     emails,
+		},
+		{
+			name:                       "list outgoing friend requests successfully",
+			direction:                  domain.FriendRequestDirectionOutgoing,
+			getFriendRequestEmailsData: []string{},
+			result:                     []string{},
+		},
+		{
+			name:                        "list friend requests fail because get friend request emails fail",
+			direction:                   domain.FriendRequestDirectionOutgoing,
+			getFriendRequestEmailsError: errDB,
+			getFriendRequestEmailsData:  []string{},
+			err:                         common.ErrCannotListEntity(domain.Friendship{}.DomainName(), errDB),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			mockFriendshipRepo := new(mockRepo.MockFriendshipRepository)
+			h := NewListFriendRequestsHandler(mockFriendshipRepo)
+
+			mockFriendshipRepo.On("GetFriendRequestEmails", ctx, "user-1", tc.direction).Return(
+				tc.getFriendRequestEmailsData, tc.getFriendRequestEmailsError).Once()
+
+			result, err := h.Handle(ctx, "user-1", tc.direction)
+			assert.Equal(t, tc.err, err)
+			assert.Equal(t, tc.result, result)
+
+			mock.AssertExpectationsForObjects(t, mockFriendshipRepo)
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package query
+
+import (
+	"context"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+type ListFriendSetHandler struct {
+	repo     domain.FriendshipRepo
+	userRepo domain.UserRepo
+}
+
+func NewListFriendSetHandler(repo domain.FriendshipRepo, userRepo domain.UserRepo) ListFriendSetHandler {
+	return ListFriendSetHandler{
+		repo:     repo,
+		userRepo: userRepo,
+	}
+}
+
+// Handle combines the friends of the users with the operation, the difference is the friends of the first user minus the others
+func (h ListFriendSetHandler) Handle(ctx context.Context, emails []string, operation domain.FriendSetOperation) ([]domain.FriendSetMember, error) {
+	if len(emails) < EMAIL_TOTAL {
+		return nil, common.ErrInvalidRequest(domain.ErrEmailIsNotValid, "emails")
+	}
+	if !operation.IsValid() {
+		return nil, common.ErrInvalidRequest(domain.ErrFriendSetOperationIsNotValid, "operation")
+	}
+
+	// get userId from email to check available
+	mapEmailUserIDs, err := h.userRepo.GetUserIDsByEmails(ctx, emails)
+	if err != nil {
+		logger.Errorf("userRepo.GetUserIDsByEmails %w", err)
+		if err == domain.ErrNotFoundUserByEmail {
+			return nil, common.ErrInvalidRequest(err, "emails")
+		}
+		return nil, common.ErrCannotGetEntity(domain.User{}.DomainName(), err)
+	}
+
+	// keep the order of the emails, the first user is the base of the difference
+	userIDs := make([]string, 0, len(emails))
+	for _, email := range emails {
+		userIDs = append(userIDs, mapEmailUserIDs[email])
+	}
+
+	result, err := h.repo.GetFriendSet(ctx, userIDs, operation)
+	if err != nil {
+		logger.Errorf("friendshipRepo.GetFriendSet %w", err)
+		return nil, common.ErrCannotListEntity(domain.Friendship{}.DomainName(), err)
+	}
+
+	return result, nil
+}
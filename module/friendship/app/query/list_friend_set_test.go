@@ -0,0 +1,111 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	mockRepo "github.com/phantranhieunhan/s3-assignment/mock/friendship/repository"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestCase_Friendship_ListFriendSet struct {
+	name      string
+	emails    []string
+	operation domain.FriendSetOperation
+	result    []domain.FriendSetMember
+	err       error
+
+	getUserIDsByEmailsError error
+	getUserIDsByEmailsData  map[string]string
+
+	getFriendSetError error
+	getFriendSetData  []domain.FriendSetMember
+}
+
+func TestFriendship_ListFriendSet(t *testing.T) {
+	t.Parallel()
+
+	emails := []string{"email-3", "email-1", "email-2"}
+	mapEmails := map[string]string{
+		"email-1": "user-1",
+		"email-2": "user-2",
+		"email-3": "user-3",
+	}
+	members := []domain.FriendSetMember{
+		{Email: "email-4", ConnectedUsers: 3},
+		{Email: "email-5", ConnectedUsers: 1},
+	}
+
+	errDB := errors.New("some error from db")
+
+	tcs := []TestCase_Friendship_ListFriendSet{
+		{
+			name:                   "list successfully",
+			emails:                 emails,
+			operation:              domain.FriendSetOperationUnion,
+			getUserIDsByEmailsData: mapEmails,
+			getFriendSetData:       members,
+			result:                 members,
+		},
+		{
+			name:      "list fail because there is only one email",
+			emails:    emails[:1],
+			operation: domain.FriendSetOperationUnion,
+			err:       common.ErrInvalidRequest(domain.ErrEmailIsNotValid, "emails"),
+		},
+		{
+			name:      "list fail because operation invalid",
+			emails:    emails,
+			operation: domain.FriendSetOperation("xor"),
+			err:       common.ErrInvalidRequest(domain.ErrFriendSetOperationIsNotValid, "operation"),
+		},
+		{
+			name:                    "list fail because email invalid",
+			emails:                  emails,
+			operation:               domain.FriendSetOperationIntersection,
+			err:                     common.ErrInvalidRequest(domain.ErrNotFoundUserByEmail, "emails"),
+			getUserIDsByEmailsError: domain.ErrNotFoundUserByEmail,
+			getUserIDsByEmailsData:  map[string]string{},
+		},
+		{
+			name:                   "list fail because get friend set fail",
+			emails:                 emails,
+			operation:              domain.FriendSetOperationDifference,
+			getUserIDsByEmailsData: mapEmails,
+			getFriendSetError:      errDB,
+			getFriendSetData:       []domain.FriendSetMember{},
+			err:                    common.ErrCannotListEntity(domain.Friendship{}.DomainName(), errDB),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			mockFriendshipRepo := new(mockRepo.MockFriendshipRepository)
+			mockUserRepo := new(mockRepo.MockUserRepository)
+			h := NewListFriendSetHandler(mockFriendshipRepo, mockUserRepo)
+
+			if tc.getUserIDsByEmailsData != nil {
+				mockUserRepo.On("GetUserIDsByEmails", ctx, tc.emails).Return(tc.getUserIDsByEmailsData, tc.getUserIDsByEmailsError).Once()
+			}
+			if tc.getFriendSetData != nil {
+				// the user ids keep the order of the emails
+				mockFriendshipRepo.On("GetFriendSet", ctx, []string{"user-3", "user-1", "user-2"}, tc.operation).Return(tc.getFriendSetData, tc.getFriendSetError).Once()
+			}
+
+			result, err := h.Handle(ctx, tc.emails, tc.operation)
+			assert.Equal(t, tc.err, err)
+			assert.Equal(t, tc.result, result)
+
+			mock.AssertExpectationsForObjects(t, mockFriendshipRepo, mockUserRepo)
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package query
+
+import (
+	"context"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+type ListFriendSuggestionsHandler struct {
+	repo     domain.FriendshipRepo
+	userRepo domain.UserRepo
+}
+
+func NewListFriendSuggestionsHandler(repo domain.FriendshipRepo, userRepo domain.UserRepo) ListFriendSuggestionsHandler {
+	return ListFriendSuggestionsHandler{
+		repo:     repo,
+		userRepo: userRepo,
+	}
+}
+
+// Handle lists the people the user may know, the ones with the most mutual friends first
+func (h ListFriendSuggestionsHandler) Handle(ctx context.Context, email string, limit int) ([]domain.FriendSuggestion, error) {
+	// get userId from email to check available
+	mapEmailUser, err := h.userRepo.GetUserIDsByEmails(ctx, []string{email})
+	if err != nil {
+		logger.Errorf("userRepo.GetUserIDsByEmails %w", err)
+		if err == domain.ErrNotFoundUserByEmail {
+			return nil, common.ErrInvalidRequest(err, "emails")
+		}
+		return nil, common.ErrCannotGetEntity(domain.User{}.DomainName(), err)
+	}
+
+	result, err := h.repo.GetFriendSuggestions(ctx, mapEmailUser[email], limit)
+	if err != nil {
+		logger.Errorf("friendshipRepo.GetFriendSuggestions %w", err)
+		return nil, common.ErrCannotListEntity(domain.Friendship{}.DomainName(), err)
+	}
+
+	return result, nil
+}
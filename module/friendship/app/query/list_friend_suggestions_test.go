@@ -0,0 +1,86 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	mockRepo "github.com/phantranhieunhan/s3-assignment/mock/friendship/repository"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestCase_Friendship_ListFriendSuggestions struct {
+	name                    string
+	result                  []domain.FriendSuggestion
+	err                     error
+	getUserIDsByEmailsError error
+	getUserIDsByEmailsData  map[string]string
+
+	getFriendSuggestionsError error
+	getFriendSuggestionsData  []domain.FriendSuggestion
+}
+
+func TestFriendship_ListFriendSuggestions(t *testing.T) {
+	t.Parallel()
+
+	email := "email-1"
+	limit := 10
+	mapEmails := map[string]string{
+		email: "user-1",
+	}
+	suggestions := []domain.FriendSuggestion{
+		{Email: "email-2", MutualFriends: 2},
+		{Email: "email-3", MutualFriends: 1},
+	}
+
+	errDB := errors.New("some error from db")
+
+	tcs := []TestCase_Friendship_ListFriendSuggestions{
+		{
+			name:                     "list successfully",
+			getUserIDsByEmailsData:   mapEmails,
+			getFriendSuggestionsData: suggestions,
+			result:                   suggestions,
+		},
+		{
+			name:                    "list fail because email invalid",
+			err:                     common.ErrInvalidRequest(domain.ErrNotFoundUserByEmail, "emails"),
+			getUserIDsByEmailsError: domain.ErrNotFoundUserByEmail,
+			getUserIDsByEmailsData:  map[string]string{},
+		},
+		{
+			name:                      "list fail because get suggestions fail",
+			getUserIDsByEmailsData:    mapEmails,
+			getFriendSuggestionsError: errDB,
+			getFriendSuggestionsData:  []domain.FriendSuggestion{},
+			err:                       common.ErrCannotListEntity(domain.Friendship{}.DomainName(), errDB),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			mockFriendshipRepo := new(mockRepo.MockFriendshipRepository)
+			mockUserRepo := new(mockRepo.MockUserRepository)
+			h := NewListFriendSuggestionsHandler(mockFriendshipRepo, mockUserRepo)
+
+			mockUserRepo.On("GetUserIDsByEmails", ctx, []string{email}).Return(tc.getUserIDsByEmailsData, tc.getUserIDsByEmailsError).Once()
+			if tc.getUserIDsByEmailsError == nil {
+				mockFriendshipRepo.On("GetFriendSuggestions", ctx, mapEmails[email], limit).Return(tc.getFriendSuggestionsData, tc.getFriendSuggestionsError).Once()
+			}
+
+			result, err := h.Handle(ctx, email, limit)
+			assert.Equal(t, tc.err, err)
+			assert.Equal(t, tc.result, result)
+
+			mock.AssertExpectationsForObjects(t, mockFriendshipRepo, mockUserRepo)
+		})
+	}
+}
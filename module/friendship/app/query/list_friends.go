@@ -20,26 +20,30 @@ func NewListFriendsHandler(repo domain.FriendshipRepo, userRepo domain.UserRepo)
 	}
 }
 
-func (h ListFriendsHandler) Handle(ctx context.Context, email string) ([]string, error) {
+// Handle lists a page of the friends of the user and the cursor of the next page
+func (h ListFriendsHandler) Handle(ctx context.Context, email string, page domain.Page) ([]string, string, error) {
 	// get userId from email to check available
 	mapEmailUser, err := h.userRepo.GetUserIDsByEmails(ctx, []string{email})
 	if err != nil {
 		logger.Errorf("userRepo.GetUserIDsByEmails %w", err)
 		if err == domain.ErrNotFoundUserByEmail {
-			return nil, common.ErrInvalidRequest(err, "emails")
+			return nil, "", common.ErrInvalidRequest(err, "emails")
 		}
-		return nil, common.ErrCannotGetEntity(domain.User{}.DomainName(), err)
+		return nil, "", common.ErrCannotGetEntity(domain.User{}.DomainName(), err)
 	}
 
 	// get list friends from userId
-	result, err := h.repo.GetFriendshipByUserIDAndStatus(ctx, mapEmailUser, domain.FriendshipStatusFriended)
+	result, nextCursor, err := h.repo.GetFriendshipByUserIDAndStatus(ctx, mapEmailUser, page, domain.FriendshipStatusFriended)
 	if err != nil {
 		if err == domain.ErrRecordNotFound {
-			return []string{}, nil
+			return []string{}, "", nil
 		}
 		logger.Errorf("friendshipRepo.GetFriendshipByUserIDAndStatus %w", err)
-		return nil, common.ErrCannotListEntity(domain.Friendship{}.DomainName(), err)
+		if err == domain.ErrCursorIsNotValid {
+			return nil, "", common.ErrInvalidRequest(err, "cursor")
+		}
+		return nil, "", common.ErrCannotListEntity(domain.Friendship{}.DomainName(), err)
 	}
 
-	return result, nil
+	return result, nextCursor, nil
 }
@@ -23,6 +23,7 @@ type TestCase_Friendship_ListFriends struct {
 
 	getFriendshipByUserIDAndStatusError error
 	getFriendshipByUserIDAndStatusData  []string
+	nextCursor                          string
 }
 
 func TestFriendship_ListFriends(t *testing.T) {
@@ -34,6 +35,9 @@ func TestFriendship_ListFriends(t *testing.T) {
 		emails[0]: friends[0],
 	}
 
+	page := domain.Page{Limit: 3, Sort: domain.PageSortCreatedAt}
+	nextCursor := domain.EncodeCursor(domain.Cursor{Key: emails[3], CreatedAt: time.Now()})
+
 	errDB := errors.New("some error from db")
 
 	tcs := []TestCase_Friendship_ListFriends{
@@ -42,8 +46,17 @@ func TestFriendship_ListFriends(t *testing.T) {
 			getUserIDsByEmailsData:             mapEmails,
 			getFriendshipByUserIDAndStatusData: emails[1:4],
 			result:                             emails[1:4],
+			nextCursor:                         nextCursor,
 			err:                                nil,
 		},
+		{
+			name:                                "get list friendship successfully when there is no friend",
+			getUserIDsByEmailsData:              mapEmails,
+			getFriendshipByUserIDAndStatusData:  []string{},
+			getFriendshipByUserIDAndStatusError: domain.ErrRecordNotFound,
+			result:                              []string{},
+			err:                                 nil,
+		},
 		{
 			name:                    "get list friendship fail because email invalid",
 			result:                  nil,
@@ -72,13 +85,14 @@ func TestFriendship_ListFriends(t *testing.T) {
 
 			mockUserRepo.On("GetUserIDsByEmails", ctx, []string{emails[0]}).Return(tc.getUserIDsByEmailsData, tc.getUserIDsByEmailsError).Once()
 			if tc.getUserIDsByEmailsError == nil {
-				mockFriendshipRepo.On("GetFriendshipByUserIDAndStatus", ctx, mapEmails, []domain.FriendshipStatus{domain.FriendshipStatusFriended}).Return(
-					tc.getFriendshipByUserIDAndStatusData, tc.getFriendshipByUserIDAndStatusError).Once()
+				mockFriendshipRepo.On("GetFriendshipByUserIDAndStatus", ctx, mapEmails, page, []domain.FriendshipStatus{domain.FriendshipStatusFriended}).Return(
+					tc.getFriendshipByUserIDAndStatusData, tc.nextCursor, tc.getFriendshipByUserIDAndStatusError).Once()
 			}
 
-			ids, err := h.Handle(ctx, emails[0])
+			ids, cursor, err := h.Handle(ctx, emails[0], page)
 			assert.Equal(t, err, tc.err)
 			assert.Equal(t, tc.result, ids)
+			assert.Equal(t, tc.nextCursor, cursor)
 
 			mock.AssertExpectationsForObjects(t, mockFriendshipRepo, mockUserRepo)
 		})
@@ -0,0 +1,45 @@
+package query
+
+import (
+	"context"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+type ListSubscribersHandler struct {
+	userRepo         domain.UserRepo
+	subscriptionRepo domain.SubscriptionRepo
+}
+
+func NewListSubscribersHandler(subscriptionRepo domain.SubscriptionRepo, userRepo domain.UserRepo) ListSubscribersHandler {
+	return ListSubscribersHandler{
+		subscriptionRepo: subscriptionRepo,
+		userRepo:         userRepo,
+	}
+}
+
+// Handle lists a page of the users subscribed to the user and the cursor of the next page
+func (h ListSubscribersHandler) Handle(ctx context.Context, email string, page domain.Page) ([]string, string, error) {
+	// get userId from email to check available
+	mapEmailUser, err := h.userRepo.GetUserIDsByEmails(ctx, []string{email})
+	if err != nil {
+		logger.Errorf("userRepo.GetUserIDsByEmails %w", err)
+		if err == domain.ErrNotFoundUserByEmail {
+			return nil, "", common.ErrInvalidRequest(err, "emails")
+		}
+		return nil, "", common.ErrCannotGetEntity(domain.User{}.DomainName(), err)
+	}
+
+	subs, nextCursor, err := h.subscriptionRepo.GetSubscriberEmails(ctx, mapEmailUser[email], page)
+	if err != nil {
+		logger.Errorf("subscriptionRepo.GetSubscriberEmails %w", err)
+		if err == domain.ErrCursorIsNotValid {
+			return nil, "", common.ErrInvalidRequest(err, "cursor")
+		}
+		return nil, "", common.ErrCannotListEntity(domain.Subscription{}.DomainName(), err)
+	}
+
+	return subs, nextCursor, nil
+}
@@ -0,0 +1,94 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	mockRepo "github.com/phantranhieunhan/s3-assignment/mock/friendship/repository"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestCase_Subscription_ListSubscribers struct {
+	name                    string
+	result                  []string
+	nextCursor              string
+	err                     error
+	getUserIDsByEmailsError error
+	getUserIDsByEmailsData  map[string]string
+
+	getSubscriberEmailsError error
+	getSubscriberEmailsData  []string
+}
+
+func TestSubscription_ListSubscribers(t *testing.T) {
+	t.Parallel()
+
+	email := "email-1"
+	mapEmails := map[string]string{
+		email: "user-1",
+	}
+	page := domain.Page{Limit: 2, Sort: domain.PageSortEmail}
+	subscribers := []string{"email-2", "email-3"}
+	nextCursor := domain.EncodeCursor(domain.Cursor{Key: "email-3"})
+
+	errDB := errors.New("some error from db")
+
+	tcs := []TestCase_Subscription_ListSubscribers{
+		{
+			name:                    "list successfully",
+			getUserIDsByEmailsData:  mapEmails,
+			getSubscriberEmailsData: subscribers,
+			result:                  subscribers,
+			nextCursor:              nextCursor,
+		},
+		{
+			name:                    "list fail because email invalid",
+			err:                     common.ErrInvalidRequest(domain.ErrNotFoundUserByEmail, "emails"),
+			getUserIDsByEmailsError: domain.ErrNotFoundUserByEmail,
+			getUserIDsByEmailsData:  map[string]string{},
+		},
+		{
+			name:                     "list fail because cursor invalid",
+			getUserIDsByEmailsData:   mapEmails,
+			getSubscriberEmailsError: domain.ErrCursorIsNotValid,
+			getSubscriberEmailsData:  []string{},
+			err:                      common.ErrInvalidRequest(domain.ErrCursorIsNotValid, "cursor"),
+		},
+		{
+			name:                     "list fail because get subscribers fail",
+			getUserIDsByEmailsData:   mapEmails,
+			getSubscriberEmailsError: errDB,
+			getSubscriberEmailsData:  []string{},
+			err:                      common.ErrCannotListEntity(domain.Subscription{}.DomainName(), errDB),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			mockSubscriptionRepo := new(mockRepo.MockSubscriptionRepository)
+			mockUserRepo := new(mockRepo.MockUserRepository)
+			h := NewListSubscribersHandler(mockSubscriptionRepo, mockUserRepo)
+
+			mockUserRepo.On("GetUserIDsByEmails", ctx, []string{email}).Return(tc.getUserIDsByEmailsData, tc.getUserIDsByEmailsError).Once()
+			if tc.getUserIDsByEmailsError == nil {
+				mockSubscriptionRepo.On("GetSubscriberEmails", ctx, mapEmails[email], page).Return(tc.getSubscriberEmailsData, tc.nextCursor, tc.getSubscriberEmailsError).Once()
+			}
+
+			result, cursor, err := h.Handle(ctx, email, page)
+			assert.Equal(t, tc.err, err)
+			assert.Equal(t, tc.result, result)
+			assert.Equal(t, tc.nextCursor, cursor)
+
+			mock.AssertExpectationsForObjects(t, mockSubscriptionRepo, mockUserRepo)
+		})
+	}
+}
@@ -21,7 +21,8 @@ func NewListUpdatesUserHandler(subscriptionRepo domain.SubscriptionRepo, userRep
 	}
 }
 
-func (h ListUpdatesUserHandler) Handle(ctx context.Context, email, text string) ([]string, error) {
+// Handle lists a page of the recipients of the update and the cursor of the next page
+func (h ListUpdatesUserHandler) Handle(ctx context.Context, email, text string, page domain.Page) ([]string, string, error) {
 	emailFromTexts := util.RemoveDuplicates(util.GetEmailsFromString(text))
 
 	// get userId from email to check available
@@ -29,22 +30,25 @@ func (h ListUpdatesUserHandler) Handle(ctx context.Context, email, text string)
 	if err != nil {
 		logger.Errorf("userRepo.GetUserIDsByEmails %w", err)
 		if err == domain.ErrNotFoundUserByEmail {
-			return nil, common.ErrInvalidRequest(err, "emails")
+			return nil, "", common.ErrInvalidRequest(err, "emails")
 		}
-		return nil, common.ErrCannotGetEntity(domain.User{}.DomainName(), err)
+		return nil, "", common.ErrCannotGetEntity(domain.User{}.DomainName(), err)
 	}
 
 	userID, ok := mapEmailUser[email]
 	if !ok {
-		return nil, common.ErrInvalidRequest(nil, "email")
+		return nil, "", common.ErrInvalidRequest(nil, "email")
 	}
 
 	// get list subscription from userId
-	subs, err := h.subscriptionRepo.GetSubscriptionEmailsByUserIDAndEmails(ctx, userID, emailFromTexts)
+	subs, nextCursor, err := h.subscriptionRepo.GetSubscriptionEmailsByUserIDAndEmails(ctx, userID, emailFromTexts, page)
 	if err != nil {
 		logger.Errorf("subscriptionRepo.GetSubscriptionEmailsByUserIDAndStatus %w", err)
-		return nil, common.ErrCannotListEntity(domain.Subscription{}.DomainName(), err)
+		if err == domain.ErrCursorIsNotValid {
+			return nil, "", common.ErrInvalidRequest(err, "cursor")
+		}
+		return nil, "", common.ErrCannotListEntity(domain.Subscription{}.DomainName(), err)
 	}
 
-	return subs, nil
+	return subs, nextCursor, nil
 }
@@ -18,6 +18,7 @@ func TestFriendship_ListUpdatesUserHandler(t *testing.T) {
 	t.Parallel()
 	emails := []string{"john@example.com", "lisa@example.com", "kate@example.com", "email1@example.com", "email2@example.com", "email3@example.com", "email4@example.com"}
 	friends := []string{"friend-1", "friend-2", "friend-3", "friend-4"}
+	page := domain.Page{Limit: domain.DefaultPageLimit, Sort: domain.PageSortEmail}
 
 	errDB := errors.New("some error from db")
 
@@ -99,9 +100,9 @@ func TestFriendship_ListUpdatesUserHandler(t *testing.T) {
 
 			mockUserRepo.On("GetUserIDsByEmails", ctx, []string{tc.getUserIDsByEmailsParam}).Return(tc.getUserIDsByEmailsData, tc.getUserIDsByEmailsError).Once()
 			if tc.getUserIDsByEmailsError == nil {
-				mockSubscriptionRepo.On("GetSubscriptionEmailsByUserIDAndEmails", ctx, friends[0], tc.mentionedEmails).Return(tc.getSubscriptionSubscribedData, tc.getSubscriptionSubscribedError).Once()
+				mockSubscriptionRepo.On("GetSubscriptionEmailsByUserIDAndEmails", ctx, friends[0], tc.mentionedEmails, page).Return(tc.getSubscriptionSubscribedData, "", tc.getSubscriptionSubscribedError).Once()
 			}
-			emails, err := h.Handle(ctx, emails[0], tc.text)
+			emails, _, err := h.Handle(ctx, emails[0], tc.text, page)
 			assert.Equal(t, err, tc.err)
 			assert.Equal(t, tc.result, emails)
 
@@ -0,0 +1,37 @@
+package query
+
+import (
+	"context"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+type ListUserEmailsHandler struct {
+	userRepo domain.UserRepo
+}
+
+func NewListUserEmailsHandler(userRepo domain.UserRepo) ListUserEmailsHandler {
+	return ListUserEmailsHandler{
+		userRepo: userRepo,
+	}
+}
+
+// Handle maps the user ids to their emails in a single lookup, it lets the callers batch the ids they need
+func (h ListUserEmailsHandler) Handle(ctx context.Context, userIDs []string) (map[string]string, error) {
+	if len(userIDs) == 0 {
+		return map[string]string{}, nil
+	}
+
+	emails, err := h.userRepo.GetEmailsByUserIDs(ctx, userIDs)
+	if err != nil {
+		logger.Errorf("userRepo.GetEmailsByUserIDs %w", err)
+		if err == domain.ErrNotFoundUserByEmail {
+			return nil, common.ErrInvalidRequest(err, "user_ids")
+		}
+		return nil, common.ErrCannotListEntity(domain.User{}.DomainName(), err)
+	}
+
+	return emails, nil
+}
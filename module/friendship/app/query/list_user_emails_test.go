@@ -0,0 +1,77 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	mockRepo "github.com/phantranhieunhan/s3-assignment/mock/friendship/repository"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestCase_User_ListUserEmails struct {
+	name    string
+	userIDs []string
+	result  map[string]string
+	err     error
+
+	getEmailsByUserIDsError error
+	getEmailsByUserIDsData  map[string]string
+}
+
+func TestUser_ListUserEmails(t *testing.T) {
+	t.Parallel()
+
+	userIDs := []string{"user-1", "user-2"}
+	mapUserEmails := map[string]string{"user-1": "email-1", "user-2": "email-2"}
+	errDB := errors.New("some error from db")
+
+	tcs := []TestCase_User_ListUserEmails{
+		{
+			name:                   "list user emails successfully",
+			userIDs:                userIDs,
+			getEmailsByUserIDsData: mapUserEmails,
+			result:                 mapUserEmails,
+		},
+		{
+			name:   "list user emails successfully without any id",
+			result: map[string]string{},
+		},
+		{
+			name:                    "list user emails fail because an id is not found",
+			userIDs:                 userIDs,
+			getEmailsByUserIDsError: domain.ErrNotFoundUserByEmail,
+			err:                     common.ErrInvalidRequest(domain.ErrNotFoundUserByEmail, "user_ids"),
+		},
+		{
+			name:                    "list user emails fail because db fail",
+			userIDs:                 userIDs,
+			getEmailsByUserIDsError: errDB,
+			err:                     common.ErrCannotListEntity(domain.User{}.DomainName(), errDB),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			mockUserRepo := new(mockRepo.MockUserRepository)
+			h := NewListUserEmailsHandler(mockUserRepo)
+
+			if len(tc.userIDs) > 0 {
+				mockUserRepo.On("GetEmailsByUserIDs", ctx, tc.userIDs).Return(tc.getEmailsByUserIDsData, tc.getEmailsByUserIDsError).Once()
+			}
+
+			result, err := h.Handle(ctx, tc.userIDs)
+			assert.Equal(t, tc.err, err)
+			assert.Equal(t, tc.result, result)
+			mock.AssertExpectationsForObjects(t, mockUserRepo)
+		})
+	}
+}
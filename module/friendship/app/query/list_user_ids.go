@@ -0,0 +1,37 @@
+package query
+
+import (
+	"context"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+type ListUserIDsHandler struct {
+	userRepo domain.UserRepo
+}
+
+func NewListUserIDsHandler(userRepo domain.UserRepo) ListUserIDsHandler {
+	return ListUserIDsHandler{
+		userRepo: userRepo,
+	}
+}
+
+// Handle maps the emails to the ids of their users in a single lookup, it lets the callers batch the emails they need
+func (h ListUserIDsHandler) Handle(ctx context.Context, emails []string) (map[string]string, error) {
+	if len(emails) == 0 {
+		return map[string]string{}, nil
+	}
+
+	ids, err := h.userRepo.GetUserIDsByEmails(ctx, emails)
+	if err != nil {
+		logger.Errorf("userRepo.GetUserIDsByEmails %w", err)
+		if err == domain.ErrNotFoundUserByEmail {
+			return nil, common.ErrInvalidRequest(err, "emails")
+		}
+		return nil, common.ErrCannotListEntity(domain.User{}.DomainName(), err)
+	}
+
+	return ids, nil
+}
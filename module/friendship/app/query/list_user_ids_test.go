@@ -0,0 +1,77 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	mockRepo "github.com/phantranhieunhan/s3-assignment/mock/friendship/repository"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestCase_User_ListUserIDs struct {
+	name   string
+	emails []string
+	result map[string]string
+	err    error
+
+	getUserIDsByEmailsError error
+	getUserIDsByEmailsData  map[string]string
+}
+
+func TestUser_ListUserIDs(t *testing.T) {
+	t.Parallel()
+
+	emails := []string{"email-1", "email-2"}
+	mapEmailUsers := map[string]string{"email-1": "user-1", "email-2": "user-2"}
+	errDB := errors.New("some error from db")
+
+	tcs := []TestCase_User_ListUserIDs{
+		{
+			name:                   "list user ids successfully",
+			emails:                 emails,
+			getUserIDsByEmailsData: mapEmailUsers,
+			result:                 mapEmailUsers,
+		},
+		{
+			name:   "list user ids successfully without any email",
+			result: map[string]string{},
+		},
+		{
+			name:                    "list user ids fail because an email is not found",
+			emails:                  emails,
+			getUserIDsByEmailsError: domain.ErrNotFoundUserByEmail,
+			err:                     common.ErrInvalidRequest(domain.ErrNotFoundUserByEmail, "emails"),
+		},
+		{
+			name:                    "list user ids fail because db fail",
+			emails:                  emails,
+			getUserIDsByEmailsError: errDB,
+			err:                     common.ErrCannotListEntity(domain.User{}.DomainName(), errDB),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			mockUserRepo := new(mockRepo.MockUserRepository)
+			h := NewListUserIDsHandler(mockUserRepo)
+
+			if len(tc.emails) > 0 {
+				mockUserRepo.On("GetUserIDsByEmails", ctx, tc.emails).Return(tc.getUserIDsByEmailsData, tc.getUserIDsByEmailsError).Once()
+			}
+
+			result, err := h.Handle(ctx, tc.emails)
+			assert.Equal(t, tc.err, err)
+			assert.Equal(t, tc.result, result)
+			mock.AssertExpectationsForObjects(t, mockUserRepo)
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package query
+
+import (
+	"context"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+type ListWebhooksHandler struct {
+	webhookRepo domain.WebhookRepo
+}
+
+func NewListWebhooksHandler(webhookRepo domain.WebhookRepo) ListWebhooksHandler {
+	return ListWebhooksHandler{
+		webhookRepo: webhookRepo,
+	}
+}
+
+// Handle lists the registered webhooks, the oldest first
+func (h ListWebhooksHandler) Handle(ctx context.Context) ([]domain.Webhook, error) {
+	result, err := h.webhookRepo.List(ctx)
+	if err != nil {
+		logger.Errorf("webhookRepo.List %w", err)
+		return nil, common.ErrCannotListEntity(domain.Webhook{}.DomainName(), err)
+	}
+	return result, nil
+}
+
+type ListDeadWebhookDeliveriesHandler struct {
+	webhookRepo domain.WebhookRepo
+}
+
+func NewListDeadWebhookDeliveriesHandler(webhookRepo domain.WebhookRepo) ListDeadWebhookDeliveriesHandler {
+	return ListDeadWebhookDeliveriesHandler{
+		webhookRepo: webhookRepo,
+	}
+}
+
+// Handle lists the dead letters of the webhook, the deliveries which ran out of attempts, the newest first
+func (h ListDeadWebhookDeliveriesHandler) Handle(ctx context.Context, webhookID string) ([]domain.WebhookDelivery, error) {
+	result, err := h.webhookRepo.GetDeliveriesByStatus(ctx, webhookID, domain.WebhookDeliveryStatusDead)
+	if err != nil {
+		logger.Errorf("webhookRepo.GetDeliveriesByStatus %w", err)
+		return nil, common.ErrCannotListEntity(domain.WebhookDelivery{}.DomainName(), err)
+	}
+	return result, nil
+}
@@ -0,0 +1,113 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	mockRepo "github.com/phantranhieunhan/s3-assignment/mock/friendship/repository"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestCase_Webhook_ListWebhooks struct {
+	name   string
+	result []domain.Webhook
+	err    error
+
+	listData  []domain.Webhook
+	listError error
+}
+
+func TestWebhook_ListWebhooks(t *testing.T) {
+	t.Parallel()
+
+	webhooks := []domain.Webhook{
+		{Base: domain.Base{Id: "webhook-1"}, URL: "https://partner.example.com/hook", EventTypes: []domain.EventType{domain.EventUserBlocked}},
+	}
+	errDB := errors.New("some error from db")
+
+	tcs := []TestCase_Webhook_ListWebhooks{
+		{
+			name:     "list successfully",
+			listData: webhooks,
+			result:   webhooks,
+		},
+		{
+			name:      "list fail because list webhooks fail",
+			listData:  []domain.Webhook{},
+			listError: errDB,
+			err:       common.ErrCannotListEntity(domain.Webhook{}.DomainName(), errDB),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			mockWebhookRepo := new(mockRepo.MockWebhookRepository)
+			h := NewListWebhooksHandler(mockWebhookRepo)
+
+			mockWebhookRepo.On("List", ctx).Return(tc.listData, tc.listError).Once()
+
+			result, err := h.Handle(ctx)
+			assert.Equal(t, tc.err, err)
+			assert.Equal(t, tc.result, result)
+			mock.AssertExpectationsForObjects(t, mockWebhookRepo)
+		})
+	}
+}
+
+type TestCase_Webhook_ListDeadWebhookDeliveries struct {
+	name   string
+	result []domain.WebhookDelivery
+	err    error
+
+	getDeliveriesData  []domain.WebhookDelivery
+	getDeliveriesError error
+}
+
+func TestWebhook_ListDeadWebhookDeliveries(t *testing.T) {
+	t.Parallel()
+
+	deliveries := []domain.WebhookDelivery{
+		{Base: domain.Base{Id: "delivery-1"}, WebhookID: "webhook-id", Status: domain.WebhookDeliveryStatusDead, Attempts: 8},
+	}
+	errDB := errors.New("some error from db")
+
+	tcs := []TestCase_Webhook_ListDeadWebhookDeliveries{
+		{
+			name:              "list successfully",
+			getDeliveriesData: deliveries,
+			result:            deliveries,
+		},
+		{
+			name:               "list fail because get deliveries fail",
+			getDeliveriesData:  []domain.WebhookDelivery{},
+			getDeliveriesError: errDB,
+			err:                common.ErrCannotListEntity(domain.WebhookDelivery{}.DomainName(), errDB),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			mockWebhookRepo := new(mockRepo.MockWebhookRepository)
+			h := NewListDeadWebhookDeliveriesHandler(mockWebhookRepo)
+
+			mockWebhookRepo.On("GetDeliveriesByStatus", ctx, "webhook-id", domain.WebhookDeliveryStatusDead).Return(tc.getDeliveriesData, tc.getDeliveriesError).Once()
+
+			result, err := h.Handle(ctx, "webhook-id")
+			assert.Equal(t, tc.err, err)
+			assert.Equal(t, tc.result, result)
+			mock.AssertExpectationsForObjects(t, mockWebhookRepo)
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+// Dispatcher is the EventPublisher queuing a delivery of the event for every webhook registered to its type.
+// The relay calls it within its transaction so that the deliveries are queued if and only if the event is marked delivered
+type Dispatcher struct {
+	webhookRepo domain.WebhookRepo
+}
+
+func NewDispatcher(webhookRepo domain.WebhookRepo) Dispatcher {
+	return Dispatcher{
+		webhookRepo: webhookRepo,
+	}
+}
+
+func (d Dispatcher) Publish(ctx context.Context, e domain.Event) error {
+	webhooks, err := d.webhookRepo.GetByEventType(ctx, e.Type)
+	if err != nil {
+		logger.Errorf("webhookRepo.GetByEventType %w", err)
+		return err
+	}
+
+	now := time.Now().UTC()
+	for _, w := range webhooks {
+		_, err = d.webhookRepo.CreateDelivery(ctx, domain.WebhookDelivery{
+			Base: domain.Base{
+				CreatedAt: now,
+				UpdatedAt: now,
+			},
+			WebhookID:     w.Base.Id,
+			EventID:       e.ID,
+			EventType:     e.Type,
+			Payload:       e.Payload,
+			Status:        domain.WebhookDeliveryStatusPending,
+			NextAttemptAt: now,
+		})
+		if err != nil {
+			logger.Errorf("webhookRepo.CreateDelivery %w", err)
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	mockRepo "github.com/phantranhieunhan/s3-assignment/mock/friendship/repository"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestCase_Dispatcher_Publish struct {
+	name string
+
+	getByEventTypeData  []domain.Webhook
+	getByEventTypeError error
+
+	createDeliveryError error
+
+	err error
+}
+
+func TestDispatcher_Publish(t *testing.T) {
+	t.Parallel()
+
+	webhooks := []domain.Webhook{
+		{Base: domain.Base{Id: "webhook-1"}, EventTypes: []domain.EventType{domain.EventUserBlocked}},
+		{Base: domain.Base{Id: "webhook-2"}, EventTypes: []domain.EventType{domain.EventUserBlocked, domain.EventUserSubscribed}},
+	}
+	errDB := errors.New("some error from db")
+
+	tcs := []TestCase_Dispatcher_Publish{
+		{
+			name:               "queue a delivery for every webhook registered to the event type",
+			getByEventTypeData: webhooks,
+		},
+		{
+			name:               "queue nothing when no webhook is registered to the event type",
+			getByEventTypeData: []domain.Webhook{},
+		},
+		{
+			name:                "publish fail because get webhooks fail",
+			getByEventTypeData:  []domain.Webhook{},
+			getByEventTypeError: errDB,
+			err:                 errDB,
+		},
+		{
+			name:                "publish fail because create delivery fail",
+			getByEventTypeData:  webhooks,
+			createDeliveryError: errDB,
+			err:                 errDB,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			mockWebhookRepo := new(mockRepo.MockWebhookRepository)
+			d := NewDispatcher(mockWebhookRepo)
+
+			e := domain.Event{
+				ID:          "event-id",
+				Type:        domain.EventUserBlocked,
+				AggregateID: "block-id",
+				Payload:     json.RawMessage(`{"user_id":"user-1","target_id":"user-2"}`),
+			}
+			mockWebhookRepo.On("GetByEventType", ctx, e.Type).Return(tc.getByEventTypeData, tc.getByEventTypeError).Once()
+			for _, w := range tc.getByEventTypeData {
+				webhookID := w.Base.Id
+				mockWebhookRepo.On("CreateDelivery", ctx, mock.MatchedBy(func(d domain.WebhookDelivery) bool {
+					return d.WebhookID == webhookID && d.EventID == e.ID && d.EventType == e.Type &&
+						string(d.Payload) == string(e.Payload) && d.Status == domain.WebhookDeliveryStatusPending
+				})).Return("delivery-id", tc.createDeliveryError).Once()
+				if tc.createDeliveryError != nil {
+					break
+				}
+			}
+
+			err := d.Publish(ctx, e)
+			assert.Equal(t, tc.err, err)
+			mock.AssertExpectationsForObjects(t, mockWebhookRepo)
+		})
+	}
+}
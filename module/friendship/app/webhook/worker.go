@@ -0,0 +1,168 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+type WorkerConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	// MaxAttempts is the number of failed attempts after which a delivery moves to the dead letters
+	MaxAttempts int
+	// BackoffBase is the wait after the first failed attempt, it doubles after every failed attempt up to BackoffMax
+	BackoffBase time.Duration
+	BackoffMax  time.Duration
+	// Lease is how long the deliveries claimed by a worker are held while it sends them, a delivery whose worker
+	// stopped before recording the outcome is due again after it. It must outlast the sending of a batch
+	Lease time.Duration
+}
+
+// Worker sends the due webhook deliveries and schedules the failed ones again with an exponential backoff
+type Worker struct {
+	webhookRepo domain.WebhookRepo
+	sender      domain.WebhookSender
+	transactor  command.Transactor
+	config      WorkerConfig
+	now         func() time.Time
+}
+
+func NewWorker(webhookRepo domain.WebhookRepo, sender domain.WebhookSender, transactor command.Transactor, config WorkerConfig) Worker {
+	return Worker{
+		webhookRepo: webhookRepo,
+		sender:      sender,
+		transactor:  transactor,
+		config:      config,
+		now: func() time.Time {
+			return time.Now().UTC()
+		},
+	}
+}
+
+// Run delivers every poll interval until the context is done
+func (w Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// drain the due deliveries without waiting for the next tick
+			for {
+				n, err := w.DeliverOnce(ctx)
+				if err != nil {
+					logger.Errorf("worker.DeliverOnce %w", err)
+				}
+				if err != nil || n < w.config.BatchSize {
+					break
+				}
+			}
+		}
+	}
+}
+
+// DeliverOnce attempts a batch of due deliveries and returns how many were attempted. The deliveries are claimed
+// in a first transaction, sent outside of any transaction so that no lock is held while waiting for the webhooks,
+// and their outcomes are recorded in a second transaction
+func (w Worker) DeliverOnce(ctx context.Context) (int, error) {
+	due, err := w.claim(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if len(due) == 0 {
+		return 0, nil
+	}
+
+	deliveries := make([]domain.WebhookDelivery, 0, len(due))
+	for _, d := range due {
+		deliveries = append(deliveries, w.attempt(ctx, d))
+	}
+
+	err = w.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		for _, delivery := range deliveries {
+			if err := w.webhookRepo.UpdateDelivery(ctx, delivery); err != nil {
+				logger.Errorf("webhookRepo.UpdateDelivery %w", err)
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(due), nil
+}
+
+// claim takes a batch of due deliveries and moves their next attempt to the end of the lease,
+// the other workers skip them until then
+func (w Worker) claim(ctx context.Context) ([]domain.DueWebhookDelivery, error) {
+	var due []domain.DueWebhookDelivery
+	err := w.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		now := w.now()
+		due, err = w.webhookRepo.GetDueDeliveries(ctx, now, w.config.BatchSize)
+		if err != nil {
+			logger.Errorf("webhookRepo.GetDueDeliveries %w", err)
+			return err
+		}
+
+		for _, d := range due {
+			claimed := d.Delivery
+			claimed.NextAttemptAt = now.Add(w.config.Lease)
+			if err = w.webhookRepo.UpdateDelivery(ctx, claimed); err != nil {
+				logger.Errorf("webhookRepo.UpdateDelivery %w", err)
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return due, nil
+}
+
+// attempt sends the delivery and returns it with the outcome of the attempt
+func (w Worker) attempt(ctx context.Context, d domain.DueWebhookDelivery) domain.WebhookDelivery {
+	delivery := d.Delivery
+	delivery.Attempts++
+	now := w.now()
+	delivery.Base.UpdatedAt = now
+
+	err := w.sender.Send(ctx, d.Webhook, delivery)
+	if err == nil {
+		delivery.Status = domain.WebhookDeliveryStatusDelivered
+		delivery.LastError = ""
+		return delivery
+	}
+
+	logger.Errorf("sender.Send %s %w", delivery.Base.Id, err)
+	delivery.LastError = err.Error()
+	if delivery.Attempts >= w.config.MaxAttempts {
+		delivery.Status = domain.WebhookDeliveryStatusDead
+		return delivery
+	}
+	delivery.NextAttemptAt = now.Add(w.Backoff(delivery.Attempts))
+	return delivery
+}
+
+// Backoff is the wait before the next attempt after the given number of failed attempts
+func (w Worker) Backoff(attempts int) time.Duration {
+	backoff := w.config.BackoffBase
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= w.config.BackoffMax {
+			return w.config.BackoffMax
+		}
+	}
+	if backoff > w.config.BackoffMax {
+		return w.config.BackoffMax
+	}
+	return backoff
+}
@@ -0,0 +1,197 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	mockRepo "github.com/phantranhieunhan/s3-assignment/mock/friendship/repository"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// transactor runs the function as is and returns its error like a rollback would
+type transactor struct{}
+
+func (transactor) WithinTransaction(ctx context.Context, f func(ctx context.Context) error) error {
+	return f(ctx)
+}
+
+// trackingTransactor runs the function as is and tells whether it is running
+type trackingTransactor struct {
+	running *bool
+}
+
+func (t trackingTransactor) WithinTransaction(ctx context.Context, f func(ctx context.Context) error) error {
+	*t.running = true
+	defer func() { *t.running = false }()
+	return f(ctx)
+}
+
+func testWorkerConfig() WorkerConfig {
+	return WorkerConfig{
+		PollInterval: time.Second,
+		BatchSize:    10,
+		MaxAttempts:  3,
+		BackoffBase:  time.Second,
+		BackoffMax:   time.Minute,
+		Lease:        time.Hour,
+	}
+}
+
+type TestCase_Worker_DeliverOnce struct {
+	name string
+
+	attempts int
+
+	getDueDeliveriesError error
+	claimError            error
+	sendError             error
+	updateDeliveryError   error
+
+	updatedDelivery func(d domain.WebhookDelivery) domain.WebhookDelivery
+
+	n   int
+	err error
+}
+
+func TestWorker_DeliverOnce(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+	errDB := errors.New("some error from db")
+	errSend := errors.New("webhook responded with status 500")
+
+	tcs := []TestCase_Worker_DeliverOnce{
+		{
+			name: "deliver successfully",
+			updatedDelivery: func(d domain.WebhookDelivery) domain.WebhookDelivery {
+				d.Attempts = 1
+				d.Status = domain.WebhookDeliveryStatusDelivered
+				return d
+			},
+			n: 1,
+		},
+		{
+			name:      "schedule the delivery again after the first failed attempt",
+			sendError: errSend,
+			updatedDelivery: func(d domain.WebhookDelivery) domain.WebhookDelivery {
+				d.Attempts = 1
+				d.LastError = errSend.Error()
+				d.NextAttemptAt = now.Add(time.Second)
+				return d
+			},
+			n: 1,
+		},
+		{
+			name:      "schedule the delivery again with a doubled backoff",
+			attempts:  1,
+			sendError: errSend,
+			updatedDelivery: func(d domain.WebhookDelivery) domain.WebhookDelivery {
+				d.Attempts = 2
+				d.LastError = errSend.Error()
+				d.NextAttemptAt = now.Add(2 * time.Second)
+				return d
+			},
+			n: 1,
+		},
+		{
+			name:      "move the delivery to the dead letters after the last attempt",
+			attempts:  2,
+			sendError: errSend,
+			updatedDelivery: func(d domain.WebhookDelivery) domain.WebhookDelivery {
+				d.Attempts = 3
+				d.LastError = errSend.Error()
+				d.Status = domain.WebhookDeliveryStatusDead
+				return d
+			},
+			n: 1,
+		},
+		{
+			name:                  "deliver fail because get due deliveries fail",
+			getDueDeliveriesError: errDB,
+			err:                   errDB,
+		},
+		{
+			name:       "deliver fail without sending because the claim fail",
+			claimError: errDB,
+			err:        errDB,
+		},
+		{
+			name:                "deliver fail because update delivery fail",
+			updateDeliveryError: errDB,
+			updatedDelivery: func(d domain.WebhookDelivery) domain.WebhookDelivery {
+				d.Attempts = 1
+				d.Status = domain.WebhookDeliveryStatusDelivered
+				return d
+			},
+			err: errDB,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			mockWebhookRepo := new(mockRepo.MockWebhookRepository)
+			mockSender := new(mockRepo.MockWebhookSender)
+			var inTransaction bool
+			w := NewWorker(mockWebhookRepo, mockSender, trackingTransactor{running: &inTransaction}, testWorkerConfig())
+			w.now = func() time.Time { return now }
+
+			webhook := domain.Webhook{Base: domain.Base{Id: "webhook-id"}, URL: "http://localhost/hook", Secret: "a-secret-of-16-chars"}
+			delivery := domain.WebhookDelivery{
+				Base:          domain.Base{Id: "delivery-id"},
+				WebhookID:     webhook.Base.Id,
+				EventID:       "event-id",
+				EventType:     domain.EventUserSubscribed,
+				Status:        domain.WebhookDeliveryStatusPending,
+				Attempts:      tc.attempts,
+				NextAttemptAt: now,
+			}
+			due := []domain.DueWebhookDelivery{{Delivery: delivery, Webhook: webhook}}
+
+			mockWebhookRepo.On("GetDueDeliveries", ctx, now, 10).Return(due, tc.getDueDeliveriesError).Once()
+			if tc.getDueDeliveriesError == nil {
+				// the delivery is held for the lease while it is sent
+				claimed := delivery
+				claimed.NextAttemptAt = now.Add(time.Hour)
+				mockWebhookRepo.On("UpdateDelivery", ctx, claimed).Return(tc.claimError).Once()
+			}
+			if tc.getDueDeliveriesError == nil && tc.claimError == nil {
+				attempted := delivery
+				attempted.Attempts++
+				attempted.Base.UpdatedAt = now
+				mockSender.On("Send", ctx, webhook, attempted).Run(func(args mock.Arguments) {
+					assert.False(t, inTransaction, "the webhook is sent outside of a transaction")
+				}).Return(tc.sendError).Once()
+				mockWebhookRepo.On("UpdateDelivery", ctx, tc.updatedDelivery(attempted)).Return(tc.updateDeliveryError).Once()
+			}
+
+			n, err := w.DeliverOnce(ctx)
+			assert.Equal(t, tc.err, err)
+			assert.Equal(t, tc.n, n)
+			mock.AssertExpectationsForObjects(t, mockWebhookRepo, mockSender)
+		})
+	}
+}
+
+func TestWorker_Backoff(t *testing.T) {
+	t.Parallel()
+
+	w := NewWorker(nil, nil, transactor{}, testWorkerConfig())
+	for attempts, expected := range map[int]time.Duration{
+		1:  time.Second,
+		2:  2 * time.Second,
+		3:  4 * time.Second,
+		6:  32 * time.Second,
+		7:  time.Minute,
+		50: time.Minute,
+	} {
+		assert.Equal(t, expected, w.Backoff(attempts), attempts)
+	}
+}
@@ -0,0 +1,152 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrAuditActionIsNotValid = errors.New("audit action is not valid")
+	ErrAuditRangeIsNotValid  = errors.New("from must not be after to")
+)
+
+// AuditAction names the command an audit entry records, one action per command of the application
+type AuditAction string
+
+const (
+	// AuditConnectFriendship is kept for the entries written when a connection made friends without a request
+	AuditConnectFriendship     AuditAction = "ConnectFriendship"
+	AuditSubscribeUser         AuditAction = "SubscribeUser"
+	AuditBlockUpdatesUser      AuditAction = "BlockUpdatesUser"
+	AuditRequestFriendship     AuditAction = "RequestFriendship"
+	AuditAcceptFriendship      AuditAction = "AcceptFriendship"
+	AuditRejectFriendship      AuditAction = "RejectFriendship"
+	AuditCancelFriendship      AuditAction = "CancelFriendship"
+	AuditUnfriend              AuditAction = "Unfriend"
+	AuditUnblockUser           AuditAction = "UnblockUser"
+	AuditCreateUser            AuditAction = "CreateUser"
+	AuditUpdateUser            AuditAction = "UpdateUser"
+	AuditDeleteUser            AuditAction = "DeleteUser" // kept for the entries of the users deleted before the account deletion
+	AuditDeleteAccount         AuditAction = "DeleteAccount"
+	AuditCancelAccountDeletion AuditAction = "CancelAccountDeletion"
+	AuditPurgeAccount          AuditAction = "PurgeAccount"
+	AuditLogin                 AuditAction = "Login"
+	AuditSetPassword           AuditAction = "SetPassword"
+	AuditPostUpdate            AuditAction = "PostUpdate"
+	AuditRegisterWebhook       AuditAction = "RegisterWebhook"
+	AuditDeleteWebhook         AuditAction = "DeleteWebhook"
+	AuditReplayWebhookDelivery AuditAction = "ReplayWebhookDelivery"
+	AuditImportRelationships   AuditAction = "ImportRelationships"
+)
+
+var auditActions = map[AuditAction]struct{}{
+	AuditConnectFriendship:     {},
+	AuditSubscribeUser:         {},
+	AuditBlockUpdatesUser:      {},
+	AuditRequestFriendship:     {},
+	AuditAcceptFriendship:      {},
+	AuditRejectFriendship:      {},
+	AuditCancelFriendship:      {},
+	AuditUnfriend:              {},
+	AuditUnblockUser:           {},
+	AuditCreateUser:            {},
+	AuditUpdateUser:            {},
+	AuditDeleteUser:            {},
+	AuditDeleteAccount:         {},
+	AuditCancelAccountDeletion: {},
+	AuditPurgeAccount:          {},
+	AuditLogin:                 {},
+	AuditSetPassword:           {},
+	AuditPostUpdate:            {},
+	AuditRegisterWebhook:       {},
+	AuditDeleteWebhook:         {},
+	AuditReplayWebhookDelivery: {},
+	AuditImportRelationships:   {},
+}
+
+func (a AuditAction) IsValid() bool {
+	_, ok := auditActions[a]
+	return ok
+}
+
+// AuditStatus is the status of the target of a command before or after it, empty when the target does not exist
+type AuditStatus string
+
+const (
+	AuditStatusNone              AuditStatus = ""
+	AuditStatusActive            AuditStatus = "active"
+	AuditStatusDeletionScheduled AuditStatus = "deletion_scheduled"
+	AuditStatusDeleted           AuditStatus = "deleted"
+	AuditStatusFriended          AuditStatus = "friended"
+	AuditStatusPending           AuditStatus = "pending"
+	AuditStatusUnfriended        AuditStatus = "unfriended"
+	AuditStatusBlocked           AuditStatus = "blocked"
+	AuditStatusSubscribed        AuditStatus = "subscribed"
+	AuditStatusUnsubscribed      AuditStatus = "unsubscribed"
+	// AuditStatusDead is the status of a webhook delivery which ran out of attempts
+	AuditStatusDead AuditStatus = "dead"
+)
+
+// FriendshipAuditStatus is the audit status of a friendship, none for a friendship which does not exist
+func FriendshipAuditStatus(s FriendshipStatus) AuditStatus {
+	switch s {
+	case FriendshipStatusFriended:
+		return AuditStatusFriended
+	case FriendshipStatusPending:
+		return AuditStatusPending
+	case FriendshipStatusUnfriended:
+		return AuditStatusUnfriended
+	case FriendshipStatusBlocked:
+		return AuditStatusBlocked
+	}
+	return AuditStatusNone
+}
+
+// SubscriptionAuditStatus is the audit status of a subscription, none for a subscription which does not exist
+func SubscriptionAuditStatus(s SubscriptionStatus) AuditStatus {
+	switch s {
+	case SubscriptionStatusSubscribed:
+		return AuditStatusSubscribed
+	case SubscriptionStatusUnsubscribed:
+		return AuditStatusUnsubscribed
+	}
+	return AuditStatusNone
+}
+
+// AuditEntry records a command run by the actor, the user id of the requestor, "admin" for the admin token
+// or "system" for the background workers, the entries are never updated nor deleted
+type AuditEntry struct {
+	ID           string      `json:"id"`
+	Actor        string      `json:"actor"`
+	Action       AuditAction `json:"action"`
+	TargetIDs    []string    `json:"target_ids"`
+	StatusBefore AuditStatus `json:"status_before"`
+	StatusAfter  AuditStatus `json:"status_after"`
+	RequestID    string      `json:"request_id"`
+	CreatedAt    time.Time   `json:"created_at"`
+}
+
+func (r AuditEntry) DomainName() string {
+	return "AuditEntry"
+}
+
+const (
+	AuditActorAdmin  = "admin"
+	AuditActorSystem = "system"
+)
+
+// AuditFilter narrows the audit log, UserID matches the entries acted by the user or targeting them,
+// From is inclusive and To is exclusive, the empty fields don't filter
+type AuditFilter struct {
+	UserID string
+	Action AuditAction
+	From   time.Time
+	To     time.Time
+}
+
+type AuditRepo interface {
+	Create(ctx context.Context, e AuditEntry) (string, error)
+	// List returns the newest entries first, the cursor is the created_at and the id of the last entry
+	List(ctx context.Context, filter AuditFilter, page Page) ([]AuditEntry, string, error)
+}
@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrBlockNotFound = errors.New("block not found")
+)
+
+// Block records the state between two users right before UserID blocked TargetID,
+// so that the block can be lifted later
+type Block struct {
+	Base               `json:",inline"`
+	UserID             string             `json:"user_id"`
+	TargetID           string             `json:"target_id"`
+	FriendshipStatus   FriendshipStatus   `json:"friendship_status"`
+	SubscriptionStatus SubscriptionStatus `json:"subscription_status"`
+}
+
+func (r Block) DomainName() string {
+	return "Block"
+}
+
+// RestoredFriendshipStatus is the friendship status to set back when the block is lifted
+func (r Block) RestoredFriendshipStatus() FriendshipStatus {
+	switch r.FriendshipStatus {
+	case FriendshipStatusInvalid, FriendshipStatusBlocked:
+		return FriendshipStatusUnfriended
+	default:
+		return r.FriendshipStatus
+	}
+}
+
+// BlockedEmail is the other side of a block with the time the block was made
+type BlockedEmail struct {
+	Email     string    `json:"email"`
+	BlockedAt time.Time `json:"blocked_at"`
+}
+
+type BlockRepo interface {
+	UpsertBlock(ctx context.Context, d Block) (string, error)
+	GetBlock(ctx context.Context, userID, targetID string) (Block, error)
+	Delete(ctx context.Context, id string) error
+	GetBlockedEmailsByUserID(ctx context.Context, userID string) ([]BlockedEmail, error)
+	GetBlockerEmailsByTargetID(ctx context.Context, targetID string) ([]BlockedEmail, error)
+	ListBlocksByUserID(ctx context.Context, userID, afterID string, limit int) ([]Block, error)
+	DeleteByUserID(ctx context.Context, userID string) error
+}
@@ -6,10 +6,22 @@ var (
 	ErrRecordNotFound          = errors.New("record not found")
 	ErrUpdateRecordNotFound    = errors.New("update record not found")
 	ErrFriendshipIsUnavailable = errors.New("error friendship is unavailable")
+	ErrFriendRequestNotFound   = errors.New("friend request not found")
 
-	ErrNotFoundUserByEmail = errors.New("not found user by email")
+	ErrFriendRequestDirectionIsNotValid = errors.New("friend request direction is not valid")
+	ErrFriendSetOperationIsNotValid     = errors.New("friend set operation is not valid")
+	ErrImportTypeIsNotValid             = errors.New("import type must be friendship or subscription")
+
+	ErrNotFoundUserByEmail  = errors.New("not found user by email")
+	ErrUserHasRelations     = errors.New("user still has friendships, subscriptions, blocks or updates")
+	ErrUserDeletionNotFound = errors.New("user has no deletion to cancel")
+	ErrUserIsDeleted        = errors.New("user is scheduled for deletion or deleted")
 
 	ErrEmailIsNotValid = errors.New("emails is not valid")
 
+	ErrPasswordIsNotValid              = errors.New("password must have at least 8 characters")
+	ErrRequestorIsNotAuthenticatedUser = errors.New("requestor is not the authenticated user")
+	ErrRequestIsNotAuthenticated       = errors.New("request is not authenticated")
+
 	ErrAlreadyExists = errors.New("already exists")
 )
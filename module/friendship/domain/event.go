@@ -0,0 +1,107 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// EventType names what happened, the commands write one event per change in the outbox
+type EventType string
+
+const (
+	EventFriendshipConnected   EventType = "FriendshipConnected"
+	EventFriendshipRequested   EventType = "FriendshipRequested"
+	EventFriendshipAccepted    EventType = "FriendshipAccepted"
+	EventFriendshipRejected    EventType = "FriendshipRejected"
+	EventFriendshipCancelled   EventType = "FriendshipCancelled"
+	EventUnfriended            EventType = "Unfriended"
+	EventUserSubscribed        EventType = "UserSubscribed"
+	EventUserBlocked           EventType = "UserBlocked"
+	EventUserUnblocked         EventType = "UserUnblocked"
+	EventUserCreated           EventType = "UserCreated"
+	EventUserUpdated           EventType = "UserUpdated"
+	EventUserDeleted           EventType = "UserDeleted"
+	EventUserDeletionScheduled EventType = "UserDeletionScheduled"
+	EventUserDeletionCancelled EventType = "UserDeletionCancelled"
+	EventUpdatePosted          EventType = "UpdatePosted"
+)
+
+var eventTypes = map[EventType]struct{}{
+	EventFriendshipConnected:   {},
+	EventFriendshipRequested:   {},
+	EventFriendshipAccepted:    {},
+	EventFriendshipRejected:    {},
+	EventFriendshipCancelled:   {},
+	EventUnfriended:            {},
+	EventUserSubscribed:        {},
+	EventUserBlocked:           {},
+	EventUserUnblocked:         {},
+	EventUserCreated:           {},
+	EventUserUpdated:           {},
+	EventUserDeleted:           {},
+	EventUserDeletionScheduled: {},
+	EventUserDeletionCancelled: {},
+	EventUpdatePosted:          {},
+}
+
+func (t EventType) IsValid() bool {
+	_, ok := eventTypes[t]
+	return ok
+}
+
+// Event is a row of the outbox, the aggregate is the entity the event is about
+type Event struct {
+	ID          string          `json:"id"`
+	Type        EventType       `json:"type"`
+	AggregateID string          `json:"aggregate_id"`
+	Payload     json.RawMessage `json:"payload"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+func (r Event) DomainName() string {
+	return "Event"
+}
+
+func NewEvent(eventType EventType, aggregateID string, payload interface{}) (Event, error) {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return Event{}, err
+	}
+	return Event{
+		Type:        eventType,
+		AggregateID: aggregateID,
+		Payload:     b,
+		CreatedAt:   time.Now().UTC(),
+	}, nil
+}
+
+// RelationEvent is the payload of the events between two users, UserID is the user who acted
+type RelationEvent struct {
+	UserID   string `json:"user_id"`
+	TargetID string `json:"target_id"`
+}
+
+// UserEvent is the payload of the events about an account
+type UserEvent struct {
+	Email string `json:"email"`
+}
+
+// UserDeletedEvent is the payload of the deletion of an account, only the id is left of a purged account
+type UserDeletedEvent struct {
+	UserID string `json:"user_id"`
+}
+
+type EventRepo interface {
+	Create(ctx context.Context, e Event) (string, error)
+	// GetUndelivered locks the outbox and returns the oldest events not delivered yet, it must run within a transaction
+	// and no other transaction reads them until it ends
+	GetUndelivered(ctx context.Context, limit int) ([]Event, error)
+	MarkDelivered(ctx context.Context, ids []string) error
+}
+
+// EventPublisher hands the events of the outbox to the outside world, an event is delivered
+// once Publish returns without error
+type EventPublisher interface {
+	Publish(ctx context.Context, e Event) error
+}
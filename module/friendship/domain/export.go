@@ -0,0 +1,33 @@
+package domain
+
+// ExportedFriendship is a friendship in the export of one of its users, with the emails of both users
+type ExportedFriendship struct {
+	Friendship  `json:",inline"`
+	UserEmail   string `json:"user_email"`
+	FriendEmail string `json:"friend_email"`
+}
+
+// ExportedSubscription is a subscription in the export of the user subscribed to or of the subscriber
+type ExportedSubscription struct {
+	Subscription    `json:",inline"`
+	UserEmail       string `json:"user_email"`
+	SubscriberEmail string `json:"subscriber_email"`
+}
+
+// ExportedBlock is a block made by the user of the export, with the email of the user blocked
+type ExportedBlock struct {
+	Block       `json:",inline"`
+	TargetEmail string `json:"target_email"`
+}
+
+// UserExport receives everything held about a user. The user comes first, then the rows of the friendships,
+// the subscriptions, the blocks, the updates posted by the user and the audit entries acted by the user
+// or targeting them, a chunk at a time in this order
+type UserExport interface {
+	WriteUser(u User) error
+	WriteFriendships(list []ExportedFriendship) error
+	WriteSubscriptions(list []ExportedSubscription) error
+	WriteBlocks(list []ExportedBlock) error
+	WriteUpdates(list []Update) error
+	WriteAuditEntries(list []AuditEntry) error
+}
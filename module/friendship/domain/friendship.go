@@ -1,6 +1,9 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"errors"
+)
 
 type FriendshipStatus int
 
@@ -16,14 +19,61 @@ func (f FriendshipStatus) CanConnect() bool {
 	return f == FriendshipStatusUnfriended
 }
 
+// CanBlockUser tells whether blocking moves the friendship to blocked, a pending request is blocked too so that it
+// can't be accepted while the block stands. The friends stay friends
 func (f FriendshipStatus) CanBlockUser() bool {
-	return f == FriendshipStatusUnfriended
+	return f == FriendshipStatusUnfriended || f == FriendshipStatusPending
 }
 
 func (f FriendshipStatus) CanNotSubscribe() bool {
 	return f == FriendshipStatusBlocked
 }
 
+func (f FriendshipStatus) CanRequest() bool {
+	return f == FriendshipStatusUnfriended
+}
+
+func (f FriendshipStatus) IsPending() bool {
+	return f == FriendshipStatusPending
+}
+
+func (f FriendshipStatus) CanUnfriend() bool {
+	return f == FriendshipStatusFriended
+}
+
+// UnfriendSubscriptionPolicy decides what happens to the subscriptions created on connection when friends unfriend
+type UnfriendSubscriptionPolicy string
+
+const (
+	UnfriendSubscriptionPolicyKeep UnfriendSubscriptionPolicy = "keep"
+	UnfriendSubscriptionPolicyDrop UnfriendSubscriptionPolicy = "drop"
+)
+
+var ErrUnfriendSubscriptionPolicyIsNotValid = errors.New("unfriend subscription policy must be keep or drop")
+
+// ParseUnfriendSubscriptionPolicy reads the policy of the config, an unset policy keeps the subscriptions
+func ParseUnfriendSubscriptionPolicy(s string) (UnfriendSubscriptionPolicy, error) {
+	switch p := UnfriendSubscriptionPolicy(s); p {
+	case UnfriendSubscriptionPolicyKeep, UnfriendSubscriptionPolicyDrop:
+		return p, nil
+	case "":
+		return UnfriendSubscriptionPolicyKeep, nil
+	}
+	return "", ErrUnfriendSubscriptionPolicyIsNotValid
+}
+
+func (p UnfriendSubscriptionPolicy) DropSubscriptions() bool {
+	return p == UnfriendSubscriptionPolicyDrop
+}
+
+type FriendRequestDirection int
+
+const (
+	FriendRequestDirectionInvalid FriendRequestDirection = iota
+	FriendRequestDirectionIncoming
+	FriendRequestDirectionOutgoing
+)
+
 type Friendship struct {
 	Base     `json:",inline"`
 	UserID   string           `json:"user_id"`
@@ -43,11 +93,55 @@ func (r Friendship) FriendshipWithBlock(userID, friendID string) Friendship {
 	}
 }
 
+// IsRequestedBy reports whether the friendship is a pending request sent from requesterID to receiverID
+func (r Friendship) IsRequestedBy(requesterID, receiverID string) bool {
+	return r.Status.IsPending() && r.UserID == requesterID && r.FriendID == receiverID
+}
+
 type Friendships []Friendship
 
+// FriendSetOperation combines the friends of several users
+type FriendSetOperation string
+
+const (
+	// FriendSetOperationIntersection keeps the friends shared by all the users
+	FriendSetOperationIntersection FriendSetOperation = "intersection"
+	// FriendSetOperationUnion keeps the friends of any of the users
+	FriendSetOperationUnion FriendSetOperation = "union"
+	// FriendSetOperationDifference keeps the friends of the first user who are not friends of the others
+	FriendSetOperationDifference FriendSetOperation = "difference"
+)
+
+func (o FriendSetOperation) IsValid() bool {
+	switch o {
+	case FriendSetOperationIntersection, FriendSetOperationUnion, FriendSetOperationDifference:
+		return true
+	}
+	return false
+}
+
+// FriendSetMember is a friend in the result of a set operation with how many of the users it is friend with
+type FriendSetMember struct {
+	Email          string `json:"email"`
+	ConnectedUsers int    `json:"connected_users"`
+}
+
+// FriendSuggestion is a friend of friends the user may know with the number of friends they have in common
+type FriendSuggestion struct {
+	Email         string `json:"email"`
+	MutualFriends int    `json:"mutual_friends"`
+}
+
 type FriendshipRepo interface {
 	Create(ctx context.Context, d Friendship) (string, error)
 	UpdateStatus(ctx context.Context, id string, status FriendshipStatus) error
+	Update(ctx context.Context, d Friendship) error
 	GetFriendshipByUserIDs(ctx context.Context, userID, friendID string) (Friendship, error)
-	GetFriendshipByUserIDAndStatus(ctx context.Context, mapEmailUser map[string]string, status ...FriendshipStatus) ([]string, error)
+	GetFriendshipByUserIDAndStatus(ctx context.Context, mapEmailUser map[string]string, page Page, status ...FriendshipStatus) ([]string, string, error)
+	GetFriendRequestEmails(ctx context.Context, userID string, direction FriendRequestDirection) ([]string, error)
+	GetFriendSuggestions(ctx context.Context, userID string, limit int) ([]FriendSuggestion, error)
+	GetShortestFriendPath(ctx context.Context, fromID, toID string, maxDepth int) ([]string, error)
+	GetFriendSet(ctx context.Context, userIDs []string, operation FriendSetOperation) ([]FriendSetMember, error)
+	ListFriendshipsByUserID(ctx context.Context, userID, afterID string, limit int) (Friendships, error)
+	DeleteByUserID(ctx context.Context, userID string) error
 }
@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const IdempotencyKeyMaxLength = 255
+
+var (
+	ErrIdempotencyKeyIsNotValid = errors.New("idempotency key must have 1 to 255 characters")
+	ErrIdempotencyKeyIsReused   = errors.New("idempotency key was used with another request")
+	ErrIdempotencyKeyIsInFlight = errors.New("a request with the idempotency key is still running")
+)
+
+// IdempotencyRecord is the first request made with an idempotency key and, once it is finished, its response.
+// The key belongs to the scope of the caller, the fingerprint identifies the method, the path and the body of the request
+type IdempotencyRecord struct {
+	Scope       string
+	Key         string
+	Fingerprint string
+	// StatusCode is 0 while the request is running
+	StatusCode  int
+	ContentType string
+	Body        []byte
+	// CreatedAt tells the reservations of a key apart, a request only completes or releases its own
+	CreatedAt time.Time
+}
+
+func (r IdempotencyRecord) DomainName() string {
+	return "IdempotencyRecord"
+}
+
+func (r IdempotencyRecord) IsInFlight() bool {
+	return r.StatusCode == 0
+}
+
+type IdempotencyRepo interface {
+	// Reserve stores the record of a request starting unless a record created from expiredBefore on holds its key.
+	// A record still in flight only holds the key while its lease runs, from leaseExpiredBefore on, so that the key
+	// of a request which never finished is not stuck until it expires. It returns the record holding the key and
+	// whether it is the given one
+	Reserve(ctx context.Context, r IdempotencyRecord, expiredBefore, leaseExpiredBefore time.Time) (IdempotencyRecord, bool, error)
+	// Complete stores the response of a reserved record, ErrRecordNotFound when the reservation was taken over
+	Complete(ctx context.Context, r IdempotencyRecord) error
+	// Release deletes a reserved record whose request failed, the key can be used again
+	Release(ctx context.Context, r IdempotencyRecord) error
+	// DeleteExpired deletes the records created before expiredBefore and returns how many there were
+	DeleteExpired(ctx context.Context, expiredBefore time.Time) (int, error)
+}
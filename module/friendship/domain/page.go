@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+var (
+	ErrPageSortIsNotValid = errors.New("sort is not valid")
+	ErrCursorIsNotValid   = errors.New("cursor is not valid")
+)
+
+// PageSort is the order of a list, both orders are ascending and tie on the email
+type PageSort string
+
+const (
+	PageSortEmail     PageSort = "email"
+	PageSortCreatedAt PageSort = "created_at"
+)
+
+func (s PageSort) IsValid() bool {
+	return s == PageSortEmail || s == PageSortCreatedAt
+}
+
+// Page asks for the items after the cursor, at most Limit of them, created at or after Since when it is set
+type Page struct {
+	Limit  int
+	Cursor string
+	Sort   PageSort
+	Since  time.Time
+}
+
+// WithDefaults fills the limit and the sort when they are not set
+func (p Page) WithDefaults() Page {
+	if p.Limit <= 0 {
+		p.Limit = DefaultPageLimit
+	}
+	if p.Sort == "" {
+		p.Sort = PageSortEmail
+	}
+	return p
+}
+
+// Cursor is the position of the last item of a page, it holds both sort keys so it is valid for any sort,
+// the key is the unique tie breaker of the list such as the email
+type Cursor struct {
+	Key       string    `json:"k"`
+	CreatedAt time.Time `json:"c"`
+}
+
+func EncodeCursor(c Cursor) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func DecodeCursor(s string) (Cursor, error) {
+	var c Cursor
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, ErrCursorIsNotValid
+	}
+	if err = json.Unmarshal(b, &c); err != nil || c.Key == "" {
+		return c, ErrCursorIsNotValid
+	}
+	return c, nil
+}
@@ -67,14 +67,14 @@ func (r Subscription) GetSubscriberUserMapKey() string {
 
 type Subscriptions []Subscription
 
-type SubscribeUserCommand interface {
-	HandleWithSubscription(ctx context.Context, ds Subscriptions) error
-}
-
 type SubscriptionRepo interface {
 	Create(ctx context.Context, sub Subscription) (string, error)
 	GetSubscription(ctx context.Context, ss Subscriptions) (Subscriptions, error)
 	UpdateStatus(ctx context.Context, id string, status SubscriptionStatus) error
 	UpsertSubscription(ctx context.Context, sub Subscription) (string, error)
-	GetSubscriptionEmailsByUserIDAndEmails(ctx context.Context, id string, emails []string) ([]string, error)
+	Delete(ctx context.Context, id string) error
+	GetSubscriptionEmailsByUserIDAndEmails(ctx context.Context, id string, emails []string, page Page) ([]string, string, error)
+	GetSubscriberEmails(ctx context.Context, id string, page Page) ([]string, string, error)
+	ListSubscriptionsByUserID(ctx context.Context, userID, afterID string, limit int) (Subscriptions, error)
+	DeleteByUserID(ctx context.Context, userID string) error
 }
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const UpdateTextMaxLength = 1000
+
+var (
+	ErrUpdateTextIsNotValid = errors.New("text must not be empty and have at most 1000 characters")
+)
+
+// Update is a piece of text posted by a user, the mentions are the emails found in the text
+type Update struct {
+	Base     `json:",inline"`
+	UserID   string   `json:"user_id"`
+	Text     string   `json:"text"`
+	Mentions []string `json:"mentions"`
+}
+
+func (r Update) DomainName() string {
+	return "Update"
+}
+
+// FeedItem is an update shown in the feed of a user with the email of its sender
+type FeedItem struct {
+	ID        string    `json:"id"`
+	Sender    string    `json:"sender"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type UpdateRepo interface {
+	Create(ctx context.Context, d Update) (string, error)
+	GetFeed(ctx context.Context, userID string, page Page) ([]FeedItem, string, error)
+	// ListUpdatesByUserID lists the updates posted by the user, ordered by id from the one after afterID
+	ListUpdatesByUserID(ctx context.Context, userID, afterID string, limit int) ([]Update, error)
+	// RemoveMentions takes the email out of the mentions of every update, the text is left as posted
+	RemoveMentions(ctx context.Context, email string) error
+}
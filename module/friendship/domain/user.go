@@ -1,6 +1,13 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// UserPasswordMinLength is the minimum length of the password chosen on registration
+const UserPasswordMinLength = 8
 
 type User struct {
 	Base     Base   `json:",inline"`
@@ -13,7 +20,50 @@ func (r User) DomainName() string {
 	return "User"
 }
 
+// UserDeletion is the deletion of an account waiting for the end of its grace period, it can be cancelled until the purge
+type UserDeletion struct {
+	UserID      string    `json:"user_id"`
+	RequestedAt time.Time `json:"requested_at"`
+	PurgeAt     time.Time `json:"purge_at"`
+}
+
+func (r UserDeletion) DomainName() string {
+	return "UserDeletion"
+}
+
+const (
+	userTombstonePrefix = "deleted-"
+	userTombstoneDomain = "@deleted.invalid"
+)
+
+// UserTombstoneEmail replaces the email of a purged user, the email is free again and nobody can log in with the tombstone
+func UserTombstoneEmail(userID string) string {
+	return userTombstonePrefix + userID + userTombstoneDomain
+}
+
+// IsUserTombstoneEmail tells whether the email is the tombstone of a purged user
+func IsUserTombstoneEmail(email string) bool {
+	return strings.HasPrefix(email, userTombstonePrefix) && strings.HasSuffix(email, userTombstoneDomain)
+}
+
 type UserRepo interface {
 	GetUserIDsByEmails(ctx context.Context, emails []string) (map[string]string, error)
 	GetEmailsByUserIDs(ctx context.Context, userIDs []string) (map[string]string, error)
+	Create(ctx context.Context, d User) (string, error)
+	GetUserByEmail(ctx context.Context, email string) (User, error)
+	Update(ctx context.Context, d User) error
+	Delete(ctx context.Context, id string) error
+	GetPasswordHash(ctx context.Context, userID string) (string, error)
+	SetPasswordHash(ctx context.Context, userID, hash string) error
+	Anonymize(ctx context.Context, id, tombstone string) error
+	ScheduleDeletion(ctx context.Context, d UserDeletion) error
+	GetDeletion(ctx context.Context, userID string) (UserDeletion, error)
+	CancelDeletion(ctx context.Context, userID string) error
+	GetDueDeletions(ctx context.Context, now time.Time, limit int) ([]UserDeletion, error)
+}
+
+// AuthToken is the signed token issued on login
+type AuthToken struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
 }
@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+const WebhookSecretMinLength = 16
+
+var (
+	ErrWebhookURLIsNotValid       = errors.New("webhook url must be an absolute http or https url")
+	ErrWebhookSecretIsNotValid    = errors.New("webhook secret must have at least 16 characters")
+	ErrWebhookEventTypeIsNotValid = errors.New("webhook event type is not valid")
+	ErrWebhookDeliveryIsNotDead   = errors.New("only a dead webhook delivery can be replayed")
+)
+
+// Webhook is an http callback registered by a partner, it receives the events of the given types
+// signed with the secret
+type Webhook struct {
+	Base       `json:",inline"`
+	URL        string      `json:"url"`
+	Secret     string      `json:"-"`
+	EventTypes []EventType `json:"event_types"`
+}
+
+func (r Webhook) DomainName() string {
+	return "Webhook"
+}
+
+func (r Webhook) Accepts(t EventType) bool {
+	for _, et := range r.EventTypes {
+		if et == t {
+			return true
+		}
+	}
+	return false
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryStatusPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryStatusDelivered WebhookDeliveryStatus = "delivered"
+	// WebhookDeliveryStatusDead is the dead letter state of a delivery which ran out of attempts
+	WebhookDeliveryStatusDead WebhookDeliveryStatus = "dead"
+)
+
+// WebhookDelivery is an event to send to a webhook, it keeps a copy of the event so that it can be replayed
+type WebhookDelivery struct {
+	Base          `json:",inline"`
+	WebhookID     string                `json:"webhook_id"`
+	EventID       string                `json:"event_id"`
+	EventType     EventType             `json:"event_type"`
+	Payload       json.RawMessage       `json:"payload"`
+	Status        WebhookDeliveryStatus `json:"status"`
+	Attempts      int                   `json:"attempts"`
+	LastError     string                `json:"last_error"`
+	NextAttemptAt time.Time             `json:"next_attempt_at"`
+}
+
+func (r WebhookDelivery) DomainName() string {
+	return "WebhookDelivery"
+}
+
+// DueWebhookDelivery is a delivery to attempt now with the webhook it goes to
+type DueWebhookDelivery struct {
+	Delivery WebhookDelivery
+	Webhook  Webhook
+}
+
+type WebhookRepo interface {
+	Create(ctx context.Context, w Webhook) (string, error)
+	List(ctx context.Context) ([]Webhook, error)
+	Delete(ctx context.Context, id string) error
+	GetByEventType(ctx context.Context, t EventType) ([]Webhook, error)
+
+	CreateDelivery(ctx context.Context, d WebhookDelivery) (string, error)
+	GetDelivery(ctx context.Context, id string) (WebhookDelivery, error)
+	// GetDueDeliveries locks the pending deliveries due at the given time, it must run within a transaction
+	GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]DueWebhookDelivery, error)
+	UpdateDelivery(ctx context.Context, d WebhookDelivery) error
+	GetDeliveriesByStatus(ctx context.Context, webhookID string, status WebhookDeliveryStatus) ([]WebhookDelivery, error)
+}
+
+// WebhookSender makes the http call of a delivery, an error means the delivery has to be attempted again
+type WebhookSender interface {
+	Send(ctx context.Context, w Webhook, d WebhookDelivery) error
+}
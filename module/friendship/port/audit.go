@@ -0,0 +1,114 @@
+package port
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/port/constant"
+)
+
+type ListAuditLogReq struct {
+	User   string `form:"user"`
+	Action string `form:"action"`
+	From   string `form:"from"`
+	To     string `form:"to"`
+	PageReq
+}
+
+func (l ListAuditLogReq) Validate() error {
+	if l.Action != "" && !domain.AuditAction(l.Action).IsValid() {
+		return common.ErrInvalidRequest(domain.ErrAuditActionIsNotValid, constant.ACTION)
+	}
+	// the audit log is always the newest entries first, from and to bound it
+	if l.Sort != "" {
+		return common.ErrInvalidRequest(domain.ErrPageSortIsNotValid, constant.SORT)
+	}
+	if l.Since != "" {
+		return common.ErrInvalidRequest(domain.ErrAuditRangeIsNotValid, constant.SINCE)
+	}
+
+	return nil
+}
+
+func (l ListAuditLogReq) ToFilter() (domain.AuditFilter, error) {
+	filter := domain.AuditFilter{UserID: l.User, Action: domain.AuditAction(l.Action)}
+	var err error
+	if l.From != "" {
+		if filter.From, err = time.Parse(time.RFC3339, l.From); err != nil {
+			return domain.AuditFilter{}, common.ErrInvalidRequest(err, constant.FROM)
+		}
+	}
+	if l.To != "" {
+		if filter.To, err = time.Parse(time.RFC3339, l.To); err != nil {
+			return domain.AuditFilter{}, common.ErrInvalidRequest(err, constant.TO)
+		}
+	}
+	return filter, nil
+}
+
+type ListAuditLogRes struct {
+	Entries []domain.AuditEntry `json:"entries"`
+	Count   int                 `json:"count"`
+}
+
+// AuditLogFilter echoes the filter applied to the audit log
+type AuditLogFilter struct {
+	User   string     `json:"user,omitempty"`
+	Action string     `json:"action,omitempty"`
+	From   *time.Time `json:"from,omitempty"`
+	To     *time.Time `json:"to,omitempty"`
+}
+
+func (s *Server) ListAuditLog(c *gin.Context) {
+	var req ListAuditLogReq
+	var err error
+	if err = c.ShouldBindQuery(&req); err != nil {
+		logger.Error("ListAuditLog.ShouldBind: ", err)
+		common.HttpErrorHandler(c, common.ErrInvalidRequest(err, "query"))
+		return
+	}
+
+	if err = req.Validate(); err != nil {
+		logger.Error("ListAuditLog.Validate: ", err)
+		common.HttpErrorHandler(c, err)
+		return
+	}
+
+	filter, err := req.ToFilter()
+	if err != nil {
+		logger.Error("ListAuditLog.Validate: ", err)
+		common.HttpErrorHandler(c, err)
+		return
+	}
+
+	page, err := req.ToPage()
+	if err != nil {
+		logger.Error("ListAuditLog.Validate: ", err)
+		common.HttpErrorHandler(c, err)
+		return
+	}
+
+	entries, nextCursor, err := s.app.Queries.ListAuditLog.Handle(c.Request.Context(), filter, page)
+	if err != nil {
+		logger.Error("ListAuditLog.Handle: ", err)
+		common.HttpErrorHandler(c, err)
+		return
+	}
+
+	echo := AuditLogFilter{User: req.User, Action: req.Action}
+	if !filter.From.IsZero() {
+		echo.From = &filter.From
+	}
+	if !filter.To.IsZero() {
+		echo.To = &filter.To
+	}
+	c.JSON(http.StatusOK, common.PagingSuccessResponse(
+		ListAuditLogRes{Entries: entries, Count: len(entries)},
+		common.Paging{Limit: page.Limit, Cursor: page.Cursor, NextCursor: nextCursor},
+		echo,
+	))
+}
@@ -0,0 +1,135 @@
+package port
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/phantranhieunhan/s3-assignment/common"
+	mockHandler "github.com/phantranhieunhan/s3-assignment/mock/friendship/handler"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestCase_ListAuditLog struct {
+	name        string
+	hasFinalErr bool
+	query       string
+	filter      domain.AuditFilter
+	page        domain.Page
+
+	queryHandlerError error
+
+	hasValidateErr bool
+}
+
+func TestListAuditLog(t *testing.T) {
+	t.Parallel()
+
+	createdAt := time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+	from := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 3, 2, 0, 0, 0, 0, time.UTC)
+	entries := []domain.AuditEntry{
+		{
+			ID:           "audit-1",
+			Actor:        "user-1",
+			Action:       domain.AuditConnectFriendship,
+			TargetIDs:    []string{"user-1", "user-2"},
+			StatusBefore: domain.AuditStatusNone,
+			StatusAfter:  domain.AuditStatusFriended,
+			RequestID:    "request-1",
+			CreatedAt:    createdAt,
+		},
+	}
+	nextCursor := domain.EncodeCursor(domain.Cursor{Key: "audit-1", CreatedAt: createdAt})
+	defaultPage := domain.Page{Limit: domain.DefaultPageLimit, Sort: domain.PageSortEmail}
+	tcs := []TestCase_ListAuditLog{
+		{
+			name: "successful without filter",
+			page: defaultPage,
+		},
+		{
+			name:   "successful with filter and page",
+			query:  "user=john@example.com&action=ConnectFriendship&from=2023-03-01T00:00:00Z&to=2023-03-02T00:00:00Z&limit=1&cursor=" + nextCursor,
+			filter: domain.AuditFilter{UserID: "john@example.com", Action: domain.AuditConnectFriendship, From: from, To: to},
+			page:   domain.Page{Limit: 1, Sort: domain.PageSortEmail, Cursor: nextCursor},
+		},
+		{
+			name:           "fail because action invalid",
+			query:          "action=not-an-action",
+			hasValidateErr: true,
+			hasFinalErr:    true,
+		},
+		{
+			name:           "fail because from invalid",
+			query:          "from=yesterday",
+			hasValidateErr: true,
+			hasFinalErr:    true,
+		},
+		{
+			name:           "fail because to invalid",
+			query:          "to=2023-03-02",
+			hasValidateErr: true,
+			hasFinalErr:    true,
+		},
+		{
+			name:           "fail because sort is provided",
+			query:          "sort=email",
+			hasValidateErr: true,
+			hasFinalErr:    true,
+		},
+		{
+			name:           "fail because cursor invalid",
+			query:          "cursor=not-a-cursor",
+			hasValidateErr: true,
+			hasFinalErr:    true,
+		},
+		{
+			name:              "fail because query handle has error",
+			page:              defaultPage,
+			queryHandlerError: common.ErrInvalidRequest(errors.New("query handler error"), "user"),
+			hasFinalErr:       true,
+		},
+	}
+
+	for _, tc := range tcs {
+		mockListAuditLogHandler := new(mockHandler.MockListAuditLogHandler)
+		if !tc.hasValidateErr {
+			mockListAuditLogHandler.On("Handle", mock.Anything, tc.filter, tc.page).Once().Return(entries, nextCursor, tc.queryHandlerError)
+		}
+
+		server := NewServer(app.Application{
+			Queries: app.Queries{
+				ListAuditLog: mockListAuditLogHandler,
+			},
+		})
+		router := gin.Default()
+		router.GET("/test", server.ListAuditLog)
+
+		req, err := http.NewRequest("GET", "/test?"+tc.query, nil)
+		assert.NoError(t, err)
+		res := httptest.NewRecorder()
+		router.ServeHTTP(res, req)
+
+		if tc.hasFinalErr {
+			assert.Equal(t, http.StatusBadRequest, res.Code, tc.name)
+		} else {
+			assert.Equal(t, http.StatusOK, res.Code, tc.name)
+			resBody := &struct {
+				ListAuditLogRes
+				Paging common.Paging `json:"paging"`
+			}{}
+			err = json.Unmarshal(res.Body.Bytes(), resBody)
+			assert.NoError(t, err)
+			assert.Equal(t, ListAuditLogRes{Entries: entries, Count: len(entries)}, resBody.ListAuditLogRes)
+			assert.Equal(t, common.Paging{Limit: tc.page.Limit, Cursor: tc.page.Cursor, NextCursor: nextCursor}, resBody.Paging)
+		}
+		mock.AssertExpectationsForObjects(t, mockListAuditLogHandler)
+	}
+}
@@ -16,7 +16,7 @@ type BlockUpdatesUserReq struct {
 	Target    string `json:"target"`
 }
 
-func (l BlockUpdatesUserReq) validate() error {
+func (l BlockUpdatesUserReq) Validate() error {
 	if err := common.ValidateRequired(l.Requestor, constant.REQUESTOR); err != nil {
 		return err
 	}
@@ -44,7 +44,7 @@ func (s *Server) BlockUpdatesUser(c *gin.Context) {
 		return
 	}
 
-	if err = req.validate(); err != nil {
+	if err = req.Validate(); err != nil {
 		logger.Error("ListCommonFriends.Validate: ", err)
 		common.HttpErrorHandler(c, err)
 		return
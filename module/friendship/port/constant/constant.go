@@ -5,4 +5,29 @@ const (
 	FRIENDS   = "friends"
 	REQUESTOR = "requestor"
 	TARGET    = "target"
+	EMAIL     = "email"
+	PASSWORD  = "password"
+	LIMIT     = "limit"
+	FROM      = "from"
+	TO        = "to"
+	MAX_DEPTH = "max_depth"
+	OPERATION = "operation"
+	CURSOR    = "cursor"
+	SORT      = "sort"
+	SINCE     = "since"
+	SENDER    = "sender"
+	TEXT      = "text"
+	TYPE      = "type"
+	ACTION    = "action"
+	USER      = "user"
+	FORMAT    = "format"
+	OFFSET    = "offset"
+	HEADER    = "header"
+	ROW       = "row"
+
+	ID          = "id"
+	DELIVERY_ID = "delivery_id"
+	URL         = "url"
+	SECRET      = "secret"
+	EVENT_TYPES = "event_types"
 )
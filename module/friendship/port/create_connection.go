@@ -7,7 +7,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/phantranhieunhan/s3-assignment/common"
 	"github.com/phantranhieunhan/s3-assignment/common/logger"
-	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
 	"github.com/phantranhieunhan/s3-assignment/module/friendship/port/constant"
 )
 
@@ -15,7 +15,7 @@ type ConnectFriendshipReq struct {
 	Friends []string `json:"friends"`
 }
 
-func (c ConnectFriendshipReq) validate() error {
+func (c ConnectFriendshipReq) Validate() error {
 	if len(c.Friends) != 2 {
 		return common.ErrInvalidRequest(fmt.Errorf("friends must be of length 2"), constant.FRIENDS)
 	}
@@ -35,6 +35,16 @@ func (c ConnectFriendshipReq) validate() error {
 	return nil
 }
 
+// payload is the friend request the first of the friends sends to the second one
+func (c ConnectFriendshipReq) payload() payload.FriendRequestPayload {
+	return payload.FriendRequestPayload{
+		Requestor: c.Friends[0],
+		Target:    c.Friends[1],
+	}
+}
+
+// ConnectFriendship sends a friend request from the first of the friends to the second one,
+// they are friends once the second one accepts it
 func (s *Server) ConnectFriendship(c *gin.Context) {
 	var req ConnectFriendshipReq
 	var err error
@@ -44,36 +54,17 @@ func (s *Server) ConnectFriendship(c *gin.Context) {
 		return
 	}
 
-	if err = req.validate(); err != nil {
+	if err = req.Validate(); err != nil {
 		logger.Error("ConnectFriendship.Validate: ", err)
 		common.HttpErrorHandler(c, err)
 		return
 	}
 
-	f, err := s.app.Commands.ConnectFriendship.Handle(c.Request.Context(), req.Friends[0], req.Friends[1])
-	if err != nil {
-		logger.Error("ConnectFriendship.Handle: ", err)
+	if _, err = s.app.Commands.RequestFriendship.Handle(c.Request.Context(), req.payload()); err != nil {
+		logger.Error("ConnectFriendship.RequestFriendship.Handle: ", err)
 		common.HttpErrorHandler(c, err)
 		return
 	}
 
-	err = s.app.Commands.SubscribeUser.HandleWithSubscription(c.Request.Context(), domain.Subscriptions{
-		domain.Subscription{
-			UserID:       f.UserID,
-			SubscriberID: f.FriendID,
-		},
-		domain.Subscription{
-			UserID:       f.FriendID,
-			SubscriberID: f.UserID,
-		},
-	})
-	if err != nil {
-		if e, ok := err.(*common.AppError); !ok || e.RootError() != domain.ErrAlreadyExists {
-			logger.Error("ConnectFriendship.SubscribeUser.HandleWithSubscription: ", err)
-			common.HttpErrorHandler(c, err)
-			return
-		}
-	}
-
 	c.JSON(http.StatusOK, common.SimpleSuccessResponse(nil))
 }
@@ -11,6 +11,7 @@ import (
 	"github.com/phantranhieunhan/s3-assignment/common"
 	mockHandler "github.com/phantranhieunhan/s3-assignment/mock/friendship/handler"
 	"github.com/phantranhieunhan/s3-assignment/module/friendship/app"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
 	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
 
 	"github.com/gin-gonic/gin"
@@ -23,10 +24,7 @@ type TestCase_ConnectFriendship struct {
 	hasFinalErr bool
 	bodyRequest ConnectFriendshipReq
 
-	connectFriendshipHandlerError error
-	connectFriendshipData         domain.Friendship
-
-	subscribeUserHandlerError error
+	requestFriendshipHandlerError error
 
 	hasValidateErr bool
 }
@@ -34,12 +32,9 @@ type TestCase_ConnectFriendship struct {
 func TestConnectFriendship(t *testing.T) {
 	t.Parallel()
 
-	mockConnectFriendshipHandler := new(mockHandler.MockConnectFriendshipHandler)
-	mockSubscribeUserHandler := new(mockHandler.MockSubscribeUserHandler)
+	mockRequestFriendshipHandler := new(mockHandler.MockRequestFriendshipHandler)
 	commandHandlerErr := errors.New("command handler error")
 
-	appErrorExistsEmail := common.ErrInvalidRequest(domain.ErrAlreadyExists, "emails")
-
 	req := ConnectFriendshipReq{
 		Friends: []string{"lisa@example.com", "common@example.com"},
 	}
@@ -48,11 +43,6 @@ func TestConnectFriendship(t *testing.T) {
 			name:        "successful",
 			bodyRequest: req,
 		},
-		{
-			name:                      "successful",
-			bodyRequest:               req,
-			subscribeUserHandlerError: appErrorExistsEmail,
-		},
 		{
 			name: "fail because request emails is not 2",
 			bodyRequest: ConnectFriendshipReq{
@@ -78,46 +68,25 @@ func TestConnectFriendship(t *testing.T) {
 			hasFinalErr:    true,
 		},
 		{
-			name:                          "fail because connect friendship handle has error",
+			name:                          "fail because request friendship handle has error",
 			bodyRequest:                   req,
-			connectFriendshipHandlerError: commandHandlerErr,
+			requestFriendshipHandlerError: commandHandlerErr,
 			hasFinalErr:                   true,
 		},
-		{
-			name:        "fail because subscribe user handle handle has error",
-			bodyRequest: req,
-			connectFriendshipData: domain.Friendship{
-				UserID:   req.Friends[0],
-				FriendID: req.Friends[1],
-			},
-			subscribeUserHandlerError: commandHandlerErr,
-			hasFinalErr:               true,
-		},
 	}
 
 	for _, tc := range tcs {
 		dataReq := tc.bodyRequest
 		if !tc.hasValidateErr {
-			mockConnectFriendshipHandler.On("Handle", mock.Anything, dataReq.Friends[0], dataReq.Friends[1]).Once().Return(tc.connectFriendshipData, tc.connectFriendshipHandlerError)
-			if tc.connectFriendshipHandlerError == nil {
-				fr := tc.connectFriendshipData
-				mockSubscribeUserHandler.On("HandleWithSubscription", mock.Anything, domain.Subscriptions{
-					domain.Subscription{
-						UserID:       fr.UserID,
-						SubscriberID: fr.FriendID,
-					},
-					domain.Subscription{
-						UserID:       fr.FriendID,
-						SubscriberID: fr.UserID,
-					},
-				}).Once().Return(tc.subscribeUserHandlerError)
-			}
+			mockRequestFriendshipHandler.On("Handle", mock.Anything, payload.FriendRequestPayload{
+				Requestor: dataReq.Friends[0],
+				Target:    dataReq.Friends[1],
+			}).Once().Return(domain.Friendship{}, tc.requestFriendshipHandlerError)
 		}
 
 		server := NewServer(app.Application{
 			Commands: app.Commands{
-				ConnectFriendship: mockConnectFriendshipHandler,
-				SubscribeUser:     mockSubscribeUserHandler,
+				RequestFriendship: mockRequestFriendshipHandler,
 			},
 		})
 		router := gin.Default()
@@ -133,15 +102,15 @@ func TestConnectFriendship(t *testing.T) {
 		router.ServeHTTP(res, req)
 
 		if tc.hasFinalErr {
-			assert.Equal(t, http.StatusBadRequest, res.Code)
+			assert.Equal(t, http.StatusBadRequest, res.Code, tc.name)
 		} else {
-			assert.Equal(t, http.StatusOK, res.Code)
+			assert.Equal(t, http.StatusOK, res.Code, tc.name)
 			resBody := &common.SuccessRes{}
 			err = json.Unmarshal(res.Body.Bytes(), resBody)
 			assert.NoError(t, err)
 			assert.Equal(t, common.SimpleSuccessResponse(nil), resBody)
 		}
-		mock.AssertExpectationsForObjects(t, mockConnectFriendshipHandler, mockSubscribeUserHandler)
+		mock.AssertExpectationsForObjects(t, mockRequestFriendshipHandler)
 	}
 
 }
@@ -0,0 +1,246 @@
+package port
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+// exportSections are the arrays of an export in the order they are written, after the user
+var exportSections = []string{"friendships", "subscriptions", "blocks", "updates", "audit_log"}
+
+const (
+	exportFriendships = iota
+	exportSubscriptions
+	exportBlocks
+	exportUpdates
+	exportAuditLog
+)
+
+var exportFriendshipStatuses = map[domain.FriendshipStatus]string{
+	domain.FriendshipStatusFriended:   "FRIENDED",
+	domain.FriendshipStatusPending:    "PENDING",
+	domain.FriendshipStatusUnfriended: "UNFRIENDED",
+	domain.FriendshipStatusBlocked:    "BLOCKED",
+}
+
+var exportSubscriptionStatuses = map[domain.SubscriptionStatus]string{
+	domain.SubscriptionStatusSubscribed:   "SUBSCRIBED",
+	domain.SubscriptionStatusUnsubscribed: "UNSUBSCRIBED",
+}
+
+type ExportFriendshipRes struct {
+	ID        string    `json:"id"`
+	User      string    `json:"user"`
+	Friend    string    `json:"friend"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ExportSubscriptionRes struct {
+	ID         string    `json:"id"`
+	User       string    `json:"user"`
+	Subscriber string    `json:"subscriber"`
+	Status     string    `json:"status"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type ExportBlockRes struct {
+	ID        string    `json:"id"`
+	Target    string    `json:"target"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ExportUpdateRes struct {
+	ID        string    `json:"id"`
+	Text      string    `json:"text"`
+	Mentions  []string  `json:"mentions"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ExportUser sends everything held about the user as a json file to download. The file is written while
+// the rows are read, an export failing midway is cut short and cannot be parsed
+func (s *Server) ExportUser(c *gin.Context) {
+	email, ok := bindUserEmail(c, "ExportUser")
+	if !ok {
+		return
+	}
+
+	w := newUserExportWriter(c.Writer, email)
+	if err := s.app.Queries.ExportUser.Handle(c.Request.Context(), email, w); err != nil {
+		logger.Error("ExportUser.Handle: ", err)
+		if !w.started {
+			common.HttpErrorHandler(c, err)
+		}
+		return
+	}
+	if err := w.Close(); err != nil {
+		logger.Error("ExportUser.Close: ", err)
+	}
+}
+
+// userExportWriter writes the export as a single json object, each chunk of rows is flushed to the client
+// as soon as it is written. The response starts with the user, so that an export failing before it
+// still gets an error response
+type userExportWriter struct {
+	w        gin.ResponseWriter
+	filename string
+	started  bool
+	// section is the index of the array being written, -1 before the first one
+	section int
+	empty   bool
+}
+
+func newUserExportWriter(w gin.ResponseWriter, email string) *userExportWriter {
+	return &userExportWriter{
+		w:        w,
+		filename: fmt.Sprintf("export-%s.json", email),
+		section:  -1,
+	}
+}
+
+func (e *userExportWriter) WriteUser(u domain.User) error {
+	e.w.Header().Set("Content-Type", gin.MIMEJSON)
+	e.w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", e.filename))
+	e.w.WriteHeader(http.StatusOK)
+	e.started = true
+
+	b, err := json.Marshal(toUserRes(u))
+	if err != nil {
+		return err
+	}
+	exportedAt, err := json.Marshal(time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(e.w, `{"exported_at":%s,"user":%s`, exportedAt, b)
+	return err
+}
+
+func (e *userExportWriter) WriteFriendships(list []domain.ExportedFriendship) error {
+	rows := make([]interface{}, 0, len(list))
+	for _, f := range list {
+		rows = append(rows, ExportFriendshipRes{
+			ID:        f.Id,
+			User:      f.UserEmail,
+			Friend:    f.FriendEmail,
+			Status:    exportFriendshipStatuses[f.Status],
+			CreatedAt: f.CreatedAt,
+			UpdatedAt: f.UpdatedAt,
+		})
+	}
+	return e.writeRows(exportFriendships, rows)
+}
+
+func (e *userExportWriter) WriteSubscriptions(list []domain.ExportedSubscription) error {
+	rows := make([]interface{}, 0, len(list))
+	for _, s := range list {
+		rows = append(rows, ExportSubscriptionRes{
+			ID:         s.Id,
+			User:       s.UserEmail,
+			Subscriber: s.SubscriberEmail,
+			Status:     exportSubscriptionStatuses[s.Status],
+			CreatedAt:  s.CreatedAt,
+			UpdatedAt:  s.UpdatedAt,
+		})
+	}
+	return e.writeRows(exportSubscriptions, rows)
+}
+
+func (e *userExportWriter) WriteBlocks(list []domain.ExportedBlock) error {
+	rows := make([]interface{}, 0, len(list))
+	for _, b := range list {
+		rows = append(rows, ExportBlockRes{
+			ID:        b.Id,
+			Target:    b.TargetEmail,
+			CreatedAt: b.CreatedAt,
+			UpdatedAt: b.UpdatedAt,
+		})
+	}
+	return e.writeRows(exportBlocks, rows)
+}
+
+func (e *userExportWriter) WriteUpdates(list []domain.Update) error {
+	rows := make([]interface{}, 0, len(list))
+	for _, u := range list {
+		mentions := u.Mentions
+		if mentions == nil {
+			mentions = []string{}
+		}
+		rows = append(rows, ExportUpdateRes{
+			ID:        u.Id,
+			Text:      u.Text,
+			Mentions:  mentions,
+			CreatedAt: u.CreatedAt,
+		})
+	}
+	return e.writeRows(exportUpdates, rows)
+}
+
+// WriteAuditEntries writes the entries as the audit log lists them
+func (e *userExportWriter) WriteAuditEntries(list []domain.AuditEntry) error {
+	rows := make([]interface{}, 0, len(list))
+	for _, entry := range list {
+		rows = append(rows, entry)
+	}
+	return e.writeRows(exportAuditLog, rows)
+}
+
+// Close writes the sections the user has no row in as empty arrays and ends the object
+func (e *userExportWriter) Close() error {
+	if err := e.open(len(exportSections)); err != nil {
+		return err
+	}
+	_, err := e.w.WriteString("}")
+	e.w.Flush()
+	return err
+}
+
+func (e *userExportWriter) writeRows(section int, rows []interface{}) error {
+	if err := e.open(section); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		b, err := json.Marshal(row)
+		if err != nil {
+			return err
+		}
+		if !e.empty {
+			b = append([]byte(","), b...)
+		}
+		if _, err = e.w.Write(b); err != nil {
+			return err
+		}
+		e.empty = false
+	}
+	e.w.Flush()
+	return nil
+}
+
+// open closes the arrays up to the section and opens it, the sections skipped are written empty
+func (e *userExportWriter) open(section int) error {
+	for e.section < section {
+		s := ""
+		if e.section >= 0 {
+			s = "]"
+		}
+		e.section++
+		if e.section < len(exportSections) {
+			s += fmt.Sprintf(`,%q:[`, exportSections[e.section])
+		}
+		if _, err := e.w.WriteString(s); err != nil {
+			return err
+		}
+		e.empty = true
+	}
+	return nil
+}
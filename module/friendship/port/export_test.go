@@ -0,0 +1,130 @@
+package port
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/phantranhieunhan/s3-assignment/common"
+	mockHandler "github.com/phantranhieunhan/s3-assignment/mock/friendship/handler"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestCase_ExportUser struct {
+	name      string
+	pathEmail string
+
+	// write is what the handler writes to the export before it returns
+	write        func(w domain.UserExport)
+	handlerError error
+
+	statusCode int
+	validJSON  bool
+}
+
+func TestExportUser(t *testing.T) {
+	t.Parallel()
+
+	email := "lisa@example.com"
+	friendship := domain.ExportedFriendship{
+		Friendship:  domain.Friendship{Base: domain.Base{Id: "friendship-1"}, Status: domain.FriendshipStatusPending},
+		UserEmail:   email,
+		FriendEmail: "john@example.com",
+	}
+	block := domain.ExportedBlock{Block: domain.Block{Base: domain.Base{Id: "block-1"}}, TargetEmail: "kate@example.com"}
+	audit := domain.AuditEntry{ID: "audit-1", Actor: "user-1", Action: domain.AuditPostUpdate, TargetIDs: []string{"user-1"}}
+
+	tcs := []TestCase_ExportUser{
+		{
+			name:      "export successfully",
+			pathEmail: email,
+			write: func(w domain.UserExport) {
+				assert.NoError(t, w.WriteUser(testUser(email)))
+				assert.NoError(t, w.WriteFriendships([]domain.ExportedFriendship{friendship}))
+				assert.NoError(t, w.WriteFriendships([]domain.ExportedFriendship{friendship}))
+				assert.NoError(t, w.WriteBlocks([]domain.ExportedBlock{block}))
+				assert.NoError(t, w.WriteAuditEntries([]domain.AuditEntry{audit}))
+			},
+			statusCode: http.StatusOK,
+			validJSON:  true,
+		},
+		{
+			name:       "export fail because the email is not valid",
+			pathEmail:  "lisa",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:         "export fail before anything is written",
+			pathEmail:    email,
+			handlerError: common.ErrInvalidRequest(domain.ErrNotFoundUserByEmail, "email"),
+			statusCode:   http.StatusBadRequest,
+		},
+		{
+			name:      "export cut short by a failure midway",
+			pathEmail: email,
+			write: func(w domain.UserExport) {
+				assert.NoError(t, w.WriteUser(testUser(email)))
+				assert.NoError(t, w.WriteFriendships([]domain.ExportedFriendship{friendship}))
+			},
+			handlerError: errors.New("some error from db"),
+			statusCode:   http.StatusOK,
+		},
+	}
+
+	for _, tc := range tcs {
+		mockExportHandler := new(mockHandler.MockExportUserHandler)
+		if tc.statusCode == http.StatusOK || tc.handlerError != nil {
+			mockExportHandler.On("Handle", mock.Anything, tc.pathEmail, mock.Anything).Run(func(args mock.Arguments) {
+				if tc.write != nil {
+					tc.write(args[2].(domain.UserExport))
+				}
+			}).Return(tc.handlerError).Once()
+		}
+
+		server := NewServer(app.Application{
+			Queries: app.Queries{
+				ExportUser: mockExportHandler,
+			},
+		})
+		router := gin.Default()
+		router.GET("/users/:email/export", server.ExportUser)
+
+		req, err := http.NewRequest(http.MethodGet, "/users/"+tc.pathEmail+"/export", nil)
+		assert.NoError(t, err)
+		res := httptest.NewRecorder()
+		router.ServeHTTP(res, req)
+
+		assert.Equal(t, tc.statusCode, res.Code, tc.name)
+		if tc.validJSON {
+			var body struct {
+				User          UserRes                 `json:"user"`
+				Friendships   []ExportFriendshipRes   `json:"friendships"`
+				Subscriptions []ExportSubscriptionRes `json:"subscriptions"`
+				Blocks        []ExportBlockRes        `json:"blocks"`
+				Updates       []ExportUpdateRes       `json:"updates"`
+				AuditLog      []domain.AuditEntry     `json:"audit_log"`
+			}
+			assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &body), tc.name)
+			assert.Equal(t, email, body.User.Email, tc.name)
+			assert.Len(t, body.Friendships, 2, tc.name)
+			assert.Equal(t, "PENDING", body.Friendships[0].Status, tc.name)
+			// a section without rows is an empty array
+			assert.NotNil(t, body.Subscriptions, tc.name)
+			assert.Empty(t, body.Subscriptions, tc.name)
+			assert.Equal(t, []ExportBlockRes{{ID: "block-1", Target: "kate@example.com"}}, body.Blocks, tc.name)
+			assert.NotNil(t, body.Updates, tc.name)
+			assert.Empty(t, body.Updates, tc.name)
+			assert.Equal(t, []domain.AuditEntry{audit}, body.AuditLog, tc.name)
+			assert.Contains(t, res.Header().Get("Content-Disposition"), "attachment", tc.name)
+		} else if tc.statusCode == http.StatusOK {
+			assert.False(t, json.Valid(res.Body.Bytes()), tc.name)
+		}
+		mock.AssertExpectationsForObjects(t, mockExportHandler)
+	}
+}
@@ -0,0 +1,80 @@
+package port
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/port/constant"
+)
+
+const (
+	DefaultPathMaxDepth = 3
+	maxPathMaxDepth     = 6
+)
+
+type FindFriendshipPathReq struct {
+	From     string `form:"from"`
+	To       string `form:"to"`
+	MaxDepth int    `form:"max_depth"`
+}
+
+func (l FindFriendshipPathReq) Validate() error {
+	if err := common.ValidateRequired(l.From, constant.FROM); err != nil {
+		return err
+	}
+	if err := common.ValidateEmail(l.From); err != nil {
+		return err
+	}
+	if err := common.ValidateRequired(l.To, constant.TO); err != nil {
+		return err
+	}
+	if err := common.ValidateEmail(l.To); err != nil {
+		return err
+	}
+	if l.MaxDepth < 0 || l.MaxDepth > maxPathMaxDepth {
+		return common.ErrInvalidRequest(fmt.Errorf("max_depth must be between 1 and %d", maxPathMaxDepth), constant.MAX_DEPTH)
+	}
+
+	return nil
+}
+
+type FindFriendshipPathRes struct {
+	Connected bool     `json:"connected"`
+	Path      []string `json:"path"`
+	Degrees   int      `json:"degrees"`
+}
+
+func (s *Server) FindFriendshipPath(c *gin.Context) {
+	var req FindFriendshipPathReq
+	var err error
+	if err = c.ShouldBindQuery(&req); err != nil {
+		logger.Error("FindFriendshipPath.ShouldBind: ", err)
+		common.HttpErrorHandler(c, common.ErrInvalidRequest(err, "query"))
+		return
+	}
+
+	if err = req.Validate(); err != nil {
+		logger.Error("FindFriendshipPath.Validate: ", err)
+		common.HttpErrorHandler(c, err)
+		return
+	}
+	if req.MaxDepth == 0 {
+		req.MaxDepth = DefaultPathMaxDepth
+	}
+
+	path, err := s.app.Queries.FindFriendshipPath.Handle(c.Request.Context(), req.From, req.To, req.MaxDepth)
+	if err != nil {
+		logger.Error("FindFriendshipPath.Handle: ", err)
+		common.HttpErrorHandler(c, err)
+		return
+	}
+
+	res := FindFriendshipPathRes{Connected: len(path) > 0, Path: path}
+	if res.Connected {
+		res.Degrees = len(path) - 1
+	}
+	c.JSON(http.StatusOK, common.CustomSuccessResponse(res))
+}
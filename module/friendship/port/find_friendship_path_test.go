@@ -0,0 +1,107 @@
+package port
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	mockHandler "github.com/phantranhieunhan/s3-assignment/mock/friendship/handler"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestCase_FindFriendshipPath struct {
+	name             string
+	hasFinalErr      bool
+	query            string
+	expectedMaxDepth int
+
+	queryHandlerData  []string
+	queryHandlerError error
+
+	hasValidateErr bool
+	expectedRes    FindFriendshipPathRes
+}
+
+func TestFindFriendshipPath(t *testing.T) {
+	t.Parallel()
+
+	path := []string{"lisa@example.com", "andy@example.com", "john@example.com"}
+	tcs := []TestCase_FindFriendshipPath{
+		{
+			name:             "successful with default max depth",
+			query:            "from=lisa@example.com&to=john@example.com",
+			expectedMaxDepth: DefaultPathMaxDepth,
+			queryHandlerData: path,
+			expectedRes:      FindFriendshipPathRes{Connected: true, Path: path, Degrees: 2},
+		},
+		{
+			name:             "successful but not connected",
+			query:            "from=lisa@example.com&to=john@example.com&max_depth=1",
+			expectedMaxDepth: 1,
+			queryHandlerData: []string{},
+			expectedRes:      FindFriendshipPathRes{Connected: false, Path: []string{}},
+		},
+		{
+			name:           "fail because from is not provided",
+			query:          "to=john@example.com",
+			hasValidateErr: true,
+			hasFinalErr:    true,
+		},
+		{
+			name:           "fail because to invalid",
+			query:          "from=lisa@example.com&to=john-example.com",
+			hasValidateErr: true,
+			hasFinalErr:    true,
+		},
+		{
+			name:           "fail because max depth is too big",
+			query:          "from=lisa@example.com&to=john@example.com&max_depth=10",
+			hasValidateErr: true,
+			hasFinalErr:    true,
+		},
+		{
+			name:              "fail because query handle has error",
+			query:             "from=lisa@example.com&to=john@example.com",
+			expectedMaxDepth:  DefaultPathMaxDepth,
+			queryHandlerData:  []string{},
+			queryHandlerError: errors.New("query handler error"),
+			hasFinalErr:       true,
+		},
+	}
+
+	for _, tc := range tcs {
+		mockFindFriendshipPathHandler := new(mockHandler.MockFindFriendshipPathHandler)
+		if !tc.hasValidateErr {
+			mockFindFriendshipPathHandler.On("Handle", mock.Anything, "lisa@example.com", "john@example.com", tc.expectedMaxDepth).Once().Return(tc.queryHandlerData, tc.queryHandlerError)
+		}
+
+		server := NewServer(app.Application{
+			Queries: app.Queries{
+				FindFriendshipPath: mockFindFriendshipPathHandler,
+			},
+		})
+		router := gin.Default()
+		router.GET("/test", server.FindFriendshipPath)
+
+		req, err := http.NewRequest("GET", "/test?"+tc.query, nil)
+		assert.NoError(t, err)
+		res := httptest.NewRecorder()
+		router.ServeHTTP(res, req)
+
+		if tc.hasFinalErr {
+			assert.Equal(t, http.StatusBadRequest, res.Code)
+		} else {
+			assert.Equal(t, http.StatusOK, res.Code)
+			resBody := &FindFriendshipPathRes{}
+			err = json.Unmarshal(res.Body.Bytes(), resBody)
+			assert.NoError(t, err)
+			assert.Equal(t, &tc.expectedRes, resBody)
+		}
+		mock.AssertExpectationsForObjects(t, mockFindFriendshipPathHandler)
+	}
+}
@@ -0,0 +1,117 @@
+package port
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/port/constant"
+)
+
+type FriendRequestReq struct {
+	Requestor string `json:"requestor"`
+	Target    string `json:"target"`
+}
+
+func (l FriendRequestReq) Validate() error {
+	if err := common.ValidateRequired(l.Requestor, constant.REQUESTOR); err != nil {
+		return err
+	}
+	if err := common.ValidateEmail(l.Requestor); err != nil {
+		return err
+	}
+
+	if err := common.ValidateRequired(l.Target, constant.TARGET); err != nil {
+		return err
+	}
+	if err := common.ValidateEmail(l.Target); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (l FriendRequestReq) payload() payload.FriendRequestPayload {
+	return payload.FriendRequestPayload{
+		Requestor: l.Requestor,
+		Target:    l.Target,
+	}
+}
+
+func (s *Server) bindFriendRequest(c *gin.Context, name string) (FriendRequestReq, bool) {
+	var req FriendRequestReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error(name+".ShouldBind: ", err)
+		common.HttpErrorHandler(c, common.ErrInvalidRequest(err, "body data"))
+		return req, false
+	}
+
+	if err := req.Validate(); err != nil {
+		logger.Error(name+".Validate: ", err)
+		common.HttpErrorHandler(c, err)
+		return req, false
+	}
+	return req, true
+}
+
+func (s *Server) RequestFriendship(c *gin.Context) {
+	req, ok := s.bindFriendRequest(c, "RequestFriendship")
+	if !ok {
+		return
+	}
+
+	_, err := s.app.Commands.RequestFriendship.Handle(c.Request.Context(), req.payload())
+	if err != nil {
+		logger.Error("RequestFriendship.Handle: ", err)
+		common.HttpErrorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, common.SimpleSuccessResponse(nil))
+}
+
+func (s *Server) AcceptFriendship(c *gin.Context) {
+	req, ok := s.bindFriendRequest(c, "AcceptFriendship")
+	if !ok {
+		return
+	}
+
+	if _, err := s.app.Commands.AcceptFriendship.Handle(c.Request.Context(), req.payload()); err != nil {
+		logger.Error("AcceptFriendship.Handle: ", err)
+		common.HttpErrorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, common.SimpleSuccessResponse(nil))
+}
+
+func (s *Server) RejectFriendship(c *gin.Context) {
+	req, ok := s.bindFriendRequest(c, "RejectFriendship")
+	if !ok {
+		return
+	}
+
+	if err := s.app.Commands.RejectFriendship.Handle(c.Request.Context(), req.payload()); err != nil {
+		logger.Error("RejectFriendship.Handle: ", err)
+		common.HttpErrorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, common.SimpleSuccessResponse(nil))
+}
+
+func (s *Server) CancelFriendship(c *gin.Context) {
+	req, ok := s.bindFriendRequest(c, "CancelFriendship")
+	if !ok {
+		return
+	}
+
+	if err := s.app.Commands.CancelFriendship.Handle(c.Request.Context(), req.payload()); err != nil {
+		logger.Error("CancelFriendship.Handle: ", err)
+		common.HttpErrorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, common.SimpleSuccessResponse(nil))
+}
@@ -0,0 +1,180 @@
+package port
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/phantranhieunhan/s3-assignment/common"
+	mockHandler "github.com/phantranhieunhan/s3-assignment/mock/friendship/handler"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestCase_FriendRequest struct {
+	name        string
+	hasFinalErr bool
+	bodyRequest FriendRequestReq
+
+	commandHandlerError error
+
+	hasValidateErr bool
+}
+
+func friendRequestTestCases() []TestCase_FriendRequest {
+	commandHandlerErr := errors.New("command handler error")
+	return []TestCase_FriendRequest{
+		{
+			name: "successful",
+			bodyRequest: FriendRequestReq{
+				Requestor: "lisa@example.com",
+				Target:    "john@example.com",
+			},
+		},
+		{
+			name: "fail because target email is not provided",
+			bodyRequest: FriendRequestReq{
+				Requestor: "lisa@example.com",
+			},
+			hasValidateErr: true,
+			hasFinalErr:    true,
+		},
+		{
+			name: "fail because requestor email invalid",
+			bodyRequest: FriendRequestReq{
+				Requestor: "lisa-example.com",
+				Target:    "john@example.com",
+			},
+			hasValidateErr: true,
+			hasFinalErr:    true,
+		},
+		{
+			name: "fail because command handle has error",
+			bodyRequest: FriendRequestReq{
+				Requestor: "lisa@example.com",
+				Target:    "john@example.com",
+			},
+			commandHandlerError: commandHandlerErr,
+			hasFinalErr:         true,
+		},
+	}
+}
+
+func serveFriendRequest(t *testing.T, handler gin.HandlerFunc, tc TestCase_FriendRequest) {
+	router := gin.Default()
+	router.POST("/test", handler)
+
+	jsonBody, err := json.Marshal(tc.bodyRequest)
+	assert.NoError(t, err)
+
+	req, err := http.NewRequest("POST", "/test", bytes.NewBuffer(jsonBody))
+	assert.NoError(t, err)
+	res := httptest.NewRecorder()
+	router.ServeHTTP(res, req)
+
+	if tc.hasFinalErr {
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+	} else {
+		assert.Equal(t, http.StatusOK, res.Code)
+		resBody := &common.SuccessRes{}
+		err = json.Unmarshal(res.Body.Bytes(), resBody)
+		assert.NoError(t, err)
+		assert.Equal(t, common.SimpleSuccessResponse(nil), resBody)
+	}
+}
+
+func TestRequestFriendship(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range friendRequestTestCases() {
+		mockRequestFriendshipHandler := new(mockHandler.MockRequestFriendshipHandler)
+		if !tc.hasValidateErr {
+			mockRequestFriendshipHandler.On("Handle", mock.Anything, payload.FriendRequestPayload{
+				Requestor: tc.bodyRequest.Requestor,
+				Target:    tc.bodyRequest.Target,
+			}).Once().Return(domain.Friendship{}, tc.commandHandlerError)
+		}
+
+		server := NewServer(app.Application{
+			Commands: app.Commands{
+				RequestFriendship: mockRequestFriendshipHandler,
+			},
+		})
+		serveFriendRequest(t, server.RequestFriendship, tc)
+		mock.AssertExpectationsForObjects(t, mockRequestFriendshipHandler)
+	}
+}
+
+func TestAcceptFriendship(t *testing.T) {
+	t.Parallel()
+
+	f := domain.Friendship{UserID: "john-id", FriendID: "lisa-id", Status: domain.FriendshipStatusFriended}
+	for _, tc := range friendRequestTestCases() {
+		mockAcceptFriendshipHandler := new(mockHandler.MockAcceptFriendshipHandler)
+		if !tc.hasValidateErr {
+			mockAcceptFriendshipHandler.On("Handle", mock.Anything, payload.FriendRequestPayload{
+				Requestor: tc.bodyRequest.Requestor,
+				Target:    tc.bodyRequest.Target,
+			}).Once().Return(f, tc.commandHandlerError)
+		}
+
+		server := NewServer(app.Application{
+			Commands: app.Commands{
+				AcceptFriendship: mockAcceptFriendshipHandler,
+			},
+		})
+		serveFriendRequest(t, server.AcceptFriendship, tc)
+		mock.AssertExpectationsForObjects(t, mockAcceptFriendshipHandler)
+	}
+}
+
+func TestRejectFriendship(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range friendRequestTestCases() {
+		mockRejectFriendshipHandler := new(mockHandler.MockRejectFriendshipHandler)
+		if !tc.hasValidateErr {
+			mockRejectFriendshipHandler.On("Handle", mock.Anything, payload.FriendRequestPayload{
+				Requestor: tc.bodyRequest.Requestor,
+				Target:    tc.bodyRequest.Target,
+			}).Once().Return(tc.commandHandlerError)
+		}
+
+		server := NewServer(app.Application{
+			Commands: app.Commands{
+				RejectFriendship: mockRejectFriendshipHandler,
+			},
+		})
+		serveFriendRequest(t, server.RejectFriendship, tc)
+		mock.AssertExpectationsForObjects(t, mockRejectFriendshipHandler)
+	}
+}
+
+func TestCancelFriendship(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range friendRequestTestCases() {
+		mockCancelFriendshipHandler := new(mockHandler.MockCancelFriendshipHandler)
+		if !tc.hasValidateErr {
+			mockCancelFriendshipHandler.On("Handle", mock.Anything, payload.FriendRequestPayload{
+				Requestor: tc.bodyRequest.Requestor,
+				Target:    tc.bodyRequest.Target,
+			}).Once().Return(tc.commandHandlerError)
+		}
+
+		server := NewServer(app.Application{
+			Commands: app.Commands{
+				CancelFriendship: mockCancelFriendshipHandler,
+			},
+		})
+		serveFriendRequest(t, server.CancelFriendship, tc)
+		mock.AssertExpectationsForObjects(t, mockCancelFriendshipHandler)
+	}
+}
@@ -0,0 +1,52 @@
+package port
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/port/constant"
+)
+
+type GetFeedRes struct {
+	Updates []domain.FeedItem `json:"updates"`
+	Count   int               `json:"count"`
+}
+
+// FeedPage sorts the page the way the feed is, the newest updates first, the requests cannot choose another sort
+func FeedPage(sort string, page domain.Page) (domain.Page, error) {
+	if sort != "" {
+		return domain.Page{}, common.ErrInvalidRequest(domain.ErrPageSortIsNotValid, constant.SORT)
+	}
+	page.Sort = domain.PageSortCreatedAt
+	return page, nil
+}
+
+// GetFeed lists the updates received by the authenticated user
+func (s *Server) GetFeed(c *gin.Context) {
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	page, err := bindPage(c)
+	if err == nil {
+		page, err = FeedPage(c.Query(constant.SORT), page)
+	}
+	if err != nil {
+		logger.Error("GetFeed.Validate: ", err)
+		common.HttpErrorHandler(c, err)
+		return
+	}
+
+	feed, nextCursor, err := s.app.Queries.GetFeed.Handle(c.Request.Context(), userID, page)
+	if err != nil {
+		logger.Error("GetFeed.Handle: ", err)
+		common.HttpErrorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, pageResponse(GetFeedRes{Updates: feed, Count: len(feed)}, page, nextCursor))
+}
@@ -0,0 +1,125 @@
+package port
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/auth"
+	mockHandler "github.com/phantranhieunhan/s3-assignment/mock/friendship/handler"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestCase_GetFeed struct {
+	name        string
+	hasFinalErr bool
+	query       string
+	userID      string
+	page        domain.Page
+
+	queryHandlerError error
+
+	hasValidateErr bool
+	code           int
+}
+
+func TestGetFeed(t *testing.T) {
+	t.Parallel()
+
+	createdAt := time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+	feed := []domain.FeedItem{
+		{ID: "update-1", Sender: "john@example.com", Text: "hello", CreatedAt: createdAt},
+	}
+	nextCursor := domain.EncodeCursor(domain.Cursor{Key: "update-1", CreatedAt: createdAt})
+	tcs := []TestCase_GetFeed{
+		{
+			name:   "successful with default page",
+			userID: "user-1",
+			page:   domain.Page{Limit: domain.DefaultPageLimit, Sort: domain.PageSortCreatedAt},
+		},
+		{
+			name:   "successful with page",
+			query:  "limit=1&cursor=" + nextCursor,
+			userID: "user-1",
+			page:   domain.Page{Limit: 1, Sort: domain.PageSortCreatedAt, Cursor: nextCursor},
+		},
+		{
+			name:           "fail because user is not authenticated",
+			query:          "limit=1",
+			hasValidateErr: true,
+			hasFinalErr:    true,
+			code:           http.StatusUnauthorized,
+		},
+		{
+			name:           "fail because sort is provided",
+			query:          "sort=email",
+			userID:         "user-1",
+			hasValidateErr: true,
+			hasFinalErr:    true,
+			code:           http.StatusBadRequest,
+		},
+		{
+			name:           "fail because cursor invalid",
+			query:          "cursor=not-a-cursor",
+			userID:         "user-1",
+			hasValidateErr: true,
+			hasFinalErr:    true,
+			code:           http.StatusBadRequest,
+		},
+		{
+			name:              "fail because query handle has error",
+			userID:            "user-1",
+			page:              domain.Page{Limit: domain.DefaultPageLimit, Sort: domain.PageSortCreatedAt},
+			queryHandlerError: common.ErrInvalidRequest(domain.ErrCursorIsNotValid, "cursor"),
+			hasFinalErr:       true,
+			code:              http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tcs {
+		mockGetFeedHandler := new(mockHandler.MockGetFeedHandler)
+		if !tc.hasValidateErr {
+			mockGetFeedHandler.On("Handle", mock.Anything, tc.userID, tc.page).Once().Return(feed, nextCursor, tc.queryHandlerError)
+		}
+
+		server := NewServer(app.Application{
+			Queries: app.Queries{
+				GetFeed: mockGetFeedHandler,
+			},
+		})
+		router := gin.Default()
+		userID := tc.userID
+		router.GET("/test", func(c *gin.Context) {
+			if userID != "" {
+				c.Request = c.Request.WithContext(auth.WithUserID(c.Request.Context(), userID))
+			}
+		}, server.GetFeed)
+
+		req, err := http.NewRequest("GET", "/test?"+tc.query, nil)
+		assert.NoError(t, err)
+		res := httptest.NewRecorder()
+		router.ServeHTTP(res, req)
+
+		if tc.hasFinalErr {
+			assert.Equal(t, tc.code, res.Code, tc.name)
+		} else {
+			assert.Equal(t, http.StatusOK, res.Code)
+			resBody := &struct {
+				GetFeedRes
+				Paging common.Paging `json:"paging"`
+			}{}
+			err = json.Unmarshal(res.Body.Bytes(), resBody)
+			assert.NoError(t, err)
+			assert.Equal(t, GetFeedRes{Updates: feed, Count: len(feed)}, resBody.GetFeedRes)
+			assert.Equal(t, common.Paging{Limit: tc.page.Limit, Cursor: tc.page.Cursor, NextCursor: nextCursor}, resBody.Paging)
+		}
+		mock.AssertExpectationsForObjects(t, mockGetFeedHandler)
+	}
+}
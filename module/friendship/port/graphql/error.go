@@ -0,0 +1,43 @@
+package graphql
+
+import (
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/constant"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/pkg/config"
+)
+
+// resolverError is an application error in the errors of a graphql response,
+// the extensions carry the key and the status code the http api would answer
+type resolverError struct {
+	appErr *common.AppError
+}
+
+func (e resolverError) Error() string {
+	return e.appErr.Message
+}
+
+func (e resolverError) Extensions() map[string]interface{} {
+	ext := map[string]interface{}{
+		"error_key":   e.appErr.Key,
+		"status_code": e.appErr.StatusCode,
+	}
+	if config.C.Env != constant.PRODUCTION_ENV_NAME && e.appErr.Log != "" {
+		ext["log"] = e.appErr.Log
+	}
+	return ext
+}
+
+// toError logs the error of a resolver and turns it into a graphql error
+func toError(name string, err error) error {
+	if err == nil {
+		return nil
+	}
+	logger.Error(name+": ", err)
+
+	appErr, ok := err.(*common.AppError)
+	if !ok {
+		appErr = common.ErrInternal(err)
+	}
+	return resolverError{appErr: appErr}
+}
@@ -0,0 +1,147 @@
+package graphql
+
+import (
+	"context"
+
+	graphqlgo "github.com/graph-gophers/graphql-go"
+
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+var friendshipStatuses = map[domain.FriendshipStatus]string{
+	domain.FriendshipStatusFriended:   "FRIENDED",
+	domain.FriendshipStatusPending:    "PENDING",
+	domain.FriendshipStatusUnfriended: "UNFRIENDED",
+	domain.FriendshipStatusBlocked:    "BLOCKED",
+}
+
+var subscriptionStatuses = map[domain.SubscriptionStatus]string{
+	domain.SubscriptionStatusInvalid:      "NONE",
+	domain.SubscriptionStatusSubscribed:   "SUBSCRIBED",
+	domain.SubscriptionStatusUnsubscribed: "UNSUBSCRIBED",
+}
+
+// friendshipResolver resolves a friendship row, both users are loaded in batch from their ids
+type friendshipResolver struct {
+	app app.Application
+	f   domain.Friendship
+}
+
+func newFriendship(app app.Application, f domain.Friendship) *friendshipResolver {
+	return &friendshipResolver{app: app, f: f}
+}
+
+// getFriendship resolves the friendship between the two users, null when they never connected
+func getFriendship(ctx context.Context, app app.Application, userEmail, friendEmail string) (*friendshipResolver, error) {
+	f, err := app.Queries.GetFriendship.Handle(ctx, userEmail, friendEmail)
+	if err != nil {
+		return nil, toError("friendship", err)
+	}
+	if f.Base.Id == "" {
+		return nil, nil
+	}
+
+	return newFriendship(app, f), nil
+}
+
+func (r *friendshipResolver) ID() graphqlgo.ID {
+	return graphqlgo.ID(r.f.Base.Id)
+}
+
+func (r *friendshipResolver) User() *userResolver {
+	return newUserByID(r.app, r.f.UserID)
+}
+
+func (r *friendshipResolver) Friend() *userResolver {
+	return newUserByID(r.app, r.f.FriendID)
+}
+
+func (r *friendshipResolver) Status() string {
+	return friendshipStatuses[r.f.Status]
+}
+
+func (r *friendshipResolver) CreatedAt() *graphqlgo.Time {
+	return toTime(r.f.Base.CreatedAt)
+}
+
+func (r *friendshipResolver) UpdatedAt() *graphqlgo.Time {
+	return toTime(r.f.Base.UpdatedAt)
+}
+
+// subscriptionResolver resolves the subscription of a subscriber to a target, a missing row has the status NONE
+type subscriptionResolver struct {
+	subscriber *userResolver
+	target     *userResolver
+	s          domain.Subscription
+}
+
+// getSubscription resolves the subscription of the subscriber to the updates of the target
+func getSubscription(ctx context.Context, app app.Application, subscriberEmail, targetEmail string) (*subscriptionResolver, error) {
+	s, err := app.Queries.GetSubscription.Handle(ctx, subscriberEmail, targetEmail)
+	if err != nil {
+		return nil, toError("subscription", err)
+	}
+
+	return &subscriptionResolver{
+		subscriber: newUserByEmail(app, subscriberEmail),
+		target:     newUserByEmail(app, targetEmail),
+		s:          s,
+	}, nil
+}
+
+func (r *subscriptionResolver) Subscriber() *userResolver {
+	return r.subscriber
+}
+
+func (r *subscriptionResolver) Target() *userResolver {
+	return r.target
+}
+
+func (r *subscriptionResolver) Status() string {
+	return subscriptionStatuses[r.s.Status]
+}
+
+func (r *subscriptionResolver) CreatedAt() *graphqlgo.Time {
+	return toTime(r.s.Base.CreatedAt)
+}
+
+type updateResolver struct {
+	sender *userResolver
+	u      domain.Update
+}
+
+func (r *updateResolver) ID() graphqlgo.ID {
+	return graphqlgo.ID(r.u.Base.Id)
+}
+
+func (r *updateResolver) Sender() *userResolver {
+	return r.sender
+}
+
+func (r *updateResolver) Text() string {
+	return r.u.Text
+}
+
+func (r *updateResolver) Mentions() []string {
+	if r.u.Mentions == nil {
+		return []string{}
+	}
+	return r.u.Mentions
+}
+
+func (r *updateResolver) CreatedAt() graphqlgo.Time {
+	return graphqlgo.Time{Time: r.u.Base.CreatedAt}
+}
+
+type authTokenResolver struct {
+	token domain.AuthToken
+}
+
+func (r *authTokenResolver) Token() string {
+	return r.token.Token
+}
+
+func (r *authTokenResolver) ExpiresAt() graphqlgo.Time {
+	return graphqlgo.Time{Time: r.token.ExpiresAt}
+}
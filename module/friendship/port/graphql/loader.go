@@ -0,0 +1,69 @@
+package graphql
+
+import (
+	"context"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/phantranhieunhan/s3-assignment/pkg/dataloader"
+)
+
+const (
+	// loaderWait is how long a loader collects the keys of the sibling fields before fetching them
+	loaderWait = 2 * time.Millisecond
+	// loaderMaxBatch keeps the IN clause of a batch small
+	loaderMaxBatch = 100
+)
+
+type loadersKey struct{}
+
+// loaders are the batched lookups of a request, the users of a list are resolved with one query instead of one per user
+type loaders struct {
+	emailsByUserID *dataloader.Loader[string, string]
+	userIDsByEmail *dataloader.Loader[string, string]
+}
+
+func newLoaders(app app.Application) *loaders {
+	return &loaders{
+		emailsByUserID: dataloader.New(func(ctx context.Context, ids []string) (map[string]string, error) {
+			return app.Queries.ListUserEmails.Handle(ctx, ids)
+		}, loaderWait, loaderMaxBatch),
+		userIDsByEmail: dataloader.New(func(ctx context.Context, emails []string) (map[string]string, error) {
+			return app.Queries.ListUserIDs.Handle(ctx, emails)
+		}, loaderWait, loaderMaxBatch),
+	}
+}
+
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
+
+// loadEmail returns the email of the user id, batched with the other ids resolved at the same time
+func loadEmail(ctx context.Context, userID string) (string, error) {
+	email, found, err := loadersFrom(ctx).emailsByUserID.Load(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return "", common.ErrInvalidRequest(domain.ErrNotFoundUserByEmail, "user_id")
+	}
+	return email, nil
+}
+
+// loadUserID returns the id of the user of the email, batched with the other emails resolved at the same time
+func loadUserID(ctx context.Context, email string) (string, error) {
+	id, found, err := loadersFrom(ctx).userIDsByEmail.Load(ctx, email)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return "", common.ErrInvalidRequest(domain.ErrNotFoundUserByEmail, "email")
+	}
+	return id, nil
+}
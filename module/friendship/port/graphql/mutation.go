@@ -0,0 +1,288 @@
+package graphql
+
+import (
+	"context"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/auth"
+	"github.com/phantranhieunhan/s3-assignment/middleware"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/port"
+)
+
+type relationArgs struct {
+	Requestor string
+	Target    string
+}
+
+// authenticated rejects the mutations acting on behalf of a requestor sent without a token,
+// the same as the authenticate middleware of the http routes
+func authenticated(ctx context.Context) error {
+	if _, ok := auth.UserIDFromContext(ctx); !ok {
+		return common.NewUnauthorized(middleware.ErrMissingToken, middleware.ErrMissingToken.Error(), "ErrUnauthorized")
+	}
+	return nil
+}
+
+func (r *Resolver) Login(ctx context.Context, args struct {
+	Email    string
+	Password string
+}) (*authTokenResolver, error) {
+	if err := (port.LoginReq{Email: args.Email, Password: args.Password}).Validate(); err != nil {
+		return nil, toError("login", err)
+	}
+
+	token, err := r.app.Commands.Login.Handle(ctx, payload.LoginPayload{
+		Email:    args.Email,
+		Password: args.Password,
+	})
+	if err != nil {
+		return nil, toError("login", err)
+	}
+
+	markNoStore(ctx)
+	return &authTokenResolver{token: token}, nil
+}
+
+func (r *Resolver) CreateUser(ctx context.Context, args struct {
+	Email    string
+	Password string
+}) (*userResolver, error) {
+	if err := (port.CreateUserReq{Email: args.Email, Password: args.Password}).Validate(); err != nil {
+		return nil, toError("createUser", err)
+	}
+
+	user, err := r.app.Commands.CreateUser.Handle(ctx, payload.CreateUserPayload{
+		Email:    args.Email,
+		Password: args.Password,
+	})
+	if err != nil {
+		return nil, toError("createUser", err)
+	}
+
+	return newUser(r.app, user), nil
+}
+
+func (r *Resolver) UpdateUser(ctx context.Context, args struct {
+	Email    string
+	NewEmail string
+}) (*userResolver, error) {
+	if err := authenticated(ctx); err != nil {
+		return nil, toError("updateUser", err)
+	}
+	if err := (port.UserReq{Email: args.Email}).Validate(); err != nil {
+		return nil, toError("updateUser", err)
+	}
+	if err := (port.UserReq{Email: args.NewEmail}).Validate(); err != nil {
+		return nil, toError("updateUser", err)
+	}
+
+	user, err := r.app.Commands.UpdateUser.Handle(ctx, payload.UpdateUserPayload{
+		Email:    args.Email,
+		NewEmail: args.NewEmail,
+	})
+	if err != nil {
+		return nil, toError("updateUser", err)
+	}
+
+	return newUser(r.app, user), nil
+}
+
+func (r *Resolver) DeleteUser(ctx context.Context, args struct{ Email string }) (bool, error) {
+	if err := authenticated(ctx); err != nil {
+		return false, toError("deleteUser", err)
+	}
+	if err := (port.UserReq{Email: args.Email}).Validate(); err != nil {
+		return false, toError("deleteUser", err)
+	}
+
+	if _, err := r.app.Commands.DeleteAccount.Handle(ctx, args.Email); err != nil {
+		return false, toError("deleteUser", err)
+	}
+
+	return true, nil
+}
+
+func (r *Resolver) ConnectFriendship(ctx context.Context, args struct{ Friends []string }) (*friendshipResolver, error) {
+	if err := authenticated(ctx); err != nil {
+		return nil, toError("connectFriendship", err)
+	}
+	if err := (port.ConnectFriendshipReq{Friends: args.Friends}).Validate(); err != nil {
+		return nil, toError("connectFriendship", err)
+	}
+
+	// the first of the friends sends a friend request to the second one
+	f, err := r.app.Commands.RequestFriendship.Handle(ctx, payload.FriendRequestPayload{
+		Requestor: args.Friends[0],
+		Target:    args.Friends[1],
+	})
+	if err != nil {
+		return nil, toError("connectFriendship", err)
+	}
+
+	return newFriendship(r.app, f), nil
+}
+
+func friendRequestPayload(ctx context.Context, args relationArgs) (payload.FriendRequestPayload, error) {
+	if err := authenticated(ctx); err != nil {
+		return payload.FriendRequestPayload{}, err
+	}
+	if err := (port.FriendRequestReq{Requestor: args.Requestor, Target: args.Target}).Validate(); err != nil {
+		return payload.FriendRequestPayload{}, err
+	}
+	return payload.FriendRequestPayload{Requestor: args.Requestor, Target: args.Target}, nil
+}
+
+func (r *Resolver) RequestFriendship(ctx context.Context, args relationArgs) (*friendshipResolver, error) {
+	p, err := friendRequestPayload(ctx, args)
+	if err != nil {
+		return nil, toError("requestFriendship", err)
+	}
+
+	f, err := r.app.Commands.RequestFriendship.Handle(ctx, p)
+	if err != nil {
+		return nil, toError("requestFriendship", err)
+	}
+
+	return newFriendship(r.app, f), nil
+}
+
+func (r *Resolver) AcceptFriendship(ctx context.Context, args relationArgs) (*friendshipResolver, error) {
+	p, err := friendRequestPayload(ctx, args)
+	if err != nil {
+		return nil, toError("acceptFriendship", err)
+	}
+
+	f, err := r.app.Commands.AcceptFriendship.Handle(ctx, p)
+	if err != nil {
+		return nil, toError("acceptFriendship", err)
+	}
+
+	return newFriendship(r.app, f), nil
+}
+
+func (r *Resolver) RejectFriendship(ctx context.Context, args relationArgs) (bool, error) {
+	p, err := friendRequestPayload(ctx, args)
+	if err != nil {
+		return false, toError("rejectFriendship", err)
+	}
+
+	if err = r.app.Commands.RejectFriendship.Handle(ctx, p); err != nil {
+		return false, toError("rejectFriendship", err)
+	}
+
+	return true, nil
+}
+
+func (r *Resolver) CancelFriendship(ctx context.Context, args relationArgs) (bool, error) {
+	p, err := friendRequestPayload(ctx, args)
+	if err != nil {
+		return false, toError("cancelFriendship", err)
+	}
+
+	if err = r.app.Commands.CancelFriendship.Handle(ctx, p); err != nil {
+		return false, toError("cancelFriendship", err)
+	}
+
+	return true, nil
+}
+
+func (r *Resolver) Unfriend(ctx context.Context, args relationArgs) (bool, error) {
+	if err := authenticated(ctx); err != nil {
+		return false, toError("unfriend", err)
+	}
+	if err := (port.UnfriendReq{Requestor: args.Requestor, Target: args.Target}).Validate(); err != nil {
+		return false, toError("unfriend", err)
+	}
+
+	err := r.app.Commands.Unfriend.Handle(ctx, payload.UnfriendPayload{
+		Requestor: args.Requestor,
+		Target:    args.Target,
+	})
+	if err != nil {
+		return false, toError("unfriend", err)
+	}
+
+	return true, nil
+}
+
+func (r *Resolver) Subscribe(ctx context.Context, args relationArgs) (*subscriptionResolver, error) {
+	if err := authenticated(ctx); err != nil {
+		return nil, toError("subscribe", err)
+	}
+	if err := (port.SubscribeUserReq{Requestor: args.Requestor, Target: args.Target}).Validate(); err != nil {
+		return nil, toError("subscribe", err)
+	}
+
+	err := r.app.Commands.SubscribeUser.Handle(ctx, payload.SubscriberUserPayloads{
+		payload.SubscriberUserPayload{
+			Requestor: args.Requestor,
+			Target:    args.Target,
+		},
+	})
+	if err != nil {
+		return nil, toError("subscribe", err)
+	}
+
+	return getSubscription(ctx, r.app, args.Requestor, args.Target)
+}
+
+func (r *Resolver) Block(ctx context.Context, args relationArgs) (bool, error) {
+	if err := authenticated(ctx); err != nil {
+		return false, toError("block", err)
+	}
+	if err := (port.BlockUpdatesUserReq{Requestor: args.Requestor, Target: args.Target}).Validate(); err != nil {
+		return false, toError("block", err)
+	}
+
+	err := r.app.Commands.BlockUpdatesUser.Handle(ctx, payload.BlockUpdatesUserPayload{
+		Requestor: args.Requestor,
+		Target:    args.Target,
+	})
+	if err != nil {
+		return false, toError("block", err)
+	}
+
+	return true, nil
+}
+
+func (r *Resolver) Unblock(ctx context.Context, args relationArgs) (bool, error) {
+	if err := authenticated(ctx); err != nil {
+		return false, toError("unblock", err)
+	}
+	if err := (port.UnblockUserReq{Requestor: args.Requestor, Target: args.Target}).Validate(); err != nil {
+		return false, toError("unblock", err)
+	}
+
+	err := r.app.Commands.UnblockUser.Handle(ctx, payload.UnblockUserPayload{
+		Requestor: args.Requestor,
+		Target:    args.Target,
+	})
+	if err != nil {
+		return false, toError("unblock", err)
+	}
+
+	return true, nil
+}
+
+func (r *Resolver) PostUpdate(ctx context.Context, args struct {
+	Sender string
+	Text   string
+}) (*updateResolver, error) {
+	if err := authenticated(ctx); err != nil {
+		return nil, toError("postUpdate", err)
+	}
+	if err := (port.PostUpdateReq{Sender: args.Sender, Text: args.Text}).Validate(); err != nil {
+		return nil, toError("postUpdate", err)
+	}
+
+	update, err := r.app.Commands.PostUpdate.Handle(ctx, payload.PostUpdatePayload{
+		Sender: args.Sender,
+		Text:   args.Text,
+	})
+	if err != nil {
+		return nil, toError("postUpdate", err)
+	}
+
+	return &updateResolver{sender: newUserByEmail(r.app, args.Sender), u: update}, nil
+}
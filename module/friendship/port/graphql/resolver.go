@@ -0,0 +1,61 @@
+package graphql
+
+import (
+	"context"
+	"time"
+
+	graphqlgo "github.com/graph-gophers/graphql-go"
+
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/port"
+)
+
+// Resolver is the root of the query and the mutation types
+type Resolver struct {
+	app app.Application
+}
+
+func (r *Resolver) User(ctx context.Context, args struct{ Email string }) (*userResolver, error) {
+	if err := (port.UserReq{Email: args.Email}).Validate(); err != nil {
+		return nil, toError("user", err)
+	}
+
+	user, err := r.app.Queries.GetUser.Handle(ctx, args.Email)
+	if err != nil {
+		if isUserNotFound(err) {
+			return nil, nil
+		}
+		return nil, toError("user", err)
+	}
+
+	return newUser(r.app, user), nil
+}
+
+func (r *Resolver) Friendship(ctx context.Context, args struct {
+	User   string
+	Friend string
+}) (*friendshipResolver, error) {
+	if err := (port.ConnectFriendshipReq{Friends: []string{args.User, args.Friend}}).Validate(); err != nil {
+		return nil, toError("friendship", err)
+	}
+
+	return getFriendship(ctx, r.app, args.User, args.Friend)
+}
+
+func (r *Resolver) Subscription(ctx context.Context, args struct {
+	Subscriber string
+	Target     string
+}) (*subscriptionResolver, error) {
+	if err := (port.SubscribeUserReq{Requestor: args.Subscriber, Target: args.Target}).Validate(); err != nil {
+		return nil, toError("subscription", err)
+	}
+
+	return getSubscription(ctx, r.app, args.Subscriber, args.Target)
+}
+
+func toTime(t time.Time) *graphqlgo.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &graphqlgo.Time{Time: t}
+}
@@ -0,0 +1,90 @@
+package graphql
+
+import (
+	"context"
+	_ "embed"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+	graphqlgo "github.com/graph-gophers/graphql-go"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/auth"
+	"github.com/phantranhieunhan/s3-assignment/middleware"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/port"
+	"github.com/phantranhieunhan/s3-assignment/pkg/config"
+)
+
+const (
+	// maxDepth bounds the nesting of a query, a user has friends who have friends and so on
+	maxDepth = 8
+	// maxParallelism bounds the fields of a request resolved at the same time
+	maxParallelism = 20
+)
+
+//go:embed schema.graphql
+var schema string
+
+// Server serves the application over graphql, the queries resolve against the application queries and
+// the mutations run the application commands with the same validation as the http port
+type Server struct {
+	app    app.Application
+	schema *graphqlgo.Schema
+}
+
+func NewServer(app app.Application) Server {
+	return Server{
+		app: app,
+		schema: graphqlgo.MustParseSchema(schema, &Resolver{app: app},
+			graphqlgo.MaxDepth(maxDepth),
+			graphqlgo.MaxParallelism(maxParallelism),
+		),
+	}
+}
+
+func (s Server) Router(r *gin.Engine) {
+	// the token is only required by the mutations acting on behalf of a requestor
+	authenticate := middleware.AuthenticateIfPresent(auth.NewTokenIssuer(config.C.Auth.Secret, config.C.Auth.TokenTTL))
+
+	r.POST("graphql", authenticate, s.Query)
+}
+
+type queryReq struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+func (s Server) Query(c *gin.Context) {
+	var req queryReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.HttpErrorHandler(c, common.ErrInvalidRequest(err, "query"))
+		return
+	}
+	if err := common.ValidateRequired(req.Query, "query"); err != nil {
+		common.HttpErrorHandler(c, err)
+		return
+	}
+
+	noStore := new(atomic.Bool)
+	ctx := withLoaders(c.Request.Context(), newLoaders(s.app))
+	ctx = context.WithValue(ctx, noStoreKey{}, noStore)
+	res := s.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+
+	if noStore.Load() {
+		c.Header(port.CacheControlHeader, port.NoStore)
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// noStoreKey holds the flag of a request whose response carries a secret, such as the token of a login,
+// the response is then neither cached nor kept to be replayed
+type noStoreKey struct{}
+
+func markNoStore(ctx context.Context) {
+	if noStore, ok := ctx.Value(noStoreKey{}).(*atomic.Bool); ok {
+		noStore.Store(true)
+	}
+}
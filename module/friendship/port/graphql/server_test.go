@@ -0,0 +1,423 @@
+package graphql
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/auth"
+	"github.com/phantranhieunhan/s3-assignment/middleware"
+	mockHandler "github.com/phantranhieunhan/s3-assignment/mock/friendship/handler"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/port"
+)
+
+var testIssuer = auth.NewTokenIssuer("secret", time.Hour)
+
+type graphqlRes struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string                 `json:"message"`
+		Extensions map[string]interface{} `json:"extensions"`
+	} `json:"errors"`
+}
+
+// serve runs the query on the graphql route of the application, with the token of the user when there is one
+func serve(t *testing.T, application app.Application, userID string, body interface{}) *httptest.ResponseRecorder {
+	s := NewServer(application)
+	router := gin.Default()
+	router.POST("/graphql", middleware.AuthenticateIfPresent(testIssuer), s.Query)
+
+	jsonBody, err := json.Marshal(body)
+	assert.NoError(t, err)
+	req, err := http.NewRequest(http.MethodPost, "/graphql", bytes.NewBuffer(jsonBody))
+	assert.NoError(t, err)
+	if userID != "" {
+		token, _, err := testIssuer.Issue(userID)
+		assert.NoError(t, err)
+		req.Header.Set(middleware.AuthorizationHeader, "Bearer "+token)
+	}
+
+	res := httptest.NewRecorder()
+	router.ServeHTTP(res, req)
+	return res
+}
+
+func exec(t *testing.T, application app.Application, userID, query string, variables map[string]interface{}) graphqlRes {
+	res := serve(t, application, userID, queryReq{Query: query, Variables: variables})
+	assert.Equal(t, http.StatusOK, res.Code)
+
+	var body graphqlRes
+	assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &body))
+	return body
+}
+
+// sameKeys matches the keys of a batch whatever the order the fields asked for them
+func sameKeys(keys ...string) interface{} {
+	sort.Strings(keys)
+	return mock.MatchedBy(func(got []string) bool {
+		got = append([]string(nil), got...)
+		sort.Strings(got)
+		return assert.ObjectsAreEqual(keys, got)
+	})
+}
+
+func TestQueryUser(t *testing.T) {
+	t.Parallel()
+
+	const query = `query($email: String!, $viewer: String!) {
+		user(email: $email) {
+			id
+			email
+			friends { count nodes { id email } }
+			mutualFriends(with: $viewer) { nodes { email } nextCursor }
+			subscription(subscriber: $viewer) { status subscriber { email } }
+		}
+	}`
+	variables := map[string]interface{}{"email": "lisa@example.com", "viewer": "john@example.com"}
+	friends := []string{"andy@example.com", "common@example.com", "john@example.com"}
+
+	t.Run("resolves the friends of the user with one lookup of their ids", func(t *testing.T) {
+		t.Parallel()
+
+		mockGetUser := new(mockHandler.MockGetUserHandler)
+		mockListFriends := new(mockHandler.MockListFriendsHandler)
+		mockListCommonFriends := new(mockHandler.MockListCommonFriendsHandler)
+		mockGetSubscription := new(mockHandler.MockGetSubscriptionHandler)
+		mockListUserIDs := new(mockHandler.MockListUserIDsHandler)
+
+		mockGetUser.On("Handle", mock.Anything, "lisa@example.com").
+			Return(domain.User{Base: domain.Base{Id: "lisa-id"}, Email: "lisa@example.com"}, nil).Once()
+		mockListFriends.On("Handle", mock.Anything, "lisa@example.com", mock.Anything).Return(friends, "", nil).Once()
+		mockListCommonFriends.On("Handle", mock.Anything, []string{"lisa@example.com", "john@example.com"}, mock.Anything).
+			Return([]string{"common@example.com"}, "next", nil).Once()
+		mockGetSubscription.On("Handle", mock.Anything, "john@example.com", "lisa@example.com").
+			Return(domain.Subscription{Base: domain.Base{Id: "sub-id"}, Status: domain.SubscriptionStatusSubscribed}, nil).Once()
+		mockListUserIDs.On("Handle", mock.Anything, sameKeys(friends...)).Return(map[string]string{
+			"andy@example.com":   "andy-id",
+			"common@example.com": "common-id",
+			"john@example.com":   "john-id",
+		}, nil).Once()
+
+		res := exec(t, app.Application{Queries: app.Queries{
+			GetUser:           mockGetUser,
+			ListFriends:       mockListFriends,
+			ListCommonFriends: mockListCommonFriends,
+			GetSubscription:   mockGetSubscription,
+			ListUserIDs:       mockListUserIDs,
+		}}, "", query, variables)
+
+		assert.Empty(t, res.Errors)
+		assert.JSONEq(t, `{"user": {
+			"id": "lisa-id",
+			"email": "lisa@example.com",
+			"friends": {"count": 3, "nodes": [
+				{"id": "andy-id", "email": "andy@example.com"},
+				{"id": "common-id", "email": "common@example.com"},
+				{"id": "john-id", "email": "john@example.com"}
+			]},
+			"mutualFriends": {"nodes": [{"email": "common@example.com"}], "nextCursor": "next"},
+			"subscription": {"status": "SUBSCRIBED", "subscriber": {"email": "john@example.com"}}
+		}}`, string(res.Data))
+		mock.AssertExpectationsForObjects(t, mockGetUser, mockListFriends, mockListCommonFriends, mockGetSubscription, mockListUserIDs)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		t.Parallel()
+
+		mockGetUser := new(mockHandler.MockGetUserHandler)
+		mockGetUser.On("Handle", mock.Anything, "lisa@example.com").
+			Return(domain.User{}, common.ErrInvalidRequest(domain.ErrNotFoundUserByEmail, "email")).Once()
+
+		res := exec(t, app.Application{Queries: app.Queries{GetUser: mockGetUser}}, "", query, variables)
+
+		assert.Empty(t, res.Errors)
+		assert.JSONEq(t, `{"user": null}`, string(res.Data))
+		mock.AssertExpectationsForObjects(t, mockGetUser)
+	})
+
+	t.Run("the error of a field is in the errors with its key", func(t *testing.T) {
+		t.Parallel()
+
+		mockGetUser := new(mockHandler.MockGetUserHandler)
+		mockGetUser.On("Handle", mock.Anything, "lisa@example.com").
+			Return(domain.User{}, common.ErrCannotGetEntity(domain.User{}.DomainName(), errors.New("db down"))).Once()
+
+		res := exec(t, app.Application{Queries: app.Queries{GetUser: mockGetUser}}, "", query, variables)
+
+		assert.Len(t, res.Errors, 1)
+		assert.Equal(t, "ErrCannotGetUser", res.Errors[0].Extensions["error_key"])
+		assert.JSONEq(t, `{"user": null}`, string(res.Data))
+		mock.AssertExpectationsForObjects(t, mockGetUser)
+	})
+}
+
+func TestQueryUserBlocked(t *testing.T) {
+	t.Parallel()
+
+	const query = `{ user(email: "lisa@example.com") { blocked { user { email } } } }`
+	blocked := []domain.BlockedEmail{{Email: "john@example.com", BlockedAt: time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)}}
+
+	tcs := []struct {
+		name     string
+		userID   string
+		errorKey string
+		expected string
+	}{
+		{name: "resolves the blocks of the authenticated user", userID: "lisa-id", expected: `{"user": {"blocked": [{"user": {"email": "john@example.com"}}]}}`},
+		{name: "fail because user is not authenticated", errorKey: "ErrUnauthorized", expected: `{"user": null}`},
+		{name: "fail because user is another one", userID: "john-id", errorKey: "ErrForbidden", expected: `{"user": null}`},
+	}
+
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockGetUser := new(mockHandler.MockGetUserHandler)
+			mockListBlockedUsers := new(mockHandler.MockListBlockedUsersHandler)
+			mockGetUser.On("Handle", mock.Anything, "lisa@example.com").
+				Return(domain.User{Base: domain.Base{Id: "lisa-id"}, Email: "lisa@example.com"}, nil).Once()
+			if tc.errorKey == "" {
+				mockListBlockedUsers.On("Handle", mock.Anything, "lisa-id").Return(blocked, nil).Once()
+			}
+
+			res := exec(t, app.Application{Queries: app.Queries{
+				GetUser:          mockGetUser,
+				ListBlockedUsers: mockListBlockedUsers,
+			}}, tc.userID, query, nil)
+
+			if tc.errorKey == "" {
+				assert.Empty(t, res.Errors)
+			} else {
+				assert.Len(t, res.Errors, 1)
+				assert.Equal(t, tc.errorKey, res.Errors[0].Extensions["error_key"])
+			}
+			assert.JSONEq(t, tc.expected, string(res.Data))
+			mock.AssertExpectationsForObjects(t, mockGetUser, mockListBlockedUsers)
+		})
+	}
+}
+
+func TestQueryUserFriendRequests(t *testing.T) {
+	t.Parallel()
+
+	const query = `{ user(email: "lisa@example.com") { friendRequests(direction: INCOMING) { email } } }`
+
+	tcs := []struct {
+		name     string
+		userID   string
+		errorKey string
+		expected string
+	}{
+		{name: "resolves the friend requests of the authenticated user", userID: "lisa-id", expected: `{"user": {"friendRequests": [{"email": "john@example.com"}]}}`},
+		{name: "fail because user is not authenticated", errorKey: "ErrUnauthorized", expected: `{"user": null}`},
+		{name: "fail because user is another one", userID: "john-id", errorKey: "ErrForbidden", expected: `{"user": null}`},
+	}
+
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockGetUser := new(mockHandler.MockGetUserHandler)
+			mockListFriendRequests := new(mockHandler.MockListFriendRequestsHandler)
+			mockGetUser.On("Handle", mock.Anything, "lisa@example.com").
+				Return(domain.User{Base: domain.Base{Id: "lisa-id"}, Email: "lisa@example.com"}, nil).Once()
+			if tc.errorKey == "" {
+				mockListFriendRequests.On("Handle", mock.Anything, "lisa-id", domain.FriendRequestDirectionIncoming).
+					Return([]string{"john@example.com"}, nil).Once()
+			}
+
+			res := exec(t, app.Application{Queries: app.Queries{
+				GetUser:            mockGetUser,
+				ListFriendRequests: mockListFriendRequests,
+			}}, tc.userID, query, nil)
+
+			if tc.errorKey == "" {
+				assert.Empty(t, res.Errors)
+			} else {
+				assert.Len(t, res.Errors, 1)
+				assert.Equal(t, tc.errorKey, res.Errors[0].Extensions["error_key"])
+			}
+			assert.JSONEq(t, tc.expected, string(res.Data))
+			mock.AssertExpectationsForObjects(t, mockGetUser, mockListFriendRequests)
+		})
+	}
+}
+
+func TestQueryFriendship(t *testing.T) {
+	t.Parallel()
+
+	const query = `{
+		friendship(user: "lisa@example.com", friend: "john@example.com") {
+			id status user { email } friend { email }
+		}
+	}`
+
+	tcs := []struct {
+		name       string
+		friendship domain.Friendship
+		expected   string
+	}{
+		{
+			name:       "resolves both users with one lookup of their emails",
+			friendship: domain.Friendship{Base: domain.Base{Id: "f-id"}, UserID: "lisa-id", FriendID: "john-id", Status: domain.FriendshipStatusPending},
+			expected: `{"friendship": {"id": "f-id", "status": "PENDING",
+				"user": {"email": "lisa@example.com"}, "friend": {"email": "john@example.com"}}}`,
+		},
+		{
+			name:     "never connected",
+			expected: `{"friendship": null}`,
+		},
+	}
+
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockGetFriendship := new(mockHandler.MockGetFriendshipHandler)
+			mockListUserEmails := new(mockHandler.MockListUserEmailsHandler)
+			mockGetFriendship.On("Handle", mock.Anything, "lisa@example.com", "john@example.com").Return(tc.friendship, nil).Once()
+			if tc.friendship.Base.Id != "" {
+				mockListUserEmails.On("Handle", mock.Anything, sameKeys("lisa-id", "john-id")).Return(map[string]string{
+					"lisa-id": "lisa@example.com",
+					"john-id": "john@example.com",
+				}, nil).Once()
+			}
+
+			res := exec(t, app.Application{Queries: app.Queries{
+				GetFriendship:  mockGetFriendship,
+				ListUserEmails: mockListUserEmails,
+			}}, "", query, nil)
+
+			assert.Empty(t, res.Errors)
+			assert.JSONEq(t, tc.expected, string(res.Data))
+			mock.AssertExpectationsForObjects(t, mockGetFriendship, mockListUserEmails)
+		})
+	}
+}
+
+func TestMutationConnectFriendship(t *testing.T) {
+	t.Parallel()
+
+	const query = `mutation($friends: [String!]!) {
+		connectFriendship(friends: $friends) { status user { email } friend { email } }
+	}`
+	friendship := domain.Friendship{Base: domain.Base{Id: "f-id"}, UserID: "lisa-id", FriendID: "john-id", Status: domain.FriendshipStatusPending}
+
+	tcs := []struct {
+		name    string
+		userID  string
+		friends []interface{}
+
+		connectError error
+
+		expectedKey string
+	}{
+		{
+			name:    "successful",
+			userID:  "lisa-id",
+			friends: []interface{}{"lisa@example.com", "john@example.com"},
+		},
+		{
+			name:        "missing token",
+			friends:     []interface{}{"lisa@example.com", "john@example.com"},
+			expectedKey: "ErrUnauthorized",
+		},
+		{
+			name:        "validate request",
+			userID:      "lisa-id",
+			friends:     []interface{}{"lisa@example.com"},
+			expectedKey: "ErrInvalidRequest",
+		},
+		{
+			name:         "connect failed",
+			userID:       "lisa-id",
+			friends:      []interface{}{"lisa@example.com", "john@example.com"},
+			connectError: common.ErrCannotCreateEntity(domain.Friendship{}.DomainName(), errors.New("db down")),
+			expectedKey:  "ErrCannotCreateFriendship",
+		},
+	}
+
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockRequest := new(mockHandler.MockRequestFriendshipHandler)
+			mockListUserEmails := new(mockHandler.MockListUserEmailsHandler)
+			if tc.expectedKey == "" || tc.connectError != nil {
+				mockRequest.On("Handle", mock.Anything, payload.FriendRequestPayload{
+					Requestor: "lisa@example.com",
+					Target:    "john@example.com",
+				}).Return(friendship, tc.connectError).Once()
+			}
+			if tc.expectedKey == "" {
+				mockListUserEmails.On("Handle", mock.Anything, sameKeys("lisa-id", "john-id")).Return(map[string]string{
+					"lisa-id": "lisa@example.com",
+					"john-id": "john@example.com",
+				}, nil).Once()
+			}
+
+			res := exec(t, app.Application{
+				Commands: app.Commands{RequestFriendship: mockRequest},
+				Queries:  app.Queries{ListUserEmails: mockListUserEmails},
+			}, tc.userID, query, map[string]interface{}{"friends": tc.friends})
+
+			if tc.expectedKey != "" {
+				assert.Len(t, res.Errors, 1)
+				assert.Equal(t, tc.expectedKey, res.Errors[0].Extensions["error_key"])
+			} else {
+				assert.Empty(t, res.Errors)
+				assert.JSONEq(t, `{"connectFriendship": {"status": "PENDING",
+					"user": {"email": "lisa@example.com"}, "friend": {"email": "john@example.com"}}}`, string(res.Data))
+			}
+			mock.AssertExpectationsForObjects(t, mockRequest, mockListUserEmails)
+		})
+	}
+}
+
+func TestQueryInvalidRequest(t *testing.T) {
+	t.Parallel()
+
+	res := serve(t, app.Application{}, "", queryReq{})
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+
+	res = serve(t, app.Application{}, "", map[string]interface{}{"query": 1})
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+}
+
+func TestMutationLoginIsNotStored(t *testing.T) {
+	t.Parallel()
+
+	mockLogin := new(mockHandler.MockLoginHandler)
+	mockLogin.On("Handle", mock.Anything, payload.LoginPayload{Email: "lisa@example.com", Password: "password"}).
+		Return(domain.AuthToken{Token: "token", ExpiresAt: time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)}, nil).Once()
+	mockGetUser := new(mockHandler.MockGetUserHandler)
+	mockGetUser.On("Handle", mock.Anything, "lisa@example.com").
+		Return(domain.User{Base: domain.Base{Id: "lisa-id"}, Email: "lisa@example.com"}, nil).Once()
+	application := app.Application{Commands: app.Commands{Login: mockLogin}, Queries: app.Queries{GetUser: mockGetUser}}
+
+	// the response holding the token is marked no-store, the others are not
+	res := serve(t, application, "", queryReq{Query: `mutation { login(email: "lisa@example.com", password: "password") { token } }`})
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, port.NoStore, res.Header().Get(port.CacheControlHeader))
+	res = serve(t, application, "", queryReq{Query: `{ user(email: "lisa@example.com") { id } }`})
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Empty(t, res.Header().Get(port.CacheControlHeader))
+	mock.AssertExpectationsForObjects(t, mockLogin, mockGetUser)
+}
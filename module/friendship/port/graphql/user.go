@@ -0,0 +1,303 @@
+package graphql
+
+import (
+	"context"
+
+	graphqlgo "github.com/graph-gophers/graphql-go"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/auth"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/port"
+)
+
+// userResolver resolves a user known by its id, its email or both, the missing one is loaded in batch
+// by the loaders of the request which also cache it for the other fields
+type userResolver struct {
+	app   app.Application
+	id    string
+	email string
+}
+
+func newUser(app app.Application, user domain.User) *userResolver {
+	return &userResolver{app: app, id: user.Base.Id, email: user.Email}
+}
+
+func newUserByEmail(app app.Application, email string) *userResolver {
+	return &userResolver{app: app, email: email}
+}
+
+func newUserByID(app app.Application, id string) *userResolver {
+	return &userResolver{app: app, id: id}
+}
+
+func newUsersByEmails(app app.Application, emails []string) []*userResolver {
+	res := make([]*userResolver, 0, len(emails))
+	for _, email := range emails {
+		res = append(res, newUserByEmail(app, email))
+	}
+	return res
+}
+
+func (u *userResolver) ID(ctx context.Context) (graphqlgo.ID, error) {
+	if u.id == "" {
+		id, err := loadUserID(ctx, u.email)
+		if err != nil {
+			return "", toError("User.id", err)
+		}
+		return graphqlgo.ID(id), nil
+	}
+	return graphqlgo.ID(u.id), nil
+}
+
+func (u *userResolver) Email(ctx context.Context) (string, error) {
+	if u.email == "" {
+		email, err := loadEmail(ctx, u.id)
+		if err != nil {
+			return "", toError("User.email", err)
+		}
+		return email, nil
+	}
+	return u.email, nil
+}
+
+type pageArgs struct {
+	First *int32
+	After *string
+	Sort  *string
+	Since *string
+}
+
+func (a pageArgs) toPage() (domain.Page, error) {
+	req := port.PageReq{}
+	if a.First != nil {
+		req.Limit = int(*a.First)
+	}
+	if a.After != nil {
+		req.Cursor = *a.After
+	}
+	if a.Sort != nil {
+		req.Sort = *a.Sort
+	}
+	if a.Since != nil {
+		req.Since = *a.Since
+	}
+	return req.ToPage()
+}
+
+type userConnectionResolver struct {
+	nodes      []*userResolver
+	nextCursor string
+}
+
+func newUserConnection(app app.Application, emails []string, nextCursor string) *userConnectionResolver {
+	return &userConnectionResolver{nodes: newUsersByEmails(app, emails), nextCursor: nextCursor}
+}
+
+func (c *userConnectionResolver) Nodes() []*userResolver {
+	return c.nodes
+}
+
+func (c *userConnectionResolver) Count() int32 {
+	return int32(len(c.nodes))
+}
+
+func (c *userConnectionResolver) NextCursor() *string {
+	if c.nextCursor == "" {
+		return nil
+	}
+	return &c.nextCursor
+}
+
+func (u *userResolver) Friends(ctx context.Context, args pageArgs) (*userConnectionResolver, error) {
+	email, err := u.Email(ctx)
+	if err != nil {
+		return nil, err
+	}
+	page, err := args.toPage()
+	if err != nil {
+		return nil, toError("User.friends", err)
+	}
+
+	list, nextCursor, err := u.app.Queries.ListFriends.Handle(ctx, email, page)
+	if err != nil {
+		return nil, toError("User.friends", err)
+	}
+
+	return newUserConnection(u.app, list, nextCursor), nil
+}
+
+func (u *userResolver) MutualFriends(ctx context.Context, args struct {
+	With  string
+	First *int32
+	After *string
+	Sort  *string
+	Since *string
+}) (*userConnectionResolver, error) {
+	email, err := u.Email(ctx)
+	if err != nil {
+		return nil, err
+	}
+	friends := []string{email, args.With}
+	if err = (port.ListCommonFriendsReq{Friends: friends}).Validate(); err != nil {
+		return nil, toError("User.mutualFriends", err)
+	}
+	page, err := pageArgs{First: args.First, After: args.After, Sort: args.Sort, Since: args.Since}.toPage()
+	if err != nil {
+		return nil, toError("User.mutualFriends", err)
+	}
+
+	list, nextCursor, err := u.app.Queries.ListCommonFriends.Handle(ctx, friends, page)
+	if err != nil {
+		return nil, toError("User.mutualFriends", err)
+	}
+
+	return newUserConnection(u.app, list, nextCursor), nil
+}
+
+func (u *userResolver) Subscribers(ctx context.Context, args pageArgs) (*userConnectionResolver, error) {
+	email, err := u.Email(ctx)
+	if err != nil {
+		return nil, err
+	}
+	page, err := args.toPage()
+	if err != nil {
+		return nil, toError("User.subscribers", err)
+	}
+
+	list, nextCursor, err := u.app.Queries.ListSubscribers.Handle(ctx, email, page)
+	if err != nil {
+		return nil, toError("User.subscribers", err)
+	}
+
+	return newUserConnection(u.app, list, nextCursor), nil
+}
+
+// FriendRequests is only resolved for the authenticated user, the pending requests of the others are not theirs to see
+func (u *userResolver) FriendRequests(ctx context.Context, args struct{ Direction string }) ([]*userResolver, error) {
+	if err := authenticated(ctx); err != nil {
+		return nil, toError("User.friendRequests", err)
+	}
+	id, err := u.ID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if userID, _ := auth.UserIDFromContext(ctx); userID != string(id) {
+		return nil, toError("User.friendRequests", common.NewForbidden(domain.ErrRequestorIsNotAuthenticatedUser, domain.ErrRequestorIsNotAuthenticatedUser.Error(), "ErrForbidden"))
+	}
+	direction := domain.FriendRequestDirectionIncoming
+	if args.Direction == "OUTGOING" {
+		direction = domain.FriendRequestDirectionOutgoing
+	}
+
+	list, err := u.app.Queries.ListFriendRequests.Handle(ctx, string(id), direction)
+	if err != nil {
+		return nil, toError("User.friendRequests", err)
+	}
+
+	return newUsersByEmails(u.app, list), nil
+}
+
+type friendSuggestionResolver struct {
+	user          *userResolver
+	mutualFriends int
+}
+
+func (s *friendSuggestionResolver) User() *userResolver {
+	return s.user
+}
+
+func (s *friendSuggestionResolver) MutualFriends() int32 {
+	return int32(s.mutualFriends)
+}
+
+func (u *userResolver) FriendSuggestions(ctx context.Context, args struct{ Limit *int32 }) ([]*friendSuggestionResolver, error) {
+	email, err := u.Email(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r := port.ListFriendSuggestionsReq{Email: email}
+	if args.Limit != nil {
+		r.Limit = int(*args.Limit)
+	}
+	if err = r.Validate(); err != nil {
+		return nil, toError("User.friendSuggestions", err)
+	}
+	if r.Limit == 0 {
+		r.Limit = port.DefaultSuggestionLimit
+	}
+
+	list, err := u.app.Queries.ListFriendSuggestions.Handle(ctx, r.Email, r.Limit)
+	if err != nil {
+		return nil, toError("User.friendSuggestions", err)
+	}
+
+	res := make([]*friendSuggestionResolver, 0, len(list))
+	for _, f := range list {
+		res = append(res, &friendSuggestionResolver{user: newUserByEmail(u.app, f.Email), mutualFriends: f.MutualFriends})
+	}
+	return res, nil
+}
+
+type blockedUserResolver struct {
+	user      *userResolver
+	blockedAt graphqlgo.Time
+}
+
+func (b *blockedUserResolver) User() *userResolver {
+	return b.user
+}
+
+func (b *blockedUserResolver) BlockedAt() graphqlgo.Time {
+	return b.blockedAt
+}
+
+// Blocked is only resolved for the authenticated user, the blocks of the others are not theirs to see
+func (u *userResolver) Blocked(ctx context.Context) ([]*blockedUserResolver, error) {
+	if err := authenticated(ctx); err != nil {
+		return nil, toError("User.blocked", err)
+	}
+	id, err := u.ID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if userID, _ := auth.UserIDFromContext(ctx); userID != string(id) {
+		return nil, toError("User.blocked", common.NewForbidden(domain.ErrRequestorIsNotAuthenticatedUser, domain.ErrRequestorIsNotAuthenticatedUser.Error(), "ErrForbidden"))
+	}
+
+	list, err := u.app.Queries.ListBlockedUsers.Handle(ctx, string(id))
+	if err != nil {
+		return nil, toError("User.blocked", err)
+	}
+
+	res := make([]*blockedUserResolver, 0, len(list))
+	for _, b := range list {
+		res = append(res, &blockedUserResolver{user: newUserByEmail(u.app, b.Email), blockedAt: graphqlgo.Time{Time: b.BlockedAt}})
+	}
+	return res, nil
+}
+
+func (u *userResolver) Friendship(ctx context.Context, args struct{ With string }) (*friendshipResolver, error) {
+	email, err := u.Email(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return getFriendship(ctx, u.app, email, args.With)
+}
+
+func (u *userResolver) Subscription(ctx context.Context, args struct{ Subscriber string }) (*subscriptionResolver, error) {
+	email, err := u.Email(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return getSubscription(ctx, u.app, args.Subscriber, email)
+}
+
+// isUserNotFound reports whether the lookup failed for a missing user, the user of the query is null then
+func isUserNotFound(err error) bool {
+	appErr, ok := err.(*common.AppError)
+	return ok && appErr.RootError() == domain.ErrNotFoundUserByEmail
+}
@@ -0,0 +1,381 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/port"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/port/constant"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/port/grpc/pb"
+)
+
+func (s *Server) Login(ctx context.Context, req *pb.LoginRequest) (*pb.LoginResponse, error) {
+	if err := (port.LoginReq{Email: req.GetEmail(), Password: req.GetPassword()}).Validate(); err != nil {
+		return nil, err
+	}
+
+	token, err := s.app.Commands.Login.Handle(ctx, payload.LoginPayload{
+		Email:    req.GetEmail(),
+		Password: req.GetPassword(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.LoginResponse{Token: token.Token, ExpiresAt: toTimestamp(token.ExpiresAt)}, nil
+}
+
+// RestoreAccount cancels the scheduled deletion of the account, the password of the account authenticates the request
+func (s *Server) RestoreAccount(ctx context.Context, req *pb.RestoreAccountRequest) (*emptypb.Empty, error) {
+	if err := (port.UserReq{Email: req.GetEmail()}).Validate(); err != nil {
+		return nil, err
+	}
+	if err := (port.RestoreAccountReq{Password: req.GetPassword()}).Validate(); err != nil {
+		return nil, err
+	}
+
+	err := s.app.Commands.CancelAccountDeletion.Handle(ctx, payload.RestoreAccountPayload{
+		Email:    req.GetEmail(),
+		Password: req.GetPassword(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *Server) ConnectFriendship(ctx context.Context, req *pb.ConnectFriendshipRequest) (*emptypb.Empty, error) {
+	if err := (port.ConnectFriendshipReq{Friends: req.GetFriends()}).Validate(); err != nil {
+		return nil, err
+	}
+
+	// the first of the friends sends a friend request to the second one
+	_, err := s.app.Commands.RequestFriendship.Handle(ctx, payload.FriendRequestPayload{
+		Requestor: req.GetFriends()[0],
+		Target:    req.GetFriends()[1],
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func friendRequestPayload(req *pb.RelationRequest) (payload.FriendRequestPayload, error) {
+	r := port.FriendRequestReq{Requestor: req.GetRequestor(), Target: req.GetTarget()}
+	if err := r.Validate(); err != nil {
+		return payload.FriendRequestPayload{}, err
+	}
+	return payload.FriendRequestPayload{Requestor: r.Requestor, Target: r.Target}, nil
+}
+
+func (s *Server) RequestFriendship(ctx context.Context, req *pb.RelationRequest) (*emptypb.Empty, error) {
+	p, err := friendRequestPayload(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = s.app.Commands.RequestFriendship.Handle(ctx, p); err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *Server) AcceptFriendship(ctx context.Context, req *pb.RelationRequest) (*emptypb.Empty, error) {
+	p, err := friendRequestPayload(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = s.app.Commands.AcceptFriendship.Handle(ctx, p); err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *Server) RejectFriendship(ctx context.Context, req *pb.RelationRequest) (*emptypb.Empty, error) {
+	p, err := friendRequestPayload(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.app.Commands.RejectFriendship.Handle(ctx, p); err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *Server) CancelFriendship(ctx context.Context, req *pb.RelationRequest) (*emptypb.Empty, error) {
+	p, err := friendRequestPayload(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.app.Commands.CancelFriendship.Handle(ctx, p); err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *Server) Unfriend(ctx context.Context, req *pb.RelationRequest) (*emptypb.Empty, error) {
+	if err := (port.UnfriendReq{Requestor: req.GetRequestor(), Target: req.GetTarget()}).Validate(); err != nil {
+		return nil, err
+	}
+
+	err := s.app.Commands.Unfriend.Handle(ctx, payload.UnfriendPayload{
+		Requestor: req.GetRequestor(),
+		Target:    req.GetTarget(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *Server) SubscribeUser(ctx context.Context, req *pb.RelationRequest) (*emptypb.Empty, error) {
+	if err := (port.SubscribeUserReq{Requestor: req.GetRequestor(), Target: req.GetTarget()}).Validate(); err != nil {
+		return nil, err
+	}
+
+	err := s.app.Commands.SubscribeUser.Handle(ctx, payload.SubscriberUserPayloads{
+		payload.SubscriberUserPayload{
+			Requestor: req.GetRequestor(),
+			Target:    req.GetTarget(),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *Server) BlockUpdatesUser(ctx context.Context, req *pb.RelationRequest) (*emptypb.Empty, error) {
+	if err := (port.BlockUpdatesUserReq{Requestor: req.GetRequestor(), Target: req.GetTarget()}).Validate(); err != nil {
+		return nil, err
+	}
+
+	err := s.app.Commands.BlockUpdatesUser.Handle(ctx, payload.BlockUpdatesUserPayload{
+		Requestor: req.GetRequestor(),
+		Target:    req.GetTarget(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *Server) UnblockUser(ctx context.Context, req *pb.RelationRequest) (*emptypb.Empty, error) {
+	if err := (port.UnblockUserReq{Requestor: req.GetRequestor(), Target: req.GetTarget()}).Validate(); err != nil {
+		return nil, err
+	}
+
+	err := s.app.Commands.UnblockUser.Handle(ctx, payload.UnblockUserPayload{
+		Requestor: req.GetRequestor(),
+		Target:    req.GetTarget(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *Server) PostUpdate(ctx context.Context, req *pb.PostUpdateRequest) (*pb.Update, error) {
+	if err := (port.PostUpdateReq{Sender: req.GetSender(), Text: req.GetText()}).Validate(); err != nil {
+		return nil, err
+	}
+
+	update, err := s.app.Commands.PostUpdate.Handle(ctx, payload.PostUpdatePayload{
+		Sender: req.GetSender(),
+		Text:   req.GetText(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.Update{
+		Id:        update.Base.Id,
+		Sender:    req.GetSender(),
+		Text:      update.Text,
+		Mentions:  update.Mentions,
+		CreatedAt: toTimestamp(update.Base.CreatedAt),
+	}, nil
+}
+
+func (s *Server) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb.User, error) {
+	if err := (port.CreateUserReq{Email: req.GetEmail(), Password: req.GetPassword()}).Validate(); err != nil {
+		return nil, err
+	}
+
+	user, err := s.app.Commands.CreateUser.Handle(ctx, payload.CreateUserPayload{
+		Email:    req.GetEmail(),
+		Password: req.GetPassword(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toUser(user), nil
+}
+
+func (s *Server) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest) (*pb.User, error) {
+	if err := (port.UserReq{Email: req.GetEmail()}).Validate(); err != nil {
+		return nil, err
+	}
+	if err := (port.UserReq{Email: req.GetNewEmail()}).Validate(); err != nil {
+		return nil, err
+	}
+
+	user, err := s.app.Commands.UpdateUser.Handle(ctx, payload.UpdateUserPayload{
+		Email:    req.GetEmail(),
+		NewEmail: req.GetNewEmail(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toUser(user), nil
+}
+
+func (s *Server) DeleteUser(ctx context.Context, req *pb.UserRequest) (*emptypb.Empty, error) {
+	if err := (port.UserReq{Email: req.GetEmail()}).Validate(); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.app.Commands.DeleteAccount.Handle(ctx, req.GetEmail()); err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// SetPassword resets the password of the user, the admin hands the new password to the user
+func (s *Server) SetPassword(ctx context.Context, req *pb.SetPasswordRequest) (*emptypb.Empty, error) {
+	if err := (port.UserReq{Email: req.GetEmail()}).Validate(); err != nil {
+		return nil, err
+	}
+	if err := (port.SetPasswordReq{Password: req.GetPassword()}).Validate(); err != nil {
+		return nil, err
+	}
+
+	err := s.app.Commands.SetPassword.Handle(ctx, payload.SetPasswordPayload{
+		Email:    req.GetEmail(),
+		Password: req.GetPassword(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// ImportRelationships writes the rows of the request as one batch of an import, the client splits a larger import
+// in requests of at most the batch size. The invalid rows are failures and the valid ones are written together,
+// a batch failing to commit fails the request
+func (s *Server) ImportRelationships(ctx context.Context, req *pb.ImportRelationshipsRequest) (*pb.ImportRelationshipsResponse, error) {
+	if n := port.ImportBatchSize(); len(req.GetRows()) > n {
+		return nil, common.ErrInvalidRequest(fmt.Errorf("rows must be at most %d", n), constant.ROW)
+	}
+
+	res := &pb.ImportRelationshipsResponse{Failures: make([]*pb.ImportFailure, 0)}
+	rows := make(payload.ImportRelationshipPayloads, 0, len(req.GetRows()))
+	written := make([]int, 0, len(req.GetRows()))
+	for i, row := range req.GetRows() {
+		r := port.ImportRelationshipReq{Type: row.GetType(), User: row.GetUser(), Target: row.GetTarget()}
+		if err := r.Validate(); err != nil {
+			res.Failures = append(res.Failures, toImportFailure(i, err))
+			continue
+		}
+		rows = append(rows, payload.ImportRelationshipPayload{
+			Type:   payload.ImportRelationshipType(r.Type),
+			User:   r.User,
+			Target: r.Target,
+		})
+		written = append(written, i)
+	}
+
+	if len(rows) > 0 {
+		rowErrs, err := s.app.Commands.ImportRelationships.Handle(ctx, rows)
+		if err != nil {
+			return nil, err
+		}
+		for k, i := range written {
+			if rowErrs[k] != nil {
+				res.Failures = append(res.Failures, toImportFailure(i, rowErrs[k]))
+				continue
+			}
+			res.Imported++
+		}
+	}
+
+	sort.Slice(res.Failures, func(i, j int) bool {
+		return res.Failures[i].Row < res.Failures[j].Row
+	})
+	res.Failed = int32(len(res.Failures))
+	return res, nil
+}
+
+// toImportFailure reports the error of a row with the key and the message of the error response, without its root
+func toImportFailure(row int, err error) *pb.ImportFailure {
+	appErr, ok := err.(*common.AppError)
+	if !ok {
+		appErr = common.ErrInternal(err)
+	}
+	return &pb.ImportFailure{Row: int32(row), ErrorKey: appErr.Key, Message: appErr.Message}
+}
+
+func (s *Server) RegisterWebhook(ctx context.Context, req *pb.RegisterWebhookRequest) (*pb.Webhook, error) {
+	r := port.RegisterWebhookReq{URL: req.GetUrl(), Secret: req.GetSecret(), EventTypes: req.GetEventTypes()}
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+
+	eventTypes := make([]domain.EventType, 0, len(r.EventTypes))
+	for _, t := range r.EventTypes {
+		eventTypes = append(eventTypes, domain.EventType(t))
+	}
+	webhook, err := s.app.Commands.RegisterWebhook.Handle(ctx, payload.RegisterWebhookPayload{
+		URL:        r.URL,
+		Secret:     r.Secret,
+		EventTypes: eventTypes,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toWebhook(webhook), nil
+}
+
+func (s *Server) DeleteWebhook(ctx context.Context, req *pb.WebhookRequest) (*emptypb.Empty, error) {
+	if err := s.app.Commands.DeleteWebhook.Handle(ctx, req.GetId()); err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *Server) ReplayWebhookDelivery(ctx context.Context, req *pb.ReplayWebhookDeliveryRequest) (*pb.WebhookDelivery, error) {
+	delivery, err := s.app.Commands.ReplayWebhookDelivery.Handle(ctx, payload.ReplayWebhookDeliveryPayload{
+		WebhookID:  req.GetWebhookId(),
+		DeliveryID: req.GetDeliveryId(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toWebhookDelivery(delivery), nil
+}
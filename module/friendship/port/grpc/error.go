@@ -0,0 +1,72 @@
+package grpc
+
+import (
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/constant"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/phantranhieunhan/s3-assignment/pkg/config"
+)
+
+// errorDomain is the domain of the ErrorInfo detail carrying the error key of the application
+const errorDomain = "friendship"
+
+// toCode maps the key of an application error to the grpc status code closest to its http status
+func toCode(appErr *common.AppError) codes.Code {
+	switch {
+	case appErr.Key == "ErrInvalidRequest":
+		if appErr.RootError() == domain.ErrRecordNotFound {
+			return codes.NotFound
+		}
+		return codes.InvalidArgument
+	case appErr.Key == "ErrUnauthorized", appErr.Key == "ErrInvalidCredentials":
+		return codes.Unauthenticated
+	case appErr.Key == "ErrForbidden":
+		return codes.PermissionDenied
+	case appErr.Key == "DB_ERROR", appErr.Key == "ErrInternal":
+		return codes.Internal
+	case strings.HasPrefix(appErr.Key, "ErrUserAlreadyExists"):
+		return codes.AlreadyExists
+	case appErr.Key == "ErrConflict":
+		// the request clashes with one still running, it can be retried
+		return codes.Aborted
+	case strings.HasPrefix(appErr.Key, "ErrCannot"):
+		if appErr.RootError() == domain.ErrRecordNotFound {
+			return codes.NotFound
+		}
+		return codes.Internal
+	}
+	return codes.Unknown
+}
+
+// toStatusError turns the error of a handler into a grpc status error, the key of the application error
+// goes with it as the reason of an ErrorInfo detail so that clients can tell the errors apart the same as over http
+func toStatusError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	appErr, ok := err.(*common.AppError)
+	if !ok {
+		appErr = common.ErrInternal(err)
+	}
+
+	info := &errdetails.ErrorInfo{Reason: appErr.Key, Domain: errorDomain}
+	if config.C.Env != constant.PRODUCTION_ENV_NAME && appErr.Log != "" {
+		info.Metadata = map[string]string{"log": appErr.Log}
+	}
+
+	st, detailErr := status.New(toCode(appErr), appErr.Message).WithDetails(info)
+	if detailErr != nil {
+		return status.Error(toCode(appErr), appErr.Message)
+	}
+	return st.Err()
+}
@@ -0,0 +1,59 @@
+package grpc
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+func TestToStatusError(t *testing.T) {
+	t.Parallel()
+
+	errDB := errors.New("some error from db")
+
+	tcs := []struct {
+		name   string
+		err    error
+		code   codes.Code
+		reason string
+	}{
+		{name: "invalid request", err: common.ErrInvalidRequest(domain.ErrNotFoundUserByEmail, "email"), code: codes.InvalidArgument, reason: "ErrInvalidRequest"},
+		{name: "invalid request of a missing record", err: common.ErrInvalidRequest(domain.ErrRecordNotFound, "delivery_id"), code: codes.NotFound, reason: "ErrInvalidRequest"},
+		{name: "unauthorized", err: common.NewUnauthorized(errDB, "token is not valid", "ErrUnauthorized"), code: codes.Unauthenticated, reason: "ErrUnauthorized"},
+		{name: "invalid credentials", err: common.NewUnauthorized(errDB, "invalid credentials", "ErrInvalidCredentials"), code: codes.Unauthenticated, reason: "ErrInvalidCredentials"},
+		{name: "forbidden", err: common.NewForbidden(domain.ErrRequestorIsNotAuthenticatedUser, "forbidden", "ErrForbidden"), code: codes.PermissionDenied, reason: "ErrForbidden"},
+		{name: "db error", err: common.ErrDB(errDB), code: codes.Internal, reason: "DB_ERROR"},
+		{name: "cannot get entity", err: common.ErrCannotGetEntity(domain.User{}.DomainName(), errDB), code: codes.Internal, reason: "ErrCannotGetUser"},
+		{name: "cannot get a missing entity", err: common.ErrCannotGetEntity(domain.Webhook{}.DomainName(), domain.ErrRecordNotFound), code: codes.NotFound, reason: "ErrCannotGetWebhook"},
+		{name: "entity existed", err: common.ErrEntityExisted(domain.User{}.DomainName(), domain.ErrAlreadyExists), code: codes.AlreadyExists, reason: "ErrUserAlreadyExistsUser"},
+		{name: "conflict", err: common.NewConflict(domain.ErrIdempotencyKeyIsInFlight, domain.ErrIdempotencyKeyIsInFlight.Error(), "ErrConflict"), code: codes.Aborted, reason: "ErrConflict"},
+		{name: "error which is not an application error", err: errDB, code: codes.Internal, reason: "ErrInternal"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			st, ok := status.FromError(toStatusError(tc.err))
+			assert.True(t, ok)
+			assert.Equal(t, tc.code, st.Code())
+
+			details := st.Details()
+			if assert.Len(t, details, 1) {
+				info, ok := details[0].(*errdetails.ErrorInfo)
+				assert.True(t, ok)
+				assert.Equal(t, tc.reason, info.Reason)
+				assert.Equal(t, errorDomain, info.Domain)
+			}
+		})
+	}
+
+	assert.NoError(t, toStatusError(nil))
+	statusErr := status.Error(codes.Unavailable, "unavailable")
+	assert.Equal(t, statusErr, toStatusError(statusErr))
+}
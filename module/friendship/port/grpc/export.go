@@ -0,0 +1,93 @@
+package grpc
+
+import (
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/port"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/port/grpc/pb"
+)
+
+// ExportUser streams everything held about the user, a chunk is sent for each chunk of rows read
+// so the export of a large account is never held in memory
+func (s *Server) ExportUser(req *pb.UserRequest, stream pb.FriendshipService_ExportUserServer) error {
+	if err := (port.UserReq{Email: req.GetEmail()}).Validate(); err != nil {
+		return err
+	}
+
+	return s.app.Queries.ExportUser.Handle(stream.Context(), req.GetEmail(), userExportStream{stream: stream, email: req.GetEmail()})
+}
+
+// userExportStream sends each part of the export as a chunk of the stream
+type userExportStream struct {
+	stream pb.FriendshipService_ExportUserServer
+	email  string
+}
+
+func (e userExportStream) WriteUser(u domain.User) error {
+	return e.stream.Send(&pb.ExportUserChunk{User: toUser(u)})
+}
+
+func (e userExportStream) WriteFriendships(list []domain.ExportedFriendship) error {
+	rows := make([]*pb.ExportedFriendship, 0, len(list))
+	for _, f := range list {
+		rows = append(rows, &pb.ExportedFriendship{
+			Id:        f.Id,
+			User:      f.UserEmail,
+			Friend:    f.FriendEmail,
+			Status:    friendshipStatuses[f.Status],
+			CreatedAt: toTimestamp(f.CreatedAt),
+			UpdatedAt: toTimestamp(f.UpdatedAt),
+		})
+	}
+	return e.stream.Send(&pb.ExportUserChunk{Friendships: rows})
+}
+
+func (e userExportStream) WriteSubscriptions(list []domain.ExportedSubscription) error {
+	rows := make([]*pb.ExportedSubscription, 0, len(list))
+	for _, s := range list {
+		rows = append(rows, &pb.ExportedSubscription{
+			Id:         s.Id,
+			User:       s.UserEmail,
+			Subscriber: s.SubscriberEmail,
+			Status:     subscriptionStatuses[s.Status],
+			CreatedAt:  toTimestamp(s.CreatedAt),
+			UpdatedAt:  toTimestamp(s.UpdatedAt),
+		})
+	}
+	return e.stream.Send(&pb.ExportUserChunk{Subscriptions: rows})
+}
+
+func (e userExportStream) WriteBlocks(list []domain.ExportedBlock) error {
+	rows := make([]*pb.ExportedBlock, 0, len(list))
+	for _, b := range list {
+		rows = append(rows, &pb.ExportedBlock{
+			Id:        b.Id,
+			Target:    b.TargetEmail,
+			CreatedAt: toTimestamp(b.CreatedAt),
+			UpdatedAt: toTimestamp(b.UpdatedAt),
+		})
+	}
+	return e.stream.Send(&pb.ExportUserChunk{Blocks: rows})
+}
+
+// WriteUpdates sends the updates posted by the user, the sender is the user of the export
+func (e userExportStream) WriteUpdates(list []domain.Update) error {
+	rows := make([]*pb.Update, 0, len(list))
+	for _, u := range list {
+		rows = append(rows, &pb.Update{
+			Id:        u.Id,
+			Sender:    e.email,
+			Text:      u.Text,
+			Mentions:  u.Mentions,
+			CreatedAt: toTimestamp(u.CreatedAt),
+		})
+	}
+	return e.stream.Send(&pb.ExportUserChunk{Updates: rows})
+}
+
+func (e userExportStream) WriteAuditEntries(list []domain.AuditEntry) error {
+	rows := make([]*pb.AuditEntry, 0, len(list))
+	for _, entry := range list {
+		rows = append(rows, toAuditEntry(entry))
+	}
+	return e.stream.Send(&pb.ExportUserChunk{AuditLog: rows})
+}
@@ -0,0 +1,178 @@
+package grpc
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"strings"
+
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/auth"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/common/requestid"
+	"github.com/phantranhieunhan/s3-assignment/middleware"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/port/grpc/pb"
+	"github.com/phantranhieunhan/s3-assignment/pkg/util"
+)
+
+const (
+	// AuthorizationMetadata and AdminTokenMetadata are the grpc counterparts of the http headers, metadata keys are lower case
+	AuthorizationMetadata = "authorization"
+	AdminTokenMetadata    = "x-admin-token"
+	RequestIDMetadata     = "x-request-id"
+
+	bearerPrefix = "Bearer "
+	// maxRequestIDLength bounds the id taken from the client, a longer one is replaced by a generated id
+	maxRequestIDLength = 128
+)
+
+// authenticatedMethods act on behalf of a requestor and need the token issued on login
+var authenticatedMethods = map[string]bool{
+	pb.FriendshipService_ConnectFriendship_FullMethodName:  true,
+	pb.FriendshipService_RequestFriendship_FullMethodName:  true,
+	pb.FriendshipService_AcceptFriendship_FullMethodName:   true,
+	pb.FriendshipService_RejectFriendship_FullMethodName:   true,
+	pb.FriendshipService_CancelFriendship_FullMethodName:   true,
+	pb.FriendshipService_Unfriend_FullMethodName:           true,
+	pb.FriendshipService_SubscribeUser_FullMethodName:      true,
+	pb.FriendshipService_BlockUpdatesUser_FullMethodName:   true,
+	pb.FriendshipService_UnblockUser_FullMethodName:        true,
+	pb.FriendshipService_PostUpdate_FullMethodName:         true,
+	pb.FriendshipService_UpdateUser_FullMethodName:         true,
+	pb.FriendshipService_DeleteUser_FullMethodName:         true,
+	pb.FriendshipService_ExportUser_FullMethodName:         true,
+	pb.FriendshipService_ListBlockedUsers_FullMethodName:   true,
+	pb.FriendshipService_ListFriendRequests_FullMethodName: true,
+	pb.FriendshipService_GetFeed_FullMethodName:            true,
+}
+
+// adminMethods need the configured admin token
+var adminMethods = map[string]bool{
+	pb.FriendshipService_SetPassword_FullMethodName:               true,
+	pb.FriendshipService_ImportRelationships_FullMethodName:       true,
+	pb.FriendshipService_ListAuditLog_FullMethodName:              true,
+	pb.FriendshipService_ListBlockers_FullMethodName:              true,
+	pb.FriendshipService_RegisterWebhook_FullMethodName:           true,
+	pb.FriendshipService_ListWebhooks_FullMethodName:              true,
+	pb.FriendshipService_DeleteWebhook_FullMethodName:             true,
+	pb.FriendshipService_ListDeadWebhookDeliveries_FullMethodName: true,
+	pb.FriendshipService_ReplayWebhookDelivery_FullMethodName:     true,
+}
+
+// recoverInterceptor answers Internal instead of crashing the process when a handler panics
+func recoverInterceptor(ctx context.Context, req interface{}, info *grpclib.UnaryServerInfo, handler grpclib.UnaryHandler) (res interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error(info.FullMethod+".Recover: ", r)
+			err = common.ErrInternal(fmt.Errorf("%v", r))
+		}
+	}()
+	return handler(ctx, req)
+}
+
+// errorInterceptor logs the error of a method and turns it into a grpc status,
+// it wraps the other interceptors so that their errors are mapped as well
+func errorInterceptor(ctx context.Context, req interface{}, info *grpclib.UnaryServerInfo, handler grpclib.UnaryHandler) (interface{}, error) {
+	res, err := handler(ctx, req)
+	if err != nil {
+		logger.Error(info.FullMethod+": ", err)
+		return nil, toStatusError(err)
+	}
+	return res, nil
+}
+
+// authInterceptor checks the bearer token of the authenticated methods and puts its user id in the context
+// read by the application handlers, and checks the admin token of the admin methods, the same as the http middlewares
+func authInterceptor(verifier middleware.TokenVerifier, adminToken string) grpclib.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpclib.UnaryServerInfo, handler grpclib.UnaryHandler) (interface{}, error) {
+		ctx, err := authorize(ctx, info.FullMethod, verifier, adminToken)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func authorize(ctx context.Context, method string, verifier middleware.TokenVerifier, adminToken string) (context.Context, error) {
+	switch {
+	case authenticatedMethods[method]:
+		header := firstMetadata(ctx, AuthorizationMetadata)
+		if !strings.HasPrefix(header, bearerPrefix) {
+			return nil, common.NewUnauthorized(middleware.ErrMissingToken, middleware.ErrMissingToken.Error(), "ErrUnauthorized")
+		}
+		userID, err := verifier.Verify(strings.TrimPrefix(header, bearerPrefix))
+		if err != nil {
+			return nil, common.NewUnauthorized(err, auth.ErrInvalidToken.Error(), "ErrUnauthorized")
+		}
+		return auth.WithUserID(ctx, userID), nil
+	case adminMethods[method]:
+		got := firstMetadata(ctx, AdminTokenMetadata)
+		if adminToken == "" || subtle.ConstantTimeCompare([]byte(got), []byte(adminToken)) != 1 {
+			return nil, common.NewUnauthorized(middleware.ErrInvalidAdminToken, middleware.ErrInvalidAdminToken.Error(), "ErrUnauthorized")
+		}
+		return auth.WithAdmin(ctx), nil
+	}
+	return ctx, nil
+}
+
+// requestIDInterceptor keeps the request id sent in the metadata or generates one and puts it in the context,
+// the same as the http middleware, the id is sent back in the header of the response
+func requestIDInterceptor(ctx context.Context, req interface{}, info *grpclib.UnaryServerInfo, handler grpclib.UnaryHandler) (interface{}, error) {
+	return handler(withRequestID(ctx), req)
+}
+
+func withRequestID(ctx context.Context) context.Context {
+	id := firstMetadata(ctx, RequestIDMetadata)
+	if id == "" || len(id) > maxRequestIDLength {
+		id = util.GenUUID()
+	}
+	_ = grpclib.SetHeader(ctx, metadata.Pairs(RequestIDMetadata, id))
+	return requestid.With(ctx, id)
+}
+
+// streamInterceptor does for the streaming methods what the unary interceptors do, in the same order:
+// it maps the errors, recovers from a panic, sets the request id and checks the tokens
+func streamInterceptor(verifier middleware.TokenVerifier, adminToken string) grpclib.StreamServerInterceptor {
+	return func(srv interface{}, ss grpclib.ServerStream, info *grpclib.StreamServerInfo, handler grpclib.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Error(info.FullMethod+".Recover: ", r)
+				err = common.ErrInternal(fmt.Errorf("%v", r))
+			}
+			if err != nil {
+				logger.Error(info.FullMethod+": ", err)
+				err = toStatusError(err)
+			}
+		}()
+
+		ctx, err := authorize(withRequestID(ss.Context()), info.FullMethod, verifier, adminToken)
+		if err != nil {
+			return err
+		}
+		return handler(srv, contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// contextStream is the stream of a method with the context set by the interceptor
+type contextStream struct {
+	grpclib.ServerStream
+	ctx context.Context
+}
+
+func (s contextStream) Context() context.Context {
+	return s.ctx
+}
+
+func firstMetadata(ctx context.Context, key string) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
@@ -0,0 +1,1601 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: friendship.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	FriendshipService_Login_FullMethodName                     = "/friendship.v1.FriendshipService/Login"
+	FriendshipService_RestoreAccount_FullMethodName            = "/friendship.v1.FriendshipService/RestoreAccount"
+	FriendshipService_ConnectFriendship_FullMethodName         = "/friendship.v1.FriendshipService/ConnectFriendship"
+	FriendshipService_RequestFriendship_FullMethodName         = "/friendship.v1.FriendshipService/RequestFriendship"
+	FriendshipService_AcceptFriendship_FullMethodName          = "/friendship.v1.FriendshipService/AcceptFriendship"
+	FriendshipService_RejectFriendship_FullMethodName          = "/friendship.v1.FriendshipService/RejectFriendship"
+	FriendshipService_CancelFriendship_FullMethodName          = "/friendship.v1.FriendshipService/CancelFriendship"
+	FriendshipService_Unfriend_FullMethodName                  = "/friendship.v1.FriendshipService/Unfriend"
+	FriendshipService_SubscribeUser_FullMethodName             = "/friendship.v1.FriendshipService/SubscribeUser"
+	FriendshipService_BlockUpdatesUser_FullMethodName          = "/friendship.v1.FriendshipService/BlockUpdatesUser"
+	FriendshipService_UnblockUser_FullMethodName               = "/friendship.v1.FriendshipService/UnblockUser"
+	FriendshipService_PostUpdate_FullMethodName                = "/friendship.v1.FriendshipService/PostUpdate"
+	FriendshipService_UpdateUser_FullMethodName                = "/friendship.v1.FriendshipService/UpdateUser"
+	FriendshipService_DeleteUser_FullMethodName                = "/friendship.v1.FriendshipService/DeleteUser"
+	FriendshipService_ExportUser_FullMethodName                = "/friendship.v1.FriendshipService/ExportUser"
+	FriendshipService_CreateUser_FullMethodName                = "/friendship.v1.FriendshipService/CreateUser"
+	FriendshipService_GetUser_FullMethodName                   = "/friendship.v1.FriendshipService/GetUser"
+	FriendshipService_ListUserEmails_FullMethodName            = "/friendship.v1.FriendshipService/ListUserEmails"
+	FriendshipService_ListUserIDs_FullMethodName               = "/friendship.v1.FriendshipService/ListUserIDs"
+	FriendshipService_GetFriendship_FullMethodName             = "/friendship.v1.FriendshipService/GetFriendship"
+	FriendshipService_GetSubscription_FullMethodName           = "/friendship.v1.FriendshipService/GetSubscription"
+	FriendshipService_ListFriends_FullMethodName               = "/friendship.v1.FriendshipService/ListFriends"
+	FriendshipService_ListCommonFriends_FullMethodName         = "/friendship.v1.FriendshipService/ListCommonFriends"
+	FriendshipService_ListFriendSuggestions_FullMethodName     = "/friendship.v1.FriendshipService/ListFriendSuggestions"
+	FriendshipService_ListFriendSet_FullMethodName             = "/friendship.v1.FriendshipService/ListFriendSet"
+	FriendshipService_FindFriendshipPath_FullMethodName        = "/friendship.v1.FriendshipService/FindFriendshipPath"
+	FriendshipService_ListUpdatesUser_FullMethodName           = "/friendship.v1.FriendshipService/ListUpdatesUser"
+	FriendshipService_ListSubscribers_FullMethodName           = "/friendship.v1.FriendshipService/ListSubscribers"
+	FriendshipService_ListFriendRequests_FullMethodName        = "/friendship.v1.FriendshipService/ListFriendRequests"
+	FriendshipService_ListBlockedUsers_FullMethodName          = "/friendship.v1.FriendshipService/ListBlockedUsers"
+	FriendshipService_GetFeed_FullMethodName                   = "/friendship.v1.FriendshipService/GetFeed"
+	FriendshipService_SetPassword_FullMethodName               = "/friendship.v1.FriendshipService/SetPassword"
+	FriendshipService_ImportRelationships_FullMethodName       = "/friendship.v1.FriendshipService/ImportRelationships"
+	FriendshipService_ListAuditLog_FullMethodName              = "/friendship.v1.FriendshipService/ListAuditLog"
+	FriendshipService_ListBlockers_FullMethodName              = "/friendship.v1.FriendshipService/ListBlockers"
+	FriendshipService_RegisterWebhook_FullMethodName           = "/friendship.v1.FriendshipService/RegisterWebhook"
+	FriendshipService_ListWebhooks_FullMethodName              = "/friendship.v1.FriendshipService/ListWebhooks"
+	FriendshipService_DeleteWebhook_FullMethodName             = "/friendship.v1.FriendshipService/DeleteWebhook"
+	FriendshipService_ListDeadWebhookDeliveries_FullMethodName = "/friendship.v1.FriendshipService/ListDeadWebhookDeliveries"
+	FriendshipService_ReplayWebhookDelivery_FullMethodName     = "/friendship.v1.FriendshipService/ReplayWebhookDelivery"
+)
+
+// FriendshipServiceClient is the client API for FriendshipService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type FriendshipServiceClient interface {
+	Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginResponse, error)
+	// RestoreAccount cancels the scheduled deletion of the account, the account can't log in until then
+	// so its password authenticates the request instead of a token
+	RestoreAccount(ctx context.Context, in *RestoreAccountRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// requires the token of the requestor
+	// ConnectFriendship sends a friend request from the first of the friends to the second one
+	ConnectFriendship(ctx context.Context, in *ConnectFriendshipRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	RequestFriendship(ctx context.Context, in *RelationRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	AcceptFriendship(ctx context.Context, in *RelationRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	RejectFriendship(ctx context.Context, in *RelationRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	CancelFriendship(ctx context.Context, in *RelationRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	Unfriend(ctx context.Context, in *RelationRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	SubscribeUser(ctx context.Context, in *RelationRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	BlockUpdatesUser(ctx context.Context, in *RelationRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	UnblockUser(ctx context.Context, in *RelationRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	PostUpdate(ctx context.Context, in *PostUpdateRequest, opts ...grpc.CallOption) (*Update, error)
+	UpdateUser(ctx context.Context, in *UpdateUserRequest, opts ...grpc.CallOption) (*User, error)
+	// DeleteUser deletes the account of the user, scheduled for the end of the grace period
+	DeleteUser(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// ExportUser streams everything held about the user, the user in the first chunk and then the rows a chunk at a time
+	ExportUser(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (FriendshipService_ExportUserClient, error)
+	CreateUser(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*User, error)
+	GetUser(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*User, error)
+	ListUserEmails(ctx context.Context, in *ListUserEmailsRequest, opts ...grpc.CallOption) (*ListUserEmailsResponse, error)
+	ListUserIDs(ctx context.Context, in *ListUserIDsRequest, opts ...grpc.CallOption) (*ListUserIDsResponse, error)
+	GetFriendship(ctx context.Context, in *GetFriendshipRequest, opts ...grpc.CallOption) (*GetFriendshipResponse, error)
+	GetSubscription(ctx context.Context, in *GetSubscriptionRequest, opts ...grpc.CallOption) (*Subscription, error)
+	ListFriends(ctx context.Context, in *EmailPageRequest, opts ...grpc.CallOption) (*ListFriendsResponse, error)
+	ListCommonFriends(ctx context.Context, in *ListCommonFriendsRequest, opts ...grpc.CallOption) (*ListFriendsResponse, error)
+	ListFriendSuggestions(ctx context.Context, in *ListFriendSuggestionsRequest, opts ...grpc.CallOption) (*ListFriendSuggestionsResponse, error)
+	ListFriendSet(ctx context.Context, in *ListFriendSetRequest, opts ...grpc.CallOption) (*ListFriendSetResponse, error)
+	FindFriendshipPath(ctx context.Context, in *FindFriendshipPathRequest, opts ...grpc.CallOption) (*FindFriendshipPathResponse, error)
+	ListUpdatesUser(ctx context.Context, in *ListUpdatesUserRequest, opts ...grpc.CallOption) (*ListUpdatesUserResponse, error)
+	ListSubscribers(ctx context.Context, in *EmailPageRequest, opts ...grpc.CallOption) (*ListSubscribersResponse, error)
+	// ListFriendRequests, ListBlockedUsers and GetFeed require the token of the requestor and list their own friend requests,
+	// blocks and feed, the email of the request is not used
+	ListFriendRequests(ctx context.Context, in *ListFriendRequestsRequest, opts ...grpc.CallOption) (*ListFriendRequestsResponse, error)
+	ListBlockedUsers(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*ListBlockedUsersResponse, error)
+	GetFeed(ctx context.Context, in *EmailPageRequest, opts ...grpc.CallOption) (*GetFeedResponse, error)
+	// requires the admin token
+	SetPassword(ctx context.Context, in *SetPasswordRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// ImportRelationships writes the rows of the request as one batch of an import, in a single transaction
+	ImportRelationships(ctx context.Context, in *ImportRelationshipsRequest, opts ...grpc.CallOption) (*ImportRelationshipsResponse, error)
+	ListAuditLog(ctx context.Context, in *ListAuditLogRequest, opts ...grpc.CallOption) (*ListAuditLogResponse, error)
+	ListBlockers(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*ListBlockersResponse, error)
+	RegisterWebhook(ctx context.Context, in *RegisterWebhookRequest, opts ...grpc.CallOption) (*Webhook, error)
+	ListWebhooks(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListWebhooksResponse, error)
+	DeleteWebhook(ctx context.Context, in *WebhookRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListDeadWebhookDeliveries(ctx context.Context, in *WebhookRequest, opts ...grpc.CallOption) (*ListDeadWebhookDeliveriesResponse, error)
+	ReplayWebhookDelivery(ctx context.Context, in *ReplayWebhookDeliveryRequest, opts ...grpc.CallOption) (*WebhookDelivery, error)
+}
+
+type friendshipServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewFriendshipServiceClient(cc grpc.ClientConnInterface) FriendshipServiceClient {
+	return &friendshipServiceClient{cc}
+}
+
+func (c *friendshipServiceClient) Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginResponse, error) {
+	out := new(LoginResponse)
+	err := c.cc.Invoke(ctx, FriendshipService_Login_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *friendshipServiceClient) RestoreAccount(ctx context.Context, in *RestoreAccountRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, FriendshipService_RestoreAccount_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *friendshipServiceClient) ConnectFriendship(ctx context.Context, in *ConnectFriendshipRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, FriendshipService_ConnectFriendship_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *friendshipServiceClient) RequestFriendship(ctx context.Context, in *RelationRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, FriendshipService_RequestFriendship_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *friendshipServiceClient) AcceptFriendship(ctx context.Context, in *RelationRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, FriendshipService_AcceptFriendship_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *friendshipServiceClient) RejectFriendship(ctx context.Context, in *RelationRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, FriendshipService_RejectFriendship_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *friendshipServiceClient) CancelFriendship(ctx context.Context, in *RelationRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, FriendshipService_CancelFriendship_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *friendshipServiceClient) Unfriend(ctx context.Context, in *RelationRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, FriendshipService_Unfriend_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *friendshipServiceClient) SubscribeUser(ctx context.Context, in *RelationRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, FriendshipService_SubscribeUser_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *friendshipServiceClient) BlockUpdatesUser(ctx context.Context, in *RelationRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, FriendshipService_BlockUpdatesUser_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *friendshipServiceClient) UnblockUser(ctx context.Context, in *RelationRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, FriendshipService_UnblockUser_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *friendshipServiceClient) PostUpdate(ctx context.Context, in *PostUpdateRequest, opts ...grpc.CallOption) (*Update, error) {
+	out := new(Update)
+	err := c.cc.Invoke(ctx, FriendshipService_PostUpdate_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *friendshipServiceClient) UpdateUser(ctx context.Context, in *UpdateUserRequest, opts ...grpc.CallOption) (*User, error) {
+	out := new(User)
+	err := c.cc.Invoke(ctx, FriendshipService_UpdateUser_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *friendshipServiceClient) DeleteUser(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, FriendshipService_DeleteUser_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *friendshipServiceClient) ExportUser(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (FriendshipService_ExportUserClient, error) {
+	stream, err := c.cc.NewStream(ctx, &FriendshipService_ServiceDesc.Streams[0], FriendshipService_ExportUser_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &friendshipServiceExportUserClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type FriendshipService_ExportUserClient interface {
+	Recv() (*ExportUserChunk, error)
+	grpc.ClientStream
+}
+
+type friendshipServiceExportUserClient struct {
+	grpc.ClientStream
+}
+
+func (x *friendshipServiceExportUserClient) Recv() (*ExportUserChunk, error) {
+	m := new(ExportUserChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *friendshipServiceClient) CreateUser(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*User, error) {
+	out := new(User)
+	err := c.cc.Invoke(ctx, FriendshipService_CreateUser_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *friendshipServiceClient) GetUser(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*User, error) {
+	out := new(User)
+	err := c.cc.Invoke(ctx, FriendshipService_GetUser_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *friendshipServiceClient) ListUserEmails(ctx context.Context, in *ListUserEmailsRequest, opts ...grpc.CallOption) (*ListUserEmailsResponse, error) {
+	out := new(ListUserEmailsResponse)
+	err := c.cc.Invoke(ctx, FriendshipService_ListUserEmails_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *friendshipServiceClient) ListUserIDs(ctx context.Context, in *ListUserIDsRequest, opts ...grpc.CallOption) (*ListUserIDsResponse, error) {
+	out := new(ListUserIDsResponse)
+	err := c.cc.Invoke(ctx, FriendshipService_ListUserIDs_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *friendshipServiceClient) GetFriendship(ctx context.Context, in *GetFriendshipRequest, opts ...grpc.CallOption) (*GetFriendshipResponse, error) {
+	out := new(GetFriendshipResponse)
+	err := c.cc.Invoke(ctx, FriendshipService_GetFriendship_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *friendshipServiceClient) GetSubscription(ctx context.Context, in *GetSubscriptionRequest, opts ...grpc.CallOption) (*Subscription, error) {
+	out := new(Subscription)
+	err := c.cc.Invoke(ctx, FriendshipService_GetSubscription_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *friendshipServiceClient) ListFriends(ctx context.Context, in *EmailPageRequest, opts ...grpc.CallOption) (*ListFriendsResponse, error) {
+	out := new(ListFriendsResponse)
+	err := c.cc.Invoke(ctx, FriendshipService_ListFriends_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *friendshipServiceClient) ListCommonFriends(ctx context.Context, in *ListCommonFriendsRequest, opts ...grpc.CallOption) (*ListFriendsResponse, error) {
+	out := new(ListFriendsResponse)
+	err := c.cc.Invoke(ctx, FriendshipService_ListCommonFriends_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *friendshipServiceClient) ListFriendSuggestions(ctx context.Context, in *ListFriendSuggestionsRequest, opts ...grpc.CallOption) (*ListFriendSuggestionsResponse, error) {
+	out := new(ListFriendSuggestionsResponse)
+	err := c.cc.Invoke(ctx, FriendshipService_ListFriendSuggestions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *friendshipServiceClient) ListFriendSet(ctx context.Context, in *ListFriendSetRequest, opts ...grpc.CallOption) (*ListFriendSetResponse, error) {
+	out := new(ListFriendSetResponse)
+	err := c.cc.Invoke(ctx, FriendshipService_ListFriendSet_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *friendshipServiceClient) FindFriendshipPath(ctx context.Context, in *FindFriendshipPathRequest, opts ...grpc.CallOption) (*FindFriendshipPathResponse, error) {
+	out := new(FindFriendshipPathResponse)
+	err := c.cc.Invoke(ctx, FriendshipService_FindFriendshipPath_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *friendshipServiceClient) ListUpdatesUser(ctx context.Context, in *ListUpdatesUserRequest, opts ...grpc.CallOption) (*ListUpdatesUserResponse, error) {
+	out := new(ListUpdatesUserResponse)
+	err := c.cc.Invoke(ctx, FriendshipService_ListUpdatesUser_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *friendshipServiceClient) ListSubscribers(ctx context.Context, in *EmailPageRequest, opts ...grpc.CallOption) (*ListSubscribersResponse, error) {
+	out := new(ListSubscribersResponse)
+	err := c.cc.Invoke(ctx, FriendshipService_ListSubscribers_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *friendshipServiceClient) ListFriendRequests(ctx context.Context, in *ListFriendRequestsRequest, opts ...grpc.CallOption) (*ListFriendRequestsResponse, error) {
+	out := new(ListFriendRequestsResponse)
+	err := c.cc.Invoke(ctx, FriendshipService_ListFriendRequests_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *friendshipServiceClient) ListBlockedUsers(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*ListBlockedUsersResponse, error) {
+	out := new(ListBlockedUsersResponse)
+	err := c.cc.Invoke(ctx, FriendshipService_ListBlockedUsers_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *friendshipServiceClient) GetFeed(ctx context.Context, in *EmailPageRequest, opts ...grpc.CallOption) (*GetFeedResponse, error) {
+	out := new(GetFeedResponse)
+	err := c.cc.Invoke(ctx, FriendshipService_GetFeed_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *friendshipServiceClient) SetPassword(ctx context.Context, in *SetPasswordRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, FriendshipService_SetPassword_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *friendshipServiceClient) ImportRelationships(ctx context.Context, in *ImportRelationshipsRequest, opts ...grpc.CallOption) (*ImportRelationshipsResponse, error) {
+	out := new(ImportRelationshipsResponse)
+	err := c.cc.Invoke(ctx, FriendshipService_ImportRelationships_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *friendshipServiceClient) ListAuditLog(ctx context.Context, in *ListAuditLogRequest, opts ...grpc.CallOption) (*ListAuditLogResponse, error) {
+	out := new(ListAuditLogResponse)
+	err := c.cc.Invoke(ctx, FriendshipService_ListAuditLog_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *friendshipServiceClient) ListBlockers(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*ListBlockersResponse, error) {
+	out := new(ListBlockersResponse)
+	err := c.cc.Invoke(ctx, FriendshipService_ListBlockers_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *friendshipServiceClient) RegisterWebhook(ctx context.Context, in *RegisterWebhookRequest, opts ...grpc.CallOption) (*Webhook, error) {
+	out := new(Webhook)
+	err := c.cc.Invoke(ctx, FriendshipService_RegisterWebhook_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *friendshipServiceClient) ListWebhooks(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListWebhooksResponse, error) {
+	out := new(ListWebhooksResponse)
+	err := c.cc.Invoke(ctx, FriendshipService_ListWebhooks_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *friendshipServiceClient) DeleteWebhook(ctx context.Context, in *WebhookRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, FriendshipService_DeleteWebhook_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *friendshipServiceClient) ListDeadWebhookDeliveries(ctx context.Context, in *WebhookRequest, opts ...grpc.CallOption) (*ListDeadWebhookDeliveriesResponse, error) {
+	out := new(ListDeadWebhookDeliveriesResponse)
+	err := c.cc.Invoke(ctx, FriendshipService_ListDeadWebhookDeliveries_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *friendshipServiceClient) ReplayWebhookDelivery(ctx context.Context, in *ReplayWebhookDeliveryRequest, opts ...grpc.CallOption) (*WebhookDelivery, error) {
+	out := new(WebhookDelivery)
+	err := c.cc.Invoke(ctx, FriendshipService_ReplayWebhookDelivery_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// FriendshipServiceServer is the server API for FriendshipService service.
+// All implementations must embed UnimplementedFriendshipServiceServer
+// for forward compatibility
+type FriendshipServiceServer interface {
+	Login(context.Context, *LoginRequest) (*LoginResponse, error)
+	// RestoreAccount cancels the scheduled deletion of the account, the account can't log in until then
+	// so its password authenticates the request instead of a token
+	RestoreAccount(context.Context, *RestoreAccountRequest) (*emptypb.Empty, error)
+	// requires the token of the requestor
+	// ConnectFriendship sends a friend request from the first of the friends to the second one
+	ConnectFriendship(context.Context, *ConnectFriendshipRequest) (*emptypb.Empty, error)
+	RequestFriendship(context.Context, *RelationRequest) (*emptypb.Empty, error)
+	AcceptFriendship(context.Context, *RelationRequest) (*emptypb.Empty, error)
+	RejectFriendship(context.Context, *RelationRequest) (*emptypb.Empty, error)
+	CancelFriendship(context.Context, *RelationRequest) (*emptypb.Empty, error)
+	Unfriend(context.Context, *RelationRequest) (*emptypb.Empty, error)
+	SubscribeUser(context.Context, *RelationRequest) (*emptypb.Empty, error)
+	BlockUpdatesUser(context.Context, *RelationRequest) (*emptypb.Empty, error)
+	UnblockUser(context.Context, *RelationRequest) (*emptypb.Empty, error)
+	PostUpdate(context.Context, *PostUpdateRequest) (*Update, error)
+	UpdateUser(context.Context, *UpdateUserRequest) (*User, error)
+	// DeleteUser deletes the account of the user, scheduled for the end of the grace period
+	DeleteUser(context.Context, *UserRequest) (*emptypb.Empty, error)
+	// ExportUser streams everything held about the user, the user in the first chunk and then the rows a chunk at a time
+	ExportUser(*UserRequest, FriendshipService_ExportUserServer) error
+	CreateUser(context.Context, *CreateUserRequest) (*User, error)
+	GetUser(context.Context, *UserRequest) (*User, error)
+	ListUserEmails(context.Context, *ListUserEmailsRequest) (*ListUserEmailsResponse, error)
+	ListUserIDs(context.Context, *ListUserIDsRequest) (*ListUserIDsResponse, error)
+	GetFriendship(context.Context, *GetFriendshipRequest) (*GetFriendshipResponse, error)
+	GetSubscription(context.Context, *GetSubscriptionRequest) (*Subscription, error)
+	ListFriends(context.Context, *EmailPageRequest) (*ListFriendsResponse, error)
+	ListCommonFriends(context.Context, *ListCommonFriendsRequest) (*ListFriendsResponse, error)
+	ListFriendSuggestions(context.Context, *ListFriendSuggestionsRequest) (*ListFriendSuggestionsResponse, error)
+	ListFriendSet(context.Context, *ListFriendSetRequest) (*ListFriendSetResponse, error)
+	FindFriendshipPath(context.Context, *FindFriendshipPathRequest) (*FindFriendshipPathResponse, error)
+	ListUpdatesUser(context.Context, *ListUpdatesUserRequest) (*ListUpdatesUserResponse, error)
+	ListSubscribers(context.Context, *EmailPageRequest) (*ListSubscribersResponse, error)
+	// ListFriendRequests, ListBlockedUsers and GetFeed require the token of the requestor and list their own friend requests,
+	// blocks and feed, the email of the request is not used
+	ListFriendRequests(context.Context, *ListFriendRequestsRequest) (*ListFriendRequestsResponse, error)
+	ListBlockedUsers(context.Context, *UserRequest) (*ListBlockedUsersResponse, error)
+	GetFeed(context.Context, *EmailPageRequest) (*GetFeedResponse, error)
+	// requires the admin token
+	SetPassword(context.Context, *SetPasswordRequest) (*emptypb.Empty, error)
+	// ImportRelationships writes the rows of the request as one batch of an import, in a single transaction
+	ImportRelationships(context.Context, *ImportRelationshipsRequest) (*ImportRelationshipsResponse, error)
+	ListAuditLog(context.Context, *ListAuditLogRequest) (*ListAuditLogResponse, error)
+	ListBlockers(context.Context, *UserRequest) (*ListBlockersResponse, error)
+	RegisterWebhook(context.Context, *RegisterWebhookRequest) (*Webhook, error)
+	ListWebhooks(context.Context, *emptypb.Empty) (*ListWebhooksResponse, error)
+	DeleteWebhook(context.Context, *WebhookRequest) (*emptypb.Empty, error)
+	ListDeadWebhookDeliveries(context.Context, *WebhookRequest) (*ListDeadWebhookDeliveriesResponse, error)
+	ReplayWebhookDelivery(context.Context, *ReplayWebhookDeliveryRequest) (*WebhookDelivery, error)
+	mustEmbedUnimplementedFriendshipServiceServer()
+}
+
+// UnimplementedFriendshipServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedFriendshipServiceServer struct {
+}
+
+func (UnimplementedFriendshipServiceServer) Login(context.Context, *LoginRequest) (*LoginResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Login not implemented")
+}
+func (UnimplementedFriendshipServiceServer) RestoreAccount(context.Context, *RestoreAccountRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreAccount not implemented")
+}
+func (UnimplementedFriendshipServiceServer) ConnectFriendship(context.Context, *ConnectFriendshipRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConnectFriendship not implemented")
+}
+func (UnimplementedFriendshipServiceServer) RequestFriendship(context.Context, *RelationRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestFriendship not implemented")
+}
+func (UnimplementedFriendshipServiceServer) AcceptFriendship(context.Context, *RelationRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AcceptFriendship not implemented")
+}
+func (UnimplementedFriendshipServiceServer) RejectFriendship(context.Context, *RelationRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RejectFriendship not implemented")
+}
+func (UnimplementedFriendshipServiceServer) CancelFriendship(context.Context, *RelationRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelFriendship not implemented")
+}
+func (UnimplementedFriendshipServiceServer) Unfriend(context.Context, *RelationRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Unfriend not implemented")
+}
+func (UnimplementedFriendshipServiceServer) SubscribeUser(context.Context, *RelationRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubscribeUser not implemented")
+}
+func (UnimplementedFriendshipServiceServer) BlockUpdatesUser(context.Context, *RelationRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BlockUpdatesUser not implemented")
+}
+func (UnimplementedFriendshipServiceServer) UnblockUser(context.Context, *RelationRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnblockUser not implemented")
+}
+func (UnimplementedFriendshipServiceServer) PostUpdate(context.Context, *PostUpdateRequest) (*Update, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PostUpdate not implemented")
+}
+func (UnimplementedFriendshipServiceServer) UpdateUser(context.Context, *UpdateUserRequest) (*User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateUser not implemented")
+}
+func (UnimplementedFriendshipServiceServer) DeleteUser(context.Context, *UserRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteUser not implemented")
+}
+func (UnimplementedFriendshipServiceServer) ExportUser(*UserRequest, FriendshipService_ExportUserServer) error {
+	return status.Errorf(codes.Unimplemented, "method ExportUser not implemented")
+}
+func (UnimplementedFriendshipServiceServer) CreateUser(context.Context, *CreateUserRequest) (*User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateUser not implemented")
+}
+func (UnimplementedFriendshipServiceServer) GetUser(context.Context, *UserRequest) (*User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUser not implemented")
+}
+func (UnimplementedFriendshipServiceServer) ListUserEmails(context.Context, *ListUserEmailsRequest) (*ListUserEmailsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUserEmails not implemented")
+}
+func (UnimplementedFriendshipServiceServer) ListUserIDs(context.Context, *ListUserIDsRequest) (*ListUserIDsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUserIDs not implemented")
+}
+func (UnimplementedFriendshipServiceServer) GetFriendship(context.Context, *GetFriendshipRequest) (*GetFriendshipResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFriendship not implemented")
+}
+func (UnimplementedFriendshipServiceServer) GetSubscription(context.Context, *GetSubscriptionRequest) (*Subscription, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSubscription not implemented")
+}
+func (UnimplementedFriendshipServiceServer) ListFriends(context.Context, *EmailPageRequest) (*ListFriendsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFriends not implemented")
+}
+func (UnimplementedFriendshipServiceServer) ListCommonFriends(context.Context, *ListCommonFriendsRequest) (*ListFriendsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCommonFriends not implemented")
+}
+func (UnimplementedFriendshipServiceServer) ListFriendSuggestions(context.Context, *ListFriendSuggestionsRequest) (*ListFriendSuggestionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFriendSuggestions not implemented")
+}
+func (UnimplementedFriendshipServiceServer) ListFriendSet(context.Context, *ListFriendSetRequest) (*ListFriendSetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFriendSet not implemented")
+}
+func (UnimplementedFriendshipServiceServer) FindFriendshipPath(context.Context, *FindFriendshipPathRequest) (*FindFriendshipPathResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindFriendshipPath not implemented")
+}
+func (UnimplementedFriendshipServiceServer) ListUpdatesUser(context.Context, *ListUpdatesUserRequest) (*ListUpdatesUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUpdatesUser not implemented")
+}
+func (UnimplementedFriendshipServiceServer) ListSubscribers(context.Context, *EmailPageRequest) (*ListSubscribersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSubscribers not implemented")
+}
+func (UnimplementedFriendshipServiceServer) ListFriendRequests(context.Context, *ListFriendRequestsRequest) (*ListFriendRequestsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFriendRequests not implemented")
+}
+func (UnimplementedFriendshipServiceServer) ListBlockedUsers(context.Context, *UserRequest) (*ListBlockedUsersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBlockedUsers not implemented")
+}
+func (UnimplementedFriendshipServiceServer) GetFeed(context.Context, *EmailPageRequest) (*GetFeedResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFeed not implemented")
+}
+func (UnimplementedFriendshipServiceServer) SetPassword(context.Context, *SetPasswordRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetPassword not implemented")
+}
+func (UnimplementedFriendshipServiceServer) ImportRelationships(context.Context, *ImportRelationshipsRequest) (*ImportRelationshipsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportRelationships not implemented")
+}
+func (UnimplementedFriendshipServiceServer) ListAuditLog(context.Context, *ListAuditLogRequest) (*ListAuditLogResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAuditLog not implemented")
+}
+func (UnimplementedFriendshipServiceServer) ListBlockers(context.Context, *UserRequest) (*ListBlockersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBlockers not implemented")
+}
+func (UnimplementedFriendshipServiceServer) RegisterWebhook(context.Context, *RegisterWebhookRequest) (*Webhook, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterWebhook not implemented")
+}
+func (UnimplementedFriendshipServiceServer) ListWebhooks(context.Context, *emptypb.Empty) (*ListWebhooksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWebhooks not implemented")
+}
+func (UnimplementedFriendshipServiceServer) DeleteWebhook(context.Context, *WebhookRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteWebhook not implemented")
+}
+func (UnimplementedFriendshipServiceServer) ListDeadWebhookDeliveries(context.Context, *WebhookRequest) (*ListDeadWebhookDeliveriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDeadWebhookDeliveries not implemented")
+}
+func (UnimplementedFriendshipServiceServer) ReplayWebhookDelivery(context.Context, *ReplayWebhookDeliveryRequest) (*WebhookDelivery, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReplayWebhookDelivery not implemented")
+}
+func (UnimplementedFriendshipServiceServer) mustEmbedUnimplementedFriendshipServiceServer() {}
+
+// UnsafeFriendshipServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to FriendshipServiceServer will
+// result in compilation errors.
+type UnsafeFriendshipServiceServer interface {
+	mustEmbedUnimplementedFriendshipServiceServer()
+}
+
+func RegisterFriendshipServiceServer(s grpc.ServiceRegistrar, srv FriendshipServiceServer) {
+	s.RegisterService(&FriendshipService_ServiceDesc, srv)
+}
+
+func _FriendshipService_Login_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoginRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FriendshipServiceServer).Login(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FriendshipService_Login_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FriendshipServiceServer).Login(ctx, req.(*LoginRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FriendshipService_RestoreAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FriendshipServiceServer).RestoreAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FriendshipService_RestoreAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FriendshipServiceServer).RestoreAccount(ctx, req.(*RestoreAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FriendshipService_ConnectFriendship_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConnectFriendshipRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FriendshipServiceServer).ConnectFriendship(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FriendshipService_ConnectFriendship_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FriendshipServiceServer).ConnectFriendship(ctx, req.(*ConnectFriendshipRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FriendshipService_RequestFriendship_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RelationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FriendshipServiceServer).RequestFriendship(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FriendshipService_RequestFriendship_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FriendshipServiceServer).RequestFriendship(ctx, req.(*RelationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FriendshipService_AcceptFriendship_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RelationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FriendshipServiceServer).AcceptFriendship(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FriendshipService_AcceptFriendship_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FriendshipServiceServer).AcceptFriendship(ctx, req.(*RelationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FriendshipService_RejectFriendship_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RelationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FriendshipServiceServer).RejectFriendship(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FriendshipService_RejectFriendship_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FriendshipServiceServer).RejectFriendship(ctx, req.(*RelationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FriendshipService_CancelFriendship_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RelationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FriendshipServiceServer).CancelFriendship(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FriendshipService_CancelFriendship_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FriendshipServiceServer).CancelFriendship(ctx, req.(*RelationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FriendshipService_Unfriend_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RelationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FriendshipServiceServer).Unfriend(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FriendshipService_Unfriend_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FriendshipServiceServer).Unfriend(ctx, req.(*RelationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FriendshipService_SubscribeUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RelationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FriendshipServiceServer).SubscribeUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FriendshipService_SubscribeUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FriendshipServiceServer).SubscribeUser(ctx, req.(*RelationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FriendshipService_BlockUpdatesUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RelationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FriendshipServiceServer).BlockUpdatesUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FriendshipService_BlockUpdatesUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FriendshipServiceServer).BlockUpdatesUser(ctx, req.(*RelationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FriendshipService_UnblockUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RelationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FriendshipServiceServer).UnblockUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FriendshipService_UnblockUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FriendshipServiceServer).UnblockUser(ctx, req.(*RelationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FriendshipService_PostUpdate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PostUpdateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FriendshipServiceServer).PostUpdate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FriendshipService_PostUpdate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FriendshipServiceServer).PostUpdate(ctx, req.(*PostUpdateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FriendshipService_UpdateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FriendshipServiceServer).UpdateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FriendshipService_UpdateUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FriendshipServiceServer).UpdateUser(ctx, req.(*UpdateUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FriendshipService_DeleteUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FriendshipServiceServer).DeleteUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FriendshipService_DeleteUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FriendshipServiceServer).DeleteUser(ctx, req.(*UserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FriendshipService_ExportUser_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(UserRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FriendshipServiceServer).ExportUser(m, &friendshipServiceExportUserServer{stream})
+}
+
+type FriendshipService_ExportUserServer interface {
+	Send(*ExportUserChunk) error
+	grpc.ServerStream
+}
+
+type friendshipServiceExportUserServer struct {
+	grpc.ServerStream
+}
+
+func (x *friendshipServiceExportUserServer) Send(m *ExportUserChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _FriendshipService_CreateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FriendshipServiceServer).CreateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FriendshipService_CreateUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FriendshipServiceServer).CreateUser(ctx, req.(*CreateUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FriendshipService_GetUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FriendshipServiceServer).GetUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FriendshipService_GetUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FriendshipServiceServer).GetUser(ctx, req.(*UserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FriendshipService_ListUserEmails_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUserEmailsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FriendshipServiceServer).ListUserEmails(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FriendshipService_ListUserEmails_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FriendshipServiceServer).ListUserEmails(ctx, req.(*ListUserEmailsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FriendshipService_ListUserIDs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUserIDsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FriendshipServiceServer).ListUserIDs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FriendshipService_ListUserIDs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FriendshipServiceServer).ListUserIDs(ctx, req.(*ListUserIDsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FriendshipService_GetFriendship_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFriendshipRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FriendshipServiceServer).GetFriendship(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FriendshipService_GetFriendship_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FriendshipServiceServer).GetFriendship(ctx, req.(*GetFriendshipRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FriendshipService_GetSubscription_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSubscriptionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FriendshipServiceServer).GetSubscription(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FriendshipService_GetSubscription_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FriendshipServiceServer).GetSubscription(ctx, req.(*GetSubscriptionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FriendshipService_ListFriends_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmailPageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FriendshipServiceServer).ListFriends(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FriendshipService_ListFriends_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FriendshipServiceServer).ListFriends(ctx, req.(*EmailPageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FriendshipService_ListCommonFriends_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCommonFriendsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FriendshipServiceServer).ListCommonFriends(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FriendshipService_ListCommonFriends_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FriendshipServiceServer).ListCommonFriends(ctx, req.(*ListCommonFriendsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FriendshipService_ListFriendSuggestions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFriendSuggestionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FriendshipServiceServer).ListFriendSuggestions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FriendshipService_ListFriendSuggestions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FriendshipServiceServer).ListFriendSuggestions(ctx, req.(*ListFriendSuggestionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FriendshipService_ListFriendSet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFriendSetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FriendshipServiceServer).ListFriendSet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FriendshipService_ListFriendSet_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FriendshipServiceServer).ListFriendSet(ctx, req.(*ListFriendSetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FriendshipService_FindFriendshipPath_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindFriendshipPathRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FriendshipServiceServer).FindFriendshipPath(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FriendshipService_FindFriendshipPath_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FriendshipServiceServer).FindFriendshipPath(ctx, req.(*FindFriendshipPathRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FriendshipService_ListUpdatesUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUpdatesUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FriendshipServiceServer).ListUpdatesUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FriendshipService_ListUpdatesUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FriendshipServiceServer).ListUpdatesUser(ctx, req.(*ListUpdatesUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FriendshipService_ListSubscribers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmailPageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FriendshipServiceServer).ListSubscribers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FriendshipService_ListSubscribers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FriendshipServiceServer).ListSubscribers(ctx, req.(*EmailPageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FriendshipService_ListFriendRequests_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFriendRequestsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FriendshipServiceServer).ListFriendRequests(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FriendshipService_ListFriendRequests_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FriendshipServiceServer).ListFriendRequests(ctx, req.(*ListFriendRequestsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FriendshipService_ListBlockedUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FriendshipServiceServer).ListBlockedUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FriendshipService_ListBlockedUsers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FriendshipServiceServer).ListBlockedUsers(ctx, req.(*UserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FriendshipService_GetFeed_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmailPageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FriendshipServiceServer).GetFeed(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FriendshipService_GetFeed_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FriendshipServiceServer).GetFeed(ctx, req.(*EmailPageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FriendshipService_SetPassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetPasswordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FriendshipServiceServer).SetPassword(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FriendshipService_SetPassword_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FriendshipServiceServer).SetPassword(ctx, req.(*SetPasswordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FriendshipService_ImportRelationships_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportRelationshipsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FriendshipServiceServer).ImportRelationships(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FriendshipService_ImportRelationships_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FriendshipServiceServer).ImportRelationships(ctx, req.(*ImportRelationshipsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FriendshipService_ListAuditLog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAuditLogRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FriendshipServiceServer).ListAuditLog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FriendshipService_ListAuditLog_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FriendshipServiceServer).ListAuditLog(ctx, req.(*ListAuditLogRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FriendshipService_ListBlockers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FriendshipServiceServer).ListBlockers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FriendshipService_ListBlockers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FriendshipServiceServer).ListBlockers(ctx, req.(*UserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FriendshipService_RegisterWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FriendshipServiceServer).RegisterWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FriendshipService_RegisterWebhook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FriendshipServiceServer).RegisterWebhook(ctx, req.(*RegisterWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FriendshipService_ListWebhooks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FriendshipServiceServer).ListWebhooks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FriendshipService_ListWebhooks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FriendshipServiceServer).ListWebhooks(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FriendshipService_DeleteWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FriendshipServiceServer).DeleteWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FriendshipService_DeleteWebhook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FriendshipServiceServer).DeleteWebhook(ctx, req.(*WebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FriendshipService_ListDeadWebhookDeliveries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FriendshipServiceServer).ListDeadWebhookDeliveries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FriendshipService_ListDeadWebhookDeliveries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FriendshipServiceServer).ListDeadWebhookDeliveries(ctx, req.(*WebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FriendshipService_ReplayWebhookDelivery_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplayWebhookDeliveryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FriendshipServiceServer).ReplayWebhookDelivery(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FriendshipService_ReplayWebhookDelivery_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FriendshipServiceServer).ReplayWebhookDelivery(ctx, req.(*ReplayWebhookDeliveryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// FriendshipService_ServiceDesc is the grpc.ServiceDesc for FriendshipService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var FriendshipService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "friendship.v1.FriendshipService",
+	HandlerType: (*FriendshipServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Login",
+			Handler:    _FriendshipService_Login_Handler,
+		},
+		{
+			MethodName: "RestoreAccount",
+			Handler:    _FriendshipService_RestoreAccount_Handler,
+		},
+		{
+			MethodName: "ConnectFriendship",
+			Handler:    _FriendshipService_ConnectFriendship_Handler,
+		},
+		{
+			MethodName: "RequestFriendship",
+			Handler:    _FriendshipService_RequestFriendship_Handler,
+		},
+		{
+			MethodName: "AcceptFriendship",
+			Handler:    _FriendshipService_AcceptFriendship_Handler,
+		},
+		{
+			MethodName: "RejectFriendship",
+			Handler:    _FriendshipService_RejectFriendship_Handler,
+		},
+		{
+			MethodName: "CancelFriendship",
+			Handler:    _FriendshipService_CancelFriendship_Handler,
+		},
+		{
+			MethodName: "Unfriend",
+			Handler:    _FriendshipService_Unfriend_Handler,
+		},
+		{
+			MethodName: "SubscribeUser",
+			Handler:    _FriendshipService_SubscribeUser_Handler,
+		},
+		{
+			MethodName: "BlockUpdatesUser",
+			Handler:    _FriendshipService_BlockUpdatesUser_Handler,
+		},
+		{
+			MethodName: "UnblockUser",
+			Handler:    _FriendshipService_UnblockUser_Handler,
+		},
+		{
+			MethodName: "PostUpdate",
+			Handler:    _FriendshipService_PostUpdate_Handler,
+		},
+		{
+			MethodName: "UpdateUser",
+			Handler:    _FriendshipService_UpdateUser_Handler,
+		},
+		{
+			MethodName: "DeleteUser",
+			Handler:    _FriendshipService_DeleteUser_Handler,
+		},
+		{
+			MethodName: "CreateUser",
+			Handler:    _FriendshipService_CreateUser_Handler,
+		},
+		{
+			MethodName: "GetUser",
+			Handler:    _FriendshipService_GetUser_Handler,
+		},
+		{
+			MethodName: "ListUserEmails",
+			Handler:    _FriendshipService_ListUserEmails_Handler,
+		},
+		{
+			MethodName: "ListUserIDs",
+			Handler:    _FriendshipService_ListUserIDs_Handler,
+		},
+		{
+			MethodName: "GetFriendship",
+			Handler:    _FriendshipService_GetFriendship_Handler,
+		},
+		{
+			MethodName: "GetSubscription",
+			Handler:    _FriendshipService_GetSubscription_Handler,
+		},
+		{
+			MethodName: "ListFriends",
+			Handler:    _FriendshipService_ListFriends_Handler,
+		},
+		{
+			MethodName: "ListCommonFriends",
+			Handler:    _FriendshipService_ListCommonFriends_Handler,
+		},
+		{
+			MethodName: "ListFriendSuggestions",
+			Handler:    _FriendshipService_ListFriendSuggestions_Handler,
+		},
+		{
+			MethodName: "ListFriendSet",
+			Handler:    _FriendshipService_ListFriendSet_Handler,
+		},
+		{
+			MethodName: "FindFriendshipPath",
+			Handler:    _FriendshipService_FindFriendshipPath_Handler,
+		},
+		{
+			MethodName: "ListUpdatesUser",
+			Handler:    _FriendshipService_ListUpdatesUser_Handler,
+		},
+		{
+			MethodName: "ListSubscribers",
+			Handler:    _FriendshipService_ListSubscribers_Handler,
+		},
+		{
+			MethodName: "ListFriendRequests",
+			Handler:    _FriendshipService_ListFriendRequests_Handler,
+		},
+		{
+			MethodName: "ListBlockedUsers",
+			Handler:    _FriendshipService_ListBlockedUsers_Handler,
+		},
+		{
+			MethodName: "GetFeed",
+			Handler:    _FriendshipService_GetFeed_Handler,
+		},
+		{
+			MethodName: "SetPassword",
+			Handler:    _FriendshipService_SetPassword_Handler,
+		},
+		{
+			MethodName: "ImportRelationships",
+			Handler:    _FriendshipService_ImportRelationships_Handler,
+		},
+		{
+			MethodName: "ListAuditLog",
+			Handler:    _FriendshipService_ListAuditLog_Handler,
+		},
+		{
+			MethodName: "ListBlockers",
+			Handler:    _FriendshipService_ListBlockers_Handler,
+		},
+		{
+			MethodName: "RegisterWebhook",
+			Handler:    _FriendshipService_RegisterWebhook_Handler,
+		},
+		{
+			MethodName: "ListWebhooks",
+			Handler:    _FriendshipService_ListWebhooks_Handler,
+		},
+		{
+			MethodName: "DeleteWebhook",
+			Handler:    _FriendshipService_DeleteWebhook_Handler,
+		},
+		{
+			MethodName: "ListDeadWebhookDeliveries",
+			Handler:    _FriendshipService_ListDeadWebhookDeliveries_Handler,
+		},
+		{
+			MethodName: "ReplayWebhookDelivery",
+			Handler:    _FriendshipService_ReplayWebhookDelivery_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ExportUser",
+			Handler:       _FriendshipService_ExportUser_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "friendship.proto",
+}
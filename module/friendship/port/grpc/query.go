@@ -0,0 +1,348 @@
+package grpc
+
+import (
+	"context"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/auth"
+	"github.com/phantranhieunhan/s3-assignment/middleware"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/port"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/port/grpc/pb"
+)
+
+func (s *Server) GetUser(ctx context.Context, req *pb.UserRequest) (*pb.User, error) {
+	if err := (port.UserReq{Email: req.GetEmail()}).Validate(); err != nil {
+		return nil, err
+	}
+
+	user, err := s.app.Queries.GetUser.Handle(ctx, req.GetEmail())
+	if err != nil {
+		return nil, err
+	}
+
+	return toUser(user), nil
+}
+
+func (s *Server) ListUserEmails(ctx context.Context, req *pb.ListUserEmailsRequest) (*pb.ListUserEmailsResponse, error) {
+	emails, err := s.app.Queries.ListUserEmails.Handle(ctx, req.GetUserIds())
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ListUserEmailsResponse{Emails: emails}, nil
+}
+
+func (s *Server) ListUserIDs(ctx context.Context, req *pb.ListUserIDsRequest) (*pb.ListUserIDsResponse, error) {
+	for _, email := range req.GetEmails() {
+		if err := (port.UserReq{Email: email}).Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	ids, err := s.app.Queries.ListUserIDs.Handle(ctx, req.GetEmails())
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ListUserIDsResponse{UserIds: ids}, nil
+}
+
+// GetFriendship returns the friendship between the two users whoever made it, unset when they never connected
+func (s *Server) GetFriendship(ctx context.Context, req *pb.GetFriendshipRequest) (*pb.GetFriendshipResponse, error) {
+	if err := (port.ConnectFriendshipReq{Friends: []string{req.GetUser(), req.GetFriend()}}).Validate(); err != nil {
+		return nil, err
+	}
+
+	f, err := s.app.Queries.GetFriendship.Handle(ctx, req.GetUser(), req.GetFriend())
+	if err != nil {
+		return nil, err
+	}
+
+	if f.Base.Id == "" {
+		return &pb.GetFriendshipResponse{}, nil
+	}
+	return &pb.GetFriendshipResponse{Friendship: toFriendship(f)}, nil
+}
+
+// GetSubscription returns the subscription of the subscriber to the updates of the target, NONE when they never subscribed
+func (s *Server) GetSubscription(ctx context.Context, req *pb.GetSubscriptionRequest) (*pb.Subscription, error) {
+	if err := (port.SubscribeUserReq{Requestor: req.GetSubscriber(), Target: req.GetTarget()}).Validate(); err != nil {
+		return nil, err
+	}
+
+	sub, err := s.app.Queries.GetSubscription.Handle(ctx, req.GetSubscriber(), req.GetTarget())
+	if err != nil {
+		return nil, err
+	}
+
+	return toSubscription(sub), nil
+}
+
+func (s *Server) ListFriends(ctx context.Context, req *pb.EmailPageRequest) (*pb.ListFriendsResponse, error) {
+	if err := (port.ListFriendsReq{Email: req.GetEmail()}).Validate(); err != nil {
+		return nil, err
+	}
+	page, err := toPage(req.GetPage())
+	if err != nil {
+		return nil, err
+	}
+
+	list, nextCursor, err := s.app.Queries.ListFriends.Handle(ctx, req.GetEmail(), page)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ListFriendsResponse{Friends: list, Count: int32(len(list)), Paging: toPaging(page, nextCursor)}, nil
+}
+
+func (s *Server) ListCommonFriends(ctx context.Context, req *pb.ListCommonFriendsRequest) (*pb.ListFriendsResponse, error) {
+	if err := (port.ListCommonFriendsReq{Friends: req.GetFriends()}).Validate(); err != nil {
+		return nil, err
+	}
+	page, err := toPage(req.GetPage())
+	if err != nil {
+		return nil, err
+	}
+
+	list, nextCursor, err := s.app.Queries.ListCommonFriends.Handle(ctx, req.GetFriends(), page)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ListFriendsResponse{Friends: list, Count: int32(len(list)), Paging: toPaging(page, nextCursor)}, nil
+}
+
+func (s *Server) ListFriendSuggestions(ctx context.Context, req *pb.ListFriendSuggestionsRequest) (*pb.ListFriendSuggestionsResponse, error) {
+	r := port.ListFriendSuggestionsReq{Email: req.GetEmail(), Limit: int(req.GetLimit())}
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	if r.Limit == 0 {
+		r.Limit = port.DefaultSuggestionLimit
+	}
+
+	list, err := s.app.Queries.ListFriendSuggestions.Handle(ctx, r.Email, r.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := make([]*pb.FriendSuggestion, 0, len(list))
+	for _, f := range list {
+		suggestions = append(suggestions, &pb.FriendSuggestion{Email: f.Email, MutualFriends: int32(f.MutualFriends)})
+	}
+	return &pb.ListFriendSuggestionsResponse{Suggestions: suggestions, Count: int32(len(suggestions))}, nil
+}
+
+func (s *Server) ListFriendSet(ctx context.Context, req *pb.ListFriendSetRequest) (*pb.ListFriendSetResponse, error) {
+	if err := (port.ListFriendSetReq{Friends: req.GetFriends(), Operation: req.GetOperation()}).Validate(); err != nil {
+		return nil, err
+	}
+
+	list, err := s.app.Queries.ListFriendSet.Handle(ctx, req.GetFriends(), domain.FriendSetOperation(req.GetOperation()))
+	if err != nil {
+		return nil, err
+	}
+
+	friends := make([]*pb.FriendSetMember, 0, len(list))
+	for _, f := range list {
+		friends = append(friends, &pb.FriendSetMember{Email: f.Email, ConnectedUsers: int32(f.ConnectedUsers)})
+	}
+	return &pb.ListFriendSetResponse{Friends: friends, Count: int32(len(friends))}, nil
+}
+
+func (s *Server) FindFriendshipPath(ctx context.Context, req *pb.FindFriendshipPathRequest) (*pb.FindFriendshipPathResponse, error) {
+	r := port.FindFriendshipPathReq{From: req.GetFrom(), To: req.GetTo(), MaxDepth: int(req.GetMaxDepth())}
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	if r.MaxDepth == 0 {
+		r.MaxDepth = port.DefaultPathMaxDepth
+	}
+
+	path, err := s.app.Queries.FindFriendshipPath.Handle(ctx, r.From, r.To, r.MaxDepth)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &pb.FindFriendshipPathResponse{Connected: len(path) > 0, Path: path}
+	if res.Connected {
+		res.Degrees = int32(len(path) - 1)
+	}
+	return res, nil
+}
+
+func (s *Server) ListFriendRequests(ctx context.Context, req *pb.ListFriendRequestsRequest) (*pb.ListFriendRequestsResponse, error) {
+	// the friend requests are the ones of the authenticated user, whatever the email of the request
+	userID, ok := auth.UserIDFromContext(ctx)
+	if !ok {
+		return nil, common.NewUnauthorized(middleware.ErrMissingToken, middleware.ErrMissingToken.Error(), "ErrUnauthorized")
+	}
+	direction := domain.FriendRequestDirection(req.GetDirection())
+	if direction != domain.FriendRequestDirectionIncoming && direction != domain.FriendRequestDirectionOutgoing {
+		return nil, common.ErrInvalidRequest(nil, "direction")
+	}
+
+	list, err := s.app.Queries.ListFriendRequests.Handle(ctx, userID, direction)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ListFriendRequestsResponse{Requests: list, Count: int32(len(list))}, nil
+}
+
+func (s *Server) ListUpdatesUser(ctx context.Context, req *pb.ListUpdatesUserRequest) (*pb.ListUpdatesUserResponse, error) {
+	if err := (port.ListUpdatesUserReq{Sender: req.GetSender(), Text: req.GetText()}).Validate(); err != nil {
+		return nil, err
+	}
+	page, err := toPage(req.GetPage())
+	if err != nil {
+		return nil, err
+	}
+
+	list, nextCursor, err := s.app.Queries.ListUpdatesUser.Handle(ctx, req.GetSender(), req.GetText(), page)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ListUpdatesUserResponse{Recipients: list, Paging: toPaging(page, nextCursor)}, nil
+}
+
+func (s *Server) ListSubscribers(ctx context.Context, req *pb.EmailPageRequest) (*pb.ListSubscribersResponse, error) {
+	if err := (port.ListSubscribersReq{Email: req.GetEmail()}).Validate(); err != nil {
+		return nil, err
+	}
+	page, err := toPage(req.GetPage())
+	if err != nil {
+		return nil, err
+	}
+
+	list, nextCursor, err := s.app.Queries.ListSubscribers.Handle(ctx, req.GetEmail(), page)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ListSubscribersResponse{Subscribers: list, Count: int32(len(list)), Paging: toPaging(page, nextCursor)}, nil
+}
+
+func (s *Server) ListBlockedUsers(ctx context.Context, req *pb.UserRequest) (*pb.ListBlockedUsersResponse, error) {
+	// the blocked users are the ones of the authenticated user, whatever the email of the request
+	userID, ok := auth.UserIDFromContext(ctx)
+	if !ok {
+		return nil, common.NewUnauthorized(middleware.ErrMissingToken, middleware.ErrMissingToken.Error(), "ErrUnauthorized")
+	}
+
+	list, err := s.app.Queries.ListBlockedUsers.Handle(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ListBlockedUsersResponse{Blocked: toBlockedEmails(list), Count: int32(len(list))}, nil
+}
+
+// ListBlockers is the admin variant listing who has blocked the user, for abuse review
+func (s *Server) ListBlockers(ctx context.Context, req *pb.UserRequest) (*pb.ListBlockersResponse, error) {
+	if err := (port.ListBlockersReq{Email: req.GetEmail()}).Validate(); err != nil {
+		return nil, err
+	}
+
+	list, err := s.app.Queries.ListBlockers.Handle(ctx, req.GetEmail())
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ListBlockersResponse{Blockers: toBlockedEmails(list), Count: int32(len(list))}, nil
+}
+
+func (s *Server) GetFeed(ctx context.Context, req *pb.EmailPageRequest) (*pb.GetFeedResponse, error) {
+	// the feed is the one of the authenticated user, whatever the email of the request
+	userID, ok := auth.UserIDFromContext(ctx)
+	if !ok {
+		return nil, common.NewUnauthorized(middleware.ErrMissingToken, middleware.ErrMissingToken.Error(), "ErrUnauthorized")
+	}
+	page, err := toPage(req.GetPage())
+	if err == nil {
+		page, err = port.FeedPage(req.GetPage().GetSort(), page)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	feed, nextCursor, err := s.app.Queries.GetFeed.Handle(ctx, userID, page)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make([]*pb.FeedItem, 0, len(feed))
+	for _, f := range feed {
+		updates = append(updates, &pb.FeedItem{Id: f.ID, Sender: f.Sender, Text: f.Text, CreatedAt: toTimestamp(f.CreatedAt)})
+	}
+	return &pb.GetFeedResponse{Updates: updates, Count: int32(len(updates)), Paging: toPaging(page, nextCursor)}, nil
+}
+
+func (s *Server) ListWebhooks(ctx context.Context, _ *emptypb.Empty) (*pb.ListWebhooksResponse, error) {
+	list, err := s.app.Queries.ListWebhooks.Handle(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	webhooks := make([]*pb.Webhook, 0, len(list))
+	for _, w := range list {
+		webhooks = append(webhooks, toWebhook(w))
+	}
+	return &pb.ListWebhooksResponse{Webhooks: webhooks, Count: int32(len(webhooks))}, nil
+}
+
+// ListDeadWebhookDeliveries lists the dead letters of the webhook, the deliveries which ran out of attempts
+func (s *Server) ListDeadWebhookDeliveries(ctx context.Context, req *pb.WebhookRequest) (*pb.ListDeadWebhookDeliveriesResponse, error) {
+	list, err := s.app.Queries.ListDeadWebhookDeliveries.Handle(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := make([]*pb.WebhookDelivery, 0, len(list))
+	for _, d := range list {
+		deliveries = append(deliveries, toWebhookDelivery(d))
+	}
+	return &pb.ListDeadWebhookDeliveriesResponse{Deliveries: deliveries, Count: int32(len(deliveries))}, nil
+}
+
+// ListAuditLog lists the audit entries matching the filter, the newest first
+func (s *Server) ListAuditLog(ctx context.Context, req *pb.ListAuditLogRequest) (*pb.ListAuditLogResponse, error) {
+	r := port.ListAuditLogReq{
+		User:   req.GetUser(),
+		Action: req.GetAction(),
+		From:   req.GetFrom(),
+		To:     req.GetTo(),
+		PageReq: port.PageReq{
+			Limit:  int(req.GetPage().GetLimit()),
+			Cursor: req.GetPage().GetCursor(),
+			Sort:   req.GetPage().GetSort(),
+			Since:  req.GetPage().GetSince(),
+		},
+	}
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	filter, err := r.ToFilter()
+	if err != nil {
+		return nil, err
+	}
+	page, err := r.ToPage()
+	if err != nil {
+		return nil, err
+	}
+
+	list, nextCursor, err := s.app.Queries.ListAuditLog.Handle(ctx, filter, page)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*pb.AuditEntry, 0, len(list))
+	for _, e := range list {
+		entries = append(entries, toAuditEntry(e))
+	}
+	return &pb.ListAuditLogResponse{Entries: entries, Count: int32(len(entries)), Paging: toPaging(page, nextCursor)}, nil
+}
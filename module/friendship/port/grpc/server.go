@@ -0,0 +1,160 @@
+package grpc
+
+import (
+	"time"
+
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/phantranhieunhan/s3-assignment/middleware"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/port"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/port/grpc/pb"
+)
+
+var friendshipStatuses = map[domain.FriendshipStatus]string{
+	domain.FriendshipStatusFriended:   "FRIENDED",
+	domain.FriendshipStatusPending:    "PENDING",
+	domain.FriendshipStatusUnfriended: "UNFRIENDED",
+	domain.FriendshipStatusBlocked:    "BLOCKED",
+}
+
+var subscriptionStatuses = map[domain.SubscriptionStatus]string{
+	domain.SubscriptionStatusInvalid:      "NONE",
+	domain.SubscriptionStatusSubscribed:   "SUBSCRIBED",
+	domain.SubscriptionStatusUnsubscribed: "UNSUBSCRIBED",
+}
+
+// Server serves the application over grpc, it validates the requests with the same rules as the http port
+type Server struct {
+	pb.UnimplementedFriendshipServiceServer
+
+	app app.Application
+}
+
+func NewServer(app app.Application) *Server {
+	return &Server{app: app}
+}
+
+// NewGRPCServer builds a grpc server serving the application, the errors are mapped to grpc status codes
+// and the tokens are checked from the metadata the same as the http headers
+func NewGRPCServer(app app.Application, verifier middleware.TokenVerifier, adminToken string, opts ...grpclib.ServerOption) *grpclib.Server {
+	opts = append(opts, grpclib.ChainUnaryInterceptor(
+		errorInterceptor,
+		recoverInterceptor,
+		requestIDInterceptor,
+		authInterceptor(verifier, adminToken),
+	), grpclib.StreamInterceptor(streamInterceptor(verifier, adminToken)))
+	s := grpclib.NewServer(opts...)
+	pb.RegisterFriendshipServiceServer(s, NewServer(app))
+
+	return s
+}
+
+func toPage(p *pb.Page) (domain.Page, error) {
+	return port.PageReq{
+		Limit:  int(p.GetLimit()),
+		Cursor: p.GetCursor(),
+		Sort:   p.GetSort(),
+		Since:  p.GetSince(),
+	}.ToPage()
+}
+
+func toPaging(page domain.Page, nextCursor string) *pb.Paging {
+	return &pb.Paging{
+		Limit:      int32(page.Limit),
+		Cursor:     page.Cursor,
+		NextCursor: nextCursor,
+		Sort:       string(page.Sort),
+	}
+}
+
+func toTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func toUser(user domain.User) *pb.User {
+	return &pb.User{
+		Id:        user.Base.Id,
+		Email:     user.Email,
+		CreatedAt: toTimestamp(user.Base.CreatedAt),
+		UpdatedAt: toTimestamp(user.Base.UpdatedAt),
+	}
+}
+
+func toFriendship(f domain.Friendship) *pb.Friendship {
+	return &pb.Friendship{
+		Id:        f.Base.Id,
+		UserId:    f.UserID,
+		FriendId:  f.FriendID,
+		Status:    friendshipStatuses[f.Status],
+		CreatedAt: toTimestamp(f.Base.CreatedAt),
+		UpdatedAt: toTimestamp(f.Base.UpdatedAt),
+	}
+}
+
+func toSubscription(s domain.Subscription) *pb.Subscription {
+	return &pb.Subscription{
+		Id:           s.Base.Id,
+		UserId:       s.UserID,
+		SubscriberId: s.SubscriberID,
+		Status:       subscriptionStatuses[s.Status],
+		CreatedAt:    toTimestamp(s.Base.CreatedAt),
+		UpdatedAt:    toTimestamp(s.Base.UpdatedAt),
+	}
+}
+
+func toAuditEntry(e domain.AuditEntry) *pb.AuditEntry {
+	return &pb.AuditEntry{
+		Id:           e.ID,
+		Actor:        e.Actor,
+		Action:       string(e.Action),
+		TargetIds:    e.TargetIDs,
+		StatusBefore: string(e.StatusBefore),
+		StatusAfter:  string(e.StatusAfter),
+		RequestId:    e.RequestID,
+		CreatedAt:    toTimestamp(e.CreatedAt),
+	}
+}
+
+func toBlockedEmails(list []domain.BlockedEmail) []*pb.BlockedEmail {
+	res := make([]*pb.BlockedEmail, 0, len(list))
+	for _, b := range list {
+		res = append(res, &pb.BlockedEmail{Email: b.Email, BlockedAt: toTimestamp(b.BlockedAt)})
+	}
+	return res
+}
+
+func toWebhook(w domain.Webhook) *pb.Webhook {
+	eventTypes := make([]string, 0, len(w.EventTypes))
+	for _, t := range w.EventTypes {
+		eventTypes = append(eventTypes, string(t))
+	}
+	return &pb.Webhook{
+		Id:         w.Id,
+		Url:        w.URL,
+		EventTypes: eventTypes,
+		CreatedAt:  toTimestamp(w.CreatedAt),
+		UpdatedAt:  toTimestamp(w.UpdatedAt),
+	}
+}
+
+func toWebhookDelivery(d domain.WebhookDelivery) *pb.WebhookDelivery {
+	return &pb.WebhookDelivery{
+		Id:            d.Id,
+		WebhookId:     d.WebhookID,
+		EventId:       d.EventID,
+		EventType:     string(d.EventType),
+		Payload:       d.Payload,
+		Status:        string(d.Status),
+		Attempts:      int32(d.Attempts),
+		LastError:     d.LastError,
+		NextAttemptAt: toTimestamp(d.NextAttemptAt),
+		CreatedAt:     toTimestamp(d.CreatedAt),
+		UpdatedAt:     toTimestamp(d.UpdatedAt),
+	}
+}
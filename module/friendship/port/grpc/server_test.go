@@ -0,0 +1,570 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/auth"
+	mockHandler "github.com/phantranhieunhan/s3-assignment/mock/friendship/handler"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/port"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/port/grpc/pb"
+)
+
+const testAdminToken = "admin-token"
+
+var testIssuer = auth.NewTokenIssuer("secret", time.Hour)
+
+// dial serves the application on an in-process listener and returns a client connected to it
+func dial(t *testing.T, application app.Application) pb.FriendshipServiceClient {
+	lis := bufconn.Listen(1024 * 1024)
+	s := NewGRPCServer(application, testIssuer, testAdminToken)
+	go func() {
+		_ = s.Serve(lis)
+	}()
+	t.Cleanup(s.Stop)
+
+	conn, err := grpclib.DialContext(context.Background(), "bufnet",
+		grpclib.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpclib.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return pb.NewFriendshipServiceClient(conn)
+}
+
+func withToken(t *testing.T, userID string) context.Context {
+	token, _, err := testIssuer.Issue(userID)
+	assert.NoError(t, err)
+	return metadata.AppendToOutgoingContext(context.Background(), AuthorizationMetadata, "Bearer "+token)
+}
+
+func assertCode(t *testing.T, code codes.Code, err error) {
+	st, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, code, st.Code(), err)
+}
+
+func TestConnectFriendship(t *testing.T) {
+	t.Parallel()
+
+	friends := []string{"lisa@example.com", "common@example.com"}
+
+	tcs := []struct {
+		name    string
+		ctx     context.Context
+		friends []string
+
+		requestError error
+
+		hasValidateErr bool
+		code           codes.Code
+	}{
+		{name: "successful", ctx: withToken(t, "user-1"), friends: friends, code: codes.OK},
+		{name: "fail because token is missing", ctx: context.Background(), friends: friends, hasValidateErr: true, code: codes.Unauthenticated},
+		{name: "fail because token is invalid", ctx: metadata.AppendToOutgoingContext(context.Background(), AuthorizationMetadata, "Bearer invalid"), friends: friends, hasValidateErr: true, code: codes.Unauthenticated},
+		{name: "fail because request emails is the same", ctx: withToken(t, "user-1"), friends: []string{friends[0], friends[0]}, hasValidateErr: true, code: codes.InvalidArgument},
+		{name: "fail because requestor is not the authenticated user", ctx: withToken(t, "user-3"), friends: friends, requestError: common.NewForbidden(domain.ErrRequestorIsNotAuthenticatedUser, "forbidden", "ErrForbidden"), code: codes.PermissionDenied},
+		{name: "fail because request has error", ctx: withToken(t, "user-1"), friends: friends, requestError: common.ErrDB(errors.New("some error from db")), code: codes.Internal},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRequestFriendshipHandler := new(mockHandler.MockRequestFriendshipHandler)
+			if !tc.hasValidateErr {
+				mockRequestFriendshipHandler.On("Handle", mock.Anything, payload.FriendRequestPayload{
+					Requestor: tc.friends[0],
+					Target:    tc.friends[1],
+				}).Return(domain.Friendship{}, tc.requestError).Once()
+			}
+
+			client := dial(t, app.Application{Commands: app.Commands{
+				RequestFriendship: mockRequestFriendshipHandler,
+			}})
+			_, err := client.ConnectFriendship(tc.ctx, &pb.ConnectFriendshipRequest{Friends: tc.friends})
+			assertCode(t, tc.code, err)
+			mock.AssertExpectationsForObjects(t, mockRequestFriendshipHandler)
+		})
+	}
+}
+
+func TestAuthenticatedUserIsInContext(t *testing.T) {
+	t.Parallel()
+
+	mockUnfriendHandler := new(mockHandler.MockUnfriendHandler)
+	mockUnfriendHandler.On("Handle", mock.MatchedBy(func(ctx context.Context) bool {
+		userID, ok := auth.UserIDFromContext(ctx)
+		return ok && userID == "user-1"
+	}), payload.UnfriendPayload{Requestor: "lisa@example.com", Target: "common@example.com"}).Return(nil).Once()
+
+	client := dial(t, app.Application{Commands: app.Commands{Unfriend: mockUnfriendHandler}})
+	_, err := client.Unfriend(withToken(t, "user-1"), &pb.RelationRequest{Requestor: "lisa@example.com", Target: "common@example.com"})
+	assert.NoError(t, err)
+	mock.AssertExpectationsForObjects(t, mockUnfriendHandler)
+}
+
+func TestListBlockedUsers(t *testing.T) {
+	t.Parallel()
+
+	blocked := []domain.BlockedEmail{{Email: "john@example.com", BlockedAt: time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)}}
+
+	t.Run("lists the blocks of the authenticated user whatever the email", func(t *testing.T) {
+		t.Parallel()
+
+		mockListBlockedUsersHandler := new(mockHandler.MockListBlockedUsersHandler)
+		mockListBlockedUsersHandler.On("Handle", mock.Anything, "user-1").Return(blocked, nil).Once()
+
+		client := dial(t, app.Application{Queries: app.Queries{ListBlockedUsers: mockListBlockedUsersHandler}})
+		res, err := client.ListBlockedUsers(withToken(t, "user-1"), &pb.UserRequest{Email: "other@example.com"})
+		assert.NoError(t, err)
+		assert.Equal(t, int32(1), res.GetCount())
+		assert.Equal(t, "john@example.com", res.GetBlocked()[0].GetEmail())
+		mock.AssertExpectationsForObjects(t, mockListBlockedUsersHandler)
+	})
+
+	t.Run("fail because token is missing", func(t *testing.T) {
+		t.Parallel()
+
+		mockListBlockedUsersHandler := new(mockHandler.MockListBlockedUsersHandler)
+		client := dial(t, app.Application{Queries: app.Queries{ListBlockedUsers: mockListBlockedUsersHandler}})
+		_, err := client.ListBlockedUsers(context.Background(), &pb.UserRequest{Email: "lisa@example.com"})
+		assertCode(t, codes.Unauthenticated, err)
+		mock.AssertExpectationsForObjects(t, mockListBlockedUsersHandler)
+	})
+}
+
+func TestAdminMethods(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+	webhooks := []domain.Webhook{{
+		Base:       domain.Base{Id: "webhook-1", CreatedAt: now, UpdatedAt: now},
+		URL:        "https://example.com/hook",
+		Secret:     "0123456789abcdef",
+		EventTypes: []domain.EventType{domain.EventFriendshipConnected},
+	}}
+
+	tcs := []struct {
+		name       string
+		adminToken string
+		code       codes.Code
+	}{
+		{name: "successful", adminToken: testAdminToken, code: codes.OK},
+		{name: "fail because admin token is missing", code: codes.Unauthenticated},
+		{name: "fail because admin token is not valid", adminToken: "invalid", code: codes.Unauthenticated},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			mockListWebhooksHandler := new(mockHandler.MockListWebhooksHandler)
+			if tc.code == codes.OK {
+				mockListWebhooksHandler.On("Handle", mock.Anything).Return(webhooks, nil).Once()
+			}
+
+			ctx := context.Background()
+			if tc.adminToken != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, AdminTokenMetadata, tc.adminToken)
+			}
+			client := dial(t, app.Application{Queries: app.Queries{ListWebhooks: mockListWebhooksHandler}})
+			res, err := client.ListWebhooks(ctx, &emptypb.Empty{})
+			assertCode(t, tc.code, err)
+			if tc.code == codes.OK {
+				assert.Equal(t, int32(1), res.Count)
+				assert.Equal(t, "webhook-1", res.Webhooks[0].Id)
+				assert.Equal(t, []string{"FriendshipConnected"}, res.Webhooks[0].EventTypes)
+				assert.Equal(t, now, res.Webhooks[0].CreatedAt.AsTime())
+			}
+			mock.AssertExpectationsForObjects(t, mockListWebhooksHandler)
+		})
+	}
+}
+
+func TestListFriends(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name string
+		page *pb.Page
+
+		handlerError error
+
+		hasValidateErr bool
+		code           codes.Code
+	}{
+		{name: "successful with the default page", code: codes.OK},
+		{name: "successful with a page", page: &pb.Page{Limit: 2, Sort: "created_at", Since: "2023-03-01T10:00:00Z"}, code: codes.OK},
+		{name: "fail because limit is too large", page: &pb.Page{Limit: 1000}, hasValidateErr: true, code: codes.InvalidArgument},
+		{name: "fail because sort is not valid", page: &pb.Page{Sort: "name"}, hasValidateErr: true, code: codes.InvalidArgument},
+		{name: "fail because since is not valid", page: &pb.Page{Since: "yesterday"}, hasValidateErr: true, code: codes.InvalidArgument},
+		{name: "fail because query handle has error", handlerError: common.ErrDB(errors.New("some error from db")), code: codes.Internal},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			page, err := toPage(tc.page)
+			assert.Equal(t, tc.hasValidateErr, err != nil)
+
+			mockListFriendsHandler := new(mockHandler.MockListFriendsHandler)
+			if !tc.hasValidateErr {
+				mockListFriendsHandler.On("Handle", mock.Anything, "lisa@example.com", page).Return([]string{"common@example.com"}, "next", tc.handlerError).Once()
+			}
+
+			client := dial(t, app.Application{Queries: app.Queries{ListFriends: mockListFriendsHandler}})
+			res, err := client.ListFriends(context.Background(), &pb.EmailPageRequest{Email: "lisa@example.com", Page: tc.page})
+			assertCode(t, tc.code, err)
+			if tc.code == codes.OK {
+				assert.Equal(t, []string{"common@example.com"}, res.Friends)
+				assert.Equal(t, int32(1), res.Count)
+				assert.Equal(t, int32(page.Limit), res.Paging.Limit)
+				assert.Equal(t, string(page.Sort), res.Paging.Sort)
+				assert.Equal(t, "next", res.Paging.NextCursor)
+			}
+			mock.AssertExpectationsForObjects(t, mockListFriendsHandler)
+		})
+	}
+}
+
+func TestFindFriendshipPath(t *testing.T) {
+	t.Parallel()
+
+	mockFindFriendshipPathHandler := new(mockHandler.MockFindFriendshipPathHandler)
+	mockFindFriendshipPathHandler.On("Handle", mock.Anything, "lisa@example.com", "andy@example.com", 3).
+		Return([]string{"lisa@example.com", "common@example.com", "andy@example.com"}, nil).Once()
+
+	client := dial(t, app.Application{Queries: app.Queries{FindFriendshipPath: mockFindFriendshipPathHandler}})
+	res, err := client.FindFriendshipPath(context.Background(), &pb.FindFriendshipPathRequest{From: "lisa@example.com", To: "andy@example.com"})
+	assert.NoError(t, err)
+	assert.True(t, res.Connected)
+	assert.Equal(t, int32(2), res.Degrees)
+	mock.AssertExpectationsForObjects(t, mockFindFriendshipPathHandler)
+}
+
+func TestListFriendRequests(t *testing.T) {
+	t.Parallel()
+
+	mockListFriendRequestsHandler := new(mockHandler.MockListFriendRequestsHandler)
+	mockListFriendRequestsHandler.On("Handle", mock.Anything, "user-1", domain.FriendRequestDirectionIncoming).
+		Return([]string{"andy@example.com"}, nil).Once()
+
+	client := dial(t, app.Application{Queries: app.Queries{ListFriendRequests: mockListFriendRequestsHandler}})
+	_, err := client.ListFriendRequests(context.Background(), &pb.ListFriendRequestsRequest{
+		Direction: pb.FriendRequestDirection_FRIEND_REQUEST_DIRECTION_INCOMING,
+	})
+	assertCode(t, codes.Unauthenticated, err)
+
+	res, err := client.ListFriendRequests(withToken(t, "user-1"), &pb.ListFriendRequestsRequest{
+		Email:     "lisa@example.com",
+		Direction: pb.FriendRequestDirection_FRIEND_REQUEST_DIRECTION_INCOMING,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"andy@example.com"}, res.Requests)
+
+	_, err = client.ListFriendRequests(withToken(t, "user-1"), &pb.ListFriendRequestsRequest{Email: "lisa@example.com"})
+	assertCode(t, codes.InvalidArgument, err)
+	mock.AssertExpectationsForObjects(t, mockListFriendRequestsHandler)
+}
+
+func TestGetUser(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+	user := domain.User{Base: domain.Base{Id: "user-id", CreatedAt: now, UpdatedAt: now}, Email: "lisa@example.com", Password: "hashed"}
+
+	mockGetUserHandler := new(mockHandler.MockGetUserHandler)
+	mockGetUserHandler.On("Handle", mock.Anything, "lisa@example.com").Return(user, nil).Once()
+	mockGetUserHandler.On("Handle", mock.Anything, "andy@example.com").Return(domain.User{}, common.ErrInvalidRequest(domain.ErrNotFoundUserByEmail, "email")).Once()
+
+	client := dial(t, app.Application{Queries: app.Queries{GetUser: mockGetUserHandler}})
+	res, err := client.GetUser(context.Background(), &pb.UserRequest{Email: "lisa@example.com"})
+	assert.NoError(t, err)
+	assert.Equal(t, "user-id", res.Id)
+	assert.Equal(t, "lisa@example.com", res.Email)
+	assert.Equal(t, now, res.CreatedAt.AsTime())
+
+	_, err = client.GetUser(context.Background(), &pb.UserRequest{Email: "andy@example.com"})
+	assertCode(t, codes.InvalidArgument, err)
+
+	_, err = client.GetUser(context.Background(), &pb.UserRequest{Email: "lisa-example.com"})
+	assertCode(t, codes.InvalidArgument, err)
+	mock.AssertExpectationsForObjects(t, mockGetUserHandler)
+}
+
+func TestRecoverFromPanic(t *testing.T) {
+	t.Parallel()
+
+	mockGetUserHandler := new(mockHandler.MockGetUserHandler)
+	mockGetUserHandler.On("Handle", mock.Anything, "lisa@example.com").Run(func(mock.Arguments) {
+		panic("boom")
+	}).Once()
+
+	client := dial(t, app.Application{Queries: app.Queries{GetUser: mockGetUserHandler}})
+	_, err := client.GetUser(context.Background(), &pb.UserRequest{Email: "lisa@example.com"})
+	assertCode(t, codes.Internal, err)
+
+	// the server keeps serving after a panic
+	_, err = client.ListWebhooks(context.Background(), &emptypb.Empty{})
+	assertCode(t, codes.Unauthenticated, err)
+}
+
+func TestRestoreAccount(t *testing.T) {
+	t.Parallel()
+
+	mockCancelAccountDeletionHandler := new(mockHandler.MockCancelAccountDeletionHandler)
+	mockCancelAccountDeletionHandler.On("Handle", mock.Anything, payload.RestoreAccountPayload{Email: "lisa@example.com", Password: "secret"}).Return(nil).Once()
+	mockCancelAccountDeletionHandler.On("Handle", mock.Anything, payload.RestoreAccountPayload{Email: "lisa@example.com", Password: "wrong"}).
+		Return(common.NewUnauthorized(errors.New("invalid email or password"), "invalid email or password", "ErrUnauthorized")).Once()
+
+	// the password authenticates the restore, no token is needed
+	client := dial(t, app.Application{Commands: app.Commands{CancelAccountDeletion: mockCancelAccountDeletionHandler}})
+	_, err := client.RestoreAccount(context.Background(), &pb.RestoreAccountRequest{Email: "lisa@example.com", Password: "secret"})
+	assert.NoError(t, err)
+
+	_, err = client.RestoreAccount(context.Background(), &pb.RestoreAccountRequest{Email: "lisa@example.com", Password: "wrong"})
+	assertCode(t, codes.Unauthenticated, err)
+
+	_, err = client.RestoreAccount(context.Background(), &pb.RestoreAccountRequest{Email: "lisa@example.com"})
+	assertCode(t, codes.InvalidArgument, err)
+	mock.AssertExpectationsForObjects(t, mockCancelAccountDeletionHandler)
+}
+
+func TestSetPassword(t *testing.T) {
+	t.Parallel()
+
+	mockSetPasswordHandler := new(mockHandler.MockSetPasswordHandler)
+	mockSetPasswordHandler.On("Handle", mock.MatchedBy(auth.IsAdmin), payload.SetPasswordPayload{Email: "lisa@example.com", Password: "new password"}).Return(nil).Once()
+
+	client := dial(t, app.Application{Commands: app.Commands{SetPassword: mockSetPasswordHandler}})
+	req := &pb.SetPasswordRequest{Email: "lisa@example.com", Password: "new password"}
+	_, err := client.SetPassword(withToken(t, "user-1"), req)
+	assertCode(t, codes.Unauthenticated, err)
+
+	adminCtx := metadata.AppendToOutgoingContext(context.Background(), AdminTokenMetadata, testAdminToken)
+	_, err = client.SetPassword(adminCtx, req)
+	assert.NoError(t, err)
+
+	_, err = client.SetPassword(adminCtx, &pb.SetPasswordRequest{Email: "lisa@example.com"})
+	assertCode(t, codes.InvalidArgument, err)
+	mock.AssertExpectationsForObjects(t, mockSetPasswordHandler)
+}
+
+func TestImportRelationships(t *testing.T) {
+	t.Parallel()
+
+	rows := payload.ImportRelationshipPayloads{
+		{Type: payload.ImportRelationshipUser, User: "lisa@example.com"},
+		{Type: payload.ImportRelationshipFriendship, User: "lisa@example.com", Target: "andy@example.com"},
+	}
+	mockImportRelationshipsHandler := new(mockHandler.MockImportRelationshipsHandler)
+	mockImportRelationshipsHandler.On("Handle", mock.MatchedBy(auth.IsAdmin), rows).
+		Return([]error{nil, common.ErrInvalidRequest(domain.ErrNotFoundUserByEmail, "emails")}, nil).Once()
+
+	client := dial(t, app.Application{Commands: app.Commands{ImportRelationships: mockImportRelationshipsHandler}})
+	req := &pb.ImportRelationshipsRequest{Rows: []*pb.ImportRelationshipRow{
+		{Type: "user", User: "lisa@example.com"},
+		{Type: "block", User: "lisa@example.com", Target: "andy@example.com"},
+		{Type: "friendship", User: "lisa@example.com", Target: "andy@example.com"},
+	}}
+	_, err := client.ImportRelationships(context.Background(), req)
+	assertCode(t, codes.Unauthenticated, err)
+
+	adminCtx := metadata.AppendToOutgoingContext(context.Background(), AdminTokenMetadata, testAdminToken)
+	res, err := client.ImportRelationships(adminCtx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), res.Imported)
+	assert.Equal(t, int32(2), res.Failed)
+	assert.Equal(t, int32(1), res.Failures[0].Row)
+	assert.Equal(t, "invalid request for type", res.Failures[0].Message)
+	assert.Equal(t, int32(2), res.Failures[1].Row)
+	assert.Equal(t, "ErrInvalidRequest", res.Failures[1].ErrorKey)
+
+	// a request larger than a batch is rejected before any row is written
+	tooMany := &pb.ImportRelationshipsRequest{}
+	for i := 0; i <= port.ImportBatchSize(); i++ {
+		tooMany.Rows = append(tooMany.Rows, &pb.ImportRelationshipRow{Type: "user", User: "lisa@example.com"})
+	}
+	_, err = client.ImportRelationships(adminCtx, tooMany)
+	assertCode(t, codes.InvalidArgument, err)
+	mock.AssertExpectationsForObjects(t, mockImportRelationshipsHandler)
+}
+
+func TestExportUser(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+	user := domain.User{Base: domain.Base{Id: "user-1", CreatedAt: now, UpdatedAt: now}, Email: "lisa@example.com"}
+
+	mockExportUserHandler := new(mockHandler.MockExportUserHandler)
+	mockExportUserHandler.On("Handle", mock.Anything, "lisa@example.com", mock.Anything).Run(func(args mock.Arguments) {
+		w := args.Get(2).(domain.UserExport)
+		assert.NoError(t, w.WriteUser(user))
+		assert.NoError(t, w.WriteFriendships([]domain.ExportedFriendship{{
+			Friendship:  domain.Friendship{Base: domain.Base{Id: "friendship-1"}, Status: domain.FriendshipStatusFriended},
+			UserEmail:   "lisa@example.com",
+			FriendEmail: "andy@example.com",
+		}}))
+		assert.NoError(t, w.WriteUpdates([]domain.Update{{Base: domain.Base{Id: "update-1", CreatedAt: now}, Text: "hello"}}))
+	}).Return(nil).Once()
+
+	client := dial(t, app.Application{Queries: app.Queries{ExportUser: mockExportUserHandler}})
+	stream, err := client.ExportUser(context.Background(), &pb.UserRequest{Email: "lisa@example.com"})
+	assert.NoError(t, err)
+	_, err = stream.Recv()
+	assertCode(t, codes.Unauthenticated, err)
+
+	stream, err = client.ExportUser(withToken(t, "user-1"), &pb.UserRequest{Email: "lisa@example.com"})
+	assert.NoError(t, err)
+	var chunks []*pb.ExportUserChunk
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if !assert.NoError(t, err) {
+			break
+		}
+		chunks = append(chunks, chunk)
+	}
+	if assert.Len(t, chunks, 3) {
+		assert.Equal(t, "user-1", chunks[0].User.Id)
+		assert.Equal(t, "andy@example.com", chunks[1].Friendships[0].Friend)
+		assert.Equal(t, "FRIENDED", chunks[1].Friendships[0].Status)
+		assert.Equal(t, "lisa@example.com", chunks[2].Updates[0].Sender)
+		assert.Equal(t, now, chunks[2].Updates[0].CreatedAt.AsTime())
+	}
+	mock.AssertExpectationsForObjects(t, mockExportUserHandler)
+}
+
+func TestListAuditLog(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+	entries := []domain.AuditEntry{{
+		ID:           "audit-1",
+		Actor:        "user-1",
+		Action:       domain.AuditConnectFriendship,
+		TargetIDs:    []string{"user-1", "user-2"},
+		StatusBefore: domain.AuditStatusActive,
+		StatusAfter:  domain.AuditStatusActive,
+		CreatedAt:    now,
+	}}
+	page, err := toPage(nil)
+	assert.NoError(t, err)
+	filter := domain.AuditFilter{UserID: "user-1", Action: domain.AuditConnectFriendship, From: now}
+
+	mockListAuditLogHandler := new(mockHandler.MockListAuditLogHandler)
+	mockListAuditLogHandler.On("Handle", mock.MatchedBy(auth.IsAdmin), filter, page).Return(entries, "next", nil).Once()
+
+	client := dial(t, app.Application{Queries: app.Queries{ListAuditLog: mockListAuditLogHandler}})
+	req := &pb.ListAuditLogRequest{User: "user-1", Action: "ConnectFriendship", From: "2023-03-01T10:00:00Z"}
+	_, err = client.ListAuditLog(withToken(t, "user-1"), req)
+	assertCode(t, codes.Unauthenticated, err)
+
+	adminCtx := metadata.AppendToOutgoingContext(context.Background(), AdminTokenMetadata, testAdminToken)
+	res, err := client.ListAuditLog(adminCtx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), res.Count)
+	assert.Equal(t, "audit-1", res.Entries[0].Id)
+	assert.Equal(t, "ConnectFriendship", res.Entries[0].Action)
+	assert.Equal(t, "next", res.Paging.NextCursor)
+
+	_, err = client.ListAuditLog(adminCtx, &pb.ListAuditLogRequest{Action: "Unknown"})
+	assertCode(t, codes.InvalidArgument, err)
+	_, err = client.ListAuditLog(adminCtx, &pb.ListAuditLogRequest{To: "yesterday"})
+	assertCode(t, codes.InvalidArgument, err)
+	mock.AssertExpectationsForObjects(t, mockListAuditLogHandler)
+}
+
+func TestGetFriendship(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+	friendship := domain.Friendship{Base: domain.Base{Id: "friendship-1", CreatedAt: now, UpdatedAt: now}, UserID: "user-1", FriendID: "user-2", Status: domain.FriendshipStatusPending}
+
+	mockGetFriendshipHandler := new(mockHandler.MockGetFriendshipHandler)
+	mockGetFriendshipHandler.On("Handle", mock.Anything, "lisa@example.com", "andy@example.com").Return(friendship, nil).Once()
+	mockGetFriendshipHandler.On("Handle", mock.Anything, "lisa@example.com", "kate@example.com").Return(domain.Friendship{}, nil).Once()
+
+	client := dial(t, app.Application{Queries: app.Queries{GetFriendship: mockGetFriendshipHandler}})
+	res, err := client.GetFriendship(context.Background(), &pb.GetFriendshipRequest{User: "lisa@example.com", Friend: "andy@example.com"})
+	assert.NoError(t, err)
+	assert.Equal(t, "friendship-1", res.Friendship.Id)
+	assert.Equal(t, "PENDING", res.Friendship.Status)
+
+	// the users never connected
+	res, err = client.GetFriendship(context.Background(), &pb.GetFriendshipRequest{User: "lisa@example.com", Friend: "kate@example.com"})
+	assert.NoError(t, err)
+	assert.Nil(t, res.Friendship)
+
+	_, err = client.GetFriendship(context.Background(), &pb.GetFriendshipRequest{User: "lisa@example.com", Friend: "lisa@example.com"})
+	assertCode(t, codes.InvalidArgument, err)
+	mock.AssertExpectationsForObjects(t, mockGetFriendshipHandler)
+}
+
+func TestGetSubscription(t *testing.T) {
+	t.Parallel()
+
+	subscription := domain.Subscription{Base: domain.Base{Id: "subscription-1"}, UserID: "user-2", SubscriberID: "user-1", Status: domain.SubscriptionStatusSubscribed}
+
+	mockGetSubscriptionHandler := new(mockHandler.MockGetSubscriptionHandler)
+	mockGetSubscriptionHandler.On("Handle", mock.Anything, "lisa@example.com", "andy@example.com").Return(subscription, nil).Once()
+	mockGetSubscriptionHandler.On("Handle", mock.Anything, "lisa@example.com", "kate@example.com").Return(domain.Subscription{}, nil).Once()
+
+	client := dial(t, app.Application{Queries: app.Queries{GetSubscription: mockGetSubscriptionHandler}})
+	res, err := client.GetSubscription(context.Background(), &pb.GetSubscriptionRequest{Subscriber: "lisa@example.com", Target: "andy@example.com"})
+	assert.NoError(t, err)
+	assert.Equal(t, "subscription-1", res.Id)
+	assert.Equal(t, "SUBSCRIBED", res.Status)
+
+	// the subscriber never subscribed to the target
+	res, err = client.GetSubscription(context.Background(), &pb.GetSubscriptionRequest{Subscriber: "lisa@example.com", Target: "kate@example.com"})
+	assert.NoError(t, err)
+	assert.Equal(t, "NONE", res.Status)
+
+	_, err = client.GetSubscription(context.Background(), &pb.GetSubscriptionRequest{Subscriber: "lisa@example.com", Target: "kate-example.com"})
+	assertCode(t, codes.InvalidArgument, err)
+	mock.AssertExpectationsForObjects(t, mockGetSubscriptionHandler)
+}
+
+func TestListUserEmailsAndIDs(t *testing.T) {
+	t.Parallel()
+
+	mockListUserEmailsHandler := new(mockHandler.MockListUserEmailsHandler)
+	mockListUserEmailsHandler.On("Handle", mock.Anything, []string{"user-1", "user-2"}).
+		Return(map[string]string{"user-1": "lisa@example.com", "user-2": "andy@example.com"}, nil).Once()
+	mockListUserIDsHandler := new(mockHandler.MockListUserIDsHandler)
+	mockListUserIDsHandler.On("Handle", mock.Anything, []string{"lisa@example.com"}).
+		Return(map[string]string{"lisa@example.com": "user-1"}, nil).Once()
+
+	client := dial(t, app.Application{Queries: app.Queries{ListUserEmails: mockListUserEmailsHandler, ListUserIDs: mockListUserIDsHandler}})
+	emails, err := client.ListUserEmails(context.Background(), &pb.ListUserEmailsRequest{UserIds: []string{"user-1", "user-2"}})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"user-1": "lisa@example.com", "user-2": "andy@example.com"}, emails.Emails)
+
+	ids, err := client.ListUserIDs(context.Background(), &pb.ListUserIDsRequest{Emails: []string{"lisa@example.com"}})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"lisa@example.com": "user-1"}, ids.UserIds)
+
+	_, err = client.ListUserIDs(context.Background(), &pb.ListUserIDsRequest{Emails: []string{"lisa-example.com"}})
+	assertCode(t, codes.InvalidArgument, err)
+	mock.AssertExpectationsForObjects(t, mockListUserEmailsHandler, mockListUserIDsHandler)
+}
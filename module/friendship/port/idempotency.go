@@ -0,0 +1,158 @@
+package port
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/middleware"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+const (
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader marks a response replayed from the first request of its key
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	// CacheControlHeader set to NoStore keeps a response holding a secret out of the idempotency records
+	CacheControlHeader = "Cache-Control"
+	NoStore            = "no-store"
+)
+
+// recordingWriter sends the response as it is written and keeps a copy of its body
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Idempotency makes the POST requests sent with an Idempotency-Key header safe to retry: the response of the first request
+// of a key is stored and replayed to the requests sent again with the key within the ttl, a request reusing the key with
+// another path or body is rejected, as is a request sent while the first one is still running. A request still running
+// after the lease is taken for gone, its key goes to the next request.
+// A key belongs to the credentials of its request. A response with a server error is not stored, the request can be retried,
+// nor is a response marked no-store such as a login token. The requests without the header or without credentials,
+// and those whose body is not json such as the streamed imports, are let through
+func Idempotency(repo domain.IdempotencyRepo, ttl, lease time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		scope := idempotencyScope(c)
+		if c.Request.Method != http.MethodPost || key == "" || scope == "" || !takesJSONBody(c) {
+			c.Next()
+			return
+		}
+		if len(key) > domain.IdempotencyKeyMaxLength {
+			common.HttpErrorHandler(c, common.ErrInvalidRequest(domain.ErrIdempotencyKeyIsNotValid, IdempotencyKeyHeader))
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			logger.Error("Idempotency.ReadBody: ", err)
+			common.HttpErrorHandler(c, common.ErrInvalidRequest(err, "body data"))
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		record := domain.IdempotencyRecord{Scope: scope, Key: key, Fingerprint: requestFingerprint(c, body)}
+		now := time.Now()
+		held, reserved, err := repo.Reserve(c.Request.Context(), record, now.Add(-ttl), now.Add(-lease))
+		if err != nil {
+			logger.Error("Idempotency.Reserve: ", err)
+			common.HttpErrorHandler(c, common.ErrCannotCreateEntity(record.DomainName(), err))
+			return
+		}
+		if !reserved {
+			replayResponse(c, held, record.Fingerprint)
+			return
+		}
+		record = held
+
+		// the request context is cancelled once the client is gone, the record is settled all the same
+		completed := false
+		defer func() {
+			if completed {
+				return
+			}
+			if err := repo.Release(context.Background(), record); err != nil {
+				logger.Error("Idempotency.Release: ", err)
+			}
+		}()
+
+		w := &recordingWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Next()
+		c.Writer = w.ResponseWriter
+
+		if w.Status() >= http.StatusInternalServerError || strings.Contains(w.Header().Get(CacheControlHeader), NoStore) {
+			return
+		}
+		record.StatusCode = w.Status()
+		record.ContentType = w.Header().Get("Content-Type")
+		record.Body = w.body.Bytes()
+		if err = repo.Complete(context.Background(), record); err != nil {
+			logger.Error("Idempotency.Complete: ", err)
+			return
+		}
+		completed = true
+	}
+}
+
+// replayResponse sends the response stored for the key, unless the key is held by another request or by a request still running
+func replayResponse(c *gin.Context, held domain.IdempotencyRecord, fingerprint string) {
+	if held.Fingerprint != fingerprint {
+		common.HttpErrorHandler(c, common.ErrInvalidRequest(domain.ErrIdempotencyKeyIsReused, IdempotencyKeyHeader))
+		return
+	}
+	if held.IsInFlight() {
+		common.HttpErrorHandler(c, common.NewConflict(domain.ErrIdempotencyKeyIsInFlight, domain.ErrIdempotencyKeyIsInFlight.Error(), "ErrConflict"))
+		return
+	}
+
+	c.Header(IdempotentReplayedHeader, "true")
+	c.Data(held.StatusCode, held.ContentType, held.Body)
+	c.Abort()
+}
+
+// takesJSONBody reports whether the request has a json body, a body without a content type is read as json by the handlers
+func takesJSONBody(c *gin.Context) bool {
+	contentType := c.ContentType()
+	return contentType == "" || contentType == gin.MIMEJSON
+}
+
+// idempotencyScope is the hash of the credentials of the request, the keys of a caller are never replayed to another one.
+// The anonymous requests have no scope, their keys would be shared by every client
+func idempotencyScope(c *gin.Context) string {
+	authorization, adminToken := c.GetHeader(middleware.AuthorizationHeader), c.GetHeader(middleware.AdminTokenHeader)
+	if authorization == "" && adminToken == "" {
+		return ""
+	}
+	return sha256Hex(authorization + "\n" + adminToken)
+}
+
+// requestFingerprint is the hash of the request a key was first used with
+func requestFingerprint(c *gin.Context, body []byte) string {
+	return sha256Hex(c.Request.URL.RequestURI() + "\n" + string(body))
+}
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,225 @@
+package port
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	mockRepo "github.com/phantranhieunhan/s3-assignment/mock/friendship/repository"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestCase_Idempotency struct {
+	name        string
+	key         string
+	contentType string
+	anonymous   bool
+	status      int
+	noStore     bool
+
+	reserveData     domain.IdempotencyRecord
+	reserveReserved bool
+	reserveError    error
+
+	expectReserve  bool
+	expectComplete bool
+	expectRelease  bool
+
+	handled      bool
+	statusCode   int
+	body         string
+	replayedBody bool
+}
+
+func TestIdempotency(t *testing.T) {
+	t.Parallel()
+
+	body := `{"friends":["andy@example.com","john@example.com"]}`
+	response := `{"success":true}`
+	key := "key-1"
+	authorization := "Bearer token-1"
+	record := domain.IdempotencyRecord{
+		Scope:       sha256Hex(authorization + "\n"),
+		Key:         key,
+		Fingerprint: sha256Hex("/test\n" + body),
+	}
+	// the reservation is completed or released by its time of creation
+	reservation := record
+	reservation.CreatedAt = time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+	completed := reservation
+	completed.StatusCode = http.StatusOK
+	completed.ContentType = "application/json"
+	completed.Body = []byte(`{"success":false}`)
+	reused := completed
+	reused.Fingerprint = "another request"
+
+	tcs := []TestCase_Idempotency{
+		{
+			name:       "successful without key",
+			status:     http.StatusOK,
+			handled:    true,
+			statusCode: http.StatusOK,
+			body:       response,
+		},
+		{
+			name:        "successful without a json body",
+			key:         key,
+			contentType: "text/csv",
+			status:      http.StatusOK,
+			handled:     true,
+			statusCode:  http.StatusOK,
+			body:        response,
+		},
+		{
+			name:            "successful and stores the first response",
+			key:             key,
+			status:          http.StatusOK,
+			reserveData:     reservation,
+			reserveReserved: true,
+			expectReserve:   true,
+			expectComplete:  true,
+			handled:         true,
+			statusCode:      http.StatusOK,
+			body:            response,
+		},
+		{
+			name:            "successful and stores the first response of a bad request",
+			key:             key,
+			status:          http.StatusBadRequest,
+			reserveData:     reservation,
+			reserveReserved: true,
+			expectReserve:   true,
+			expectComplete:  true,
+			handled:         true,
+			statusCode:      http.StatusBadRequest,
+			body:            response,
+		},
+		{
+			name:          "successful and replays the stored response",
+			key:           key,
+			reserveData:   completed,
+			expectReserve: true,
+			statusCode:    http.StatusOK,
+			body:          `{"success":false}`,
+			replayedBody:  true,
+		},
+		{
+			name:            "successful and releases the key of a server error",
+			key:             key,
+			status:          http.StatusInternalServerError,
+			reserveData:     reservation,
+			reserveReserved: true,
+			expectReserve:   true,
+			expectRelease:   true,
+			handled:         true,
+			statusCode:      http.StatusInternalServerError,
+			body:            response,
+		},
+		{
+			name:            "successful and releases the key of a response not to store",
+			key:             key,
+			status:          http.StatusOK,
+			noStore:         true,
+			reserveData:     reservation,
+			reserveReserved: true,
+			expectReserve:   true,
+			expectRelease:   true,
+			handled:         true,
+			statusCode:      http.StatusOK,
+			body:            response,
+		},
+		{
+			name:       "successful without credentials, the key would be shared by every client",
+			key:        key,
+			anonymous:  true,
+			status:     http.StatusOK,
+			handled:    true,
+			statusCode: http.StatusOK,
+			body:       response,
+		},
+		{
+			name:          "fail because key is reused with another request",
+			key:           key,
+			reserveData:   reused,
+			expectReserve: true,
+			statusCode:    http.StatusBadRequest,
+		},
+		{
+			name:          "fail because the first request is running",
+			key:           key,
+			reserveData:   reservation,
+			expectReserve: true,
+			statusCode:    http.StatusConflict,
+		},
+		{
+			name:       "fail because key is too long",
+			key:        strings.Repeat("k", domain.IdempotencyKeyMaxLength+1),
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:          "fail because reserve has error",
+			key:           key,
+			reserveError:  errors.New("some error from db"),
+			expectReserve: true,
+			statusCode:    http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tcs {
+		repo := new(mockRepo.MockIdempotencyRepository)
+		if tc.expectReserve {
+			repo.On("Reserve", mock.Anything, record, mock.Anything, mock.Anything).Return(tc.reserveData, tc.reserveReserved, tc.reserveError).Once()
+		}
+		if tc.expectComplete {
+			stored := reservation
+			stored.StatusCode = tc.status
+			stored.ContentType = "application/json; charset=utf-8"
+			stored.Body = []byte(response)
+			repo.On("Complete", mock.Anything, stored).Return(nil).Once()
+		}
+		if tc.expectRelease {
+			repo.On("Release", mock.Anything, reservation).Return(nil).Once()
+		}
+
+		handled := false
+		router := gin.New()
+		router.POST("/test", Idempotency(repo, time.Hour, time.Minute), func(c *gin.Context) {
+			handled = true
+			if tc.noStore {
+				c.Header(CacheControlHeader, NoStore)
+			}
+			c.Data(tc.status, "application/json; charset=utf-8", []byte(response))
+		})
+
+		req, err := http.NewRequest(http.MethodPost, "/test", bytes.NewBufferString(body))
+		assert.NoError(t, err)
+		if tc.key != "" {
+			req.Header.Set(IdempotencyKeyHeader, tc.key)
+		}
+		if !tc.anonymous {
+			req.Header.Set("Authorization", authorization)
+		}
+		if tc.contentType != "" {
+			req.Header.Set("Content-Type", tc.contentType)
+		}
+		res := httptest.NewRecorder()
+		router.ServeHTTP(res, req)
+
+		assert.Equal(t, tc.handled, handled, tc.name)
+		assert.Equal(t, tc.statusCode, res.Code, tc.name)
+		if tc.body != "" {
+			assert.Equal(t, tc.body, res.Body.String(), tc.name)
+		}
+		if tc.replayedBody {
+			assert.Equal(t, "true", res.Header().Get(IdempotentReplayedHeader), tc.name)
+		}
+		mock.AssertExpectationsForObjects(t, repo)
+	}
+}
@@ -0,0 +1,327 @@
+package port
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/phantranhieunhan/s3-assignment/common"
+	commonConstant "github.com/phantranhieunhan/s3-assignment/common/constant"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/port/constant"
+	"github.com/phantranhieunhan/s3-assignment/pkg/config"
+)
+
+const (
+	importFormatCSV   = "csv"
+	importFormatJSONL = "jsonl"
+
+	// defaultImportBatchSize applies when the batch size of the imports is not configured
+	defaultImportBatchSize = 500
+)
+
+// ImportRelationshipReq is a row of an import, a line of jsonl or a record of csv under a type,user,target header.
+// A user row has the email of the user and no target
+type ImportRelationshipReq struct {
+	Type   string `json:"type"`
+	User   string `json:"user"`
+	Target string `json:"target"`
+}
+
+// Validate checks the row with the rules of the request creating the same user or relationship
+func (r ImportRelationshipReq) Validate() error {
+	switch payload.ImportRelationshipType(r.Type) {
+	case payload.ImportRelationshipUser:
+		if r.Target != "" {
+			return common.ErrInvalidRequest(fmt.Errorf("target must be empty for a user"), constant.TARGET)
+		}
+		return UserReq{Email: r.User}.Validate()
+	case payload.ImportRelationshipFriendship:
+		return ConnectFriendshipReq{Friends: []string{r.User, r.Target}}.Validate()
+	case payload.ImportRelationshipSubscription:
+		return SubscribeUserReq{Requestor: r.User, Target: r.Target}.Validate()
+	}
+	return common.ErrInvalidRequest(domain.ErrImportTypeIsNotValid, constant.TYPE)
+}
+
+// ImportReq is the format of the body of an import and the number of rows to skip, read from the query string
+type ImportReq struct {
+	Format string `form:"format"`
+	Offset int    `form:"offset"`
+}
+
+func (r ImportReq) Validate() error {
+	if r.Format != "" && r.Format != importFormatCSV && r.Format != importFormatJSONL {
+		return common.ErrInvalidRequest(fmt.Errorf("format must be %s or %s", importFormatCSV, importFormatJSONL), constant.FORMAT)
+	}
+	if r.Offset < 0 {
+		return common.ErrInvalidRequest(fmt.Errorf("offset must not be negative"), constant.OFFSET)
+	}
+	return nil
+}
+
+// ImportRowRes is the error of a row, the rows are numbered from 0 in the order of the body
+type ImportRowRes struct {
+	Row   int              `json:"row"`
+	Error *common.AppError `json:"error"`
+}
+
+// ImportRes sums up the rows of an import and lists the failed ones, the rows imported are only counted.
+// An import stopped by a failing transaction or an unreadable body is not completed,
+// sending the same body again with next_offset as offset resumes it
+type ImportRes struct {
+	Imported   int            `json:"imported"`
+	Failed     int            `json:"failed"`
+	Completed  bool           `json:"completed"`
+	NextOffset int            `json:"next_offset"`
+	Failures   []ImportRowRes `json:"failures"`
+}
+
+// ImportRelationships streams the rows of the body into users, friendships and subscriptions. The rows are written in batches,
+// a transaction each, and the rows before the offset are skipped
+func (s *Server) ImportRelationships(c *gin.Context) {
+	var req ImportReq
+	var err error
+	if err = c.ShouldBindQuery(&req); err != nil {
+		logger.Error("ImportRelationships.ShouldBind: ", err)
+		common.HttpErrorHandler(c, common.ErrInvalidRequest(err, constant.OFFSET))
+		return
+	}
+
+	if err = req.Validate(); err != nil {
+		logger.Error("ImportRelationships.Validate: ", err)
+		common.HttpErrorHandler(c, err)
+		return
+	}
+
+	rows, err := newImportReader(req.Format, c.Request.Body)
+	if err != nil {
+		logger.Error("ImportRelationships.newImportReader: ", err)
+		common.HttpErrorHandler(c, err)
+		return
+	}
+
+	res := importRows(c.Request.Context(), s.app, rows, req.Offset, ImportBatchSize())
+
+	c.JSON(http.StatusOK, common.SimpleSuccessResponse(res))
+}
+
+// ImportBatchSize is the number of rows written in a transaction, the configured one or the default
+func ImportBatchSize() int {
+	if config.C.Import.BatchSize <= 0 {
+		return defaultImportBatchSize
+	}
+	return config.C.Import.BatchSize
+}
+
+// importRows reads the rows up to the end of the body and writes them batch by batch,
+// it stops at the first batch failing to commit
+func importRows(ctx context.Context, app app.Application, rows importReader, offset, batchSize int) ImportRes {
+	b := importBatch{res: ImportRes{NextOffset: offset, Failures: make([]ImportRowRes, 0)}}
+
+	for row := 0; ; row++ {
+		req, err := rows.Read()
+		if err == io.EOF {
+			b.res.Completed = b.flush(ctx, app, row)
+			break
+		}
+		var rowErr *common.AppError
+		if err != nil && !errors.As(err, &rowErr) {
+			logger.Error("ImportRelationships.Read: ", err)
+			b.flush(ctx, app, row)
+			break
+		}
+		if row < offset {
+			continue
+		}
+
+		if err == nil {
+			err = req.Validate()
+		}
+		if err != nil {
+			b.results = append(b.results, ImportRowRes{Row: row, Error: toAppError(err)})
+		} else {
+			b.add(row, req)
+		}
+
+		if row+1-b.res.NextOffset == batchSize && !b.flush(ctx, app, row+1) {
+			break
+		}
+	}
+
+	return b.res
+}
+
+// importBatch gathers the rows read since the last batch, in order, with the valid ones to write
+type importBatch struct {
+	res     ImportRes
+	rows    payload.ImportRelationshipPayloads
+	results []ImportRowRes
+	written []int
+}
+
+func (b *importBatch) add(row int, req ImportRelationshipReq) {
+	b.rows = append(b.rows, payload.ImportRelationshipPayload{
+		Type:   payload.ImportRelationshipType(req.Type),
+		User:   req.User,
+		Target: req.Target,
+	})
+	b.written = append(b.written, len(b.results))
+	b.results = append(b.results, ImportRowRes{Row: row})
+}
+
+// flush writes the rows of the batch, adds its rows to the summary and moves the offset to next.
+// It reports false when the batch failed, its rows are failures then and the offset stays on the first row of the batch
+func (b *importBatch) flush(ctx context.Context, app app.Application, next int) bool {
+	defer func() {
+		b.rows = nil
+		b.results = nil
+		b.written = nil
+	}()
+
+	ok := true
+	if len(b.rows) > 0 {
+		rowErrs, err := app.Commands.ImportRelationships.Handle(ctx, b.rows)
+		if err != nil {
+			logger.Error("ImportRelationships.Handle: ", err)
+			ok = false
+		}
+		for k, i := range b.written {
+			if err != nil {
+				b.results[i].Error = toAppError(err)
+			} else {
+				b.results[i].Error = toAppError(rowErrs[k])
+			}
+		}
+	}
+
+	for _, r := range b.results {
+		if r.Error == nil {
+			b.res.Imported++
+			continue
+		}
+		b.res.Failed++
+		b.res.Failures = append(b.res.Failures, r)
+	}
+	if ok {
+		b.res.NextOffset = next
+	}
+	return ok
+}
+
+// toAppError reports the error of a row the same as an error response, without its root in production
+func toAppError(err error) *common.AppError {
+	if err == nil {
+		return nil
+	}
+	appErr, ok := err.(*common.AppError)
+	if !ok {
+		appErr = common.ErrInternal(err)
+	}
+	if config.C.Env == commonConstant.PRODUCTION_ENV_NAME {
+		appErr.ClearRoot()
+	}
+	return appErr
+}
+
+// importReader reads the rows of an import body one at a time up to io.EOF. A row which cannot be parsed
+// is returned as an invalid request and the next one can be read, any other error ends the body
+type importReader interface {
+	Read() (ImportRelationshipReq, error)
+}
+
+func newImportReader(format string, body io.Reader) (importReader, error) {
+	if format == importFormatCSV {
+		return newCSVImportReader(body)
+	}
+	return jsonlImportReader{r: bufio.NewReader(body)}, nil
+}
+
+// jsonlImportReader reads a json object per line, the blank lines are not rows
+type jsonlImportReader struct {
+	r *bufio.Reader
+}
+
+func (j jsonlImportReader) Read() (ImportRelationshipReq, error) {
+	for {
+		line, err := j.r.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) == 0 {
+			if err != nil {
+				return ImportRelationshipReq{}, err
+			}
+			continue
+		}
+		if err != nil && err != io.EOF {
+			return ImportRelationshipReq{}, err
+		}
+
+		var req ImportRelationshipReq
+		if err = json.Unmarshal(line, &req); err != nil {
+			return ImportRelationshipReq{}, common.ErrInvalidRequest(err, constant.ROW)
+		}
+		return req, nil
+	}
+}
+
+// csvImportReader reads the records under a header naming the type, user and target columns, in any order
+type csvImportReader struct {
+	r       *csv.Reader
+	columns map[string]int
+}
+
+func newCSVImportReader(body io.Reader) (*csvImportReader, error) {
+	r := csv.NewReader(body)
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	header, err := r.Read()
+	if err != nil {
+		return nil, common.ErrInvalidRequest(err, constant.HEADER)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+	for _, name := range []string{constant.TYPE, constant.USER, constant.TARGET} {
+		if _, ok := columns[name]; !ok {
+			return nil, common.ErrInvalidRequest(fmt.Errorf("header must name the %s column", name), constant.HEADER)
+		}
+	}
+
+	return &csvImportReader{r: r, columns: columns}, nil
+}
+
+func (c *csvImportReader) Read() (ImportRelationshipReq, error) {
+	record, err := c.r.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return ImportRelationshipReq{}, common.ErrInvalidRequest(err, constant.ROW)
+		}
+		return ImportRelationshipReq{}, err
+	}
+
+	return ImportRelationshipReq{
+		Type:   c.field(record, constant.TYPE),
+		User:   c.field(record, constant.USER),
+		Target: c.field(record, constant.TARGET),
+	}, nil
+}
+
+func (c *csvImportReader) field(record []string, name string) string {
+	if i := c.columns[name]; i < len(record) {
+		return strings.TrimSpace(record[i])
+	}
+	return ""
+}
@@ -0,0 +1,194 @@
+package port
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/phantranhieunhan/s3-assignment/common"
+	mockHandler "github.com/phantranhieunhan/s3-assignment/mock/friendship/handler"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var (
+	importFriendship   = payload.ImportRelationshipPayload{Type: payload.ImportRelationshipFriendship, User: "lisa@example.com", Target: "john@example.com"}
+	importSubscription = payload.ImportRelationshipPayload{Type: payload.ImportRelationshipSubscription, User: "kate@example.com", Target: "lisa@example.com"}
+	importUser         = payload.ImportRelationshipPayload{Type: payload.ImportRelationshipUser, User: "kate@example.com"}
+)
+
+type TestCase_ImportRelationships struct {
+	name string
+	path string
+	body string
+
+	handlerRows  payload.ImportRelationshipPayloads
+	handlerErrs  []error
+	handlerError error
+
+	statusCode int
+	res        ImportRes
+}
+
+func TestImportRelationships(t *testing.T) {
+	t.Parallel()
+
+	errNotFound := common.ErrInvalidRequest(domain.ErrNotFoundUserByEmail, "emails")
+
+	tcs := []TestCase_ImportRelationships{
+		{
+			name:        "import jsonl rows",
+			path:        "/admin/import",
+			body:        `{"type":"friendship","user":"lisa@example.com","target":"john@example.com"}` + "\n\n" + `{"type":"subscription","user":"kate@example.com","target":"lisa@example.com"}`,
+			handlerRows: payload.ImportRelationshipPayloads{importFriendship, importSubscription},
+			handlerErrs: []error{nil, nil},
+			statusCode:  http.StatusOK,
+			res:         ImportRes{Imported: 2, Completed: true, NextOffset: 2},
+		},
+		{
+			name:        "import a user before the relationships of the user",
+			path:        "/admin/import",
+			body:        `{"type":"user","user":"kate@example.com"}` + "\n" + `{"type":"subscription","user":"kate@example.com","target":"lisa@example.com"}`,
+			handlerRows: payload.ImportRelationshipPayloads{importUser, importSubscription},
+			handlerErrs: []error{nil, nil},
+			statusCode:  http.StatusOK,
+			res:         ImportRes{Imported: 2, Completed: true, NextOffset: 2},
+		},
+		{
+			name:        "import csv rows under a header in any order",
+			path:        "/admin/import?format=csv",
+			body:        "target,type,user\njohn@example.com,friendship,lisa@example.com\nlisa@example.com,subscription,kate@example.com\n",
+			handlerRows: payload.ImportRelationshipPayloads{importFriendship, importSubscription},
+			handlerErrs: []error{nil, errNotFound},
+			statusCode:  http.StatusOK,
+			res:         ImportRes{Imported: 1, Failed: 1, Completed: true, NextOffset: 2, Failures: []ImportRowRes{{Row: 1, Error: errNotFound}}},
+		},
+		{
+			name:        "skip the rows before the offset",
+			path:        "/admin/import?format=csv&offset=1",
+			body:        "type,user,target\nfriendship,lisa@example.com,john@example.com\nsubscription,kate@example.com,lisa@example.com\n",
+			handlerRows: payload.ImportRelationshipPayloads{importSubscription},
+			handlerErrs: []error{nil},
+			statusCode:  http.StatusOK,
+			res:         ImportRes{Imported: 1, Completed: true, NextOffset: 2},
+		},
+		{
+			name: "report the rows failing validation without writing them",
+			path: "/admin/import",
+			body: `{"type":"friendship","user":"lisa@example.com","target":"lisa@example.com"}` + "\n" +
+				`{"type":"block","user":"lisa@example.com","target":"john@example.com"}` + "\n" +
+				`not json` + "\n" +
+				`{"type":"user","user":"kate@example.com","target":"lisa@example.com"}` + "\n" +
+				`{"type":"friendship","user":"lisa@example.com","target":"john@example.com"}`,
+			handlerRows: payload.ImportRelationshipPayloads{importFriendship},
+			handlerErrs: []error{nil},
+			statusCode:  http.StatusOK,
+			res: ImportRes{Imported: 1, Failed: 4, Completed: true, NextOffset: 5, Failures: []ImportRowRes{
+				{Row: 0, Error: common.ErrInvalidRequest(nil, "")},
+				{Row: 1, Error: common.ErrInvalidRequest(nil, "")},
+				{Row: 2, Error: common.ErrInvalidRequest(nil, "")},
+				{Row: 3, Error: common.ErrInvalidRequest(nil, "")},
+			}},
+		},
+		{
+			name:         "stop at a batch failing to commit",
+			path:         "/admin/import?offset=0",
+			body:         `{"type":"friendship","user":"lisa@example.com","target":"john@example.com"}`,
+			handlerRows:  payload.ImportRelationshipPayloads{importFriendship},
+			handlerError: errors.New("some error from db"),
+			statusCode:   http.StatusOK,
+			res:          ImportRes{Failed: 1, NextOffset: 0, Failures: []ImportRowRes{{Row: 0, Error: common.ErrInternal(errors.New("some error from db"))}}},
+		},
+		{
+			name:       "fail because the csv header misses a column",
+			path:       "/admin/import?format=csv",
+			body:       "type,user\nfriendship,lisa@example.com\n",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "fail because the format is unknown",
+			path:       "/admin/import?format=xml",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "fail because the offset is negative",
+			path:       "/admin/import?offset=-1",
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tcs {
+		mockImportHandler := new(mockHandler.MockImportRelationshipsHandler)
+		if tc.handlerRows != nil {
+			mockImportHandler.On("Handle", mock.Anything, tc.handlerRows).Once().Return(tc.handlerErrs, tc.handlerError)
+		}
+
+		server := NewServer(app.Application{
+			Commands: app.Commands{
+				ImportRelationships: mockImportHandler,
+			},
+		})
+		router := gin.Default()
+		router.POST("/admin/import", server.ImportRelationships)
+
+		req, err := http.NewRequest(http.MethodPost, tc.path, strings.NewReader(tc.body))
+		assert.NoError(t, err)
+		res := httptest.NewRecorder()
+		router.ServeHTTP(res, req)
+
+		assert.Equal(t, tc.statusCode, res.Code, tc.name)
+		if tc.statusCode == http.StatusOK {
+			var body struct {
+				Data ImportRes `json:"data"`
+			}
+			assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &body), tc.name)
+			assert.Equal(t, tc.res.Imported, body.Data.Imported, tc.name)
+			assert.Equal(t, tc.res.Failed, body.Data.Failed, tc.name)
+			assert.Equal(t, tc.res.Completed, body.Data.Completed, tc.name)
+			assert.Equal(t, tc.res.NextOffset, body.Data.NextOffset, tc.name)
+			assert.Len(t, body.Data.Failures, len(tc.res.Failures), tc.name)
+			for i, r := range tc.res.Failures {
+				assert.Equal(t, r.Row, body.Data.Failures[i].Row, tc.name)
+				assert.Equal(t, r.Error.Key, body.Data.Failures[i].Error.Key, tc.name)
+			}
+		}
+		mock.AssertExpectationsForObjects(t, mockImportHandler)
+	}
+}
+
+func TestImportRows_Batches(t *testing.T) {
+	t.Parallel()
+
+	errDB := errors.New("some error from db")
+	mockImportHandler := new(mockHandler.MockImportRelationshipsHandler)
+	mockImportHandler.On("Handle", mock.Anything, payload.ImportRelationshipPayloads{importFriendship, importSubscription}).Once().Return([]error{nil, nil}, nil)
+	mockImportHandler.On("Handle", mock.Anything, payload.ImportRelationshipPayloads{importSubscription, importFriendship}).Once().Return(nil, errDB)
+
+	var body bytes.Buffer
+	for _, row := range []payload.ImportRelationshipPayload{importFriendship, importSubscription, importSubscription, importFriendship, importFriendship} {
+		b, err := json.Marshal(ImportRelationshipReq{Type: string(row.Type), User: row.User, Target: row.Target})
+		assert.NoError(t, err)
+		body.Write(append(b, '\n'))
+	}
+
+	application := app.Application{Commands: app.Commands{ImportRelationships: mockImportHandler}}
+	res := importRows(context.Background(), application, jsonlImportReader{r: bufio.NewReader(&body)}, 0, 2)
+
+	// the second batch rolled back, the import resumes on its first row and the last row is never read
+	assert.False(t, res.Completed)
+	assert.Equal(t, 2, res.NextOffset)
+	assert.Equal(t, 2, res.Imported)
+	assert.Equal(t, 2, res.Failed)
+	assert.Equal(t, []int{2, 3}, []int{res.Failures[0].Row, res.Failures[1].Row})
+	mock.AssertExpectationsForObjects(t, mockImportHandler)
+}
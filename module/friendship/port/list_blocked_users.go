@@ -0,0 +1,83 @@
+package port
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/port/constant"
+)
+
+type ListBlockersReq struct {
+	Email string `json:"email"`
+}
+
+func (c ListBlockersReq) Validate() error {
+	if err := common.ValidateRequired(c.Email, constant.EMAIL); err != nil {
+		return err
+	}
+
+	if err := common.ValidateEmail(c.Email); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+type ListBlockedUsersRes struct {
+	Blocked []domain.BlockedEmail `json:"blocked"`
+	Count   int                   `json:"count"`
+}
+
+type ListBlockersRes struct {
+	Blockers []domain.BlockedEmail `json:"blockers"`
+	Count    int                   `json:"count"`
+}
+
+// ListBlockedUsers lists the users blocked by the authenticated user
+func (s *Server) ListBlockedUsers(c *gin.Context) {
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	list, err := s.app.Queries.ListBlockedUsers.Handle(c.Request.Context(), userID)
+	if err != nil {
+		logger.Error("ListBlockedUsers.Handle: ", err)
+		common.HttpErrorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, common.CustomSuccessResponse(
+		ListBlockedUsersRes{Blocked: list, Count: len(list)},
+	))
+}
+
+// ListBlockers is the admin variant listing who has blocked the user, for abuse review
+func (s *Server) ListBlockers(c *gin.Context) {
+	var req ListBlockersReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("ListBlockers.ShouldBind: ", err)
+		common.HttpErrorHandler(c, common.ErrInvalidRequest(err, constant.EMAIL))
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		logger.Error("ListBlockers.Validate: ", err)
+		common.HttpErrorHandler(c, err)
+		return
+	}
+
+	list, err := s.app.Queries.ListBlockers.Handle(c.Request.Context(), req.Email)
+	if err != nil {
+		logger.Error("ListBlockers.Handle: ", err)
+		common.HttpErrorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, common.CustomSuccessResponse(
+		ListBlockersRes{Blockers: list, Count: len(list)},
+	))
+}
@@ -0,0 +1,152 @@
+package port
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/auth"
+	mockHandler "github.com/phantranhieunhan/s3-assignment/mock/friendship/handler"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestCase_ListBlockers struct {
+	name        string
+	hasFinalErr bool
+	bodyRequest ListBlockersReq
+
+	handlerError error
+	handlerData  []domain.BlockedEmail
+
+	hasValidateErr bool
+}
+
+func TestListBlockedUsers(t *testing.T) {
+	t.Parallel()
+
+	blocked := []domain.BlockedEmail{
+		{Email: "john@example.com", BlockedAt: time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)},
+	}
+	tcs := []struct {
+		name         string
+		userID       string
+		handlerError error
+		code         int
+	}{
+		{name: "successful", userID: "user-1", code: http.StatusOK},
+		{name: "fail because user is not authenticated", code: http.StatusUnauthorized},
+		{name: "fail because handle has error", userID: "user-1", handlerError: common.ErrDB(errors.New("some error from db")), code: http.StatusBadRequest},
+	}
+
+	for _, tc := range tcs {
+		mockListBlockedUsersHandler := new(mockHandler.MockListBlockedUsersHandler)
+		if tc.userID != "" {
+			mockListBlockedUsersHandler.On("Handle", mock.Anything, tc.userID).Once().Return(blocked, tc.handlerError)
+		}
+
+		server := NewServer(app.Application{
+			Queries: app.Queries{
+				ListBlockedUsers: mockListBlockedUsersHandler,
+			},
+		})
+		router := gin.Default()
+		userID := tc.userID
+		router.GET("/test", func(c *gin.Context) {
+			if userID != "" {
+				c.Request = c.Request.WithContext(auth.WithUserID(c.Request.Context(), userID))
+			}
+		}, server.ListBlockedUsers)
+
+		req, err := http.NewRequest("GET", "/test", nil)
+		assert.NoError(t, err)
+		res := httptest.NewRecorder()
+		router.ServeHTTP(res, req)
+
+		assert.Equal(t, tc.code, res.Code, tc.name)
+		if tc.code == http.StatusOK {
+			resBody := &ListBlockedUsersRes{}
+			err := json.Unmarshal(res.Body.Bytes(), resBody)
+			assert.NoError(t, err)
+			assert.Equal(t, &ListBlockedUsersRes{Blocked: blocked, Count: len(blocked)}, resBody)
+		}
+		mock.AssertExpectationsForObjects(t, mockListBlockedUsersHandler)
+	}
+}
+
+func TestListBlockers(t *testing.T) {
+	t.Parallel()
+
+	req := ListBlockersReq{
+		Email: "lisa@example.com",
+	}
+	tcs := []TestCase_ListBlockers{
+		{
+			name:        "successful",
+			bodyRequest: req,
+			handlerData: []domain.BlockedEmail{
+				{Email: "john@example.com", BlockedAt: time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)},
+			},
+		},
+		{
+			name:           "fail because email empty",
+			bodyRequest:    ListBlockersReq{},
+			hasValidateErr: true,
+			hasFinalErr:    true,
+		},
+		{
+			name:           "fail because email invalid",
+			bodyRequest:    ListBlockersReq{Email: "lisa-example.com"},
+			hasValidateErr: true,
+			hasFinalErr:    true,
+		},
+		{
+			name:         "fail because handle has error",
+			bodyRequest:  req,
+			handlerError: errors.New("query handler error"),
+			hasFinalErr:  true,
+		},
+	}
+
+	for _, tc := range tcs {
+		mockListBlockersHandler := new(mockHandler.MockListBlockersHandler)
+		if !tc.hasValidateErr {
+			mockListBlockersHandler.On("Handle", mock.Anything, tc.bodyRequest.Email).Once().Return(tc.handlerData, tc.handlerError)
+		}
+
+		server := NewServer(app.Application{
+			Queries: app.Queries{
+				ListBlockers: mockListBlockersHandler,
+			},
+		})
+		router := gin.Default()
+		router.POST("/test", server.ListBlockers)
+
+		jsonBody, err := json.Marshal(tc.bodyRequest)
+		assert.NoError(t, err)
+
+		req, err := http.NewRequest("POST", "/test", bytes.NewBuffer(jsonBody))
+		assert.NoError(t, err)
+		res := httptest.NewRecorder()
+		router.ServeHTTP(res, req)
+
+		if tc.hasFinalErr {
+			assert.Equal(t, http.StatusBadRequest, res.Code)
+		} else {
+			assert.Equal(t, http.StatusOK, res.Code)
+			resBody := &ListBlockersRes{}
+			err := json.Unmarshal(res.Body.Bytes(), resBody)
+			assert.NoError(t, err)
+			assert.Equal(t, &ListBlockersRes{Blockers: tc.handlerData, Count: len(tc.handlerData)}, resBody)
+		}
+		mock.AssertExpectationsForObjects(t, mockListBlockersHandler)
+	}
+}
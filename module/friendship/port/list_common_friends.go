@@ -14,7 +14,7 @@ type ListCommonFriendsReq struct {
 	Friends []string `json:"friends"`
 }
 
-func (l ListCommonFriendsReq) validate() error {
+func (l ListCommonFriendsReq) Validate() error {
 	if len(l.Friends) != 2 {
 		return common.ErrInvalidRequest(fmt.Errorf("friends must be of length 2"), constant.FRIENDS)
 	}
@@ -48,23 +48,30 @@ func (s *Server) ListCommonFriends(c *gin.Context) {
 		return
 	}
 
-	if err = req.validate(); err != nil {
+	if err = req.Validate(); err != nil {
 		logger.Error("ListCommonFriends.Validate: ", err)
 		common.HttpErrorHandler(c, err)
 		return
 	}
 
-	list, err := s.app.Queries.ListCommonFriends.Handle(c.Request.Context(), req.Friends)
+	page, err := bindPage(c)
+	if err != nil {
+		logger.Error("ListCommonFriends.BindPage: ", err)
+		common.HttpErrorHandler(c, err)
+		return
+	}
+
+	list, nextCursor, err := s.app.Queries.ListCommonFriends.Handle(c.Request.Context(), req.Friends, page)
 	if err != nil {
 		logger.Error("ListFriends.Handle: ", err)
 		common.HttpErrorHandler(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, common.CustomSuccessResponse(
+	c.JSON(http.StatusOK, pageResponse(
 		ListCommonFriendsResp{
 			Friends: list,
 			Count:   len(list),
-		},
+		}, page, nextCursor,
 	))
 }
@@ -11,6 +11,7 @@ import (
 	"github.com/gin-gonic/gin"
 	mockHandler "github.com/phantranhieunhan/s3-assignment/mock/friendship/handler"
 	"github.com/phantranhieunhan/s3-assignment/module/friendship/app"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -74,7 +75,7 @@ func TestListCommonFriends(t *testing.T) {
 	for _, tc := range tcs {
 		dataReq := tc.bodyRequest
 		if !tc.hasValidateErr {
-			mockListCommonFriendsHandler.On("Handle", mock.Anything, tc.bodyRequest.Friends).Once().Return(tc.listCommonFriendsData, tc.listCommonFriendsHandlerError)
+			mockListCommonFriendsHandler.On("Handle", mock.Anything, tc.bodyRequest.Friends, domain.Page{Limit: domain.DefaultPageLimit, Sort: domain.PageSortEmail}).Once().Return(tc.listCommonFriendsData, "", tc.listCommonFriendsHandlerError)
 		}
 
 		server := NewServer(app.Application{
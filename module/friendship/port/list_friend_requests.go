@@ -0,0 +1,43 @@
+package port
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+)
+
+type ListFriendRequestsRes struct {
+	Requests []string `json:"requests"`
+	Count    int      `json:"count"`
+}
+
+// ListIncomingFriendRequests lists the pending friend requests received by the authenticated user
+func (s *Server) ListIncomingFriendRequests(c *gin.Context) {
+	s.listFriendRequests(c, domain.FriendRequestDirectionIncoming)
+}
+
+// ListOutgoingFriendRequests lists the pending friend requests sent by the authenticated user
+func (s *Server) ListOutgoingFriendRequests(c *gin.Context) {
+	s.listFriendRequests(c, domain.FriendRequestDirectionOutgoing)
+}
+
+func (s *Server) listFriendRequests(c *gin.Context, direction domain.FriendRequestDirection) {
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	list, err := s.app.Queries.ListFriendRequests.Handle(c.Request.Context(), userID, direction)
+	if err != nil {
+		logger.Error("ListFriendRequests.Handle: ", err)
+		common.HttpErrorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, common.CustomSuccessResponse(
+		ListFriendRequestsRes{Requests: list, Count: len(list)},
+	))
+}
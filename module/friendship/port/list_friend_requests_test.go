@@ -0,0 +1,106 @@
+package port
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/phantranhieunhan/s3-assignment/common/auth"
+	mockHandler "github.com/phantranhieunhan/s3-assignment/mock/friendship/handler"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestCase_ListFriendRequests struct {
+	name     string
+	userID   string
+	incoming bool
+	code     int
+
+	listFriendRequestsHandlerError error
+	listFriendRequestsData         []string
+}
+
+func TestListFriendRequests(t *testing.T) {
+	t.Parallel()
+
+	commandHandlerErr := errors.New("command handler error")
+
+	tcs := []TestCase_ListFriendRequests{
+		{
+			name:                   "successful incoming",
+			userID:                 "user-1",
+			incoming:               true,
+			listFriendRequestsData: []string{"john@example.com", "kate@example.com"},
+			code:                   http.StatusOK,
+		},
+		{
+			name:                   "successful outgoing",
+			userID:                 "user-1",
+			listFriendRequestsData: []string{"andy@example.com"},
+			code:                   http.StatusOK,
+		},
+		{
+			name:     "fail because user is not authenticated",
+			incoming: true,
+			code:     http.StatusUnauthorized,
+		},
+		{
+			name:                           "fail because list friend requests handle has error",
+			userID:                         "user-1",
+			listFriendRequestsHandlerError: commandHandlerErr,
+			code:                           http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tcs {
+		mockListFriendRequestsHandler := new(mockHandler.MockListFriendRequestsHandler)
+		direction := domain.FriendRequestDirectionOutgoing
+		if tc.incoming {
+			direction = domain.FriendRequestDirectionIncoming
+		}
+		if tc.userID != "" {
+			mockListFriendRequestsHandler.On("Handle", mock.Anything, tc.userID, direction).Once().Return(tc.listFriendRequestsData, tc.listFriendRequestsHandlerError)
+		}
+
+		server := NewServer(app.Application{
+			Queries: app.Queries{
+				ListFriendRequests: mockListFriendRequestsHandler,
+			},
+		})
+		router := gin.Default()
+		userID := tc.userID
+		authenticated := func(c *gin.Context) {
+			if userID != "" {
+				c.Request = c.Request.WithContext(auth.WithUserID(c.Request.Context(), userID))
+			}
+		}
+		if tc.incoming {
+			router.GET("/test", authenticated, server.ListIncomingFriendRequests)
+		} else {
+			router.GET("/test", authenticated, server.ListOutgoingFriendRequests)
+		}
+
+		req, err := http.NewRequest("GET", "/test", nil)
+		assert.NoError(t, err)
+		res := httptest.NewRecorder()
+		router.ServeHTTP(res, req)
+
+		assert.Equal(t, tc.code, res.Code, tc.name)
+		if tc.code == http.StatusOK {
+			resBody := &ListFriendRequestsRes{}
+			err = json.Unmarshal(res.Body.Bytes(), resBody)
+			assert.NoError(t, err)
+			assert.Equal(t, &ListFriendRequestsRes{
+				Requests: tc.listFriendRequestsData,
+				Count:    len(tc.listFriendRequestsData),
+			}, resBody)
+		}
+		mock.AssertExpectationsForObjects(t, mockListFriendRequestsHandler)
+	}
+}
@@ -0,0 +1,79 @@
+package port
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/port/constant"
+)
+
+const maxFriendSetSize = 20
+
+type ListFriendSetReq struct {
+	Friends   []string `json:"friends"`
+	Operation string   `json:"operation"`
+}
+
+func (l ListFriendSetReq) Validate() error {
+	if len(l.Friends) < 2 || len(l.Friends) > maxFriendSetSize {
+		return common.ErrInvalidRequest(fmt.Errorf("friends must be of length 2 to %d", maxFriendSetSize), constant.FRIENDS)
+	}
+
+	seen := make(map[string]bool, len(l.Friends))
+	for i, friend := range l.Friends {
+		if err := common.ValidateRequired(friend, fmt.Sprintf("friend %d", i)); err != nil {
+			return err
+		}
+		if err := common.ValidateEmail(friend); err != nil {
+			return err
+		}
+		if seen[friend] {
+			return common.ErrInvalidRequest(fmt.Errorf("friends must be different"), constant.FRIENDS)
+		}
+		seen[friend] = true
+	}
+
+	if !domain.FriendSetOperation(l.Operation).IsValid() {
+		return common.ErrInvalidRequest(domain.ErrFriendSetOperationIsNotValid, constant.OPERATION)
+	}
+	return nil
+}
+
+type ListFriendSetRes struct {
+	Friends []domain.FriendSetMember `json:"friends"`
+	Count   int                      `json:"count"`
+}
+
+func (s *Server) ListFriendSet(c *gin.Context) {
+	var req ListFriendSetReq
+	var err error
+	if err = c.ShouldBindJSON(&req); err != nil {
+		logger.Error("ListFriendSet.ShouldBind: ", err)
+		common.HttpErrorHandler(c, common.ErrInvalidRequest(err, constant.FRIENDS))
+		return
+	}
+
+	if err = req.Validate(); err != nil {
+		logger.Error("ListFriendSet.Validate: ", err)
+		common.HttpErrorHandler(c, err)
+		return
+	}
+
+	list, err := s.app.Queries.ListFriendSet.Handle(c.Request.Context(), req.Friends, domain.FriendSetOperation(req.Operation))
+	if err != nil {
+		logger.Error("ListFriendSet.Handle: ", err)
+		common.HttpErrorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, common.CustomSuccessResponse(
+		ListFriendSetRes{
+			Friends: list,
+			Count:   len(list),
+		},
+	))
+}
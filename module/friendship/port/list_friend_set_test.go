@@ -0,0 +1,115 @@
+package port
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	mockHandler "github.com/phantranhieunhan/s3-assignment/mock/friendship/handler"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestCase_ListFriendSet struct {
+	name      string
+	body      string
+	emails    []string
+	operation domain.FriendSetOperation
+
+	queryHandlerError error
+
+	hasValidateErr bool
+	hasFinalErr    bool
+}
+
+func TestListFriendSet(t *testing.T) {
+	t.Parallel()
+
+	members := []domain.FriendSetMember{
+		{Email: "kate@example.com", ConnectedUsers: 2},
+	}
+	emails := []string{"lisa@example.com", "john@example.com", "andy@example.com"}
+	tcs := []TestCase_ListFriendSet{
+		{
+			name:      "successful",
+			body:      `{"friends":["lisa@example.com","john@example.com","andy@example.com"],"operation":"intersection"}`,
+			emails:    emails,
+			operation: domain.FriendSetOperationIntersection,
+		},
+		{
+			name:           "fail because there is only one email",
+			body:           `{"friends":["lisa@example.com"],"operation":"union"}`,
+			hasValidateErr: true,
+			hasFinalErr:    true,
+		},
+		{
+			name:           "fail because emails are duplicated",
+			body:           `{"friends":["lisa@example.com","lisa@example.com"],"operation":"union"}`,
+			hasValidateErr: true,
+			hasFinalErr:    true,
+		},
+		{
+			name:           "fail because email invalid",
+			body:           `{"friends":["lisa@example.com","john-example.com"],"operation":"union"}`,
+			hasValidateErr: true,
+			hasFinalErr:    true,
+		},
+		{
+			name:           "fail because operation invalid",
+			body:           `{"friends":["lisa@example.com","john@example.com"],"operation":"xor"}`,
+			hasValidateErr: true,
+			hasFinalErr:    true,
+		},
+		{
+			name:           "fail because body is not json",
+			body:           `friends`,
+			hasValidateErr: true,
+			hasFinalErr:    true,
+		},
+		{
+			name:              "fail because query handle has error",
+			body:              `{"friends":["lisa@example.com","john@example.com","andy@example.com"],"operation":"difference"}`,
+			emails:            emails,
+			operation:         domain.FriendSetOperationDifference,
+			queryHandlerError: errors.New("query handler error"),
+			hasFinalErr:       true,
+		},
+	}
+
+	for _, tc := range tcs {
+		mockListFriendSetHandler := new(mockHandler.MockListFriendSetHandler)
+		if !tc.hasValidateErr {
+			mockListFriendSetHandler.On("Handle", mock.Anything, tc.emails, tc.operation).Once().Return(members, tc.queryHandlerError)
+		}
+
+		server := NewServer(app.Application{
+			Queries: app.Queries{
+				ListFriendSet: mockListFriendSetHandler,
+			},
+		})
+		router := gin.Default()
+		router.GET("/test", server.ListFriendSet)
+
+		req, err := http.NewRequest("GET", "/test", strings.NewReader(tc.body))
+		assert.NoError(t, err)
+		res := httptest.NewRecorder()
+		router.ServeHTTP(res, req)
+
+		if tc.hasFinalErr {
+			assert.Equal(t, http.StatusBadRequest, res.Code)
+		} else {
+			assert.Equal(t, http.StatusOK, res.Code)
+			resBody := &ListFriendSetRes{}
+			err = json.Unmarshal(res.Body.Bytes(), resBody)
+			assert.NoError(t, err)
+			assert.Equal(t, &ListFriendSetRes{Friends: members, Count: len(members)}, resBody)
+		}
+		mock.AssertExpectationsForObjects(t, mockListFriendSetHandler)
+	}
+}
@@ -0,0 +1,74 @@
+package port
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/port/constant"
+)
+
+const (
+	DefaultSuggestionLimit = 10
+	maxSuggestionLimit     = 100
+)
+
+type ListFriendSuggestionsReq struct {
+	Email string `form:"email"`
+	Limit int    `form:"limit"`
+}
+
+func (l ListFriendSuggestionsReq) Validate() error {
+	if err := common.ValidateRequired(l.Email, constant.EMAIL); err != nil {
+		return err
+	}
+	if err := common.ValidateEmail(l.Email); err != nil {
+		return err
+	}
+	if l.Limit < 0 || l.Limit > maxSuggestionLimit {
+		return common.ErrInvalidRequest(fmt.Errorf("limit must be between 1 and %d", maxSuggestionLimit), constant.LIMIT)
+	}
+
+	return nil
+}
+
+type ListFriendSuggestionsRes struct {
+	Suggestions []domain.FriendSuggestion `json:"suggestions"`
+	Count       int                       `json:"count"`
+}
+
+func (s *Server) ListFriendSuggestions(c *gin.Context) {
+	var req ListFriendSuggestionsReq
+	var err error
+	if err = c.ShouldBindQuery(&req); err != nil {
+		logger.Error("ListFriendSuggestions.ShouldBind: ", err)
+		common.HttpErrorHandler(c, common.ErrInvalidRequest(err, "query"))
+		return
+	}
+
+	if err = req.Validate(); err != nil {
+		logger.Error("ListFriendSuggestions.Validate: ", err)
+		common.HttpErrorHandler(c, err)
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = DefaultSuggestionLimit
+	}
+
+	list, err := s.app.Queries.ListFriendSuggestions.Handle(c.Request.Context(), req.Email, req.Limit)
+	if err != nil {
+		logger.Error("ListFriendSuggestions.Handle: ", err)
+		common.HttpErrorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, common.CustomSuccessResponse(
+		ListFriendSuggestionsRes{
+			Suggestions: list,
+			Count:       len(list),
+		},
+	))
+}
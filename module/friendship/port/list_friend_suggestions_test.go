@@ -0,0 +1,113 @@
+package port
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	mockHandler "github.com/phantranhieunhan/s3-assignment/mock/friendship/handler"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestCase_ListFriendSuggestions struct {
+	name          string
+	hasFinalErr   bool
+	query         string
+	email         string
+	expectedLimit int
+
+	queryHandlerError error
+
+	hasValidateErr bool
+}
+
+func TestListFriendSuggestions(t *testing.T) {
+	t.Parallel()
+
+	suggestions := []domain.FriendSuggestion{
+		{Email: "john@example.com", MutualFriends: 2},
+	}
+	tcs := []TestCase_ListFriendSuggestions{
+		{
+			name:          "successful with default limit",
+			query:         "email=lisa@example.com",
+			email:         "lisa@example.com",
+			expectedLimit: DefaultSuggestionLimit,
+		},
+		{
+			name:          "successful with limit",
+			query:         "email=lisa@example.com&limit=5",
+			email:         "lisa@example.com",
+			expectedLimit: 5,
+		},
+		{
+			name:           "fail because email is not provided",
+			query:          "limit=5",
+			hasValidateErr: true,
+			hasFinalErr:    true,
+		},
+		{
+			name:           "fail because email invalid",
+			query:          "email=lisa-example.com",
+			hasValidateErr: true,
+			hasFinalErr:    true,
+		},
+		{
+			name:           "fail because limit is too big",
+			query:          "email=lisa@example.com&limit=1000",
+			hasValidateErr: true,
+			hasFinalErr:    true,
+		},
+		{
+			name:           "fail because limit is not a number",
+			query:          "email=lisa@example.com&limit=ten",
+			hasValidateErr: true,
+			hasFinalErr:    true,
+		},
+		{
+			name:              "fail because query handle has error",
+			query:             "email=lisa@example.com",
+			email:             "lisa@example.com",
+			expectedLimit:     DefaultSuggestionLimit,
+			queryHandlerError: errors.New("query handler error"),
+			hasFinalErr:       true,
+		},
+	}
+
+	for _, tc := range tcs {
+		mockListFriendSuggestionsHandler := new(mockHandler.MockListFriendSuggestionsHandler)
+		if !tc.hasValidateErr {
+			mockListFriendSuggestionsHandler.On("Handle", mock.Anything, tc.email, tc.expectedLimit).Once().Return(suggestions, tc.queryHandlerError)
+		}
+
+		server := NewServer(app.Application{
+			Queries: app.Queries{
+				ListFriendSuggestions: mockListFriendSuggestionsHandler,
+			},
+		})
+		router := gin.Default()
+		router.GET("/test", server.ListFriendSuggestions)
+
+		req, err := http.NewRequest("GET", "/test?"+tc.query, nil)
+		assert.NoError(t, err)
+		res := httptest.NewRecorder()
+		router.ServeHTTP(res, req)
+
+		if tc.hasFinalErr {
+			assert.Equal(t, http.StatusBadRequest, res.Code)
+		} else {
+			assert.Equal(t, http.StatusOK, res.Code)
+			resBody := &ListFriendSuggestionsRes{}
+			err = json.Unmarshal(res.Body.Bytes(), resBody)
+			assert.NoError(t, err)
+			assert.Equal(t, &ListFriendSuggestionsRes{Suggestions: suggestions, Count: len(suggestions)}, resBody)
+		}
+		mock.AssertExpectationsForObjects(t, mockListFriendSuggestionsHandler)
+	}
+}
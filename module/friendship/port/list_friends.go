@@ -13,7 +13,7 @@ type ListFriendsReq struct {
 	Email string `json:"email"`
 }
 
-func (c ListFriendsReq) validate() error {
+func (c ListFriendsReq) Validate() error {
 	if err := common.ValidateRequired(c.Email, "email"); err != nil {
 		return err
 	}
@@ -39,20 +39,27 @@ func (s *Server) ListFriends(c *gin.Context) {
 		return
 	}
 
-	if err = req.validate(); err != nil {
+	if err = req.Validate(); err != nil {
 		logger.Error("ListFriends.Validate: ", err)
 		common.HttpErrorHandler(c, err)
 		return
 	}
 
-	list, err := s.app.Queries.ListFriends.Handle(c.Request.Context(), req.Email)
+	page, err := bindPage(c)
+	if err != nil {
+		logger.Error("ListFriends.BindPage: ", err)
+		common.HttpErrorHandler(c, err)
+		return
+	}
+
+	list, nextCursor, err := s.app.Queries.ListFriends.Handle(c.Request.Context(), req.Email, page)
 	if err != nil {
 		logger.Error("ListFriends.Handle: ", err)
 		common.HttpErrorHandler(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, common.CustomSuccessResponse(
-		ListFriendsRes{Friends: list, Count: len(list)},
+	c.JSON(http.StatusOK, pageResponse(
+		ListFriendsRes{Friends: list, Count: len(list)}, page, nextCursor,
 	))
 }
@@ -7,10 +7,13 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/phantranhieunhan/s3-assignment/common"
 	mockHandler "github.com/phantranhieunhan/s3-assignment/mock/friendship/handler"
 	"github.com/phantranhieunhan/s3-assignment/module/friendship/app"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -19,9 +22,12 @@ type TestCase_ListFriends struct {
 	name        string
 	hasFinalErr bool
 	bodyRequest ListFriendsReq
+	query       string
+	page        domain.Page
 
 	listFriendsHandlerError error
 	listFriendsData         []string
+	nextCursor              string
 
 	hasValidateErr bool
 }
@@ -35,12 +41,53 @@ func TestListFriends(t *testing.T) {
 	req := ListFriendsReq{
 		Email: "lisa@example.com",
 	}
+	defaultPage := domain.Page{Limit: domain.DefaultPageLimit, Sort: domain.PageSortEmail}
+	since := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	cursor := domain.EncodeCursor(domain.Cursor{Key: "andy@example.com", CreatedAt: since})
+	nextCursor := domain.EncodeCursor(domain.Cursor{Key: "kate@example.com", CreatedAt: since.Add(time.Hour)})
 	tcs := []TestCase_ListFriends{
 		{
 			name:            "successful",
 			bodyRequest:     req,
+			page:            defaultPage,
 			listFriendsData: []string{"john@example.com", "kate@example.com"},
 		},
+		{
+			name:            "successful with page",
+			bodyRequest:     req,
+			query:           "limit=2&sort=created_at&since=2023-01-02T03:04:05Z&cursor=" + cursor,
+			page:            domain.Page{Limit: 2, Sort: domain.PageSortCreatedAt, Since: since, Cursor: cursor},
+			listFriendsData: []string{"john@example.com", "kate@example.com"},
+			nextCursor:      nextCursor,
+		},
+		{
+			name:           "fail because limit is too big",
+			bodyRequest:    req,
+			query:          "limit=1000",
+			hasValidateErr: true,
+			hasFinalErr:    true,
+		},
+		{
+			name:           "fail because sort invalid",
+			bodyRequest:    req,
+			query:          "sort=name",
+			hasValidateErr: true,
+			hasFinalErr:    true,
+		},
+		{
+			name:           "fail because cursor invalid",
+			bodyRequest:    req,
+			query:          "cursor=not-a-cursor",
+			hasValidateErr: true,
+			hasFinalErr:    true,
+		},
+		{
+			name:           "fail because since invalid",
+			bodyRequest:    req,
+			query:          "since=yesterday",
+			hasValidateErr: true,
+			hasFinalErr:    true,
+		},
 		{
 			name:           "fail because email empty",
 			bodyRequest:    ListFriendsReq{},
@@ -56,6 +103,7 @@ func TestListFriends(t *testing.T) {
 		{
 			name:                    "fail because list friends handle has error",
 			bodyRequest:             req,
+			page:                    defaultPage,
 			listFriendsHandlerError: commandHandlerErr,
 			hasFinalErr:             true,
 		},
@@ -64,7 +112,7 @@ func TestListFriends(t *testing.T) {
 	for _, tc := range tcs {
 		dataReq := tc.bodyRequest
 		if !tc.hasValidateErr {
-			mockListFriendsHandler.On("Handle", mock.Anything, tc.bodyRequest.Email).Once().Return(tc.listFriendsData, tc.listFriendsHandlerError)
+			mockListFriendsHandler.On("Handle", mock.Anything, tc.bodyRequest.Email, tc.page).Once().Return(tc.listFriendsData, tc.nextCursor, tc.listFriendsHandlerError)
 		}
 
 		server := NewServer(app.Application{
@@ -79,7 +127,7 @@ func TestListFriends(t *testing.T) {
 		jsonBody, err := json.Marshal(dataReq)
 		assert.NoError(t, err)
 
-		req, err := http.NewRequest("POST", "/test", bytes.NewBuffer(jsonBody))
+		req, err := http.NewRequest("POST", "/test?"+tc.query, bytes.NewBuffer(jsonBody))
 		assert.NoError(t, err)
 		res := httptest.NewRecorder()
 		router.ServeHTTP(res, req)
@@ -95,6 +143,18 @@ func TestListFriends(t *testing.T) {
 				Friends: tc.listFriendsData,
 				Count:   len(tc.listFriendsData),
 			}, resBody)
+
+			pagingBody := &struct {
+				Paging common.Paging `json:"paging"`
+				Filter PageFilter    `json:"filter"`
+			}{}
+			err = json.Unmarshal(res.Body.Bytes(), pagingBody)
+			assert.NoError(t, err)
+			assert.Equal(t, common.Paging{Limit: tc.page.Limit, Cursor: tc.page.Cursor, NextCursor: tc.nextCursor}, pagingBody.Paging)
+			assert.Equal(t, tc.page.Sort, pagingBody.Filter.Sort)
+			if !tc.page.Since.IsZero() {
+				assert.True(t, tc.page.Since.Equal(*pagingBody.Filter.Since))
+			}
 		}
 		mock.AssertExpectationsForObjects(t, mockListFriendsHandler)
 	}
@@ -0,0 +1,62 @@
+package port
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/port/constant"
+)
+
+type ListSubscribersReq struct {
+	Email string `form:"email"`
+	PageReq
+}
+
+func (l ListSubscribersReq) Validate() error {
+	if err := common.ValidateRequired(l.Email, constant.EMAIL); err != nil {
+		return err
+	}
+
+	return common.ValidateEmail(l.Email)
+}
+
+type ListSubscribersRes struct {
+	Subscribers []string `json:"subscribers"`
+	Count       int      `json:"count"`
+}
+
+func (s *Server) ListSubscribers(c *gin.Context) {
+	var req ListSubscribersReq
+	var err error
+	if err = c.ShouldBindQuery(&req); err != nil {
+		logger.Error("ListSubscribers.ShouldBind: ", err)
+		common.HttpErrorHandler(c, common.ErrInvalidRequest(err, "query"))
+		return
+	}
+
+	if err = req.Validate(); err != nil {
+		logger.Error("ListSubscribers.Validate: ", err)
+		common.HttpErrorHandler(c, err)
+		return
+	}
+
+	page, err := req.ToPage()
+	if err != nil {
+		logger.Error("ListSubscribers.Validate: ", err)
+		common.HttpErrorHandler(c, err)
+		return
+	}
+
+	list, nextCursor, err := s.app.Queries.ListSubscribers.Handle(c.Request.Context(), req.Email, page)
+	if err != nil {
+		logger.Error("ListSubscribers.Handle: ", err)
+		common.HttpErrorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, pageResponse(
+		ListSubscribersRes{Subscribers: list, Count: len(list)}, page, nextCursor,
+	))
+}
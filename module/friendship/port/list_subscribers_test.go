@@ -0,0 +1,105 @@
+package port
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/phantranhieunhan/s3-assignment/common"
+	mockHandler "github.com/phantranhieunhan/s3-assignment/mock/friendship/handler"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestCase_ListSubscribers struct {
+	name        string
+	hasFinalErr bool
+	query       string
+	email       string
+	page        domain.Page
+
+	queryHandlerError error
+
+	hasValidateErr bool
+}
+
+func TestListSubscribers(t *testing.T) {
+	t.Parallel()
+
+	subscribers := []string{"john@example.com", "kate@example.com"}
+	nextCursor := domain.EncodeCursor(domain.Cursor{Key: "kate@example.com"})
+	tcs := []TestCase_ListSubscribers{
+		{
+			name:  "successful with default page",
+			query: "email=lisa@example.com",
+			email: "lisa@example.com",
+			page:  domain.Page{Limit: domain.DefaultPageLimit, Sort: domain.PageSortEmail},
+		},
+		{
+			name:  "successful with page",
+			query: "email=lisa@example.com&limit=2&sort=created_at",
+			email: "lisa@example.com",
+			page:  domain.Page{Limit: 2, Sort: domain.PageSortCreatedAt},
+		},
+		{
+			name:           "fail because email is not provided",
+			query:          "limit=2",
+			hasValidateErr: true,
+			hasFinalErr:    true,
+		},
+		{
+			name:           "fail because sort invalid",
+			query:          "email=lisa@example.com&sort=name",
+			hasValidateErr: true,
+			hasFinalErr:    true,
+		},
+		{
+			name:              "fail because query handle has error",
+			query:             "email=lisa@example.com",
+			email:             "lisa@example.com",
+			page:              domain.Page{Limit: domain.DefaultPageLimit, Sort: domain.PageSortEmail},
+			queryHandlerError: errors.New("query handler error"),
+			hasFinalErr:       true,
+		},
+	}
+
+	for _, tc := range tcs {
+		mockListSubscribersHandler := new(mockHandler.MockListSubscribersHandler)
+		if !tc.hasValidateErr {
+			mockListSubscribersHandler.On("Handle", mock.Anything, tc.email, tc.page).Once().Return(subscribers, nextCursor, tc.queryHandlerError)
+		}
+
+		server := NewServer(app.Application{
+			Queries: app.Queries{
+				ListSubscribers: mockListSubscribersHandler,
+			},
+		})
+		router := gin.Default()
+		router.GET("/test", server.ListSubscribers)
+
+		req, err := http.NewRequest("GET", "/test?"+tc.query, nil)
+		assert.NoError(t, err)
+		res := httptest.NewRecorder()
+		router.ServeHTTP(res, req)
+
+		if tc.hasFinalErr {
+			assert.Equal(t, http.StatusBadRequest, res.Code)
+		} else {
+			assert.Equal(t, http.StatusOK, res.Code)
+			resBody := &struct {
+				ListSubscribersRes
+				Paging common.Paging `json:"paging"`
+			}{}
+			err = json.Unmarshal(res.Body.Bytes(), resBody)
+			assert.NoError(t, err)
+			assert.Equal(t, ListSubscribersRes{Subscribers: subscribers, Count: len(subscribers)}, resBody.ListSubscribersRes)
+			assert.Equal(t, common.Paging{Limit: tc.page.Limit, NextCursor: nextCursor}, resBody.Paging)
+		}
+		mock.AssertExpectationsForObjects(t, mockListSubscribersHandler)
+	}
+}
@@ -14,7 +14,7 @@ type ListUpdatesUserReq struct {
 	Text   string `json:"text"`
 }
 
-func (c ListUpdatesUserReq) validate() error {
+func (c ListUpdatesUserReq) Validate() error {
 	if err := common.ValidateRequired(c.Sender, "sender"); err != nil {
 		return err
 	}
@@ -39,20 +39,27 @@ func (s *Server) ListUpdatesUser(c *gin.Context) {
 		return
 	}
 
-	if err = req.validate(); err != nil {
+	if err = req.Validate(); err != nil {
 		logger.Error("ListUpdatesUser.Validate: ", err)
 		common.HttpErrorHandler(c, err)
 		return
 	}
 
-	list, err := s.app.Queries.ListUpdatesUser.Handle(c.Request.Context(), req.Sender, req.Text)
+	page, err := bindPage(c)
+	if err != nil {
+		logger.Error("ListUpdatesUser.BindPage: ", err)
+		common.HttpErrorHandler(c, err)
+		return
+	}
+
+	list, nextCursor, err := s.app.Queries.ListUpdatesUser.Handle(c.Request.Context(), req.Sender, req.Text, page)
 	if err != nil {
 		logger.Error("ListUpdatesUser.Handle: ", err)
 		common.HttpErrorHandler(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, common.CustomSuccessResponse(
-		ListUpdatesUserRes{Recipients: list},
+	c.JSON(http.StatusOK, pageResponse(
+		ListUpdatesUserRes{Recipients: list}, page, nextCursor,
 	))
 }
@@ -11,6 +11,7 @@ import (
 	"github.com/gin-gonic/gin"
 	mockHandler "github.com/phantranhieunhan/s3-assignment/mock/friendship/handler"
 	"github.com/phantranhieunhan/s3-assignment/module/friendship/app"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -65,7 +66,7 @@ func TestListUpdatesUser(t *testing.T) {
 	for _, tc := range tcs {
 		dataReq := tc.bodyRequest
 		if !tc.hasValidateErr {
-			mockListUpdatesUserHandler.On("Handle", mock.Anything, tc.bodyRequest.Sender, tc.bodyRequest.Text).Once().Return(tc.ListUpdatesUserData, tc.ListUpdatesUserHandlerError)
+			mockListUpdatesUserHandler.On("Handle", mock.Anything, tc.bodyRequest.Sender, tc.bodyRequest.Text, domain.Page{Limit: domain.DefaultPageLimit, Sort: domain.PageSortEmail}).Once().Return(tc.ListUpdatesUserData, "", tc.ListUpdatesUserHandlerError)
 		}
 
 		server := NewServer(app.Application{
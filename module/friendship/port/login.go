@@ -0,0 +1,71 @@
+package port
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/port/constant"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LoginReq struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+func (l LoginReq) Validate() error {
+	if err := common.ValidateRequired(l.Email, constant.EMAIL); err != nil {
+		return err
+	}
+	if err := common.ValidateEmail(l.Email); err != nil {
+		return err
+	}
+	if err := common.ValidateRequired(l.Password, constant.PASSWORD); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+type LoginRes struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (s *Server) Login(c *gin.Context) {
+	var req LoginReq
+	var err error
+
+	if err = c.ShouldBindJSON(&req); err != nil {
+		logger.Error("Login.ShouldBind: ", err)
+		common.HttpErrorHandler(c, common.ErrInvalidRequest(err, "body data"))
+		return
+	}
+
+	if err = req.Validate(); err != nil {
+		logger.Error("Login.Validate: ", err)
+		common.HttpErrorHandler(c, err)
+		return
+	}
+
+	token, err := s.app.Commands.Login.Handle(c.Request.Context(), payload.LoginPayload{
+		Email:    req.Email,
+		Password: req.Password,
+	})
+	if err != nil {
+		logger.Error("Login.Handle: ", err)
+		common.HttpErrorHandler(c, err)
+		return
+	}
+
+	// the token is a credential, it is neither cached nor kept to be replayed
+	c.Header(CacheControlHeader, NoStore)
+	c.JSON(http.StatusOK, common.CustomSuccessResponse(LoginRes{
+		Token:     token.Token,
+		ExpiresAt: token.ExpiresAt,
+	}))
+}
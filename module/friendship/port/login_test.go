@@ -0,0 +1,96 @@
+package port
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/phantranhieunhan/s3-assignment/common"
+	mockHandler "github.com/phantranhieunhan/s3-assignment/mock/friendship/handler"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestCase_Login struct {
+	name        string
+	statusCode  int
+	bodyRequest LoginReq
+
+	commandHandlerError error
+
+	hasValidateErr bool
+}
+
+func TestLogin(t *testing.T) {
+	t.Parallel()
+
+	token := domain.AuthToken{Token: "signed-token", ExpiresAt: time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)}
+	tcs := []TestCase_Login{
+		{
+			name:        "successful",
+			bodyRequest: LoginReq{Email: "lisa@example.com", Password: "password"},
+			statusCode:  http.StatusOK,
+		},
+		{
+			name:           "fail because email invalid",
+			bodyRequest:    LoginReq{Email: "lisa-example.com", Password: "password"},
+			hasValidateErr: true,
+			statusCode:     http.StatusBadRequest,
+		},
+		{
+			name:           "fail because password is not provided",
+			bodyRequest:    LoginReq{Email: "lisa@example.com"},
+			hasValidateErr: true,
+			statusCode:     http.StatusBadRequest,
+		},
+		{
+			name:                "fail because credentials are invalid",
+			bodyRequest:         LoginReq{Email: "lisa@example.com", Password: "wrong-password"},
+			commandHandlerError: common.NewUnauthorized(errors.New("invalid"), "invalid", "ErrInvalidCredentials"),
+			statusCode:          http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range tcs {
+		mockLoginHandler := new(mockHandler.MockLoginHandler)
+		if !tc.hasValidateErr {
+			mockLoginHandler.On("Handle", mock.Anything, payload.LoginPayload{
+				Email:    tc.bodyRequest.Email,
+				Password: tc.bodyRequest.Password,
+			}).Once().Return(token, tc.commandHandlerError)
+		}
+
+		server := NewServer(app.Application{
+			Commands: app.Commands{
+				Login: mockLoginHandler,
+			},
+		})
+		router := gin.Default()
+		router.POST("/test", server.Login)
+
+		jsonBody, err := json.Marshal(tc.bodyRequest)
+		assert.NoError(t, err)
+
+		req, err := http.NewRequest("POST", "/test", bytes.NewBuffer(jsonBody))
+		assert.NoError(t, err)
+		res := httptest.NewRecorder()
+		router.ServeHTTP(res, req)
+
+		assert.Equal(t, tc.statusCode, res.Code)
+		if tc.statusCode == http.StatusOK {
+			resBody := &LoginRes{}
+			err = json.Unmarshal(res.Body.Bytes(), resBody)
+			assert.NoError(t, err)
+			assert.Equal(t, &LoginRes{Token: token.Token, ExpiresAt: token.ExpiresAt}, resBody)
+		}
+		mock.AssertExpectationsForObjects(t, mockLoginHandler)
+	}
+}
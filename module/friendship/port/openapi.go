@@ -0,0 +1,48 @@
+package port
+
+import (
+	_ "embed"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gin-gonic/gin"
+)
+
+//go:embed openapi.yaml
+var openAPIDocument []byte
+
+//go:embed swagger.html
+var swaggerUIPage []byte
+
+// LoadOpenAPI parses and checks the openapi document of the routes of the server
+func LoadOpenAPI() (*openapi3.T, error) {
+	loader := openapi3.NewLoader()
+	spec, err := loader.LoadFromData(openAPIDocument)
+	if err != nil {
+		return nil, err
+	}
+	if err = spec.Validate(loader.Context); err != nil {
+		return nil, err
+	}
+
+	return spec, nil
+}
+
+// mustLoadOpenAPI loads the embedded document, it is checked by the tests so an error is a bug of the build
+func mustLoadOpenAPI() *openapi3.T {
+	spec, err := LoadOpenAPI()
+	if err != nil {
+		panic(err)
+	}
+	return spec
+}
+
+func serveOpenAPI(spec *openapi3.T) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, spec)
+	}
+}
+
+func (s *Server) SwaggerUI(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", swaggerUIPage)
+}
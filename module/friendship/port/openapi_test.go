@@ -0,0 +1,119 @@
+package port
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	mockHandler "github.com/phantranhieunhan/s3-assignment/mock/friendship/handler"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app"
+)
+
+var ginParam = regexp.MustCompile(`:([a-z_]+)`)
+
+func TestOpenAPICoversRoutes(t *testing.T) {
+	t.Parallel()
+
+	spec, err := LoadOpenAPI()
+	assert.NoError(t, err)
+
+	router := gin.New()
+	NewServer(app.Application{}).Router(router)
+
+	for _, route := range router.Routes() {
+		// gin names the path parameters :name, openapi {name}
+		path := ginParam.ReplaceAllString(strings.TrimSuffix(route.Path, "/"), "{$1}")
+		item := spec.Paths.Find(path)
+		if !assert.NotNil(t, item, "%s %s is not in the openapi document", route.Method, route.Path) {
+			continue
+		}
+		assert.NotNil(t, item.GetOperation(route.Method), "%s %s is not in the openapi document", route.Method, route.Path)
+	}
+}
+
+func TestOpenAPIValidatesRoutes(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name   string
+		method string
+		path   string
+		body   interface{}
+
+		statusCode int
+		getUser    bool
+	}{
+		{
+			name:       "serve the document",
+			method:     http.MethodGet,
+			path:       "/openapi.json",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "serve the swagger ui",
+			method:     http.MethodGet,
+			path:       "/docs",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "the response of a route matches the document",
+			method:     http.MethodGet,
+			path:       "/users/lisa@example.com",
+			statusCode: http.StatusOK,
+			getUser:    true,
+		},
+		{
+			name:       "fail because the password is too short",
+			method:     http.MethodPost,
+			path:       "/users",
+			body:       CreateUserReq{Email: "lisa@example.com", Password: "short"},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "fail because max_depth is not a number",
+			method:     http.MethodGet,
+			path:       "/friendship/path?from=lisa@example.com&to=john@example.com&max_depth=abc",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "fail because the operation is unknown",
+			method:     http.MethodGet,
+			path:       "/friendship/set",
+			body:       ListFriendSetReq{Friends: []string{"lisa@example.com", "john@example.com"}, Operation: "xor"},
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tcs {
+		mockGetUser := new(mockHandler.MockGetUserHandler)
+		if tc.getUser {
+			mockGetUser.On("Handle", mock.Anything, "lisa@example.com").Return(testUser("lisa@example.com"), nil).Once()
+		}
+
+		router := gin.New()
+		NewServer(app.Application{Queries: app.Queries{GetUser: mockGetUser}}).Router(router)
+
+		var body []byte
+		if tc.body != nil {
+			var err error
+			body, err = json.Marshal(tc.body)
+			assert.NoError(t, err)
+		}
+		req, err := http.NewRequest(tc.method, tc.path, bytes.NewBuffer(body))
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		res := httptest.NewRecorder()
+		router.ServeHTTP(res, req)
+
+		assert.Equal(t, tc.statusCode, res.Code, tc.name)
+		mock.AssertExpectationsForObjects(t, mockGetUser)
+	}
+}
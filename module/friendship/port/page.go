@@ -0,0 +1,73 @@
+package port
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/port/constant"
+)
+
+// PageReq is the cursor paging of the list endpoints, read from the query string
+type PageReq struct {
+	Limit  int    `form:"limit"`
+	Cursor string `form:"cursor"`
+	Sort   string `form:"sort"`
+	Since  string `form:"since"`
+}
+
+func (p PageReq) ToPage() (domain.Page, error) {
+	if p.Limit < 0 || p.Limit > domain.MaxPageLimit {
+		return domain.Page{}, common.ErrInvalidRequest(fmt.Errorf("limit must be between 1 and %d", domain.MaxPageLimit), constant.LIMIT)
+	}
+	if p.Sort != "" && !domain.PageSort(p.Sort).IsValid() {
+		return domain.Page{}, common.ErrInvalidRequest(domain.ErrPageSortIsNotValid, constant.SORT)
+	}
+	if p.Cursor != "" {
+		if _, err := domain.DecodeCursor(p.Cursor); err != nil {
+			return domain.Page{}, common.ErrInvalidRequest(err, constant.CURSOR)
+		}
+	}
+
+	page := domain.Page{Limit: p.Limit, Cursor: p.Cursor, Sort: domain.PageSort(p.Sort)}
+	if p.Since != "" {
+		since, err := time.Parse(time.RFC3339, p.Since)
+		if err != nil {
+			return domain.Page{}, common.ErrInvalidRequest(err, constant.SINCE)
+		}
+		page.Since = since
+	}
+
+	return page.WithDefaults(), nil
+}
+
+// PageFilter echoes the sort and the filter applied to a list
+type PageFilter struct {
+	Sort  domain.PageSort `json:"sort"`
+	Since *time.Time      `json:"since,omitempty"`
+}
+
+// bindPage reads the page of a list request from the query string
+func bindPage(c *gin.Context) (domain.Page, error) {
+	var req PageReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		return domain.Page{}, common.ErrInvalidRequest(err, "query")
+	}
+	return req.ToPage()
+}
+
+// pageResponse wraps a list response with the paging and the filter of its page
+func pageResponse(custom interface{}, page domain.Page, nextCursor string) *common.SuccessRes {
+	filter := PageFilter{Sort: page.Sort}
+	if !page.Since.IsZero() {
+		filter.Since = &page.Since
+	}
+
+	return common.PagingSuccessResponse(custom, common.Paging{
+		Limit:      page.Limit,
+		Cursor:     page.Cursor,
+		NextCursor: nextCursor,
+	}, filter)
+}
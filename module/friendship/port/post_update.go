@@ -0,0 +1,78 @@
+package port
+
+import (
+	"net/http"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/port/constant"
+)
+
+type PostUpdateReq struct {
+	Sender string `json:"sender"`
+	Text   string `json:"text"`
+}
+
+func (p PostUpdateReq) Validate() error {
+	if err := common.ValidateRequired(p.Sender, constant.SENDER); err != nil {
+		return err
+	}
+	if err := common.ValidateEmail(p.Sender); err != nil {
+		return err
+	}
+	if err := common.ValidateRequired(p.Text, constant.TEXT); err != nil {
+		return err
+	}
+	if utf8.RuneCountInString(p.Text) > domain.UpdateTextMaxLength {
+		return common.ErrInvalidRequest(domain.ErrUpdateTextIsNotValid, constant.TEXT)
+	}
+
+	return nil
+}
+
+type UpdateRes struct {
+	ID        string    `json:"id"`
+	Sender    string    `json:"sender"`
+	Text      string    `json:"text"`
+	Mentions  []string  `json:"mentions"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (s *Server) PostUpdate(c *gin.Context) {
+	var req PostUpdateReq
+	var err error
+	if err = c.ShouldBindJSON(&req); err != nil {
+		logger.Error("PostUpdate.ShouldBind: ", err)
+		common.HttpErrorHandler(c, common.ErrInvalidRequest(err, "body data"))
+		return
+	}
+
+	if err = req.Validate(); err != nil {
+		logger.Error("PostUpdate.Validate: ", err)
+		common.HttpErrorHandler(c, err)
+		return
+	}
+
+	update, err := s.app.Commands.PostUpdate.Handle(c.Request.Context(), payload.PostUpdatePayload{
+		Sender: req.Sender,
+		Text:   req.Text,
+	})
+	if err != nil {
+		logger.Error("PostUpdate.Handle: ", err)
+		common.HttpErrorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, common.SimpleSuccessResponse(UpdateRes{
+		ID:        update.Base.Id,
+		Sender:    req.Sender,
+		Text:      update.Text,
+		Mentions:  update.Mentions,
+		CreatedAt: update.Base.CreatedAt,
+	}))
+}
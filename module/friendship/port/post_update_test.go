@@ -0,0 +1,115 @@
+package port
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	mockHandler "github.com/phantranhieunhan/s3-assignment/mock/friendship/handler"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestCase_PostUpdate struct {
+	name        string
+	statusCode  int
+	bodyRequest PostUpdateReq
+
+	commandHandlerError error
+
+	hasValidateErr bool
+}
+
+func TestPostUpdate(t *testing.T) {
+	t.Parallel()
+
+	update := domain.Update{
+		Base:     domain.Base{Id: "update-1", CreatedAt: time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)},
+		UserID:   "user-1",
+		Text:     "hello kate@example.com",
+		Mentions: []string{"kate@example.com"},
+	}
+	tcs := []TestCase_PostUpdate{
+		{
+			name:        "successful",
+			bodyRequest: PostUpdateReq{Sender: "lisa@example.com", Text: update.Text},
+			statusCode:  http.StatusCreated,
+		},
+		{
+			name:           "fail because sender invalid",
+			bodyRequest:    PostUpdateReq{Sender: "lisa-example.com", Text: update.Text},
+			hasValidateErr: true,
+			statusCode:     http.StatusBadRequest,
+		},
+		{
+			name:           "fail because text is empty",
+			bodyRequest:    PostUpdateReq{Sender: "lisa@example.com"},
+			hasValidateErr: true,
+			statusCode:     http.StatusBadRequest,
+		},
+		{
+			name:           "fail because text is too long",
+			bodyRequest:    PostUpdateReq{Sender: "lisa@example.com", Text: strings.Repeat("a", domain.UpdateTextMaxLength+1)},
+			hasValidateErr: true,
+			statusCode:     http.StatusBadRequest,
+		},
+		{
+			name:                "fail because command handle has error",
+			bodyRequest:         PostUpdateReq{Sender: "lisa@example.com", Text: update.Text},
+			commandHandlerError: errors.New("command handler error"),
+			statusCode:          http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tcs {
+		mockPostUpdateHandler := new(mockHandler.MockPostUpdateHandler)
+		if !tc.hasValidateErr {
+			mockPostUpdateHandler.On("Handle", mock.Anything, payload.PostUpdatePayload{
+				Sender: tc.bodyRequest.Sender,
+				Text:   tc.bodyRequest.Text,
+			}).Once().Return(update, tc.commandHandlerError)
+		}
+
+		server := NewServer(app.Application{
+			Commands: app.Commands{
+				PostUpdate: mockPostUpdateHandler,
+			},
+		})
+		router := gin.Default()
+		router.POST("/test", server.PostUpdate)
+
+		jsonBody, err := json.Marshal(tc.bodyRequest)
+		assert.NoError(t, err)
+
+		req, err := http.NewRequest("POST", "/test", bytes.NewBuffer(jsonBody))
+		assert.NoError(t, err)
+		res := httptest.NewRecorder()
+		router.ServeHTTP(res, req)
+
+		assert.Equal(t, tc.statusCode, res.Code)
+		if tc.statusCode == http.StatusCreated {
+			resBody := &struct {
+				Data UpdateRes `json:"data"`
+			}{}
+			err = json.Unmarshal(res.Body.Bytes(), resBody)
+			assert.NoError(t, err)
+			assert.Equal(t, UpdateRes{
+				ID:        update.Base.Id,
+				Sender:    tc.bodyRequest.Sender,
+				Text:      update.Text,
+				Mentions:  update.Mentions,
+				CreatedAt: update.Base.CreatedAt,
+			}, resBody.Data)
+		}
+		mock.AssertExpectationsForObjects(t, mockPostUpdateHandler)
+	}
+}
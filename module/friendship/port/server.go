@@ -2,7 +2,11 @@ package port
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/auth"
+	"github.com/phantranhieunhan/s3-assignment/middleware"
 	"github.com/phantranhieunhan/s3-assignment/module/friendship/app"
+	"github.com/phantranhieunhan/s3-assignment/pkg/config"
 )
 
 type Server struct {
@@ -16,13 +20,76 @@ func NewServer(app app.Application) Server {
 }
 
 func (s Server) Router(r *gin.Engine) {
-	friendship := r.Group("friendship")
-	friendship.POST("connect", s.ConnectFriendship)
+	// commands acting on behalf of a requestor need the token issued on login
+	authenticate := middleware.Authenticate(auth.NewTokenIssuer(config.C.Auth.Secret, config.C.Auth.TokenTTL))
+
+	spec := mustLoadOpenAPI()
+	validate, err := middleware.ValidateOpenAPI(spec)
+	if err != nil {
+		panic(err)
+	}
+	r.GET("openapi.json", serveOpenAPI(spec))
+	r.GET("docs", s.SwaggerUI)
+
+	// the requests and the responses of the api are validated against the openapi document
+	api := r.Group("", validate)
+
+	api.POST("auth/login", s.Login)
+
+	friendship := api.Group("friendship")
+	friendship.POST("connect", authenticate, s.ConnectFriendship)
 	friendship.GET("friends", s.ListFriends)
 	friendship.GET("mutuals", s.ListCommonFriends)
+	friendship.GET("suggestions", s.ListFriendSuggestions)
+	friendship.GET("set", s.ListFriendSet)
+	friendship.GET("path", s.FindFriendshipPath)
+	friendship.POST("request", authenticate, s.RequestFriendship)
+	friendship.POST("accept", authenticate, s.AcceptFriendship)
+	friendship.POST("reject", authenticate, s.RejectFriendship)
+	friendship.POST("cancel", authenticate, s.CancelFriendship)
+	friendship.POST("unfriend", authenticate, s.Unfriend)
+	friendship.GET("requests/incoming", authenticate, s.ListIncomingFriendRequests)
+	friendship.GET("requests/outgoing", authenticate, s.ListOutgoingFriendRequests)
 
-	subscription := r.Group("subscription")
-	subscription.POST("subscribe", s.SubscribeUser)
-	subscription.POST("block", s.BlockUpdatesUser)
+	subscription := api.Group("subscription")
+	subscription.POST("subscribe", authenticate, s.SubscribeUser)
+	subscription.POST("block", authenticate, s.BlockUpdatesUser)
+	subscription.POST("unblock", authenticate, s.UnblockUser)
 	subscription.GET("updates_user", s.ListUpdatesUser)
+	subscription.GET("subscribers", s.ListSubscribers)
+	subscription.GET("blocked", authenticate, s.ListBlockedUsers)
+
+	api.POST("updates", authenticate, s.PostUpdate)
+	api.GET("feed", authenticate, s.GetFeed)
+
+	users := api.Group("users")
+	users.POST("", s.CreateUser)
+	users.GET(":email", s.GetUser)
+	users.PATCH(":email", authenticate, s.UpdateUser)
+	// deleting the user is deleting its account, there is one way to delete a user
+	users.DELETE(":email", authenticate, s.DeleteAccount)
+	users.GET(":email/export", authenticate, s.ExportUser)
+	users.DELETE(":email/account", authenticate, s.DeleteAccount)
+	// an account scheduled for deletion can't log in, its password authenticates the restore
+	users.POST(":email/account/restore", s.RestoreAccount)
+
+	admin := api.Group("admin", middleware.AdminOnly(config.C.Admin.Token))
+	admin.POST("users/:email/password", s.SetPassword)
+	admin.GET("subscription/blockers", s.ListBlockers)
+	admin.POST("webhooks", s.RegisterWebhook)
+	admin.GET("webhooks", s.ListWebhooks)
+	admin.DELETE("webhooks/:id", s.DeleteWebhook)
+	admin.GET("webhooks/:id/dead-letters", s.ListDeadWebhookDeliveries)
+	admin.POST("webhooks/:id/dead-letters/:delivery_id/replay", s.ReplayWebhookDelivery)
+	admin.POST("import", s.ImportRelationships)
+	admin.GET("audit", s.ListAuditLog)
+}
+
+// authenticatedUserID returns the user of the token checked by the authenticate middleware
+func authenticatedUserID(c *gin.Context) (string, bool) {
+	userID, ok := auth.UserIDFromContext(c.Request.Context())
+	if !ok {
+		common.HttpErrorHandler(c, common.NewUnauthorized(middleware.ErrMissingToken, middleware.ErrMissingToken.Error(), "ErrUnauthorized"))
+	}
+	return userID, ok
 }
@@ -16,7 +16,7 @@ type SubscribeUserReq struct {
 	Target    string `json:"target"`
 }
 
-func (l SubscribeUserReq) validate() error {
+func (l SubscribeUserReq) Validate() error {
 	if err := common.ValidateRequired(l.Requestor, constant.REQUESTOR); err != nil {
 		return err
 	}
@@ -42,7 +42,7 @@ func (s *Server) SubscribeUser(c *gin.Context) {
 		return
 	}
 
-	if err = req.validate(); err != nil {
+	if err = req.Validate(); err != nil {
 		logger.Error("ListCommonFriends.Validate: ", err)
 		common.HttpErrorHandler(c, err)
 		return
@@ -0,0 +1,61 @@
+package port
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/port/constant"
+)
+
+type UnfriendReq struct {
+	Requestor string `json:"requestor"`
+	Target    string `json:"target"`
+}
+
+func (l UnfriendReq) Validate() error {
+	if err := common.ValidateRequired(l.Requestor, constant.REQUESTOR); err != nil {
+		return err
+	}
+	if err := common.ValidateEmail(l.Requestor); err != nil {
+		return err
+	}
+
+	if err := common.ValidateRequired(l.Target, constant.TARGET); err != nil {
+		return err
+	}
+	if err := common.ValidateEmail(l.Target); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *Server) Unfriend(c *gin.Context) {
+	var req UnfriendReq
+	var err error
+	if err = c.ShouldBindJSON(&req); err != nil {
+		logger.Error("Unfriend.ShouldBind: ", err)
+		common.HttpErrorHandler(c, common.ErrInvalidRequest(err, "body data"))
+		return
+	}
+
+	if err = req.Validate(); err != nil {
+		logger.Error("Unfriend.Validate: ", err)
+		common.HttpErrorHandler(c, err)
+		return
+	}
+
+	err = s.app.Commands.Unfriend.Handle(c.Request.Context(), payload.UnfriendPayload{
+		Requestor: req.Requestor,
+		Target:    req.Target,
+	})
+	if err != nil {
+		logger.Error("Unfriend.Handle: ", err)
+		common.HttpErrorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, common.SimpleSuccessResponse(nil))
+}
@@ -0,0 +1,108 @@
+package port
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/phantranhieunhan/s3-assignment/common"
+	mockHandler "github.com/phantranhieunhan/s3-assignment/mock/friendship/handler"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestCase_Unfriend struct {
+	name        string
+	hasFinalErr bool
+	bodyRequest UnfriendReq
+
+	commandHandlerError error
+
+	hasValidateErr bool
+}
+
+func TestUnfriend(t *testing.T) {
+	t.Parallel()
+
+	mockUnfriendHandler := new(mockHandler.MockUnfriendHandler)
+	commandHandlerErr := errors.New("command handler error")
+	tcs := []TestCase_Unfriend{
+		{
+			name: "successful",
+			bodyRequest: UnfriendReq{
+				Requestor: "lisa@example.com",
+				Target:    "john@example.com",
+			},
+		},
+		{
+			name: "fail because target email is not provided",
+			bodyRequest: UnfriendReq{
+				Requestor: "lisa@example.com",
+			},
+			hasValidateErr: true,
+			hasFinalErr:    true,
+		},
+		{
+			name: "fail because requestor email invalid",
+			bodyRequest: UnfriendReq{
+				Requestor: "lisa-example.com",
+				Target:    "john@example.com",
+			},
+			hasValidateErr: true,
+			hasFinalErr:    true,
+		},
+		{
+			name: "fail because command handle has error",
+			bodyRequest: UnfriendReq{
+				Requestor: "lisa@example.com",
+				Target:    "john@example.com",
+			},
+			commandHandlerError: commandHandlerErr,
+			hasFinalErr:         true,
+		},
+	}
+
+	for _, tc := range tcs {
+		dataReq := tc.bodyRequest
+		if !tc.hasValidateErr {
+			mockUnfriendHandler.On("Handle", mock.Anything, payload.UnfriendPayload{
+				Requestor: tc.bodyRequest.Requestor,
+				Target:    tc.bodyRequest.Target,
+			}).Once().Return(tc.commandHandlerError)
+		}
+
+		server := NewServer(app.Application{
+			Commands: app.Commands{
+				Unfriend: mockUnfriendHandler,
+			},
+		})
+		router := gin.Default()
+
+		router.POST("/test", server.Unfriend)
+
+		jsonBody, err := json.Marshal(dataReq)
+		assert.NoError(t, err)
+
+		req, err := http.NewRequest("POST", "/test", bytes.NewBuffer(jsonBody))
+		assert.NoError(t, err)
+		res := httptest.NewRecorder()
+		router.ServeHTTP(res, req)
+
+		if tc.hasFinalErr {
+			assert.Equal(t, http.StatusBadRequest, res.Code)
+		} else {
+			assert.Equal(t, http.StatusOK, res.Code)
+			resBody := &common.SuccessRes{}
+			err = json.Unmarshal(res.Body.Bytes(), resBody)
+			assert.NoError(t, err)
+			assert.Equal(t, common.SimpleSuccessResponse(nil), resBody)
+		}
+	}
+	mock.AssertExpectationsForObjects(t, mockUnfriendHandler)
+}
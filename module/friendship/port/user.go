@@ -0,0 +1,274 @@
+package port
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/port/constant"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UserReq struct {
+	Email string `json:"email"`
+}
+
+func (l UserReq) Validate() error {
+	if err := common.ValidateRequired(l.Email, constant.EMAIL); err != nil {
+		return err
+	}
+	if err := common.ValidateEmail(l.Email); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+type CreateUserReq struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+func (l CreateUserReq) Validate() error {
+	if err := (UserReq{Email: l.Email}).Validate(); err != nil {
+		return err
+	}
+	if err := common.ValidateRequired(l.Password, constant.PASSWORD); err != nil {
+		return err
+	}
+	if len(l.Password) < domain.UserPasswordMinLength {
+		return common.ErrInvalidRequest(domain.ErrPasswordIsNotValid, constant.PASSWORD)
+	}
+
+	return nil
+}
+
+type SetPasswordReq struct {
+	Password string `json:"password"`
+}
+
+func (l SetPasswordReq) Validate() error {
+	if err := common.ValidateRequired(l.Password, constant.PASSWORD); err != nil {
+		return err
+	}
+	if len(l.Password) < domain.UserPasswordMinLength {
+		return common.ErrInvalidRequest(domain.ErrPasswordIsNotValid, constant.PASSWORD)
+	}
+
+	return nil
+}
+
+type RestoreAccountReq struct {
+	Password string `json:"password"`
+}
+
+func (l RestoreAccountReq) Validate() error {
+	return common.ValidateRequired(l.Password, constant.PASSWORD)
+}
+
+type UserRes struct {
+	ID        string    `json:"id"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func toUserRes(user domain.User) UserRes {
+	return UserRes{
+		ID:        user.Base.Id,
+		Email:     user.Email,
+		CreatedAt: user.Base.CreatedAt,
+		UpdatedAt: user.Base.UpdatedAt,
+	}
+}
+
+// bindUserEmail reads and validates the email given in the path
+func bindUserEmail(c *gin.Context, action string) (string, bool) {
+	req := UserReq{Email: c.Param(constant.EMAIL)}
+	if err := req.Validate(); err != nil {
+		logger.Error(action+".Validate: ", err)
+		common.HttpErrorHandler(c, err)
+		return "", false
+	}
+	return req.Email, true
+}
+
+func (s *Server) CreateUser(c *gin.Context) {
+	var req CreateUserReq
+	var err error
+
+	if err = c.ShouldBindJSON(&req); err != nil {
+		logger.Error("CreateUser.ShouldBind: ", err)
+		common.HttpErrorHandler(c, common.ErrInvalidRequest(err, "body data"))
+		return
+	}
+
+	if err = req.Validate(); err != nil {
+		logger.Error("CreateUser.Validate: ", err)
+		common.HttpErrorHandler(c, err)
+		return
+	}
+
+	user, err := s.app.Commands.CreateUser.Handle(c.Request.Context(), payload.CreateUserPayload{
+		Email:    req.Email,
+		Password: req.Password,
+	})
+	if err != nil {
+		logger.Error("CreateUser.Handle: ", err)
+		common.HttpErrorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, common.SimpleSuccessResponse(toUserRes(user)))
+}
+
+func (s *Server) GetUser(c *gin.Context) {
+	email, ok := bindUserEmail(c, "GetUser")
+	if !ok {
+		return
+	}
+
+	user, err := s.app.Queries.GetUser.Handle(c.Request.Context(), email)
+	if err != nil {
+		logger.Error("GetUser.Handle: ", err)
+		common.HttpErrorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, common.SimpleSuccessResponse(toUserRes(user)))
+}
+
+func (s *Server) UpdateUser(c *gin.Context) {
+	email, ok := bindUserEmail(c, "UpdateUser")
+	if !ok {
+		return
+	}
+
+	var req UserReq
+	var err error
+	if err = c.ShouldBindJSON(&req); err != nil {
+		logger.Error("UpdateUser.ShouldBind: ", err)
+		common.HttpErrorHandler(c, common.ErrInvalidRequest(err, "body data"))
+		return
+	}
+
+	if err = req.Validate(); err != nil {
+		logger.Error("UpdateUser.Validate: ", err)
+		common.HttpErrorHandler(c, err)
+		return
+	}
+
+	user, err := s.app.Commands.UpdateUser.Handle(c.Request.Context(), payload.UpdateUserPayload{
+		Email:    email,
+		NewEmail: req.Email,
+	})
+	if err != nil {
+		logger.Error("UpdateUser.Handle: ", err)
+		common.HttpErrorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, common.SimpleSuccessResponse(toUserRes(user)))
+}
+
+// SetPassword resets the password of the user, the admin hands the new password to the user
+func (s *Server) SetPassword(c *gin.Context) {
+	email, ok := bindUserEmail(c, "SetPassword")
+	if !ok {
+		return
+	}
+
+	var req SetPasswordReq
+	var err error
+	if err = c.ShouldBindJSON(&req); err != nil {
+		logger.Error("SetPassword.ShouldBind: ", err)
+		common.HttpErrorHandler(c, common.ErrInvalidRequest(err, "body data"))
+		return
+	}
+
+	if err = req.Validate(); err != nil {
+		logger.Error("SetPassword.Validate: ", err)
+		common.HttpErrorHandler(c, err)
+		return
+	}
+
+	err = s.app.Commands.SetPassword.Handle(c.Request.Context(), payload.SetPasswordPayload{
+		Email:    email,
+		Password: req.Password,
+	})
+	if err != nil {
+		logger.Error("SetPassword.Handle: ", err)
+		common.HttpErrorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, common.SimpleSuccessResponse(nil))
+}
+
+type AccountDeletionRes struct {
+	RequestedAt time.Time `json:"requested_at"`
+	PurgeAt     time.Time `json:"purge_at"`
+	// Purged is true when the account was purged right away, without a grace period
+	Purged bool `json:"purged"`
+}
+
+// DeleteAccount deletes the account of the user along with its friendships, subscriptions and blocks.
+// The account can be restored until the end of the grace period
+func (s *Server) DeleteAccount(c *gin.Context) {
+	email, ok := bindUserEmail(c, "DeleteAccount")
+	if !ok {
+		return
+	}
+
+	deletion, err := s.app.Commands.DeleteAccount.Handle(c.Request.Context(), email)
+	if err != nil {
+		logger.Error("DeleteAccount.Handle: ", err)
+		common.HttpErrorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, common.SimpleSuccessResponse(AccountDeletionRes{
+		RequestedAt: deletion.RequestedAt,
+		PurgeAt:     deletion.PurgeAt,
+		Purged:      !deletion.PurgeAt.After(deletion.RequestedAt),
+	}))
+}
+
+// RestoreAccount cancels the scheduled deletion of the account. The account can't log in until it is restored,
+// the password of the account authenticates the request instead of a token
+func (s *Server) RestoreAccount(c *gin.Context) {
+	email, ok := bindUserEmail(c, "RestoreAccount")
+	if !ok {
+		return
+	}
+
+	var req RestoreAccountReq
+	var err error
+	if err = c.ShouldBindJSON(&req); err != nil {
+		logger.Error("RestoreAccount.ShouldBind: ", err)
+		common.HttpErrorHandler(c, common.ErrInvalidRequest(err, "body data"))
+		return
+	}
+
+	if err = req.Validate(); err != nil {
+		logger.Error("RestoreAccount.Validate: ", err)
+		common.HttpErrorHandler(c, err)
+		return
+	}
+
+	err = s.app.Commands.CancelAccountDeletion.Handle(c.Request.Context(), payload.RestoreAccountPayload{
+		Email:    email,
+		Password: req.Password,
+	})
+	if err != nil {
+		logger.Error("CancelAccountDeletion.Handle: ", err)
+		common.HttpErrorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, common.SimpleSuccessResponse(nil))
+}
@@ -0,0 +1,468 @@
+package port
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/phantranhieunhan/s3-assignment/common"
+	mockHandler "github.com/phantranhieunhan/s3-assignment/mock/friendship/handler"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestCase_User struct {
+	name        string
+	hasFinalErr bool
+	pathEmail   string
+	bodyRequest UserReq
+
+	handlerError error
+
+	hasValidateErr bool
+}
+
+type userSuccessRes struct {
+	Success bool    `json:"success"`
+	Data    UserRes `json:"data"`
+}
+
+func testUser(email string) domain.User {
+	now := time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+	return domain.User{
+		Base:  domain.Base{Id: "user-id", CreatedAt: now, UpdatedAt: now},
+		Email: email,
+	}
+}
+
+func serveUser(t *testing.T, method string, handler gin.HandlerFunc, tc TestCase_User, expectedCode int) *httptest.ResponseRecorder {
+	router := gin.Default()
+	router.Handle(method, "/users/:email", handler)
+
+	jsonBody, err := json.Marshal(tc.bodyRequest)
+	assert.NoError(t, err)
+
+	req, err := http.NewRequest(method, "/users/"+tc.pathEmail, bytes.NewBuffer(jsonBody))
+	assert.NoError(t, err)
+	res := httptest.NewRecorder()
+	router.ServeHTTP(res, req)
+
+	if tc.hasFinalErr {
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+	} else {
+		assert.Equal(t, expectedCode, res.Code)
+	}
+	return res
+}
+
+func assertUserRes(t *testing.T, res *httptest.ResponseRecorder, user domain.User) {
+	resBody := &userSuccessRes{}
+	err := json.Unmarshal(res.Body.Bytes(), resBody)
+	assert.NoError(t, err)
+	assert.Equal(t, &userSuccessRes{Success: true, Data: toUserRes(user)}, resBody)
+}
+
+type TestCase_CreateUser struct {
+	name        string
+	hasFinalErr bool
+	bodyRequest CreateUserReq
+
+	handlerError error
+
+	hasValidateErr bool
+}
+
+func TestCreateUser(t *testing.T) {
+	t.Parallel()
+
+	tcs := []TestCase_CreateUser{
+		{
+			name:        "successful",
+			bodyRequest: CreateUserReq{Email: "lisa@example.com", Password: "password"},
+		},
+		{
+			name:           "fail because email is not provided",
+			bodyRequest:    CreateUserReq{Password: "password"},
+			hasValidateErr: true,
+			hasFinalErr:    true,
+		},
+		{
+			name:           "fail because email invalid",
+			bodyRequest:    CreateUserReq{Email: "lisa-example.com", Password: "password"},
+			hasValidateErr: true,
+			hasFinalErr:    true,
+		},
+		{
+			name:           "fail because password is not provided",
+			bodyRequest:    CreateUserReq{Email: "lisa@example.com"},
+			hasValidateErr: true,
+			hasFinalErr:    true,
+		},
+		{
+			name:           "fail because password is too short",
+			bodyRequest:    CreateUserReq{Email: "lisa@example.com", Password: "short"},
+			hasValidateErr: true,
+			hasFinalErr:    true,
+		},
+		{
+			name:         "fail because command handle has error",
+			bodyRequest:  CreateUserReq{Email: "lisa@example.com", Password: "password"},
+			handlerError: errors.New("command handler error"),
+			hasFinalErr:  true,
+		},
+	}
+
+	for _, tc := range tcs {
+		mockCreateUserHandler := new(mockHandler.MockCreateUserHandler)
+		user := testUser(tc.bodyRequest.Email)
+		if !tc.hasValidateErr {
+			mockCreateUserHandler.On("Handle", mock.Anything, payload.CreateUserPayload{
+				Email:    tc.bodyRequest.Email,
+				Password: tc.bodyRequest.Password,
+			}).Once().Return(user, tc.handlerError)
+		}
+
+		server := NewServer(app.Application{
+			Commands: app.Commands{
+				CreateUser: mockCreateUserHandler,
+			},
+		})
+		router := gin.Default()
+		router.POST("/users", server.CreateUser)
+
+		jsonBody, err := json.Marshal(tc.bodyRequest)
+		assert.NoError(t, err)
+
+		req, err := http.NewRequest("POST", "/users", bytes.NewBuffer(jsonBody))
+		assert.NoError(t, err)
+		res := httptest.NewRecorder()
+		router.ServeHTTP(res, req)
+
+		if tc.hasFinalErr {
+			assert.Equal(t, http.StatusBadRequest, res.Code)
+		} else {
+			assert.Equal(t, http.StatusCreated, res.Code)
+			assertUserRes(t, res, user)
+		}
+		mock.AssertExpectationsForObjects(t, mockCreateUserHandler)
+	}
+}
+
+func TestGetUser(t *testing.T) {
+	t.Parallel()
+
+	tcs := []TestCase_User{
+		{
+			name:      "successful",
+			pathEmail: "lisa@example.com",
+		},
+		{
+			name:           "fail because email invalid",
+			pathEmail:      "lisa-example.com",
+			hasValidateErr: true,
+			hasFinalErr:    true,
+		},
+		{
+			name:         "fail because query handle has error",
+			pathEmail:    "lisa@example.com",
+			handlerError: errors.New("query handler error"),
+			hasFinalErr:  true,
+		},
+	}
+
+	for _, tc := range tcs {
+		mockGetUserHandler := new(mockHandler.MockGetUserHandler)
+		user := testUser(tc.pathEmail)
+		if !tc.hasValidateErr {
+			mockGetUserHandler.On("Handle", mock.Anything, tc.pathEmail).Once().Return(user, tc.handlerError)
+		}
+
+		server := NewServer(app.Application{
+			Queries: app.Queries{
+				GetUser: mockGetUserHandler,
+			},
+		})
+		res := serveUser(t, http.MethodGet, server.GetUser, tc, http.StatusOK)
+		if !tc.hasFinalErr {
+			assertUserRes(t, res, user)
+		}
+		mock.AssertExpectationsForObjects(t, mockGetUserHandler)
+	}
+}
+
+func TestUpdateUser(t *testing.T) {
+	t.Parallel()
+
+	tcs := []TestCase_User{
+		{
+			name:        "successful",
+			pathEmail:   "lisa@example.com",
+			bodyRequest: UserReq{Email: "lisa.new@example.com"},
+		},
+		{
+			name:           "fail because path email invalid",
+			pathEmail:      "lisa-example.com",
+			bodyRequest:    UserReq{Email: "lisa.new@example.com"},
+			hasValidateErr: true,
+			hasFinalErr:    true,
+		},
+		{
+			name:           "fail because new email is not provided",
+			pathEmail:      "lisa@example.com",
+			hasValidateErr: true,
+			hasFinalErr:    true,
+		},
+		{
+			name:           "fail because new email invalid",
+			pathEmail:      "lisa@example.com",
+			bodyRequest:    UserReq{Email: "lisa-new-example.com"},
+			hasValidateErr: true,
+			hasFinalErr:    true,
+		},
+		{
+			name:         "fail because command handle has error",
+			pathEmail:    "lisa@example.com",
+			bodyRequest:  UserReq{Email: "lisa.new@example.com"},
+			handlerError: errors.New("command handler error"),
+			hasFinalErr:  true,
+		},
+	}
+
+	for _, tc := range tcs {
+		mockUpdateUserHandler := new(mockHandler.MockUpdateUserHandler)
+		user := testUser(tc.bodyRequest.Email)
+		if !tc.hasValidateErr {
+			mockUpdateUserHandler.On("Handle", mock.Anything, payload.UpdateUserPayload{
+				Email:    tc.pathEmail,
+				NewEmail: tc.bodyRequest.Email,
+			}).Once().Return(user, tc.handlerError)
+		}
+
+		server := NewServer(app.Application{
+			Commands: app.Commands{
+				UpdateUser: mockUpdateUserHandler,
+			},
+		})
+		res := serveUser(t, http.MethodPatch, server.UpdateUser, tc, http.StatusOK)
+		if !tc.hasFinalErr {
+			assertUserRes(t, res, user)
+		}
+		mock.AssertExpectationsForObjects(t, mockUpdateUserHandler)
+	}
+}
+
+func TestDeleteAccount(t *testing.T) {
+	t.Parallel()
+
+	requestedAt := time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+	scheduled := domain.UserDeletion{UserID: "user-id", RequestedAt: requestedAt, PurgeAt: requestedAt.Add(time.Hour)}
+
+	tcs := []struct {
+		TestCase_User
+		deletion domain.UserDeletion
+		purged   bool
+	}{
+		{
+			TestCase_User: TestCase_User{name: "successful with a grace period", pathEmail: "lisa@example.com"},
+			deletion:      scheduled,
+		},
+		{
+			TestCase_User: TestCase_User{name: "successful without a grace period", pathEmail: "lisa@example.com"},
+			deletion:      domain.UserDeletion{UserID: "user-id", RequestedAt: requestedAt, PurgeAt: requestedAt},
+			purged:        true,
+		},
+		{
+			TestCase_User: TestCase_User{
+				name:           "fail because email invalid",
+				pathEmail:      "lisa-example.com",
+				hasValidateErr: true,
+				hasFinalErr:    true,
+			},
+		},
+		{
+			TestCase_User: TestCase_User{
+				name:         "fail because command handle has error",
+				pathEmail:    "lisa@example.com",
+				handlerError: errors.New("command handler error"),
+				hasFinalErr:  true,
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		mockDeleteAccountHandler := new(mockHandler.MockDeleteAccountHandler)
+		if !tc.hasValidateErr {
+			mockDeleteAccountHandler.On("Handle", mock.Anything, tc.pathEmail).Once().Return(tc.deletion, tc.handlerError)
+		}
+
+		server := NewServer(app.Application{
+			Commands: app.Commands{
+				DeleteAccount: mockDeleteAccountHandler,
+			},
+		})
+		res := serveUser(t, http.MethodDelete, server.DeleteAccount, tc.TestCase_User, http.StatusOK)
+		if !tc.hasFinalErr {
+			var body struct {
+				Data AccountDeletionRes `json:"data"`
+			}
+			assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &body), tc.name)
+			assert.Equal(t, AccountDeletionRes{RequestedAt: tc.deletion.RequestedAt, PurgeAt: tc.deletion.PurgeAt, Purged: tc.purged}, body.Data, tc.name)
+		}
+		mock.AssertExpectationsForObjects(t, mockDeleteAccountHandler)
+	}
+}
+
+func TestRestoreAccount(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name        string
+		pathEmail   string
+		bodyRequest RestoreAccountReq
+
+		handlerError error
+
+		hasValidateErr bool
+		hasFinalErr    bool
+	}{
+		{
+			name:        "successful",
+			pathEmail:   "lisa@example.com",
+			bodyRequest: RestoreAccountReq{Password: "password"},
+		},
+		{
+			name:           "fail because email invalid",
+			pathEmail:      "lisa-example.com",
+			bodyRequest:    RestoreAccountReq{Password: "password"},
+			hasValidateErr: true,
+			hasFinalErr:    true,
+		},
+		{
+			name:           "fail because password is missing",
+			pathEmail:      "lisa@example.com",
+			hasValidateErr: true,
+			hasFinalErr:    true,
+		},
+		{
+			name:         "fail because no deletion is scheduled",
+			pathEmail:    "lisa@example.com",
+			bodyRequest:  RestoreAccountReq{Password: "password"},
+			handlerError: common.ErrInvalidRequest(domain.ErrUserDeletionNotFound, "email"),
+			hasFinalErr:  true,
+		},
+	}
+
+	for _, tc := range tcs {
+		mockCancelHandler := new(mockHandler.MockCancelAccountDeletionHandler)
+		if !tc.hasValidateErr {
+			mockCancelHandler.On("Handle", mock.Anything, payload.RestoreAccountPayload{
+				Email:    tc.pathEmail,
+				Password: tc.bodyRequest.Password,
+			}).Once().Return(tc.handlerError)
+		}
+
+		server := NewServer(app.Application{
+			Commands: app.Commands{
+				CancelAccountDeletion: mockCancelHandler,
+			},
+		})
+		router := gin.Default()
+		router.POST("/users/:email/account/restore", server.RestoreAccount)
+
+		jsonBody, err := json.Marshal(tc.bodyRequest)
+		assert.NoError(t, err)
+		req, err := http.NewRequest(http.MethodPost, "/users/"+tc.pathEmail+"/account/restore", bytes.NewBuffer(jsonBody))
+		assert.NoError(t, err)
+		res := httptest.NewRecorder()
+		router.ServeHTTP(res, req)
+
+		if tc.hasFinalErr {
+			assert.Equal(t, http.StatusBadRequest, res.Code, tc.name)
+		} else {
+			assert.Equal(t, http.StatusOK, res.Code, tc.name)
+		}
+		mock.AssertExpectationsForObjects(t, mockCancelHandler)
+	}
+}
+
+func TestSetPassword(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name        string
+		pathEmail   string
+		bodyRequest SetPasswordReq
+
+		handlerError error
+
+		hasValidateErr bool
+		hasFinalErr    bool
+	}{
+		{
+			name:        "successful",
+			pathEmail:   "lisa@example.com",
+			bodyRequest: SetPasswordReq{Password: "new password"},
+		},
+		{
+			name:           "fail because email invalid",
+			pathEmail:      "lisa-example.com",
+			bodyRequest:    SetPasswordReq{Password: "new password"},
+			hasValidateErr: true,
+			hasFinalErr:    true,
+		},
+		{
+			name:           "fail because password is too short",
+			pathEmail:      "lisa@example.com",
+			bodyRequest:    SetPasswordReq{Password: "short"},
+			hasValidateErr: true,
+			hasFinalErr:    true,
+		},
+		{
+			name:         "fail because command handle has error",
+			pathEmail:    "lisa@example.com",
+			bodyRequest:  SetPasswordReq{Password: "new password"},
+			handlerError: common.ErrInvalidRequest(domain.ErrNotFoundUserByEmail, "email"),
+			hasFinalErr:  true,
+		},
+	}
+
+	for _, tc := range tcs {
+		mockSetPasswordHandler := new(mockHandler.MockSetPasswordHandler)
+		if !tc.hasValidateErr {
+			mockSetPasswordHandler.On("Handle", mock.Anything, payload.SetPasswordPayload{
+				Email:    tc.pathEmail,
+				Password: tc.bodyRequest.Password,
+			}).Once().Return(tc.handlerError)
+		}
+
+		server := NewServer(app.Application{
+			Commands: app.Commands{
+				SetPassword: mockSetPasswordHandler,
+			},
+		})
+		router := gin.Default()
+		router.POST("/admin/users/:email/password", server.SetPassword)
+
+		jsonBody, err := json.Marshal(tc.bodyRequest)
+		assert.NoError(t, err)
+		req, err := http.NewRequest(http.MethodPost, "/admin/users/"+tc.pathEmail+"/password", bytes.NewBuffer(jsonBody))
+		assert.NoError(t, err)
+		res := httptest.NewRecorder()
+		router.ServeHTTP(res, req)
+
+		if tc.hasFinalErr {
+			assert.Equal(t, http.StatusBadRequest, res.Code, tc.name)
+		} else {
+			assert.Equal(t, http.StatusOK, res.Code, tc.name)
+		}
+		mock.AssertExpectationsForObjects(t, mockSetPasswordHandler)
+	}
+}
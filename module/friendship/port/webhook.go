@@ -0,0 +1,139 @@
+package port
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+	"github.com/phantranhieunhan/s3-assignment/common"
+	"github.com/phantranhieunhan/s3-assignment/common/logger"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/port/constant"
+)
+
+type RegisterWebhookReq struct {
+	URL        string   `json:"url"`
+	Secret     string   `json:"secret"`
+	EventTypes []string `json:"event_types"`
+}
+
+func (r RegisterWebhookReq) Validate() error {
+	if err := common.ValidateRequired(r.URL, constant.URL); err != nil {
+		return err
+	}
+	u, err := url.Parse(r.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return common.ErrInvalidRequest(domain.ErrWebhookURLIsNotValid, constant.URL)
+	}
+
+	if len(r.Secret) < domain.WebhookSecretMinLength {
+		return common.ErrInvalidRequest(domain.ErrWebhookSecretIsNotValid, constant.SECRET)
+	}
+
+	if err := common.ValidateRequired(r.EventTypes, constant.EVENT_TYPES); err != nil {
+		return err
+	}
+	for _, t := range r.EventTypes {
+		if !domain.EventType(t).IsValid() {
+			return common.ErrInvalidRequest(domain.ErrWebhookEventTypeIsNotValid, constant.EVENT_TYPES)
+		}
+	}
+
+	return nil
+}
+
+type ListWebhooksRes struct {
+	Webhooks []domain.Webhook `json:"webhooks"`
+	Count    int              `json:"count"`
+}
+
+type ListDeadWebhookDeliveriesRes struct {
+	Deliveries []domain.WebhookDelivery `json:"deliveries"`
+	Count      int                      `json:"count"`
+}
+
+func (s *Server) RegisterWebhook(c *gin.Context) {
+	var req RegisterWebhookReq
+	var err error
+
+	if err = c.ShouldBindJSON(&req); err != nil {
+		logger.Error("RegisterWebhook.ShouldBind: ", err)
+		common.HttpErrorHandler(c, common.ErrInvalidRequest(err, "body data"))
+		return
+	}
+
+	if err = req.Validate(); err != nil {
+		logger.Error("RegisterWebhook.Validate: ", err)
+		common.HttpErrorHandler(c, err)
+		return
+	}
+
+	eventTypes := make([]domain.EventType, 0, len(req.EventTypes))
+	for _, t := range req.EventTypes {
+		eventTypes = append(eventTypes, domain.EventType(t))
+	}
+	webhook, err := s.app.Commands.RegisterWebhook.Handle(c.Request.Context(), payload.RegisterWebhookPayload{
+		URL:        req.URL,
+		Secret:     req.Secret,
+		EventTypes: eventTypes,
+	})
+	if err != nil {
+		logger.Error("RegisterWebhook.Handle: ", err)
+		common.HttpErrorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, common.SimpleSuccessResponse(webhook))
+}
+
+func (s *Server) ListWebhooks(c *gin.Context) {
+	list, err := s.app.Queries.ListWebhooks.Handle(c.Request.Context())
+	if err != nil {
+		logger.Error("ListWebhooks.Handle: ", err)
+		common.HttpErrorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, common.CustomSuccessResponse(
+		ListWebhooksRes{Webhooks: list, Count: len(list)},
+	))
+}
+
+func (s *Server) DeleteWebhook(c *gin.Context) {
+	if err := s.app.Commands.DeleteWebhook.Handle(c.Request.Context(), c.Param(constant.ID)); err != nil {
+		logger.Error("DeleteWebhook.Handle: ", err)
+		common.HttpErrorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, common.SimpleSuccessResponse(nil))
+}
+
+// ListDeadWebhookDeliveries lists the dead letters of the webhook, the deliveries which ran out of attempts
+func (s *Server) ListDeadWebhookDeliveries(c *gin.Context) {
+	list, err := s.app.Queries.ListDeadWebhookDeliveries.Handle(c.Request.Context(), c.Param(constant.ID))
+	if err != nil {
+		logger.Error("ListDeadWebhookDeliveries.Handle: ", err)
+		common.HttpErrorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, common.CustomSuccessResponse(
+		ListDeadWebhookDeliveriesRes{Deliveries: list, Count: len(list)},
+	))
+}
+
+func (s *Server) ReplayWebhookDelivery(c *gin.Context) {
+	delivery, err := s.app.Commands.ReplayWebhookDelivery.Handle(c.Request.Context(), payload.ReplayWebhookDeliveryPayload{
+		WebhookID:  c.Param(constant.ID),
+		DeliveryID: c.Param(constant.DELIVERY_ID),
+	})
+	if err != nil {
+		logger.Error("ReplayWebhookDelivery.Handle: ", err)
+		common.HttpErrorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, common.SimpleSuccessResponse(delivery))
+}
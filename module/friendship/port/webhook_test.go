@@ -0,0 +1,252 @@
+package port
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	mockHandler "github.com/phantranhieunhan/s3-assignment/mock/friendship/handler"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command/payload"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestCase_RegisterWebhook struct {
+	name        string
+	hasFinalErr bool
+	bodyRequest RegisterWebhookReq
+
+	handlerError error
+
+	hasValidateErr bool
+}
+
+func TestRegisterWebhook(t *testing.T) {
+	t.Parallel()
+
+	valid := RegisterWebhookReq{
+		URL:        "https://partner.example.com/hook",
+		Secret:     "a-secret-of-16-chars",
+		EventTypes: []string{"FriendshipConnected", "UserSubscribed", "UserBlocked"},
+	}
+
+	tcs := []TestCase_RegisterWebhook{
+		{
+			name:        "successful",
+			bodyRequest: valid,
+		},
+		{
+			name:           "fail because url is not provided",
+			bodyRequest:    RegisterWebhookReq{Secret: valid.Secret, EventTypes: valid.EventTypes},
+			hasValidateErr: true,
+			hasFinalErr:    true,
+		},
+		{
+			name:           "fail because url is not http",
+			bodyRequest:    RegisterWebhookReq{URL: "ftp://partner.example.com/hook", Secret: valid.Secret, EventTypes: valid.EventTypes},
+			hasValidateErr: true,
+			hasFinalErr:    true,
+		},
+		{
+			name:           "fail because url is not absolute",
+			bodyRequest:    RegisterWebhookReq{URL: "/hook", Secret: valid.Secret, EventTypes: valid.EventTypes},
+			hasValidateErr: true,
+			hasFinalErr:    true,
+		},
+		{
+			name:           "fail because secret is too short",
+			bodyRequest:    RegisterWebhookReq{URL: valid.URL, Secret: "short", EventTypes: valid.EventTypes},
+			hasValidateErr: true,
+			hasFinalErr:    true,
+		},
+		{
+			name:           "fail because event types are not provided",
+			bodyRequest:    RegisterWebhookReq{URL: valid.URL, Secret: valid.Secret},
+			hasValidateErr: true,
+			hasFinalErr:    true,
+		},
+		{
+			name:           "fail because event type is not valid",
+			bodyRequest:    RegisterWebhookReq{URL: valid.URL, Secret: valid.Secret, EventTypes: []string{"UserTeleported"}},
+			hasValidateErr: true,
+			hasFinalErr:    true,
+		},
+		{
+			name:         "fail because command handle has error",
+			bodyRequest:  valid,
+			handlerError: errors.New("command handler error"),
+			hasFinalErr:  true,
+		},
+	}
+
+	for _, tc := range tcs {
+		mockRegisterWebhookHandler := new(mockHandler.MockRegisterWebhookHandler)
+		webhook := domain.Webhook{
+			Base:       domain.Base{Id: "webhook-id"},
+			URL:        tc.bodyRequest.URL,
+			Secret:     tc.bodyRequest.Secret,
+			EventTypes: []domain.EventType{domain.EventFriendshipConnected, domain.EventUserSubscribed, domain.EventUserBlocked},
+		}
+		if !tc.hasValidateErr {
+			mockRegisterWebhookHandler.On("Handle", mock.Anything, payload.RegisterWebhookPayload{
+				URL:        tc.bodyRequest.URL,
+				Secret:     tc.bodyRequest.Secret,
+				EventTypes: webhook.EventTypes,
+			}).Once().Return(webhook, tc.handlerError)
+		}
+
+		server := NewServer(app.Application{
+			Commands: app.Commands{
+				RegisterWebhook: mockRegisterWebhookHandler,
+			},
+		})
+		router := gin.Default()
+		router.POST("/admin/webhooks", server.RegisterWebhook)
+
+		jsonBody, err := json.Marshal(tc.bodyRequest)
+		assert.NoError(t, err)
+
+		req, err := http.NewRequest(http.MethodPost, "/admin/webhooks", bytes.NewBuffer(jsonBody))
+		assert.NoError(t, err)
+		res := httptest.NewRecorder()
+		router.ServeHTTP(res, req)
+
+		if tc.hasFinalErr {
+			assert.Equal(t, http.StatusBadRequest, res.Code, tc.name)
+		} else {
+			assert.Equal(t, http.StatusCreated, res.Code, tc.name)
+			// the secret is never sent back
+			assert.NotContains(t, res.Body.String(), tc.bodyRequest.Secret)
+			assert.Contains(t, res.Body.String(), `"id":"webhook-id"`)
+		}
+		mock.AssertExpectationsForObjects(t, mockRegisterWebhookHandler)
+	}
+}
+
+func serveWebhook(t *testing.T, method, route, path string, handler gin.HandlerFunc) *httptest.ResponseRecorder {
+	router := gin.Default()
+	router.Handle(method, route, handler)
+
+	req, err := http.NewRequest(method, path, nil)
+	assert.NoError(t, err)
+	res := httptest.NewRecorder()
+	router.ServeHTTP(res, req)
+	return res
+}
+
+type TestCase_Webhook struct {
+	name         string
+	handlerError error
+}
+
+func webhookTestCases() []TestCase_Webhook {
+	return []TestCase_Webhook{
+		{
+			name: "successful",
+		},
+		{
+			name:         "fail because handle has error",
+			handlerError: errors.New("handler error"),
+		},
+	}
+}
+
+func assertWebhookCode(t *testing.T, res *httptest.ResponseRecorder, tc TestCase_Webhook) {
+	if tc.handlerError != nil {
+		assert.Equal(t, http.StatusBadRequest, res.Code, tc.name)
+	} else {
+		assert.Equal(t, http.StatusOK, res.Code, tc.name)
+	}
+}
+
+func TestListWebhooks(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range webhookTestCases() {
+		mockListWebhooksHandler := new(mockHandler.MockListWebhooksHandler)
+		webhooks := []domain.Webhook{{Base: domain.Base{Id: "webhook-id"}, URL: "https://partner.example.com/hook", Secret: "a-secret-of-16-chars"}}
+		mockListWebhooksHandler.On("Handle", mock.Anything).Once().Return(webhooks, tc.handlerError)
+
+		server := NewServer(app.Application{
+			Queries: app.Queries{
+				ListWebhooks: mockListWebhooksHandler,
+			},
+		})
+		res := serveWebhook(t, http.MethodGet, "/admin/webhooks", "/admin/webhooks", server.ListWebhooks)
+		assertWebhookCode(t, res, tc)
+		if tc.handlerError == nil {
+			assert.Contains(t, res.Body.String(), `"count":1`)
+			assert.NotContains(t, res.Body.String(), "a-secret-of-16-chars")
+		}
+		mock.AssertExpectationsForObjects(t, mockListWebhooksHandler)
+	}
+}
+
+func TestDeleteWebhook(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range webhookTestCases() {
+		mockDeleteWebhookHandler := new(mockHandler.MockDeleteWebhookHandler)
+		mockDeleteWebhookHandler.On("Handle", mock.Anything, "webhook-id").Once().Return(tc.handlerError)
+
+		server := NewServer(app.Application{
+			Commands: app.Commands{
+				DeleteWebhook: mockDeleteWebhookHandler,
+			},
+		})
+		res := serveWebhook(t, http.MethodDelete, "/admin/webhooks/:id", "/admin/webhooks/webhook-id", server.DeleteWebhook)
+		assertWebhookCode(t, res, tc)
+		mock.AssertExpectationsForObjects(t, mockDeleteWebhookHandler)
+	}
+}
+
+func TestListDeadWebhookDeliveries(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range webhookTestCases() {
+		mockListDeadWebhookDeliveriesHandler := new(mockHandler.MockListDeadWebhookDeliveriesHandler)
+		deliveries := []domain.WebhookDelivery{{Base: domain.Base{Id: "delivery-id"}, WebhookID: "webhook-id", Status: domain.WebhookDeliveryStatusDead}}
+		mockListDeadWebhookDeliveriesHandler.On("Handle", mock.Anything, "webhook-id").Once().Return(deliveries, tc.handlerError)
+
+		server := NewServer(app.Application{
+			Queries: app.Queries{
+				ListDeadWebhookDeliveries: mockListDeadWebhookDeliveriesHandler,
+			},
+		})
+		res := serveWebhook(t, http.MethodGet, "/admin/webhooks/:id/dead-letters", "/admin/webhooks/webhook-id/dead-letters", server.ListDeadWebhookDeliveries)
+		assertWebhookCode(t, res, tc)
+		if tc.handlerError == nil {
+			assert.Contains(t, res.Body.String(), `"count":1`)
+		}
+		mock.AssertExpectationsForObjects(t, mockListDeadWebhookDeliveriesHandler)
+	}
+}
+
+func TestReplayWebhookDelivery(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range webhookTestCases() {
+		mockReplayWebhookDeliveryHandler := new(mockHandler.MockReplayWebhookDeliveryHandler)
+		delivery := domain.WebhookDelivery{Base: domain.Base{Id: "delivery-id"}, WebhookID: "webhook-id", Status: domain.WebhookDeliveryStatusPending}
+		mockReplayWebhookDeliveryHandler.On("Handle", mock.Anything, payload.ReplayWebhookDeliveryPayload{
+			WebhookID:  "webhook-id",
+			DeliveryID: "delivery-id",
+		}).Once().Return(delivery, tc.handlerError)
+
+		server := NewServer(app.Application{
+			Commands: app.Commands{
+				ReplayWebhookDelivery: mockReplayWebhookDeliveryHandler,
+			},
+		})
+		res := serveWebhook(t, http.MethodPost, "/admin/webhooks/:id/dead-letters/:delivery_id/replay",
+			"/admin/webhooks/webhook-id/dead-letters/delivery-id/replay", server.ReplayWebhookDelivery)
+		assertWebhookCode(t, res, tc)
+		mock.AssertExpectationsForObjects(t, mockReplayWebhookDeliveryHandler)
+	}
+}
@@ -1,32 +1,119 @@
 package friendship
 
 import (
+	"context"
+
 	"github.com/gin-gonic/gin"
+	grpclib "google.golang.org/grpc"
 
-	"github.com/phantranhieunhan/s3-assignment/common/adapter/postgres"
-	"github.com/phantranhieunhan/s3-assignment/module/friendship/adapter/postgres/repository"
+	"github.com/phantranhieunhan/s3-assignment/common/auth"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/adapter/publisher"
+	webhookadapter "github.com/phantranhieunhan/s3-assignment/module/friendship/adapter/webhook"
 	"github.com/phantranhieunhan/s3-assignment/module/friendship/app"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/account"
 	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/idempotency"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/outbox"
 	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/query"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/webhook"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
 	"github.com/phantranhieunhan/s3-assignment/module/friendship/port"
+	graphqlport "github.com/phantranhieunhan/s3-assignment/module/friendship/port/graphql"
+	grpcport "github.com/phantranhieunhan/s3-assignment/module/friendship/port/grpc"
+	"github.com/phantranhieunhan/s3-assignment/pkg/config"
 )
 
-func New(r *gin.Engine, db postgres.Database) {
-	friendshipRepo := repository.NewFriendshipRepository(db)
-	userRepo := repository.NewUserRepository(db)
-	subRepo := repository.NewSubscriptionRepository(db)
+// New routes the http and graphql apis on the engine, starts the outbox relay, the webhook worker
+// and the account purger, and returns the grpc server of the same application, ready to serve.
+// It fails before routing anything when the config holds a value the application does not know
+func New(r *gin.Engine, storage Storage) (*grpclib.Server, error) {
+	unfriendSubscriptionPolicy, err := domain.ParseUnfriendSubscriptionPolicy(config.C.Friendship.UnfriendSubscriptionPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	friendshipRepo := storage.FriendshipRepo
+	userRepo := storage.UserRepo
+	subRepo := storage.SubscriptionRepo
+	blockRepo := storage.BlockRepo
+	updateRepo := storage.UpdateRepo
+	eventRepo := storage.EventRepo
+	webhookRepo := storage.WebhookRepo
+	auditRepo := storage.AuditRepo
+	transactor := storage.Transactor
+	tokenIssuer := auth.NewTokenIssuer(config.C.Auth.Secret, config.C.Auth.TokenTTL)
 
 	application := app.Application{
 		Commands: app.Commands{
-			ConnectFriendship: command.NewConnectFriendshipHandler(friendshipRepo, userRepo, db),
-			SubscribeUser:     command.NewSubscribeUserHandler(friendshipRepo, userRepo, subRepo, db),
-			BlockUpdatesUser:  command.NewBlockUpdatesUserHandler(friendshipRepo, userRepo, subRepo, db),
+			SubscribeUser:         command.NewSubscribeUserHandler(friendshipRepo, userRepo, subRepo, eventRepo, auditRepo, transactor),
+			BlockUpdatesUser:      command.NewBlockUpdatesUserHandler(friendshipRepo, userRepo, subRepo, blockRepo, eventRepo, auditRepo, transactor),
+			RequestFriendship:     command.NewRequestFriendshipHandler(friendshipRepo, userRepo, eventRepo, auditRepo, transactor),
+			AcceptFriendship:      command.NewAcceptFriendshipHandler(friendshipRepo, userRepo, subRepo, eventRepo, auditRepo, transactor),
+			RejectFriendship:      command.NewRejectFriendshipHandler(friendshipRepo, userRepo, eventRepo, auditRepo, transactor),
+			CancelFriendship:      command.NewCancelFriendshipHandler(friendshipRepo, userRepo, eventRepo, auditRepo, transactor),
+			Unfriend:              command.NewUnfriendHandler(friendshipRepo, userRepo, subRepo, eventRepo, auditRepo, transactor, unfriendSubscriptionPolicy),
+			UnblockUser:           command.NewUnblockUserHandler(friendshipRepo, userRepo, subRepo, blockRepo, eventRepo, auditRepo, transactor),
+			CreateUser:            command.NewCreateUserHandler(userRepo, eventRepo, auditRepo, transactor),
+			UpdateUser:            command.NewUpdateUserHandler(userRepo, eventRepo, auditRepo, transactor),
+			DeleteAccount:         command.NewDeleteAccountHandler(userRepo, friendshipRepo, subRepo, blockRepo, updateRepo, eventRepo, auditRepo, transactor, config.C.Account.DeletionGracePeriod),
+			CancelAccountDeletion: command.NewCancelAccountDeletionHandler(userRepo, eventRepo, auditRepo, transactor),
+			SetPassword:           command.NewSetPasswordHandler(userRepo, auditRepo, transactor),
+			Login:                 command.NewLoginHandler(userRepo, auditRepo, tokenIssuer),
+			PostUpdate:            command.NewPostUpdateHandler(updateRepo, userRepo, eventRepo, auditRepo, transactor),
+			RegisterWebhook:       command.NewRegisterWebhookHandler(webhookRepo, auditRepo, transactor),
+			DeleteWebhook:         command.NewDeleteWebhookHandler(webhookRepo, auditRepo, transactor),
+			ReplayWebhookDelivery: command.NewReplayWebhookDeliveryHandler(webhookRepo, auditRepo, transactor),
+			ImportRelationships:   command.NewImportRelationshipsHandler(friendshipRepo, userRepo, subRepo, eventRepo, auditRepo, transactor),
 		},
 		Queries: app.Queries{
-			ListFriends:       query.NewListFriendsHandler(friendshipRepo, userRepo),
-			ListCommonFriends: query.NewListCommonFriendsHandler(friendshipRepo, userRepo),
-			ListUpdatesUser:   query.NewListUpdatesUserHandler(subRepo, userRepo),
+			ListFriends:               query.NewListFriendsHandler(friendshipRepo, userRepo),
+			ListCommonFriends:         query.NewListCommonFriendsHandler(friendshipRepo, userRepo),
+			ListUpdatesUser:           query.NewListUpdatesUserHandler(subRepo, userRepo),
+			ListSubscribers:           query.NewListSubscribersHandler(subRepo, userRepo),
+			ListFriendRequests:        query.NewListFriendRequestsHandler(friendshipRepo),
+			ListBlockedUsers:          query.NewListBlockedUsersHandler(blockRepo),
+			ListBlockers:              query.NewListBlockersHandler(blockRepo, userRepo),
+			GetUser:                   query.NewGetUserHandler(userRepo),
+			ExportUser:                query.NewExportUserHandler(userRepo, friendshipRepo, subRepo, blockRepo, updateRepo, auditRepo),
+			ListUserEmails:            query.NewListUserEmailsHandler(userRepo),
+			ListUserIDs:               query.NewListUserIDsHandler(userRepo),
+			GetFriendship:             query.NewGetFriendshipHandler(friendshipRepo, userRepo),
+			GetSubscription:           query.NewGetSubscriptionHandler(subRepo, userRepo),
+			ListFriendSuggestions:     query.NewListFriendSuggestionsHandler(friendshipRepo, userRepo),
+			FindFriendshipPath:        query.NewFindFriendshipPathHandler(friendshipRepo, userRepo),
+			ListFriendSet:             query.NewListFriendSetHandler(friendshipRepo, userRepo),
+			GetFeed:                   query.NewGetFeedHandler(updateRepo),
+			ListWebhooks:              query.NewListWebhooksHandler(webhookRepo),
+			ListDeadWebhookDeliveries: query.NewListDeadWebhookDeliveriesHandler(webhookRepo),
+			ListAuditLog:              query.NewListAuditLogHandler(auditRepo, userRepo),
 		},
 	}
+	// the POST requests of the http and the graphql api replay the response of their Idempotency-Key
+	r.Use(port.Idempotency(storage.IdempotencyRepo, config.C.Idempotency.TTL, config.C.Idempotency.Lease))
 	port.NewServer(application).Router(r)
+	graphqlport.NewServer(application).Router(r)
+
+	// the relay hands every event to the log and queues a delivery for the webhooks registered to its type
+	eventPublisher := publisher.NewMultiPublisher(publisher.NewLogPublisher(), webhook.NewDispatcher(webhookRepo))
+	relay := outbox.NewRelay(eventRepo, eventPublisher, transactor, config.C.Outbox.PollInterval, config.C.Outbox.BatchSize)
+	go relay.Run(context.Background())
+
+	worker := webhook.NewWorker(webhookRepo, webhookadapter.NewHTTPSender(config.C.Webhook.Timeout), transactor, webhook.WorkerConfig{
+		PollInterval: config.C.Webhook.PollInterval,
+		BatchSize:    config.C.Webhook.BatchSize,
+		MaxAttempts:  config.C.Webhook.MaxAttempts,
+		BackoffBase:  config.C.Webhook.BackoffBase,
+		BackoffMax:   config.C.Webhook.BackoffMax,
+		Lease:        config.C.Webhook.Lease,
+	})
+	go worker.Run(context.Background())
+
+	purger := account.NewPurger(userRepo, command.NewPurgeAccountHandler(userRepo, friendshipRepo, subRepo, blockRepo, updateRepo, eventRepo, auditRepo, transactor),
+		config.C.Account.PurgeInterval, config.C.Account.PurgeBatchSize)
+	go purger.Run(context.Background())
+
+	cleaner := idempotency.NewCleaner(storage.IdempotencyRepo, config.C.Idempotency.TTL, config.C.Idempotency.CleanupInterval)
+	go cleaner.Run(context.Background())
+
+	return grpcport.NewGRPCServer(application, tokenIssuer, config.C.Admin.Token), nil
 }
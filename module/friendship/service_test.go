@@ -0,0 +1,586 @@
+package friendship
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/phantranhieunhan/s3-assignment/middleware"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/adapter/memory"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/phantranhieunhan/s3-assignment/pkg/cache"
+	"github.com/phantranhieunhan/s3-assignment/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// newMemoryServer serves the whole module on the memory storage behind the lru cache
+func newMemoryServer(t *testing.T) *gin.Engine {
+	config.C.Auth.Secret = "secret"
+	config.C.Auth.TokenTTL = time.Hour
+	config.C.Outbox.PollInterval = time.Hour
+	config.C.Outbox.BatchSize = 100
+	config.C.Webhook.PollInterval = time.Hour
+	config.C.Webhook.BatchSize = 20
+	config.C.Account.DeletionGracePeriod = time.Hour
+	config.C.Account.PurgeInterval = time.Hour
+	config.C.Account.PurgeBatchSize = 50
+	config.C.Idempotency.TTL = time.Hour
+	config.C.Idempotency.Lease = time.Minute
+	config.C.Idempotency.CleanupInterval = time.Hour
+
+	r := gin.New()
+	r.Use(middleware.RequestID)
+	_, err := New(r, NewCachedStorage(NewMemoryStorage(memory.NewStore()), cache.NewLRU(100), time.Minute))
+	assert.NoError(t, err)
+	return r
+}
+
+func serve(t *testing.T, r *gin.Engine, method, path, token string, body interface{}) (int, map[string]interface{}) {
+	jsonBody, err := json.Marshal(body)
+	assert.NoError(t, err)
+
+	req, err := http.NewRequest(method, path, bytes.NewBuffer(jsonBody))
+	assert.NoError(t, err)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+
+	resBody := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &resBody))
+	return res.Code, resBody
+}
+
+// login returns the token of the user signed up with the password "password"
+func login(t *testing.T, r *gin.Engine, email string) string {
+	code, res := serve(t, r, http.MethodPost, "/auth/login", "", map[string]string{"email": email, "password": "password"})
+	assert.Equal(t, http.StatusOK, code)
+	token, _ := res["token"].(string)
+	assert.NotEmpty(t, token)
+	return token
+}
+
+// befriend sends the friend request of the user with the token to the friend, who logs in to accept it
+func befriend(t *testing.T, r *gin.Engine, token, email, friendEmail string) {
+	code, _ := serve(t, r, http.MethodPost, "/friendship/connect", token, map[string][]string{"friends": {email, friendEmail}})
+	assert.Equal(t, http.StatusOK, code)
+	code, _ = serve(t, r, http.MethodPost, "/friendship/accept", login(t, r, friendEmail), map[string]string{"requestor": friendEmail, "target": email})
+	assert.Equal(t, http.StatusOK, code)
+}
+
+func TestService_MemoryStorage(t *testing.T) {
+	r := newMemoryServer(t)
+
+	for _, email := range []string{"andy@example.com", "john@example.com"} {
+		code, _ := serve(t, r, http.MethodPost, "/users", "", map[string]string{"email": email, "password": "password"})
+		assert.Equal(t, http.StatusCreated, code)
+	}
+	code, _ := serve(t, r, http.MethodPost, "/users", "", map[string]string{"email": "andy@example.com", "password": "password"})
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, res := serve(t, r, http.MethodPost, "/auth/login", "", map[string]string{"email": "andy@example.com", "password": "password"})
+	assert.Equal(t, http.StatusOK, code)
+	token, _ := res["token"].(string)
+	assert.NotEmpty(t, token)
+
+	// the connection waits for john to accept it
+	friends := map[string][]string{"friends": {"andy@example.com", "john@example.com"}}
+	code, _ = serve(t, r, http.MethodPost, "/friendship/connect", token, friends)
+	assert.Equal(t, http.StatusOK, code)
+	code, _ = serve(t, r, http.MethodPost, "/friendship/connect", token, friends)
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, res = serve(t, r, http.MethodGet, "/friendship/friends", "", map[string]string{"email": "john@example.com"})
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []interface{}{}, res["friends"])
+	code, _ = serve(t, r, http.MethodPost, "/friendship/accept", token, map[string]string{"requestor": "andy@example.com", "target": "john@example.com"})
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, _ = serve(t, r, http.MethodPost, "/friendship/accept", login(t, r, "john@example.com"), map[string]string{"requestor": "john@example.com", "target": "andy@example.com"})
+	assert.Equal(t, http.StatusOK, code)
+	code, res = serve(t, r, http.MethodGet, "/friendship/friends", "", map[string]string{"email": "john@example.com"})
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []interface{}{"andy@example.com"}, res["friends"])
+
+	// the cached friend list is dropped once the unfriend commits
+	code, _ = serve(t, r, http.MethodPost, "/friendship/unfriend", token, map[string]string{"requestor": "andy@example.com", "target": "john@example.com"})
+	assert.Equal(t, http.StatusOK, code)
+	code, res = serve(t, r, http.MethodGet, "/friendship/friends", "", map[string]string{"email": "john@example.com"})
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []interface{}{}, res["friends"])
+}
+
+// a friend request pending when the requestor blocks the target can't be accepted until the block is lifted
+func TestService_BlockPendingFriendRequest(t *testing.T) {
+	r := newMemoryServer(t)
+
+	for _, email := range []string{"andy@example.com", "john@example.com"} {
+		code, _ := serve(t, r, http.MethodPost, "/users", "", map[string]string{"email": email, "password": "password"})
+		assert.Equal(t, http.StatusCreated, code)
+	}
+	andy := login(t, r, "andy@example.com")
+	john := login(t, r, "john@example.com")
+	accept := map[string]string{"requestor": "john@example.com", "target": "andy@example.com"}
+	block := map[string]string{"requestor": "andy@example.com", "target": "john@example.com"}
+
+	code, _ := serve(t, r, http.MethodPost, "/friendship/connect", andy, map[string][]string{"friends": {"andy@example.com", "john@example.com"}})
+	assert.Equal(t, http.StatusOK, code)
+	code, _ = serve(t, r, http.MethodPost, "/subscription/block", andy, block)
+	assert.Equal(t, http.StatusOK, code)
+	code, _ = serve(t, r, http.MethodPost, "/friendship/accept", john, accept)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	// the request is pending again once the block is lifted
+	code, _ = serve(t, r, http.MethodPost, "/subscription/unblock", andy, block)
+	assert.Equal(t, http.StatusOK, code)
+	code, _ = serve(t, r, http.MethodPost, "/friendship/accept", john, accept)
+	assert.Equal(t, http.StatusOK, code)
+}
+
+func TestService_Import(t *testing.T) {
+	config.C.Admin.Token = "admin-token"
+	r := newMemoryServer(t)
+
+	for _, email := range []string{"andy@example.com", "john@example.com"} {
+		code, _ := serve(t, r, http.MethodPost, "/users", "", map[string]string{"email": email, "password": "password"})
+		assert.Equal(t, http.StatusCreated, code)
+	}
+
+	body := "type,user,target\n" +
+		"user,kate@example.com,\n" +
+		"friendship,andy@example.com,john@example.com\n" +
+		"subscription,kate@example.com,andy@example.com\n" +
+		"friendship,andy@example.com,nobody@example.com\n" +
+		"subscription,kate@example.com,andy@example.com\n" +
+		"user,john@example.com,\n"
+	req, err := http.NewRequest(http.MethodPost, "/admin/import?format=csv", bytes.NewBufferString(body))
+	assert.NoError(t, err)
+	req.Header.Set("Content-Type", "text/csv")
+	req.Header.Set("X-Admin-Token", "admin-token")
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusOK, res.Code, res.Body.String())
+
+	var imported struct {
+		Data struct {
+			Imported   int  `json:"imported"`
+			Failed     int  `json:"failed"`
+			Completed  bool `json:"completed"`
+			NextOffset int  `json:"next_offset"`
+			Failures   []struct {
+				Row int `json:"row"`
+			} `json:"failures"`
+		} `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &imported))
+	// the unknown user, the second subscription and the existing user fail, the other rows are written
+	assert.Equal(t, 3, imported.Data.Imported)
+	assert.Equal(t, 3, imported.Data.Failed)
+	assert.True(t, imported.Data.Completed)
+	assert.Equal(t, 6, imported.Data.NextOffset)
+	if assert.Len(t, imported.Data.Failures, 3) {
+		assert.Equal(t, []int{3, 4, 5}, []int{imported.Data.Failures[0].Row, imported.Data.Failures[1].Row, imported.Data.Failures[2].Row})
+	}
+
+	// the imported user has no password until the admin sets one
+	code, _ := serve(t, r, http.MethodPost, "/auth/login", "", map[string]string{"email": "kate@example.com", "password": "password"})
+	assert.Equal(t, http.StatusUnauthorized, code)
+
+	code, resBody := serve(t, r, http.MethodGet, "/friendship/friends", "", map[string]string{"email": "john@example.com"})
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []interface{}{"andy@example.com"}, resBody["friends"])
+	code, resBody = serve(t, r, http.MethodGet, "/subscription/subscribers?email=andy@example.com", "", nil)
+	assert.Equal(t, http.StatusOK, code)
+	assert.ElementsMatch(t, []interface{}{"john@example.com", "kate@example.com"}, resBody["subscribers"])
+}
+
+func TestService_Export(t *testing.T) {
+	r := newMemoryServer(t)
+
+	for _, email := range []string{"andy@example.com", "john@example.com", "kate@example.com"} {
+		code, _ := serve(t, r, http.MethodPost, "/users", "", map[string]string{"email": email, "password": "password"})
+		assert.Equal(t, http.StatusCreated, code)
+	}
+	code, res := serve(t, r, http.MethodPost, "/auth/login", "", map[string]string{"email": "andy@example.com", "password": "password"})
+	assert.Equal(t, http.StatusOK, code)
+	token, _ := res["token"].(string)
+
+	befriend(t, r, token, "andy@example.com", "john@example.com")
+	code, _ = serve(t, r, http.MethodPost, "/subscription/block", token, map[string]string{"requestor": "andy@example.com", "target": "kate@example.com"})
+	assert.Equal(t, http.StatusOK, code)
+	code, _ = serve(t, r, http.MethodPost, "/updates", token, map[string]string{"sender": "andy@example.com", "text": "hello john@example.com"})
+	assert.Equal(t, http.StatusCreated, code)
+
+	req, err := http.NewRequest(http.MethodGet, "/users/andy@example.com/export", nil)
+	assert.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, `attachment; filename="export-andy@example.com.json"`, rec.Header().Get("Content-Disposition"))
+
+	var export struct {
+		User struct {
+			Email string `json:"email"`
+		} `json:"user"`
+		Friendships []struct {
+			Friend string `json:"friend"`
+			Status string `json:"status"`
+		} `json:"friendships"`
+		Subscriptions []struct {
+			User       string `json:"user"`
+			Subscriber string `json:"subscriber"`
+		} `json:"subscriptions"`
+		Blocks []struct {
+			Target string `json:"target"`
+		} `json:"blocks"`
+		Updates []struct {
+			Text     string   `json:"text"`
+			Mentions []string `json:"mentions"`
+		} `json:"updates"`
+		AuditLog []struct {
+			Action string `json:"action"`
+		} `json:"audit_log"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &export))
+	assert.Equal(t, "andy@example.com", export.User.Email)
+	// the block keeps a friendship and a subscription of its own
+	friendships := make([]string, 0)
+	for _, f := range export.Friendships {
+		friendships = append(friendships, f.Friend+" "+f.Status)
+	}
+	assert.ElementsMatch(t, []string{"john@example.com FRIENDED", "kate@example.com BLOCKED"}, friendships)
+	assert.Len(t, export.Subscriptions, 3)
+	if assert.Len(t, export.Blocks, 1) {
+		assert.Equal(t, "kate@example.com", export.Blocks[0].Target)
+	}
+	if assert.Len(t, export.Updates, 1) {
+		assert.Equal(t, "hello john@example.com", export.Updates[0].Text)
+		assert.Equal(t, []string{"john@example.com"}, export.Updates[0].Mentions)
+	}
+	// the audit log of the user, the newest first, from the signup to the update
+	if assert.NotEmpty(t, export.AuditLog) {
+		assert.Equal(t, string(domain.AuditPostUpdate), export.AuditLog[0].Action)
+		assert.Equal(t, string(domain.AuditCreateUser), export.AuditLog[len(export.AuditLog)-1].Action)
+	}
+
+	// only the user itself may export its data
+	code, _ = serve(t, r, http.MethodGet, "/users/john@example.com/export", token, nil)
+	assert.Equal(t, http.StatusForbidden, code)
+}
+
+func TestService_DeleteAccount(t *testing.T) {
+	r := newMemoryServer(t)
+
+	for _, email := range []string{"andy@example.com", "john@example.com"} {
+		code, _ := serve(t, r, http.MethodPost, "/users", "", map[string]string{"email": email, "password": "password"})
+		assert.Equal(t, http.StatusCreated, code)
+	}
+	code, res := serve(t, r, http.MethodPost, "/auth/login", "", map[string]string{"email": "andy@example.com", "password": "password"})
+	assert.Equal(t, http.StatusOK, code)
+	token, _ := res["token"].(string)
+	befriend(t, r, token, "andy@example.com", "john@example.com")
+
+	// the account deletion takes the friends along once the grace period is over
+	code, res = serve(t, r, http.MethodDelete, "/users/andy@example.com/account", token, nil)
+	assert.Equal(t, http.StatusOK, code)
+	deletion, _ := res["data"].(map[string]interface{})
+	assert.Equal(t, false, deletion["purged"])
+
+	// deleting again keeps the first schedule, the plain delete is the same deletion
+	code, res = serve(t, r, http.MethodDelete, "/users/andy@example.com", token, nil)
+	assert.Equal(t, http.StatusOK, code)
+	again, _ := res["data"].(map[string]interface{})
+	assert.Equal(t, deletion["purge_at"], again["purge_at"])
+
+	// the account can't log in, act nor be acted on during the grace period
+	code, _ = serve(t, r, http.MethodPost, "/auth/login", "", map[string]string{"email": "andy@example.com", "password": "password"})
+	assert.Equal(t, http.StatusForbidden, code)
+	johnToken := login(t, r, "john@example.com")
+	code, _ = serve(t, r, http.MethodPost, "/friendship/unfriend", johnToken, map[string]string{"requestor": "john@example.com", "target": "andy@example.com"})
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = serve(t, r, http.MethodPost, "/subscription/subscribe", johnToken, map[string]string{"requestor": "john@example.com", "target": "andy@example.com"})
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = serve(t, r, http.MethodPost, "/updates", token, map[string]string{"sender": "andy@example.com", "text": "hello"})
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	// the password of the account restores it, the account logs in again
+	code, _ = serve(t, r, http.MethodPost, "/users/andy@example.com/account/restore", "", map[string]string{"password": "wrong password"})
+	assert.Equal(t, http.StatusUnauthorized, code)
+	code, _ = serve(t, r, http.MethodPost, "/users/andy@example.com/account/restore", "", map[string]string{"password": "password"})
+	assert.Equal(t, http.StatusOK, code)
+	code, _ = serve(t, r, http.MethodPost, "/users/andy@example.com/account/restore", "", map[string]string{"password": "password"})
+	assert.Equal(t, http.StatusBadRequest, code)
+	login(t, r, "andy@example.com")
+	code, _ = serve(t, r, http.MethodPost, "/friendship/unfriend", johnToken, map[string]string{"requestor": "john@example.com", "target": "andy@example.com"})
+	assert.Equal(t, http.StatusOK, code)
+
+	// without a grace period the account is purged right away
+	config.C.Account.DeletionGracePeriod = 0
+	defer func() { config.C.Account.DeletionGracePeriod = time.Hour }()
+	r = gin.New()
+	_, err := New(r, NewMemoryStorage(memory.NewStore()))
+	assert.NoError(t, err)
+	for _, email := range []string{"andy@example.com", "john@example.com"} {
+		code, _ := serve(t, r, http.MethodPost, "/users", "", map[string]string{"email": email, "password": "password"})
+		assert.Equal(t, http.StatusCreated, code)
+	}
+	code, res = serve(t, r, http.MethodPost, "/auth/login", "", map[string]string{"email": "andy@example.com", "password": "password"})
+	assert.Equal(t, http.StatusOK, code)
+	token, _ = res["token"].(string)
+	befriend(t, r, token, "andy@example.com", "john@example.com")
+	code, _ = serve(t, r, http.MethodPost, "/updates", login(t, r, "john@example.com"), map[string]string{"sender": "john@example.com", "text": "hello andy@example.com"})
+	assert.Equal(t, http.StatusCreated, code)
+
+	code, res = serve(t, r, http.MethodGet, "/users/andy@example.com", "", nil)
+	assert.Equal(t, http.StatusOK, code)
+	andy, _ := res["data"].(map[string]interface{})
+	andyID, _ := andy["id"].(string)
+
+	code, res = serve(t, r, http.MethodDelete, "/users/andy@example.com/account", token, nil)
+	assert.Equal(t, http.StatusOK, code)
+	deletion, _ = res["data"].(map[string]interface{})
+	assert.Equal(t, true, deletion["purged"])
+
+	code, _ = serve(t, r, http.MethodGet, "/users/andy@example.com", "", nil)
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, res = serve(t, r, http.MethodGet, "/friendship/friends", "", map[string]string{"email": "john@example.com"})
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []interface{}{}, res["friends"])
+	code, _ = serve(t, r, http.MethodPost, "/auth/login", "", map[string]string{"email": "andy@example.com", "password": "password"})
+	assert.NotEqual(t, http.StatusOK, code)
+	// the token issued before the purge can't act as the tombstone
+	tombstone := "deleted-" + andyID + "@deleted.invalid"
+	code, _ = serve(t, r, http.MethodPost, "/updates", token, map[string]string{"sender": tombstone, "text": "hello"})
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = serve(t, r, http.MethodPost, "/subscription/subscribe", token, map[string]string{"requestor": tombstone, "target": "john@example.com"})
+	assert.Equal(t, http.StatusBadRequest, code)
+	// the email can be used again, the mention of the purged account does not reach the new one
+	code, _ = serve(t, r, http.MethodPost, "/users", "", map[string]string{"email": "andy@example.com", "password": "password"})
+	assert.Equal(t, http.StatusCreated, code)
+	code, res = serve(t, r, http.MethodGet, "/feed", login(t, r, "andy@example.com"), nil)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, float64(0), res["count"])
+}
+
+func TestNewStorage(t *testing.T) {
+	storage, err := NewStorage(StorageDriverMemory)
+	assert.NoError(t, err)
+	assert.NotNil(t, storage.Transactor)
+
+	config.C.Storage.SQLitePath = filepath.Join(t.TempDir(), "friendship.db")
+	storage, err = NewStorage(StorageDriverSQLite)
+	assert.NoError(t, err)
+	_, err = storage.UserRepo.Create(context.Background(), domain.User{Email: "john@example.com"})
+	assert.NoError(t, err)
+
+	_, err = NewStorage("mysql")
+	assert.Equal(t, ErrStorageDriverIsNotValid, err)
+}
+
+func TestNewRejectsUnknownUnfriendSubscriptionPolicy(t *testing.T) {
+	config.C.Friendship.UnfriendSubscriptionPolicy = "archive"
+	defer func() { config.C.Friendship.UnfriendSubscriptionPolicy = "" }()
+
+	_, err := New(gin.New(), NewMemoryStorage(memory.NewStore()))
+	assert.Equal(t, domain.ErrUnfriendSubscriptionPolicyIsNotValid, err)
+}
+
+func TestWithCache(t *testing.T) {
+	storage := NewMemoryStorage(memory.NewStore())
+
+	cached, err := WithCache(storage, CacheDriverNone, 0, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, storage, cached)
+
+	cached, err = WithCache(storage, CacheDriverLRU, 100, time.Minute)
+	assert.NoError(t, err)
+	assert.NotEqual(t, storage.UserRepo, cached.UserRepo)
+
+	_, err = WithCache(storage, "redis", 100, time.Minute)
+	assert.Equal(t, ErrCacheDriverIsNotValid, err)
+}
+
+func TestService_AuditLog(t *testing.T) {
+	config.C.Admin.Token = "admin-token"
+	r := newMemoryServer(t)
+
+	for _, email := range []string{"andy@example.com", "john@example.com"} {
+		code, _ := serve(t, r, http.MethodPost, "/users", "", map[string]string{"email": email, "password": "password"})
+		assert.Equal(t, http.StatusCreated, code)
+	}
+	code, res := serve(t, r, http.MethodPost, "/auth/login", "", map[string]string{"email": "andy@example.com", "password": "password"})
+	assert.Equal(t, http.StatusOK, code)
+	token, _ := res["token"].(string)
+
+	jsonBody, err := json.Marshal(map[string][]string{"friends": {"andy@example.com", "john@example.com"}})
+	assert.NoError(t, err)
+	req, err := http.NewRequest(http.MethodPost, "/friendship/connect", bytes.NewBuffer(jsonBody))
+	assert.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("X-Request-ID", "connect-request")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "connect-request", rec.Header().Get("X-Request-ID"))
+	code, _ = serve(t, r, http.MethodPost, "/friendship/accept", login(t, r, "john@example.com"), map[string]string{"requestor": "john@example.com", "target": "andy@example.com"})
+	assert.Equal(t, http.StatusOK, code)
+
+	// the audit log is for the admin only
+	code, _ = serve(t, r, http.MethodGet, "/admin/audit", token, nil)
+	assert.Equal(t, http.StatusUnauthorized, code)
+
+	listAudit := func(query string) []map[string]interface{} {
+		req, err := http.NewRequest(http.MethodGet, "/admin/audit?"+query, nil)
+		assert.NoError(t, err)
+		req.Header.Set("X-Admin-Token", "admin-token")
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		var body struct {
+			Entries []map[string]interface{} `json:"entries"`
+		}
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		return body.Entries
+	}
+
+	// two signups, a login, the friend request and, after the login of john, its acceptance subscribing the friends
+	// to each other, the newest first
+	entries := listAudit("")
+	actions := make([]interface{}, 0, len(entries))
+	for _, e := range entries {
+		actions = append(actions, e["action"])
+	}
+	assert.Equal(t, []interface{}{"SubscribeUser", "SubscribeUser", "AcceptFriendship", "Login", "RequestFriendship", "Login", "CreateUser", "CreateUser"}, actions)
+
+	entries = listAudit("user=andy@example.com&action=RequestFriendship")
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, "connect-request", entries[0]["request_id"])
+		assert.Equal(t, "", entries[0]["status_before"])
+		assert.Equal(t, "pending", entries[0]["status_after"])
+		assert.Len(t, entries[0]["target_ids"], 2)
+	}
+	assert.Len(t, listAudit("user=john@example.com"), 6)
+	assert.Len(t, listAudit("from="+time.Now().Add(time.Hour).UTC().Format(time.RFC3339)), 0)
+}
+
+func TestService_IdempotencyKey(t *testing.T) {
+	r := newMemoryServer(t)
+
+	for _, email := range []string{"andy@example.com", "john@example.com", "kate@example.com"} {
+		code, _ := serve(t, r, http.MethodPost, "/users", "", map[string]string{"email": email, "password": "password"})
+		assert.Equal(t, http.StatusCreated, code)
+	}
+	code, res := serve(t, r, http.MethodPost, "/auth/login", "", map[string]string{"email": "andy@example.com", "password": "password"})
+	assert.Equal(t, http.StatusOK, code)
+	token, _ := res["token"].(string)
+
+	send := func(path, key string, body interface{}) *httptest.ResponseRecorder {
+		jsonBody, err := json.Marshal(body)
+		assert.NoError(t, err)
+		req, err := http.NewRequest(http.MethodPost, path, bytes.NewBuffer(jsonBody))
+		assert.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Idempotency-Key", key)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// the retry of a connection gets the response of the first call instead of an error
+	connect := map[string][]string{"friends": {"andy@example.com", "john@example.com"}}
+	first := send("/friendship/connect", "connect-1", connect)
+	assert.Equal(t, http.StatusOK, first.Code, first.Body.String())
+	retry := send("/friendship/connect", "connect-1", connect)
+	assert.Equal(t, http.StatusOK, retry.Code, retry.Body.String())
+	assert.Equal(t, first.Body.String(), retry.Body.String())
+	assert.Equal(t, "true", retry.Header().Get("Idempotent-Replayed"))
+
+	// without the key the request runs again
+	code, _ = serve(t, r, http.MethodPost, "/friendship/connect", token, connect)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	// the key cannot be used for another request
+	other := send("/friendship/connect", "connect-1", map[string][]string{"friends": {"andy@example.com", "kate@example.com"}})
+	assert.Equal(t, http.StatusBadRequest, other.Code)
+	other = send("/subscription/subscribe", "connect-1", connect)
+	assert.Equal(t, http.StatusBadRequest, other.Code)
+
+	subscribe := map[string]string{"requestor": "andy@example.com", "target": "kate@example.com"}
+	first = send("/subscription/subscribe", "subscribe-1", subscribe)
+	assert.Equal(t, http.StatusOK, first.Code, first.Body.String())
+	retry = send("/subscription/subscribe", "subscribe-1", subscribe)
+	assert.Equal(t, http.StatusOK, retry.Code, retry.Body.String())
+	assert.Equal(t, "true", retry.Header().Get("Idempotent-Replayed"))
+
+	code, res = serve(t, r, http.MethodGet, "/subscription/subscribers?email=kate@example.com", "", nil)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []interface{}{"andy@example.com"}, res["subscribers"])
+
+	// the token of a login is never kept, the retry logs in again
+	login := map[string]string{"email": "andy@example.com", "password": "password"}
+	first = send("/auth/login", "login-1", login)
+	assert.Equal(t, http.StatusOK, first.Code, first.Body.String())
+	assert.Equal(t, "no-store", first.Header().Get("Cache-Control"))
+	retry = send("/auth/login", "login-1", login)
+	assert.Equal(t, http.StatusOK, retry.Code, retry.Body.String())
+	assert.Empty(t, retry.Header().Get("Idempotent-Replayed"))
+}
+
+func TestService_SetPassword(t *testing.T) {
+	config.C.Admin.Token = "admin-token"
+	r := newMemoryServer(t)
+
+	code, _ := serve(t, r, http.MethodPost, "/users", "", map[string]string{"email": "andy@example.com", "password": "password"})
+	assert.Equal(t, http.StatusCreated, code)
+
+	setPassword := func(token string) int {
+		jsonBody, err := json.Marshal(map[string]string{"password": "new password"})
+		assert.NoError(t, err)
+		req, err := http.NewRequest(http.MethodPost, "/admin/users/andy@example.com/password", bytes.NewBuffer(jsonBody))
+		assert.NoError(t, err)
+		req.Header.Set("X-Admin-Token", token)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	assert.Equal(t, http.StatusUnauthorized, setPassword("wrong-token"))
+	assert.Equal(t, http.StatusOK, setPassword("admin-token"))
+
+	code, _ = serve(t, r, http.MethodPost, "/auth/login", "", map[string]string{"email": "andy@example.com", "password": "password"})
+	assert.Equal(t, http.StatusUnauthorized, code)
+	code, _ = serve(t, r, http.MethodPost, "/auth/login", "", map[string]string{"email": "andy@example.com", "password": "new password"})
+	assert.Equal(t, http.StatusOK, code)
+}
+
+func TestService_Feed(t *testing.T) {
+	r := newMemoryServer(t)
+
+	for _, email := range []string{"andy@example.com", "john@example.com"} {
+		code, _ := serve(t, r, http.MethodPost, "/users", "", map[string]string{"email": email, "password": "password"})
+		assert.Equal(t, http.StatusCreated, code)
+	}
+	andy, john := login(t, r, "andy@example.com"), login(t, r, "john@example.com")
+	befriend(t, r, andy, "andy@example.com", "john@example.com")
+	code, _ := serve(t, r, http.MethodPost, "/updates", john, map[string]string{"sender": "john@example.com", "text": "hello"})
+	assert.Equal(t, http.StatusCreated, code)
+
+	// the feed is the one of the token, the email query param is not read
+	code, _ = serve(t, r, http.MethodGet, "/feed?email=andy@example.com", "", nil)
+	assert.Equal(t, http.StatusUnauthorized, code)
+	code, res := serve(t, r, http.MethodGet, "/feed?email=john@example.com", andy, nil)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, float64(1), res["count"])
+	code, res = serve(t, r, http.MethodGet, "/feed", john, nil)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, float64(0), res["count"])
+	code, _ = serve(t, r, http.MethodGet, "/feed?sort=email", andy, nil)
+	assert.Equal(t, http.StatusBadRequest, code)
+}
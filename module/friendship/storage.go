@@ -0,0 +1,134 @@
+package friendship
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/phantranhieunhan/s3-assignment/common/adapter/postgres"
+	sqlitedb "github.com/phantranhieunhan/s3-assignment/common/adapter/sqlite"
+	cacheadapter "github.com/phantranhieunhan/s3-assignment/module/friendship/adapter/cache"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/adapter/memory"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/adapter/postgres/repository"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/adapter/sqlite"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/app/command"
+	"github.com/phantranhieunhan/s3-assignment/module/friendship/domain"
+	"github.com/phantranhieunhan/s3-assignment/pkg/cache"
+	"github.com/phantranhieunhan/s3-assignment/pkg/config"
+)
+
+const (
+	StorageDriverPostgres = "postgres"
+	// StorageDriverSQLite keeps everything in a single file, it suits a single instance
+	StorageDriverSQLite = "sqlite"
+	// StorageDriverMemory keeps everything in the memory of the process, it needs no infrastructure and is lost on restart
+	StorageDriverMemory = "memory"
+)
+
+const (
+	// CacheDriverLRU caches in the memory of the process, every instance has a cache of its own
+	CacheDriverLRU  = "lru"
+	CacheDriverNone = "none"
+)
+
+var (
+	ErrStorageDriverIsNotValid = errors.New("storage driver must be postgres, sqlite or memory")
+	ErrCacheDriverIsNotValid   = errors.New("cache driver must be lru or none")
+)
+
+// Storage is the set of repositories of a storage driver with the transactor running their transactions
+type Storage struct {
+	FriendshipRepo   domain.FriendshipRepo
+	UserRepo         domain.UserRepo
+	SubscriptionRepo domain.SubscriptionRepo
+	BlockRepo        domain.BlockRepo
+	UpdateRepo       domain.UpdateRepo
+	EventRepo        domain.EventRepo
+	WebhookRepo      domain.WebhookRepo
+	AuditRepo        domain.AuditRepo
+	IdempotencyRepo  domain.IdempotencyRepo
+	Transactor       command.Transactor
+}
+
+// NewStorage opens the storage of the driver, the postgres driver connects to the configured database
+// and the sqlite driver opens the configured file and creates the missing tables
+func NewStorage(driver string) (Storage, error) {
+	switch driver {
+	case StorageDriverPostgres, "":
+		return NewPostgresStorage(postgres.NewDatabase()), nil
+	case StorageDriverSQLite:
+		db := sqlitedb.NewDatabase(config.C.Storage.SQLitePath)
+		if err := sqlite.CreateSchema(context.Background(), db); err != nil {
+			return Storage{}, err
+		}
+		return NewSQLiteStorage(db), nil
+	case StorageDriverMemory:
+		return NewMemoryStorage(memory.NewStore()), nil
+	}
+	return Storage{}, ErrStorageDriverIsNotValid
+}
+
+func NewPostgresStorage(db postgres.Database) Storage {
+	return Storage{
+		FriendshipRepo:   repository.NewFriendshipRepository(db),
+		UserRepo:         repository.NewUserRepository(db),
+		SubscriptionRepo: repository.NewSubscriptionRepository(db),
+		BlockRepo:        repository.NewBlockRepository(db),
+		UpdateRepo:       repository.NewUpdateRepository(db),
+		EventRepo:        repository.NewEventRepository(db),
+		WebhookRepo:      repository.NewWebhookRepository(db),
+		AuditRepo:        repository.NewAuditRepository(db),
+		IdempotencyRepo:  repository.NewIdempotencyRepository(db),
+		Transactor:       db,
+	}
+}
+
+func NewMemoryStorage(store *memory.Store) Storage {
+	return Storage{
+		FriendshipRepo:   memory.NewFriendshipRepository(store),
+		UserRepo:         memory.NewUserRepository(store),
+		SubscriptionRepo: memory.NewSubscriptionRepository(store),
+		BlockRepo:        memory.NewBlockRepository(store),
+		UpdateRepo:       memory.NewUpdateRepository(store),
+		EventRepo:        memory.NewEventRepository(store),
+		WebhookRepo:      memory.NewWebhookRepository(store),
+		AuditRepo:        memory.NewAuditRepository(store),
+		IdempotencyRepo:  memory.NewIdempotencyRepository(store),
+		Transactor:       store,
+	}
+}
+
+func NewSQLiteStorage(db sqlitedb.Database) Storage {
+	return Storage{
+		FriendshipRepo:   sqlite.NewFriendshipRepository(db),
+		UserRepo:         sqlite.NewUserRepository(db),
+		SubscriptionRepo: sqlite.NewSubscriptionRepository(db),
+		BlockRepo:        sqlite.NewBlockRepository(db),
+		UpdateRepo:       sqlite.NewUpdateRepository(db),
+		EventRepo:        sqlite.NewEventRepository(db),
+		WebhookRepo:      sqlite.NewWebhookRepository(db),
+		AuditRepo:        sqlite.NewAuditRepository(db),
+		IdempotencyRepo:  sqlite.NewIdempotencyRepository(db),
+		Transactor:       db,
+	}
+}
+
+// WithCache caches the user lookups and the friend lists of the storage in the cache of the driver
+func WithCache(storage Storage, driver string, size int, ttl time.Duration) (Storage, error) {
+	switch driver {
+	case CacheDriverNone, "":
+		return storage, nil
+	case CacheDriverLRU:
+		return NewCachedStorage(storage, cache.NewLRU(size), ttl), nil
+	}
+	return Storage{}, ErrCacheDriverIsNotValid
+}
+
+// NewCachedStorage wraps the user and friendship repositories with the cache, the transactor invalidates
+// the entries changed by a transaction once it is over
+func NewCachedStorage(storage Storage, c cache.Cache, ttl time.Duration) Storage {
+	storage.UserRepo = cacheadapter.NewUserRepository(storage.UserRepo, c, ttl)
+	storage.FriendshipRepo = cacheadapter.NewFriendshipRepository(storage.FriendshipRepo, c, ttl)
+	storage.Transactor = cacheadapter.NewTransactor(storage.Transactor, c)
+	return storage
+}
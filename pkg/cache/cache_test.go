@@ -0,0 +1,60 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRU_Evict(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	c := NewLRU(2)
+
+	assert.NoError(t, c.Set(ctx, "a", []byte("1"), 0))
+	assert.NoError(t, c.Set(ctx, "b", []byte("2"), 0))
+	// reading a makes b the least recently used
+	_, ok, err := c.Get(ctx, "a")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.NoError(t, c.Set(ctx, "c", []byte("3"), 0))
+
+	_, ok, _ = c.Get(ctx, "b")
+	assert.False(t, ok)
+	value, ok, _ := c.Get(ctx, "a")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("1"), value)
+	assert.Equal(t, 2, c.Len())
+
+	assert.NoError(t, c.Set(ctx, "a", []byte("4"), 0))
+	value, _, _ = c.Get(ctx, "a")
+	assert.Equal(t, []byte("4"), value)
+
+	assert.NoError(t, c.Delete(ctx, "a", "unknown"))
+	_, ok, _ = c.Get(ctx, "a")
+	assert.False(t, ok)
+}
+
+func TestLRU_Expire(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	now := time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+	c := NewLRU(10)
+	c.now = func() time.Time { return now }
+
+	assert.NoError(t, c.Set(ctx, "a", []byte("1"), time.Minute))
+	assert.NoError(t, c.Set(ctx, "b", []byte("2"), 0))
+
+	now = now.Add(59 * time.Second)
+	_, ok, _ := c.Get(ctx, "a")
+	assert.True(t, ok)
+
+	now = now.Add(time.Second)
+	_, ok, _ = c.Get(ctx, "a")
+	assert.False(t, ok)
+	_, ok, _ = c.Get(ctx, "b")
+	assert.True(t, ok)
+	assert.Equal(t, 1, c.Len())
+}
@@ -8,6 +8,8 @@ import (
 	"path"
 	"path/filepath"
 	"runtime"
+	"strconv"
+	"time"
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/pkg/errors"
@@ -28,10 +30,66 @@ type config struct {
 	Server      struct {
 		Port string `mapstructure:"PORT"`
 	}
+	Grpc struct {
+		Port string `mapstructure:"PORT"`
+	}
+	Storage struct {
+		Driver     string `mapstructure:"DRIVER"`
+		SQLitePath string `mapstructure:"SQLITE_PATH"`
+	}
+	Cache struct {
+		Driver string        `mapstructure:"DRIVER"`
+		Size   int           `mapstructure:"SIZE"`
+		TTL    time.Duration `mapstructure:"TTL"`
+	}
+	Friendship struct {
+		UnfriendSubscriptionPolicy string `mapstructure:"UNFRIEND_SUBSCRIPTION_POLICY"`
+	}
+	Admin struct {
+		Token string `mapstructure:"TOKEN"`
+	}
+	Auth struct {
+		Secret   string        `mapstructure:"SECRET"`
+		TokenTTL time.Duration `mapstructure:"TOKEN_TTL"`
+	}
+	Outbox struct {
+		PollInterval time.Duration `mapstructure:"POLL_INTERVAL"`
+		BatchSize    int           `mapstructure:"BATCH_SIZE"`
+	}
+	Webhook struct {
+		PollInterval time.Duration `mapstructure:"POLL_INTERVAL"`
+		BatchSize    int           `mapstructure:"BATCH_SIZE"`
+		Timeout      time.Duration `mapstructure:"TIMEOUT"`
+		MaxAttempts  int           `mapstructure:"MAX_ATTEMPTS"`
+		BackoffBase  time.Duration `mapstructure:"BACKOFF_BASE"`
+		BackoffMax   time.Duration `mapstructure:"BACKOFF_MAX"`
+		Lease        time.Duration `mapstructure:"LEASE"`
+	}
+	Import struct {
+		BatchSize int `mapstructure:"BATCH_SIZE"`
+	}
+	Account struct {
+		DeletionGracePeriod time.Duration `mapstructure:"DELETION_GRACE_PERIOD"`
+		PurgeInterval       time.Duration `mapstructure:"PURGE_INTERVAL"`
+		PurgeBatchSize      int           `mapstructure:"PURGE_BATCH_SIZE"`
+	}
+	Idempotency struct {
+		TTL             time.Duration `mapstructure:"TTL"`
+		Lease           time.Duration `mapstructure:"LEASE"`
+		CleanupInterval time.Duration `mapstructure:"CLEANUP_INTERVAL"`
+	}
 }
 
 var C config
 
+// defaultAuthSecret is the secret of config.yml, it is only good for local development
+const defaultAuthSecret = "changeme"
+
+var ErrAuthSecretIsNotSet = errors.New("auth secret must be set with AUTH_SECRET outside of dev")
+
+// redactedValue replaces the secrets in the dump of the config
+const redactedValue = "[redacted]"
+
 func ReadConfig() error {
 	Config := &C
 
@@ -78,7 +136,134 @@ func ReadConfig() error {
 		C.Server.Port = port
 	}
 
-	spew.Dump(C)
+	grpcPort := os.Getenv(constant.GRPC_PORT)
+	if grpcPort != "" {
+		C.Grpc.Port = grpcPort
+	}
+
+	storageDriver := os.Getenv(constant.STORAGE_DRIVER)
+	if storageDriver != "" {
+		C.Storage.Driver = storageDriver
+	}
+
+	storageSQLitePath := os.Getenv(constant.STORAGE_SQLITE_PATH)
+	if storageSQLitePath != "" {
+		C.Storage.SQLitePath = storageSQLitePath
+	}
+
+	cacheDriver := os.Getenv(constant.CACHE_DRIVER)
+	if cacheDriver != "" {
+		C.Cache.Driver = cacheDriver
+	}
+
+	unfriendSubscriptionPolicy := os.Getenv(constant.UNFRIEND_SUBSCRIPTION_POLICY)
+	if unfriendSubscriptionPolicy != "" {
+		C.Friendship.UnfriendSubscriptionPolicy = unfriendSubscriptionPolicy
+	}
+
+	adminToken := os.Getenv(constant.ADMIN_TOKEN)
+	if adminToken != "" {
+		C.Admin.Token = adminToken
+	}
+
+	authSecret := os.Getenv(constant.AUTH_SECRET)
+	if authSecret != "" {
+		C.Auth.Secret = authSecret
+	}
+
+	durations := map[string]*time.Duration{
+		constant.AUTH_TOKEN_TTL:                &C.Auth.TokenTTL,
+		constant.CACHE_TTL:                     &C.Cache.TTL,
+		constant.OUTBOX_POLL_INTERVAL:          &C.Outbox.PollInterval,
+		constant.WEBHOOK_POLL_INTERVAL:         &C.Webhook.PollInterval,
+		constant.WEBHOOK_TIMEOUT:               &C.Webhook.Timeout,
+		constant.WEBHOOK_BACKOFF_BASE:          &C.Webhook.BackoffBase,
+		constant.WEBHOOK_BACKOFF_MAX:           &C.Webhook.BackoffMax,
+		constant.WEBHOOK_LEASE:                 &C.Webhook.Lease,
+		constant.ACCOUNT_DELETION_GRACE_PERIOD: &C.Account.DeletionGracePeriod,
+		constant.ACCOUNT_PURGE_INTERVAL:        &C.Account.PurgeInterval,
+		constant.IDEMPOTENCY_TTL:               &C.Idempotency.TTL,
+		constant.IDEMPOTENCY_LEASE:             &C.Idempotency.Lease,
+		constant.IDEMPOTENCY_CLEANUP_INTERVAL:  &C.Idempotency.CleanupInterval,
+	}
+	for key, value := range durations {
+		if err := readDurationEnv(key, value); err != nil {
+			return err
+		}
+	}
+
+	ints := map[string]*int{
+		constant.CACHE_SIZE:               &C.Cache.Size,
+		constant.OUTBOX_BATCH_SIZE:        &C.Outbox.BatchSize,
+		constant.WEBHOOK_BATCH_SIZE:       &C.Webhook.BatchSize,
+		constant.WEBHOOK_MAX_ATTEMPTS:     &C.Webhook.MaxAttempts,
+		constant.IMPORT_BATCH_SIZE:        &C.Import.BatchSize,
+		constant.ACCOUNT_PURGE_BATCH_SIZE: &C.Account.PurgeBatchSize,
+	}
+	for key, value := range ints {
+		if err := readIntEnv(key, value); err != nil {
+			return err
+		}
+	}
+
+	if err := checkAuthSecret(C.Env, C.Auth.Secret); err != nil {
+		return err
+	}
+
+	spew.Dump(C.redacted())
+	return nil
+}
+
+// checkAuthSecret refuses an empty or default secret outside of dev, anybody could sign login tokens with it
+func checkAuthSecret(env, secret string) error {
+	if env == constant.DEV_ENV_NAME {
+		return nil
+	}
+	if secret == "" || secret == defaultAuthSecret {
+		return ErrAuthSecretIsNotSet
+	}
+	return nil
+}
+
+// redacted returns a copy of the config without its secrets, to be printed
+func (c config) redacted() config {
+	if c.Auth.Secret != "" {
+		c.Auth.Secret = redactedValue
+	}
+	if c.Admin.Token != "" {
+		c.Admin.Token = redactedValue
+	}
+	if c.PostgresULR != "" {
+		c.PostgresULR = redactedValue
+	}
+	return c
+}
+
+// readDurationEnv overrides the value with the duration set in the env variable, if any
+func readDurationEnv(key string, value *time.Duration) error {
+	env := os.Getenv(key)
+	if env == "" {
+		return nil
+	}
+	d, err := time.ParseDuration(env)
+	if err != nil {
+		return errors.Wrap(err, "time.ParseDuration "+key)
+	}
+	*value = d
+	return nil
+}
+
+// readIntEnv overrides the value with the integer set in the env variable, if any
+func readIntEnv(key string, value *int) error {
+	env := os.Getenv(key)
+	if env == "" {
+		return nil
+	}
+	i, err := strconv.Atoi(env)
+	if err != nil {
+		return errors.Wrap(err, "strconv.Atoi "+key)
+	}
+	*value = i
 	return nil
 }
 
@@ -0,0 +1,106 @@
+// Package dataloader batches the lookups of keys made at about the same time into a single fetch,
+// it avoids the N+1 queries of resolving the items of a list one by one
+package dataloader
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// BatchFunc fetches the values of the keys at once, a key missing from the result has no value
+type BatchFunc[K comparable, V any] func(ctx context.Context, keys []K) (map[K]V, error)
+
+type result[V any] struct {
+	done  chan struct{}
+	value V
+	found bool
+	err   error
+}
+
+type batch[K comparable, V any] struct {
+	keys    []K
+	results map[K]*result[V]
+	full    chan struct{}
+}
+
+// Loader collects the keys loaded within wait of the first one, or until maxBatch keys, and fetches them together.
+// It keeps the result of every key for its own life, a loader is meant to live as long as a single request.
+type Loader[K comparable, V any] struct {
+	fetch    BatchFunc[K, V]
+	wait     time.Duration
+	maxBatch int
+
+	mu      sync.Mutex
+	results map[K]*result[V]
+	pending *batch[K, V]
+}
+
+func New[K comparable, V any](fetch BatchFunc[K, V], wait time.Duration, maxBatch int) *Loader[K, V] {
+	return &Loader[K, V]{
+		fetch:    fetch,
+		wait:     wait,
+		maxBatch: maxBatch,
+		results:  make(map[K]*result[V]),
+	}
+}
+
+// Load returns the value of the key, false when the fetch has no value for it
+func (l *Loader[K, V]) Load(ctx context.Context, key K) (V, bool, error) {
+	l.mu.Lock()
+	r, ok := l.results[key]
+	if !ok {
+		r = &result[V]{done: make(chan struct{})}
+		l.results[key] = r
+		l.enqueue(ctx, key, r)
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-r.done:
+		return r.value, r.found, r.err
+	case <-ctx.Done():
+		var zero V
+		return zero, false, ctx.Err()
+	}
+}
+
+// enqueue adds the key to the pending batch, it starts a batch when there is none, l.mu is held
+func (l *Loader[K, V]) enqueue(ctx context.Context, key K, r *result[V]) {
+	if l.pending == nil {
+		l.pending = &batch[K, V]{results: make(map[K]*result[V]), full: make(chan struct{})}
+		go l.run(ctx, l.pending)
+	}
+	b := l.pending
+	b.keys = append(b.keys, key)
+	b.results[key] = r
+	if l.maxBatch > 0 && len(b.keys) >= l.maxBatch {
+		l.pending = nil
+		close(b.full)
+	}
+}
+
+func (l *Loader[K, V]) run(ctx context.Context, b *batch[K, V]) {
+	timer := time.NewTimer(l.wait)
+	select {
+	case <-timer.C:
+		l.mu.Lock()
+		if l.pending == b {
+			l.pending = nil
+		}
+		l.mu.Unlock()
+	case <-b.full:
+		timer.Stop()
+	}
+
+	values, err := l.fetch(ctx, b.keys)
+	for _, key := range b.keys {
+		r := b.results[key]
+		if err != nil {
+			r.err = err
+		} else {
+			r.value, r.found = values[key]
+		}
+		close(r.done)
+	}
+}
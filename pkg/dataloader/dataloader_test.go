@@ -0,0 +1,118 @@
+package dataloader
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type countingFetch struct {
+	mu      sync.Mutex
+	batches [][]string
+	err     error
+}
+
+func (f *countingFetch) fetch(_ context.Context, keys []string) (map[string]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	sorted := append([]string(nil), keys...)
+	sort.Strings(sorted)
+	f.batches = append(f.batches, sorted)
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	values := make(map[string]string, len(keys))
+	for _, k := range keys {
+		if k != "missing" {
+			values[k] = "value-" + k
+		}
+	}
+	return values, nil
+}
+
+func loadAll(t *testing.T, l *Loader[string, string], keys []string) map[string]string {
+	var mu sync.Mutex
+	values := make(map[string]string, len(keys))
+
+	var wg sync.WaitGroup
+	for _, key := range keys {
+		wg.Add(1)
+		go func(key string) {
+			defer wg.Done()
+			v, found, err := l.Load(context.Background(), key)
+			assert.NoError(t, err)
+			if found {
+				mu.Lock()
+				values[key] = v
+				mu.Unlock()
+			}
+		}(key)
+	}
+	wg.Wait()
+	return values
+}
+
+func TestLoaderBatchesKeys(t *testing.T) {
+	t.Parallel()
+
+	f := &countingFetch{}
+	l := New(f.fetch, 20*time.Millisecond, 100)
+
+	values := loadAll(t, l, []string{"a", "b", "c", "a", "missing"})
+	assert.Equal(t, map[string]string{"a": "value-a", "b": "value-b", "c": "value-c"}, values)
+	assert.Equal(t, [][]string{{"a", "b", "c", "missing"}}, f.batches)
+
+	// the loaded keys are not fetched again
+	v, found, err := l.Load(context.Background(), "b")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "value-b", v)
+	assert.Len(t, f.batches, 1)
+}
+
+func TestLoaderSplitsFullBatches(t *testing.T) {
+	t.Parallel()
+
+	f := &countingFetch{}
+	l := New(f.fetch, time.Second, 2)
+
+	start := time.Now()
+	values := loadAll(t, l, []string{"a", "b", "c", "d"})
+	assert.Len(t, values, 4)
+	assert.Len(t, f.batches, 2)
+	// full batches are fetched without waiting
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestLoaderReturnsFetchError(t *testing.T) {
+	t.Parallel()
+
+	errDB := errors.New("some error from db")
+	f := &countingFetch{err: errDB}
+	l := New(f.fetch, time.Millisecond, 10)
+
+	_, found, err := l.Load(context.Background(), "a")
+	assert.Equal(t, errDB, err)
+	assert.False(t, found)
+}
+
+func TestLoaderStopsWaitingWhenContextIsDone(t *testing.T) {
+	t.Parallel()
+
+	l := New(func(ctx context.Context, keys []string) (map[string]string, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}, time.Millisecond, 10)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, _, err := l.Load(ctx, "a")
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
@@ -0,0 +1,341 @@
+// Package migrate applies the versioned sql migrations to a postgres database and records them in
+// the schema_migrations table
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// lockKey is the key of the advisory lock held while migrating so that two instances don't migrate at the same time
+const lockKey int64 = 918_273_645
+
+var (
+	ErrMigrationFileIsNotValid = errors.New("migration file name must be <version>_<name>.up.sql or <version>_<name>.down.sql")
+	ErrMigrationIsDuplicated   = errors.New("migration version is duplicated")
+	ErrMigrationHasNoUp        = errors.New("migration has no up file")
+	ErrMigrationHasNoDown      = errors.New("migration has no down file")
+	ErrStepsIsNotValid         = errors.New("number of migrations to roll back must be positive")
+	ErrBaselineIsNotValid      = errors.New("baseline must be the version of a migration")
+	ErrDatabaseIsNotBaselined  = errors.New("database has tables but no recorded migration, record the migrations already applied with migrate baseline VERSION")
+)
+
+var fileName = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// Migration is a schema change, Down reverts Up and is empty when the change can't be reverted
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status is a migration with the time it was applied, AppliedAt is zero while the migration is pending
+type Status struct {
+	Migration
+	AppliedAt time.Time
+}
+
+func (s Status) Applied() bool {
+	return !s.AppliedAt.IsZero()
+}
+
+// Load reads the migrations of the directory of fsys, ordered by version
+func Load(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, e := range entries {
+		if e.IsDir() || path.Ext(e.Name()) != ".sql" {
+			continue
+		}
+		match := fileName.FindStringSubmatch(e.Name())
+		if match == nil {
+			return nil, fmt.Errorf("%s: %w", e.Name(), ErrMigrationFileIsNotValid)
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", e.Name(), ErrMigrationFileIsNotValid)
+		}
+		b, err := fs.ReadFile(fsys, path.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		}
+		if m.Name != match[2] {
+			return nil, fmt.Errorf("%s: %w", e.Name(), ErrMigrationIsDuplicated)
+		}
+		if match[3] == "up" {
+			m.Up = string(b)
+		} else {
+			m.Down = string(b)
+		}
+	}
+
+	result := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("%d_%s: %w", m.Version, m.Name, ErrMigrationHasNoUp)
+		}
+		result = append(result, *m)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Version < result[j].Version })
+	return result, nil
+}
+
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+func NewMigrator(db *sql.DB, migrations []Migration) Migrator {
+	return Migrator{
+		db:         db,
+		migrations: migrations,
+	}
+}
+
+// Up applies the pending migrations in the order of their versions and returns them.
+// Every migration runs in its own transaction with its record in schema_migrations, a failed migration
+// leaves the ones before it applied
+func (m Migrator) Up(ctx context.Context) ([]Migration, error) {
+	applied := make([]Migration, 0)
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		versions, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if len(versions) == 0 {
+			// the schema was created without the migrator, running the first migrations again would fail
+			unrecorded, err := hasTables(ctx, conn)
+			if err != nil {
+				return err
+			}
+			if unrecorded {
+				return ErrDatabaseIsNotBaselined
+			}
+		}
+		for _, migration := range m.migrations {
+			if _, ok := versions[migration.Version]; ok {
+				continue
+			}
+			err := withinTransaction(ctx, conn, func(tx *sql.Tx) error {
+				if _, err := tx.ExecContext(ctx, migration.Up); err != nil {
+					return err
+				}
+				_, err := tx.ExecContext(ctx, "insert into schema_migrations (version, name, applied_at) values ($1, $2, now())", migration.Version, migration.Name)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("migrate up %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down rolls back the last n applied migrations, the latest first, and returns them
+func (m Migrator) Down(ctx context.Context, n int) ([]Migration, error) {
+	if n <= 0 {
+		return nil, ErrStepsIsNotValid
+	}
+
+	reverted := make([]Migration, 0, n)
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		versions, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for i := len(m.migrations) - 1; i >= 0 && len(reverted) < n; i-- {
+			migration := m.migrations[i]
+			if _, ok := versions[migration.Version]; !ok {
+				continue
+			}
+			if migration.Down == "" {
+				return fmt.Errorf("migrate down %d_%s: %w", migration.Version, migration.Name, ErrMigrationHasNoDown)
+			}
+			err := withinTransaction(ctx, conn, func(tx *sql.Tx) error {
+				if _, err := tx.ExecContext(ctx, migration.Down); err != nil {
+					return err
+				}
+				_, err := tx.ExecContext(ctx, "delete from schema_migrations where version = $1", migration.Version)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("migrate down %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			reverted = append(reverted, migration)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Baseline records the migrations up to the version as applied without running them and returns them. It adopts
+// a database whose schema was created without the migrator, such as the initdb scripts of the postgres image.
+// Nothing is recorded on a database with recorded migrations or without any table, so it is safe to run again
+func (m Migrator) Baseline(ctx context.Context, version int64) ([]Migration, error) {
+	migrations, err := migrationsUpTo(m.migrations, version)
+	if err != nil {
+		return nil, err
+	}
+
+	recorded := make([]Migration, 0, len(migrations))
+	err = m.withLock(ctx, func(conn *sql.Conn) error {
+		versions, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if len(versions) > 0 {
+			return nil
+		}
+		adoptable, err := hasTables(ctx, conn)
+		if err != nil || !adoptable {
+			return err
+		}
+		return withinTransaction(ctx, conn, func(tx *sql.Tx) error {
+			for _, migration := range migrations {
+				_, err := tx.ExecContext(ctx, "insert into schema_migrations (version, name, applied_at) values ($1, $2, now())", migration.Version, migration.Name)
+				if err != nil {
+					return fmt.Errorf("migrate baseline %d_%s: %w", migration.Version, migration.Name, err)
+				}
+				recorded = append(recorded, migration)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return recorded, nil
+}
+
+// migrationsUpTo returns the migrations up to the version, which must be the version of one of them
+func migrationsUpTo(migrations []Migration, version int64) ([]Migration, error) {
+	for i, migration := range migrations {
+		if migration.Version == version {
+			return migrations[:i+1], nil
+		}
+	}
+	return nil, ErrBaselineIsNotValid
+}
+
+// Status lists the migrations with the time they were applied
+func (m Migrator) Status(ctx context.Context) ([]Status, error) {
+	result := make([]Status, 0, len(m.migrations))
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		versions, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, migration := range m.migrations {
+			result = append(result, Status{Migration: migration, AppliedAt: versions[migration.Version]})
+		}
+		return nil
+	})
+	return result, err
+}
+
+// Seed runs the seed scripts in a single transaction, the scripts must be safe to run again
+func (m Migrator) Seed(ctx context.Context, scripts ...string) error {
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		return withinTransaction(ctx, conn, func(tx *sql.Tx) error {
+			for _, script := range scripts {
+				if _, err := tx.ExecContext(ctx, script); err != nil {
+					return fmt.Errorf("seed: %w", err)
+				}
+			}
+			return nil
+		})
+	})
+}
+
+// withLock runs fn on a connection holding the advisory lock, the lock belongs to the session
+// so every statement has to run on that connection
+func (m Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) (err error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err = conn.ExecContext(ctx, "select pg_advisory_lock($1)", lockKey); err != nil {
+		return err
+	}
+	defer func() {
+		// the lock is released with the session anyway when the unlock fails
+		if _, unlockErr := conn.ExecContext(context.Background(), "select pg_advisory_unlock($1)", lockKey); unlockErr != nil && err == nil {
+			err = unlockErr
+		}
+	}()
+
+	_, err = conn.ExecContext(ctx, `create table if not exists schema_migrations(
+		version bigint not null primary key,
+		name text not null,
+		applied_at timestamp with time zone not null
+	)`)
+	if err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+func appliedVersions(ctx context.Context, conn *sql.Conn) (map[int64]time.Time, error) {
+	rows, err := conn.QueryContext(ctx, "select version, applied_at from schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[int64]time.Time)
+	for rows.Next() {
+		var (
+			version   int64
+			appliedAt time.Time
+		)
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		result[version] = appliedAt
+	}
+	return result, rows.Err()
+}
+
+// hasTables reports whether the schema has tables other than schema_migrations
+func hasTables(ctx context.Context, conn *sql.Conn) (bool, error) {
+	var exists bool
+	err := conn.QueryRowContext(ctx, `select exists (
+		select 1 from information_schema.tables where table_schema = current_schema() and table_name <> 'schema_migrations'
+	)`).Scan(&exists)
+	return exists, err
+}
+
+func withinTransaction(ctx context.Context, conn *sql.Conn, fn func(tx *sql.Tx) error) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
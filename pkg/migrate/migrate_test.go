@@ -0,0 +1,89 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name   string
+		files  fstest.MapFS
+		result []Migration
+		err    error
+	}{
+		{
+			name: "load the migrations ordered by version",
+			files: fstest.MapFS{
+				"0010_blocks.up.sql":   {Data: []byte("create table blocks();")},
+				"0010_blocks.down.sql": {Data: []byte("drop table blocks;")},
+				"0002_users.up.sql":    {Data: []byte("create table users();")},
+				"README.md":            {Data: []byte("# MIGRATION")},
+				"seed/users.sql":       {Data: []byte("insert into users default values;")},
+			},
+			result: []Migration{
+				{Version: 2, Name: "users", Up: "create table users();"},
+				{Version: 10, Name: "blocks", Up: "create table blocks();", Down: "drop table blocks;"},
+			},
+		},
+		{
+			name:  "fail because a file name is not valid",
+			files: fstest.MapFS{"users.sql": {Data: []byte("create table users();")}},
+			err:   ErrMigrationFileIsNotValid,
+		},
+		{
+			name: "fail because two migrations have the same version",
+			files: fstest.MapFS{
+				"0001_users.up.sql":  {Data: []byte("create table users();")},
+				"0001_blocks.up.sql": {Data: []byte("create table blocks();")},
+			},
+			err: ErrMigrationIsDuplicated,
+		},
+		{
+			name:  "fail because a migration has no up file",
+			files: fstest.MapFS{"0001_users.down.sql": {Data: []byte("drop table users;")}},
+			err:   ErrMigrationHasNoUp,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := Load(tc.files, ".")
+			if tc.err != nil {
+				assert.True(t, errors.Is(err, tc.err), err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.result, result)
+		})
+	}
+}
+
+func TestMigrator_Down_StepsIsNotValid(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewMigrator(nil, nil).Down(context.Background(), 0)
+	assert.Equal(t, ErrStepsIsNotValid, err)
+}
+
+func TestMigrationsUpTo(t *testing.T) {
+	t.Parallel()
+
+	migrations := []Migration{{Version: 1, Name: "users"}, {Version: 2, Name: "blocks"}, {Version: 10, Name: "updates"}}
+
+	result, err := migrationsUpTo(migrations, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, migrations[:2], result)
+	result, err = migrationsUpTo(migrations, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, migrations, result)
+
+	_, err = migrationsUpTo(migrations, 3)
+	assert.Equal(t, ErrBaselineIsNotValid, err)
+}